@@ -0,0 +1,50 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// DefaultConsoleCaptureDuration is how long the serial console is recorded by default
+const DefaultConsoleCaptureDuration = "5m"
+
+// ConsoleArtifactType is the OCI artifact type of serial console transcripts
+// attached to the flashed image
+const ConsoleArtifactType = "application/vnd.automotive.console.transcript.v1+text"
+
+// ConsoleCaptureSpec records the serial console of the device over the flash
+// lease once it is flashed. The device is power-cycled so the transcript
+// covers the whole boot.
+type ConsoleCaptureSpec struct {
+	// Duration is how long the console is recorded after flashing in Go duration format (default "5m")
+	// +optional
+	Duration string `json:"duration,omitempty"`
+
+	// SkipPowerCycle skips power-cycling the device before recording
+	// +optional
+	SkipPowerCycle bool `json:"skipPowerCycle,omitempty"`
+
+	// SkipUpload keeps the transcript in the flash TaskRun logs only, instead of
+	// also attaching it to the flashed image as an OCI referrer
+	// +optional
+	SkipUpload bool `json:"skipUpload,omitempty"`
+}
+
+// GetDuration returns the capture duration, or the default
+func (s *ConsoleCaptureSpec) GetDuration() string {
+	if s.Duration != "" {
+		return s.Duration
+	}
+	return DefaultConsoleCaptureDuration
+}
@@ -92,6 +92,18 @@ func TestCRDSchemaMatchesGoTypes(t *testing.T) {
 			statusType: reflect.TypeOf(OperatorConfigStatus{}),
 			specType:   reflect.TypeOf(OperatorConfigSpec{}),
 		},
+		{
+			name:       "FlashJob",
+			crdFile:    "automotive.sdv.cloud.redhat.com_flashjobs.yaml",
+			statusType: reflect.TypeOf(FlashJobStatus{}),
+			specType:   reflect.TypeOf(FlashJobSpec{}),
+		},
+		{
+			name:       "WorkspaceSnapshot",
+			crdFile:    "automotive.sdv.cloud.redhat.com_workspacesnapshots.yaml",
+			statusType: reflect.TypeOf(WorkspaceSnapshotStatus{}),
+			specType:   reflect.TypeOf(WorkspaceSnapshotSpec{}),
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,133 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// Device test boot checks
+const (
+	DeviceTestBootCheckConsole = "console"
+	DeviceTestBootCheckSSH     = "ssh"
+)
+
+// Device test defaults
+const (
+	DefaultDeviceTestBootMarker  = "login:"
+	DefaultDeviceTestBootTimeout = "10m"
+)
+
+// DeviceTestSpec configures smoke tests run on the device after it is flashed.
+// The tests reuse the flash lease: the board is power-cycled, the boot check
+// waits for the device to come up and the test script is run against it.
+type DeviceTestSpec struct {
+	// Script is the test script. It runs with JMP_LEASE and JMP_CLIENT_CONFIG set,
+	// so `jmp shell --lease "$JMP_LEASE" -- j ...` reaches the device, and should
+	// write JUnit XML files to $TEST_RESULTS_DIR. If it writes none, its exit
+	// code is reported as a single test case.
+	// +kubebuilder:validation:Required
+	Script string `json:"script"`
+
+	// Image is the container image the script runs in. Defaults to the Jumpstarter image.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// BootCheck selects how a successful boot is detected: "console" waits for
+	// BootMarker on the serial console, "ssh" waits until the device accepts SSH.
+	// +kubebuilder:validation:Enum=console;ssh
+	// +kubebuilder:default=console
+	// +optional
+	BootCheck string `json:"bootCheck,omitempty"`
+
+	// BootMarker is the regular expression awaited on the serial console (default "login:")
+	// +optional
+	BootMarker string `json:"bootMarker,omitempty"`
+
+	// BootTimeout is how long to wait for the boot check in Go duration format (default "10m")
+	// +optional
+	BootTimeout string `json:"bootTimeout,omitempty"`
+
+	// SkipPowerCycle skips power-cycling the device before the boot check
+	// +optional
+	SkipPowerCycle bool `json:"skipPowerCycle,omitempty"`
+}
+
+// DeviceTestResults summarizes the JUnit results of on-device tests
+type DeviceTestResults struct {
+	// Booted is true once the boot check passed
+	Booted bool `json:"booted"`
+
+	// Total is the number of test cases run
+	Total int32 `json:"total"`
+
+	// Passed is the number of test cases that passed
+	Passed int32 `json:"passed"`
+
+	// Failed is the number of test cases that failed or errored
+	Failed int32 `json:"failed"`
+
+	// Skipped is the number of skipped test cases
+	Skipped int32 `json:"skipped"`
+
+	// Message describes the outcome of the tests
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// FailedCases lists the first failed test cases
+	// +optional
+	FailedCases []DeviceTestCase `json:"failedCases,omitempty"`
+}
+
+// DeviceTestCase identifies a test case from the JUnit results
+type DeviceTestCase struct {
+	// Suite is the name of the test suite
+	// +optional
+	Suite string `json:"suite,omitempty"`
+
+	// Name is the name of the test case
+	Name string `json:"name"`
+
+	// Message is the failure message
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// GetBootCheck returns the boot check, or the console default
+func (s *DeviceTestSpec) GetBootCheck() string {
+	if s.BootCheck != "" {
+		return s.BootCheck
+	}
+	return DeviceTestBootCheckConsole
+}
+
+// GetBootMarker returns the console boot marker, or the default
+func (s *DeviceTestSpec) GetBootMarker() string {
+	if s.BootMarker != "" {
+		return s.BootMarker
+	}
+	return DefaultDeviceTestBootMarker
+}
+
+// GetBootTimeout returns the boot timeout, or the default
+func (s *DeviceTestSpec) GetBootTimeout() string {
+	if s.BootTimeout != "" {
+		return s.BootTimeout
+	}
+	return DefaultDeviceTestBootTimeout
+}
+
+// Succeeded returns true if the device booted and no test case failed
+func (r *DeviceTestResults) Succeeded() bool {
+	return r.Booted && r.Failed == 0
+}
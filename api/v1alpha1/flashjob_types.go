@@ -0,0 +1,245 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FlashJob phases
+const (
+	FlashJobPhasePending   = "Pending"
+	FlashJobPhaseRunning   = "Running"
+	FlashJobPhaseCompleted = "Completed"
+	FlashJobPhaseFailed    = "Failed"
+)
+
+// FlashJob condition types
+const (
+	// FlashJobConditionLeaseAcquired is True once a Jumpstarter lease is held for the device.
+	FlashJobConditionLeaseAcquired = "LeaseAcquired"
+	// FlashJobConditionFlashed is True once the image was written to the device.
+	FlashJobConditionFlashed = "Flashed"
+	// FlashJobConditionLeaseReleased is True once the lease acquired by the job was released.
+	// It is False while the lease is kept for access to the flashed device.
+	FlashJobConditionLeaseReleased = "LeaseReleased"
+	// FlashJobConditionTested is True once the on-device tests passed
+	FlashJobConditionTested = "Tested"
+)
+
+// FlashJobSpec defines the desired state of FlashJob
+type FlashJobSpec struct {
+	// ImageRef is the OCI reference of the disk image to flash
+	// +kubebuilder:validation:Required
+	ImageRef string `json:"imageRef"`
+
+	// Target is the target platform used to look up the exporter selector and
+	// flash command in OperatorConfig jumpstarter.targetMappings
+	// +optional
+	Target string `json:"target,omitempty"`
+
+	// ExporterSelector is the Jumpstarter exporter label selector (overrides the Target mapping)
+	// +optional
+	ExporterSelector string `json:"exporterSelector,omitempty"`
+
+	// FlashCmd is the flash command template (overrides the Target mapping).
+	// {image_uri} is replaced with ImageRef.
+	// +optional
+	FlashCmd string `json:"flashCmd,omitempty"`
+
+	// LeaseDuration is the Jumpstarter lease duration in HH:MM:SS format.
+	// Empty uses the OperatorConfig flash timeout or Jumpstarter default lease duration.
+	// +optional
+	LeaseDuration string `json:"leaseDuration,omitempty"`
+
+	// LeaseName is an existing Jumpstarter lease to flash with instead of acquiring one
+	// (mutually exclusive with LeaseDuration)
+	// +optional
+	LeaseName string `json:"leaseName,omitempty"`
+
+	// ClientConfigSecretRef is the name of a secret holding the Jumpstarter
+	// client config under the "client.yaml" key
+	// +kubebuilder:validation:Required
+	ClientConfigSecretRef string `json:"clientConfigSecretRef"`
+
+	// RegistryAuthSecretRef is the name of a secret with "username" and "password"
+	// keys used by the exporter to pull ImageRef
+	// +optional
+	RegistryAuthSecretRef string `json:"registryAuthSecretRef,omitempty"`
+
+	// Test runs smoke tests on the device after it is flashed, reusing the flash lease
+	// +optional
+	Test *DeviceTestSpec `json:"test,omitempty"`
+
+	// Console records the serial console of the device after it is flashed
+	// +optional
+	Console *ConsoleCaptureSpec `json:"console,omitempty"`
+
+	// Fleet flashes the image to several exporters matching the selector,
+	// each under its own lease (mutually exclusive with LeaseName)
+	// +optional
+	Fleet *FleetFlashSpec `json:"fleet,omitempty"`
+
+	// TTL is the time-to-live of the FlashJob after it finishes. The FlashJob,
+	// its TaskRun and secrets are then deleted.
+	// Uses Go duration format (e.g. "24h"). Empty uses the OperatorConfig
+	// default build TTL. Set to "0" to disable expiry.
+	// +optional
+	TTL string `json:"ttl,omitempty"`
+}
+
+// FleetFlashSpec selects how many exporters a fleet flash targets.
+// Exactly one of Count and AllExporters must be set.
+type FleetFlashSpec struct {
+	// Count is the number of boards to flash
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Count int32 `json:"count,omitempty"`
+
+	// AllExporters flashes every exporter matching the selector. The exporters
+	// are counted from the Jumpstarter Exporter resources in this cluster.
+	// +optional
+	AllExporters bool `json:"allExporters,omitempty"`
+
+	// MaxParallel limits how many boards are flashed at the same time.
+	// 0 flashes all boards at once.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxParallel int32 `json:"maxParallel,omitempty"`
+}
+
+// FleetBoardStatus is the flash status of one board of a fleet flash
+type FleetBoardStatus struct {
+	// Board is the 1-based index of the board in the fleet
+	Board int32 `json:"board"`
+
+	// Phase is the flash phase of the board
+	// +kubebuilder:validation:Enum=Pending;Running;Completed;Failed
+	Phase string `json:"phase"`
+
+	// Message provides details about the board's flash
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// TaskRunName is the TaskRun flashing the board
+	// +optional
+	TaskRunName string `json:"taskRunName,omitempty"`
+
+	// LeaseID is the Jumpstarter lease used for the board
+	// +optional
+	LeaseID string `json:"leaseId,omitempty"`
+
+	// Exporter is the Jumpstarter exporter the lease was granted on
+	// +optional
+	Exporter string `json:"exporter,omitempty"`
+
+	// DeviceTests summarizes the on-device test results of the board
+	// +optional
+	DeviceTests *DeviceTestResults `json:"deviceTests,omitempty"`
+
+	// ConsoleRef is the OCI referrer holding the serial console transcript of the board
+	// +optional
+	ConsoleRef string `json:"consoleRef,omitempty"`
+}
+
+// FlashJobStatus defines the observed state of FlashJob
+type FlashJobStatus struct {
+	// Phase represents the current phase of the flash job
+	// +kubebuilder:validation:Enum=Pending;Running;Completed;Failed
+	Phase string `json:"phase,omitempty"`
+
+	// Message provides additional details about the current phase
+	Message string `json:"message,omitempty"`
+
+	// TaskRunName is the name of the Tekton TaskRun running the flash
+	TaskRunName string `json:"taskRunName,omitempty"`
+
+	// LeaseID is the Jumpstarter lease used for the flash
+	LeaseID string `json:"leaseId,omitempty"`
+
+	// Exporter is the Jumpstarter exporter the lease was granted on
+	Exporter string `json:"exporter,omitempty"`
+
+	// DeviceTests summarizes the on-device test results when spec.test is set
+	// +optional
+	DeviceTests *DeviceTestResults `json:"deviceTests,omitempty"`
+
+	// ConsoleRef is the OCI referrer holding the serial console transcript when
+	// spec.console is set and the upload succeeded
+	// +optional
+	ConsoleRef string `json:"consoleRef,omitempty"`
+
+	// Boards is the per-board status of a fleet flash
+	// +optional
+	Boards []FleetBoardStatus `json:"boards,omitempty"`
+
+	// StartTime is when the flash TaskRun started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the flash job finished
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// ExpiresAt is when the FlashJob will be deleted. Nil if expiry is disabled.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// Conditions track lease acquisition, flashing, testing and lease release
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Exporter",type=string,JSONPath=`.status.exporter`
+// +kubebuilder:printcolumn:name="Lease",type=string,JSONPath=`.status.leaseId`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// FlashJob is the Schema for the flashjobs API.
+// It flashes a disk image to a Jumpstarter-managed device.
+type FlashJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FlashJobSpec   `json:"spec,omitempty"`
+	Status FlashJobStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FlashJobList contains a list of FlashJob
+type FlashJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FlashJob `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FlashJob{}, &FlashJobList{})
+}
+
+// IsFinished returns true if the flash job completed or failed
+func (s *FlashJobStatus) IsFinished() bool {
+	return s.Phase == FlashJobPhaseCompleted || s.Phase == FlashJobPhaseFailed
+}
+
+// IsFinished returns true if the board was flashed or failed
+func (s *FleetBoardStatus) IsFinished() bool {
+	return s.Phase == FlashJobPhaseCompleted || s.Phase == FlashJobPhaseFailed
+}
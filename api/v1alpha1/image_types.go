@@ -68,15 +68,32 @@ type ImageSize struct {
 	VirtualBytes *int64 `json:"virtualBytes,omitempty"`
 }
 
+// Image location types
+const (
+	LocationTypeRegistry = "registry"
+	LocationTypeS3       = "s3"
+	LocationTypePVC      = "pvc"
+	LocationTypeHTTP     = "http"
+)
+
 // ImageLocation defines where an image is stored with support for different storage types
 type ImageLocation struct {
 	// Type specifies the storage type
-	// +kubebuilder:validation:Enum=registry
+	// +kubebuilder:validation:Enum=registry;s3;pvc;http
 	// +kubebuilder:default=registry
 	Type string `json:"type"`
 
 	// Registry contains configuration for container registry storage
 	Registry *RegistryLocation `json:"registry,omitempty"`
+
+	// S3 contains configuration for S3-compatible object storage
+	S3 *S3Location `json:"s3,omitempty"`
+
+	// PVC contains configuration for storage on a PersistentVolumeClaim
+	PVC *PVCLocation `json:"pvc,omitempty"`
+
+	// HTTP contains configuration for a plain HTTP(S) download location
+	HTTP *HTTPLocation `json:"http,omitempty"`
 }
 
 // RegistryLocation defines storage in a container registry
@@ -91,6 +108,56 @@ type RegistryLocation struct {
 	SecretRef string `json:"secretRef,omitempty"`
 }
 
+// S3Location identifies an object in S3-compatible object storage (AWS S3, MinIO, Ceph RGW)
+type S3Location struct {
+	// Endpoint is the base URL of the S3 service (e.g., "https://minio.example.com:9000").
+	// Objects are addressed path-style as <endpoint>/<bucket>/<key>.
+	Endpoint string `json:"endpoint"`
+
+	// Region is used for request signing
+	// +kubebuilder:default=us-east-1
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Bucket is the bucket holding the object
+	Bucket string `json:"bucket"`
+
+	// Key is the object key within the bucket
+	Key string `json:"key"`
+
+	// SecretRef is the name of a secret with accessKeyId and secretAccessKey keys
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+}
+
+// GetRegion returns the signing region, defaulting to us-east-1
+func (l *S3Location) GetRegion() string {
+	if l.Region != "" {
+		return l.Region
+	}
+	return "us-east-1"
+}
+
+// PVCLocation identifies a file on a PersistentVolumeClaim
+type PVCLocation struct {
+	// ClaimName is the name of the PersistentVolumeClaim in the resource's namespace
+	ClaimName string `json:"claimName"`
+
+	// Path is the file path relative to the root of the volume
+	Path string `json:"path"`
+}
+
+// HTTPLocation identifies a file served over HTTP(S)
+type HTTPLocation struct {
+	// URL is the full http:// or https:// URL of the file
+	URL string `json:"url"`
+
+	// SecretRef is the name of a secret with either a token key (bearer auth)
+	// or username and password keys (basic auth)
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+}
+
 // ImageMetadata contains additional metadata about the image
 type ImageMetadata struct {
 	// CreatedBy identifies who or what created this image
@@ -121,6 +188,14 @@ type ImageStatus struct {
 	// LastVerified is when the image location was last verified to be accessible
 	LastVerified *metav1.Time `json:"lastVerified,omitempty"`
 
+	// Digest is the manifest digest the registry resolved at the last verification
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
+	// SizeBytes is the total layer size reported by the registry at the last verification
+	// +optional
+	SizeBytes int64 `json:"sizeBytes,omitempty"`
+
 	// Message provides more detail about the current phase
 	Message string `json:"message,omitempty"`
 
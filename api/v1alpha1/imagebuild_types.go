@@ -17,6 +17,9 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"strings"
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -24,6 +27,7 @@ import (
 const (
 	ImageBuildPhasePending   = "Pending"
 	ImageBuildPhaseUploading = "Uploading"
+	ImageBuildPhaseQueued    = "Queued"
 	ImageBuildPhaseBuilding  = "Building"
 	ImageBuildPhasePushing   = "Pushing"
 	ImageBuildPhaseFlashing  = "Flashing"
@@ -33,10 +37,24 @@ const (
 	ImageBuildPhaseExpired   = "Expired"
 )
 
+// Build failure classes for RetryPolicy.RetryOn and BuildAttempt.FailureClass.
+const (
+	FailureClassMirrorDownload      = "MirrorDownload"
+	FailureClassRegistryServerError = "RegistryServerError"
+	FailureClassTimeout             = "Timeout"
+)
+
+// Build stages for BuildAttempt.Stage.
+const (
+	BuildStageBuild = "Build"
+	BuildStagePush  = "Push"
+)
+
 // ImageBuild condition types for Status.Conditions.
 const (
 	ImageBuildConditionReady       = "Ready"
 	ImageBuildConditionProgressing = "Progressing"
+	ImageBuildConditionPreempted   = "Preempted"
 )
 
 // IsTerminalBuildPhase reports whether phase is a final build state.
@@ -54,6 +72,15 @@ type ImageBuildSpec struct {
 	// Architecture specifies the target architecture (e.g., "amd64", "arm64")
 	Architecture string `json:"architecture,omitempty"`
 
+	// Architectures lists the target architectures for a multi-architecture build.
+	// When more than one is set, the controller runs one PipelineRun per architecture
+	// pinned to matching nodes, Architecture is ignored, and bootc containers are
+	// published as an OCI image index.
+	// +optional
+	// +kubebuilder:validation:MaxItems=8
+	// +kubebuilder:validation:items:Enum=amd64;arm64
+	Architectures []string `json:"architectures,omitempty"`
+
 	// StorageClass is the name of the storage class to use for the build PVC
 	StorageClass string `json:"storageClass,omitempty"`
 
@@ -123,6 +150,25 @@ type ImageBuildSpec struct {
 	// Empty uses the OperatorConfig default. Set to "0" to disable expiry.
 	// +optional
 	TTL string `json:"ttl,omitempty"`
+
+	// Priority names a build priority class from OperatorConfig osBuilds.priorityClasses.
+	// Higher-priority builds are admitted from the build queue first and may
+	// preempt lower-priority builds when the queue preemption policy allows it.
+	// Empty uses the default priority class.
+	// +optional
+	Priority string `json:"priority,omitempty"`
+
+	// RetryPolicy automatically retries builds that fail with a transient error.
+	// Only the failed stage is re-run. Retries apply to single-architecture builds.
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// SigningKeySecretRef is the name of a Secret containing the cosign private key
+	// ("cosign.key") and optional password ("cosign.password") used to sign the
+	// pushed container and disk artifact. It overrides the OperatorConfig
+	// osBuilds.signing key.
+	// +optional
+	SigningKeySecretRef string `json:"signingKeySecretRef,omitempty"`
 }
 
 // FlashSpec defines configuration for flashing images to hardware via Jumpstarter
@@ -151,6 +197,10 @@ type FlashSpec struct {
 	// When set, the target-based lookup is skipped entirely
 	// +optional
 	ExporterSelector string `json:"exporterSelector,omitempty"`
+
+	// Test runs smoke tests on the device after it is flashed, reusing the flash lease
+	// +optional
+	Test *DeviceTestSpec `json:"test,omitempty"`
 }
 
 // AIBSpec defines the automotive-image-builder configuration
@@ -226,15 +276,41 @@ type ExportSpec struct {
 	Disk *DiskExport `json:"disk,omitempty"`
 }
 
-// DiskExport defines where to export the disk image
-// Currently supports OCI registries, extensible for future storage types
+// DiskExport defines where to export the disk image.
+// OCI is pushed by the registry push task; S3, PVC and HTTP are mutually
+// exclusive and handled by the storage export task.
+// +kubebuilder:validation:XValidation:rule="(has(self.s3) ? 1 : 0) + (has(self.pvc) ? 1 : 0) + (has(self.http) ? 1 : 0) <= 1",message="only one of s3, pvc or http may be set"
 type DiskExport struct {
 	// OCI is the registry URL to push the disk image as an OCI artifact
 	OCI string `json:"oci,omitempty"`
 
-	// Future storage options:
-	// S3 *S3Export `json:"s3,omitempty"`
-	// PVC *PVCExport `json:"pvc,omitempty"`
+	// S3 uploads the disk image to S3-compatible object storage
+	// +optional
+	S3 *S3Location `json:"s3,omitempty"`
+
+	// PVC copies the disk image onto a PersistentVolumeClaim
+	// +optional
+	PVC *PVCLocation `json:"pvc,omitempty"`
+
+	// HTTP uploads the disk image with an HTTP PUT
+	// +optional
+	HTTP *HTTPLocation `json:"http,omitempty"`
+}
+
+// StorageType returns the non-registry storage type configured for the export, if any
+func (d *DiskExport) StorageType() string {
+	switch {
+	case d == nil:
+		return ""
+	case d.S3 != nil:
+		return LocationTypeS3
+	case d.PVC != nil:
+		return LocationTypePVC
+	case d.HTTP != nil:
+		return LocationTypeHTTP
+	default:
+		return ""
+	}
 }
 
 // ImageBuildStatus defines the observed state of ImageBuild
@@ -244,7 +320,7 @@ type ImageBuildStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
 	// Phase represents the current phase of the build
-	// +kubebuilder:validation:Enum=Pending;Uploading;Building;Pushing;Flashing;Completed;Failed;Cancelled;Expired
+	// +kubebuilder:validation:Enum=Pending;Uploading;Queued;Building;Pushing;Flashing;Completed;Failed;Cancelled;Expired
 	Phase string `json:"phase,omitempty"`
 
 	// StartTime is when the build started
@@ -256,6 +332,11 @@ type ImageBuildStatus struct {
 	// Message provides more detail about the current phase
 	Message string `json:"message,omitempty"`
 
+	// QueuePosition is the 1-based position of the build in the build queue
+	// while it is in the Queued phase
+	// +optional
+	QueuePosition int32 `json:"queuePosition,omitempty"`
+
 	// PVCName is the name of the PVC where the artifact is stored
 	PVCName string `json:"pvcName,omitempty"`
 
@@ -287,6 +368,10 @@ type ImageBuildStatus struct {
 	// +optional
 	LeaseID string `json:"leaseId,omitempty"`
 
+	// DeviceTests summarizes the on-device test results when flash.test is set
+	// +optional
+	DeviceTests *DeviceTestResults `json:"deviceTests,omitempty"`
+
 	// ExpiresAt is when this build will transition to the Expired phase
 	// and have its associated resources cleaned up. The ImageBuild CR itself
 	// is preserved. Nil if expiry is disabled (TTL "0", no-expire annotation,
@@ -298,6 +383,145 @@ type ImageBuildStatus struct {
 	// Used to determine whether an expired build originally succeeded or failed.
 	// +optional
 	PreviousPhase string `json:"previousPhase,omitempty"`
+
+	// ─── Multi-architecture builds ───
+
+	// Architectures holds the per-architecture state of a multi-architecture build.
+	// +optional
+	Architectures []ArchitectureBuildStatus `json:"architectures,omitempty"`
+
+	// ImageIndexTaskRunName is the name of the TaskRun that publishes the OCI image index
+	// for a multi-architecture bootc container
+	// +optional
+	ImageIndexTaskRunName string `json:"imageIndexTaskRunName,omitempty"`
+
+	// ─── Retries ───
+
+	// Attempts records the failed attempts of a build that has a RetryPolicy,
+	// oldest first. The attempt in progress is not listed.
+	// +optional
+	Attempts []BuildAttempt `json:"attempts,omitempty"`
+
+	// NextRetryTime is when the next retry of the failed stage will start
+	// +optional
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
+}
+
+// RetryPolicy configures automatic retries of transiently failed builds
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	MaxAttempts int32 `json:"maxAttempts"`
+
+	// Backoff is the delay before the first retry, doubled for every further retry.
+	// Uses Go duration format (e.g. "30s", "2m"). Defaults to "30s".
+	// +optional
+	Backoff string `json:"backoff,omitempty"`
+
+	// RetryOn lists the failure classes that are retried.
+	// Defaults to MirrorDownload and RegistryServerError.
+	// +kubebuilder:validation:items:Enum=MirrorDownload;RegistryServerError;Timeout
+	// +optional
+	RetryOn []string `json:"retryOn,omitempty"`
+}
+
+// BuildAttempt records one failed attempt of a build stage
+type BuildAttempt struct {
+	// Attempt is the 1-based attempt number
+	Attempt int32 `json:"attempt"`
+
+	// Stage is the build stage that failed
+	// +kubebuilder:validation:Enum=Build;Push
+	Stage string `json:"stage"`
+
+	// PipelineRunName is the name of the PipelineRun of this attempt
+	// +optional
+	PipelineRunName string `json:"pipelineRunName,omitempty"`
+
+	// TaskRunName is the name of the TaskRun that failed
+	// +optional
+	TaskRunName string `json:"taskRunName,omitempty"`
+
+	// FailureClass is the classified failure, empty if the failure is not transient
+	// +optional
+	FailureClass string `json:"failureClass,omitempty"`
+
+	// FailureReason describes why the attempt failed
+	// +optional
+	FailureReason string `json:"failureReason,omitempty"`
+
+	// CompletionTime is when the attempt failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// DefaultRetryBackoff is the retry backoff used when RetryPolicy.Backoff is empty
+const DefaultRetryBackoff = 30 * time.Second
+
+// GetBackoff returns the delay before the given retry (1 for the first retry)
+func (p *RetryPolicy) GetBackoff(retry int) time.Duration {
+	backoff := DefaultRetryBackoff
+	if p.Backoff != "" {
+		if d, err := time.ParseDuration(p.Backoff); err == nil && d >= 0 {
+			backoff = d
+		}
+	}
+	for i := 1; i < retry && backoff < time.Hour; i++ {
+		backoff *= 2
+	}
+	return backoff
+}
+
+// Retries reports whether failures of the given class are retried
+func (p *RetryPolicy) Retries(failureClass string) bool {
+	if failureClass == "" {
+		return false
+	}
+	retryOn := p.RetryOn
+	if len(retryOn) == 0 {
+		retryOn = []string{FailureClassMirrorDownload, FailureClassRegistryServerError}
+	}
+	for _, class := range retryOn {
+		if class == failureClass {
+			return true
+		}
+	}
+	return false
+}
+
+// ArchitectureBuildStatus is the observed state of one architecture in a multi-architecture build
+type ArchitectureBuildStatus struct {
+	// Architecture is the target architecture (e.g., "amd64", "arm64")
+	Architecture string `json:"architecture"`
+
+	// Phase is the phase of this architecture's PipelineRun
+	// +kubebuilder:validation:Enum=Pending;Building;Completed;Failed;Cancelled
+	Phase string `json:"phase,omitempty"`
+
+	// Message provides more detail about the current phase
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// PipelineRunName is the name of the PipelineRun building this architecture
+	// +optional
+	PipelineRunName string `json:"pipelineRunName,omitempty"`
+
+	// StartTime is when this architecture's PipelineRun was created
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when this architecture's PipelineRun finished
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// ContainerImage is the per-architecture bootc container reference
+	// +optional
+	ContainerImage string `json:"containerImage,omitempty"`
+
+	// DiskImage is the per-architecture disk image OCI reference
+	// +optional
+	DiskImage string `json:"diskImage,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -465,6 +689,14 @@ func (s *ImageBuildSpec) GetUseServiceAccountAuth() bool {
 	return s.Export != nil && s.Export.UseServiceAccountAuth
 }
 
+// GetDiskStorageExport returns the disk export when it targets S3, PVC or HTTP storage
+func (s *ImageBuildSpec) GetDiskStorageExport() *DiskExport {
+	if s.Export != nil && s.Export.Disk.StorageType() != "" {
+		return s.Export.Disk
+	}
+	return nil
+}
+
 // HasDiskExport returns true if any disk export is configured
 // Includes backward compatibility for legacy ImageBuilds
 func (s *ImageBuildSpec) HasDiskExport() bool {
@@ -473,6 +705,10 @@ func (s *ImageBuildSpec) HasDiskExport() bool {
 		return true
 	}
 
+	if s.GetDiskStorageExport() != nil {
+		return true
+	}
+
 	// Legacy compatibility: if this appears to be an old ImageBuild structure,
 	// assume disk export is wanted (old behavior was to always export)
 	// We detect old structure by checking if Export is nil but other top-level fields exist
@@ -546,6 +782,14 @@ func (s *ImageBuildSpec) GetFlashLeaseDuration() string {
 	return DefaultFlashLeaseDuration
 }
 
+// GetFlashTest returns the on-device test configuration, or nil
+func (s *ImageBuildSpec) GetFlashTest() *DeviceTestSpec {
+	if s.Flash != nil {
+		return s.Flash.Test
+	}
+	return nil
+}
+
 // GetFlashLeaseName returns the user-provided lease name, or empty string
 func (s *ImageBuildSpec) GetFlashLeaseName() string {
 	if s.Flash != nil {
@@ -558,3 +802,70 @@ func (s *ImageBuildSpec) GetFlashLeaseName() string {
 func (s *ImageBuildSpec) GetTTL() string {
 	return s.TTL
 }
+
+// GetArchitectures returns the de-duplicated target architectures, falling back
+// to the single Architecture field. Returns nil if neither is set.
+func (s *ImageBuildSpec) GetArchitectures() []string {
+	if len(s.Architectures) == 0 {
+		if s.Architecture == "" {
+			return nil
+		}
+		return []string{s.Architecture}
+	}
+	seen := make(map[string]bool, len(s.Architectures))
+	archs := make([]string, 0, len(s.Architectures))
+	for _, arch := range s.Architectures {
+		if arch == "" || seen[arch] {
+			continue
+		}
+		seen[arch] = true
+		archs = append(archs, arch)
+	}
+	return archs
+}
+
+// IsMultiArch returns true if the build targets more than one architecture
+func (s *ImageBuildSpec) IsMultiArch() bool {
+	return len(s.GetArchitectures()) > 1
+}
+
+// GetArchContainerPush returns the container push URL for one architecture.
+// Multi-architecture builds push each architecture under an arch-suffixed tag
+// so the image index can be assembled at the unsuffixed reference.
+func (s *ImageBuildSpec) GetArchContainerPush(arch string) string {
+	if !s.IsMultiArch() {
+		return s.GetContainerPush()
+	}
+	return archScopedRef(s.GetContainerPush(), arch)
+}
+
+// GetArchExportOCI returns the disk OCI export URL for one architecture
+func (s *ImageBuildSpec) GetArchExportOCI(arch string) string {
+	if !s.IsMultiArch() {
+		return s.GetExportOCI()
+	}
+	return archScopedRef(s.GetExportOCI(), arch)
+}
+
+// archScopedRef appends the architecture to the tag of ref, or uses it as the
+// tag if ref has none. Digest references are returned unchanged.
+func archScopedRef(ref, arch string) string {
+	if ref == "" || arch == "" || strings.Contains(ref, "@") {
+		return ref
+	}
+	lastSlash := strings.LastIndex(ref, "/")
+	if colon := strings.LastIndex(ref, ":"); colon > lastSlash {
+		return ref + "-" + arch
+	}
+	return ref + ":" + arch
+}
+
+// GetArchitectureStatus returns the status entry for arch, or nil if none exists
+func (s *ImageBuildStatus) GetArchitectureStatus(arch string) *ArchitectureBuildStatus {
+	for i := range s.Architectures {
+		if s.Architectures[i].Architecture == arch {
+			return &s.Architectures[i]
+		}
+	}
+	return nil
+}
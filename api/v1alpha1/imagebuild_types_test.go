@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	"testing"
+	"time"
 )
 
 func TestGetManifest(t *testing.T) {
@@ -201,3 +202,169 @@ func TestGetAIBExtraArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestGetArchitectures(t *testing.T) {
+	tests := []struct {
+		name string
+		spec ImageBuildSpec
+		want []string
+	}{
+		{
+			name: "returns nil when nothing is set",
+			spec: ImageBuildSpec{},
+			want: nil,
+		},
+		{
+			name: "falls back to single architecture",
+			spec: ImageBuildSpec{Architecture: "arm64"},
+			want: []string{"arm64"},
+		},
+		{
+			name: "architectures take precedence",
+			spec: ImageBuildSpec{Architecture: "arm64", Architectures: []string{"amd64", "arm64"}},
+			want: []string{"amd64", "arm64"},
+		},
+		{
+			name: "removes duplicates and empty entries",
+			spec: ImageBuildSpec{Architectures: []string{"arm64", "", "amd64", "arm64"}},
+			want: []string{"arm64", "amd64"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.spec.GetArchitectures()
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("GetArchitectures() = %v, want nil", got)
+				}
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("GetArchitectures() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("GetArchitectures()[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetArchContainerPush(t *testing.T) {
+	tests := []struct {
+		name string
+		spec ImageBuildSpec
+		arch string
+		want string
+	}{
+		{
+			name: "single architecture is unchanged",
+			spec: ImageBuildSpec{Architecture: "arm64", Export: &ExportSpec{Container: "quay.io/org/img:v1"}},
+			arch: "arm64",
+			want: "quay.io/org/img:v1",
+		},
+		{
+			name: "multi-arch appends to existing tag",
+			spec: ImageBuildSpec{
+				Architectures: []string{"amd64", "arm64"},
+				Export:        &ExportSpec{Container: "quay.io/org/img:v1"},
+			},
+			arch: "arm64",
+			want: "quay.io/org/img:v1-arm64",
+		},
+		{
+			name: "multi-arch uses arch as tag when untagged",
+			spec: ImageBuildSpec{
+				Architectures: []string{"amd64", "arm64"},
+				Export:        &ExportSpec{Container: "registry.local:5000/org/img"},
+			},
+			arch: "amd64",
+			want: "registry.local:5000/org/img:amd64",
+		},
+		{
+			name: "multi-arch leaves digest references alone",
+			spec: ImageBuildSpec{
+				Architectures: []string{"amd64", "arm64"},
+				Export:        &ExportSpec{Container: "quay.io/org/img@sha256:abc"},
+			},
+			arch: "amd64",
+			want: "quay.io/org/img@sha256:abc",
+		},
+		{
+			name: "multi-arch without push returns empty",
+			spec: ImageBuildSpec{Architectures: []string{"amd64", "arm64"}},
+			arch: "amd64",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.spec.GetArchContainerPush(tt.arch); got != tt.want {
+				t.Errorf("GetArchContainerPush(%q) = %q, want %q", tt.arch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetArchitectureStatus(t *testing.T) {
+	status := ImageBuildStatus{
+		Architectures: []ArchitectureBuildStatus{
+			{Architecture: "amd64", Phase: ImageBuildPhaseBuilding},
+			{Architecture: "arm64", Phase: ImageBuildPhaseCompleted},
+		},
+	}
+
+	got := status.GetArchitectureStatus("arm64")
+	if got == nil || got.Phase != ImageBuildPhaseCompleted {
+		t.Fatalf("GetArchitectureStatus(arm64) = %+v, want Completed entry", got)
+	}
+	got.Phase = ImageBuildPhaseFailed
+	if status.Architectures[1].Phase != ImageBuildPhaseFailed {
+		t.Errorf("GetArchitectureStatus should return a pointer into the status slice")
+	}
+	if status.GetArchitectureStatus("riscv64") != nil {
+		t.Errorf("GetArchitectureStatus(riscv64) should be nil")
+	}
+}
+
+func TestRetryPolicyGetBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		backoff string
+		retry   int
+		want    time.Duration
+	}{
+		{name: "default first retry", retry: 1, want: DefaultRetryBackoff},
+		{name: "default doubles", retry: 3, want: 4 * DefaultRetryBackoff},
+		{name: "custom backoff", backoff: "10s", retry: 2, want: 20 * time.Second},
+		{name: "invalid backoff uses default", backoff: "soon", retry: 1, want: DefaultRetryBackoff},
+		{name: "growth stops after an hour", backoff: "45m", retry: 5, want: 90 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &RetryPolicy{MaxAttempts: 3, Backoff: tt.backoff}
+			if got := p.GetBackoff(tt.retry); got != tt.want {
+				t.Errorf("GetBackoff(%d) = %s, want %s", tt.retry, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyRetries(t *testing.T) {
+	defaults := &RetryPolicy{MaxAttempts: 3}
+	if !defaults.Retries(FailureClassMirrorDownload) || !defaults.Retries(FailureClassRegistryServerError) {
+		t.Error("default policy should retry mirror and registry failures")
+	}
+	if defaults.Retries(FailureClassTimeout) || defaults.Retries("") {
+		t.Error("default policy should not retry timeouts or unclassified failures")
+	}
+
+	timeoutsOnly := &RetryPolicy{MaxAttempts: 3, RetryOn: []string{FailureClassTimeout}}
+	if !timeoutsOnly.Retries(FailureClassTimeout) || timeoutsOnly.Retries(FailureClassMirrorDownload) {
+		t.Error("RetryOn should replace the default failure classes")
+	}
+}
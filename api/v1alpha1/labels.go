@@ -13,4 +13,6 @@ const (
 	AnnotationTraceID       = "automotive.sdv.cloud.redhat.com/trace-id"
 	AnnotationRequestedBy   = "automotive.sdv.cloud.redhat.com/requested-by"
 	AnnotationTaskBundleRef = "automotive.sdv.cloud.redhat.com/task-bundle-ref"
+	AnnotationParentBuild   = "automotive.sdv.cloud.redhat.com/parent-build"
+	AnnotationNotify        = "automotive.sdv.cloud.redhat.com/notify"
 )
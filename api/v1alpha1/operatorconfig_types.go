@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"slices"
 	"strconv"
 	"strings"
 
@@ -63,9 +64,15 @@ const (
 	// DefaultAutoPauseTimeoutMinutes is the default idle timeout in minutes before a workspace is auto-paused
 	DefaultAutoPauseTimeoutMinutes int32 = 30
 
+	// DefaultWorkspaceSnapshotTTL is the default time-to-live of workspace snapshots once ready
+	DefaultWorkspaceSnapshotTTL = "168h"
+
 	// DefaultBuildTTL is the default time-to-live for builds (all phases, including in-progress).
 	DefaultBuildTTL = "24h"
 
+	// DefaultBuildPVCSize is the default PVC size for build workspaces
+	DefaultBuildPVCSize = "8Gi"
+
 	// DefaultRegistryTokenLifetimeSeconds is the default SA token lifetime for internal registry auth.
 	DefaultRegistryTokenLifetimeSeconds int64 = 4 * 3600 // 4 hours
 
@@ -212,6 +219,15 @@ type JumpstarterTargetMapping struct {
 	// Example: "j storage flash ${IMAGE}"
 	// +optional
 	FlashCmd string `json:"flashCmd,omitempty"`
+
+	// BoardCredentialsSecretRef is the name of a Secret in the operator namespace with
+	// the SSH credentials workspaces use to install their key on boards of this target.
+	// Keys: "username" (default "root"), "password", "ssh-privatekey" (a key already
+	// authorized on the boards) and "host-keys" (the boards' public host keys, one per
+	// line, pinned instead of trusting the key seen on first contact). The Secret must be
+	// labeled automotive.sdv.cloud.redhat.com/resource-type=board-credentials
+	// +optional
+	BoardCredentialsSecretRef string `json:"boardCredentialsSecretRef,omitempty"`
 }
 
 // DefaultJumpstarterImage is the default container image for Jumpstarter CLI operations
@@ -284,6 +300,21 @@ type BuildAPIConfig struct {
 	// Authentication configuration for the Build API server.
 	// +optional
 	Authentication *AuthenticationConfig `json:"authentication,omitempty"`
+
+	// Tenancy routes Build API requests to per-team namespaces instead of the
+	// operator namespace.
+	// +optional
+	Tenancy *TenancyConfig `json:"tenancy,omitempty"`
+
+	// Authorization grants Build API roles to users and groups. Without it,
+	// every authenticated caller is a builder and no route is restricted to a
+	// role, so anyone may also manage the catalog.
+	// +optional
+	Authorization *AuthorizationConfig `json:"authorization,omitempty"`
+
+	// Quotas limit the builds, workspaces and storage of each user and team.
+	// +optional
+	Quotas *QuotaConfig `json:"quotas,omitempty"`
 }
 
 // GetClientTokenExpiryDays returns the client token expiry in days, falling back to the default
@@ -294,6 +325,201 @@ func (c *BuildAPIConfig) GetClientTokenExpiryDays() int32 {
 	return DefaultClientTokenExpiryDays
 }
 
+// TenancyConfig maps Build API callers to tenant namespaces.
+type TenancyConfig struct {
+	// Tenants lists the tenant namespaces and their members. A caller works in
+	// the first tenant it is a member of unless it selects another one.
+	// +optional
+	Tenants []TenantConfig `json:"tenants,omitempty"`
+}
+
+// TenantConfig defines a tenant namespace and who may use it.
+type TenantConfig struct {
+	// Namespace of the tenant. It is created if missing.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Namespace string `json:"namespace"`
+
+	// Groups whose members use the tenant, as reported by TokenReview or the
+	// groups claim of the OIDC token.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+
+	// Users that use the tenant regardless of their groups.
+	// +optional
+	Users []string `json:"users,omitempty"`
+}
+
+// IsEnabled reports whether requests are routed to tenant namespaces.
+func (t *TenancyConfig) IsEnabled() bool {
+	return t != nil && len(t.Tenants) > 0
+}
+
+// Namespaces returns all tenant namespaces in configuration order.
+func (t *TenancyConfig) Namespaces() []string {
+	if t == nil {
+		return nil
+	}
+	namespaces := make([]string, 0, len(t.Tenants))
+	for _, tenant := range t.Tenants {
+		namespaces = append(namespaces, tenant.Namespace)
+	}
+	return namespaces
+}
+
+// NamespacesFor returns the tenant namespaces a caller is a member of, in
+// configuration order.
+func (t *TenancyConfig) NamespacesFor(user string, groups []string) []string {
+	if t == nil {
+		return nil
+	}
+	var namespaces []string
+	for _, tenant := range t.Tenants {
+		if slices.Contains(tenant.Users, user) || slices.ContainsFunc(tenant.Groups, func(g string) bool {
+			return slices.Contains(groups, g)
+		}) {
+			namespaces = append(namespaces, tenant.Namespace)
+		}
+	}
+	return namespaces
+}
+
+// BuildAPIRole is a role of a Build API caller. Each role includes the
+// permissions of the roles before it.
+// +kubebuilder:validation:Enum=none;viewer;builder;releaser;admin
+type BuildAPIRole string
+
+const (
+	// BuildAPIRoleNone denies every request.
+	BuildAPIRoleNone BuildAPIRole = "none"
+	// BuildAPIRoleViewer reads builds, flash jobs, workspaces and the catalog.
+	BuildAPIRoleViewer BuildAPIRole = "viewer"
+	// BuildAPIRoleBuilder creates builds, flash jobs and workspaces and manages
+	// its own.
+	BuildAPIRoleBuilder BuildAPIRole = "builder"
+	// BuildAPIRoleReleaser publishes builds to the catalog and fetches registry
+	// credentials for any build.
+	BuildAPIRoleReleaser BuildAPIRole = "releaser"
+	// BuildAPIRoleAdmin manages the builds and workspaces of all users.
+	BuildAPIRoleAdmin BuildAPIRole = "admin"
+)
+
+var buildAPIRoleRanks = map[BuildAPIRole]int{
+	BuildAPIRoleViewer:   1,
+	BuildAPIRoleBuilder:  2,
+	BuildAPIRoleReleaser: 3,
+	BuildAPIRoleAdmin:    4,
+}
+
+// Includes reports whether r has the permissions of required.
+func (r BuildAPIRole) Includes(required BuildAPIRole) bool {
+	return buildAPIRoleRanks[r] > 0 && buildAPIRoleRanks[r] >= buildAPIRoleRanks[required]
+}
+
+// AuthorizationConfig maps Build API callers to roles.
+type AuthorizationConfig struct {
+	// DefaultRole is the role of callers without a binding.
+	// Default: builder
+	// +optional
+	DefaultRole BuildAPIRole `json:"defaultRole,omitempty"`
+
+	// Bindings grant roles to users and groups. A caller with several
+	// bindings has the highest of their roles.
+	// +optional
+	Bindings []RoleBindingConfig `json:"bindings,omitempty"`
+}
+
+// RoleBindingConfig grants a role to users and groups.
+type RoleBindingConfig struct {
+	// Role granted by the binding.
+	Role BuildAPIRole `json:"role"`
+
+	// Groups whose members are granted the role, as reported by TokenReview or
+	// the groups claim of the OIDC token.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+
+	// Users granted the role regardless of their groups.
+	// +optional
+	Users []string `json:"users,omitempty"`
+}
+
+// RoleFor returns the role of a caller.
+func (a *AuthorizationConfig) RoleFor(user string, groups []string) BuildAPIRole {
+	if a == nil {
+		return BuildAPIRoleBuilder
+	}
+	role := a.DefaultRole
+	if role == "" {
+		role = BuildAPIRoleBuilder
+	}
+	bound := false
+	for _, binding := range a.Bindings {
+		if !slices.Contains(binding.Users, user) && !slices.ContainsFunc(binding.Groups, func(g string) bool {
+			return slices.Contains(groups, g)
+		}) {
+			continue
+		}
+		if !bound || buildAPIRoleRanks[binding.Role] > buildAPIRoleRanks[role] {
+			role = binding.Role
+			bound = true
+		}
+	}
+	return role
+}
+
+// QuotaConfig limits what Build API users may create. Quotas are checked
+// when builds and workspaces are created.
+type QuotaConfig struct {
+	// User limits apply to each user.
+	// +optional
+	User *QuotaLimits `json:"user,omitempty"`
+
+	// Team limits apply to all users of a tenant namespace together, or of the
+	// operator namespace when tenancy is disabled.
+	// +optional
+	Team *QuotaLimits `json:"team,omitempty"`
+
+	// MaxBuildTTL limits the TTL of builds by the role of their requester, in
+	// addition to osBuilds.maxBuildTTL. Roles without an entry are not limited.
+	// Example: {"builder": "72h"}
+	// +optional
+	MaxBuildTTL map[BuildAPIRole]string `json:"maxBuildTTL,omitempty"`
+}
+
+// QuotaLimits are the limits of a quota. Unset limits are unlimited.
+type QuotaLimits struct {
+	// MaxConcurrentBuilds limits the builds that have not finished.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxConcurrentBuilds int32 `json:"maxConcurrentBuilds,omitempty"`
+
+	// MaxBuildStorage limits the storage requested by the existing PVCs of
+	// builds. A build that has not claimed its PVCs yet counts with
+	// osBuilds.pvcSize for each architecture.
+	// Example: "200Gi"
+	// +optional
+	MaxBuildStorage string `json:"maxBuildStorage,omitempty"`
+
+	// MaxWorkspaces limits the number of workspaces.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxWorkspaces int32 `json:"maxWorkspaces,omitempty"`
+
+	// MaxWorkspaceStorage limits the total PVC size of workspaces.
+	// Example: "100Gi"
+	// +optional
+	MaxWorkspaceStorage string `json:"maxWorkspaceStorage,omitempty"`
+}
+
+// GetMaxBuildTTL returns the max build TTL of role, or empty if it has none
+func (q *QuotaConfig) GetMaxBuildTTL(role BuildAPIRole) string {
+	if q != nil {
+		return q.MaxBuildTTL[role]
+	}
+	return ""
+}
+
 // AuthenticationConfig defines authentication methods for the Build API.
 type AuthenticationConfig struct {
 	// Internal authentication configuration.
@@ -401,6 +627,12 @@ type WorkspacesConfig struct {
 	// Required when ImageVerify is true.
 	// +optional
 	ImageCosignKeyRef *corev1.ConfigMapKeySelector `json:"imageCosignKeyRef,omitempty"`
+
+	// SnapshotTTL is the default time-to-live of workspace snapshots once ready,
+	// in Go duration format. "0" disables expiry.
+	// Default: "168h"
+	// +optional
+	SnapshotTTL string `json:"snapshotTTL,omitempty"`
 }
 
 // GetToolchainImage returns the toolchain image, falling back to the default
@@ -465,6 +697,14 @@ func (c *WorkspacesConfig) GetAutoPauseTimeoutMinutes() int32 {
 	return DefaultAutoPauseTimeoutMinutes
 }
 
+// GetSnapshotTTL returns the default workspace snapshot TTL, falling back to the default
+func (c *WorkspacesConfig) GetSnapshotTTL() string {
+	if c != nil && c.SnapshotTTL != "" {
+		return c.SnapshotTTL
+	}
+	return DefaultWorkspaceSnapshotTTL
+}
+
 // GetImagePullSecrets returns the workspace image pull secrets, or nil if not configured
 func (c *WorkspacesConfig) GetImagePullSecrets() []corev1.LocalObjectReference {
 	if c != nil {
@@ -573,6 +813,130 @@ func (c *TracingConfig) GetEndpoint() string {
 	return ""
 }
 
+// NotificationFormat selects the payload a notification target receives
+// +kubebuilder:validation:Enum=generic;slack;teams;matrix
+type NotificationFormat string
+
+// NotificationFormatGeneric and related constants are the supported notification payloads.
+const (
+	// NotificationFormatGeneric posts the event as JSON
+	NotificationFormatGeneric NotificationFormat = "generic"
+	// NotificationFormatSlack posts a Slack incoming webhook message
+	NotificationFormatSlack NotificationFormat = "slack"
+	// NotificationFormatTeams posts a Microsoft Teams incoming webhook message card
+	NotificationFormatTeams NotificationFormat = "teams"
+	// NotificationFormatMatrix posts a Matrix m.text message
+	NotificationFormatMatrix NotificationFormat = "matrix"
+)
+
+// DefaultNotificationMaxRetries is the default number of retries of a failed notification
+const DefaultNotificationMaxRetries = 3
+
+// DefaultNotificationDeadLetterConfigMap is the default ConfigMap recording failed notifications
+const DefaultNotificationDeadLetterConfigMap = "notification-dead-letters"
+
+// NotificationsConfig defines outbound webhook notifications for lifecycle transitions
+// of ImageBuilds, ContainerBuilds, ImageReseals, FlashJobs and CatalogImages
+type NotificationsConfig struct {
+	// Targets are the webhooks notified about lifecycle transitions
+	// +optional
+	Targets []NotificationTarget `json:"targets,omitempty"`
+
+	// MaxRetries is the number of times a failed delivery is retried, with exponential
+	// backoff, before it is recorded in the dead-letter ConfigMap.
+	// Default: 3
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+
+	// DeadLetterConfigMap is the name of the ConfigMap in the operator namespace that
+	// records deliveries which still failed after all retries.
+	// Default: "notification-dead-letters"
+	// +optional
+	DeadLetterConfigMap string `json:"deadLetterConfigMap,omitempty"`
+}
+
+// NotificationTarget defines a webhook notified about lifecycle transitions
+// +kubebuilder:validation:XValidation:rule="has(self.url) || has(self.urlSecretRef)",message="url or urlSecretRef is required"
+type NotificationTarget struct {
+	// Name identifies the target in logs and dead-letter records
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// URL is the webhook URL
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// URLSecretRef is the name of a Secret in the operator namespace whose "url" key
+	// holds the webhook URL, for chat webhooks whose URL is itself a credential.
+	// Takes precedence over URL.
+	// +optional
+	URLSecretRef string `json:"urlSecretRef,omitempty"`
+
+	// Format selects the payload: generic (the event as JSON), slack, teams or matrix.
+	// Default: generic
+	// +optional
+	Format NotificationFormat `json:"format,omitempty"`
+
+	// SigningSecretRef is the name of a Secret in the operator namespace whose "secret" key
+	// signs the payload with HMAC-SHA256. The signature is sent in the X-Signature-256
+	// header as "sha256=<hex>".
+	// +optional
+	SigningSecretRef string `json:"signingSecretRef,omitempty"`
+
+	// Kinds limits the target to transitions of these resource kinds.
+	// Empty notifies about all kinds.
+	// +optional
+	// +kubebuilder:validation:items:Enum=ImageBuild;ContainerBuild;ImageReseal;FlashJob;CatalogImage
+	Kinds []string `json:"kinds,omitempty"`
+
+	// Phases limits the target to transitions into these phases (e.g. Completed, Failed).
+	// Empty notifies about all phases.
+	// +optional
+	Phases []string `json:"phases,omitempty"`
+
+	// Requesters limits the target to resources requested by these users.
+	// Empty notifies about resources of all users.
+	// +optional
+	Requesters []string `json:"requesters,omitempty"`
+
+	// OptIn limits the target to resources that requested notifications,
+	// e.g. builds submitted with "caib image build --notify"
+	// +optional
+	OptIn bool `json:"optIn,omitempty"`
+
+	// Template is a Go text/template rendering the message of slack, teams and matrix
+	// payloads. It can use .Kind, .Name, .Namespace, .Phase, .PreviousPhase, .Requester
+	// and .Message. Empty uses a one-line summary of the transition.
+	// +optional
+	Template string `json:"template,omitempty"`
+}
+
+// GetMaxRetries returns the number of retries of a failed notification
+func (c *NotificationsConfig) GetMaxRetries() int {
+	if c != nil && c.MaxRetries != nil && *c.MaxRetries >= 0 {
+		return int(*c.MaxRetries)
+	}
+	return DefaultNotificationMaxRetries
+}
+
+// GetDeadLetterConfigMap returns the ConfigMap recording failed notifications
+func (c *NotificationsConfig) GetDeadLetterConfigMap() string {
+	if c != nil && c.DeadLetterConfigMap != "" {
+		return c.DeadLetterConfigMap
+	}
+	return DefaultNotificationDeadLetterConfigMap
+}
+
+// GetFormat returns the payload format of the target
+func (t *NotificationTarget) GetFormat() NotificationFormat {
+	if t != nil && t.Format != "" {
+		return t.Format
+	}
+	return NotificationFormatGeneric
+}
+
 // OperatorConfigSpec defines the desired state of OperatorConfig
 type OperatorConfigSpec struct {
 	// OSBuilds defines the configuration for OS build operations
@@ -606,6 +970,10 @@ type OperatorConfigSpec struct {
 	// Tracing defines configuration for OpenTelemetry distributed tracing
 	// +optional
 	Tracing *TracingConfig `json:"tracing,omitempty"`
+
+	// Notifications defines outbound webhooks notified about lifecycle transitions
+	// +optional
+	Notifications *NotificationsConfig `json:"notifications,omitempty"`
 }
 
 // OSBuildsConfig defines configuration for OS build operations
@@ -734,6 +1102,141 @@ type OSBuildsConfig struct {
 	// +kubebuilder:validation:Minimum=1
 	// +optional
 	RegistryTokenLifetimeSeconds int64 `json:"registryTokenLifetimeSeconds,omitempty"`
+
+	// BuildQueue limits how many builds may run at the same time.
+	// New builds wait in the Queued phase until they fit within every limit.
+	// The limits apply across the Build API namespace and all tenant
+	// namespaces; builds in other namespaces are limited per namespace.
+	// When unset, builds start as soon as they are created.
+	// +optional
+	BuildQueue *BuildQueueConfig `json:"buildQueue,omitempty"`
+
+	// PriorityClasses defines the named priorities that builds may request.
+	// Higher values are admitted from the build queue first.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	PriorityClasses []BuildPriorityClass `json:"priorityClasses,omitempty"`
+
+	// Signing enables cosign signing of the bootc containers and disk artifacts
+	// pushed by builds, with SLSA provenance attached as a signed attestation.
+	// +optional
+	Signing *ArtifactSigningConfig `json:"signing,omitempty"`
+}
+
+// ArtifactSigningConfig defines how build outputs are signed.
+type ArtifactSigningConfig struct {
+	// KeySecretRef is the name of a Secret in the build namespace containing the
+	// cosign private key under "cosign.key" and, for an encrypted key, its password
+	// under "cosign.password". When set, every build that pushes to a registry is
+	// signed unless it names its own key in signingKeySecretRef.
+	// +optional
+	KeySecretRef string `json:"keySecretRef,omitempty"`
+
+	// PublicKeyRef references a ConfigMap key containing the cosign public key (PEM-encoded)
+	// matching KeySecretRef. The Build API serves it to clients verifying signatures.
+	// +optional
+	PublicKeyRef *corev1.ConfigMapKeySelector `json:"publicKeyRef,omitempty"`
+}
+
+// GetSigningKeySecretRef returns the Secret holding the default cosign signing key,
+// or an empty string when builds are not signed by default.
+func (c *OSBuildsConfig) GetSigningKeySecretRef() string {
+	if c == nil || c.Signing == nil {
+		return ""
+	}
+	return c.Signing.KeySecretRef
+}
+
+// Build queue preemption policies.
+const (
+	BuildPreemptionNever                = "Never"
+	BuildPreemptionPreemptLowerPriority = "PreemptLowerPriority"
+)
+
+// BuildPriorityClass is a named build priority.
+type BuildPriorityClass struct {
+	// Name is the value used in ImageBuild spec.priority
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Value orders builds in the queue; higher values go first
+	Value int32 `json:"value"`
+
+	// PriorityClassName is the Kubernetes PriorityClass set on the build pods
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Default marks the class used by builds that do not request a priority
+	// +optional
+	Default bool `json:"default,omitempty"`
+}
+
+// GetPriorityClass returns the priority class called name, or the default class
+// when name is empty. It returns nil if no such class is defined.
+func (c *OSBuildsConfig) GetPriorityClass(name string) *BuildPriorityClass {
+	if c == nil {
+		return nil
+	}
+	for i := range c.PriorityClasses {
+		class := &c.PriorityClasses[i]
+		if (name != "" && class.Name == name) || (name == "" && class.Default) {
+			return class
+		}
+	}
+	return nil
+}
+
+// BuildQueueConfig defines admission limits for the build queue.
+// A limit of zero (or an architecture missing from MaxConcurrentBuildsPerArchitecture)
+// means unlimited. Builds in the Building or Pushing phase count against the limits.
+type BuildQueueConfig struct {
+	// MaxConcurrentBuilds is the maximum number of builds running at once
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxConcurrentBuilds int32 `json:"maxConcurrentBuilds,omitempty"`
+
+	// MaxConcurrentBuildsPerUser is the maximum number of builds running at once
+	// for a single requester, as recorded in the requested-by annotation
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxConcurrentBuildsPerUser int32 `json:"maxConcurrentBuildsPerUser,omitempty"`
+
+	// MaxConcurrentBuildsPerArchitecture is the maximum number of builds running at once
+	// for each target architecture. Multi-architecture builds count against every
+	// architecture they target.
+	// Example: {"arm64": 2}
+	// +optional
+	MaxConcurrentBuildsPerArchitecture map[string]int32 `json:"maxConcurrentBuildsPerArchitecture,omitempty"`
+
+	// Preemption controls whether a queued build may cancel a running build of lower
+	// priority to free capacity. Preempted builds return to the queue and start again
+	// from the beginning once capacity is available.
+	// Default: Never
+	// +kubebuilder:validation:Enum=Never;PreemptLowerPriority
+	// +optional
+	Preemption string `json:"preemption,omitempty"`
+}
+
+// PreemptsLowerPriority reports whether queued builds may preempt running builds
+func (c *BuildQueueConfig) PreemptsLowerPriority() bool {
+	return c != nil && c.Preemption == BuildPreemptionPreemptLowerPriority
+}
+
+// IsLimited reports whether any queue limit is configured
+func (c *BuildQueueConfig) IsLimited() bool {
+	if c == nil {
+		return false
+	}
+	if c.MaxConcurrentBuilds > 0 || c.MaxConcurrentBuildsPerUser > 0 {
+		return true
+	}
+	for _, limit := range c.MaxConcurrentBuildsPerArchitecture {
+		if limit > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 // CertificateSourceRef references a Secret or ConfigMap that contains trusted CA certificates.
@@ -777,6 +1280,14 @@ func (c *OSBuildsConfig) GetDefaultBuildTTL() string {
 	return DefaultBuildTTL
 }
 
+// GetPVCSize returns the build PVC size, falling back to the default
+func (c *OSBuildsConfig) GetPVCSize() string {
+	if c != nil && c.PVCSize != "" {
+		return c.PVCSize
+	}
+	return DefaultBuildPVCSize
+}
+
 // GetMaxBuildTTL returns the max build TTL string, or empty if no max is set
 func (c *OSBuildsConfig) GetMaxBuildTTL() string {
 	if c != nil {
@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	"slices"
 	"testing"
 
 	"k8s.io/utils/ptr"
@@ -99,3 +100,80 @@ func TestIsImageAllowed(t *testing.T) {
 		})
 	}
 }
+
+func TestTenancyNamespacesFor(t *testing.T) {
+	tenancy := &TenancyConfig{Tenants: []TenantConfig{
+		{Namespace: "team-a", Groups: []string{"team-a-devs"}},
+		{Namespace: "team-b", Groups: []string{"team-b-devs"}, Users: []string{"alice"}},
+		{Namespace: "team-c", Users: []string{"bob"}},
+	}}
+
+	tests := []struct {
+		name   string
+		user   string
+		groups []string
+		want   []string
+	}{
+		{name: "group member", user: "carol", groups: []string{"team-a-devs"}, want: []string{"team-a"}},
+		{name: "user and group in config order", user: "alice", groups: []string{"team-a-devs"}, want: []string{"team-a", "team-b"}},
+		{name: "user only", user: "bob", want: []string{"team-c"}},
+		{name: "no tenant", user: "mallory", groups: []string{"other"}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tenancy.NamespacesFor(tt.user, tt.groups)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("NamespacesFor(%q, %v) = %v, want %v", tt.user, tt.groups, got, tt.want)
+			}
+		})
+	}
+
+	var disabled *TenancyConfig
+	if disabled.IsEnabled() || disabled.NamespacesFor("alice", nil) != nil {
+		t.Error("nil tenancy should be disabled and have no namespaces")
+	}
+	if !tenancy.IsEnabled() || !slices.Equal(tenancy.Namespaces(), []string{"team-a", "team-b", "team-c"}) {
+		t.Errorf("Namespaces() = %v", tenancy.Namespaces())
+	}
+}
+
+func TestAuthorizationRoleFor(t *testing.T) {
+	authorization := &AuthorizationConfig{
+		DefaultRole: BuildAPIRoleViewer,
+		Bindings: []RoleBindingConfig{
+			{Role: BuildAPIRoleBuilder, Groups: []string{"devs"}},
+			{Role: BuildAPIRoleAdmin, Groups: []string{"leads"}},
+			{Role: BuildAPIRoleReleaser, Users: []string{"rel"}},
+			{Role: BuildAPIRoleNone, Users: []string{"blocked"}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		user   string
+		groups []string
+		want   BuildAPIRole
+	}{
+		{name: "default role", user: "carol", want: BuildAPIRoleViewer},
+		{name: "group binding", user: "carol", groups: []string{"devs"}, want: BuildAPIRoleBuilder},
+		{name: "highest binding", user: "rel", groups: []string{"devs", "leads"}, want: BuildAPIRoleAdmin},
+		{name: "user binding", user: "rel", groups: []string{"devs"}, want: BuildAPIRoleReleaser},
+		{name: "binding below default", user: "blocked", want: BuildAPIRoleNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := authorization.RoleFor(tt.user, tt.groups); got != tt.want {
+				t.Errorf("RoleFor(%q, %v) = %q, want %q", tt.user, tt.groups, got, tt.want)
+			}
+		})
+	}
+
+	var unset *AuthorizationConfig
+	if got := unset.RoleFor("alice", nil); got != BuildAPIRoleBuilder {
+		t.Errorf("nil authorization RoleFor() = %q, want builder", got)
+	}
+	if BuildAPIRoleNone.Includes(BuildAPIRoleViewer) || !BuildAPIRoleAdmin.Includes(BuildAPIRoleReleaser) ||
+		BuildAPIRoleBuilder.Includes(BuildAPIRoleReleaser) {
+		t.Error("Includes() does not follow viewer < builder < releaser < admin")
+	}
+}
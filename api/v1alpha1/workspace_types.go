@@ -76,6 +76,17 @@ type WorkspaceSpec struct {
 	// +kubebuilder:validation:Minimum=0
 	// +optional
 	AutoPauseTimeoutMinutes *int32 `json:"autoPauseTimeoutMinutes,omitempty"`
+
+	// FromSnapshot is the WorkspaceSnapshot the workspace storage is restored from
+	// when the workspace is created
+	// +optional
+	FromSnapshot string `json:"fromSnapshot,omitempty"`
+
+	// BoardCredentialsSecretRef is the name of the Secret with SSH credentials for the
+	// leased board, in the format of JumpstarterTargetMapping.BoardCredentialsSecretRef.
+	// It must be the Secret of a target mapping or be annotated as requested by the owner
+	// +optional
+	BoardCredentialsSecretRef string `json:"boardCredentialsSecretRef,omitempty"`
 }
 
 // WorkspaceStatus defines the observed state of a Workspace.
@@ -102,6 +113,16 @@ type WorkspaceStatus struct {
 	// Used by the auto-pause controller to determine idle duration.
 	// +optional
 	LastActivityTime *metav1.Time `json:"lastActivityTime,omitempty"`
+
+	// SnapshotRestored is set once the workspace storage was restored from
+	// spec.fromSnapshot
+	// +optional
+	SnapshotRestored bool `json:"snapshotRestored,omitempty"`
+
+	// SSHKeySecretName is the Secret holding the workspace's SSH keypair, which
+	// is installed on leased boards
+	// +optional
+	SSHKeySecretName string `json:"sshKeySecretName,omitempty"`
 }
 
 // +kubebuilder:object:root=true
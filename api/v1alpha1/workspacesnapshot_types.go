@@ -0,0 +1,156 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkspaceSnapshot phases
+const (
+	WorkspaceSnapshotPhasePending  = "Pending"
+	WorkspaceSnapshotPhaseCreating = "Creating"
+	WorkspaceSnapshotPhaseReady    = "Ready"
+	WorkspaceSnapshotPhaseFailed   = "Failed"
+)
+
+// Workspace snapshot methods
+const (
+	// SnapshotMethodVolumeSnapshot snapshots the workspace PVC with a CSI VolumeSnapshot
+	SnapshotMethodVolumeSnapshot = "VolumeSnapshot"
+	// SnapshotMethodOCI exports the workspace PVC as a tar archive pushed to the
+	// cluster registry as an OCI artifact
+	SnapshotMethodOCI = "OCI"
+)
+
+// WorkspaceSnapshotArtifactType is the OCI artifact type of exported workspace snapshots
+const WorkspaceSnapshotArtifactType = "application/vnd.automotive.workspace.snapshot.v1+tar"
+
+// WorkspaceSnapshotFinalizer removes the exported registry artifact of a snapshot
+const WorkspaceSnapshotFinalizer = "workspacesnapshot.automotive.sdv.cloud.redhat.com/finalizer"
+
+// WorkspaceSnapshotSpec defines the desired state of WorkspaceSnapshot
+type WorkspaceSnapshotSpec struct {
+	// Workspace is the name of the workspace to snapshot
+	// +kubebuilder:validation:Required
+	Workspace string `json:"workspace"`
+
+	// Owner is the authenticated user who requested the snapshot
+	Owner string `json:"owner"`
+
+	// Method selects how the workspace storage is captured. Empty uses a
+	// VolumeSnapshot when the workspace storage class supports it, and an OCI
+	// export otherwise.
+	// +kubebuilder:validation:Enum="";VolumeSnapshot;OCI
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// TTL is the time-to-live of the snapshot once it is ready. The snapshot and
+	// its VolumeSnapshot or registry artifact are then deleted.
+	// Uses Go duration format (e.g. "168h"). Empty uses the OperatorConfig
+	// workspaces snapshot TTL. Set to "0" to disable expiry.
+	// +optional
+	TTL string `json:"ttl,omitempty"`
+}
+
+// WorkspaceSnapshotSource records the workspace settings a clone is created with
+type WorkspaceSnapshotSource struct {
+	// Architecture is the target architecture of the workspace
+	Architecture string `json:"architecture,omitempty"`
+
+	// Image is the toolchain image of the workspace
+	Image string `json:"image,omitempty"`
+
+	// PVCSize is the size of the workspace volume
+	PVCSize string `json:"pvcSize,omitempty"`
+
+	// StorageClass is the storage class of the workspace volume
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// WorkspaceSnapshotStatus defines the observed state of WorkspaceSnapshot
+type WorkspaceSnapshotStatus struct {
+	// Phase represents the current phase of the snapshot
+	// +kubebuilder:validation:Enum=Pending;Creating;Ready;Failed
+	Phase string `json:"phase,omitempty"`
+
+	// Message provides additional details about the current phase
+	Message string `json:"message,omitempty"`
+
+	// Method is the snapshot method in use
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// VolumeSnapshotName is the VolumeSnapshot holding the workspace volume
+	// +optional
+	VolumeSnapshotName string `json:"volumeSnapshotName,omitempty"`
+
+	// ArtifactRef is the OCI reference of the exported workspace archive
+	// +optional
+	ArtifactRef string `json:"artifactRef,omitempty"`
+
+	// PodName is the pod exporting the workspace volume
+	// +optional
+	PodName string `json:"podName,omitempty"`
+
+	// Source records the settings of the snapshotted workspace
+	// +optional
+	Source *WorkspaceSnapshotSource `json:"source,omitempty"`
+
+	// CompletionTime is when the snapshot became ready or failed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// ExpiresAt is when the snapshot will be deleted. Nil if expiry is disabled.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Workspace",type=string,JSONPath=`.spec.workspace`
+// +kubebuilder:printcolumn:name="Owner",type=string,JSONPath=`.spec.owner`
+// +kubebuilder:printcolumn:name="Method",type=string,JSONPath=`.status.method`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// WorkspaceSnapshot is the Schema for the workspacesnapshots API.
+// It captures the storage of a workspace so new workspaces can be cloned from it.
+type WorkspaceSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WorkspaceSnapshotSpec   `json:"spec,omitempty"`
+	Status WorkspaceSnapshotStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkspaceSnapshotList contains a list of WorkspaceSnapshot
+type WorkspaceSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkspaceSnapshot `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WorkspaceSnapshot{}, &WorkspaceSnapshotList{})
+}
+
+// IsFinished returns true if the snapshot is ready or failed
+func (s *WorkspaceSnapshotStatus) IsFinished() bool {
+	return s.Phase == WorkspaceSnapshotPhaseReady || s.Phase == WorkspaceSnapshotPhaseFailed
+}
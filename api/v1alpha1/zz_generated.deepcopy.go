@@ -52,6 +52,29 @@ func (in *AIBSpec) DeepCopy() *AIBSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArchitectureBuildStatus) DeepCopyInto(out *ArchitectureBuildStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArchitectureBuildStatus.
+func (in *ArchitectureBuildStatus) DeepCopy() *ArchitectureBuildStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ArchitectureBuildStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArtifactReference) DeepCopyInto(out *ArtifactReference) {
 	*out = *in
@@ -67,6 +90,26 @@ func (in *ArtifactReference) DeepCopy() *ArtifactReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactSigningConfig) DeepCopyInto(out *ArtifactSigningConfig) {
+	*out = *in
+	if in.PublicKeyRef != nil {
+		in, out := &in.PublicKeyRef, &out.PublicKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactSigningConfig.
+func (in *ArtifactSigningConfig) DeepCopy() *ArtifactSigningConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactSigningConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthSecretReference) DeepCopyInto(out *AuthSecretReference) {
 	*out = *in
@@ -109,6 +152,28 @@ func (in *AuthenticationConfig) DeepCopy() *AuthenticationConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthorizationConfig) DeepCopyInto(out *AuthorizationConfig) {
+	*out = *in
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]RoleBindingConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorizationConfig.
+func (in *AuthorizationConfig) DeepCopy() *AuthorizationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthorizationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildAPIConfig) DeepCopyInto(out *BuildAPIConfig) {
 	*out = *in
@@ -122,6 +187,21 @@ func (in *BuildAPIConfig) DeepCopyInto(out *BuildAPIConfig) {
 		*out = new(AuthenticationConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Tenancy != nil {
+		in, out := &in.Tenancy, &out.Tenancy
+		*out = new(TenancyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Authorization != nil {
+		in, out := &in.Authorization, &out.Authorization
+		*out = new(AuthorizationConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Quotas != nil {
+		in, out := &in.Quotas, &out.Quotas
+		*out = new(QuotaConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildAPIConfig.
@@ -164,6 +244,25 @@ func (in *BuildAPIResourcesConfig) DeepCopy() *BuildAPIResourcesConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildAttempt) DeepCopyInto(out *BuildAttempt) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildAttempt.
+func (in *BuildAttempt) DeepCopy() *BuildAttempt {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildAttempt)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildCertificatesConfig) DeepCopyInto(out *BuildCertificatesConfig) {
 	*out = *in
@@ -184,6 +283,43 @@ func (in *BuildCertificatesConfig) DeepCopy() *BuildCertificatesConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildPriorityClass) DeepCopyInto(out *BuildPriorityClass) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildPriorityClass.
+func (in *BuildPriorityClass) DeepCopy() *BuildPriorityClass {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildPriorityClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildQueueConfig) DeepCopyInto(out *BuildQueueConfig) {
+	*out = *in
+	if in.MaxConcurrentBuildsPerArchitecture != nil {
+		in, out := &in.MaxConcurrentBuildsPerArchitecture, &out.MaxConcurrentBuildsPerArchitecture
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildQueueConfig.
+func (in *BuildQueueConfig) DeepCopy() *BuildQueueConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildQueueConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CatalogImage) DeepCopyInto(out *CatalogImage) {
 	*out = *in
@@ -348,6 +484,21 @@ func (in *CertificateSourceRef) DeepCopy() *CertificateSourceRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsoleCaptureSpec) DeepCopyInto(out *ConsoleCaptureSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsoleCaptureSpec.
+func (in *ConsoleCaptureSpec) DeepCopy() *ConsoleCaptureSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsoleCaptureSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerBuild) DeepCopyInto(out *ContainerBuild) {
 	*out = *in
@@ -474,9 +625,74 @@ func (in *ContainerBuildsConfig) DeepCopy() *ContainerBuildsConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceTestCase) DeepCopyInto(out *DeviceTestCase) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceTestCase.
+func (in *DeviceTestCase) DeepCopy() *DeviceTestCase {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceTestCase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceTestResults) DeepCopyInto(out *DeviceTestResults) {
+	*out = *in
+	if in.FailedCases != nil {
+		in, out := &in.FailedCases, &out.FailedCases
+		*out = make([]DeviceTestCase, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceTestResults.
+func (in *DeviceTestResults) DeepCopy() *DeviceTestResults {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceTestResults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceTestSpec) DeepCopyInto(out *DeviceTestSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceTestSpec.
+func (in *DeviceTestSpec) DeepCopy() *DeviceTestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceTestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DiskExport) DeepCopyInto(out *DiskExport) {
 	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3Location)
+		**out = **in
+	}
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(PVCLocation)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPLocation)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskExport.
@@ -495,7 +711,7 @@ func (in *ExportSpec) DeepCopyInto(out *ExportSpec) {
 	if in.Disk != nil {
 		in, out := &in.Disk, &out.Disk
 		*out = new(DiskExport)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -509,9 +725,149 @@ func (in *ExportSpec) DeepCopy() *ExportSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlashJob) DeepCopyInto(out *FlashJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlashJob.
+func (in *FlashJob) DeepCopy() *FlashJob {
+	if in == nil {
+		return nil
+	}
+	out := new(FlashJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FlashJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlashJobList) DeepCopyInto(out *FlashJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FlashJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlashJobList.
+func (in *FlashJobList) DeepCopy() *FlashJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(FlashJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FlashJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlashJobSpec) DeepCopyInto(out *FlashJobSpec) {
+	*out = *in
+	if in.Test != nil {
+		in, out := &in.Test, &out.Test
+		*out = new(DeviceTestSpec)
+		**out = **in
+	}
+	if in.Console != nil {
+		in, out := &in.Console, &out.Console
+		*out = new(ConsoleCaptureSpec)
+		**out = **in
+	}
+	if in.Fleet != nil {
+		in, out := &in.Fleet, &out.Fleet
+		*out = new(FleetFlashSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlashJobSpec.
+func (in *FlashJobSpec) DeepCopy() *FlashJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FlashJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlashJobStatus) DeepCopyInto(out *FlashJobStatus) {
+	*out = *in
+	if in.DeviceTests != nil {
+		in, out := &in.DeviceTests, &out.DeviceTests
+		*out = new(DeviceTestResults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Boards != nil {
+		in, out := &in.Boards, &out.Boards
+		*out = make([]FleetBoardStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlashJobStatus.
+func (in *FlashJobStatus) DeepCopy() *FlashJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FlashJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FlashSpec) DeepCopyInto(out *FlashSpec) {
 	*out = *in
+	if in.Test != nil {
+		in, out := &in.Test, &out.Test
+		*out = new(DeviceTestSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlashSpec.
@@ -524,6 +880,56 @@ func (in *FlashSpec) DeepCopy() *FlashSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetBoardStatus) DeepCopyInto(out *FleetBoardStatus) {
+	*out = *in
+	if in.DeviceTests != nil {
+		in, out := &in.DeviceTests, &out.DeviceTests
+		*out = new(DeviceTestResults)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetBoardStatus.
+func (in *FleetBoardStatus) DeepCopy() *FleetBoardStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetBoardStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetFlashSpec) DeepCopyInto(out *FleetFlashSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetFlashSpec.
+func (in *FleetFlashSpec) DeepCopy() *FleetFlashSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetFlashSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPLocation) DeepCopyInto(out *HTTPLocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPLocation.
+func (in *HTTPLocation) DeepCopy() *HTTPLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPLocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HardwareTarget) DeepCopyInto(out *HardwareTarget) {
 	*out = *in
@@ -628,6 +1034,11 @@ func (in *ImageBuildList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageBuildSpec) DeepCopyInto(out *ImageBuildSpec) {
 	*out = *in
+	if in.Architectures != nil {
+		in, out := &in.Architectures, &out.Architectures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.AIB != nil {
 		in, out := &in.AIB, &out.AIB
 		*out = new(AIBSpec)
@@ -641,7 +1052,12 @@ func (in *ImageBuildSpec) DeepCopyInto(out *ImageBuildSpec) {
 	if in.Flash != nil {
 		in, out := &in.Flash, &out.Flash
 		*out = new(FlashSpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -673,10 +1089,33 @@ func (in *ImageBuildStatus) DeepCopyInto(out *ImageBuildStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DeviceTests != nil {
+		in, out := &in.DeviceTests, &out.DeviceTests
+		*out = new(DeviceTestResults)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ExpiresAt != nil {
 		in, out := &in.ExpiresAt, &out.ExpiresAt
 		*out = (*in).DeepCopy()
 	}
+	if in.Architectures != nil {
+		in, out := &in.Architectures, &out.Architectures
+		*out = make([]ArchitectureBuildStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Attempts != nil {
+		in, out := &in.Attempts, &out.Attempts
+		*out = make([]BuildAttempt, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NextRetryTime != nil {
+		in, out := &in.NextRetryTime, &out.NextRetryTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageBuildStatus.
@@ -729,6 +1168,21 @@ func (in *ImageLocation) DeepCopyInto(out *ImageLocation) {
 		*out = new(RegistryLocation)
 		**out = **in
 	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3Location)
+		**out = **in
+	}
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(PVCLocation)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPLocation)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageLocation.
@@ -1054,6 +1508,63 @@ func (in *MonitoringConfig) DeepCopy() *MonitoringConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationTarget) DeepCopyInto(out *NotificationTarget) {
+	*out = *in
+	if in.Kinds != nil {
+		in, out := &in.Kinds, &out.Kinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Requesters != nil {
+		in, out := &in.Requesters, &out.Requesters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationTarget.
+func (in *NotificationTarget) DeepCopy() *NotificationTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationsConfig) DeepCopyInto(out *NotificationsConfig) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]NotificationTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationsConfig.
+func (in *NotificationsConfig) DeepCopy() *NotificationsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OSBuildsConfig) DeepCopyInto(out *OSBuildsConfig) {
 	*out = *in
@@ -1086,6 +1597,21 @@ func (in *OSBuildsConfig) DeepCopyInto(out *OSBuildsConfig) {
 		*out = new(corev1.ConfigMapKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.BuildQueue != nil {
+		in, out := &in.BuildQueue, &out.BuildQueue
+		*out = new(BuildQueueConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PriorityClasses != nil {
+		in, out := &in.PriorityClasses, &out.PriorityClasses
+		*out = make([]BuildPriorityClass, len(*in))
+		copy(*out, *in)
+	}
+	if in.Signing != nil {
+		in, out := &in.Signing, &out.Signing
+		*out = new(ArtifactSigningConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSBuildsConfig.
@@ -1200,6 +1726,11 @@ func (in *OperatorConfigSpec) DeepCopyInto(out *OperatorConfigSpec) {
 		*out = new(TracingConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(NotificationsConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigSpec.
@@ -1234,6 +1765,21 @@ func (in *OperatorConfigStatus) DeepCopy() *OperatorConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCLocation) DeepCopyInto(out *PVCLocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCLocation.
+func (in *PVCLocation) DeepCopy() *PVCLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCLocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PlatformInfo) DeepCopyInto(out *PlatformInfo) {
 	*out = *in
@@ -1264,6 +1810,53 @@ func (in *PlatformVariant) DeepCopy() *PlatformVariant {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaConfig) DeepCopyInto(out *QuotaConfig) {
+	*out = *in
+	if in.User != nil {
+		in, out := &in.User, &out.User
+		*out = new(QuotaLimits)
+		**out = **in
+	}
+	if in.Team != nil {
+		in, out := &in.Team, &out.Team
+		*out = new(QuotaLimits)
+		**out = **in
+	}
+	if in.MaxBuildTTL != nil {
+		in, out := &in.MaxBuildTTL, &out.MaxBuildTTL
+		*out = make(map[BuildAPIRole]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaConfig.
+func (in *QuotaConfig) DeepCopy() *QuotaConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaLimits) DeepCopyInto(out *QuotaLimits) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaLimits.
+func (in *QuotaLimits) DeepCopy() *QuotaLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RegistryLocation) DeepCopyInto(out *RegistryLocation) {
 	*out = *in
@@ -1308,6 +1901,113 @@ func (in *RegistryMetadata) DeepCopy() *RegistryMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	if in.RetryOn != nil {
+		in, out := &in.RetryOn, &out.RetryOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleBindingConfig) DeepCopyInto(out *RoleBindingConfig) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleBindingConfig.
+func (in *RoleBindingConfig) DeepCopy() *RoleBindingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleBindingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3Location) DeepCopyInto(out *S3Location) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Location.
+func (in *S3Location) DeepCopy() *S3Location {
+	if in == nil {
+		return nil
+	}
+	out := new(S3Location)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenancyConfig) DeepCopyInto(out *TenancyConfig) {
+	*out = *in
+	if in.Tenants != nil {
+		in, out := &in.Tenants, &out.Tenants
+		*out = make([]TenantConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenancyConfig.
+func (in *TenancyConfig) DeepCopy() *TenancyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TenancyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantConfig) DeepCopyInto(out *TenantConfig) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantConfig.
+func (in *TenantConfig) DeepCopy() *TenantConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TracingConfig) DeepCopyInto(out *TracingConfig) {
 	*out = *in
@@ -1387,6 +2087,123 @@ func (in *WorkspaceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceSnapshot) DeepCopyInto(out *WorkspaceSnapshot) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceSnapshot.
+func (in *WorkspaceSnapshot) DeepCopy() *WorkspaceSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceSnapshot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceSnapshotList) DeepCopyInto(out *WorkspaceSnapshotList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WorkspaceSnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceSnapshotList.
+func (in *WorkspaceSnapshotList) DeepCopy() *WorkspaceSnapshotList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceSnapshotList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceSnapshotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceSnapshotSource) DeepCopyInto(out *WorkspaceSnapshotSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceSnapshotSource.
+func (in *WorkspaceSnapshotSource) DeepCopy() *WorkspaceSnapshotSource {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceSnapshotSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceSnapshotSpec) DeepCopyInto(out *WorkspaceSnapshotSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceSnapshotSpec.
+func (in *WorkspaceSnapshotSpec) DeepCopy() *WorkspaceSnapshotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceSnapshotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceSnapshotStatus) DeepCopyInto(out *WorkspaceSnapshotStatus) {
+	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(WorkspaceSnapshotSource)
+		**out = **in
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceSnapshotStatus.
+func (in *WorkspaceSnapshotStatus) DeepCopy() *WorkspaceSnapshotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceSnapshotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceSpec) DeepCopyInto(out *WorkspaceSpec) {
 	*out = *in
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	caibconfig "github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/config"
 	buildapiclient "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi/client"
 )
 
@@ -116,6 +117,9 @@ func CreateClientWithReauth(ctx context.Context, serverURL string, authToken *st
 	// Configure TLS options
 	var opts []buildapiclient.Option
 	opts = append(opts, buildapiclient.WithAuthToken(tokenValue))
+	if project := caibconfig.Project(); project != "" {
+		opts = append(opts, buildapiclient.WithProject(project))
+	}
 	if insecureSkipTLS {
 		opts = append(opts, buildapiclient.WithInsecureTLS())
 	}
@@ -27,6 +27,7 @@ const (
 	phaseFlashing  = automotivev1alpha1.ImageBuildPhaseFlashing
 	phasePending   = automotivev1alpha1.ImageBuildPhasePending
 	phaseUploading = automotivev1alpha1.ImageBuildPhaseUploading
+	phaseQueued    = automotivev1alpha1.ImageBuildPhaseQueued
 	phaseRunning   = "Running"
 
 	errPrefixFlash = "flash"
@@ -69,6 +70,12 @@ type Options struct {
 	JumpstarterClient      *string
 	LeaseDuration          *string
 	LeaseName              *string
+	TestScript             *string
+	TestImage              *string
+	BootCheck              *string
+	BootMarker             *string
+	BootTimeout            *string
+	NoPowerCycle           *bool
 	FlashCmd               *string
 	ExporterSelector       *string
 
@@ -76,11 +83,21 @@ type Options struct {
 	InternalRegistryImageName *string
 	InternalRegistryTag       *string
 
+	// Disk export to s3, http or pvc storage instead of a registry
+	ExportTo     *string
+	S3Endpoint   *string
+	S3Region     *string
+	ExportSecret *string
+
 	SecureBuild       *bool
 	Reproducible      *bool
 	TaskBundleRef     *string
 	RestoreSourcesRef *string
 	TTL               *string
+	Priority          *string
+	MaxAttempts       *int
+	Notify            *bool
+	SigningKeySecret  *string
 
 	InsecureSkipTLS *bool
 
@@ -135,16 +152,20 @@ func (h *Handler) validateBootcBuildFlags() error {
 		)
 	}
 
+	if err := h.validateExportToFlags("--push-disk"); err != nil {
+		return err
+	}
+
 	if *h.opts.OutputDir != "" && !*h.opts.BuildDiskImage {
 		*h.opts.BuildDiskImage = true
 	}
-	if *h.opts.ExportOCI != "" && !*h.opts.BuildDiskImage {
+	if (*h.opts.ExportOCI != "" || h.exportTarget() != "") && !*h.opts.BuildDiskImage {
 		*h.opts.BuildDiskImage = true
 	}
 	if *h.opts.FlashAfterBuild && !*h.opts.BuildDiskImage {
 		*h.opts.BuildDiskImage = true
 	}
-	if !*h.opts.UseInternalRegistry {
+	if !*h.opts.UseInternalRegistry && h.exportTarget() == "" {
 		if err := common.ValidateOutputRequiresPush(*h.opts.OutputDir, *h.opts.ExportOCI, "--push-disk"); err != nil {
 			return err
 		}
@@ -177,6 +198,83 @@ func (h *Handler) validateReproducibleFlags() error {
 	return nil
 }
 
+// exportTarget returns the --export-to value, or "" when the command has no such flag.
+func (h *Handler) exportTarget() string {
+	if h.opts.ExportTo == nil {
+		return ""
+	}
+	return strings.TrimSpace(*h.opts.ExportTo)
+}
+
+// priority returns the requested build priority class, empty for the default class.
+func (h *Handler) priority() string {
+	if h.opts.Priority == nil {
+		return ""
+	}
+	return strings.TrimSpace(*h.opts.Priority)
+}
+
+// notify reports whether --notify opted the build in to notifications.
+func (h *Handler) notify() bool {
+	return h.opts.Notify != nil && *h.opts.Notify
+}
+
+// signingKeySecret returns the Secret holding the cosign key requested with
+// --signing-key-secret, empty to use the OperatorConfig signing key.
+func (h *Handler) signingKeySecret() string {
+	if h.opts.SigningKeySecret == nil {
+		return ""
+	}
+	return strings.TrimSpace(*h.opts.SigningKeySecret)
+}
+
+// retryPolicy returns the retry policy requested with --max-attempts, nil when retries are disabled.
+func (h *Handler) retryPolicy() *buildapitypes.RetryPolicy {
+	if h.opts.MaxAttempts == nil || *h.opts.MaxAttempts <= 0 {
+		return nil
+	}
+	return &buildapitypes.RetryPolicy{MaxAttempts: int32(*h.opts.MaxAttempts)}
+}
+
+// validateExportToFlags rejects combining --export-to with registry disk exports.
+// registryFlag is the disk push flag of the current command.
+func (h *Handler) validateExportToFlags(registryFlag string) error {
+	target := h.exportTarget()
+	if target == "" {
+		return nil
+	}
+	if *h.opts.ExportOCI != "" {
+		return fmt.Errorf("--export-to cannot be used with %s", registryFlag)
+	}
+	if *h.opts.UseInternalRegistry {
+		return fmt.Errorf("--export-to cannot be used with --internal-registry")
+	}
+	if *h.opts.FlashAfterBuild {
+		return fmt.Errorf("--flash requires a registry disk export and cannot be used with --export-to")
+	}
+	return nil
+}
+
+// applyExportLocation sets the s3, http or pvc disk export on the build request.
+func (h *Handler) applyExportLocation(req *buildapitypes.BuildRequest) error {
+	target := h.exportTarget()
+	if target == "" {
+		return nil
+	}
+	value := func(p *string) string {
+		if p == nil {
+			return ""
+		}
+		return strings.TrimSpace(*p)
+	}
+	loc, err := common.ParseExportTarget(target, value(h.opts.S3Endpoint), value(h.opts.S3Region), value(h.opts.ExportSecret))
+	if err != nil {
+		return err
+	}
+	req.ExportLocation = loc
+	return nil
+}
+
 // applyRegistryCredentialsToRequest sets registry credentials on the build request.
 // When --internal-registry is combined with --push, both are configured so the
 // container is pushed externally while the disk image uses the internal registry.
@@ -302,6 +400,22 @@ func ApplyTargetDefaults(cmd *cobra.Command, config *buildapitypes.OperatorConfi
 	}
 }
 
+// SplitArchitectures turns a comma-separated --arch value into a multi-arch request.
+// The server builds each architecture separately and publishes an image index.
+func SplitArchitectures(req *buildapitypes.BuildRequest) {
+	if !strings.Contains(string(req.Architecture), ",") {
+		return
+	}
+	var archs []buildapitypes.Architecture
+	for _, arch := range strings.Split(string(req.Architecture), ",") {
+		if arch = strings.TrimSpace(arch); arch != "" {
+			archs = append(archs, buildapitypes.Architecture(arch))
+		}
+	}
+	req.Architecture = ""
+	req.Architectures = archs
+}
+
 // displayBuildResults shows push locations after build completion.
 // It queries the server for actual build status so that messages are only
 // shown for steps that actually succeeded.
@@ -359,9 +473,23 @@ func (h *Handler) displayBuildResults(ctx context.Context, api *buildapiclient.C
 	if st.ContainerImage != "" && *h.opts.ContainerPush != "" {
 		fmt.Printf("%s %s\n", labelColor("Container image pushed to:"), valueColor(*h.opts.ContainerPush))
 	}
+	for _, arch := range st.Architectures {
+		if arch.ContainerImage != "" {
+			fmt.Printf("%s %s\n", labelColor("  "+arch.Architecture+":"), valueColor(arch.ContainerImage))
+		}
+	}
 	if st.DiskImage != "" && *h.opts.ExportOCI != "" {
 		fmt.Printf("%s %s\n", labelColor("Disk image pushed to:"), valueColor(*h.opts.ExportOCI))
 	}
+	if st.DiskLocation != nil {
+		fmt.Printf("%s %s\n", labelColor("Disk image exported to:"), valueColor(st.DiskLocation.URL))
+		if *h.opts.OutputDir != "" {
+			if err := common.DownloadStorageArtifact(ctx, api, buildName, st.DiskLocation, *h.opts.OutputDir); err != nil {
+				h.handleError(fmt.Errorf("failed to download disk image: %w", err))
+			}
+		}
+		return
+	}
 	if *h.opts.OutputDir != "" && st.DiskImage != "" {
 		_, registryUsername, registryPassword := registryauth.ExtractRegistryCredentials(*h.opts.ContainerPush, *h.opts.ExportOCI)
 		if err := common.PullOCIArtifact(
@@ -385,6 +513,12 @@ func (h *Handler) validateFlashLeaseFlags(cmd *cobra.Command) error {
 			"caib image build --flash --lease-duration <duration>",
 		)
 	}
+	if testScript := h.deviceTestFlags().ScriptFile; !*h.opts.FlashAfterBuild && testScript != "" {
+		return common.NewActionableError(
+			fmt.Errorf("--test-script requires --flash"),
+			fmt.Sprintf("caib image build --flash --test-script %s", testScript),
+		)
+	}
 	return nil
 }
 
@@ -413,9 +547,32 @@ func (h *Handler) applyFlashOptions(req *buildapitypes.BuildRequest, pushRequire
 	}
 	req.FlashCmd = *h.opts.FlashCmd
 	req.FlashExporterSelector = *h.opts.ExporterSelector
+	test, err := common.DeviceTestRequest(h.deviceTestFlags())
+	if err != nil {
+		return err
+	}
+	req.FlashTest = test
 	return nil
 }
 
+// deviceTestFlags collects the on-device test flags.
+func (h *Handler) deviceTestFlags() common.DeviceTestFlags {
+	value := func(p *string) string {
+		if p == nil {
+			return ""
+		}
+		return strings.TrimSpace(*p)
+	}
+	return common.DeviceTestFlags{
+		ScriptFile:     value(h.opts.TestScript),
+		Image:          value(h.opts.TestImage),
+		BootCheck:      value(h.opts.BootCheck),
+		BootMarker:     value(h.opts.BootMarker),
+		BootTimeout:    value(h.opts.BootTimeout),
+		SkipPowerCycle: h.opts.NoPowerCycle != nil && *h.opts.NoPowerCycle,
+	}
+}
+
 func (h *Handler) displayBuildLogsCommand(buildName string) {
 	if clilog.IsQuiet() {
 		return
@@ -524,12 +681,20 @@ func (h *Handler) RunBuild(cmd *cobra.Command, args []string) {
 		TaskBundleRef:          *h.opts.TaskBundleRef,
 		RestoreSourcesRef:      *h.opts.RestoreSourcesRef,
 		TTL:                    *h.opts.TTL,
+		Priority:               h.priority(),
+		RetryPolicy:            h.retryPolicy(),
+		SigningKeySecretRef:    h.signingKeySecret(),
+		Notify:                 h.notify(),
 	}
 
 	if err := h.applyRegistryCredentialsToRequest(&req); err != nil {
 		h.handleError(err)
 		return
 	}
+	if err := h.applyExportLocation(&req); err != nil {
+		h.handleError(err)
+		return
+	}
 
 	validateFlash := *h.opts.FlashAfterBuild && *h.opts.ExporterSelector == ""
 	operatorConfig, cfgErr := h.fetchTargetDefaults(ctx, api, *h.opts.Target, validateFlash)
@@ -538,6 +703,7 @@ func (h *Handler) RunBuild(cmd *cobra.Command, args []string) {
 		return
 	}
 	ApplyTargetDefaults(cmd, operatorConfig, &req)
+	SplitArchitectures(&req)
 
 	if err := h.applyFlashOptions(&req, "--push-disk"); err != nil {
 		h.handleError(err)
@@ -588,8 +754,13 @@ func (h *Handler) RunDisk(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	if err := h.validateExportToFlags("--push"); err != nil {
+		h.handleError(err)
+		return
+	}
+
 	// Default to internal registry when no push destination is specified
-	if *h.opts.ExportOCI == "" && !*h.opts.UseInternalRegistry {
+	if *h.opts.ExportOCI == "" && h.exportTarget() == "" && !*h.opts.UseInternalRegistry {
 		*h.opts.UseInternalRegistry = true
 	}
 
@@ -642,12 +813,20 @@ func (h *Handler) RunDisk(cmd *cobra.Command, args []string) {
 		TaskBundleRef:          *h.opts.TaskBundleRef,
 		RestoreSourcesRef:      *h.opts.RestoreSourcesRef,
 		TTL:                    *h.opts.TTL,
+		Priority:               h.priority(),
+		RetryPolicy:            h.retryPolicy(),
+		SigningKeySecretRef:    h.signingKeySecret(),
+		Notify:                 h.notify(),
 	}
 
 	if err := h.applyRegistryCredentialsToRequest(&req); err != nil {
 		h.handleError(err)
 		return
 	}
+	if err := h.applyExportLocation(&req); err != nil {
+		h.handleError(err)
+		return
+	}
 
 	validateFlash := *h.opts.FlashAfterBuild && *h.opts.ExporterSelector == ""
 	operatorConfig, cfgErr := h.fetchTargetDefaults(ctx, api, *h.opts.Target, validateFlash)
@@ -702,6 +881,11 @@ func (h *Handler) RunBuildDev(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	if err := h.validateExportToFlags("--push"); err != nil {
+		h.handleError(err)
+		return
+	}
+
 	if *h.opts.UseInternalRegistry {
 		if *h.opts.ExportOCI != "" {
 			h.handleError(common.NewActionableError(
@@ -710,9 +894,11 @@ func (h *Handler) RunBuildDev(cmd *cobra.Command, args []string) {
 			))
 			return
 		}
-	} else if err := common.ValidateOutputRequiresPush(*h.opts.OutputDir, *h.opts.ExportOCI, "--push"); err != nil {
-		h.handleError(err)
-		return
+	} else if h.exportTarget() == "" {
+		if err := common.ValidateOutputRequiresPush(*h.opts.OutputDir, *h.opts.ExportOCI, "--push"); err != nil {
+			h.handleError(err)
+			return
+		}
 	}
 
 	if *h.opts.BuildName == "" {
@@ -789,12 +975,20 @@ func (h *Handler) RunBuildDev(cmd *cobra.Command, args []string) {
 		TaskBundleRef:          *h.opts.TaskBundleRef,
 		RestoreSourcesRef:      *h.opts.RestoreSourcesRef,
 		TTL:                    *h.opts.TTL,
+		Priority:               h.priority(),
+		RetryPolicy:            h.retryPolicy(),
+		SigningKeySecretRef:    h.signingKeySecret(),
+		Notify:                 h.notify(),
 	}
 
 	if err := h.applyRegistryCredentialsToRequest(&req); err != nil {
 		h.handleError(err)
 		return
 	}
+	if err := h.applyExportLocation(&req); err != nil {
+		h.handleError(err)
+		return
+	}
 
 	validateFlash := *h.opts.FlashAfterBuild && *h.opts.ExporterSelector == ""
 	operatorConfig, cfgErr := h.fetchTargetDefaults(ctx, api, *h.opts.Target, validateFlash)
@@ -847,7 +1041,7 @@ func (h *Handler) handleFileUploads(
 	localRefs []map[string]string,
 ) error {
 	for _, ref := range localRefs {
-		if _, err := os.Stat(ref["source_path"]); err != nil {
+		if _, err := os.Stat(common.LocalInputPath(ref)); err != nil {
 			return fmt.Errorf("referenced file %s does not exist: %w", ref["source_path"], err)
 		}
 	}
@@ -879,7 +1073,7 @@ func (h *Handler) handleFileUploads(
 	uploads := make([]buildapiclient.Upload, 0, len(localRefs))
 	for _, ref := range localRefs {
 		uploads = append(uploads, buildapiclient.Upload{
-			SourcePath: ref["source_path"],
+			SourcePath: common.LocalInputPath(ref),
 			DestPath:   ref["source_path"],
 		})
 	}
@@ -920,13 +1114,24 @@ func (h *Handler) handleFileUploads(
 		break
 	}
 	clilog.Infoln("Local files uploaded. Build will proceed.")
+
+	// Keep the uploaded files so the build can be rebuilt with `caib image rebuild`.
+	if err := common.CacheBuildInputs(buildName, localRefs); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache local files for rebuilds: %v\n", err)
+	}
 	return nil
 }
 
 // RunDelete handles `caib image delete`.
 func (h *Handler) RunDelete(_ *cobra.Command, args []string) {
 	h.runBuildAction(args[0], "deleted", func(ctx context.Context, api *buildapiclient.Client, name string) error {
-		return api.DeleteBuild(ctx, name)
+		if err := api.DeleteBuild(ctx, name); err != nil {
+			return err
+		}
+		if err := common.RemoveCachedBuildInputs(name); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to remove cached local files: %v\n", err)
+		}
+		return nil
 	})
 }
 
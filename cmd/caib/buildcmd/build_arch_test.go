@@ -0,0 +1,41 @@
+package buildcmd
+
+import (
+	"reflect"
+	"testing"
+
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+)
+
+func TestSplitArchitectures(t *testing.T) {
+	tests := []struct {
+		name      string
+		arch      string
+		wantArch  buildapitypes.Architecture
+		wantArchs []buildapitypes.Architecture
+	}{
+		{name: "single architecture untouched", arch: "arm64", wantArch: "arm64"},
+		{
+			name:      "comma-separated list becomes multi-arch",
+			arch:      "amd64, arm64",
+			wantArchs: []buildapitypes.Architecture{"amd64", "arm64"},
+		},
+		{
+			name:      "empty entries are dropped",
+			arch:      "amd64,,arm64,",
+			wantArchs: []buildapitypes.Architecture{"amd64", "arm64"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := buildapitypes.BuildRequest{Architecture: buildapitypes.Architecture(tt.arch)}
+			SplitArchitectures(&req)
+			if req.Architecture != tt.wantArch {
+				t.Errorf("Architecture = %q, want %q", req.Architecture, tt.wantArch)
+			}
+			if !reflect.DeepEqual(req.Architectures, tt.wantArchs) {
+				t.Errorf("Architectures = %v, want %v", req.Architectures, tt.wantArchs)
+			}
+		})
+	}
+}
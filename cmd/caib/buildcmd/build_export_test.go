@@ -0,0 +1,67 @@
+package buildcmd
+
+import (
+	"strings"
+	"testing"
+
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+)
+
+func TestApplyExportLocation(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "minio")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "minio123")
+
+	opts := newTestDiskOpts()
+	exportTo := "s3://images/rhivos/disk.qcow2"
+	endpoint := "http://minio.minio.svc:9000"
+	opts.ExportTo = &exportTo
+	opts.S3Endpoint = &endpoint
+	h := NewHandler(opts)
+
+	req := &buildapitypes.BuildRequest{}
+	if err := h.applyExportLocation(req); err != nil {
+		t.Fatalf("applyExportLocation() error = %v", err)
+	}
+	loc := req.ExportLocation
+	if loc == nil || loc.Type != "s3" || loc.URL != exportTo || loc.Endpoint != endpoint {
+		t.Fatalf("unexpected export location %+v", loc)
+	}
+	if loc.Credentials == nil || loc.Credentials.SecretAccessKey != "minio123" {
+		t.Fatalf("expected credentials from the environment, got %+v", loc.Credentials)
+	}
+}
+
+func TestApplyExportLocation_NotConfigured(t *testing.T) {
+	h := NewHandler(newTestDiskOpts())
+	req := &buildapitypes.BuildRequest{}
+	if err := h.applyExportLocation(req); err != nil {
+		t.Fatalf("applyExportLocation() error = %v", err)
+	}
+	if req.ExportLocation != nil {
+		t.Fatalf("expected no export location, got %+v", req.ExportLocation)
+	}
+}
+
+func TestValidateExportToFlags(t *testing.T) {
+	exportTo := "pvc://images/disk.raw"
+
+	opts := newTestDiskOpts()
+	opts.ExportTo = &exportTo
+	*opts.ExportOCI = "quay.io/org/disk:v1"
+	if err := NewHandler(opts).validateExportToFlags("--push-disk"); err == nil || !strings.Contains(err.Error(), "--push-disk") {
+		t.Errorf("expected --export-to to conflict with --push-disk, got %v", err)
+	}
+
+	opts = newTestDiskOpts()
+	opts.ExportTo = &exportTo
+	*opts.UseInternalRegistry = true
+	if err := NewHandler(opts).validateExportToFlags("--push"); err == nil {
+		t.Error("expected --export-to to conflict with --internal-registry")
+	}
+
+	opts = newTestDiskOpts()
+	opts.ExportTo = &exportTo
+	if err := NewHandler(opts).validateExportToFlags("--push"); err != nil {
+		t.Errorf("validateExportToFlags() error = %v", err)
+	}
+}
@@ -26,7 +26,7 @@ func (h *Handler) waitForBuildCompletion(ctx context.Context, api *buildapiclien
 	clilog.Infoln("Waiting for build to complete...")
 	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(*h.opts.Timeout)*time.Minute)
 	defer cancel()
-	ticker := time.NewTicker(5 * time.Second)
+	ticker := common.NewStatusTicker(timeoutCtx, 5*time.Second, common.BuildWatch(api, name))
 	defer ticker.Stop()
 
 	userFollowRequested := *h.opts.FollowLogs
@@ -167,7 +167,7 @@ func (h *Handler) waitForBuildCompletion(ctx context.Context, api *buildapiclien
 				continue
 			}
 
-			if st.Phase == phasePending {
+			if st.Phase == phasePending || st.Phase == phaseQueued {
 				streamState.Reset()
 				if userFollowRequested && !pendingWarningShown {
 					clilog.Infoln("Waiting for build to start before streaming logs...")
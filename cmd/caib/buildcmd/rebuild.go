@@ -0,0 +1,165 @@
+package buildcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/clilog"
+	common "github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/common"
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/registryauth"
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+	"github.com/spf13/cobra"
+)
+
+// RunRebuild handles `caib image rebuild`.
+func (h *Handler) RunRebuild(cmd *cobra.Command, args []string) {
+	h.applyWaitFollowDefaults(cmd, true, false)
+
+	ctx := context.Background()
+	parentName := args[0]
+
+	if strings.TrimSpace(*h.opts.ServerURL) == "" {
+		h.handleError(common.ServerURLRequiredError(fmt.Sprintf("caib image rebuild --server <server-url> %s", parentName)))
+		return
+	}
+
+	req, err := h.rebuildRequest(cmd)
+	if err != nil {
+		h.handleError(err)
+		return
+	}
+
+	api, err := common.CreateBuildAPIClient(*h.opts.ServerURL, h.opts.AuthToken, *h.opts.InsecureSkipTLS)
+	if err != nil {
+		h.handleError(err)
+		return
+	}
+
+	parent, err := api.GetBuild(ctx, parentName)
+	if err != nil {
+		h.handleError(err)
+		return
+	}
+	if err := h.applyRebuildRegistryCredentials(parent.Parameters, &req); err != nil {
+		h.handleError(err)
+		return
+	}
+
+	tpl, err := api.GetBuildTemplate(ctx, parentName)
+	if err != nil {
+		h.handleError(err)
+		return
+	}
+	localRefs, err := rebuildInputs(parentName, tpl.Manifest)
+	if err != nil {
+		h.handleError(err)
+		return
+	}
+	req.HasLocalFiles = len(localRefs) > 0
+
+	resp, err := api.RebuildBuild(ctx, parentName, req)
+	if err != nil {
+		h.handleError(err)
+		return
+	}
+	clilog.Infof("Rebuild %s of %s accepted: %s - %s\n", resp.Name, parentName, resp.Phase, resp.Message)
+	h.displayBuildLogsCommand(resp.Name)
+
+	if len(localRefs) > 0 {
+		if err := h.handleFileUploads(ctx, api, resp.Name, localRefs); err != nil {
+			h.handleError(err)
+			return
+		}
+	}
+
+	if *h.opts.WaitForBuild || *h.opts.FollowLogs {
+		if err := h.waitForBuildCompletion(ctx, api, resp.Name); err != nil {
+			return
+		}
+	}
+
+	h.displayBuildResults(ctx, api, resp.Name)
+}
+
+// rebuildRequest collects the overrides given on the command line. Only flags
+// that were set override the parent build.
+func (h *Handler) rebuildRequest(cmd *cobra.Command) (buildapitypes.RebuildRequest, error) {
+	var req buildapitypes.RebuildRequest
+	flags := cmd.Flags()
+
+	if flags.Changed("name") {
+		req.Name = *h.opts.BuildName
+	}
+	defs, err := h.resolveCustomDefs()
+	if err != nil {
+		return req, err
+	}
+	req.CustomDefs = defs
+	if flags.Changed("target") {
+		req.Target = buildapitypes.Target(*h.opts.Target)
+	}
+	if flags.Changed("arch") {
+		if strings.Contains(*h.opts.Architecture, ",") {
+			return req, fmt.Errorf("--arch accepts a single architecture for rebuilds")
+		}
+		req.Architecture = buildapitypes.Architecture(*h.opts.Architecture)
+	}
+	if flags.Changed("aib-image") {
+		req.AutomotiveImageBuilder = *h.opts.AutomotiveImageBuilder
+	}
+	if flags.Changed("ttl") {
+		req.TTL = *h.opts.TTL
+	}
+	return req, nil
+}
+
+// applyRebuildRegistryCredentials resolves credentials for the parent build's push
+// destinations. Registry credentials are not kept after a build completes, so
+// they are sent again with every rebuild.
+func (h *Handler) applyRebuildRegistryCredentials(
+	params *buildapitypes.BuildParameters, req *buildapitypes.RebuildRequest,
+) error {
+	if params == nil || (params.ContainerPush == "" && params.ExportOCI == "") {
+		return nil
+	}
+	if params.UseServiceAccountAuth && !h.hasRegistryCredentials() {
+		return nil
+	}
+
+	registryURL, username, password := registryauth.ExtractRegistryCredentials(params.ContainerPush, params.ExportOCI)
+	creds, err := registryauth.ResolveRegistryCredentials(registryURL, username, password, *h.opts.RegistryAuthFile)
+	if err != nil {
+		return err
+	}
+	req.RegistryCredentials = creds
+	return nil
+}
+
+// rebuildInputs returns the local files to upload for a rebuild: the files
+// cached when the parent build was submitted, or else the files the manifest
+// references relative to the working directory.
+func rebuildInputs(parentName, manifest string) ([]map[string]string, error) {
+	cached, err := common.CachedBuildInputs(parentName)
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil {
+		return cached, nil
+	}
+
+	refs, err := common.FindLocalFileReferences(manifest, ".")
+	if err != nil {
+		return nil, fmt.Errorf("manifest file reference error: %w", err)
+	}
+	for _, ref := range refs {
+		if _, err := os.Stat(ref["source_path"]); err != nil {
+			return nil, common.NewActionableError(
+				fmt.Errorf("local file %s of build %s is not cached and was not found", ref["source_path"], parentName),
+				"Run the rebuild from the directory the original build was submitted from",
+			)
+		}
+	}
+	return refs, nil
+}
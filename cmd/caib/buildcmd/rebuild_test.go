@@ -0,0 +1,136 @@
+package buildcmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	common "github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/common"
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+	"github.com/spf13/cobra"
+)
+
+func newTestRebuildCmd(opts Options) *cobra.Command {
+	cmd := &cobra.Command{Use: "rebuild"}
+	cmd.Flags().StringVarP(opts.BuildName, "name", "n", "", "")
+	cmd.Flags().StringVarP(opts.Target, "target", "t", "", "")
+	cmd.Flags().StringVarP(opts.Architecture, "arch", "a", "amd64", "")
+	cmd.Flags().StringVar(opts.AutomotiveImageBuilder, "aib-image", "", "")
+	cmd.Flags().StringArrayVarP(opts.CustomDefs, "define", "D", []string{}, "")
+	cmd.Flags().StringVar(opts.TTL, "ttl", "", "")
+	return cmd
+}
+
+func TestRebuildRequest_OnlySetFlagsOverride(t *testing.T) {
+	opts := newTestDiskOpts()
+	cmd := newTestRebuildCmd(opts)
+	h := NewHandler(opts)
+
+	req, err := h.rebuildRequest(cmd)
+	if err != nil {
+		t.Fatalf("rebuildRequest() error = %v", err)
+	}
+	if !reflect.DeepEqual(req, buildapitypes.RebuildRequest{}) {
+		t.Fatalf("expected no overrides without flags, got %+v", req)
+	}
+
+	for flag, value := range map[string]string{
+		"target":    "ridesx4",
+		"arch":      "arm64",
+		"aib-image": "quay.io/org/aib:next",
+		"ttl":       "1h",
+		"define":    "debug=true",
+	} {
+		if err := cmd.Flags().Set(flag, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req, err = h.rebuildRequest(cmd)
+	if err != nil {
+		t.Fatalf("rebuildRequest() error = %v", err)
+	}
+	want := buildapitypes.RebuildRequest{
+		CustomDefs:             []string{"debug=true"},
+		Target:                 "ridesx4",
+		Architecture:           "arm64",
+		AutomotiveImageBuilder: "quay.io/org/aib:next",
+		TTL:                    "1h",
+	}
+	if !reflect.DeepEqual(req, want) {
+		t.Fatalf("rebuildRequest() = %+v, want %+v", req, want)
+	}
+}
+
+func TestRebuildRequest_RejectsMultipleArchitectures(t *testing.T) {
+	opts := newTestDiskOpts()
+	cmd := newTestRebuildCmd(opts)
+	if err := cmd.Flags().Set("arch", "amd64,arm64"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewHandler(opts).rebuildRequest(cmd); err == nil {
+		t.Fatal("expected error for a comma-separated --arch")
+	}
+}
+
+func TestApplyRebuildRegistryCredentials(t *testing.T) {
+	t.Setenv("REGISTRY_URL", "")
+	t.Setenv("REGISTRY_USERNAME", "myuser")
+	t.Setenv("REGISTRY_PASSWORD", "mypass")
+	h := NewHandler(newTestDiskOpts())
+
+	req := &buildapitypes.RebuildRequest{}
+	if err := h.applyRebuildRegistryCredentials(&buildapitypes.BuildParameters{
+		ContainerPush: "quay.io/org/app:bootc",
+	}, req); err != nil {
+		t.Fatalf("applyRebuildRegistryCredentials() error = %v", err)
+	}
+	if req.RegistryCredentials == nil || req.RegistryCredentials.RegistryURL != "quay.io" {
+		t.Fatalf("expected quay.io credentials, got %+v", req.RegistryCredentials)
+	}
+
+	req = &buildapitypes.RebuildRequest{}
+	if err := h.applyRebuildRegistryCredentials(&buildapitypes.BuildParameters{}, req); err != nil {
+		t.Fatalf("applyRebuildRegistryCredentials() error = %v", err)
+	}
+	if req.RegistryCredentials != nil {
+		t.Fatalf("expected no credentials for a build without push destinations, got %+v", req.RegistryCredentials)
+	}
+}
+
+func TestRebuildInputs(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	manifest := "content:\n  add_files:\n    - path: /etc/app.conf\n      source_path: app.conf\n"
+	if _, err := rebuildInputs("parent-ab12c", manifest); err == nil || !strings.Contains(err.Error(), "app.conf") {
+		t.Fatalf("expected error for a missing uncached file, got %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "app.conf"), []byte("key=value\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	refs, err := rebuildInputs("parent-ab12c", manifest)
+	if err != nil {
+		t.Fatalf("rebuildInputs() error = %v", err)
+	}
+	if len(refs) != 1 || common.LocalInputPath(refs[0]) != "app.conf" {
+		t.Fatalf("expected app.conf from the working directory, got %v", refs)
+	}
+
+	if err := common.CacheBuildInputs("parent-ab12c", refs); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "app.conf")); err != nil {
+		t.Fatal(err)
+	}
+	refs, err = rebuildInputs("parent-ab12c", manifest)
+	if err != nil {
+		t.Fatalf("rebuildInputs() error = %v", err)
+	}
+	if len(refs) != 1 || refs[0]["source_path"] != "app.conf" || common.LocalInputPath(refs[0]) == "app.conf" {
+		t.Fatalf("expected app.conf from the cache, got %v", refs)
+	}
+}
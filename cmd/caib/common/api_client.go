@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/auth"
+	caibconfig "github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/config"
 	buildapiclient "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi/client"
 	"k8s.io/client-go/tools/clientcmd"
 )
@@ -62,6 +63,9 @@ func CreateBuildAPIClient(serverURL string, authToken *string, insecureSkipTLS b
 	if tokenValue != "" {
 		opts = append(opts, buildapiclient.WithAuthToken(tokenValue))
 	}
+	if project := caibconfig.Project(); project != "" {
+		opts = append(opts, buildapiclient.WithProject(project))
+	}
 
 	if insecureSkipTLS {
 		opts = append(opts, buildapiclient.WithInsecureTLS())
@@ -0,0 +1,54 @@
+package caibcommon
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+)
+
+// DeviceTestFlags holds the values of the on-device test flags.
+type DeviceTestFlags struct {
+	ScriptFile     string
+	Image          string
+	BootCheck      string
+	BootMarker     string
+	BootTimeout    string
+	SkipPowerCycle bool
+}
+
+// DeviceTestRequest builds the device test request from the test flags.
+// It returns nil when no test script was given.
+func DeviceTestRequest(flags DeviceTestFlags) (*buildapitypes.DeviceTestRequest, error) {
+	scriptFile := strings.TrimSpace(flags.ScriptFile)
+	if scriptFile == "" {
+		if flags.Image != "" || flags.BootCheck != "" || flags.BootMarker != "" || flags.BootTimeout != "" || flags.SkipPowerCycle {
+			return nil, fmt.Errorf("--test-image, --boot-check, --boot-marker, --boot-timeout and --no-power-cycle require --test-script")
+		}
+		return nil, nil
+	}
+	script, err := os.ReadFile(scriptFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read test script: %w", err)
+	}
+	return &buildapitypes.DeviceTestRequest{
+		Script:         string(script),
+		Image:          flags.Image,
+		BootCheck:      flags.BootCheck,
+		BootMarker:     flags.BootMarker,
+		BootTimeout:    flags.BootTimeout,
+		SkipPowerCycle: flags.SkipPowerCycle,
+	}, nil
+}
+
+// FormatDeviceTests describes device test results in one line.
+func FormatDeviceTests(results *buildapitypes.DeviceTestResults) string {
+	if !results.Booted {
+		return "device did not boot"
+	}
+	if results.Total == 0 {
+		return "no test cases reported"
+	}
+	return fmt.Sprintf("%d passed, %d failed, %d skipped", results.Passed, results.Failed, results.Skipped)
+}
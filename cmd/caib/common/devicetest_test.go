@@ -0,0 +1,69 @@
+package caibcommon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+)
+
+func TestDeviceTestRequest(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "smoke.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nj ssh -- systemctl is-system-running\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := DeviceTestRequest(DeviceTestFlags{ScriptFile: script, BootCheck: "ssh", BootTimeout: "5m"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(req.Script, "systemctl is-system-running") {
+		t.Errorf("expected script content, got %q", req.Script)
+	}
+	if req.BootCheck != "ssh" || req.BootTimeout != "5m" {
+		t.Errorf("expected boot flags to be passed through, got %+v", req)
+	}
+}
+
+func TestDeviceTestRequest_NoScript(t *testing.T) {
+	req, err := DeviceTestRequest(DeviceTestFlags{})
+	if err != nil || req != nil {
+		t.Fatalf("expected no request without flags, got %+v, %v", req, err)
+	}
+
+	if _, err := DeviceTestRequest(DeviceTestFlags{BootCheck: "ssh"}); err == nil {
+		t.Fatal("expected error for test flags without --test-script")
+	}
+}
+
+func TestDeviceTestRequest_MissingScript(t *testing.T) {
+	_, err := DeviceTestRequest(DeviceTestFlags{ScriptFile: filepath.Join(t.TempDir(), "missing.sh")})
+	if err == nil || !strings.Contains(err.Error(), "failed to read test script") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+}
+
+func TestFormatDeviceTests(t *testing.T) {
+	tests := []struct {
+		name    string
+		results buildapitypes.DeviceTestResults
+		want    string
+	}{
+		{name: "not booted", results: buildapitypes.DeviceTestResults{}, want: "device did not boot"},
+		{name: "no cases", results: buildapitypes.DeviceTestResults{Booted: true}, want: "no test cases reported"},
+		{
+			name:    "results",
+			results: buildapitypes.DeviceTestResults{Booted: true, Total: 4, Passed: 2, Failed: 1, Skipped: 1},
+			want:    "2 passed, 1 failed, 1 skipped",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatDeviceTests(&tt.results); got != tt.want {
+				t.Errorf("FormatDeviceTests() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package caibcommon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/config"
+)
+
+const inputCacheIndexFile = "index.json"
+
+// cachedInput maps a manifest source_path to its cached copy.
+type cachedInput struct {
+	SourcePath string `json:"sourcePath"`
+	File       string `json:"file"`
+}
+
+// inputCacheDir returns the directory holding the cached local inputs of a build.
+func inputCacheDir(buildName string) (string, error) {
+	if buildName == "" || buildName != filepath.Base(buildName) {
+		return "", fmt.Errorf("invalid build name %q", buildName)
+	}
+	dir, err := config.CacheDirPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "inputs", buildName), nil
+}
+
+// LocalInputPath returns the local file to upload for a manifest file
+// reference: its cached copy when set, otherwise the source_path itself.
+func LocalInputPath(ref map[string]string) string {
+	if p := ref["local_path"]; p != "" {
+		return p
+	}
+	return ref["source_path"]
+}
+
+// CacheBuildInputs copies the local files uploaded for a build into the caib
+// cache so that `caib image rebuild` can upload them again.
+func CacheBuildInputs(buildName string, localRefs []map[string]string) error {
+	dir, err := inputCacheDir(buildName)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	index := make([]cachedInput, 0, len(localRefs))
+	for i, ref := range localRefs {
+		file := strconv.Itoa(i)
+		if err := copyFile(LocalInputPath(ref), filepath.Join(dir, file)); err != nil {
+			_ = os.RemoveAll(dir)
+			return fmt.Errorf("failed to cache %s: %w", ref["source_path"], err)
+		}
+		index = append(index, cachedInput{SourcePath: ref["source_path"], File: file})
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, inputCacheIndexFile), data, 0600)
+}
+
+// CachedBuildInputs returns the local file references cached for a build, with
+// local_path pointing at the cached copies. It returns nil if the build's
+// inputs are not cached.
+func CachedBuildInputs(buildName string) ([]map[string]string, error) {
+	dir, err := inputCacheDir(buildName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, inputCacheIndexFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var index []cachedInput
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("invalid input cache for build %s: %w", buildName, err)
+	}
+
+	refs := make([]map[string]string, 0, len(index))
+	for _, entry := range index {
+		refs = append(refs, map[string]string{
+			"source_path": entry.SourcePath,
+			"local_path":  filepath.Join(dir, entry.File),
+		})
+	}
+	return refs, nil
+}
+
+// RemoveCachedBuildInputs deletes the cached local inputs of a build.
+func RemoveCachedBuildInputs(buildName string) error {
+	dir, err := inputCacheDir(buildName)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
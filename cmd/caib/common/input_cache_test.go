@@ -0,0 +1,63 @@
+package caibcommon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildInputCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	src := filepath.Join(t.TempDir(), "app.conf")
+	if err := os.WriteFile(src, []byte("key=value\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if refs, err := CachedBuildInputs("my-build-ab12c"); err != nil || refs != nil {
+		t.Fatalf("CachedBuildInputs() before caching = %v, %v; want nil, nil", refs, err)
+	}
+
+	if err := CacheBuildInputs("my-build-ab12c", []map[string]string{{"source_path": src}}); err != nil {
+		t.Fatalf("CacheBuildInputs() error = %v", err)
+	}
+	if err := os.Remove(src); err != nil {
+		t.Fatal(err)
+	}
+
+	refs, err := CachedBuildInputs("my-build-ab12c")
+	if err != nil {
+		t.Fatalf("CachedBuildInputs() error = %v", err)
+	}
+	if len(refs) != 1 || refs[0]["source_path"] != src {
+		t.Fatalf("CachedBuildInputs() = %v, want one entry for %s", refs, src)
+	}
+	data, err := os.ReadFile(LocalInputPath(refs[0]))
+	if err != nil || string(data) != "key=value\n" {
+		t.Fatalf("cached copy = %q, %v", data, err)
+	}
+
+	// A rebuild caches its inputs again from the parent's cached copies.
+	if err := CacheBuildInputs("my-build-de34f", refs); err != nil {
+		t.Fatalf("CacheBuildInputs() from cache error = %v", err)
+	}
+
+	if err := RemoveCachedBuildInputs("my-build-ab12c"); err != nil {
+		t.Fatalf("RemoveCachedBuildInputs() error = %v", err)
+	}
+	if refs, _ := CachedBuildInputs("my-build-ab12c"); refs != nil {
+		t.Fatalf("expected no cached inputs after removal, got %v", refs)
+	}
+	if refs, _ := CachedBuildInputs("my-build-de34f"); len(refs) != 1 {
+		t.Fatalf("expected rebuild inputs to stay cached, got %v", refs)
+	}
+}
+
+func TestBuildInputCache_RejectsInvalidNames(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	for _, name := range []string{"", "../escape", "a/b"} {
+		if _, err := CachedBuildInputs(name); err == nil {
+			t.Errorf("CachedBuildInputs(%q) expected error", name)
+		}
+	}
+}
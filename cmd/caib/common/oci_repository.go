@@ -0,0 +1,105 @@
+package caibcommon
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/containers/image/v5/types"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/registryauth"
+)
+
+// NewOCIRepository returns a registry client for repoName that authenticates
+// with the credentials of sysCtx, or else with the first auth file found.
+func NewOCIRepository(repoName string, sysCtx *types.SystemContext) (*remote.Repository, error) {
+	repo, err := remote.NewRepository(repoName)
+	if err != nil {
+		return nil, fmt.Errorf("parse repository: %w", err)
+	}
+
+	authClient := &auth.Client{}
+	if sysCtx.DockerAuthConfig != nil && sysCtx.DockerAuthConfig.Username != "" {
+		authClient.Credential = auth.StaticCredential(repo.Reference.Host(), auth.Credential{
+			Username: sysCtx.DockerAuthConfig.Username,
+			Password: sysCtx.DockerAuthConfig.Password,
+		})
+	} else {
+		authFilePath := sysCtx.AuthFilePath
+		if authFilePath == "" {
+			for _, candidate := range registryauth.FileCandidates() {
+				if _, err := os.Stat(candidate); err == nil {
+					authFilePath = candidate
+					break
+				}
+			}
+		}
+		if authFilePath != "" {
+			store, err := credentials.NewFileStore(authFilePath)
+			if err != nil {
+				return nil, fmt.Errorf("load auth file %s: %w", authFilePath, err)
+			}
+			authClient.Credential = credentials.Credential(store)
+		}
+	}
+	if sysCtx.DockerInsecureSkipTLSVerify == types.OptionalBoolTrue {
+		authClient.Client = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: true, //nolint:gosec // user explicitly opted in
+				},
+			},
+		}
+	}
+	repo.Client = authClient
+	return repo, nil
+}
+
+// FetchOCIReferrer returns the content of the first referrer of the given
+// artifact type attached to digest. It returns nil if there is none.
+func FetchOCIReferrer(ctx context.Context, repo *remote.Repository, digest, artifactType string) ([]byte, error) {
+	dgst, err := godigest.Parse(digest)
+	if err != nil {
+		return nil, fmt.Errorf("parse digest: %w", err)
+	}
+
+	var referrer *ocispec.Descriptor
+	err = repo.Referrers(ctx, ocispec.Descriptor{Digest: dgst}, artifactType, func(referrers []ocispec.Descriptor) error {
+		if referrer == nil && len(referrers) > 0 {
+			referrer = &referrers[0]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list referrers: %w", err)
+	}
+	if referrer == nil {
+		return nil, nil
+	}
+
+	manifestData, err := content.FetchAll(ctx, repo, *referrer)
+	if err != nil {
+		return nil, fmt.Errorf("fetch referrer manifest: %w", err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, fmt.Errorf("parse referrer manifest: %w", err)
+	}
+	if len(manifest.Layers) == 0 {
+		return nil, fmt.Errorf("referrer %s has no layers", referrer.Digest)
+	}
+	data, err := content.FetchAll(ctx, repo.Blobs(), manifest.Layers[0])
+	if err != nil {
+		return nil, fmt.Errorf("fetch referrer content: %w", err)
+	}
+	return data, nil
+}
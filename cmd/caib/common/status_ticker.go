@@ -0,0 +1,90 @@
+package caibcommon
+
+import (
+	"context"
+	"time"
+
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+	buildapiclient "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi/client"
+)
+
+// streamingPollInterval is the polling interval while status changes are streamed.
+const streamingPollInterval = 30 * time.Second
+
+// WatchFunc streams the status changes of a resource to fn until ctx is done.
+type WatchFunc func(ctx context.Context, fn func(buildapitypes.BuildEvent) error) error
+
+// BuildWatch returns the WatchFunc of an ImageBuild.
+func BuildWatch(api *buildapiclient.Client, name string) WatchFunc {
+	return func(ctx context.Context, fn func(buildapitypes.BuildEvent) error) error {
+		return api.WatchBuild(ctx, name, fn)
+	}
+}
+
+// ResourceWatch returns the WatchFunc of a resource of kind, e.g. "FlashJob".
+func ResourceWatch(api *buildapiclient.Client, kind, name string) WatchFunc {
+	filter := buildapiclient.EventFilter{Kind: kind, Name: name}
+	return func(ctx context.Context, fn func(buildapitypes.BuildEvent) error) error {
+		return api.WatchEvents(ctx, filter, fn)
+	}
+}
+
+// StatusTicker ticks whenever the status of a resource should be checked: as
+// soon as the Build API streams a change and every interval otherwise. While
+// changes are streamed, the interval is stretched to streamingPollInterval;
+// polling at the full rate resumes when the stream is unavailable or ends.
+type StatusTicker struct {
+	C <-chan time.Time
+
+	ticker *time.Ticker
+	cancel context.CancelFunc
+}
+
+// NewStatusTicker returns a StatusTicker polling every interval and woken up
+// by the events of watch, which may be nil.
+func NewStatusTicker(ctx context.Context, interval time.Duration, watch WatchFunc) *StatusTicker {
+	ctx, cancel := context.WithCancel(ctx)
+	c := make(chan time.Time, 1)
+	t := &StatusTicker{C: c, ticker: time.NewTicker(interval), cancel: cancel}
+
+	tick := func(now time.Time) {
+		select {
+		case c <- now:
+		default:
+		}
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-t.ticker.C:
+				tick(now)
+			}
+		}
+	}()
+
+	if watch != nil {
+		go func() {
+			streaming := false
+			_ = watch(ctx, func(buildapitypes.BuildEvent) error {
+				if !streaming {
+					streaming = true
+					t.ticker.Reset(max(interval, streamingPollInterval))
+				}
+				tick(time.Now())
+				return nil
+			})
+			if streaming && ctx.Err() == nil {
+				t.ticker.Reset(interval)
+			}
+		}()
+	}
+	return t
+}
+
+// Stop stops the ticker and its event stream.
+func (t *StatusTicker) Stop() {
+	t.cancel()
+	t.ticker.Stop()
+}
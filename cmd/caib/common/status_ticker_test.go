@@ -0,0 +1,49 @@
+package caibcommon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+	buildapiclient "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi/client"
+)
+
+func TestStatusTickerWakesUpOnEvents(t *testing.T) {
+	events := make(chan buildapitypes.BuildEvent)
+	ticker := NewStatusTicker(context.Background(), time.Hour, func(ctx context.Context, fn func(buildapitypes.BuildEvent) error) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case ev := <-events:
+				if err := fn(ev); err != nil {
+					return err
+				}
+			}
+		}
+	})
+	defer ticker.Stop()
+
+	events <- buildapitypes.BuildEvent{Phase: "Building"}
+	select {
+	case <-ticker.C:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a tick for the streamed event")
+	}
+}
+
+func TestStatusTickerPollsWithoutEvents(t *testing.T) {
+	ticker := NewStatusTicker(context.Background(), 10*time.Millisecond, func(context.Context, func(buildapitypes.BuildEvent) error) error {
+		return buildapiclient.ErrEventsUnavailable
+	})
+	defer ticker.Stop()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-ticker.C:
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected polling ticks when events are unavailable")
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package caibcommon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+	buildapiclient "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi/client"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/storage"
+)
+
+// Environment variables holding storage credentials for --export-to and downloads.
+const (
+	EnvAWSAccessKeyID     = "AWS_ACCESS_KEY_ID"
+	EnvAWSSecretAccessKey = "AWS_SECRET_ACCESS_KEY"
+	EnvAWSSessionToken    = "AWS_SESSION_TOKEN"
+	EnvExportToken        = "CAIB_EXPORT_TOKEN"
+	EnvExportUsername     = "CAIB_EXPORT_USERNAME"
+	EnvExportPassword     = "CAIB_EXPORT_PASSWORD"
+)
+
+// StorageBackendFactory creates storage backends for downloads; replaced in tests.
+var StorageBackendFactory storage.Factory = storage.NewBackend
+
+// ParseExportTarget converts an --export-to value into an export location.
+// Supported forms are s3://bucket/key, http(s)://host/path and pvc://claim/path.
+// Credentials are taken from the environment unless secretRef names an
+// existing secret in the build namespace.
+func ParseExportTarget(target, endpoint, region, secretRef string) (*buildapitypes.ArtifactLocation, error) {
+	target = strings.TrimSpace(target)
+	loc := &buildapitypes.ArtifactLocation{SecretRef: strings.TrimSpace(secretRef)}
+
+	switch {
+	case strings.HasPrefix(target, "s3://"):
+		if _, _, err := storage.ParseS3URL(target); err != nil {
+			return nil, err
+		}
+		if endpoint == "" {
+			return nil, NewActionableError(
+				fmt.Errorf("--s3-endpoint is required for s3:// export targets"),
+				fmt.Sprintf("caib image build --export-to %s --s3-endpoint https://<host>", target),
+			)
+		}
+		loc.Type = automotivev1alpha1.LocationTypeS3
+		loc.URL = target
+		loc.Endpoint = endpoint
+		loc.Region = region
+	case strings.HasPrefix(target, "http://"), strings.HasPrefix(target, "https://"):
+		loc.Type = automotivev1alpha1.LocationTypeHTTP
+		loc.URL = target
+	case strings.HasPrefix(target, "pvc://"):
+		claim, filePath, _ := strings.Cut(strings.TrimPrefix(target, "pvc://"), "/")
+		if claim == "" || filePath == "" {
+			return nil, fmt.Errorf("invalid pvc export target %q: expected pvc://<claim>/<path>", target)
+		}
+		loc.Type = automotivev1alpha1.LocationTypePVC
+		loc.ClaimName = claim
+		loc.URL = filePath
+		return loc, nil
+	default:
+		return nil, fmt.Errorf("invalid export target %q: must start with s3://, http://, https:// or pvc://", target)
+	}
+
+	if loc.SecretRef == "" {
+		loc.Credentials = storageCredentialsFromEnv(loc.Type)
+	}
+	return loc, nil
+}
+
+func storageCredentialsFromEnv(locType string) *buildapitypes.StorageCredentials {
+	creds := &buildapitypes.StorageCredentials{}
+	if locType == automotivev1alpha1.LocationTypeS3 {
+		creds.AccessKeyID = os.Getenv(EnvAWSAccessKeyID)
+		creds.SecretAccessKey = os.Getenv(EnvAWSSecretAccessKey)
+		creds.Token = os.Getenv(EnvAWSSessionToken)
+	} else {
+		creds.Token = os.Getenv(EnvExportToken)
+		creds.Username = os.Getenv(EnvExportUsername)
+		creds.Password = os.Getenv(EnvExportPassword)
+	}
+	if *creds == (buildapitypes.StorageCredentials{}) {
+		return nil
+	}
+	return creds
+}
+
+// DownloadStorageArtifact downloads a disk image exported to s3, http or pvc
+// storage. S3 and HTTP objects are fetched directly using credentials from the
+// environment; PVC exports are streamed through the build API server.
+// destPath may be a file or an existing directory.
+func DownloadStorageArtifact(
+	ctx context.Context,
+	api *buildapiclient.Client,
+	buildName string,
+	loc *buildapitypes.ArtifactLocation,
+	destPath string,
+) error {
+	var (
+		body     io.ReadCloser
+		info     *storage.ObjectInfo
+		err      error
+		fileName = path.Base(loc.URL)
+	)
+	switch loc.Type {
+	case automotivev1alpha1.LocationTypePVC:
+		body, err = api.DownloadBuildDisk(ctx, buildName)
+	case automotivev1alpha1.LocationTypeS3, automotivev1alpha1.LocationTypeHTTP:
+		var backend storage.Backend
+		backend, err = StorageBackendFactory(imageLocationFromArtifact(loc), credentialsFromEnv(loc.Type))
+		if err == nil {
+			body, info, err = backend.Open(ctx)
+		}
+	default:
+		return fmt.Errorf("unsupported disk location type %q", loc.Type)
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = body.Close() }()
+
+	if fi, statErr := os.Stat(destPath); (statErr == nil && fi.IsDir()) || strings.HasSuffix(destPath, string(os.PathSeparator)) {
+		destPath = filepath.Join(destPath, sanitizeFilename(fileName, 0))
+	}
+	if dir := filepath.Dir(destPath); dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("create output dir: %w", err)
+		}
+	}
+	fmt.Printf("Downloading %s to %s\n", loc.URL, destPath)
+
+	out, err := os.Create(destPath)
+	if err != nil {
+		return fmt.Errorf("create destination: %w", err)
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(out, hash), body); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("download %s: %w", loc.URL, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("close destination: %w", err)
+	}
+
+	if info != nil && info.Digest != "" {
+		if got := "sha256:" + hex.EncodeToString(hash.Sum(nil)); got != info.Digest {
+			return fmt.Errorf("digest mismatch for %s: expected %s, got %s", loc.URL, info.Digest, got)
+		}
+	}
+	return nil
+}
+
+func imageLocationFromArtifact(loc *buildapitypes.ArtifactLocation) *automotivev1alpha1.ImageLocation {
+	location := &automotivev1alpha1.ImageLocation{Type: loc.Type}
+	switch loc.Type {
+	case automotivev1alpha1.LocationTypeS3:
+		bucket, key, _ := storage.ParseS3URL(loc.URL)
+		location.S3 = &automotivev1alpha1.S3Location{
+			Endpoint: loc.Endpoint,
+			Region:   loc.Region,
+			Bucket:   bucket,
+			Key:      key,
+		}
+	case automotivev1alpha1.LocationTypeHTTP:
+		location.HTTP = &automotivev1alpha1.HTTPLocation{URL: loc.URL}
+	}
+	return location
+}
+
+func credentialsFromEnv(locType string) storage.Credentials {
+	creds := storageCredentialsFromEnv(locType)
+	if creds == nil {
+		return storage.Credentials{}
+	}
+	return storage.Credentials{
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		Token:           creds.Token,
+		Username:        creds.Username,
+		Password:        creds.Password,
+	}
+}
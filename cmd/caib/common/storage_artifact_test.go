@@ -0,0 +1,124 @@
+package caibcommon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+	buildapiclient "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi/client"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/storage"
+)
+
+func TestParseExportTarget(t *testing.T) {
+	t.Setenv(EnvAWSAccessKeyID, "minio")
+	t.Setenv(EnvAWSSecretAccessKey, "minio123")
+
+	loc, err := ParseExportTarget("s3://images/rhivos/disk.qcow2", "http://minio:9000", "", "")
+	if err != nil {
+		t.Fatalf("ParseExportTarget() error = %v", err)
+	}
+	if loc.Type != "s3" || loc.Endpoint != "http://minio:9000" || loc.Credentials == nil || loc.Credentials.AccessKeyID != "minio" {
+		t.Errorf("unexpected s3 location %+v", loc)
+	}
+
+	loc, err = ParseExportTarget("s3://images/disk.qcow2", "http://minio:9000", "", "minio-creds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.Credentials != nil || loc.SecretRef != "minio-creds" {
+		t.Errorf("expected --export-secret to replace environment credentials, got %+v", loc)
+	}
+
+	loc, err = ParseExportTarget("pvc://images/builds/disk.raw", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.Type != "pvc" || loc.ClaimName != "images" || loc.URL != "builds/disk.raw" {
+		t.Errorf("unexpected pvc location %+v", loc)
+	}
+
+	for _, target := range []string{"s3://images/disk.qcow2", "pvc://images", "ftp://host/disk.raw"} {
+		if _, err := ParseExportTarget(target, "", "", ""); err == nil {
+			t.Errorf("ParseExportTarget(%q) should fail", target)
+		}
+	}
+}
+
+func TestDownloadStorageArtifact_HTTP(t *testing.T) {
+	t.Setenv(EnvExportToken, "secret")
+	content := "disk-image-bytes"
+	sum := sha256.Sum256([]byte(content))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set(storage.HTTPDigestHeader, hex.EncodeToString(sum[:]))
+		_, _ = io.WriteString(w, content)
+	}))
+	defer srv.Close()
+
+	outDir := t.TempDir()
+	loc := &buildapitypes.ArtifactLocation{Type: "http", URL: srv.URL + "/builds/disk.raw"}
+	if err := DownloadStorageArtifact(context.Background(), nil, "my-build", loc, outDir); err != nil {
+		t.Fatalf("DownloadStorageArtifact() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(outDir, "disk.raw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != content {
+		t.Errorf("downloaded %q, want %q", data, content)
+	}
+}
+
+func TestDownloadStorageArtifact_DigestMismatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(storage.HTTPDigestHeader, strings.Repeat("0", 64))
+		_, _ = io.WriteString(w, "tampered")
+	}))
+	defer srv.Close()
+
+	loc := &buildapitypes.ArtifactLocation{Type: "http", URL: srv.URL + "/disk.raw"}
+	err := DownloadStorageArtifact(context.Background(), nil, "my-build", loc, filepath.Join(t.TempDir(), "disk.raw"))
+	if err == nil || !strings.Contains(err.Error(), "digest mismatch") {
+		t.Errorf("DownloadStorageArtifact() error = %v, want digest mismatch", err)
+	}
+}
+
+func TestDownloadStorageArtifact_PVCUsesServer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/builds/my-build/disk" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = io.WriteString(w, "from-pvc")
+	}))
+	defer srv.Close()
+
+	api, err := buildapiclient.New(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(t.TempDir(), "out", "disk.raw")
+	loc := &buildapitypes.ArtifactLocation{Type: "pvc", ClaimName: "images", URL: "builds/disk.raw"}
+	if err := DownloadStorageArtifact(context.Background(), api, "my-build", loc, dest); err != nil {
+		t.Fatalf("DownloadStorageArtifact() error = %v", err)
+	}
+	data, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "from-pvc" {
+		t.Errorf("downloaded %q, want %q", data, "from-pvc")
+	}
+}
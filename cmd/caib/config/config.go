@@ -46,6 +46,12 @@ func DefaultServer() string {
 	return ""
 }
 
+// Project returns the project, or tenant namespace, of Build API requests:
+// the CAIB_PROJECT env, which --project sets, or "" for the caller's default.
+func Project() string {
+	return strings.TrimSpace(os.Getenv("CAIB_PROJECT"))
+}
+
 // DefaultServerWithDerive returns the effective default server URL.
 // Resolution order: CAIB_SERVER env → saved config → Jumpstarter derivation.
 func DefaultServerWithDerive() string {
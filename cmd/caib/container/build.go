@@ -415,7 +415,11 @@ func waitForContainerBuildCompletion(ctx context.Context, name string, pb *ui.Pr
 	}
 
 	timeout := time.After(waitTimeout)
-	ticker := time.NewTicker(2 * time.Second)
+	var watch caibcommon.WatchFunc
+	if api, err := createBuildAPIClient(serverURL, &authToken); err == nil {
+		watch = caibcommon.ResourceWatch(api, "ContainerBuild", name)
+	}
+	ticker := caibcommon.NewStatusTicker(ctx, 2*time.Second, watch)
 	defer ticker.Stop()
 
 	for {
@@ -26,6 +26,7 @@ import (
 	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/auth"
 	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/clilog"
 	caibcommon "github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/common"
+	caibconfig "github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/config"
 	buildapiclient "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi/client"
 	"k8s.io/client-go/tools/clientcmd"
 )
@@ -152,6 +153,9 @@ func createBuildAPIClient(serverURL string, authToken *string) (*buildapiclient.
 	if strings.TrimSpace(*authToken) != "" {
 		opts = append(opts, buildapiclient.WithAuthToken(strings.TrimSpace(*authToken)))
 	}
+	if project := caibconfig.Project(); project != "" {
+		opts = append(opts, buildapiclient.WithProject(project))
+	}
 
 	// Configure TLS
 	if insecureSkipTLS {
@@ -21,6 +21,7 @@ type Options struct {
 	ServerURL       *string
 	AuthToken       *string
 	OutputDir       *string
+	SBOM            *bool
 	InsecureSkipTLS *bool
 
 	HandleError func(error)
@@ -88,10 +89,28 @@ func (h *Handler) RunDownload(_ *cobra.Command, args []string) {
 		return
 	}
 
+	if st.DiskLocation != nil {
+		if h.opts.SBOM != nil && *h.opts.SBOM {
+			h.handleError(fmt.Errorf(
+				"build %s exported its disk image to %s storage; SBOMs are only attached to disk images pushed to an OCI registry",
+				downloadBuildName, st.DiskLocation.Type,
+			))
+			return
+		}
+		clilog.Infof("Downloading disk image from %s storage\n", st.DiskLocation.Type)
+		err := common.ExecuteWithReauth(serverURL, h.opts.AuthToken, insecureSkipTLS, func(api *buildapiclient.Client) error {
+			return common.DownloadStorageArtifact(ctx, api, downloadBuildName, st.DiskLocation, outputDir)
+		})
+		if err != nil {
+			h.handleError(fmt.Errorf("download failed: %w", err))
+		}
+		return
+	}
+
 	ociRef := st.DiskImage
 	if ociRef == "" {
 		h.handleError(fmt.Errorf(
-			"build %s has no disk image artifact to download (no OCI export was configured)",
+			"build %s has no disk image artifact to download (no disk export was configured)",
 			downloadBuildName,
 		))
 		return
@@ -112,6 +131,13 @@ func (h *Handler) RunDownload(_ *cobra.Command, args []string) {
 		}
 	}
 
+	if h.opts.SBOM != nil && *h.opts.SBOM {
+		if err := downloadSBOMs(ctx, ociRef, outputDir, registryUsername, registryPassword, insecureSkipTLS); err != nil {
+			h.handleError(fmt.Errorf("SBOM download failed: %w", err))
+		}
+		return
+	}
+
 	clilog.Infof("Downloading disk image from %s\n", ociRef)
 	if err := common.PullOCIArtifact(ociRef, outputDir, registryUsername, registryPassword, insecureSkipTLS); err != nil {
 		h.handleError(fmt.Errorf("download failed: %w", err))
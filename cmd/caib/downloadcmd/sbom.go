@@ -0,0 +1,75 @@
+package downloadcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/image/v5/types"
+	"oras.land/oras-go/v2/registry"
+
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/clilog"
+	common "github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/common"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/oci"
+)
+
+// sbomLabels are the OCI spec labels of the SBOM referrers attached to disk
+// images by the push task.
+var sbomLabels = []string{"SPDX SBOM", "CycloneDX SBOM"}
+
+// downloadSBOMs writes the SBOM referrers of the disk image at ociRef to
+// outputDir, named after the filenames of the OCI spec.
+func downloadSBOMs(ctx context.Context, ociRef, outputDir, username, password string, insecureSkipTLS bool) error {
+	ref, err := registry.ParseReference(ociRef)
+	if err != nil {
+		return fmt.Errorf("parse reference %s: %w", ociRef, err)
+	}
+
+	sysCtx := &types.SystemContext{}
+	if insecureSkipTLS {
+		sysCtx.DockerInsecureSkipTLSVerify = types.OptionalBoolTrue
+	}
+	if username != "" && password != "" {
+		sysCtx.DockerAuthConfig = &types.DockerAuthConfig{Username: username, Password: password}
+	}
+	repo, err := common.NewOCIRepository(ref.Registry+"/"+ref.Repository, sysCtx)
+	if err != nil {
+		return err
+	}
+	reference := ref.Reference
+	if reference == "" {
+		reference = "latest"
+	}
+	desc, err := repo.Resolve(ctx, reference)
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", ociRef, err)
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+	spec := oci.Get()
+	fileMap := spec.ReferrerFileMap()
+	found := 0
+	for _, label := range sbomLabels {
+		artifactType := spec.ReferrerArtifactTypeByLabel(label)
+		data, err := common.FetchOCIReferrer(ctx, repo, desc.Digest.String(), artifactType)
+		if err != nil {
+			return fmt.Errorf("fetch %s: %w", label, err)
+		}
+		if data == nil {
+			continue
+		}
+		destPath := filepath.Join(outputDir, fileMap[artifactType])
+		if err := os.WriteFile(destPath, data, 0644); err != nil {
+			return fmt.Errorf("write %s: %w", destPath, err)
+		}
+		clilog.Infof("Downloaded %s → %s\n", label, destPath)
+		found++
+	}
+	if found == 0 {
+		return fmt.Errorf("no SBOM is attached to %s@%s", ref.Registry+"/"+ref.Repository, desc.Digest)
+	}
+	return nil
+}
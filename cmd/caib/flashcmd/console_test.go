@@ -0,0 +1,59 @@
+package flashcmd
+
+import (
+	"strings"
+	"testing"
+
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+)
+
+func TestConsoleRequest(t *testing.T) {
+	boolPtr := func(v bool) *bool { return &v }
+	strPtr := func(v string) *string { return &v }
+
+	tests := []struct {
+		name    string
+		opts    Options
+		want    *buildapitypes.ConsoleCaptureRequest
+		wantErr string
+	}{
+		{name: "no console", opts: Options{}},
+		{
+			name: "default duration",
+			opts: Options{CaptureConsole: boolPtr(true), ConsoleDuration: strPtr("")},
+			want: &buildapitypes.ConsoleCaptureRequest{},
+		},
+		{
+			name: "duration without power cycle",
+			opts: Options{CaptureConsole: boolPtr(true), ConsoleDuration: strPtr("10m"), NoPowerCycle: boolPtr(true)},
+			want: &buildapitypes.ConsoleCaptureRequest{Duration: "10m", SkipPowerCycle: true},
+		},
+		{
+			name:    "duration without console",
+			opts:    Options{CaptureConsole: boolPtr(false), ConsoleDuration: strPtr("10m")},
+			wantErr: "requires --console",
+		},
+		{
+			name:    "invalid duration",
+			opts:    Options{CaptureConsole: boolPtr(true), ConsoleDuration: strPtr("soon")},
+			wantErr: "invalid --console-duration",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewHandler(tt.opts).consoleRequest()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("consoleRequest() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -41,6 +41,17 @@ type Options struct {
 	LeaseDuration     *string
 	LeaseName         *string
 	FlashCmd          *string
+	TestScript        *string
+	TestImage         *string
+	BootCheck         *string
+	BootMarker        *string
+	BootTimeout       *string
+	NoPowerCycle      *bool
+	FleetBoards       *int
+	FleetAllBoards    *bool
+	FleetMaxParallel  *int
+	CaptureConsole    *bool
+	ConsoleDuration   *string
 	WaitForBuild      *bool
 	FollowLogs        *bool
 	InsecureSkipTLS   *bool
@@ -130,6 +141,21 @@ func (h *Handler) RunFlash(cmd *cobra.Command, args []string) {
 	if req.LeaseName == "" {
 		req.LeaseDuration = *h.opts.LeaseDuration
 	}
+	req.Test, err = common.DeviceTestRequest(h.deviceTestFlags())
+	if err != nil {
+		h.handleError(err)
+		return
+	}
+	req.Fleet, err = h.fleetRequest()
+	if err != nil {
+		h.handleError(err)
+		return
+	}
+	req.Console, err = h.consoleRequest()
+	if err != nil {
+		h.handleError(err)
+		return
+	}
 
 	// Resolve OCI registry credentials for the flash image
 	authFile := ""
@@ -153,9 +179,14 @@ func (h *Handler) RunFlash(cmd *cobra.Command, args []string) {
 	}
 	clilog.Infof("Flash job %s accepted: %s - %s\n", resp.Name, resp.Phase, resp.Message)
 
-	if *h.opts.WaitForBuild || *h.opts.FollowLogs {
-		h.waitForFlashCompletion(ctx, api, resp.Name)
+	if !*h.opts.WaitForBuild && !*h.opts.FollowLogs && req.Console == nil {
+		return
+	}
+	if req.Fleet != nil {
+		h.waitForFleetCompletion(ctx, resp.Name, req.Fleet)
+		return
 	}
+	h.waitForFlashCompletion(ctx, api, resp.Name)
 }
 
 // parseLeaseDuration converts HH:MM:SS format to time.Duration.
@@ -202,11 +233,21 @@ func (h *Handler) waitForFlashCompletion(ctx context.Context, _ *buildapiclient.
 	}
 	timeoutCtx, cancel := context.WithTimeout(ctx, timeoutDuration)
 	defer cancel()
-	ticker := time.NewTicker(5 * time.Second)
+	var watch common.WatchFunc
+	if api, err := common.CreateBuildAPIClient(*h.opts.ServerURL, h.opts.AuthToken, *h.opts.InsecureSkipTLS); err == nil {
+		watch = common.ResourceWatch(api, "FlashJob", name)
+	}
+	ticker := common.NewStatusTicker(timeoutCtx, 5*time.Second, watch)
 	defer ticker.Stop()
 
 	var lastPhase, lastMessage string
 	pendingWarningShown := false
+	// The serial console replaces the flash logs when it is captured
+	console := h.opts.CaptureConsole != nil && *h.opts.CaptureConsole
+	logPath := "logs"
+	if console {
+		logPath = "console"
+	}
 
 	flashLogTransport := &http.Transport{
 		ResponseHeaderTimeout: 30 * time.Second,
@@ -248,6 +289,15 @@ func (h *Handler) waitForFlashCompletion(ctx context.Context, _ *buildapiclient.
 
 			if st.Phase == phaseCompleted {
 				clilog.Infoln("Flash completed successfully!")
+				if st.LeaseID != "" {
+					clilog.Infof("Lease: %s (exporter: %s)\n", st.LeaseID, st.Exporter)
+				}
+				if st.DeviceTests != nil {
+					clilog.Infof("Device tests: %s\n", common.FormatDeviceTests(st.DeviceTests))
+				}
+				if st.ConsoleRef != "" {
+					clilog.Infof("Console transcript: %s\n", st.ConsoleRef)
+				}
 				return
 			}
 			if st.Phase == phaseFailed {
@@ -255,7 +305,7 @@ func (h *Handler) waitForFlashCompletion(ctx context.Context, _ *buildapiclient.
 				return
 			}
 
-			if !*h.opts.FollowLogs || streamState.Active || !streamState.CanRetry(maxLogRetries) {
+			if (!*h.opts.FollowLogs && !console) || streamState.Active || !streamState.CanRetry(maxLogRetries) {
 				continue
 			}
 
@@ -273,7 +323,7 @@ func (h *Handler) waitForFlashCompletion(ctx context.Context, _ *buildapiclient.
 					clilog.Infoln("Flash is running. Attempting to stream logs...")
 					pendingWarningShown = false
 				}
-				if err := h.tryFlashLogStreaming(timeoutCtx, logClient, name, streamState); err != nil {
+				if err := h.tryFlashLogStreaming(timeoutCtx, logClient, name, logPath, streamState); err != nil {
 					streamState.RetryCount++
 				}
 			}
@@ -281,8 +331,11 @@ func (h *Handler) waitForFlashCompletion(ctx context.Context, _ *buildapiclient.
 	}
 }
 
-func (h *Handler) tryFlashLogStreaming(ctx context.Context, logClient *http.Client, name string, state *logstream.State) error {
-	logURL := strings.TrimRight(strings.TrimSpace(*h.opts.ServerURL), "/") + "/v1/flash/" + url.PathEscape(name) + "/logs?follow=1"
+func (h *Handler) tryFlashLogStreaming(
+	ctx context.Context, logClient *http.Client, name, logPath string, state *logstream.State,
+) error {
+	logURL := strings.TrimRight(strings.TrimSpace(*h.opts.ServerURL), "/") + "/v1/flash/" + url.PathEscape(name) +
+		"/" + logPath + "?follow=1"
 	if !state.StartTime.IsZero() {
 		logURL += "&since=" + url.QueryEscape(state.StartTime.Format(time.RFC3339))
 	}
@@ -310,3 +363,45 @@ func (h *Handler) tryFlashLogStreaming(ctx context.Context, logClient *http.Clie
 	}
 	return logstream.HandleLogStreamError(resp, state, maxLogRetries)
 }
+
+// consoleRequest builds the serial console capture from --console and
+// --console-duration. It returns nil when the console is not captured.
+func (h *Handler) consoleRequest() (*buildapitypes.ConsoleCaptureRequest, error) {
+	duration := ""
+	if h.opts.ConsoleDuration != nil {
+		duration = strings.TrimSpace(*h.opts.ConsoleDuration)
+	}
+	if h.opts.CaptureConsole == nil || !*h.opts.CaptureConsole {
+		if duration != "" {
+			return nil, fmt.Errorf("--console-duration requires --console")
+		}
+		return nil, nil
+	}
+	if duration != "" {
+		if d, err := time.ParseDuration(duration); err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid --console-duration %q: must be a positive duration (e.g. 5m)", duration)
+		}
+	}
+	return &buildapitypes.ConsoleCaptureRequest{
+		Duration:       duration,
+		SkipPowerCycle: h.opts.NoPowerCycle != nil && *h.opts.NoPowerCycle,
+	}, nil
+}
+
+// deviceTestFlags collects the on-device test flags.
+func (h *Handler) deviceTestFlags() common.DeviceTestFlags {
+	value := func(p *string) string {
+		if p == nil {
+			return ""
+		}
+		return strings.TrimSpace(*p)
+	}
+	return common.DeviceTestFlags{
+		ScriptFile:     value(h.opts.TestScript),
+		Image:          value(h.opts.TestImage),
+		BootCheck:      value(h.opts.BootCheck),
+		BootMarker:     value(h.opts.BootMarker),
+		BootTimeout:    value(h.opts.BootTimeout),
+		SkipPowerCycle: h.opts.NoPowerCycle != nil && *h.opts.NoPowerCycle,
+	}
+}
@@ -0,0 +1,166 @@
+package flashcmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/clilog"
+	common "github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/common"
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+	buildapiclient "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi/client"
+)
+
+// fleetRequest builds the fleet request from --boards, --all-boards and
+// --max-parallel. It returns nil for a single-board flash.
+func (h *Handler) fleetRequest() (*buildapitypes.FleetFlashRequest, error) {
+	var boards, maxParallel int
+	var allBoards bool
+	if h.opts.FleetBoards != nil {
+		boards = *h.opts.FleetBoards
+	}
+	if h.opts.FleetAllBoards != nil {
+		allBoards = *h.opts.FleetAllBoards
+	}
+	if h.opts.FleetMaxParallel != nil {
+		maxParallel = *h.opts.FleetMaxParallel
+	}
+
+	if boards < 0 || maxParallel < 0 {
+		return nil, fmt.Errorf("--boards and --max-parallel must not be negative")
+	}
+	if boards > 0 && allBoards {
+		return nil, fmt.Errorf("--boards and --all-boards are mutually exclusive")
+	}
+	if boards == 0 && !allBoards {
+		if maxParallel > 0 {
+			return nil, fmt.Errorf("--max-parallel requires --boards or --all-boards")
+		}
+		return nil, nil
+	}
+	if h.opts.LeaseName != nil && *h.opts.LeaseName != "" {
+		return nil, fmt.Errorf("--lease cannot be used with --boards or --all-boards")
+	}
+	return &buildapitypes.FleetFlashRequest{
+		Count:        int32(boards),
+		AllExporters: allBoards,
+		MaxParallel:  int32(maxParallel),
+	}, nil
+}
+
+// waitForFleetCompletion waits for a fleet flash to complete, printing the
+// per-board progress table whenever it changes. Each batch of boards gets the
+// lease duration to finish.
+func (h *Handler) waitForFleetCompletion(ctx context.Context, name string, fleet *buildapitypes.FleetFlashRequest) {
+	clilog.Infoln("Waiting for fleet flash to complete...")
+	if *h.opts.FollowLogs || (h.opts.CaptureConsole != nil && *h.opts.CaptureConsole) {
+		clilog.Infoln("Log streaming is not available for fleet flashes, showing per-board progress instead")
+	}
+
+	leaseDuration, err := parseLeaseDuration(*h.opts.LeaseDuration)
+	if err != nil {
+		h.handleError(fmt.Errorf("invalid lease duration: %w", err))
+		return
+	}
+	batchTimeout := leaseDuration + 10*time.Minute
+	start := time.Now()
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	var lastTable string
+	boardCount := int(fleet.Count)
+	for {
+		select {
+		case <-ctx.Done():
+			h.handleError(ctx.Err())
+			return
+		case <-ticker.C:
+		}
+		if time.Since(start) > batchTimeout*time.Duration(fleetBatches(boardCount, int(fleet.MaxParallel))) {
+			h.handleError(fmt.Errorf("timed out waiting for fleet flash"))
+			return
+		}
+
+		reqCtx, cancelReq := context.WithTimeout(ctx, 2*time.Minute)
+		var st *buildapitypes.FlashResponse
+		err := common.ExecuteWithReauth(*h.opts.ServerURL, h.opts.AuthToken, *h.opts.InsecureSkipTLS, func(api *buildapiclient.Client) error {
+			var getErr error
+			st, getErr = api.GetFlash(reqCtx, name)
+			return getErr
+		})
+		cancelReq()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "status check failed: %v\n", err)
+			continue
+		}
+		if len(st.Boards) > 0 {
+			boardCount = len(st.Boards)
+		}
+
+		var table bytes.Buffer
+		if err := writeBoardTable(&table, st.Boards); err != nil {
+			h.handleError(err)
+			return
+		}
+		if table.String() != lastTable {
+			clilog.Infof("status: %s - %s\n", st.Phase, st.Message)
+			fmt.Print(table.String())
+			lastTable = table.String()
+		}
+
+		switch st.Phase {
+		case phaseCompleted:
+			clilog.Infof("Fleet flash completed: %s\n", st.Message)
+			for _, board := range st.Boards {
+				if board.ConsoleRef != "" {
+					clilog.Infof("Board %d console transcript: %s\n", board.Board, board.ConsoleRef)
+				}
+			}
+			return
+		case phaseFailed:
+			h.handleError(fmt.Errorf("fleet flash failed: %s", st.Message))
+			return
+		}
+	}
+}
+
+// fleetBatches returns how many rounds of maxParallel boards a fleet flash takes.
+func fleetBatches(boards, maxParallel int) int {
+	if boards <= 0 || maxParallel <= 0 || maxParallel >= boards {
+		return 1
+	}
+	return (boards + maxParallel - 1) / maxParallel
+}
+
+// writeBoardTable renders the per-board state of a fleet flash.
+func writeBoardTable(out io.Writer, boards []buildapitypes.FlashBoardStatus) error {
+	if len(boards) == 0 {
+		return nil
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "BOARD\tPHASE\tEXPORTER\tLEASE\tMESSAGE"); err != nil {
+		return err
+	}
+	for _, board := range boards {
+		message := board.Message
+		if board.DeviceTests != nil && board.Phase == phaseCompleted {
+			message = "tests: " + common.FormatDeviceTests(board.DeviceTests)
+		}
+		if _, err := fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", board.Board, board.Phase,
+			valueOrDash(board.Exporter), valueOrDash(board.LeaseID), valueOrDash(message)); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
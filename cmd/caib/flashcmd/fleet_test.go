@@ -0,0 +1,117 @@
+package flashcmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+)
+
+func TestFleetRequest(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	boolPtr := func(v bool) *bool { return &v }
+	strPtr := func(v string) *string { return &v }
+
+	tests := []struct {
+		name    string
+		opts    Options
+		want    *buildapitypes.FleetFlashRequest
+		wantErr string
+	}{
+		{name: "single board", opts: Options{}},
+		{
+			name: "count",
+			opts: Options{FleetBoards: intPtr(12), FleetMaxParallel: intPtr(4)},
+			want: &buildapitypes.FleetFlashRequest{Count: 12, MaxParallel: 4},
+		},
+		{
+			name: "all boards",
+			opts: Options{FleetAllBoards: boolPtr(true)},
+			want: &buildapitypes.FleetFlashRequest{AllExporters: true},
+		},
+		{
+			name:    "count and all boards",
+			opts:    Options{FleetBoards: intPtr(2), FleetAllBoards: boolPtr(true)},
+			wantErr: "mutually exclusive",
+		},
+		{
+			name:    "max parallel without fleet",
+			opts:    Options{FleetMaxParallel: intPtr(2)},
+			wantErr: "requires --boards or --all-boards",
+		},
+		{
+			name:    "existing lease",
+			opts:    Options{FleetBoards: intPtr(2), LeaseName: strPtr("lease-1")},
+			wantErr: "--lease cannot be used",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewHandler(tt.opts).fleetRequest()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("fleetRequest() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFleetBatches(t *testing.T) {
+	for _, tt := range []struct{ boards, maxParallel, want int }{
+		{boards: 12, maxParallel: 0, want: 1},
+		{boards: 12, maxParallel: 4, want: 3},
+		{boards: 12, maxParallel: 5, want: 3},
+		{boards: 3, maxParallel: 4, want: 1},
+		{boards: 0, maxParallel: 4, want: 1},
+	} {
+		if got := fleetBatches(tt.boards, tt.maxParallel); got != tt.want {
+			t.Errorf("fleetBatches(%d, %d) = %d, want %d", tt.boards, tt.maxParallel, got, tt.want)
+		}
+	}
+}
+
+func TestWriteBoardTable(t *testing.T) {
+	var out bytes.Buffer
+	err := writeBoardTable(&out, []buildapitypes.FlashBoardStatus{
+		{
+			Board: 1, Phase: phaseCompleted, Exporter: "rack-01", LeaseID: "lease-1",
+			DeviceTests: &buildapitypes.DeviceTestResults{Booted: true, Total: 2, Passed: 2},
+		},
+		{Board: 2, Phase: phaseFailed, Exporter: "rack-02", LeaseID: "lease-2", Message: "flash step failed"},
+		{Board: 3, Phase: phasePending},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header and 3 rows, got:\n%s", out.String())
+	}
+	if !strings.HasPrefix(lines[0], "BOARD") || !strings.Contains(lines[0], "EXPORTER") {
+		t.Errorf("unexpected header %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "tests: 2 passed, 0 failed, 0 skipped") {
+		t.Errorf("expected device test summary, got %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "flash step failed") {
+		t.Errorf("expected failure message, got %q", lines[2])
+	}
+	if fields := strings.Fields(lines[3]); len(fields) != 5 || fields[2] != "-" {
+		t.Errorf("expected dashes for a pending board, got %q", lines[3])
+	}
+
+	out.Reset()
+	if err := writeBoardTable(&out, nil); err != nil || out.Len() != 0 {
+		t.Errorf("expected no output without boards, got %q, %v", out.String(), err)
+	}
+}
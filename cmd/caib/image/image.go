@@ -27,7 +27,10 @@ type Options struct {
 	RunToken             func(*cobra.Command, []string)
 	RunDelete            func(*cobra.Command, []string)
 	RunCancel            func(*cobra.Command, []string)
+	RunRebuild           func(*cobra.Command, []string)
+	RunDiff              func(*cobra.Command, []string)
 	RunInspect           func(*cobra.Command, []string)
+	RunVerify            func(*cobra.Command, []string)
 
 	GetDefaultArch func() string
 
@@ -67,15 +70,40 @@ type Options struct {
 	LeaseName         *string
 	FlashCmd          *string
 
+	TestScript   *string
+	TestImage    *string
+	BootCheck    *string
+	BootMarker   *string
+	BootTimeout  *string
+	NoPowerCycle *bool
+
+	FleetBoards      *int
+	FleetAllBoards   *bool
+	FleetMaxParallel *int
+
+	CaptureConsole  *bool
+	ConsoleDuration *string
+
 	UseInternalRegistry       *bool
 	InternalRegistryImageName *string
 	InternalRegistryTag       *string
 
+	ExportTo     *string
+	S3Endpoint   *string
+	S3Region     *string
+	ExportSecret *string
+
 	SecureBuild       *bool
 	Reproducible      *bool
 	TaskBundleRef     *string
 	RestoreSourcesRef *string
 	TTL               *string
+	Priority          *string
+	MaxAttempts       *int
+	Notify            *bool
+	DownloadSBOM      *bool
+	SigningKeySecret  *string
+	VerifyKeyFile     *string
 
 	SealedBuilderImage      *string
 	SealedArchitecture      *string
@@ -108,6 +136,7 @@ func NewImageCmd(opts Options) *cobra.Command {
 	buildDevCmd := newBuildDevCmd(opts)
 	listCmd := newListCmd(opts)
 	showCmd := newShowCmd(opts)
+	diffCmd := newDiffCmd(opts)
 	downloadCmd := newDownloadCmd(opts)
 	logsCmd := newLogsCmd(opts)
 	flashCmd := newFlashCmd(opts)
@@ -115,6 +144,7 @@ func NewImageCmd(opts Options) *cobra.Command {
 	tokenCmd := newTokenCmd(opts)
 	deleteCmd := newDeleteCmd(opts)
 	cancelCmd := newCancelCmd(opts)
+	rebuildCmd := newRebuildCmd(opts)
 
 	prepareResealCmd := newPrepareResealCmd(opts)
 	resealCmd := newResealCmd(opts)
@@ -127,7 +157,7 @@ func NewImageCmd(opts Options) *cobra.Command {
 	buildCmd.Flags().StringVarP(opts.BuildName, "name", "n", "", "name for the ImageBuild (auto-generated if omitted)")
 	buildCmd.Flags().StringVarP(opts.Distro, "distro", "d", "autosd", "distribution to build")
 	buildCmd.Flags().StringVarP(opts.Target, "target", "t", "", "target platform (default: from manifest, or qemu)")
-	buildCmd.Flags().StringVarP(opts.Architecture, "arch", "a", opts.GetDefaultArch(), "architecture (amd64, arm64)")
+	buildCmd.Flags().StringVarP(opts.Architecture, "arch", "a", opts.GetDefaultArch(), "architecture (amd64, arm64); comma-separate to build several and push an image index")
 	buildCmd.Flags().StringVar(opts.ContainerPush, "push", "", "push bootc container to registry (optional if --disk is used)")
 	buildCmd.Flags().BoolVar(opts.BuildDiskImage, "disk", false, "also build disk image from container")
 	buildCmd.Flags().StringVarP(opts.OutputDir, "output", "o", "", "download disk image to file from registry (implies --disk; requires --push-disk or --internal-registry)")
@@ -136,6 +166,7 @@ func NewImageCmd(opts Options) *cobra.Command {
 	)
 	buildCmd.Flags().StringVar(opts.CompressionAlgo, "compress", "gzip", "compression algorithm (gzip, lz4, xz)")
 	buildCmd.Flags().StringVar(opts.ExportOCI, "push-disk", "", "push disk image as OCI artifact to registry (implies --disk)")
+	addExportToFlags(buildCmd, opts)
 	buildCmd.Flags().StringVar(
 		opts.RegistryAuthFile,
 		"registry-auth-file",
@@ -165,9 +196,14 @@ func NewImageCmd(opts Options) *cobra.Command {
 	buildCmd.Flags().StringVar(opts.LeaseName, "lease", "", "existing Jumpstarter lease name (mutually exclusive with --lease-duration)")
 	buildCmd.Flags().StringVar(opts.FlashCmd, "flash-cmd", "", "override flash command (default: from OperatorConfig target mapping)")
 	buildCmd.Flags().StringVar(opts.ExporterSelector, "exporter", "", "direct exporter selector for flash (alternative to --target lookup)")
+	addDeviceTestFlags(buildCmd, opts)
 	// Secure build
 	buildCmd.Flags().BoolVar(opts.SecureBuild, "secure", false, "resolve tasks from signed Tekton Bundle (requires OperatorConfig taskBundleRef)")
 	buildCmd.Flags().StringVar(opts.TTL, "ttl", "", "time-to-live for the build (e.g. 24h, 72h, 168h); empty=server default, 0=no expiry")
+	buildCmd.Flags().StringVar(opts.Priority, "priority", "", "build priority class defined in OperatorConfig (e.g. release); empty=default class")
+	buildCmd.Flags().IntVar(opts.MaxAttempts, "max-attempts", 0, "retry the failed stage of builds that fail with a transient mirror or registry error, up to this many attempts in total; 0=no retries")
+	buildCmd.Flags().BoolVar(opts.Notify, "notify", false, "notify the OperatorConfig notification targets about this build's lifecycle")
+	buildCmd.Flags().StringVar(opts.SigningKeySecret, "signing-key-secret", "", "Secret holding the cosign key (cosign.key, cosign.password) that signs the pushed artifacts; empty=OperatorConfig signing key")
 	// Reproducible build
 	buildCmd.Flags().BoolVar(opts.Reproducible, "reproducible", false, "save RPMs, manifest, and task bundle for future reproduction (requires --secure)")
 	buildCmd.Flags().StringVar(opts.TaskBundleRef, "task-bundle-ref", "", "digest-pinned Tekton bundle ref for reproducible rebuild (e.g. quay.io/org/tasks@sha256:abc...)")
@@ -191,6 +227,19 @@ func NewImageCmd(opts Options) *cobra.Command {
 		opts.AuthToken, "token", os.Getenv("CAIB_TOKEN"),
 		"Bearer token for authentication (e.g., OpenShift access token)",
 	)
+	diffCmd.Flags().StringVar(
+		opts.ServerURL, "server", defaultServer, "REST API server base URL (e.g. https://api.example)",
+	)
+	diffCmd.Flags().StringVar(
+		opts.AuthToken, "token", os.Getenv("CAIB_TOKEN"),
+		"Bearer token for authentication (e.g., OpenShift access token)",
+	)
+	diffCmd.Flags().StringVar(
+		opts.RegistryAuthFile,
+		"registry-auth-file",
+		"",
+		"path to Docker/Podman auth file for reading the package lists of the disk artifacts",
+	)
 
 	// disk command flags (create disk from existing container)
 	diskCmd.Flags().StringVar(opts.ServerURL, "server", defaultServer, "REST API server base URL")
@@ -202,6 +251,7 @@ func NewImageCmd(opts Options) *cobra.Command {
 	)
 	diskCmd.Flags().StringVar(opts.CompressionAlgo, "compress", "gzip", "compression algorithm (gzip, lz4, xz)")
 	diskCmd.Flags().StringVar(opts.ExportOCI, "push", "", "push disk image as OCI artifact to registry")
+	addExportToFlags(diskCmd, opts)
 	diskCmd.Flags().StringVar(
 		opts.RegistryAuthFile,
 		"registry-auth-file",
@@ -227,9 +277,14 @@ func NewImageCmd(opts Options) *cobra.Command {
 	diskCmd.Flags().StringVar(opts.LeaseName, "lease", "", "existing Jumpstarter lease name (mutually exclusive with --lease-duration)")
 	diskCmd.Flags().StringVar(opts.FlashCmd, "flash-cmd", "", "override flash command (default: from OperatorConfig target mapping)")
 	diskCmd.Flags().StringVar(opts.ExporterSelector, "exporter", "", "direct exporter selector for flash (alternative to --target lookup)")
+	addDeviceTestFlags(diskCmd, opts)
 	// Secure build
 	diskCmd.Flags().BoolVar(opts.SecureBuild, "secure", false, "resolve tasks from signed Tekton Bundle (requires OperatorConfig taskBundleRef)")
 	diskCmd.Flags().StringVar(opts.TTL, "ttl", "", "time-to-live for the build (e.g. 24h, 72h, 168h); empty=server default, 0=no expiry")
+	diskCmd.Flags().StringVar(opts.Priority, "priority", "", "build priority class defined in OperatorConfig (e.g. release); empty=default class")
+	diskCmd.Flags().IntVar(opts.MaxAttempts, "max-attempts", 0, "retry the failed stage of builds that fail with a transient mirror or registry error, up to this many attempts in total; 0=no retries")
+	diskCmd.Flags().BoolVar(opts.Notify, "notify", false, "notify the OperatorConfig notification targets about this build's lifecycle")
+	diskCmd.Flags().StringVar(opts.SigningKeySecret, "signing-key-secret", "", "Secret holding the cosign key (cosign.key, cosign.password) that signs the pushed artifacts; empty=OperatorConfig signing key")
 	diskCmd.Flags().StringVar(opts.TaskBundleRef, "task-bundle-ref", "", "digest-pinned Tekton bundle ref for reproducible rebuild (e.g. quay.io/org/tasks@sha256:abc...)")
 	// Internal registry options
 	diskCmd.Flags().BoolVar(opts.UseInternalRegistry, "internal-registry", false, "push to OpenShift internal registry")
@@ -248,6 +303,7 @@ func NewImageCmd(opts Options) *cobra.Command {
 	buildDevCmd.Flags().StringVarP(opts.OutputDir, "output", "o", "", "download artifact to file from registry (requires --push)")
 	buildDevCmd.Flags().StringVar(opts.CompressionAlgo, "compress", "gzip", "compression algorithm (gzip, lz4, xz)")
 	buildDevCmd.Flags().StringVar(opts.ExportOCI, "push", "", "push disk image as OCI artifact to registry")
+	addExportToFlags(buildDevCmd, opts)
 	buildDevCmd.Flags().StringVar(
 		opts.RegistryAuthFile,
 		"registry-auth-file",
@@ -274,9 +330,14 @@ func NewImageCmd(opts Options) *cobra.Command {
 	buildDevCmd.Flags().StringVar(opts.LeaseName, "lease", "", "existing Jumpstarter lease name (mutually exclusive with --lease-duration)")
 	buildDevCmd.Flags().StringVar(opts.FlashCmd, "flash-cmd", "", "override flash command (default: from OperatorConfig target mapping)")
 	buildDevCmd.Flags().StringVar(opts.ExporterSelector, "exporter", "", "direct exporter selector for flash (alternative to --target lookup)")
+	addDeviceTestFlags(buildDevCmd, opts)
 	// Secure build
 	buildDevCmd.Flags().BoolVar(opts.SecureBuild, "secure", false, "resolve tasks from signed Tekton Bundle (requires OperatorConfig taskBundleRef)")
 	buildDevCmd.Flags().StringVar(opts.TTL, "ttl", "", "time-to-live for the build (e.g. 24h, 72h, 168h); empty=server default, 0=no expiry")
+	buildDevCmd.Flags().StringVar(opts.Priority, "priority", "", "build priority class defined in OperatorConfig (e.g. release); empty=default class")
+	buildDevCmd.Flags().IntVar(opts.MaxAttempts, "max-attempts", 0, "retry the failed stage of builds that fail with a transient mirror or registry error, up to this many attempts in total; 0=no retries")
+	buildDevCmd.Flags().BoolVar(opts.Notify, "notify", false, "notify the OperatorConfig notification targets about this build's lifecycle")
+	buildDevCmd.Flags().StringVar(opts.SigningKeySecret, "signing-key-secret", "", "Secret holding the cosign key (cosign.key, cosign.password) that signs the pushed artifacts; empty=OperatorConfig signing key")
 	// Reproducible build
 	buildDevCmd.Flags().BoolVar(opts.Reproducible, "reproducible", false, "save RPMs, manifest, and task bundle for future reproduction (requires --secure)")
 	buildDevCmd.Flags().StringVar(opts.TaskBundleRef, "task-bundle-ref", "", "digest-pinned Tekton bundle ref for reproducible rebuild (e.g. quay.io/org/tasks@sha256:abc...)")
@@ -295,6 +356,7 @@ func NewImageCmd(opts Options) *cobra.Command {
 	downloadCmd.Flags().StringVar(opts.ServerURL, "server", defaultServer, "REST API server base URL")
 	downloadCmd.Flags().StringVar(opts.AuthToken, "token", os.Getenv("CAIB_TOKEN"), "Bearer token for authentication")
 	downloadCmd.Flags().StringVarP(opts.OutputDir, "output", "o", "", "destination file or directory for the artifact")
+	downloadCmd.Flags().BoolVar(opts.DownloadSBOM, "sbom", false, "download the SPDX and CycloneDX SBOMs of the disk image to the output directory instead of the disk image")
 
 	// token command flags
 	tokenCmd.Flags().StringVar(opts.ServerURL, "server", defaultServer, "REST API server base URL")
@@ -308,6 +370,29 @@ func NewImageCmd(opts Options) *cobra.Command {
 	cancelCmd.Flags().StringVar(opts.ServerURL, "server", defaultServer, "REST API server base URL")
 	cancelCmd.Flags().StringVar(opts.AuthToken, "token", os.Getenv("CAIB_TOKEN"), "Bearer token for authentication")
 
+	// rebuild command flags (only flags that are set override the parent build)
+	rebuildCmd.Flags().StringVar(opts.ServerURL, "server", defaultServer, "REST API server base URL")
+	rebuildCmd.Flags().StringVar(opts.AuthToken, "token", os.Getenv("CAIB_TOKEN"), "Bearer token for authentication")
+	rebuildCmd.Flags().StringVarP(opts.BuildName, "name", "n", "", "name for the new ImageBuild (default: parent build name)")
+	rebuildCmd.Flags().StringVarP(opts.Target, "target", "t", "", "override the target platform")
+	rebuildCmd.Flags().StringVarP(opts.Architecture, "arch", "a", opts.GetDefaultArch(), "override the architecture (amd64, arm64)")
+	rebuildCmd.Flags().StringVar(
+		opts.AutomotiveImageBuilder, "aib-image",
+		automotivev1alpha1.DefaultAutomotiveImageBuilderImage, "override the AIB container image",
+	)
+	rebuildCmd.Flags().StringArrayVarP(opts.CustomDefs, "define", "D", []string{}, "custom definition KEY=VALUE, replacing the parent's value for KEY")
+	rebuildCmd.Flags().StringArrayVar(opts.DefineFiles, "define-file", []string{}, "load defines from YAML dictionary file (can be repeated)")
+	rebuildCmd.Flags().StringVar(opts.TTL, "ttl", "", "override the time-to-live for the build (e.g. 24h, 72h, 168h); 0=no expiry")
+	rebuildCmd.Flags().StringVar(
+		opts.RegistryAuthFile,
+		"registry-auth-file",
+		"",
+		"path to Docker/Podman auth file for push authentication (takes precedence over env vars and auto-discovery)",
+	)
+	rebuildCmd.Flags().IntVar(opts.Timeout, "timeout", 60, "timeout in minutes")
+	rebuildCmd.Flags().BoolVarP(opts.WaitForBuild, "wait", "w", true, "wait for build to complete")
+	rebuildCmd.Flags().BoolVarP(opts.FollowLogs, "follow", "f", false, "follow build logs (shows full log output instead of progress bar)")
+
 	// flash command flags
 	flashCmd.Flags().StringVar(opts.ServerURL, "server", defaultServer, "REST API server base URL")
 	flashCmd.Flags().StringVar(opts.AuthToken, "token", os.Getenv("CAIB_TOKEN"), "Bearer token for authentication")
@@ -318,6 +403,12 @@ func NewImageCmd(opts Options) *cobra.Command {
 	flashCmd.Flags().StringVar(opts.LeaseDuration, "lease-duration", "03:00:00", "device lease duration (HH:MM:SS)")
 	flashCmd.Flags().StringVar(opts.LeaseName, "lease", "", "existing Jumpstarter lease name (mutually exclusive with --lease-duration)")
 	flashCmd.Flags().StringVar(opts.FlashCmd, "flash-cmd", "", "override flash command (default: from OperatorConfig target mapping)")
+	addDeviceTestFlags(flashCmd, opts)
+	flashCmd.Flags().IntVar(opts.FleetBoards, "boards", 0, "flash this many boards matching the target or exporter, each under its own lease")
+	flashCmd.Flags().BoolVar(opts.FleetAllBoards, "all-boards", false, "flash every online exporter matching the target or exporter")
+	flashCmd.Flags().IntVar(opts.FleetMaxParallel, "max-parallel", 0, "flash at most this many boards at the same time (default: all at once)")
+	flashCmd.Flags().BoolVar(opts.CaptureConsole, "console", false, "record the device serial console after flashing and stream it instead of the flash logs")
+	flashCmd.Flags().StringVar(opts.ConsoleDuration, "console-duration", "", "how long to record the serial console (default: 5m)")
 	flashCmd.Flags().StringVar(
 		opts.RegistryAuthFile,
 		"registry-auth-file",
@@ -333,7 +424,17 @@ func NewImageCmd(opts Options) *cobra.Command {
 		"",
 		"path to Docker/Podman auth file for registry authentication",
 	)
-	inspectCmd.Flags().StringVarP(opts.OutputDir, "output-dir", "o", "", "download referrer artifacts (manifest, RPMs) to this directory")
+	inspectCmd.Flags().StringVarP(opts.OutputDir, "output-dir", "o", "", "download referrer artifacts (manifests, RPMs, SBOMs) to this directory")
+	verifyCmd := newVerifyCmd(opts)
+	verifyCmd.Flags().StringVar(opts.ServerURL, "server", defaultServer, "REST API server base URL, used to fetch the signing public key")
+	verifyCmd.Flags().StringVar(opts.AuthToken, "token", os.Getenv("CAIB_TOKEN"), "Bearer token for authentication")
+	verifyCmd.Flags().StringVar(opts.VerifyKeyFile, "key", "", "path to the cosign public key (PEM); defaults to the signing key configured on the server")
+	verifyCmd.Flags().StringVar(
+		opts.RegistryAuthFile,
+		"registry-auth-file",
+		"",
+		"path to Docker/Podman auth file for registry authentication",
+	)
 
 	// Sealed operation shared flags
 	addSealedFlags(prepareResealCmd, opts, defaultServer)
@@ -348,13 +449,16 @@ func NewImageCmd(opts Options) *cobra.Command {
 		buildDevCmd,
 		listCmd,
 		showCmd,
+		diffCmd,
 		downloadCmd,
 		logsCmd,
 		tokenCmd,
 		deleteCmd,
 		cancelCmd,
+		rebuildCmd,
 		flashCmd,
 		inspectCmd,
+		verifyCmd,
 		prepareResealCmd,
 		resealCmd,
 		extractForSigningCmd,
@@ -472,6 +576,30 @@ Examples:
 	}
 }
 
+func newDiffCmd(opts Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <from-build> <to-build>",
+		Short: "Show what changed between two ImageBuilds",
+		Long: `Diff compares two ImageBuilds: their AIB manifests, custom defines,
+AIB and builder images, task bundle refs and build parameters. If one build
+was rebuilt from the other, the lineage between them is shown.
+
+When both builds pushed a disk image with an osbuild manifest referrer (as
+reproducible builds do), the RPM package lists are compared as well. The
+referrers are read with the credentials from the environment or
+--registry-auth-file.
+
+Examples:
+  # Compare the last good nightly with the one that regressed
+  caib image diff nightly-ab12c nightly-de34f
+
+  # Compare as JSON
+  caib image diff nightly-ab12c nightly-de34f --output-format json`,
+		Args: cobra.ExactArgs(2),
+		Run:  opts.RunDiff,
+	}
+}
+
 func newDownloadCmd(opts Options) *cobra.Command {
 	return &cobra.Command{
 		Use:   "download <build-name>",
@@ -482,12 +610,18 @@ The build must have pushed a disk image to an OCI registry (via --push-disk
 or --push on disk/build-dev commands). The artifact is pulled from the
 registry to a local file.
 
+With --sbom, the SPDX and CycloneDX SBOMs attached to the disk image as OCI
+referrers are downloaded to the output directory instead.
+
 Examples:
   # Download disk image from a completed build
   caib image download my-build -o ./disk.qcow2
 
   # Download to a directory (multi-layer artifacts extract here)
-  caib image download my-build -o ./output/`,
+  caib image download my-build -o ./output/
+
+  # Download the SBOMs of the disk image
+  caib image download my-build --sbom -o ./sbom/`,
 		Args: cobra.ExactArgs(1),
 		Run:  opts.RunDownload,
 	}
@@ -582,6 +716,32 @@ Examples:
 	}
 }
 
+func newRebuildCmd(opts Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rebuild <build-name>",
+		Short: "Rebuild an existing build with optional overrides",
+		Long: `Rebuild creates a new ImageBuild from the inputs of an existing build.
+
+The manifest, build settings and push destinations of the parent build are
+reused. Defines, target, architecture, AIB image and TTL can be overridden.
+Local files referenced by the manifest are uploaded again from the caib cache,
+or from the working directory if they were not cached.
+
+Registry credentials are not kept after a build, so they are resolved again
+from the environment or --registry-auth-file. The new build is linked to its
+parent and shown as "Parent Build" in 'caib image show'.
+
+Examples:
+  # Rebuild a build as-is
+  caib image rebuild my-build-ab12c
+
+  # Rebuild with a different define and target
+  caib image rebuild my-build-ab12c -D debug=true --target ridesx4`,
+		Args: cobra.ExactArgs(1),
+		Run:  opts.RunRebuild,
+	}
+}
+
 func newInspectCmd(opts Options) *cobra.Command {
 	return &cobra.Command{
 		Use:   "inspect <oci-registry-reference>",
@@ -591,7 +751,8 @@ build provenance information: distro, target, architecture, builder versions,
 and the exact command to reproduce the build.
 
 If --output-dir is given, referrer artifacts (AIB manifest, RPM archive,
-osbuild manifest) are downloaded to the specified directory.
+osbuild manifest, SPDX and CycloneDX SBOMs) are downloaded to the specified
+directory.
 
 Examples:
   # Show build provenance
@@ -604,6 +765,27 @@ Examples:
 	}
 }
 
+func newVerifyCmd(opts Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify <oci-registry-reference>",
+		Short: "Verify the cosign signature and SLSA provenance of a build artifact",
+		Long: `Verify checks the cosign signature of a bootc container or disk artifact
+pushed by a signed build, and the signed SLSA provenance attached to it.
+
+The public key is read from --key, or else fetched from the Build API when the
+OperatorConfig sets osBuilds.signing.publicKeyRef.
+
+Examples:
+  # Verify with the signing key configured on the server
+  caib image verify quay.io/org/my-os:v1
+
+  # Verify a disk artifact with a local public key
+  caib image verify quay.io/org/my-disk:v1 --key cosign.pub`,
+		Args: cobra.ExactArgs(1),
+		Run:  opts.RunVerify,
+	}
+}
+
 func newPrepareResealCmd(opts Options) *cobra.Command {
 	return &cobra.Command{
 		Use:   "prepare-reseal [source-container] [output-container]",
@@ -688,3 +870,28 @@ func addSealedFlags(cmd *cobra.Command, opts Options, defaultServer string) {
 	cmd.Flags().StringVar(opts.SealedKeyPassword, "passwd", "", "Password for encrypted key file (used with --key)")
 	cmd.Flags().IntVar(opts.Timeout, "timeout", 120, "Timeout in minutes")
 }
+
+// addExportToFlags registers the flags for exporting the disk image to s3, http or pvc storage.
+func addExportToFlags(cmd *cobra.Command, opts Options) {
+	cmd.Flags().StringVar(
+		opts.ExportTo, "export-to", "",
+		"export disk image to s3://bucket/key, http(s)://URL or pvc://claim/path instead of a registry "+
+			"(credentials from AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY or CAIB_EXPORT_TOKEN)",
+	)
+	cmd.Flags().StringVar(opts.S3Endpoint, "s3-endpoint", "", "S3-compatible service endpoint for s3:// export targets")
+	cmd.Flags().StringVar(opts.S3Region, "s3-region", "", "S3 signing region (default: us-east-1)")
+	cmd.Flags().StringVar(opts.ExportSecret, "export-secret", "", "existing secret with export credentials in the build namespace")
+}
+
+// addDeviceTestFlags registers the flags for on-device tests run after flashing.
+func addDeviceTestFlags(cmd *cobra.Command, opts Options) {
+	cmd.Flags().StringVar(
+		opts.TestScript, "test-script", "",
+		"script run against the device after flashing; it should write JUnit XML to $TEST_RESULTS_DIR",
+	)
+	cmd.Flags().StringVar(opts.TestImage, "test-image", "", "container image the test script runs in (default: Jumpstarter image)")
+	cmd.Flags().StringVar(opts.BootCheck, "boot-check", "", "how to detect a successful boot before testing: console or ssh (default: console)")
+	cmd.Flags().StringVar(opts.BootMarker, "boot-marker", "", "regular expression awaited on the serial console (default: login:)")
+	cmd.Flags().StringVar(opts.BootTimeout, "boot-timeout", "", "how long to wait for the device to boot (default: 10m)")
+	cmd.Flags().BoolVar(opts.NoPowerCycle, "no-power-cycle", false, "do not power-cycle the device before the boot check")
+}
@@ -3,10 +3,8 @@ package inspectcmd
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -20,9 +18,6 @@ import (
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
-	"oras.land/oras-go/v2/registry/remote"
-	"oras.land/oras-go/v2/registry/remote/auth"
-	"oras.land/oras-go/v2/registry/remote/credentials"
 
 	caibcommon "github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/common"
 	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/registryauth"
@@ -220,46 +215,11 @@ func discoverReferrers(ociRef, digest string, sysCtx *types.SystemContext) ([]re
 		return nil, fmt.Errorf("could not parse repository from %s", ociRef)
 	}
 
-	repo, err := remote.NewRepository(repoName)
+	repo, err := caibcommon.NewOCIRepository(repoName, sysCtx)
 	if err != nil {
-		return nil, fmt.Errorf("parse repository: %w", err)
+		return nil, err
 	}
 
-	authClient := &auth.Client{}
-	if sysCtx.DockerAuthConfig != nil && sysCtx.DockerAuthConfig.Username != "" {
-		authClient.Credential = auth.StaticCredential(repo.Reference.Host(), auth.Credential{
-			Username: sysCtx.DockerAuthConfig.Username,
-			Password: sysCtx.DockerAuthConfig.Password,
-		})
-	} else {
-		authFilePath := sysCtx.AuthFilePath
-		if authFilePath == "" {
-			for _, candidate := range registryauth.FileCandidates() {
-				if _, err := os.Stat(candidate); err == nil {
-					authFilePath = candidate
-					break
-				}
-			}
-		}
-		if authFilePath != "" {
-			store, err := credentials.NewFileStore(authFilePath)
-			if err != nil {
-				return nil, fmt.Errorf("load auth file %s: %w", authFilePath, err)
-			}
-			authClient.Credential = credentials.Credential(store)
-		}
-	}
-	if sysCtx.DockerInsecureSkipTLSVerify == types.OptionalBoolTrue {
-		authClient.Client = &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: true, //nolint:gosec // user explicitly opted in
-				},
-			},
-		}
-	}
-	repo.Client = authClient
-
 	dgst, err := godigest.Parse(digest)
 	if err != nil {
 		return nil, fmt.Errorf("parse digest: %w", err)
@@ -340,3 +340,21 @@ func TestPrintProvenance_NoAnnotations(t *testing.T) {
 		t.Error("should show no-annotations message")
 	}
 }
+
+func TestPrintProvenance_SBOMReferrers(t *testing.T) {
+	h := NewHandler(Options{})
+	referrerTypes := map[string]bool{
+		"application/spdx+json": true,
+	}
+
+	out := captureStdout(t, func() {
+		h.printProvenance("ref", "dig", map[string]string{}, nil, referrerTypes)
+	})
+
+	if !strings.Contains(out, "✓ SPDX SBOM  (application/spdx+json)") {
+		t.Errorf("should list the attached SPDX SBOM, got: %s", out)
+	}
+	if !strings.Contains(out, "✗ CycloneDX SBOM") {
+		t.Errorf("should list the missing CycloneDX SBOM, got: %s", out)
+	}
+}
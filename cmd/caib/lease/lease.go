@@ -0,0 +1,314 @@
+// Package lease implements CLI commands for managing the Jumpstarter leases
+// held by builds, flash jobs and workspaces.
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/clilog"
+	caibcommon "github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/common"
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/config"
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+	buildapiclient "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi/client"
+)
+
+var (
+	serverURL       string
+	authToken       string
+	insecureSkipTLS bool
+
+	// list flags
+	showAll bool
+
+	// extend flags
+	extendBy string
+
+	// attach flags
+	clientConfigFile string
+)
+
+// NewLeaseCmd creates the lease command with subcommands.
+func NewLeaseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lease",
+		Short: "Manage the Jumpstarter leases held by your builds, flashes and workspaces",
+		Long: `List, extend, release and attach to the Jumpstarter leases acquired for your
+builds, flash jobs and workspaces.
+
+Leases acquired by the operator are released automatically once the build,
+flash job or workspace holding them is deleted.
+
+Examples:
+  # Show your active leases and what holds them
+  caib lease list
+
+  # Keep a board for another hour
+  caib lease extend lease-abc123 --by 1h
+
+  # Open a Jumpstarter shell on the leased board
+  caib lease attach lease-abc123
+
+  # Give the board back
+  caib lease release lease-abc123`,
+	}
+
+	cmd.PersistentFlags().StringVar(&serverURL, "server", config.DefaultServer(), "REST API server base URL")
+	cmd.PersistentFlags().StringVar(&authToken, "token", os.Getenv("CAIB_TOKEN"), "Bearer token for authentication")
+	cmd.PersistentFlags().BoolVar(&insecureSkipTLS, "insecure-skip-tls-verify", false, "skip TLS certificate verification")
+
+	cmd.AddCommand(
+		newListCmd(),
+		newExtendCmd(),
+		newReleaseCmd(),
+		newAttachCmd(),
+	)
+
+	return cmd
+}
+
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List your leases",
+		Args:  cobra.NoArgs,
+		Run:   runList,
+	}
+	cmd.Flags().BoolVar(&showAll, "all", false, "include ended and released leases")
+	return cmd
+}
+
+func newExtendCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "extend <lease>",
+		Short: "Extend a lease",
+		Long: `Extend a lease by the given duration.
+
+Examples:
+  caib lease extend lease-abc123 --by 30m`,
+		Args: cobra.ExactArgs(1),
+		Run:  runExtend,
+	}
+	cmd.Flags().StringVar(&extendBy, "by", "1h", "duration to add to the lease (e.g. 30m, 2h)")
+	return cmd
+}
+
+func newReleaseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "release <lease>",
+		Short: "Release a lease and free its board",
+		Args:  cobra.ExactArgs(1),
+		Run:   runRelease,
+	}
+}
+
+func newAttachCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "attach <lease> [-- command...]",
+		Short: "Open a Jumpstarter shell on a leased board",
+		Long: `Run jmp shell on the board held by a lease, using your Jumpstarter client config.
+The jmp CLI must be installed locally.
+
+Examples:
+  # Interactive shell
+  caib lease attach lease-abc123
+
+  # Run a single command
+  caib lease attach lease-abc123 -- j power cycle`,
+		Args: cobra.MinimumNArgs(1),
+		Run:  runAttach,
+	}
+	cmd.Flags().StringVar(&clientConfigFile, "client", "", "path to Jumpstarter client config file (default: current jmp client)")
+	return cmd
+}
+
+func runList(_ *cobra.Command, _ []string) {
+	requireServer()
+
+	var leases []buildapitypes.LeaseResponse
+	err := caibcommon.ExecuteWithReauth(serverURL, &authToken, insecureSkipTLS, func(client *buildapiclient.Client) error {
+		l, cerr := client.ListLeases(context.Background(), showAll)
+		if cerr != nil {
+			return cerr
+		}
+		leases = l
+		return nil
+	})
+	if err != nil {
+		handleError(fmt.Errorf("failed to list leases: %w", err))
+	}
+
+	if len(leases) == 0 {
+		fmt.Println("No leases found")
+		return
+	}
+	if err := writeLeaseTable(os.Stdout, leases, time.Now()); err != nil {
+		handleError(err)
+	}
+}
+
+func runExtend(_ *cobra.Command, args []string) {
+	requireServer()
+	name := args[0]
+
+	var lease *buildapitypes.LeaseResponse
+	err := caibcommon.ExecuteWithReauth(serverURL, &authToken, insecureSkipTLS, func(client *buildapiclient.Client) error {
+		l, cerr := client.ExtendLease(context.Background(), name, extendBy)
+		if cerr != nil {
+			return cerr
+		}
+		lease = l
+		return nil
+	})
+	if err != nil {
+		handleError(fmt.Errorf("failed to extend lease: %w", err))
+	}
+
+	clilog.Infof("Lease %q extended by %s (expires %s)\n", lease.Name, extendBy, formatExpiry(lease, time.Now()))
+}
+
+func runRelease(_ *cobra.Command, args []string) {
+	requireServer()
+	name := args[0]
+
+	err := caibcommon.ExecuteWithReauth(serverURL, &authToken, insecureSkipTLS, func(client *buildapiclient.Client) error {
+		_, cerr := client.ReleaseLease(context.Background(), name)
+		return cerr
+	})
+	if err != nil {
+		handleError(fmt.Errorf("failed to release lease: %w", err))
+	}
+
+	clilog.Infof("Lease %q released\n", name)
+}
+
+func runAttach(cmd *cobra.Command, args []string) {
+	requireServer()
+	name := args[0]
+	var command []string
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		command = args[dash:]
+	}
+
+	var lease *buildapitypes.LeaseResponse
+	err := caibcommon.ExecuteWithReauth(serverURL, &authToken, insecureSkipTLS, func(client *buildapiclient.Client) error {
+		l, cerr := client.GetLease(context.Background(), name)
+		if cerr != nil {
+			return cerr
+		}
+		lease = l
+		return nil
+	})
+	if err != nil {
+		handleError(fmt.Errorf("failed to get lease: %w", err))
+	}
+	if !lease.Active {
+		handleError(fmt.Errorf("lease %q has ended", name))
+	}
+
+	clientInfo, err := caibcommon.ResolveJumpstarterClient(strings.TrimSpace(clientConfigFile))
+	if err != nil {
+		handleError(err)
+	}
+	jmpPath, err := exec.LookPath("jmp")
+	if err != nil {
+		handleError(fmt.Errorf("jmp CLI not found in PATH, install Jumpstarter to attach to leases: %w", err))
+	}
+
+	clilog.Infof("Attaching to lease %q on %s with Jumpstarter client %q\n", name, valueOrDash(lease.Exporter), clientInfo.Name)
+	shell := exec.Command(jmpPath, attachArgs(clientInfo.Path, name, command)...)
+	shell.Stdin, shell.Stdout, shell.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := shell.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		handleError(fmt.Errorf("jmp shell failed: %w", err))
+	}
+}
+
+// attachArgs returns the jmp arguments that open a shell on a lease,
+// optionally running a single command.
+func attachArgs(clientConfigPath, lease string, command []string) []string {
+	args := []string{"shell", "--client-config", clientConfigPath, "--lease", lease}
+	if len(command) > 0 {
+		args = append(append(args, "--"), command...)
+	}
+	return args
+}
+
+// writeLeaseTable renders leases with the resources holding them.
+func writeLeaseTable(out io.Writer, leases []buildapitypes.LeaseResponse, now time.Time) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "LEASE\tEXPORTER\tEXPIRES\tOWNERS"); err != nil {
+		return err
+	}
+	for i := range leases {
+		lease := &leases[i]
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", lease.Name, valueOrDash(lease.Exporter),
+			formatExpiry(lease, now), formatOwners(lease.Owners)); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// formatExpiry describes when a lease ends relative to now.
+func formatExpiry(lease *buildapitypes.LeaseResponse, now time.Time) string {
+	switch {
+	case lease.Released:
+		return "released"
+	case !lease.Active:
+		return "ended"
+	case lease.ExpiresAt == "":
+		return "waiting for exporter"
+	}
+	expires, err := time.Parse(time.RFC3339, lease.ExpiresAt)
+	if err != nil {
+		return lease.ExpiresAt
+	}
+	remaining := expires.Sub(now)
+	if remaining <= 0 {
+		return "expiring"
+	}
+	return "in " + remaining.Round(time.Minute).String()
+}
+
+// formatOwners lists the resources holding a lease as kind/name.
+func formatOwners(owners []buildapitypes.LeaseOwner) string {
+	if len(owners) == 0 {
+		return "-"
+	}
+	parts := make([]string, 0, len(owners))
+	for _, owner := range owners {
+		parts = append(parts, strings.ToLower(owner.Kind)+"/"+owner.Name)
+	}
+	return strings.Join(parts, ",")
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func requireServer() {
+	if serverURL == "" {
+		handleError(caibcommon.ServerURLRequiredError("caib lease --server <server-url>"))
+	}
+}
+
+func handleError(err error) {
+	fmt.Fprintln(os.Stderr, caibcommon.FormatError(err))
+	os.Exit(1)
+}
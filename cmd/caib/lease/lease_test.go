@@ -0,0 +1,68 @@
+package lease
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+)
+
+func TestAttachArgs(t *testing.T) {
+	got := attachArgs("/home/me/client.yaml", "lease-1", nil)
+	want := []string{"shell", "--client-config", "/home/me/client.yaml", "--lease", "lease-1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("attachArgs() = %v, want %v", got, want)
+	}
+
+	got = attachArgs("/home/me/client.yaml", "lease-1", []string{"j", "power", "cycle"})
+	want = append(want, "--", "j", "power", "cycle")
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("attachArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestFormatExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		lease buildapitypes.LeaseResponse
+		want  string
+	}{
+		{lease: buildapitypes.LeaseResponse{Active: true, ExpiresAt: "2026-01-01T11:30:00Z"}, want: "in 1h30m0s"},
+		{lease: buildapitypes.LeaseResponse{Active: true, ExpiresAt: "2026-01-01T09:00:00Z"}, want: "expiring"},
+		{lease: buildapitypes.LeaseResponse{Active: true}, want: "waiting for exporter"},
+		{lease: buildapitypes.LeaseResponse{}, want: "ended"},
+		{lease: buildapitypes.LeaseResponse{Released: true}, want: "released"},
+	} {
+		if got := formatExpiry(&tt.lease, now); got != tt.want {
+			t.Errorf("formatExpiry(%+v) = %q, want %q", tt.lease, got, tt.want)
+		}
+	}
+}
+
+func TestWriteLeaseTable(t *testing.T) {
+	var out bytes.Buffer
+	err := writeLeaseTable(&out, []buildapitypes.LeaseResponse{
+		{
+			Name: "lease-1", Exporter: "rack-01", Active: true, ExpiresAt: "2026-01-01T11:00:00Z",
+			Owners: []buildapitypes.LeaseOwner{{Kind: "FlashJob", Name: "flash-1"}, {Kind: "Workspace", Name: "my-app"}},
+		},
+		{Name: "lease-2", Active: true},
+	}, time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "LEASE") {
+		t.Fatalf("expected header and 2 rows, got:\n%s", out.String())
+	}
+	if !strings.Contains(lines[1], "flashjob/flash-1,workspace/my-app") || !strings.Contains(lines[1], "in 1h0m0s") {
+		t.Errorf("unexpected row %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "waiting for exporter") {
+		t.Errorf("unexpected row %q", lines[2])
+	}
+}
@@ -57,11 +57,34 @@ var (
 	leaseName         string
 	flashCmdOverride  string
 
+	// Device test options
+	testScript   string
+	testImage    string
+	bootCheck    string
+	bootMarker   string
+	bootTimeout  string
+	noPowerCycle bool
+
+	// Fleet flash options
+	fleetBoards      int
+	fleetAllBoards   bool
+	fleetMaxParallel int
+
+	// Serial console options
+	captureConsole  bool
+	consoleDuration string
+
 	// Internal registry options
 	useInternalRegistry       bool
 	internalRegistryImageName string
 	internalRegistryTag       string
 
+	// Storage export options
+	exportTo     string
+	s3Endpoint   string
+	s3Region     string
+	exportSecret string
+
 	// Secure build
 	secureBuild bool
 
@@ -73,9 +96,28 @@ var (
 	// Build TTL
 	buildTTL string
 
+	// Build priority class
+	buildPriority string
+
+	// Build retry policy
+	buildMaxAttempts int
+
+	// Download SBOMs instead of the disk image
+	downloadSBOM bool
+
+	// Opt in to build notifications
+	buildNotify bool
+
+	// Artifact signing
+	signingKeySecret string
+	verifyKeyFile    string
+
 	// Output options
 	quiet bool
 
+	// Project (tenant namespace) of Build API requests
+	project string
+
 	// TLS options
 	insecureSkipTLS bool
 
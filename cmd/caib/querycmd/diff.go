@@ -0,0 +1,244 @@
+package querycmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/containers/image/v5/types"
+	"github.com/spf13/cobra"
+
+	common "github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/common"
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/registryauth"
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+	buildapiclient "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi/client"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/oci"
+)
+
+// RunDiff handles `caib image diff`.
+func (h *Handler) RunDiff(_ *cobra.Command, args []string) {
+	format, err := h.resolveOutputFormat()
+	if err != nil {
+		h.handleError(err)
+		return
+	}
+
+	ctx := context.Background()
+	fromName, toName := args[0], args[1]
+
+	if h.opts.ServerURL == nil || strings.TrimSpace(*h.opts.ServerURL) == "" {
+		h.handleError(fmt.Errorf("server URL required (use --server, CAIB_SERVER, run 'caib login <server-url>' or 'jmp login <endpoint>')"))
+		return
+	}
+	if h.opts.InsecureSkipTLS == nil {
+		h.handleError(fmt.Errorf("internal error: --insecure option is not configured"))
+		return
+	}
+	serverURL := strings.TrimSpace(*h.opts.ServerURL)
+
+	var diff *buildapitypes.BuildDiffResponse
+	err = common.ExecuteWithReauth(serverURL, h.opts.AuthToken, *h.opts.InsecureSkipTLS, func(api *buildapiclient.Client) error {
+		var diffErr error
+		diff, diffErr = api.DiffBuilds(ctx, fromName, toName)
+		return diffErr
+	})
+	if err != nil {
+		h.handleError(fmt.Errorf("error comparing ImageBuilds %s and %s: %w", fromName, toName, err))
+		return
+	}
+
+	if err := h.addPackageDiff(ctx, diff); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: package lists not compared: %v\n", err)
+	}
+
+	h.renderFormatted(format, diff, func() error { return printBuildDiff(diff) })
+}
+
+// addPackageDiff compares the RPMs listed in the osbuild manifest referrers of
+// both disk artifacts. The API server cannot read them, as registry
+// credentials are not kept after a build.
+func (h *Handler) addPackageDiff(ctx context.Context, diff *buildapitypes.BuildDiffResponse) error {
+	if diff.From.DiskArtifact == "" || diff.To.DiskArtifact == "" {
+		if diff.From.Reproducible && diff.To.Reproducible {
+			return fmt.Errorf("disk artifact digests are not recorded for both builds")
+		}
+		return nil
+	}
+
+	fromPackages, err := h.packageVersions(ctx, diff.From.DiskArtifact)
+	if err != nil {
+		return fmt.Errorf("%s: %w", diff.From.Name, err)
+	}
+	toPackages, err := h.packageVersions(ctx, diff.To.DiskArtifact)
+	if err != nil {
+		return fmt.Errorf("%s: %w", diff.To.Name, err)
+	}
+	if fromPackages == nil || toPackages == nil {
+		if diff.From.Reproducible && diff.To.Reproducible {
+			return fmt.Errorf("osbuild manifest referrer not found")
+		}
+		return nil
+	}
+	diff.Packages = buildapitypes.DiffValues(fromPackages, toPackages)
+	return nil
+}
+
+// packageVersions returns the RPM versions of a digest-pinned disk artifact,
+// keyed by name.arch, or nil if it has no osbuild manifest referrer.
+func (h *Handler) packageVersions(ctx context.Context, ref string) (map[string]string, error) {
+	repoName, digest, ok := strings.Cut(ref, "@")
+	if !ok {
+		return nil, fmt.Errorf("disk artifact %s is not pinned to a digest", ref)
+	}
+
+	sysCtx := &types.SystemContext{}
+	if h.opts.InsecureSkipTLS != nil && *h.opts.InsecureSkipTLS {
+		sysCtx.DockerInsecureSkipTLSVerify = types.OptionalBoolTrue
+	}
+	if h.opts.RegistryAuthFile != nil {
+		sysCtx.AuthFilePath = *h.opts.RegistryAuthFile
+	}
+	if _, username, password := registryauth.ExtractRegistryCredentials(ref, ""); username != "" && password != "" {
+		sysCtx.DockerAuthConfig = &types.DockerAuthConfig{Username: username, Password: password}
+	}
+
+	repo, err := common.NewOCIRepository(repoName, sysCtx)
+	if err != nil {
+		return nil, err
+	}
+	data, err := common.FetchOCIReferrer(ctx, repo, digest, oci.Get().ReferrerArtifactTypeByLabel("osbuild Manifest"))
+	if err != nil || data == nil {
+		return nil, err
+	}
+	return osbuildPackages(data)
+}
+
+// osbuildPackages lists the RPMs downloaded by an osbuild manifest, mapping
+// name.arch to version-release.
+func osbuildPackages(data []byte) (map[string]string, error) {
+	var manifest struct {
+		Sources map[string]struct {
+			Items map[string]json.RawMessage `json:"items"`
+		} `json:"sources"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parse osbuild manifest: %w", err)
+	}
+
+	packages := make(map[string]string)
+	for _, source := range manifest.Sources {
+		for _, raw := range source.Items {
+			// Items are either a URL or an object with a url (curl) or path (librepo).
+			var location string
+			if err := json.Unmarshal(raw, &location); err != nil {
+				var item struct {
+					URL  string `json:"url"`
+					Path string `json:"path"`
+				}
+				if err := json.Unmarshal(raw, &item); err != nil {
+					continue
+				}
+				location = item.URL
+				if location == "" {
+					location = item.Path
+				}
+			}
+			location, _, _ = strings.Cut(location, "?")
+			file, err := url.PathUnescape(path.Base(location))
+			if err != nil || !strings.HasSuffix(file, ".rpm") {
+				continue
+			}
+			if name, version, ok := splitRPMFilename(file); ok {
+				packages[name] = version
+			}
+		}
+	}
+	return packages, nil
+}
+
+// splitRPMFilename splits name-version-release.arch.rpm into name.arch and
+// version-release.
+func splitRPMFilename(file string) (string, string, bool) {
+	nvra := strings.TrimSuffix(file, ".rpm")
+	dot := strings.LastIndex(nvra, ".")
+	if dot < 0 {
+		return "", "", false
+	}
+	nvr, arch := nvra[:dot], nvra[dot+1:]
+	release := strings.LastIndex(nvr, "-")
+	if release < 0 {
+		return "", "", false
+	}
+	version := strings.LastIndex(nvr[:release], "-")
+	if version <= 0 {
+		return "", "", false
+	}
+	return nvr[:version] + "." + arch, nvr[version+1:], true
+}
+
+func printBuildDiff(diff *buildapitypes.BuildDiffResponse) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	rows := [][2]string{
+		{"From", fmt.Sprintf("%s (%s)", diff.From.Name, valueOrDash(diff.From.Phase))},
+		{"To", fmt.Sprintf("%s (%s)", diff.To.Name, valueOrDash(diff.To.Phase))},
+	}
+	if len(diff.Lineage) > 0 {
+		rows = append(rows, [2]string{"Lineage", strings.Join(diff.Lineage, " <- ")})
+	}
+	for _, row := range rows {
+		if _, err := fmt.Fprintf(w, "%s\t%s\n", row[0], row[1]); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(diff.Parameters) == 0 && len(diff.CustomDefs) == 0 && len(diff.Packages) == 0 && diff.Manifest == "" {
+		_, err := fmt.Fprintln(os.Stdout, "\nNo differences")
+		return err
+	}
+	for _, section := range []struct {
+		header  string
+		entries []buildapitypes.DiffEntry
+	}{
+		{"PARAMETER", diff.Parameters},
+		{"DEFINE", diff.CustomDefs},
+		{"PACKAGE", diff.Packages},
+	} {
+		if err := printDiffEntries(section.header, section.entries); err != nil {
+			return err
+		}
+	}
+	if diff.Manifest != "" {
+		if _, err := fmt.Fprintf(os.Stdout, "\n%s", diff.Manifest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printDiffEntries renders one section of a build diff.
+func printDiffEntries(header string, entries []buildapitypes.DiffEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintln(os.Stdout); err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintf(w, "%s\tFROM\tTO\n", header); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", e.Name, valueOrDash(e.From), valueOrDash(e.To)); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,97 @@
+package querycmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+)
+
+func TestOSBuildPackages(t *testing.T) {
+	manifest := `{
+  "version": "2",
+  "sources": {
+    "org.osbuild.curl": {
+      "items": {
+        "sha256:aaa": {"url": "https://mirror.example.com/BaseOS/x86_64/os/Packages/bash-5.1.8-9.el9.x86_64.rpm"},
+        "sha256:bbb": "https://mirror.example.com/AppStream/x86_64/os/Packages/libstdc%2B%2B-11.5.0-2.el9.x86_64.rpm",
+        "sha256:ccc": {"url": "https://mirror.example.com/containers/image.tar"}
+      }
+    },
+    "org.osbuild.librepo": {
+      "items": {
+        "sha256:ddd": {"path": "Packages/kernel-automotive-5.14.0-570.el9iv.aarch64.rpm", "mirror": "REDACTED"}
+      }
+    }
+  }
+}`
+	got, err := osbuildPackages([]byte(manifest))
+	if err != nil {
+		t.Fatalf("osbuildPackages() error = %v", err)
+	}
+	want := map[string]string{
+		"bash.x86_64":               "5.1.8-9.el9",
+		"libstdc++.x86_64":          "11.5.0-2.el9",
+		"kernel-automotive.aarch64": "5.14.0-570.el9iv",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("osbuildPackages() = %v, want %v", got, want)
+	}
+}
+
+func TestSplitRPMFilename(t *testing.T) {
+	tests := []struct {
+		file    string
+		name    string
+		version string
+		ok      bool
+	}{
+		{"bash-5.1.8-9.el9.x86_64.rpm", "bash.x86_64", "5.1.8-9.el9", true},
+		{"python3-dnf-plugins-core-4.3.0-20.el9.noarch.rpm", "python3-dnf-plugins-core.noarch", "4.3.0-20.el9", true},
+		{"noversion.x86_64.rpm", "", "", false},
+		{"norelease-1.0.rpm", "", "", false},
+	}
+	for _, tt := range tests {
+		name, version, ok := splitRPMFilename(tt.file)
+		if name != tt.name || version != tt.version || ok != tt.ok {
+			t.Errorf("splitRPMFilename(%q) = %q, %q, %t; want %q, %q, %t",
+				tt.file, name, version, ok, tt.name, tt.version, tt.ok)
+		}
+	}
+}
+
+func TestPrintBuildDiff(t *testing.T) {
+	out := captureStdout(t, func() {
+		_ = printBuildDiff(&buildapitypes.BuildDiffResponse{
+			From:       buildapitypes.BuildDiffSide{Name: "nightly-ab12c", Phase: "Completed"},
+			To:         buildapitypes.BuildDiffSide{Name: "nightly-de34f", Phase: "Failed"},
+			Lineage:    []string{"nightly-de34f", "nightly-ab12c"},
+			Parameters: []buildapitypes.DiffEntry{{Name: "builderImage", From: "builder@sha256:aaa", To: "builder@sha256:bbb"}},
+			CustomDefs: []buildapitypes.DiffEntry{{Name: "debug", To: "true"}},
+			Packages:   []buildapitypes.DiffEntry{{Name: "bash.x86_64", From: "5.1.8-9.el9", To: "5.1.8-10.el9"}},
+			Manifest:   "--- nightly-ab12c/manifest.aib.yml\n+++ nightly-de34f/manifest.aib.yml\n",
+		})
+	})
+	for _, want := range []string{
+		"nightly-de34f <- nightly-ab12c",
+		"PARAMETER", "builder@sha256:bbb",
+		"DEFINE", "debug",
+		"PACKAGE", "5.1.8-10.el9",
+		"+++ nightly-de34f/manifest.aib.yml",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got: %s", want, out)
+		}
+	}
+
+	out = captureStdout(t, func() {
+		_ = printBuildDiff(&buildapitypes.BuildDiffResponse{
+			From: buildapitypes.BuildDiffSide{Name: "nightly-ab12c", Phase: "Completed"},
+			To:   buildapitypes.BuildDiffSide{Name: "nightly-de34f", Phase: "Completed"},
+		})
+	})
+	if !strings.Contains(out, "No differences") || strings.Contains(out, "Lineage") {
+		t.Errorf("expected no differences and no lineage, got: %s", out)
+	}
+}
@@ -1,4 +1,4 @@
-// Package querycmd provides handlers for image list/show commands.
+// Package querycmd provides handlers for image list/show/diff commands.
 package querycmd
 
 import (
@@ -21,15 +21,16 @@ const outputFormatTable = "table"
 
 // Options wires query handlers to caller-owned state and helper callbacks.
 type Options struct {
-	ServerURL       *string
-	AuthToken       *string
-	OutputFormat    *string
-	InsecureSkipTLS *bool
+	ServerURL        *string
+	AuthToken        *string
+	OutputFormat     *string
+	RegistryAuthFile *string
+	InsecureSkipTLS  *bool
 
 	HandleError func(error)
 }
 
-// Handler implements list/show command run functions.
+// Handler implements list/show/diff command run functions.
 type Handler struct {
 	opts Options
 }
@@ -221,6 +222,9 @@ func buildParametersFromTemplate(tpl *buildapitypes.BuildTemplateResponse) *buil
 		FlashLeaseDuration:     tpl.FlashLeaseDuration,
 		UseServiceAccountAuth:  tpl.UseInternalRegistry,
 	}
+	for _, arch := range tpl.Architectures {
+		params.Architectures = append(params.Architectures, string(arch))
+	}
 
 	if strings.TrimSpace(params.Architecture) == "" &&
 		strings.TrimSpace(params.Distro) == "" &&
@@ -275,17 +279,32 @@ func printBuildDetails(st *buildapitypes.BuildResponse) error {
 		{"Name", st.Name},
 		{"Phase", st.Phase},
 		{"Message", st.Message},
+	}
+	if st.QueuePosition > 0 {
+		rows = append(rows, [2]string{"Queue Position", fmt.Sprintf("%d", st.QueuePosition)})
+	}
+	if st.NextRetryTime != "" {
+		rows = append(rows, [2]string{"Next Retry", st.NextRetryTime})
+	}
+	if st.ParentBuild != "" {
+		rows = append(rows, [2]string{"Parent Build", st.ParentBuild})
+	}
+	rows = append(rows, [][2]string{
 		{"Requested By", valueOrDash(st.RequestedBy)},
 		{"Start Time", valueOrDash(st.StartTime)},
 		{"Completion Time", valueOrDash(st.CompletionTime)},
 		{"Container Image", valueOrDash(st.ContainerImage)},
 		{"Disk Image", valueOrDash(st.DiskImage)},
 		{"Warning", valueOrDash(st.Warning)},
-	}
+	}...)
 
 	if st.Parameters != nil {
+		architecture := st.Parameters.Architecture
+		if len(st.Parameters.Architectures) > 0 {
+			architecture = strings.Join(st.Parameters.Architectures, ", ")
+		}
 		rows = append(rows,
-			[2]string{"Architecture", valueOrDash(st.Parameters.Architecture)},
+			[2]string{"Architecture", valueOrDash(architecture)},
 			[2]string{"Distro", valueOrDash(st.Parameters.Distro)},
 			[2]string{"Target", valueOrDash(st.Parameters.Target)},
 			[2]string{"Mode", valueOrDash(st.Parameters.Mode)},
@@ -294,7 +313,11 @@ func printBuildDetails(st *buildapitypes.BuildResponse) error {
 			[2]string{"Storage Class", valueOrDash(st.Parameters.StorageClass)},
 			[2]string{"AIB Image", valueOrDash(st.Parameters.AutomotiveImageBuilder)},
 			[2]string{"Builder Image", valueOrDash(st.Parameters.BuilderImage)},
+			[2]string{"Priority", valueOrDash(st.Parameters.Priority)},
 		)
+		if st.Parameters.MaxAttempts > 0 {
+			rows = append(rows, [2]string{"Max Attempts", fmt.Sprintf("%d", st.Parameters.MaxAttempts)})
+		}
 	}
 
 	if st.Jumpstarter != nil {
@@ -305,12 +328,104 @@ func printBuildDetails(st *buildapitypes.BuildResponse) error {
 			[2]string{"Jumpstarter Lease ID", valueOrDash(st.Jumpstarter.LeaseID)},
 		)
 	}
+	if st.DeviceTests != nil {
+		rows = append(rows, [2]string{"Device Tests", common.FormatDeviceTests(st.DeviceTests)})
+	}
 
 	for _, row := range rows {
 		if _, err := fmt.Fprintf(w, "%s\t%s\n", row[0], row[1]); err != nil {
 			return err
 		}
 	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := printArchitectureDetails(st.Architectures); err != nil {
+		return err
+	}
+	if err := printAttemptDetails(st.Attempts); err != nil {
+		return err
+	}
+	return printFailedTestCases(st.DeviceTests)
+}
+
+// printFailedTestCases renders the failed on-device test cases of a build.
+func printFailedTestCases(results *buildapitypes.DeviceTestResults) error {
+	if results == nil || len(results.FailedCases) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintln(os.Stdout); err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "SUITE\tFAILED TEST\tMESSAGE"); err != nil {
+		return err
+	}
+	for _, tc := range results.FailedCases {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", valueOrDash(tc.Suite), tc.Name, valueOrDash(tc.Message)); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// printAttemptDetails renders the failed attempts of a build with a retry policy.
+func printAttemptDetails(attempts []buildapitypes.BuildAttempt) error {
+	if len(attempts) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintln(os.Stdout); err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "ATTEMPT\tSTAGE\tRUN\tFAILURE CLASS\tREASON"); err != nil {
+		return err
+	}
+	for _, a := range attempts {
+		run := a.PipelineRunName
+		if a.TaskRunName != "" {
+			run = a.TaskRunName
+		}
+		if _, err := fmt.Fprintf(
+			w,
+			"%d\t%s\t%s\t%s\t%s\n",
+			a.Attempt,
+			a.Stage,
+			valueOrDash(run),
+			valueOrDash(a.FailureClass),
+			valueOrDash(a.FailureReason),
+		); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// printArchitectureDetails renders the per-architecture table of a multi-arch build.
+func printArchitectureDetails(archs []buildapitypes.ArchitectureStatus) error {
+	if len(archs) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintln(os.Stdout); err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "ARCH\tPHASE\tCONTAINER IMAGE\tDISK IMAGE\tMESSAGE"); err != nil {
+		return err
+	}
+	for _, a := range archs {
+		if _, err := fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\t%s\n",
+			a.Architecture,
+			valueOrDash(a.Phase),
+			valueOrDash(a.ContainerImage),
+			valueOrDash(a.DiskImage),
+			valueOrDash(a.Message),
+		); err != nil {
+			return err
+		}
+	}
 	return w.Flush()
 }
 
@@ -266,6 +266,136 @@ func TestFormatOutputYAML_Show(t *testing.T) {
 	}
 }
 
+func TestPrintBuildDetails_MultiArch(t *testing.T) {
+	st := &buildapitypes.BuildResponse{
+		Name:           "multi",
+		Phase:          "Building",
+		ContainerImage: "quay.io/org/img:v1",
+		Parameters: &buildapitypes.BuildParameters{
+			Architecture:  "amd64",
+			Architectures: []string{"amd64", "arm64"},
+		},
+		Architectures: []buildapitypes.ArchitectureStatus{
+			{Architecture: "amd64", Phase: "Completed", ContainerImage: "quay.io/org/img:v1-amd64"},
+			{Architecture: "arm64", Phase: "Building"},
+		},
+	}
+	out := captureStdout(t, func() {
+		_ = printBuildDetails(st)
+	})
+
+	if !strings.Contains(out, "amd64, arm64") {
+		t.Errorf("expected architecture list in parameters, got: %s", out)
+	}
+	if !strings.Contains(out, "ARCH") || !strings.Contains(out, "quay.io/org/img:v1-amd64") {
+		t.Errorf("expected per-architecture table, got: %s", out)
+	}
+}
+
+func TestPrintBuildDetails_SingleArchHasNoArchTable(t *testing.T) {
+	st := &buildapitypes.BuildResponse{
+		Name:       "single",
+		Phase:      "Completed",
+		Parameters: &buildapitypes.BuildParameters{Architecture: "arm64"},
+	}
+	out := captureStdout(t, func() {
+		_ = printBuildDetails(st)
+	})
+
+	if strings.Contains(out, "ARCH\t") || strings.Contains(out, "CONTAINER IMAGE") {
+		t.Errorf("did not expect per-architecture table, got: %s", out)
+	}
+}
+
+func TestPrintBuildDetails_QueuePosition(t *testing.T) {
+	queued := captureStdout(t, func() {
+		_ = printBuildDetails(&buildapitypes.BuildResponse{Name: "queued", Phase: "Queued", QueuePosition: 3})
+	})
+	if !strings.Contains(queued, "Queue Position") || !strings.Contains(queued, "3") {
+		t.Errorf("expected queue position, got: %s", queued)
+	}
+
+	running := captureStdout(t, func() {
+		_ = printBuildDetails(&buildapitypes.BuildResponse{Name: "running", Phase: "Building"})
+	})
+	if strings.Contains(running, "Queue Position") {
+		t.Errorf("did not expect queue position for a running build, got: %s", running)
+	}
+}
+
+func TestPrintBuildDetails_Attempts(t *testing.T) {
+	out := captureStdout(t, func() {
+		_ = printBuildDetails(&buildapitypes.BuildResponse{
+			Name:          "retried",
+			Phase:         "Pushing",
+			NextRetryTime: "2026-01-01T10:00:00Z",
+			Parameters:    &buildapitypes.BuildParameters{MaxAttempts: 3},
+			Attempts: []buildapitypes.BuildAttempt{{
+				Attempt:         1,
+				Stage:           "Push",
+				PipelineRunName: "retried-abc",
+				TaskRunName:     "retried-push-xyz",
+				FailureClass:    "RegistryServerError",
+				FailureReason:   "Push to registry failed: 503 Service Unavailable",
+			}},
+		})
+	})
+	for _, want := range []string{"Next Retry", "Max Attempts", "ATTEMPT", "retried-push-xyz", "RegistryServerError"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got: %s", want, out)
+		}
+	}
+}
+
+func TestPrintBuildDetails_ParentBuild(t *testing.T) {
+	out := captureStdout(t, func() {
+		_ = printBuildDetails(&buildapitypes.BuildResponse{
+			Name:        "my-build-de34f",
+			Phase:       "Building",
+			ParentBuild: "my-build-ab12c",
+		})
+	})
+	if !strings.Contains(out, "Parent Build") || !strings.Contains(out, "my-build-ab12c") {
+		t.Errorf("expected parent build row in output, got: %s", out)
+	}
+
+	out = captureStdout(t, func() {
+		_ = printBuildDetails(&buildapitypes.BuildResponse{Name: "my-build-ab12c", Phase: "Completed"})
+	})
+	if strings.Contains(out, "Parent Build") {
+		t.Errorf("expected no parent build row for a build that was not rebuilt, got: %s", out)
+	}
+}
+
+func TestPrintBuildDetails_DeviceTests(t *testing.T) {
+	out := captureStdout(t, func() {
+		_ = printBuildDetails(&buildapitypes.BuildResponse{
+			Name:  "smoke",
+			Phase: "Failed",
+			DeviceTests: &buildapitypes.DeviceTestResults{
+				Booted: true, Total: 3, Passed: 2, Failed: 1,
+				FailedCases: []buildapitypes.DeviceTestCase{{Suite: "smoke", Name: "network-up", Message: "no route to host"}},
+			},
+		})
+	})
+	for _, want := range []string{"Device Tests", "2 passed, 1 failed, 0 skipped", "FAILED TEST", "network-up", "no route to host"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got: %s", want, out)
+		}
+	}
+
+	out = captureStdout(t, func() {
+		_ = printBuildDetails(&buildapitypes.BuildResponse{
+			Name:        "smoke",
+			Phase:       "Failed",
+			DeviceTests: &buildapitypes.DeviceTestResults{},
+		})
+	})
+	if !strings.Contains(out, "device did not boot") {
+		t.Errorf("expected boot failure in output, got: %s", out)
+	}
+}
+
 func TestValueOrDash(t *testing.T) {
 	tests := []struct {
 		input string
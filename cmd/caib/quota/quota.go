@@ -0,0 +1,117 @@
+// Package quota implements the CLI command that shows Build API quota usage.
+package quota
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	caibcommon "github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/common"
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/config"
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+	buildapiclient "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi/client"
+)
+
+var (
+	serverURL       string
+	authToken       string
+	insecureSkipTLS bool
+)
+
+// NewQuotaCmd creates the quota command.
+func NewQuotaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "quota",
+		Short: "Show your build and workspace usage against your quotas",
+		Long: `Show how many concurrent builds, how much build storage, and how many workspaces
+and how much workspace storage you and your team use, against the quotas set in
+the OperatorConfig.
+
+Examples:
+  caib quota
+  caib --project team-a quota`,
+		Args: cobra.NoArgs,
+		Run:  runQuota,
+	}
+
+	cmd.Flags().StringVar(&serverURL, "server", config.DefaultServer(), "REST API server base URL")
+	cmd.Flags().StringVar(&authToken, "token", os.Getenv("CAIB_TOKEN"), "Bearer token for authentication")
+	cmd.Flags().BoolVar(&insecureSkipTLS, "insecure-skip-tls-verify", false, "skip TLS certificate verification")
+
+	return cmd
+}
+
+func runQuota(_ *cobra.Command, _ []string) {
+	if serverURL == "" {
+		handleError(caibcommon.ServerURLRequiredError("caib quota --server <server-url>"))
+	}
+
+	var quota *buildapitypes.QuotaResponse
+	err := caibcommon.ExecuteWithReauth(serverURL, &authToken, insecureSkipTLS, func(client *buildapiclient.Client) error {
+		q, cerr := client.GetQuota(context.Background())
+		if cerr != nil {
+			return cerr
+		}
+		quota = q
+		return nil
+	})
+	if err != nil {
+		handleError(fmt.Errorf("failed to get quota: %w", err))
+	}
+
+	if err := writeQuotaTable(os.Stdout, quota); err != nil {
+		handleError(err)
+	}
+}
+
+// writeQuotaTable renders the usage of the user and the team against their limits.
+func writeQuotaTable(out io.Writer, quota *buildapitypes.QuotaResponse) error {
+	maxTTL := quota.MaxBuildTTL
+	if maxTTL == "" {
+		maxTTL = "unlimited"
+	}
+	if _, err := fmt.Fprintf(out, "User: %s (%s)\nTeam: %s\nMax build TTL: %s\n\n",
+		quota.User, quota.Role, quota.Team, maxTTL); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "RESOURCE\tUSER\tTEAM"); err != nil {
+		return err
+	}
+	user, team := &quota.UserUsage, &quota.TeamUsage
+	rows := [][3]string{
+		{"Concurrent builds", formatCount(user.ConcurrentBuilds, user.MaxConcurrentBuilds), formatCount(team.ConcurrentBuilds, team.MaxConcurrentBuilds)},
+		{"Build storage", formatSize(user.BuildStorage, user.MaxBuildStorage), formatSize(team.BuildStorage, team.MaxBuildStorage)},
+		{"Workspaces", formatCount(user.Workspaces, user.MaxWorkspaces), formatCount(team.Workspaces, team.MaxWorkspaces)},
+		{"Workspace storage", formatSize(user.WorkspaceStorage, user.MaxWorkspaceStorage), formatSize(team.WorkspaceStorage, team.MaxWorkspaceStorage)},
+	}
+	for _, row := range rows {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", row[0], row[1], row[2]); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func formatCount(used, limit int32) string {
+	return formatSize(strconv.Itoa(int(used)), strconv.Itoa(int(limit)))
+}
+
+// formatSize shows usage as "used / limit", or "used / -" without a limit.
+func formatSize(used, limit string) string {
+	if limit == "" || limit == "0" {
+		limit = "-"
+	}
+	return used + " / " + limit
+}
+
+func handleError(err error) {
+	fmt.Fprintln(os.Stderr, caibcommon.FormatError(err))
+	os.Exit(1)
+}
@@ -0,0 +1,42 @@
+package quota
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+)
+
+func TestWriteQuotaTable(t *testing.T) {
+	var out bytes.Buffer
+	err := writeQuotaTable(&out, &buildapitypes.QuotaResponse{
+		User: "alice",
+		Role: "builder",
+		Team: "team-a",
+		UserUsage: buildapitypes.QuotaUsage{
+			ConcurrentBuilds: 1, MaxConcurrentBuilds: 2,
+			BuildStorage: "16Gi", MaxBuildStorage: "100Gi",
+			Workspaces:       1,
+			WorkspaceStorage: "10Gi",
+		},
+		TeamUsage: buildapitypes.QuotaUsage{ConcurrentBuilds: 4, BuildStorage: "64Gi", Workspaces: 3, WorkspaceStorage: "30Gi"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"User: alice (builder)",
+		"Max build TTL: unlimited",
+		"1 / 2",
+		"16Gi / 100Gi",
+		"10Gi / -",
+		"64Gi / -",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+	}
+}
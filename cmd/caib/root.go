@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"runtime/debug"
 	"strings"
 
@@ -10,6 +11,8 @@ import (
 	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/clilog"
 	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/container"
 	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/image"
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/lease"
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/quota"
 	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/workspace"
 	"github.com/spf13/cobra"
 )
@@ -47,6 +50,10 @@ func newRootCmd() *cobra.Command {
 			if !validOutputFormats[f] {
 				return fmt.Errorf("invalid output format %q (supported: table, json, yaml)", outputFormat)
 			}
+			// Subcommands create their clients from the environment
+			if p := strings.TrimSpace(project); p != "" {
+				return os.Setenv("CAIB_PROJECT", p)
+			}
 			return nil
 		},
 	}
@@ -66,6 +73,12 @@ func newRootCmd() *cobra.Command {
 		"table",
 		"output format: table, json, yaml",
 	)
+	rootCmd.PersistentFlags().StringVar(
+		&project,
+		"project",
+		os.Getenv("CAIB_PROJECT"),
+		"project (tenant namespace) to work in, when the server has tenancy enabled (env: CAIB_PROJECT)",
+	)
 	rootCmd.PersistentFlags().BoolVarP(
 		&quiet,
 		"quiet",
@@ -89,6 +102,8 @@ func newRootCmd() *cobra.Command {
 		catalog.NewCatalogCmd(),
 		authcmd.NewAuthCmd(),
 		workspace.NewWorkspaceCmd(),
+		lease.NewLeaseCmd(),
+		quota.NewQuotaCmd(),
 	)
 
 	return rootCmd
@@ -9,6 +9,7 @@ import (
 	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/querycmd"
 	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/sealedcmd"
 	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/tokencmd"
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/verifycmd"
 )
 
 type runtimeState struct {
@@ -52,15 +53,40 @@ type runtimeState struct {
 	LeaseName         *string
 	FlashCmd          *string
 
+	TestScript   *string
+	TestImage    *string
+	BootCheck    *string
+	BootMarker   *string
+	BootTimeout  *string
+	NoPowerCycle *bool
+
+	FleetBoards      *int
+	FleetAllBoards   *bool
+	FleetMaxParallel *int
+
+	CaptureConsole  *bool
+	ConsoleDuration *string
+
 	UseInternalRegistry       *bool
 	InternalRegistryImageName *string
 	InternalRegistryTag       *string
 
+	ExportTo     *string
+	S3Endpoint   *string
+	S3Region     *string
+	ExportSecret *string
+
 	SecureBuild       *bool
 	Reproducible      *bool
 	TaskBundleRef     *string
 	RestoreSourcesRef *string
 	TTL               *string
+	Priority          *string
+	MaxAttempts       *int
+	Notify            *bool
+	DownloadSBOM      *bool
+	SigningKeySecret  *string
+	VerifyKeyFile     *string
 
 	InsecureSkipTLS *bool
 
@@ -117,15 +143,40 @@ func newRuntimeState() runtimeState {
 		LeaseName:         &leaseName,
 		FlashCmd:          &flashCmdOverride,
 
+		TestScript:   &testScript,
+		TestImage:    &testImage,
+		BootCheck:    &bootCheck,
+		BootMarker:   &bootMarker,
+		BootTimeout:  &bootTimeout,
+		NoPowerCycle: &noPowerCycle,
+
+		FleetBoards:      &fleetBoards,
+		FleetAllBoards:   &fleetAllBoards,
+		FleetMaxParallel: &fleetMaxParallel,
+
+		CaptureConsole:  &captureConsole,
+		ConsoleDuration: &consoleDuration,
+
 		UseInternalRegistry:       &useInternalRegistry,
 		InternalRegistryImageName: &internalRegistryImageName,
 		InternalRegistryTag:       &internalRegistryTag,
 
+		ExportTo:     &exportTo,
+		S3Endpoint:   &s3Endpoint,
+		S3Region:     &s3Region,
+		ExportSecret: &exportSecret,
+
 		SecureBuild:       &secureBuild,
 		Reproducible:      &reproducibleBuild,
 		TaskBundleRef:     &taskBundleRef,
 		RestoreSourcesRef: &restoreSourcesRef,
 		TTL:               &buildTTL,
+		Priority:          &buildPriority,
+		MaxAttempts:       &buildMaxAttempts,
+		Notify:            &buildNotify,
+		DownloadSBOM:      &downloadSBOM,
+		SigningKeySecret:  &signingKeySecret,
+		VerifyKeyFile:     &verifyKeyFile,
 
 		InsecureSkipTLS: &insecureSkipTLS,
 
@@ -149,6 +200,7 @@ type handlerSet struct {
 	sealed   *sealedcmd.Handler
 	token    *tokencmd.Handler
 	inspect  *inspectcmd.Handler
+	verify   *verifycmd.Handler
 }
 
 func (s runtimeState) newHandlers() handlerSet {
@@ -189,28 +241,44 @@ func (s runtimeState) newHandlers() handlerSet {
 			LeaseName:                 s.LeaseName,
 			FlashCmd:                  s.FlashCmd,
 			ExporterSelector:          s.ExporterSelector,
+			TestScript:                s.TestScript,
+			TestImage:                 s.TestImage,
+			BootCheck:                 s.BootCheck,
+			BootMarker:                s.BootMarker,
+			BootTimeout:               s.BootTimeout,
+			NoPowerCycle:              s.NoPowerCycle,
 			UseInternalRegistry:       s.UseInternalRegistry,
 			InternalRegistryImageName: s.InternalRegistryImageName,
 			InternalRegistryTag:       s.InternalRegistryTag,
+			ExportTo:                  s.ExportTo,
+			S3Endpoint:                s.S3Endpoint,
+			S3Region:                  s.S3Region,
+			ExportSecret:              s.ExportSecret,
 			SecureBuild:               s.SecureBuild,
 			Reproducible:              s.Reproducible,
 			TaskBundleRef:             s.TaskBundleRef,
 			RestoreSourcesRef:         s.RestoreSourcesRef,
 			TTL:                       s.TTL,
+			Priority:                  s.Priority,
+			MaxAttempts:               s.MaxAttempts,
+			Notify:                    s.Notify,
+			SigningKeySecret:          s.SigningKeySecret,
 			InsecureSkipTLS:           s.InsecureSkipTLS,
 			HandleError:               handleError,
 		}),
 		query: querycmd.NewHandler(querycmd.Options{
-			ServerURL:       s.ServerURL,
-			AuthToken:       s.AuthToken,
-			OutputFormat:    s.OutputFormat,
-			InsecureSkipTLS: s.InsecureSkipTLS,
-			HandleError:     handleError,
+			ServerURL:        s.ServerURL,
+			AuthToken:        s.AuthToken,
+			OutputFormat:     s.OutputFormat,
+			RegistryAuthFile: s.RegistryAuthFile,
+			InsecureSkipTLS:  s.InsecureSkipTLS,
+			HandleError:      handleError,
 		}),
 		download: downloadcmd.NewHandler(downloadcmd.Options{
 			ServerURL:       s.ServerURL,
 			AuthToken:       s.AuthToken,
 			OutputDir:       s.OutputDir,
+			SBOM:            s.DownloadSBOM,
 			InsecureSkipTLS: s.InsecureSkipTLS,
 			HandleError:     handleError,
 		}),
@@ -224,6 +292,17 @@ func (s runtimeState) newHandlers() handlerSet {
 			LeaseDuration:     s.LeaseDuration,
 			LeaseName:         s.LeaseName,
 			FlashCmd:          s.FlashCmd,
+			TestScript:        s.TestScript,
+			TestImage:         s.TestImage,
+			BootCheck:         s.BootCheck,
+			BootMarker:        s.BootMarker,
+			BootTimeout:       s.BootTimeout,
+			NoPowerCycle:      s.NoPowerCycle,
+			FleetBoards:       s.FleetBoards,
+			FleetAllBoards:    s.FleetAllBoards,
+			FleetMaxParallel:  s.FleetMaxParallel,
+			CaptureConsole:    s.CaptureConsole,
+			ConsoleDuration:   s.ConsoleDuration,
 			WaitForBuild:      s.WaitForBuild,
 			FollowLogs:        s.FollowLogs,
 			InsecureSkipTLS:   s.InsecureSkipTLS,
@@ -264,6 +343,14 @@ func (s runtimeState) newHandlers() handlerSet {
 			InsecureSkipTLS:  s.InsecureSkipTLS,
 			HandleError:      handleError,
 		}),
+		verify: verifycmd.NewHandler(verifycmd.Options{
+			ServerURL:        s.ServerURL,
+			AuthToken:        s.AuthToken,
+			KeyFile:          s.VerifyKeyFile,
+			RegistryAuthFile: s.RegistryAuthFile,
+			InsecureSkipTLS:  s.InsecureSkipTLS,
+			HandleError:      handleError,
+		}),
 	}
 }
 
@@ -284,7 +371,10 @@ func (s runtimeState) imageOptions(h handlerSet) image.Options {
 		RunToken:             h.token.RunToken,
 		RunDelete:            h.build.RunDelete,
 		RunCancel:            h.build.RunCancel,
+		RunRebuild:           h.build.RunRebuild,
+		RunDiff:              h.query.RunDiff,
 		RunInspect:           h.inspect.RunInspect,
+		RunVerify:            h.verify.RunVerify,
 		GetDefaultArch:       getDefaultArch,
 
 		ServerURL:              s.ServerURL,
@@ -323,15 +413,40 @@ func (s runtimeState) imageOptions(h handlerSet) image.Options {
 		LeaseName:         s.LeaseName,
 		FlashCmd:          s.FlashCmd,
 
+		TestScript:   s.TestScript,
+		TestImage:    s.TestImage,
+		BootCheck:    s.BootCheck,
+		BootMarker:   s.BootMarker,
+		BootTimeout:  s.BootTimeout,
+		NoPowerCycle: s.NoPowerCycle,
+
+		FleetBoards:      s.FleetBoards,
+		FleetAllBoards:   s.FleetAllBoards,
+		FleetMaxParallel: s.FleetMaxParallel,
+
+		CaptureConsole:  s.CaptureConsole,
+		ConsoleDuration: s.ConsoleDuration,
+
 		UseInternalRegistry:       s.UseInternalRegistry,
 		InternalRegistryImageName: s.InternalRegistryImageName,
 		InternalRegistryTag:       s.InternalRegistryTag,
 
+		ExportTo:     s.ExportTo,
+		S3Endpoint:   s.S3Endpoint,
+		S3Region:     s.S3Region,
+		ExportSecret: s.ExportSecret,
+
 		SecureBuild:       s.SecureBuild,
 		Reproducible:      s.Reproducible,
 		TaskBundleRef:     s.TaskBundleRef,
 		RestoreSourcesRef: s.RestoreSourcesRef,
 		TTL:               s.TTL,
+		Priority:          s.Priority,
+		MaxAttempts:       s.MaxAttempts,
+		Notify:            s.Notify,
+		DownloadSBOM:      s.DownloadSBOM,
+		SigningKeySecret:  s.SigningKeySecret,
+		VerifyKeyFile:     s.VerifyKeyFile,
 
 		SealedBuilderImage:      s.SealedBuilderImage,
 		SealedArchitecture:      s.SealedArchitecture,
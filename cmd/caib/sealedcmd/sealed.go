@@ -208,7 +208,7 @@ func (h *Handler) sealedWaitForCompletion(
 	waitCtx, cancel := context.WithTimeout(ctx, sealedTimeout)
 	defer cancel()
 
-	ticker := time.NewTicker(5 * time.Second)
+	ticker := common.NewStatusTicker(waitCtx, 5*time.Second, common.ResourceWatch(api, "ImageReseal", name))
 	defer ticker.Stop()
 
 	var lastPhase string
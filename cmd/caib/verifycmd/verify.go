@@ -0,0 +1,211 @@
+// Package verifycmd provides the image verify handler for cosign signatures
+// and SLSA provenance of build artifacts.
+package verifycmd
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	ociremote "github.com/sigstore/cosign/v3/pkg/oci/remote"
+	"github.com/spf13/cobra"
+
+	common "github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/common"
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/registryauth"
+	buildapiclient "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi/client"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/bundleverify"
+)
+
+// provenanceLabels maps provenance external parameters to display labels.
+var provenanceLabels = map[string]string{
+	"distro":       "Distro",
+	"target":       "Target",
+	"architecture": "Arch",
+	"mode":         "Mode",
+	"exportFormat": "Export Format",
+}
+
+// Options wires verify handler dependencies.
+type Options struct {
+	ServerURL        *string
+	AuthToken        *string
+	KeyFile          *string
+	RegistryAuthFile *string
+	InsecureSkipTLS  *bool
+
+	HandleError func(error)
+}
+
+// Handler implements the verify command.
+type Handler struct {
+	opts Options
+}
+
+// NewHandler creates a verify handler.
+func NewHandler(opts Options) *Handler {
+	return &Handler{opts: opts}
+}
+
+func (h *Handler) handleError(err error) {
+	if h.opts.HandleError != nil {
+		h.opts.HandleError(err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, common.FormatError(err))
+	os.Exit(1)
+}
+
+// provenance is the subset of the SLSA v1 predicate written by the
+// sign-artifacts task that is shown to the user.
+type provenance struct {
+	BuildDefinition struct {
+		ExternalParameters map[string]any    `json:"externalParameters"`
+		InternalParameters map[string]string `json:"internalParameters"`
+	} `json:"buildDefinition"`
+	RunDetails struct {
+		Metadata struct {
+			FinishedOn string `json:"finishedOn"`
+		} `json:"metadata"`
+	} `json:"runDetails"`
+}
+
+// RunVerify handles `caib image verify <oci-ref>`.
+func (h *Handler) RunVerify(_ *cobra.Command, args []string) {
+	ctx := context.Background()
+	ociRef := args[0]
+
+	pubKeyPEM, err := h.publicKey(ctx)
+	if err != nil {
+		h.handleError(err)
+		return
+	}
+	registryOpts, err := h.registryOptions(ociRef)
+	if err != nil {
+		h.handleError(err)
+		return
+	}
+
+	if err := bundleverify.VerifyBundle(ctx, ociRef, pubKeyPEM, registryOpts...); err != nil {
+		h.handleError(fmt.Errorf("signature of %s not verified: %w", ociRef, err))
+		return
+	}
+	predicate, err := bundleverify.VerifyProvenance(ctx, ociRef, pubKeyPEM, registryOpts...)
+	if err != nil {
+		h.handleError(fmt.Errorf("signature of %s verified, but provenance not verified: %w", ociRef, err))
+		return
+	}
+
+	if err := printVerification(ociRef, predicate); err != nil {
+		h.handleError(err)
+	}
+}
+
+// publicKey returns the cosign public key from --key, or else the signing
+// public key served by the Build API.
+func (h *Handler) publicKey(ctx context.Context) ([]byte, error) {
+	if h.opts.KeyFile != nil && strings.TrimSpace(*h.opts.KeyFile) != "" {
+		data, err := os.ReadFile(strings.TrimSpace(*h.opts.KeyFile))
+		if err != nil {
+			return nil, fmt.Errorf("read public key: %w", err)
+		}
+		return data, nil
+	}
+
+	if h.opts.ServerURL == nil || strings.TrimSpace(*h.opts.ServerURL) == "" {
+		return nil, fmt.Errorf("public key required (use --key, or --server to use the signing key configured on the server)")
+	}
+	insecureSkipTLS := h.opts.InsecureSkipTLS != nil && *h.opts.InsecureSkipTLS
+	var pubKey string
+	err := common.ExecuteWithReauth(strings.TrimSpace(*h.opts.ServerURL), h.opts.AuthToken, insecureSkipTLS,
+		func(api *buildapiclient.Client) error {
+			cfg, cfgErr := api.GetOperatorConfig(ctx)
+			if cfgErr != nil {
+				return cfgErr
+			}
+			pubKey = cfg.SigningPublicKey
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("error reading signing public key from server: %w", err)
+	}
+	if pubKey == "" {
+		return nil, fmt.Errorf("server has no signing public key configured (use --key)")
+	}
+	return []byte(pubKey), nil
+}
+
+// registryOptions authenticates with REGISTRY_USERNAME/REGISTRY_PASSWORD, else
+// the --registry-auth-file or first auth file found, else the default keychain.
+func (h *Handler) registryOptions(ociRef string) ([]ociremote.Option, error) {
+	var remoteOpts []remote.Option
+	if _, username, password := registryauth.ExtractRegistryCredentials(ociRef, ""); username != "" && password != "" {
+		remoteOpts = append(remoteOpts, remote.WithAuth(&authn.Basic{Username: username, Password: password}))
+	} else {
+		authFile := ""
+		if h.opts.RegistryAuthFile != nil {
+			authFile = strings.TrimSpace(*h.opts.RegistryAuthFile)
+		}
+		if authFile == "" {
+			for _, candidate := range registryauth.FileCandidates() {
+				if _, err := os.Stat(candidate); err == nil {
+					authFile = candidate
+					break
+				}
+			}
+		}
+		var keychain authn.Keychain = authn.DefaultKeychain
+		if authFile != "" {
+			var err error
+			if keychain, err = bundleverify.KeychainFromAuthFile(authFile); err != nil {
+				return nil, err
+			}
+		}
+		remoteOpts = append(remoteOpts, remote.WithAuthFromKeychain(keychain))
+	}
+	if h.opts.InsecureSkipTLS != nil && *h.opts.InsecureSkipTLS {
+		remoteOpts = append(remoteOpts, remote.WithTransport(&http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true, //nolint:gosec // user explicitly opted in
+			},
+		}))
+	}
+	return []ociremote.Option{ociremote.WithRemoteOptions(remoteOpts...)}, nil
+}
+
+func printVerification(ociRef string, predicate json.RawMessage) error {
+	var p provenance
+	if err := json.Unmarshal(predicate, &p); err != nil {
+		return fmt.Errorf("parse provenance: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	rows := [][2]string{
+		{"Reference", ociRef},
+		{"Signature", "verified"},
+		{"Provenance", "verified (" + bundleverify.SLSAProvenanceV1PredicateType + ")"},
+	}
+	for _, key := range []string{"distro", "target", "architecture", "mode", "exportFormat"} {
+		if value, ok := p.BuildDefinition.ExternalParameters[key].(string); ok && value != "" {
+			rows = append(rows, [2]string{provenanceLabels[key], value})
+		}
+	}
+	if run := p.BuildDefinition.InternalParameters["pipelineRun"]; run != "" {
+		rows = append(rows, [2]string{"PipelineRun", run})
+	}
+	if finished := p.RunDetails.Metadata.FinishedOn; finished != "" {
+		rows = append(rows, [2]string{"Built", finished})
+	}
+	for _, row := range rows {
+		if _, err := fmt.Fprintf(w, "%s:\t%s\n", row[0], row[1]); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
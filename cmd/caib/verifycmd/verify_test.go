@@ -0,0 +1,86 @@
+package verifycmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPublicKeyFromFile(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "cosign.pub")
+	if err := os.WriteFile(keyFile, []byte("-----BEGIN PUBLIC KEY-----\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewHandler(Options{KeyFile: &keyFile})
+	got, err := h.publicKey(t.Context())
+	if err != nil {
+		t.Fatalf("publicKey() error = %v", err)
+	}
+	if string(got) != "-----BEGIN PUBLIC KEY-----\n" {
+		t.Errorf("publicKey() = %q", got)
+	}
+
+	empty := ""
+	h = NewHandler(Options{KeyFile: &empty, ServerURL: &empty})
+	if _, err := h.publicKey(t.Context()); err == nil || !strings.Contains(err.Error(), "--key") {
+		t.Errorf("expected error mentioning --key, got %v", err)
+	}
+}
+
+func TestPrintVerification(t *testing.T) {
+	predicate := []byte(`{
+  "buildDefinition": {
+    "externalParameters": {"distro": "autosd", "target": "qemu", "architecture": "arm64", "mode": "image", "exportFormat": ""},
+    "internalParameters": {"pipelineRun": "builds/nightly-ab12c-run"}
+  },
+  "runDetails": {"metadata": {"finishedOn": "2026-01-02T03:04:05Z"}}
+}`)
+
+	out := captureStdout(t, func() {
+		if err := printVerification("quay.io/org/repo@sha256:abc", predicate); err != nil {
+			t.Errorf("printVerification() error = %v", err)
+		}
+	})
+	for _, want := range []string{
+		"quay.io/org/repo@sha256:abc",
+		"https://slsa.dev/provenance/v1",
+		"autosd", "qemu", "arm64",
+		"builds/nightly-ab12c-run",
+		"2026-01-02T03:04:05Z",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got: %s", want, out)
+		}
+	}
+	if strings.Contains(out, "Export Format") {
+		t.Errorf("expected empty parameters to be omitted, got: %s", out)
+	}
+
+	if err := printVerification("quay.io/org/repo@sha256:abc", []byte("not json")); err == nil {
+		t.Error("expected error for invalid predicate")
+	}
+}
+
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	old := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+
+	fn()
+
+	_ = w.Close()
+	os.Stdout = old
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,153 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/gorilla/websocket"
+	"github.com/spf13/cobra"
+
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/clilog"
+	caibcommon "github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/common"
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+	buildapiclient "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi/client"
+)
+
+var (
+	// debug flags
+	debugBinary  string
+	debugSymbols string
+	debugPort    int
+	debugDAPPort int
+	debugAddress string
+)
+
+func newDebugCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "debug <name> --binary <path> [-- <args>...]",
+		Short: "Debug a program on the leased board with gdb",
+		Long: `Start a program under gdbserver on the board leased by the workspace and
+attach gdb to it from the workspace pod. gdbserver is reached through the
+workspace's Jumpstarter tunnel, so the board image must include gdbserver.
+
+By default an interactive gdb session runs in the terminal. With --dap, caib
+listens on a local port for Debug Adapter Protocol connections instead, so an
+IDE can drive the session; each connection starts the program afresh. Use an
+"attach" request with "target": "127.0.0.1:<port>", where <port> is --port.
+
+gdb reads the program from the board. Pass --symbols with an unstripped copy
+in the workspace to get debug information for a stripped board binary.
+
+Examples:
+  # Interactive session
+  caib workspace debug my-app --binary /usr/local/bin/app -- --verbose
+
+  # With symbols from the workspace build
+  caib workspace debug my-app --binary /usr/local/bin/app --symbols build/app
+
+  # DAP endpoint for an IDE on localhost:4711
+  caib workspace debug my-app --binary /usr/local/bin/app --dap 4711`,
+		Args: cobra.MinimumNArgs(1),
+		Run:  runDebug,
+	}
+
+	cmd.Flags().StringVar(&debugBinary, "binary", "", "absolute path of the program on the board (required)")
+	cmd.Flags().StringVar(&debugSymbols, "symbols", "", "unstripped binary in the workspace, relative to /workspace/src")
+	cmd.Flags().IntVar(&debugPort, "port", buildapitypes.DefaultGDBServerPort, "gdbserver port on the board")
+	cmd.Flags().IntVar(&debugDAPPort, "dap", 0, "serve the Debug Adapter Protocol on this local port")
+	cmd.Flags().StringVar(&debugAddress, "address", "127.0.0.1", "local address to listen on with --dap")
+	_ = cmd.MarkFlagRequired("binary")
+
+	return cmd
+}
+
+// debugRequest builds the debug request from the flags and program arguments.
+func debugRequest(programArgs []string, mode string) buildapitypes.WorkspaceDebugRequest {
+	return buildapitypes.WorkspaceDebugRequest{
+		Binary:  debugBinary,
+		Args:    programArgs,
+		Symbols: debugSymbols,
+		Port:    debugPort,
+		Mode:    mode,
+	}
+}
+
+func runDebug(_ *cobra.Command, args []string) {
+	requireServer()
+	name := args[0]
+
+	if debugDAPPort != 0 {
+		if _, err := parsePort(strconv.Itoa(debugDAPPort)); err != nil {
+			handleError(fmt.Errorf("invalid --dap port: %w", err))
+		}
+		runDebugDAP(name, debugRequest(args[1:], buildapitypes.DebugModeDAP))
+		return
+	}
+
+	req := debugRequest(args[1:], buildapitypes.DebugModeGDB)
+	runTerminalSession("debug", func(ctx context.Context, client *buildapiclient.Client) (*websocket.Conn, error) {
+		return client.DebugWorkspace(ctx, name, req)
+	})
+}
+
+// runDebugDAP serves DAP connections on a local port, one at a time since
+// each session starts gdbserver on the same board port.
+func runDebugDAP(name string, req buildapitypes.WorkspaceDebugRequest) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	addr := net.JoinHostPort(debugAddress, strconv.Itoa(debugDAPPort))
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		handleError(fmt.Errorf("failed to listen on %s: %w", addr, err))
+	}
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+	}()
+
+	clilog.Infof("Serving DAP for %s on %s (gdbserver port %d)\n", req.Binary, addr, req.Port)
+	clilog.Infoln("Press Ctrl+C to stop")
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				handleError(fmt.Errorf("accept failed: %w", err))
+			}
+			return
+		}
+		clilog.Infof("Debug session started from %s\n", conn.RemoteAddr())
+		if err := serveDAPConnection(ctx, conn, name, req); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: debug session failed: %v\n", err)
+		} else {
+			clilog.Infoln("Debug session ended")
+		}
+	}
+}
+
+func serveDAPConnection(ctx context.Context, conn net.Conn, name string, req buildapitypes.WorkspaceDebugRequest) error {
+	defer func() { _ = conn.Close() }()
+
+	var wsConn *websocket.Conn
+	err := caibcommon.ExecuteWithReauth(serverURL, &authToken, insecureSkipTLS, func(client *buildapiclient.Client) error {
+		c, cerr := client.DebugWorkspace(ctx, name, req)
+		if cerr != nil {
+			return cerr
+		}
+		wsConn = c
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = wsConn.Close() }()
+
+	return bridgeConnection(conn, wsConn)
+}
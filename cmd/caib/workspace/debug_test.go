@@ -0,0 +1,33 @@
+package workspace
+
+import (
+	"reflect"
+	"testing"
+
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+)
+
+func TestDebugRequest(t *testing.T) {
+	debugBinary, debugSymbols, debugPort = "/usr/bin/app", "build/app", 3000
+	t.Cleanup(func() { debugBinary, debugSymbols, debugPort = "", "", buildapitypes.DefaultGDBServerPort })
+
+	got := debugRequest([]string{"--verbose"}, buildapitypes.DebugModeDAP)
+	want := buildapitypes.WorkspaceDebugRequest{
+		Binary:  "/usr/bin/app",
+		Args:    []string{"--verbose"},
+		Symbols: "build/app",
+		Port:    3000,
+		Mode:    buildapitypes.DebugModeDAP,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("debugRequest() = %+v, want %+v", got, want)
+	}
+
+	q := got.QueryValues()
+	if q.Get("binary") != "/usr/bin/app" || q.Get("port") != "3000" || q.Get("arg") != "--verbose" {
+		t.Errorf("unexpected query %v", q.Encode())
+	}
+	if q := debugRequest(nil, buildapitypes.DebugModeGDB).QueryValues(); q.Has("arg") {
+		t.Errorf("unexpected arg in query %v", q.Encode())
+	}
+}
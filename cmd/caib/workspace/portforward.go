@@ -0,0 +1,209 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/gorilla/websocket"
+	"github.com/spf13/cobra"
+
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/clilog"
+	caibcommon "github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/common"
+	buildapiclient "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi/client"
+)
+
+var (
+	// port-forward flags
+	portForwardBoard   bool
+	portForwardAddress string
+)
+
+// portMapping forwards a local port to a remote one.
+type portMapping struct {
+	local  int
+	remote int
+}
+
+func newPortForwardCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "port-forward <name> <local>:<remote> [<local>:<remote>...]",
+		Short: "Forward local ports to a workspace or its leased board",
+		Long: `Forward local TCP ports to ports of the workspace pod, or of the board
+leased by the workspace with --board. Connections are tunneled through the
+Build API, so no cluster or lab network access is needed.
+
+A single port number forwards the same local and remote port. Board ports are
+reached over SSH through the workspace's Jumpstarter tunnel, the same way
+'caib workspace deploy' copies files.
+
+Examples:
+  # gdbserver running on the board
+  caib workspace port-forward my-app 2345:2345 --board
+  gdb -ex 'target remote localhost:2345' ./build/app
+
+  # A web UI on the board and a dev server in the workspace
+  caib workspace port-forward my-app 8080:80 --board
+  caib workspace port-forward my-app 3000`,
+		Args: cobra.MinimumNArgs(2),
+		Run:  runPortForward,
+	}
+
+	cmd.Flags().BoolVar(&portForwardBoard, "board", false, "forward to the leased board instead of the workspace pod")
+	cmd.Flags().StringVar(&portForwardAddress, "address", "127.0.0.1", "local address to listen on")
+
+	return cmd
+}
+
+// parsePortMapping parses "<local>:<remote>" or "<port>".
+func parsePortMapping(s string) (portMapping, error) {
+	localStr, remoteStr, found := strings.Cut(s, ":")
+	if !found {
+		remoteStr = localStr
+	}
+	local, err := parsePort(localStr)
+	if err != nil {
+		return portMapping{}, fmt.Errorf("invalid port mapping %q: %w", s, err)
+	}
+	remote, err := parsePort(remoteStr)
+	if err != nil {
+		return portMapping{}, fmt.Errorf("invalid port mapping %q: %w", s, err)
+	}
+	return portMapping{local: local, remote: remote}, nil
+}
+
+func parsePort(s string) (int, error) {
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("port must be between 1 and 65535, got %q", s)
+	}
+	return port, nil
+}
+
+func runPortForward(_ *cobra.Command, args []string) {
+	requireServer()
+	name := args[0]
+
+	var mappings []portMapping
+	for _, arg := range args[1:] {
+		m, err := parsePortMapping(arg)
+		if err != nil {
+			handleError(err)
+		}
+		mappings = append(mappings, m)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	target := "workspace " + name
+	if portForwardBoard {
+		target = "board of workspace " + name
+	}
+
+	var listeners []net.Listener
+	for _, m := range mappings {
+		addr := net.JoinHostPort(portForwardAddress, strconv.Itoa(m.local))
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			handleError(fmt.Errorf("failed to listen on %s: %w", addr, err))
+		}
+		listeners = append(listeners, ln)
+		clilog.Infof("Forwarding from %s -> %s:%d\n", addr, target, m.remote)
+	}
+
+	var dialMu sync.Mutex
+	for i, ln := range listeners {
+		go acceptConnections(ctx, ln, name, mappings[i].remote, &dialMu)
+	}
+	clilog.Infoln("Press Ctrl+C to stop")
+
+	<-ctx.Done()
+	for _, ln := range listeners {
+		_ = ln.Close()
+	}
+}
+
+func acceptConnections(ctx context.Context, ln net.Listener, name string, remote int, dialMu *sync.Mutex) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				fmt.Fprintf(os.Stderr, "Warning: accept failed: %v\n", err)
+			}
+			return
+		}
+		go func() {
+			defer func() { _ = conn.Close() }()
+			if err := forwardConnection(ctx, conn, name, remote, dialMu); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: connection to port %d failed: %v\n", remote, err)
+			}
+		}()
+	}
+}
+
+func forwardConnection(ctx context.Context, conn net.Conn, name string, remote int, dialMu *sync.Mutex) error {
+	// ExecuteWithReauth may refresh the shared token, so dial one at a time
+	var wsConn *websocket.Conn
+	dialMu.Lock()
+	err := caibcommon.ExecuteWithReauth(serverURL, &authToken, insecureSkipTLS, func(client *buildapiclient.Client) error {
+		c, cerr := client.PortForwardWorkspace(ctx, name, remote, portForwardBoard)
+		if cerr != nil {
+			return cerr
+		}
+		wsConn = c
+		return nil
+	})
+	dialMu.Unlock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = wsConn.Close() }()
+
+	return bridgeConnection(conn, wsConn)
+}
+
+// bridgeConnection copies data between a local connection and a port-forward
+// WebSocket until the remote side closes. The end of the local input is sent
+// as an empty message, so that responses still arrive after a half-close.
+func bridgeConnection(conn net.Conn, wsConn *websocket.Conn) error {
+	go func() {
+		buf := make([]byte, 32*1024)
+		for {
+			n, rerr := conn.Read(buf)
+			if n > 0 {
+				if werr := wsConn.WriteMessage(websocket.BinaryMessage, buf[:n]); werr != nil {
+					return
+				}
+			}
+			if rerr != nil {
+				_ = wsConn.WriteMessage(websocket.BinaryMessage, nil)
+				return
+			}
+		}
+	}()
+
+	for {
+		_, msg, err := wsConn.ReadMessage()
+		if err != nil {
+			var closeErr *websocket.CloseError
+			if errors.As(err, &closeErr) {
+				if closeErr.Code == websocket.CloseNormalClosure {
+					return nil
+				}
+				return errors.New(closeErr.Text)
+			}
+			return err
+		}
+		if _, err := conn.Write(msg); err != nil {
+			return err
+		}
+	}
+}
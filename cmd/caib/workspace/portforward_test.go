@@ -0,0 +1,102 @@
+package workspace
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestParsePortMapping(t *testing.T) {
+	for in, want := range map[string]portMapping{
+		"8080:80": {local: 8080, remote: 80},
+		"2345":    {local: 2345, remote: 2345},
+	} {
+		got, err := parsePortMapping(in)
+		if err != nil || got != want {
+			t.Errorf("parsePortMapping(%q) = %+v, %v, want %+v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "http", "0:80", "8080:70000", "8080:"} {
+		if _, err := parsePortMapping(in); err == nil {
+			t.Errorf("parsePortMapping(%q) should fail", in)
+		}
+	}
+}
+
+func TestBridgeConnectionHalfClose(t *testing.T) {
+	// The server echoes data and, once the client's input ends, replies and
+	// closes, like a relay whose remote answers a request after EOF.
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		var received []byte
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if len(msg) == 0 {
+				break
+			}
+			received = append(received, msg...)
+		}
+		_ = conn.WriteMessage(websocket.BinaryMessage, append([]byte("got "), received...))
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	}))
+	defer srv.Close()
+
+	wsConn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = wsConn.Close() }()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	done := make(chan error, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			done <- err
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		done <- bridgeConnection(conn, wsConn)
+	}()
+
+	client, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+	if _, err := client.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.(*net.TCPConn).CloseWrite(); err != nil {
+		t.Fatal(err)
+	}
+
+	reply, err := io.ReadAll(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(reply) != "got ping" {
+		t.Errorf("reply = %q, want %q", reply, "got ping")
+	}
+	if err := <-done; err != nil {
+		t.Errorf("unexpected bridge error: %v", err)
+	}
+}
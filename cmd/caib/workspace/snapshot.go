@@ -0,0 +1,237 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/clilog"
+	caibcommon "github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/common"
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+	buildapiclient "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi/client"
+)
+
+var (
+	// snapshot flags
+	snapshotName       string
+	snapshotMethod     string
+	snapshotTTL        string
+	waitForSnapshot    bool
+	snapshotsWorkspace string
+
+	// create flag
+	fromSnapshot string
+)
+
+func newSnapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot <name>",
+		Short: "Snapshot a workspace's storage",
+		Long: `Snapshot captures the workspace's persistent volume so that new
+workspaces can be cloned from it with 'caib workspace create --from-snapshot'.
+
+A Kubernetes VolumeSnapshot is used when the workspace's storage class
+supports it. Otherwise the volume is exported as a tar archive and pushed
+to the cluster registry as an OCI artifact.
+
+Examples:
+  # Snapshot with a generated name
+  caib workspace snapshot my-app
+
+  # Named snapshot kept for 30 days
+  caib workspace snapshot my-app --name my-app-baseline --ttl 720h
+
+  # Clone a new workspace from it
+  caib workspace create my-app-2 --from-snapshot my-app-baseline`,
+		Args: cobra.ExactArgs(1),
+		Run:  runSnapshot,
+	}
+
+	cmd.Flags().StringVar(&snapshotName, "name", "", "snapshot name (default: <workspace>-<timestamp>)")
+	cmd.Flags().StringVar(&snapshotMethod, "method", "", "snapshot method: VolumeSnapshot or OCI (default: chosen from the storage class)")
+	cmd.Flags().StringVar(&snapshotTTL, "ttl", "", "how long to keep the snapshot, e.g. 72h (\"0\" keeps it forever, default: from OperatorConfig)")
+	cmd.Flags().BoolVarP(&waitForSnapshot, "wait", "w", true, "wait for the snapshot to be ready")
+
+	return cmd
+}
+
+func newSnapshotsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshots",
+		Short: "List workspace snapshots",
+		Long: `List your workspace snapshots, newest first.
+
+Examples:
+  caib workspace snapshots
+  caib workspace snapshots --workspace my-app
+  caib workspace snapshots delete my-app-baseline`,
+		Args: cobra.NoArgs,
+		Run:  runListSnapshots,
+	}
+
+	cmd.Flags().StringVar(&snapshotsWorkspace, "workspace", "", "only list snapshots of this workspace")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "delete <snapshot>",
+		Short: "Delete a workspace snapshot and its stored data",
+		Args:  cobra.ExactArgs(1),
+		Run:   runDeleteSnapshot,
+	})
+
+	return cmd
+}
+
+func runSnapshot(_ *cobra.Command, args []string) {
+	requireServer()
+	name := args[0]
+
+	req := buildapitypes.WorkspaceSnapshotRequest{
+		Name:   snapshotName,
+		Method: snapshotMethod,
+		TTL:    snapshotTTL,
+	}
+
+	var resp *buildapitypes.WorkspaceSnapshotResponse
+	err := caibcommon.ExecuteWithReauth(serverURL, &authToken, insecureSkipTLS, func(client *buildapiclient.Client) error {
+		r, cerr := client.SnapshotWorkspace(context.Background(), name, req)
+		if cerr != nil {
+			return cerr
+		}
+		resp = r
+		return nil
+	})
+	if err != nil {
+		handleError(fmt.Errorf("failed to snapshot workspace: %w", err))
+	}
+
+	clilog.Infof("Snapshot %q of workspace %q requested\n", resp.Name, name)
+	if !waitForSnapshot {
+		clilog.Infof("  Phase:        %s\n", resp.Phase)
+		return
+	}
+
+	snapshot := waitForSnapshotReady(resp.Name)
+	clilog.Infof("Snapshot %q is ready\n", snapshot.Name)
+	clilog.Infof("  Method:       %s\n", snapshot.Method)
+	if snapshot.ArtifactRef != "" {
+		clilog.Infof("  Artifact:     %s\n", snapshot.ArtifactRef)
+	}
+	if snapshot.ExpiresAt != "" {
+		clilog.Infof("  Expires:      %s\n", snapshot.ExpiresAt)
+	}
+	clilog.Infof("Clone it with: caib workspace create <name> --from-snapshot %s\n", snapshot.Name)
+}
+
+func runListSnapshots(_ *cobra.Command, _ []string) {
+	requireServer()
+
+	var snapshots []buildapitypes.WorkspaceSnapshotResponse
+	err := caibcommon.ExecuteWithReauth(serverURL, &authToken, insecureSkipTLS, func(client *buildapiclient.Client) error {
+		s, cerr := client.ListWorkspaceSnapshots(context.Background(), snapshotsWorkspace)
+		if cerr != nil {
+			return cerr
+		}
+		snapshots = s
+		return nil
+	})
+	if err != nil {
+		handleError(fmt.Errorf("failed to list workspace snapshots: %w", err))
+	}
+
+	if len(snapshots) == 0 {
+		fmt.Println("No workspace snapshots found")
+		return
+	}
+
+	if err := writeSnapshotTable(os.Stdout, snapshots); err != nil {
+		handleError(err)
+	}
+}
+
+func runDeleteSnapshot(_ *cobra.Command, args []string) {
+	requireServer()
+	name := args[0]
+
+	err := caibcommon.ExecuteWithReauth(serverURL, &authToken, insecureSkipTLS, func(client *buildapiclient.Client) error {
+		return client.DeleteWorkspaceSnapshot(context.Background(), name)
+	})
+	if err != nil {
+		handleError(fmt.Errorf("failed to delete workspace snapshot: %w", err))
+	}
+
+	clilog.Infof("Workspace snapshot %q deleted\n", name)
+}
+
+// writeSnapshotTable renders workspace snapshots as a table.
+func writeSnapshotTable(out io.Writer, snapshots []buildapitypes.WorkspaceSnapshotResponse) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tWORKSPACE\tPHASE\tMETHOD\tSIZE\tAGE\tEXPIRES")
+	for _, s := range snapshots {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			s.Name, s.Workspace, s.Phase, dashIfEmpty(s.Method), dashIfEmpty(s.PVCSize), dashIfEmpty(s.Age), dashIfEmpty(s.ExpiresAt))
+	}
+	return w.Flush()
+}
+
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// waitForSnapshotReady polls a snapshot until it is ready and exits on failure.
+// Registry exports copy the whole volume, so the timeout is generous.
+func waitForSnapshotReady(name string) *buildapitypes.WorkspaceSnapshotResponse {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	timeout := time.After(30 * time.Minute)
+	ticker := time.NewTicker(3 * time.Second)
+	defer ticker.Stop()
+
+	lastPhase := ""
+	for {
+		select {
+		case <-ctx.Done():
+			handleError(fmt.Errorf("interrupted while waiting for snapshot %q", name))
+		case <-timeout:
+			handleError(caibcommon.NewActionableError(
+				fmt.Errorf("timed out waiting for snapshot %q to be ready", name),
+				"caib workspace snapshots",
+			))
+		case <-ticker.C:
+			var snapshot *buildapitypes.WorkspaceSnapshotResponse
+			err := caibcommon.ExecuteWithReauth(serverURL, &authToken, insecureSkipTLS, func(client *buildapiclient.Client) error {
+				r, cerr := client.GetWorkspaceSnapshot(ctx, name)
+				if cerr != nil {
+					return cerr
+				}
+				snapshot = r
+				return nil
+			})
+			if err != nil {
+				continue // transient error, retry
+			}
+
+			if snapshot.Phase != lastPhase {
+				lastPhase = snapshot.Phase
+				clilog.Infof("  Phase: %s\n", snapshot.Phase)
+			}
+
+			switch snapshot.Phase {
+			case "Ready":
+				return snapshot
+			case "Failed":
+				handleError(fmt.Errorf("snapshot %q failed: %s", name, snapshot.Message))
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package workspace
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+)
+
+func TestWriteSnapshotTable(t *testing.T) {
+	var out bytes.Buffer
+	err := writeSnapshotTable(&out, []buildapitypes.WorkspaceSnapshotResponse{
+		{Name: "my-app-baseline", Workspace: "my-app", Phase: "Ready", Method: "VolumeSnapshot", PVCSize: "200Gi", Age: "1h0m0s"},
+		{Name: "my-app-20260101-100000", Workspace: "my-app", Phase: "Pending"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "NAME") {
+		t.Fatalf("expected header and 2 rows, got:\n%s", out.String())
+	}
+	if fields := strings.Fields(lines[1]); len(fields) != 7 || fields[3] != "VolumeSnapshot" || fields[4] != "200Gi" {
+		t.Errorf("unexpected row %q", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); len(fields) != 7 || fields[3] != "-" || fields[6] != "-" {
+		t.Errorf("expected placeholders for unset columns, got %q", lines[2])
+	}
+}
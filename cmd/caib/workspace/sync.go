@@ -0,0 +1,429 @@
+package workspace
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/clilog"
+	caibcommon "github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/common"
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+	buildapiclient "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi/client"
+)
+
+const (
+	// syncChunkSize is the target size of a single sync upload. Files larger
+	// than this are sent on their own, up to the server's upload limit.
+	syncChunkSize = buildapitypes.MaxSyncUploadSize / 2
+
+	// syncDebounce is how long --watch waits for changes to settle before syncing.
+	syncDebounce = 500 * time.Millisecond
+)
+
+func runSync(_ *cobra.Command, args []string) {
+	requireServer()
+	name := args[0]
+
+	dir := "."
+	if len(args) > 1 {
+		dir = args[1]
+	}
+
+	if len(syncPullPaths) > 0 {
+		if syncWatch {
+			handleError(fmt.Errorf("--watch cannot be combined with --pull"))
+		}
+		runPull(name, dir)
+		return
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		handleError(fmt.Errorf("invalid directory: %w", err))
+	}
+	info, err := os.Stat(absDir)
+	if err != nil || !info.IsDir() {
+		handleError(fmt.Errorf("source directory does not exist or is not a directory: %s", absDir))
+	}
+
+	if syncWatch {
+		watchAndSync(name, absDir)
+		return
+	}
+	if err := syncTree(name, absDir, nil, false); err != nil {
+		handleError(err)
+	}
+}
+
+// syncTree uploads the changed git-tracked files of absDir and removes the
+// files deleted since the last sync. With quiet set, nothing is printed when
+// the workspace is already up to date.
+func syncTree(name, absDir string, cache map[string]hashCacheEntry, quiet bool) error {
+	files, err := gitTrackedFiles(absDir)
+	if err != nil {
+		return fmt.Errorf("failed to list git-tracked files: %w", err)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no git-tracked files found in %s", absDir)
+	}
+
+	manifest := computeManifest(absDir, files, cache)
+	planReq := buildapitypes.SyncPlanRequest{Files: manifest}
+
+	var plan *buildapitypes.SyncPlanResponse
+	err = caibcommon.ExecuteWithReauth(serverURL, &authToken, insecureSkipTLS, func(client *buildapiclient.Client) error {
+		p, cerr := client.SyncPlan(context.Background(), name, planReq)
+		if cerr != nil {
+			return cerr
+		}
+		plan = p
+		return nil
+	})
+	if err != nil {
+		// Fall back to full sync — warn so users know why delta didn't work
+		fmt.Fprintf(os.Stderr, "Warning: sync plan unavailable (%v), uploading all files\n", err)
+		clilog.Infof("Syncing %d tracked files to workspace %q...\n", len(files), name)
+		return uploadFiles(name, absDir, files)
+	}
+
+	var toDelete []string
+	if !syncNoDelete {
+		toDelete = plan.Deleted
+	}
+
+	if len(plan.Changed) == 0 && len(toDelete) == 0 {
+		if !quiet {
+			clilog.Infof("Workspace %q is up to date (%d files)\n", name, plan.Unchanged)
+		}
+	} else if len(plan.Changed) > 0 {
+		clilog.Infof("Syncing %d changed files to workspace %q (%d unchanged)...\n",
+			len(plan.Changed), name, plan.Unchanged)
+		if err := uploadFiles(name, absDir, plan.Changed); err != nil {
+			return err
+		}
+	}
+
+	// Record the synced tree even when nothing changed, so that later
+	// deletions are detected.
+	finishReq := buildapitypes.SyncFinishRequest{Files: sortedKeys(manifest), Delete: toDelete}
+	err = caibcommon.ExecuteWithReauth(serverURL, &authToken, insecureSkipTLS, func(client *buildapiclient.Client) error {
+		_, cerr := client.SyncFinish(context.Background(), name, finishReq)
+		return cerr
+	})
+	if err != nil {
+		return fmt.Errorf("failed to finish sync: %w", err)
+	}
+	if len(toDelete) > 0 {
+		clilog.Infof("Removed %d deleted files from workspace %q\n", len(toDelete), name)
+	}
+	return nil
+}
+
+// uploadFiles uploads files in tar archives of at most syncChunkSize each.
+func uploadFiles(name, absDir string, files []string) error {
+	chunks, err := chunkFiles(absDir, files, syncChunkSize, buildapitypes.MaxSyncUploadSize)
+	if err != nil {
+		return err
+	}
+
+	for i, chunk := range chunks {
+		if len(chunks) > 1 {
+			clilog.Infof("  Part %d/%d (%d files)\n", i+1, len(chunks), len(chunk.files))
+		}
+		err := caibcommon.ExecuteWithReauth(serverURL, &authToken, insecureSkipTLS, func(client *buildapiclient.Client) error {
+			// Stream the archive so that only one chunk is read at a time
+			// and retries start from a fresh archive.
+			pr, pw := io.Pipe()
+			go func() { _ = pw.CloseWithError(tarTrackedFiles(absDir, chunk.files, pw)) }()
+			progress := newProgressReader(pr, chunk.size)
+			err := client.SyncWorkspace(context.Background(), name, progress)
+			progress.finish()
+			_ = pr.Close()
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to sync workspace: %w", err)
+		}
+	}
+	clilog.Infoln("Files synced")
+	return nil
+}
+
+// syncChunk is a group of files uploaded in one tar archive.
+type syncChunk struct {
+	files []string
+	size  int64 // estimated archive size
+}
+
+// chunkFiles splits files into groups whose tar archives stay below
+// chunkSize. A file bigger than chunkSize gets a group of its own, and an
+// error is returned if it cannot fit in a single upload of maxSize.
+func chunkFiles(baseDir string, files []string, chunkSize, maxSize int64) ([]syncChunk, error) {
+	const tarBlock = 512
+	var chunks []syncChunk
+	current := syncChunk{}
+	for _, relPath := range files {
+		fi, err := os.Lstat(filepath.Join(baseDir, relPath))
+		if err != nil {
+			continue // file may have been deleted since ls-files
+		}
+		// Header block, plus a PAX header for long names, plus padded content
+		entry := int64(tarBlock)
+		if len(relPath) > 100 {
+			entry += 2*tarBlock + int64(len(relPath))
+		}
+		if fi.Mode().IsRegular() {
+			entry += (fi.Size() + tarBlock - 1) / tarBlock * tarBlock
+		}
+		// Two zero blocks end each archive
+		if entry+2*tarBlock > maxSize {
+			return nil, fmt.Errorf("%s (%s) exceeds the %s upload limit", relPath, humanSize(fi.Size()), humanSize(maxSize))
+		}
+		if len(current.files) > 0 && current.size+entry+2*tarBlock > chunkSize {
+			current.size += 2 * tarBlock
+			chunks = append(chunks, current)
+			current = syncChunk{}
+		}
+		current.files = append(current.files, relPath)
+		current.size += entry
+	}
+	if len(current.files) > 0 {
+		current.size += 2 * tarBlock
+		chunks = append(chunks, current)
+	}
+	return chunks, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// watchAndSync syncs absDir once, then again each time its files change,
+// until interrupted.
+func watchAndSync(name, absDir string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		handleError(fmt.Errorf("failed to watch %s: %w", absDir, err))
+	}
+	defer func() { _ = watcher.Close() }()
+	if err := addWatchDirs(watcher, absDir); err != nil {
+		handleError(fmt.Errorf("failed to watch %s: %w", absDir, err))
+	}
+
+	cache := make(map[string]hashCacheEntry)
+	if err := syncTree(name, absDir, cache, false); err != nil {
+		handleError(err)
+	}
+	clilog.Infof("Watching %s for changes (Ctrl+C to stop)...\n", absDir)
+
+	debounce := time.NewTimer(syncDebounce)
+	debounce.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if !isSyncRelevant(absDir, event.Name) {
+				continue
+			}
+			if event.Has(fsnotify.Create) {
+				if fi, err := os.Lstat(event.Name); err == nil && fi.IsDir() {
+					_ = addWatchDirs(watcher, event.Name)
+				}
+			}
+			debounce.Reset(syncDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(os.Stderr, "Warning: watch error: %v\n", err)
+		case <-debounce.C:
+			if err := syncTree(name, absDir, cache, true); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", caibcommon.FormatError(err))
+			}
+		}
+	}
+}
+
+// addWatchDirs watches root and every directory below it. Inside .git only
+// the directory itself is watched, to notice changes to the index.
+func addWatchDirs(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if err := watcher.Add(p); err != nil {
+			return err
+		}
+		if d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+// isSyncRelevant reports whether a change to p can affect the synced tree.
+// Within .git only the index matters, as it decides which files are tracked.
+func isSyncRelevant(absDir, p string) bool {
+	rel, err := filepath.Rel(absDir, p)
+	if err != nil {
+		return false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for i, part := range parts {
+		if part == ".git" {
+			return i == len(parts)-2 && parts[i+1] == "index"
+		}
+	}
+	return true
+}
+
+func runPull(name, destDir string) {
+	absDest, err := filepath.Abs(destDir)
+	if err != nil {
+		handleError(fmt.Errorf("invalid directory: %w", err))
+	}
+	if err := os.MkdirAll(absDest, 0o755); err != nil {
+		handleError(fmt.Errorf("failed to create %s: %w", absDest, err))
+	}
+
+	for _, remotePath := range syncPullPaths {
+		var count int
+		err := caibcommon.ExecuteWithReauth(serverURL, &authToken, insecureSkipTLS, func(client *buildapiclient.Client) error {
+			body, cerr := client.PullWorkspace(context.Background(), name, remotePath)
+			if cerr != nil {
+				return cerr
+			}
+			defer func() { _ = body.Close() }()
+			count, cerr = extractTar(body, absDest)
+			return cerr
+		})
+		if err != nil {
+			handleError(fmt.Errorf("failed to pull %s: %w", remotePath, err))
+		}
+		clilog.Infof("Pulled %s from workspace %q into %s (%d files)\n", remotePath, name, absDest, count)
+	}
+}
+
+// extractTar extracts a tar stream into destDir and returns the number of
+// files written. Entries that would land outside destDir, directly or through
+// a symlink, are rejected.
+func extractTar(r io.Reader, destDir string) (int, error) {
+	root, err := filepath.EvalSymlinks(destDir)
+	if err != nil {
+		return 0, err
+	}
+
+	tr := tar.NewReader(r)
+	count := 0
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, fmt.Errorf("reading archive: %w", err)
+		}
+
+		target := filepath.Join(root, filepath.FromSlash(hdr.Name))
+		if !isWithin(root, target) {
+			return count, fmt.Errorf("archive entry %q is outside the destination", hdr.Name)
+		}
+		if target == root {
+			continue
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := mkdirWithin(root, target); err != nil {
+				return count, err
+			}
+		case tar.TypeReg:
+			if err := mkdirWithin(root, filepath.Dir(target)); err != nil {
+				return count, err
+			}
+			// Replace rather than write through an existing symlink
+			if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return count, err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_EXCL, os.FileMode(hdr.Mode)&0o777)
+			if err != nil {
+				return count, err
+			}
+			_, err = io.Copy(f, tr)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return count, fmt.Errorf("writing %s: %w", hdr.Name, err)
+			}
+			count++
+		case tar.TypeSymlink:
+			linkTarget := filepath.Join(filepath.Dir(target), hdr.Linkname)
+			if filepath.IsAbs(hdr.Linkname) || !isWithin(root, linkTarget) {
+				fmt.Fprintf(os.Stderr, "Warning: skipping symlink %s -> %s pointing outside the destination\n", hdr.Name, hdr.Linkname)
+				continue
+			}
+			if err := mkdirWithin(root, filepath.Dir(target)); err != nil {
+				return count, err
+			}
+			if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return count, err
+			}
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return count, err
+			}
+		}
+	}
+}
+
+// mkdirWithin creates dir and checks that, after resolving symlinks, it is
+// still inside root.
+func mkdirWithin(root, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	if !isWithin(root, resolved) {
+		return fmt.Errorf("%s resolves outside the destination", dir)
+	}
+	return nil
+}
+
+func isWithin(root, p string) bool {
+	rel, err := filepath.Rel(root, p)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,189 @@
+package workspace
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name string, size int) {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, bytes.Repeat([]byte("x"), size), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChunkFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a", 3000)
+	writeFile(t, dir, "b", 3000)
+	writeFile(t, dir, "c", 100)
+	writeFile(t, dir, "big", 9000)
+
+	chunks, err := chunkFiles(dir, []string{"a", "b", "c", "missing", "big"}, 8192, 16384)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got [][]string
+	for _, c := range chunks {
+		got = append(got, c.files)
+		if c.size > 8192 && len(c.files) > 1 {
+			t.Errorf("chunk %v is %d bytes, above the chunk size", c.files, c.size)
+		}
+	}
+	want := "[[a b] [c] [big]]"
+	if s := fmtChunks(got); s != want {
+		t.Errorf("chunkFiles() = %s, want %s", s, want)
+	}
+
+	// The estimated size must match the archive actually produced
+	var buf bytes.Buffer
+	if err := tarTrackedFiles(dir, chunks[0].files, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if int64(buf.Len()) > chunks[0].size {
+		t.Errorf("archive is %d bytes, estimated %d", buf.Len(), chunks[0].size)
+	}
+
+	if _, err := chunkFiles(dir, []string{"big"}, 4096, 8192); err == nil {
+		t.Error("expected an error for a file above the upload limit")
+	}
+}
+
+func fmtChunks(chunks [][]string) string {
+	parts := make([]string, 0, len(chunks))
+	for _, c := range chunks {
+		parts = append(parts, "["+strings.Join(c, " ")+"]")
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+func TestComputeManifestCache(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "main.c", 10)
+
+	cache := make(map[string]hashCacheEntry)
+	first := computeManifest(dir, []string{"main.c", "gone.c"}, cache)
+	if len(first) != 1 || first["main.c"] == "" {
+		t.Fatalf("unexpected manifest %v", first)
+	}
+
+	// A stale cache entry is used as long as size and mtime match
+	entry := cache["main.c"]
+	entry.sum = "cached"
+	cache["main.c"] = entry
+	if got := computeManifest(dir, []string{"main.c"}, cache)["main.c"]; got != "cached" {
+		t.Errorf("expected the cached checksum, got %q", got)
+	}
+
+	writeFile(t, dir, "main.c", 20)
+	if got := computeManifest(dir, []string{"main.c"}, cache)["main.c"]; got == "cached" || got == first["main.c"] {
+		t.Errorf("expected a new checksum after the file changed, got %q", got)
+	}
+}
+
+func TestIsSyncRelevant(t *testing.T) {
+	root := "/src"
+	for p, want := range map[string]bool{
+		"/src/main.c":              true,
+		"/src/lib/util.c":          true,
+		"/src/.git/index":          true,
+		"/src/.git/index.lock":     false,
+		"/src/.git/objects/ab/cd":  false,
+		"/src/vendor/x/.git/index": true,
+	} {
+		if got := isSyncRelevant(root, p); got != want {
+			t.Errorf("isSyncRelevant(%q) = %v, want %v", p, got, want)
+		}
+	}
+}
+
+type tarEntry struct {
+	name, link, body string
+	typ              byte
+}
+
+func makeTar(t *testing.T, entries []tarEntry) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Linkname: e.link, Typeflag: e.typ, Mode: 0o644, Size: int64(len(e.body))}
+		if e.typ == tar.TypeDir {
+			hdr.Mode = 0o755
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if e.body != "" {
+			if _, err := tw.Write([]byte(e.body)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestExtractTar(t *testing.T) {
+	dest := t.TempDir()
+	archive := makeTar(t, []tarEntry{
+		{name: "build/", typ: tar.TypeDir},
+		{name: "build/app", typ: tar.TypeReg, body: "binary"},
+		{name: "build/app-link", typ: tar.TypeSymlink, link: "app"},
+		{name: "build/escape", typ: tar.TypeSymlink, link: "../../etc"},
+	})
+
+	count, err := extractTar(archive, dest)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 file, got %d", count)
+	}
+	if data, err := os.ReadFile(filepath.Join(dest, "build", "app")); err != nil || string(data) != "binary" {
+		t.Errorf("unexpected content %q (%v)", data, err)
+	}
+	if link, err := os.Readlink(filepath.Join(dest, "build", "app-link")); err != nil || link != "app" {
+		t.Errorf("unexpected symlink %q (%v)", link, err)
+	}
+	if _, err := os.Lstat(filepath.Join(dest, "build", "escape")); err == nil {
+		t.Error("symlink pointing outside the destination must be skipped")
+	}
+}
+
+func TestExtractTarRejectsTraversal(t *testing.T) {
+	for _, entries := range [][]tarEntry{
+		{{name: "../outside", typ: tar.TypeReg, body: "x"}},
+		// A symlinked directory planted by an earlier pull must not be written through
+		{{name: "out/file", typ: tar.TypeReg, body: "x"}},
+	} {
+		parent := t.TempDir()
+		dest := filepath.Join(parent, "dest")
+		if err := os.MkdirAll(dest, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(parent, filepath.Join(dest, "out")); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := extractTar(makeTar(t, entries), dest); err == nil {
+			t.Errorf("expected %s to be rejected", entries[0].name)
+		}
+		if _, err := os.Stat(filepath.Join(parent, "outside")); err == nil {
+			t.Errorf("%s was written outside the destination", entries[0].name)
+		}
+		if _, err := os.Stat(filepath.Join(parent, "file")); err == nil {
+			t.Errorf("%s was written outside the destination", entries[0].name)
+		}
+	}
+}
@@ -4,7 +4,6 @@ package workspace
 import (
 	"archive/tar"
 	"bufio"
-	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/base64"
@@ -42,6 +41,8 @@ var (
 	architecture     string
 	toolchainImage   string
 	clientConfigFile string
+	boardTarget      string
+	boardCredentials string
 
 	// resource flags
 	cpuRequest    string
@@ -56,6 +57,11 @@ var (
 
 	// deploy flags
 	artifactMappings []string
+
+	// sync flags
+	syncPullPaths []string
+	syncWatch     bool
+	syncNoDelete  bool
 )
 
 // NewWorkspaceCmd creates the workspace command with subcommands.
@@ -94,7 +100,11 @@ Examples:
 		newSyncCmd(),
 		newExecCmd(),
 		newShellCmd(),
+		newPortForwardCmd(),
+		newDebugCmd(),
 		newDeployCmd(),
+		newSnapshotCmd(),
+		newSnapshotsCmd(),
 	)
 
 	return cmd
@@ -117,7 +127,10 @@ Examples:
   caib workspace create my-app --from-build my-os-build --client ~/.config/jumpstarter/clients/myboard.yaml
 
   # Workspace with explicit lease and architecture
-  caib workspace create my-app --lease lease-abc123 --arch amd64 --client ~/.config/jumpstarter/clients/myboard.yaml`,
+  caib workspace create my-app --lease lease-abc123 --arch amd64 --client ~/.config/jumpstarter/clients/myboard.yaml
+
+  # Clone the storage of a workspace snapshot
+  caib workspace create my-app-2 --from-snapshot my-app-baseline`,
 		Args: cobra.ExactArgs(1),
 		Run:  runCreate,
 	}
@@ -129,6 +142,9 @@ Examples:
 	cmd.Flags().StringVar(&clientConfigFile, "client", "", "path to Jumpstarter client config file")
 	cmd.Flags().StringVar(&cpuRequest, "cpu", "", "CPU request/limit (e.g., \"1\", \"500m\")")
 	cmd.Flags().StringVar(&memoryRequest, "memory", "", "memory request/limit (e.g., \"2Gi\", \"512Mi\")")
+	cmd.Flags().StringVar(&fromSnapshot, "from-snapshot", "", "workspace snapshot to clone storage, architecture and image from")
+	cmd.Flags().StringVar(&boardTarget, "target", "", "board target, selects the board credentials of its Jumpstarter target mapping (default: from --from-build)")
+	cmd.Flags().StringVar(&boardCredentials, "board-credentials", "", "secret with the SSH credentials of the leased board (default: from the target mapping)")
 	cmd.Flags().BoolVar(&tmpfsBuildDir, "tmpfs", false, "mount a tmpfs volume at /tmp/build for faster compilation (uses RAM)")
 	cmd.Flags().IntVar(&autoPauseTimeout, "auto-pause-timeout", -1, "auto-pause timeout in minutes (0=disable, -1=use global default)")
 	cmd.Flags().BoolVarP(&waitForRunningFlag, "wait", "w", true, "wait for workspace to be running")
@@ -167,7 +183,7 @@ func newStartCmd() *cobra.Command {
 		Use:   "start <name>",
 		Short: "Start a stopped workspace",
 		Long: `Start a previously stopped workspace by recreating its pod.
-The workspace's persistent storage (source code, build cache, SSH keys) is preserved.
+The workspace's persistent storage (source code, build cache) and SSH key are preserved.
 
 Examples:
   caib workspace start my-app`,
@@ -194,18 +210,36 @@ Examples:
 }
 
 func newSyncCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "sync <name> [directory]",
-		Short: "Upload local source directory to a workspace",
+		Short: "Sync a local source directory with a workspace",
 		Long: `Sync uploads a local directory to the workspace's /workspace/src/ path.
 If no directory is specified, the current directory is used.
 
+Only git-tracked files are uploaded, and only those that changed since the
+last sync. Files removed locally since the last sync are removed from the
+workspace too, unless --no-delete is given. Large trees are uploaded in
+several parts.
+
+With --watch, sync keeps running and uploads changes as they are saved.
+
+With --pull, files are copied the other way: each path of the workspace is
+downloaded into the directory. Relative paths are taken from /workspace/src.
+
 Examples:
   caib workspace sync my-app ./src
-  caib workspace sync my-app`,
+  caib workspace sync my-app
+  caib workspace sync my-app --watch
+  caib workspace sync my-app ./out --pull build/app --pull build/lib`,
 		Args: cobra.RangeArgs(1, 2),
 		Run:  runSync,
 	}
+
+	cmd.Flags().StringArrayVar(&syncPullPaths, "pull", nil, "workspace path to download instead of uploading (repeatable)")
+	cmd.Flags().BoolVar(&syncWatch, "watch", false, "keep syncing changes until interrupted")
+	cmd.Flags().BoolVar(&syncNoDelete, "no-delete", false, "do not remove files deleted locally from the workspace")
+
+	return cmd
 }
 
 func newExecCmd() *cobra.Command {
@@ -285,15 +319,18 @@ func runCreate(_ *cobra.Command, args []string) {
 	}
 
 	req := buildapitypes.WorkspaceRequest{
-		Name:          name,
-		FromBuild:     fromBuild,
-		Lease:         leaseID,
-		Arch:          architecture,
-		Image:         toolchainImage,
-		ClientConfig:  clientConfigB64,
-		CPU:           cpuRequest,
-		Memory:        memoryRequest,
-		TmpfsBuildDir: tmpfsBuildDir,
+		Name:             name,
+		FromBuild:        fromBuild,
+		Lease:            leaseID,
+		Arch:             architecture,
+		Image:            toolchainImage,
+		ClientConfig:     clientConfigB64,
+		CPU:              cpuRequest,
+		Memory:           memoryRequest,
+		TmpfsBuildDir:    tmpfsBuildDir,
+		FromSnapshot:     fromSnapshot,
+		Target:           boardTarget,
+		BoardCredentials: boardCredentials,
 	}
 	if autoPauseTimeout < -1 {
 		handleError(fmt.Errorf("--auto-pause-timeout must be >= -1"))
@@ -318,9 +355,15 @@ func runCreate(_ *cobra.Command, args []string) {
 
 	clilog.Infof("Workspace %q created\n", resp.Name)
 	clilog.Infof("  Architecture: %s\n", resp.Arch)
+	if resp.FromSnapshot != "" {
+		clilog.Infof("  Snapshot:     %s\n", resp.FromSnapshot)
+	}
 	if resp.Lease != "" {
 		clilog.Infof("  Lease:        %s\n", resp.Lease)
 	}
+	if resp.BoardCredentials != "" {
+		clilog.Infof("  Credentials:  %s\n", resp.BoardCredentials)
+	}
 
 	if waitForRunningFlag {
 		waitForRunning(resp.Name)
@@ -390,6 +433,9 @@ func runShow(_ *cobra.Command, args []string) {
 	if ws.Lease != "" {
 		fmt.Printf("Lease:        %s\n", ws.Lease)
 	}
+	if ws.BoardCredentials != "" {
+		fmt.Printf("Credentials:  %s\n", ws.BoardCredentials)
+	}
 	if ws.Age != "" {
 		fmt.Printf("Age:          %s\n", ws.Age)
 	}
@@ -458,92 +504,31 @@ func runStop(_ *cobra.Command, args []string) {
 	clilog.Infof("Workspace %q stopped (storage preserved)\n", resp.Name)
 }
 
-func runSync(_ *cobra.Command, args []string) {
-	requireServer()
-	name := args[0]
-
-	srcDir := "."
-	if len(args) > 1 {
-		srcDir = args[1]
-	}
-
-	absDir, err := filepath.Abs(srcDir)
-	if err != nil {
-		handleError(fmt.Errorf("invalid directory: %w", err))
-	}
-	info, err := os.Stat(absDir)
-	if err != nil || !info.IsDir() {
-		handleError(fmt.Errorf("source directory does not exist or is not a directory: %s", absDir))
-	}
-
-	files, err := gitTrackedFiles(absDir)
-	if err != nil {
-		handleError(fmt.Errorf("failed to list git-tracked files: %w", err))
-	}
-	if len(files) == 0 {
-		handleError(fmt.Errorf("no git-tracked files found in %s", absDir))
-	}
-
-	manifest := computeManifest(absDir, files)
-	planReq := buildapitypes.SyncPlanRequest{Files: manifest}
-
-	var plan *buildapitypes.SyncPlanResponse
-	err = caibcommon.ExecuteWithReauth(serverURL, &authToken, insecureSkipTLS, func(client *buildapiclient.Client) error {
-		p, cerr := client.SyncPlan(context.Background(), name, planReq)
-		if cerr != nil {
-			return cerr
-		}
-		plan = p
-		return nil
-	})
-	if err != nil {
-		// Fall back to full sync — warn so users know why delta didn't work
-		fmt.Fprintf(os.Stderr, "Warning: sync plan unavailable (%v), uploading all files\n", err)
-		clilog.Infof("Syncing %d tracked files to workspace %q...\n", len(files), name)
-		uploadFiles(name, absDir, files)
-		return
-	}
-
-	if len(plan.Changed) == 0 {
-		clilog.Infof("Workspace %q is up to date (%d files)\n", name, plan.Unchanged)
-		return
-	}
-
-	clilog.Infof("Syncing %d changed files to workspace %q (%d unchanged)...\n",
-		len(plan.Changed), name, plan.Unchanged)
-	uploadFiles(name, absDir, plan.Changed)
-}
-
-func uploadFiles(name, absDir string, files []string) {
-	var buf bytes.Buffer
-	if err := tarTrackedFiles(absDir, files, &buf); err != nil {
-		handleError(fmt.Errorf("failed to create tar archive: %w", err))
-	}
-
-	totalBytes := int64(buf.Len())
-	archive := buf.Bytes()
-
-	var pr *progressReader
-	err := caibcommon.ExecuteWithReauth(serverURL, &authToken, insecureSkipTLS, func(client *buildapiclient.Client) error {
-		pr = newProgressReader(bytes.NewReader(archive), totalBytes)
-		err := client.SyncWorkspace(context.Background(), name, pr)
-		pr.finish()
-		return err
-	})
-	if err != nil {
-		handleError(fmt.Errorf("failed to sync workspace: %w", err))
-	}
-	clilog.Infoln("Files synced")
+// hashCacheEntry remembers the checksum of a file for a given size and mtime.
+type hashCacheEntry struct {
+	size    int64
+	modTime time.Time
+	sum     string
 }
 
-func computeManifest(baseDir string, files []string) map[string]string {
+// computeManifest hashes the given files. When cache is not nil, files whose
+// size and mtime are unchanged since the previous call are not read again.
+func computeManifest(baseDir string, files []string, cache map[string]hashCacheEntry) map[string]string {
 	manifest := make(map[string]string, len(files))
 	for _, relPath := range files {
 		absPath := filepath.Join(baseDir, relPath)
-		f, err := os.Open(absPath)
+		fi, err := os.Stat(absPath)
 		if err != nil {
 			continue // file may have been deleted since ls-files
 		}
+		if entry, ok := cache[relPath]; ok && entry.size == fi.Size() && entry.modTime.Equal(fi.ModTime()) {
+			manifest[relPath] = entry.sum
+			continue
+		}
+		f, err := os.Open(absPath)
+		if err != nil {
+			continue
+		}
 		h := sha256.New()
 		_, err = io.Copy(h, f)
 		_ = f.Close()
@@ -551,6 +536,9 @@ func computeManifest(baseDir string, files []string) map[string]string {
 			continue
 		}
 		manifest[relPath] = hex.EncodeToString(h.Sum(nil))
+		if cache != nil {
+			cache[relPath] = hashCacheEntry{size: fi.Size(), modTime: fi.ModTime(), sum: manifest[relPath]}
+		}
 	}
 	return manifest
 }
@@ -657,6 +645,14 @@ func runShell(_ *cobra.Command, args []string) {
 	requireServer()
 	name := args[0]
 
+	runTerminalSession("shell", func(ctx context.Context, client *buildapiclient.Client) (*websocket.Conn, error) {
+		return client.ShellWorkspace(ctx, name)
+	})
+}
+
+// runTerminalSession connects the local terminal in raw mode to an
+// interactive WebSocket session opened by dial.
+func runTerminalSession(operation string, dial func(context.Context, *buildapiclient.Client) (*websocket.Conn, error)) {
 	// Set terminal to raw mode
 	fd := int(os.Stdin.Fd())
 	if !term.IsTerminal(fd) {
@@ -682,7 +678,7 @@ func runShell(_ *cobra.Command, args []string) {
 
 	var ws *websocket.Conn
 	err = caibcommon.ExecuteWithReauth(serverURL, &authToken, insecureSkipTLS, func(client *buildapiclient.Client) error {
-		conn, cerr := client.ShellWorkspace(ctx, name)
+		conn, cerr := dial(ctx, client)
 		if cerr != nil {
 			return cerr
 		}
@@ -691,7 +687,7 @@ func runShell(_ *cobra.Command, args []string) {
 	})
 	if err != nil {
 		_ = term.Restore(fd, oldState)
-		handleError(fmt.Errorf("shell failed: %w", err))
+		handleError(fmt.Errorf("%s failed: %w", operation, err))
 	}
 	defer func() { _ = ws.Close() }()
 
@@ -717,7 +713,7 @@ func runShell(_ *cobra.Command, args []string) {
 		if rerr != nil {
 			if closeErr, ok := rerr.(*websocket.CloseError); ok && closeErr.Code != websocket.CloseNormalClosure {
 				_ = term.Restore(fd, oldState)
-				fmt.Fprintf(os.Stderr, "shell error: %s\n", closeErr.Text)
+				fmt.Fprintf(os.Stderr, "%s error: %s\n", operation, closeErr.Text)
 			}
 			return
 		}
@@ -885,7 +881,7 @@ func (p *progressReader) Read(b []byte) (int, error) {
 	n, err := p.r.Read(b)
 	p.read += int64(n)
 	if p.total > 0 {
-		pct := int(p.read * 100 / p.total)
+		pct := int(min(p.read*100/p.total, 100)) // total may be an estimate
 		if pct != p.last {
 			p.last = pct
 			p.render(pct)
@@ -43,6 +43,9 @@ func main() {
 		tasks.GeneratePushArtifactRegistryTask("", nil),
 		tasks.GeneratePrepareBuilderTask("", nil),
 		tasks.GenerateFlashTask("", nil),
+		tasks.GeneratePushImageIndexTask("", nil),
+		tasks.GeneratePushArtifactStorageTask("", nil),
+		tasks.GenerateSignArtifactsTask("", nil),
 	}
 	taskList = append(taskList, tasks.GenerateSealedTasks("")...)
 
@@ -46,14 +46,19 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 
 	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/notifications"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/telemetry"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/catalogimage"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/containerbuild"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/controllerutils"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/flashjob"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/image"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/imagebuild"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/imagereseal"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/lease"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/operatorconfig"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/workspace"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/workspacesnapshot"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -189,15 +194,6 @@ func main() {
 		LeaderElectionID:       leaderElectionID,
 	}
 
-	// Set namespace scope if specified
-	if watchNamespace != "" {
-		mgrOptions.Cache = cache.Options{
-			DefaultNamespaces: map[string]cache.Config{
-				watchNamespace: {},
-			},
-		}
-	}
-
 	restConfig := ctrl.GetConfigOrDie()
 	// controller-runtime v0.21.0 no longer sets client-side rate limits by default.
 	// Restore the previous defaults to avoid throttling under the more restrictive
@@ -205,6 +201,19 @@ func main() {
 	restConfig.QPS = 20
 	restConfig.Burst = 30
 
+	// Set namespace scope if specified, including the Build API tenant namespaces
+	if watchNamespace != "" {
+		defaultNamespaces := map[string]cache.Config{
+			watchNamespace: {},
+		}
+		for _, namespace := range readTenantNamespaces(restConfig, scheme, watchNamespace) {
+			defaultNamespaces[namespace] = cache.Config{}
+		}
+		mgrOptions.Cache = cache.Options{
+			DefaultNamespaces: defaultNamespaces,
+		}
+	}
+
 	shutdownTracing := func(context.Context) error { return nil }
 	tracingEnabled, tracingEndpoint, tracingSamplingRatio, tracingInsecure := readTracingConfig(restConfig, scheme, watchNamespace)
 	if tracingEnabled {
@@ -230,9 +239,10 @@ func main() {
 	// Register controllers based on mode
 	if mode == modePlatform || mode == modeAll {
 		operatorConfigReconciler := &operatorconfig.OperatorConfigReconciler{
-			Client: mgr.GetClient(),
-			Scheme: mgr.GetScheme(),
-			Log:    ctrl.Log.WithName("controllers").WithName("OperatorConfig"),
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Scheme:    mgr.GetScheme(),
+			Log:       ctrl.Log.WithName("controllers").WithName("OperatorConfig"),
 		}
 
 		if err = operatorConfigReconciler.SetupWithManager(mgr); err != nil {
@@ -242,6 +252,13 @@ func main() {
 	}
 
 	if mode == modeBuild || mode == modeAll {
+		notifier := notifications.New(
+			mgr.GetClient(), ctrl.Log.WithName("notifications"), controllerutils.OperatorNamespace())
+		if err = notifier.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to set up notifications")
+			os.Exit(1)
+		}
+
 		imageBuildReconciler := &imagebuild.ImageBuildReconciler{
 			Client:     mgr.GetClient(),
 			APIReader:  mgr.GetAPIReader(),
@@ -249,6 +266,7 @@ func main() {
 			Log:        ctrl.Log.WithName("controllers").WithName("ImageBuild"),
 			Recorder:   mgr.GetEventRecorderFor("imagebuild-controller"),
 			RestConfig: mgr.GetConfig(),
+			Notifier:   notifier,
 		}
 
 		if err = imageBuildReconciler.SetupWithManager(mgr); err != nil {
@@ -256,10 +274,21 @@ func main() {
 			os.Exit(1)
 		}
 
+		// Image and CatalogImage share one registry client so a failing registry
+		// trips a single circuit breaker for both controllers.
+		registryClient := catalogimage.NewCircuitBreakerRegistryClient(
+			catalogimage.NewRegistryClient(),
+			catalogimage.NewCircuitBreakerRegistry(
+				catalogimage.DefaultCircuitBreakerConfig(),
+				ctrl.Log.WithName("controllers").WithName("Registry"),
+			),
+		)
+
 		imageReconciler := &image.ImageReconciler{
-			Client: mgr.GetClient(),
-			Scheme: mgr.GetScheme(),
-			Log:    ctrl.Log.WithName("controllers").WithName("Image"),
+			Client:         mgr.GetClient(),
+			Scheme:         mgr.GetScheme(),
+			Log:            ctrl.Log.WithName("controllers").WithName("Image"),
+			RegistryClient: registryClient,
 		}
 
 		if err = imageReconciler.SetupWithManager(mgr); err != nil {
@@ -268,9 +297,11 @@ func main() {
 		}
 
 		catalogImageReconciler := &catalogimage.CatalogImageReconciler{
-			Client: mgr.GetClient(),
-			Scheme: mgr.GetScheme(),
-			Log:    ctrl.Log.WithName("controllers").WithName("CatalogImage"),
+			Client:         mgr.GetClient(),
+			Scheme:         mgr.GetScheme(),
+			Log:            ctrl.Log.WithName("controllers").WithName("CatalogImage"),
+			RegistryClient: registryClient,
+			Notifier:       notifier,
 		}
 
 		if err = catalogImageReconciler.SetupWithManager(mgr); err != nil {
@@ -283,6 +314,7 @@ func main() {
 			Scheme:   mgr.GetScheme(),
 			Log:      ctrl.Log.WithName("controllers").WithName("ContainerBuild"),
 			Recorder: mgr.GetEventRecorderFor("containerbuild-controller"),
+			Notifier: notifier,
 		}
 
 		if err = containerBuildReconciler.SetupWithManager(mgr); err != nil {
@@ -295,12 +327,25 @@ func main() {
 			Scheme:   mgr.GetScheme(),
 			Log:      ctrl.Log.WithName("controllers").WithName("ImageReseal"),
 			Recorder: mgr.GetEventRecorderFor("imagereseal-controller"),
+			Notifier: notifier,
 		}
 		if err = imageResealReconciler.SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "ImageReseal")
 			os.Exit(1)
 		}
 
+		flashJobReconciler := &flashjob.Reconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Log:      ctrl.Log.WithName("controllers").WithName("FlashJob"),
+			Recorder: mgr.GetEventRecorderFor("flashjob-controller"),
+			Notifier: notifier,
+		}
+		if err = flashJobReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "FlashJob")
+			os.Exit(1)
+		}
+
 		workspaceReconciler := &workspace.Reconciler{
 			Client:     mgr.GetClient(),
 			Scheme:     mgr.GetScheme(),
@@ -311,6 +356,26 @@ func main() {
 			setupLog.Error(err, "unable to create controller", "controller", "Workspace")
 			os.Exit(1)
 		}
+
+		workspaceSnapshotReconciler := &workspacesnapshot.Reconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Log:      ctrl.Log.WithName("controllers").WithName("WorkspaceSnapshot"),
+			Recorder: mgr.GetEventRecorderFor("workspacesnapshot-controller"),
+		}
+		if err = workspaceSnapshotReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "WorkspaceSnapshot")
+			os.Exit(1)
+		}
+
+		leaseReconciler := &lease.Reconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("Lease"),
+		}
+		if err = leaseReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Lease")
+			os.Exit(1)
+		}
 	}
 
 	// Health checks
@@ -330,6 +395,22 @@ func main() {
 	}
 }
 
+// readTenantNamespaces returns the Build API tenant namespaces configured in the
+// OperatorConfig, so that the controllers watch them too. Tenants added later
+// are only watched after a restart.
+func readTenantNamespaces(restConfig *rest.Config, s *runtime.Scheme, namespace string) []string {
+	c, err := client.New(restConfig, client.Options{Scheme: s})
+	if err != nil {
+		setupLog.Info("could not create startup client for tenant namespaces")
+		return nil
+	}
+	namespaces := controllerutils.TenantNamespaces(context.Background(), c, namespace)
+	if len(namespaces) > 0 {
+		setupLog.Info("watching tenant namespaces", "namespaces", namespaces)
+	}
+	return namespaces
+}
+
 func readTracingConfig(restConfig *rest.Config, s *runtime.Scheme, namespace string) (bool, string, float64, bool) {
 	c, err := client.New(restConfig, client.Options{Scheme: s})
 	if err != nil {
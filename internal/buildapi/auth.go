@@ -16,9 +16,16 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// requestIdentity is the authenticated caller of a request.
+type requestIdentity struct {
+	username string
+	groups   []string
+	authType string
+}
+
 func (a *APIServer) authMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		username, authType, authErr := a.authenticateRequest(c)
+		identity, authErr := a.authenticateRequest(c)
 		if authErr != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error":   "unauthorized",
@@ -28,9 +35,13 @@ func (a *APIServer) authMiddleware() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
-		if username != "" {
-			c.Set("requester", username)
-			c.Set("authType", authType)
+		if identity.username != "" {
+			c.Set("requester", identity.username)
+			c.Set("groups", identity.groups)
+			c.Set("authType", identity.authType)
+		}
+		if !a.resolveTenant(c) {
+			return
 		}
 		c.Next()
 	}
@@ -63,6 +74,13 @@ func (a *APIServer) refreshAuthConfigIfNeeded() {
 		return
 	}
 
+	a.tenancy = nil
+	a.authorization = nil
+	if operatorConfig.Spec.BuildAPI != nil {
+		a.tenancy = operatorConfig.Spec.BuildAPI.Tenancy
+		a.authorization = operatorConfig.Spec.BuildAPI.Authorization
+	}
+
 	// Build new config from OperatorConfig (without creating authenticator yet)
 	var newConfig *AuthenticationConfiguration
 	if operatorConfig.Spec.BuildAPI != nil && operatorConfig.Spec.BuildAPI.Authentication != nil {
@@ -122,12 +140,12 @@ func (a *APIServer) refreshAuthConfigIfNeeded() {
 	a.externalJWT = authn
 }
 
-func (a *APIServer) authenticateRequest(c *gin.Context) (string, string, *authError) {
+func (a *APIServer) authenticateRequest(c *gin.Context) (*requestIdentity, *authError) {
 	a.refreshAuthConfigIfNeeded()
 
 	token := extractBearerToken(c)
 	if token == "" {
-		return "", "", &authError{
+		return nil, &authError{
 			Reason:  "missing_token",
 			Details: "No bearer token provided. Set Authorization header with 'Bearer <token>' or use CAIB_TOKEN environment variable.",
 		}
@@ -145,12 +163,12 @@ func (a *APIServer) authenticateRequest(c *gin.Context) (string, string, *authEr
 
 	if internalJWT != nil {
 		authAttempts = append(authAttempts, "internal_jwt")
-		if subject, ok := validateInternalJWT(token, internalJWT); ok {
-			username := subject
+		if claims, ok := parseInternalJWT(token, internalJWT); ok {
+			username := claims.Subject
 			if internalPrefix != "" {
 				username = internalPrefix + username
 			}
-			return username, "internal", nil
+			return &requestIdentity{username: username, groups: claims.Groups, authType: "internal"}, nil
 		}
 	}
 
@@ -159,11 +177,11 @@ func (a *APIServer) authenticateRequest(c *gin.Context) (string, string, *authEr
 		result := a.authenticateExternalJWT(c, token, externalJWT)
 		if result.ok {
 			if internalJWT != nil {
-				if err := a.ensureClientTokenSecret(c, result.username, token); err != nil {
+				if err := a.ensureClientTokenSecret(c, result.username, result.groups, token); err != nil {
 					a.log.Error(err, "failed to ensure client token secret", "username", result.username)
 				}
 			}
-			return result.username, "external", nil
+			return &requestIdentity{username: result.username, groups: result.groups, authType: "external"}, nil
 		}
 		oidcError = result.err
 	}
@@ -174,7 +192,7 @@ func (a *APIServer) authenticateRequest(c *gin.Context) (string, string, *authEr
 	cfg, err := getRESTConfigFromRequest(c)
 	if err != nil {
 		a.log.Error(err, "Failed to get REST config for TokenReview fallback")
-		return "", "", &authError{
+		return nil, &authError{
 			Reason:  "server_error",
 			Details: "Failed to initialize Kubernetes client for token validation. Check build-api logs.",
 		}
@@ -183,7 +201,7 @@ func (a *APIServer) authenticateRequest(c *gin.Context) (string, string, *authEr
 	clientset, err := kubernetes.NewForConfig(cfg)
 	if err != nil {
 		a.log.Error(err, "Failed to create Kubernetes client for TokenReview")
-		return "", "", &authError{
+		return nil, &authError{
 			Reason:  "server_error",
 			Details: "Failed to create Kubernetes client for token validation. Check build-api logs.",
 		}
@@ -193,7 +211,7 @@ func (a *APIServer) authenticateRequest(c *gin.Context) (string, string, *authEr
 	res, err := clientset.AuthenticationV1().TokenReviews().Create(c.Request.Context(), tr, metav1.CreateOptions{})
 	if err != nil {
 		a.log.Error(err, "TokenReview API call failed")
-		return "", "", &authError{
+		return nil, &authError{
 			Reason:  "token_review_failed",
 			Details: "Failed to validate token with Kubernetes API. The token may be malformed or the server may have connectivity issues.",
 		}
@@ -201,15 +219,15 @@ func (a *APIServer) authenticateRequest(c *gin.Context) (string, string, *authEr
 	if res.Status.Authenticated {
 		username := res.Status.User.Username
 		if username == "" {
-			return "", "", &authError{
+			return nil, &authError{
 				Reason:  "invalid_token",
 				Details: "Token was authenticated but no username was returned.",
 			}
 		}
-		return username, "k8s", nil
+		return &requestIdentity{username: username, groups: res.Status.User.Groups, authType: "k8s"}, nil
 	}
 
-	return "", "", a.buildAuthFailureError(authAttempts, oidcError, res.Status.Error)
+	return nil, a.buildAuthFailureError(authAttempts, oidcError, res.Status.Error)
 }
 
 func (a *APIServer) buildAuthFailureError(authAttempts []string, oidcError error, tokenReviewError string) *authError {
@@ -0,0 +1,92 @@
+package buildapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+// Reasons of authorization denials, returned in the "reason" field of 403 responses.
+const (
+	reasonInsufficientRole = "insufficient_role"
+	reasonNotOwner         = "not_owner"
+	reasonNotProjectMember = "not_project_member"
+)
+
+// routeRoles lists the routes that require more than the default role: viewer
+// for reads and builder for everything else. Keys are "METHOD /full/path".
+var routeRoles = map[string]automotivev1alpha1.BuildAPIRole{
+	"GET /v1/workspaces/:name/shell":        automotivev1alpha1.BuildAPIRoleBuilder,
+	"GET /v1/workspaces/:name/port-forward": automotivev1alpha1.BuildAPIRoleBuilder,
+	"GET /v1/workspaces/:name/debug":        automotivev1alpha1.BuildAPIRoleBuilder,
+	"GET /v1/workspaces/:name/sync/pull":    automotivev1alpha1.BuildAPIRoleBuilder,
+	"POST /v1/catalog/images":               automotivev1alpha1.BuildAPIRoleReleaser,
+	"DELETE /v1/catalog/images/:name":       automotivev1alpha1.BuildAPIRoleReleaser,
+	"POST /v1/catalog/publish":              automotivev1alpha1.BuildAPIRoleReleaser,
+}
+
+// requiredRole returns the role a route requires.
+func requiredRole(method, fullPath string) automotivev1alpha1.BuildAPIRole {
+	if role, ok := routeRoles[method+" "+fullPath]; ok {
+		return role
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return automotivev1alpha1.BuildAPIRoleViewer
+	default:
+		return automotivev1alpha1.BuildAPIRoleBuilder
+	}
+}
+
+// authorizeMiddleware resolves the role of the authenticated caller and
+// rejects requests to routes that require a higher one. Without an
+// authorization config, callers are builders but no route is restricted, as
+// before roles existed. It runs after authMiddleware.
+func (a *APIServer) authorizeMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		a.authConfigMu.RLock()
+		authorization := a.authorization
+		a.authConfigMu.RUnlock()
+
+		role := authorization.RoleFor(c.GetString("requester"), c.GetStringSlice("groups"))
+		c.Set("role", string(role))
+
+		if authorization == nil {
+			c.Next()
+			return
+		}
+		if required := requiredRole(c.Request.Method, c.FullPath()); !role.Includes(required) {
+			forbidden(c, reasonInsufficientRole, fmt.Sprintf("this request requires the %s role, you have the %s role", required, role))
+			return
+		}
+		c.Next()
+	}
+}
+
+// requesterRole returns the role resolved by authorizeMiddleware. Requests
+// that did not pass through it are treated as builders.
+func requesterRole(c *gin.Context) automotivev1alpha1.BuildAPIRole {
+	if role := c.GetString("role"); role != "" {
+		return automotivev1alpha1.BuildAPIRole(role)
+	}
+	return automotivev1alpha1.BuildAPIRoleBuilder
+}
+
+// canActOn reports whether the requester may act on a resource of owner: its
+// own resources, and those of anyone else with role.
+func (a *APIServer) canActOn(c *gin.Context, owner string, role automotivev1alpha1.BuildAPIRole) bool {
+	return owner == a.resolveRequester(c) || requesterRole(c).Includes(role)
+}
+
+// forbidden aborts the request with a structured authorization denial.
+func forbidden(c *gin.Context, reason, details string) {
+	c.JSON(http.StatusForbidden, gin.H{
+		"error":   "forbidden",
+		"reason":  reason,
+		"details": details,
+	})
+	c.Abort()
+}
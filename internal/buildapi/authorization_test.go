@@ -0,0 +1,145 @@
+package buildapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+	"github.com/gin-gonic/gin"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+)
+
+var _ = Describe("Authorization", func() {
+	var server *APIServer
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		server = NewAPIServer(":0", logr.Discard())
+		server.authorization = &automotivev1alpha1.AuthorizationConfig{
+			DefaultRole: automotivev1alpha1.BuildAPIRoleViewer,
+			Bindings: []automotivev1alpha1.RoleBindingConfig{
+				{Role: automotivev1alpha1.BuildAPIRoleBuilder, Groups: []string{"devs"}},
+				{Role: automotivev1alpha1.BuildAPIRoleReleaser, Users: []string{"rel"}},
+				{Role: automotivev1alpha1.BuildAPIRoleAdmin, Users: []string{"lead"}},
+			},
+		}
+	})
+
+	It("should require builder for writes and releaser for publishing", func() {
+		Expect(requiredRole(http.MethodGet, "/v1/builds")).To(Equal(automotivev1alpha1.BuildAPIRoleViewer))
+		Expect(requiredRole(http.MethodPost, "/v1/builds")).To(Equal(automotivev1alpha1.BuildAPIRoleBuilder))
+		Expect(requiredRole(http.MethodGet, "/v1/workspaces/:name/shell")).To(Equal(automotivev1alpha1.BuildAPIRoleBuilder))
+		Expect(requiredRole(http.MethodPost, "/v1/catalog/publish")).To(Equal(automotivev1alpha1.BuildAPIRoleReleaser))
+	})
+
+	Context("middleware", func() {
+		request := func(method, path, user string, groups ...string) *httptest.ResponseRecorder {
+			router := gin.New()
+			grp := router.Group("/v1/catalog")
+			grp.Use(func(c *gin.Context) {
+				c.Set("requester", user)
+				c.Set("groups", groups)
+			}, server.authorizeMiddleware())
+			ok := func(c *gin.Context) { c.String(http.StatusOK, string(requesterRole(c))) }
+			grp.GET("/images", ok)
+			grp.POST("/images", ok)
+			grp.DELETE("/images/:name", ok)
+			grp.POST("/images/:name/verify", ok)
+			grp.POST("/publish", ok)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(method, path, nil)
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		It("should let viewers read", func() {
+			w := request(http.MethodGet, "/v1/catalog/images", "carol")
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(Equal("viewer"))
+		})
+
+		It("should deny viewers writes with a structured reason", func() {
+			w := request(http.MethodPost, "/v1/catalog/images/img/verify", "carol")
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			var body map[string]string
+			Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+			Expect(body).To(HaveKeyWithValue("error", "forbidden"))
+			Expect(body).To(HaveKeyWithValue("reason", reasonInsufficientRole))
+			Expect(body["details"]).To(ContainSubstring("requires the builder role"))
+
+			Expect(request(http.MethodPost, "/v1/catalog/images/img/verify", "carol", "devs").Code).To(Equal(http.StatusOK))
+		})
+
+		It("should only let releasers publish", func() {
+			Expect(request(http.MethodPost, "/v1/catalog/publish", "carol", "devs").Code).To(Equal(http.StatusForbidden))
+			Expect(request(http.MethodPost, "/v1/catalog/publish", "rel").Code).To(Equal(http.StatusOK))
+			Expect(request(http.MethodPost, "/v1/catalog/publish", "lead").Code).To(Equal(http.StatusOK))
+		})
+
+		It("should not restrict the catalog without an authorization config", func() {
+			server.authorization = nil
+			for _, route := range [][2]string{
+				{http.MethodPost, "/v1/catalog/images"},
+				{http.MethodDelete, "/v1/catalog/images/img"},
+				{http.MethodPost, "/v1/catalog/publish"},
+			} {
+				w := request(route[0], route[1], "carol")
+				Expect(w.Code).To(Equal(http.StatusOK), route[1])
+				Expect(w.Body.String()).To(Equal("builder"))
+			}
+		})
+	})
+
+	Context("other users' resources", func() {
+		var originalGetClientFromRequestFn func(*gin.Context) (ctrlclient.Client, error)
+
+		BeforeEach(func() {
+			originalGetClientFromRequestFn = getClientFromRequestFn
+		})
+
+		AfterEach(func() {
+			getClientFromRequestFn = originalGetClientFromRequestFn
+		})
+
+		cancel := func(role automotivev1alpha1.BuildAPIRole) *httptest.ResponseRecorder {
+			scheme := runtime.NewScheme()
+			Expect(automotivev1alpha1.AddToScheme(scheme)).To(Succeed())
+			build := &automotivev1alpha1.ImageBuild{ObjectMeta: metav1.ObjectMeta{
+				Name:        "runaway",
+				Namespace:   resolveNamespace(),
+				Annotations: map[string]string{labels.RequestedBy: "alice"},
+			}}
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(build).WithStatusSubresource(build).Build()
+			getClientFromRequestFn = func(_ *gin.Context) (ctrlclient.Client, error) {
+				return k8sClient, nil
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/v1/builds/runaway/cancel", nil)
+			c.Set("requester", "lead")
+			c.Set("role", string(role))
+			server.cancelBuild(c, "runaway")
+			return w
+		}
+
+		It("should let admins cancel other users' builds", func() {
+			w := cancel(automotivev1alpha1.BuildAPIRoleReleaser)
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(w.Body.String()).To(ContainSubstring(reasonNotOwner))
+
+			w = cancel(automotivev1alpha1.BuildAPIRoleAdmin)
+			Expect(w.Code).To(Equal(http.StatusOK), w.Body.String())
+		})
+	})
+})
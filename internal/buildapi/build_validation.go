@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
@@ -48,9 +50,76 @@ func validateBuildRequest(req *BuildRequest) error {
 		return fmt.Errorf("reproducible builds require secureBuild to be true")
 	}
 
+	if req.SigningKeySecretRef != "" {
+		if errs := validation.IsDNS1123Subdomain(req.SigningKeySecretRef); len(errs) > 0 {
+			return fmt.Errorf("invalid signingKeySecretRef %q: %s", req.SigningKeySecretRef, strings.Join(errs, "; "))
+		}
+	}
+
+	if req.FlashTest != nil {
+		if !req.FlashEnabled {
+			return fmt.Errorf("flashTest requires flash to be enabled")
+		}
+		if err := validateDeviceTest(req.FlashTest); err != nil {
+			return err
+		}
+	}
+
+	return validateRetryPolicy(req.RetryPolicy)
+}
+
+// retryFailureClasses are the failure classes accepted in RetryPolicy.RetryOn.
+var retryFailureClasses = []string{
+	automotivev1alpha1.FailureClassMirrorDownload,
+	automotivev1alpha1.FailureClassRegistryServerError,
+	automotivev1alpha1.FailureClassTimeout,
+}
+
+func validateRetryPolicy(policy *RetryPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	if policy.MaxAttempts < 1 || policy.MaxAttempts > 10 {
+		return fmt.Errorf("invalid retry policy: maxAttempts must be between 1 and 10, got %d", policy.MaxAttempts)
+	}
+	if policy.Backoff != "" {
+		if d, err := time.ParseDuration(policy.Backoff); err != nil || d < 0 {
+			return fmt.Errorf("invalid retry policy: backoff %q must be a non-negative duration (e.g. 30s, 2m)", policy.Backoff)
+		}
+	}
+	for _, class := range policy.RetryOn {
+		if !slices.Contains(retryFailureClasses, class) {
+			return fmt.Errorf("invalid retry policy: unknown failure class %q: must be one of %s",
+				class, strings.Join(retryFailureClasses, ", "))
+		}
+	}
 	return nil
 }
 
+// retryPolicySpec converts a request retry policy to the ImageBuild spec.
+func retryPolicySpec(policy *RetryPolicy) *automotivev1alpha1.RetryPolicy {
+	if policy == nil {
+		return nil
+	}
+	return &automotivev1alpha1.RetryPolicy{
+		MaxAttempts: policy.MaxAttempts,
+		Backoff:     policy.Backoff,
+		RetryOn:     policy.RetryOn,
+	}
+}
+
+// requestRetryPolicy converts an ImageBuild retry policy back into request form for build templates.
+func requestRetryPolicy(policy *automotivev1alpha1.RetryPolicy) *RetryPolicy {
+	if policy == nil {
+		return nil
+	}
+	return &RetryPolicy{
+		MaxAttempts: policy.MaxAttempts,
+		Backoff:     policy.Backoff,
+		RetryOn:     policy.RetryOn,
+	}
+}
+
 // resolveAndClampTTL validates the requested TTL and enforces MaxBuildTTL if configured.
 func resolveAndClampTTL(ctx context.Context, k8sClient client.Client, namespace, requestedTTL string) (string, error) {
 	if requestedTTL == "" {
@@ -90,6 +159,35 @@ func resolveAndClampTTL(ctx context.Context, k8sClient client.Client, namespace,
 	return requestedTTL, nil
 }
 
+// validatePriority checks that a requested priority names a priority class
+// defined in the OperatorConfig.
+func validatePriority(ctx context.Context, k8sClient client.Client, namespace, priority string) error {
+	if priority == "" {
+		return nil
+	}
+	operatorCfg, cfgErr := loadOperatorConfigFn(ctx, k8sClient, namespace)
+	if cfgErr != nil && !k8serrors.IsNotFound(cfgErr) {
+		return fmt.Errorf("failed to load OperatorConfig: %w", cfgErr)
+	}
+	var osBuilds *automotivev1alpha1.OSBuildsConfig
+	if operatorCfg != nil {
+		osBuilds = operatorCfg.Spec.OSBuilds
+	}
+	if osBuilds.GetPriorityClass(priority) != nil {
+		return nil
+	}
+	var names []string
+	if osBuilds != nil {
+		for _, class := range osBuilds.PriorityClasses {
+			names = append(names, class.Name)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("unknown priority %q: no priority classes are configured", priority)
+	}
+	return fmt.Errorf("unknown priority %q: must be one of %s", priority, strings.Join(names, ", "))
+}
+
 // applyBuildDefaults sets default values for build request fields
 func applyBuildDefaults(req *BuildRequest) error {
 	if req.Distro == "" {
@@ -98,6 +196,9 @@ func applyBuildDefaults(req *BuildRequest) error {
 	if req.Target == "" {
 		req.Target = "qemu"
 	}
+	if err := normalizeArchitectures(req); err != nil {
+		return err
+	}
 	if req.Architecture == "" {
 		req.Architecture = "arm64"
 	}
@@ -136,6 +237,56 @@ func applyBuildDefaults(req *BuildRequest) error {
 	return nil
 }
 
+// normalizeArchitectures validates and de-duplicates a multi-architecture request.
+// The first entry becomes the primary Architecture; a single entry is treated as
+// a regular single-architecture build.
+func normalizeArchitectures(req *BuildRequest) error {
+	if len(req.Architectures) == 0 {
+		return nil
+	}
+	seen := make(map[Architecture]bool, len(req.Architectures))
+	archs := make([]Architecture, 0, len(req.Architectures))
+	for _, arch := range req.Architectures {
+		arch = arch.Normalize()
+		if !arch.IsValid() {
+			return fmt.Errorf("invalid architecture %q: must be amd64, arm64, x86_64, or aarch64", arch)
+		}
+		if seen[arch] {
+			continue
+		}
+		seen[arch] = true
+		archs = append(archs, arch)
+	}
+	req.Architecture = archs[0]
+	if len(archs) == 1 {
+		archs = nil
+	}
+	req.Architectures = archs
+	return nil
+}
+
+// validateMultiArchRequest rejects options the controller cannot fan out per architecture.
+func validateMultiArchRequest(req *BuildRequest, needsUpload bool) error {
+	if len(req.Architectures) < 2 {
+		return nil
+	}
+	switch {
+	case req.FlashEnabled:
+		return fmt.Errorf("flash is not supported for multi-architecture builds")
+	case needsUpload:
+		return fmt.Errorf("local input files are not supported for multi-architecture builds")
+	case req.Workspace != "":
+		return fmt.Errorf("workspace builds are not supported for multi-architecture builds")
+	case strings.Contains(req.ContainerPush, "@") || strings.Contains(req.ExportOCI, "@"):
+		return fmt.Errorf("multi-architecture builds push per-architecture tags and cannot target a digest reference")
+	case req.ExportLocation != nil:
+		return fmt.Errorf("exportLocation is not supported for multi-architecture builds")
+	case req.RetryPolicy != nil:
+		return fmt.Errorf("retryPolicy is not supported for multi-architecture builds")
+	}
+	return nil
+}
+
 func validateRestoreSourcesRef(req *BuildRequest) error {
 	if req.RestoreSourcesRef == "" {
 		return nil
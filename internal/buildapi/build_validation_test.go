@@ -194,3 +194,130 @@ var _ = Describe("validateRestoreSourcesRef", func() {
 		Expect(req.RestoreSourcesRef).ToNot(HavePrefix(" "))
 	})
 })
+
+var _ = Describe("normalizeArchitectures", func() {
+	It("normalizes, dedupes, and promotes the first entry to Architecture", func() {
+		req := &BuildRequest{Architectures: []Architecture{"x86_64", "aarch64", "amd64"}}
+		Expect(applyBuildDefaults(req)).To(Succeed())
+		Expect(req.Architectures).To(Equal([]Architecture{ArchAMD64, ArchARM64}))
+		Expect(req.Architecture).To(Equal(ArchAMD64))
+	})
+
+	It("collapses a single architecture into a single-arch build", func() {
+		req := &BuildRequest{Architectures: []Architecture{"aarch64", "arm64"}}
+		Expect(applyBuildDefaults(req)).To(Succeed())
+		Expect(req.Architectures).To(BeNil())
+		Expect(req.Architecture).To(Equal(ArchARM64))
+	})
+
+	It("rejects invalid architectures", func() {
+		req := &BuildRequest{Architectures: []Architecture{"amd64", "riscv64"}}
+		err := applyBuildDefaults(req)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid architecture"))
+	})
+})
+
+var _ = Describe("validateMultiArchRequest", func() {
+	multiArch := func() *BuildRequest {
+		return &BuildRequest{
+			Architectures: []Architecture{ArchAMD64, ArchARM64},
+			ContainerPush: "quay.io/org/image:v1",
+		}
+	}
+
+	It("accepts a plain multi-arch request", func() {
+		Expect(validateMultiArchRequest(multiArch(), false)).To(Succeed())
+	})
+
+	It("ignores single-arch requests", func() {
+		req := &BuildRequest{Architecture: ArchAMD64, FlashEnabled: true}
+		Expect(validateMultiArchRequest(req, true)).To(Succeed())
+	})
+
+	It("rejects flash", func() {
+		req := multiArch()
+		req.FlashEnabled = true
+		Expect(validateMultiArchRequest(req, false)).To(MatchError(ContainSubstring("flash")))
+	})
+
+	It("rejects local input files", func() {
+		Expect(validateMultiArchRequest(multiArch(), true)).To(MatchError(ContainSubstring("local input files")))
+	})
+
+	It("rejects workspace builds", func() {
+		req := multiArch()
+		req.Workspace = "dev"
+		Expect(validateMultiArchRequest(req, false)).To(MatchError(ContainSubstring("workspace")))
+	})
+
+	It("rejects digest push targets", func() {
+		req := multiArch()
+		req.ContainerPush = "quay.io/org/image@sha256:" + strings.Repeat("a", 64)
+		Expect(validateMultiArchRequest(req, false)).To(MatchError(ContainSubstring("digest")))
+	})
+
+	It("rejects storage exports", func() {
+		req := multiArch()
+		req.ExportLocation = &ArtifactLocation{Type: "http", URL: "https://files.example.com/disk.raw"}
+		Expect(validateMultiArchRequest(req, false)).To(MatchError(ContainSubstring("exportLocation")))
+	})
+
+	It("rejects retry policies", func() {
+		req := multiArch()
+		req.RetryPolicy = &RetryPolicy{MaxAttempts: 3}
+		Expect(validateMultiArchRequest(req, false)).To(MatchError(ContainSubstring("retryPolicy")))
+	})
+})
+
+var _ = Describe("validateRetryPolicy", func() {
+	It("accepts a nil policy", func() {
+		Expect(validateRetryPolicy(nil)).To(Succeed())
+	})
+
+	It("accepts a valid policy", func() {
+		Expect(validateRetryPolicy(&RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     "1m",
+			RetryOn:     []string{"MirrorDownload", "Timeout"},
+		})).To(Succeed())
+	})
+
+	It("rejects maxAttempts out of range", func() {
+		err := validateRetryPolicy(&RetryPolicy{MaxAttempts: 0})
+		Expect(err).To(MatchError(ContainSubstring("maxAttempts must be between 1 and 10")))
+		Expect(validateRetryPolicy(&RetryPolicy{MaxAttempts: 11})).NotTo(Succeed())
+	})
+
+	It("rejects an invalid backoff", func() {
+		err := validateRetryPolicy(&RetryPolicy{MaxAttempts: 2, Backoff: "soon"})
+		Expect(err).To(MatchError(ContainSubstring(`backoff "soon"`)))
+	})
+
+	It("rejects unknown failure classes", func() {
+		err := validateRetryPolicy(&RetryPolicy{MaxAttempts: 2, RetryOn: []string{"Anything"}})
+		Expect(err).To(MatchError(ContainSubstring(`unknown failure class "Anything"`)))
+	})
+
+	It("is applied by validateBuildRequest", func() {
+		req := &BuildRequest{
+			Name:        "my-build",
+			Manifest:    "name: test\n",
+			Mode:        ModeBootc,
+			RetryPolicy: &RetryPolicy{MaxAttempts: 20},
+		}
+		Expect(validateBuildRequest(req)).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("validateBuildRequest signingKeySecretRef", func() {
+	It("accepts a valid Secret name", func() {
+		req := &BuildRequest{Name: "my-build", Manifest: "name: test\n", Mode: ModeBootc, SigningKeySecretRef: "team-cosign"}
+		Expect(validateBuildRequest(req)).To(Succeed())
+	})
+
+	It("rejects an invalid Secret name", func() {
+		req := &BuildRequest{Name: "my-build", Manifest: "name: test\n", Mode: ModeBootc, SigningKeySecretRef: "Team_Cosign"}
+		Expect(validateBuildRequest(req)).To(MatchError(ContainSubstring("invalid signingKeySecretRef")))
+	})
+})
@@ -197,6 +197,9 @@ func (h *Handler) HandleCreateCatalogImage(c *gin.Context) {
 	catalogImage := &automotivev1alpha1.CatalogImage{}
 	catalogImage.Name = req.Name
 	catalogImage.Namespace = namespace
+	if requester := c.GetString("requester"); requester != "" {
+		catalogImage.Annotations = map[string]string{automotivev1alpha1.AnnotationRequestedBy: requester}
+	}
 	catalogImage.Spec = automotivev1alpha1.CatalogImageSpec{
 		RegistryURL: req.RegistryURL,
 		Digest:      req.Digest,
@@ -358,6 +361,14 @@ func (h *Handler) HandlePublishImageBuild(c *gin.Context) {
 	catalogImage := &automotivev1alpha1.CatalogImage{}
 	catalogImage.Name = catalogImageName
 	catalogImage.Namespace = req.ImageBuildNamespace
+	catalogImage.Annotations = map[string]string{}
+	if requester := c.GetString("requester"); requester != "" {
+		catalogImage.Annotations[automotivev1alpha1.AnnotationRequestedBy] = requester
+	}
+	// Builds that asked for notifications are also notified about their publication
+	if notify := imageBuild.Annotations[automotivev1alpha1.AnnotationNotify]; notify != "" {
+		catalogImage.Annotations[automotivev1alpha1.AnnotationNotify] = notify
+	}
 	catalogImage.Spec = automotivev1alpha1.CatalogImageSpec{
 		RegistryURL: registryURL,
 		Tags:        req.Tags,
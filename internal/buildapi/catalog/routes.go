@@ -22,12 +22,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// RegisterRoutes registers catalog API routes on the given router group
-func RegisterRoutes(group *gin.RouterGroup, k8sClient client.Client, log logr.Logger) {
+// RegisterRoutes registers catalog API routes on the given router group.
+// The middleware, such as authentication and authorization, runs before every
+// catalog route.
+func RegisterRoutes(group *gin.RouterGroup, k8sClient client.Client, log logr.Logger, middleware ...gin.HandlerFunc) {
 	handler := NewHandler(k8sClient, log)
 
 	// Catalog image routes
 	catalogGroup := group.Group("/catalog")
+	catalogGroup.Use(middleware...)
 	{
 		// List catalog images
 		catalogGroup.GET("/images", handler.HandleListCatalogImages)
@@ -2,11 +2,13 @@
 package client
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -14,7 +16,9 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
 	"github.com/gorilla/websocket"
@@ -25,6 +29,7 @@ type Client struct {
 	baseURL    *url.URL
 	httpClient *http.Client
 	authToken  string
+	project    string
 }
 
 // New creates a new build API client with the given base URL and options.
@@ -55,6 +60,19 @@ func WithHTTPClient(h *http.Client) Option { return func(c *Client) { c.httpClie
 // WithAuthToken sets an authentication token for API requests.
 func WithAuthToken(t string) Option { return func(c *Client) { c.authToken = t } }
 
+// WithProject selects the project, or tenant namespace, that requests work in.
+func WithProject(p string) Option { return func(c *Client) { c.project = p } }
+
+// setHeaders sets the authentication and project headers of a request.
+func (c *Client) setHeaders(h http.Header) {
+	if c.authToken != "" {
+		h.Set("Authorization", "Bearer "+c.authToken)
+	}
+	if c.project != "" {
+		h.Set(buildapi.ProjectHeader, c.project)
+	}
+}
+
 // WithInsecureTLS skips TLS certificate verification (use only for testing)
 func WithInsecureTLS() Option {
 	return func(c *Client) {
@@ -115,9 +133,7 @@ func (c *Client) CreateBuild(ctx context.Context, req buildapi.BuildRequest) (*b
 		return nil, err
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
-	if c.authToken != "" {
-		httpReq.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(httpReq.Header)
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, err
@@ -138,6 +154,41 @@ func (c *Client) CreateBuild(ctx context.Context, req buildapi.BuildRequest) (*b
 	return &out, nil
 }
 
+// RebuildBuild creates a new build from the inputs of an existing build.
+//
+//nolint:dupl // Build and Flash methods are intentionally similar but work with different types
+func (c *Client) RebuildBuild(ctx context.Context, name string, req buildapi.RebuildRequest) (*buildapi.BuildResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := c.resolve(path.Join("/v1/builds", url.PathEscape(name), "rebuild"))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	c.setHeaders(httpReq.Header)
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close response body: %v\n", err)
+		}
+	}()
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("rebuild failed: %s: %s", resp.Status, string(b))
+	}
+	var out buildapi.BuildResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
 // GetBuild retrieves the status and details of a specific build by name.
 func (c *Client) GetBuild(ctx context.Context, name string) (*buildapi.BuildResponse, error) {
 	endpoint := c.resolve(path.Join("/v1/builds", url.PathEscape(name)))
@@ -145,9 +196,7 @@ func (c *Client) GetBuild(ctx context.Context, name string) (*buildapi.BuildResp
 	if err != nil {
 		return nil, err
 	}
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -168,6 +217,27 @@ func (c *Client) GetBuild(ctx context.Context, name string) (*buildapi.BuildResp
 	return &out, nil
 }
 
+// DownloadBuildDisk streams the disk image of a build that was exported to a PVC.
+// The caller must close the returned reader.
+func (c *Client) DownloadBuildDisk(ctx context.Context, name string) (io.ReadCloser, error) {
+	endpoint := c.resolve(path.Join("/v1/builds", url.PathEscape(name), "disk"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(req.Header)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("download disk failed: %s: %s", resp.Status, string(b))
+	}
+	return resp.Body, nil
+}
+
 // DeleteBuild deletes an image build by name.
 func (c *Client) DeleteBuild(ctx context.Context, name string) error {
 	return c.doBuildAction(ctx, http.MethodDelete, path.Join("/v1/builds", url.PathEscape(name)), "delete build")
@@ -183,9 +253,7 @@ func (c *Client) doBuildAction(ctx context.Context, method, endpoint, action str
 	if err != nil {
 		return err
 	}
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
@@ -207,9 +275,7 @@ func (c *Client) CreateBuildToken(ctx context.Context, name string) (*buildapi.T
 	if err != nil {
 		return nil, err
 	}
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -238,9 +304,7 @@ func (c *Client) GetBuildProgress(ctx context.Context, name string) (*buildapi.B
 	if err != nil {
 		return nil, err
 	}
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -273,9 +337,7 @@ func (c *Client) GetBuildTemplate(ctx context.Context, name string) (*buildapi.B
 	if err != nil {
 		return nil, err
 	}
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -296,6 +358,64 @@ func (c *Client) GetBuildTemplate(ctx context.Context, name string) (*buildapi.B
 	return &out, nil
 }
 
+// GetBuildTests returns the on-device test results of a build.
+//
+//nolint:dupl // HTTP client methods share structural boilerplate by design
+func (c *Client) GetBuildTests(ctx context.Context, name string) (*buildapi.BuildTestsResponse, error) {
+	endpoint := c.resolve(path.Join("/v1/builds", url.PathEscape(name), "tests"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(req.Header)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close response body: %v\n", err)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("get build tests failed: %s: %s", resp.Status, string(b))
+	}
+	var out buildapi.BuildTestsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DiffBuilds compares two builds.
+func (c *Client) DiffBuilds(ctx context.Context, from, to string) (*buildapi.BuildDiffResponse, error) {
+	endpoint := c.resolve("/v1/builds/diff") + "?" + url.Values{"from": {from}, "to": {to}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(req.Header)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close response body: %v\n", err)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("diff builds failed: %s: %s", resp.Status, string(b))
+	}
+	var out buildapi.BuildDiffResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
 // ListBuilds retrieves a list of all builds from the API server.
 func (c *Client) ListBuilds(ctx context.Context) ([]buildapi.BuildListItem, error) {
 	var out []buildapi.BuildListItem
@@ -330,9 +450,7 @@ func (c *Client) CreateFlash(ctx context.Context, req buildapi.FlashRequest) (*b
 		return nil, err
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
-	if c.authToken != "" {
-		httpReq.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(httpReq.Header)
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, err
@@ -360,9 +478,7 @@ func (c *Client) GetFlash(ctx context.Context, name string) (*buildapi.FlashResp
 	if err != nil {
 		return nil, err
 	}
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -398,9 +514,7 @@ func (c *Client) listJSON(ctx context.Context, endpoint, operation string, out a
 	if err != nil {
 		return err
 	}
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
@@ -435,9 +549,7 @@ func (c *Client) CreateSealed(ctx context.Context, req buildapi.SealedRequest) (
 		return nil, err
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
-	if c.authToken != "" {
-		httpReq.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(httpReq.Header)
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, err
@@ -466,9 +578,7 @@ func (c *Client) GetSealed(ctx context.Context, op buildapi.SealedOperation, nam
 	if err != nil {
 		return nil, err
 	}
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -497,9 +607,7 @@ func (c *Client) ListSealed(ctx context.Context, op buildapi.SealedOperation) ([
 	if err != nil {
 		return nil, err
 	}
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -588,9 +696,7 @@ func (c *Client) UploadFiles(ctx context.Context, name string, files []Upload) e
 		return err
 	}
 	req.Header.Set("Content-Type", mw.FormDataContentType())
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
@@ -621,9 +727,7 @@ func (c *Client) CreateContainerBuild(ctx context.Context, req buildapi.Containe
 		return nil, err
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
-	if c.authToken != "" {
-		httpReq.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(httpReq.Header)
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, err
@@ -651,9 +755,7 @@ func (c *Client) GetContainerBuild(ctx context.Context, name string) (*buildapi.
 	if err != nil {
 		return nil, err
 	}
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -691,9 +793,7 @@ func (c *Client) UploadContainerBuildContext(ctx context.Context, name string, t
 		return err
 	}
 	req.Header.Set("Content-Type", "application/octet-stream")
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
@@ -717,9 +817,7 @@ func (c *Client) GetOperatorConfig(ctx context.Context) (*buildapi.OperatorConfi
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
@@ -752,9 +850,7 @@ func (c *Client) CreateWorkspace(ctx context.Context, req buildapi.WorkspaceRequ
 		return nil, err
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
-	if c.authToken != "" {
-		httpReq.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(httpReq.Header)
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, err
@@ -778,9 +874,7 @@ func (c *Client) ListWorkspaces(ctx context.Context) ([]buildapi.WorkspaceRespon
 	if err != nil {
 		return nil, err
 	}
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -804,9 +898,7 @@ func (c *Client) GetWorkspace(ctx context.Context, name string) (*buildapi.Works
 	if err != nil {
 		return nil, err
 	}
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -842,9 +934,7 @@ func (c *Client) SetWorkspaceLease(ctx context.Context, name, leaseID string) er
 		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
@@ -867,9 +957,7 @@ func (c *Client) workspaceAction(ctx context.Context, name, action string) (*bui
 	if err != nil {
 		return nil, err
 	}
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -893,9 +981,7 @@ func (c *Client) DeleteWorkspace(ctx context.Context, name string) error {
 	if err != nil {
 		return err
 	}
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
@@ -908,6 +994,88 @@ func (c *Client) DeleteWorkspace(ctx context.Context, name string) error {
 	return nil
 }
 
+// SnapshotWorkspace captures the storage of a workspace so new workspaces can
+// be cloned from it.
+func (c *Client) SnapshotWorkspace(
+	ctx context.Context, name string, req buildapi.WorkspaceSnapshotRequest,
+) (*buildapi.WorkspaceSnapshotResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	return c.workspaceSnapshotRequest(ctx, http.MethodPost, path.Join("/v1/workspaces", url.PathEscape(name), "snapshots"),
+		body, http.StatusCreated, "snapshot workspace")
+}
+
+// ListWorkspaceSnapshots lists the caller's workspace snapshots, optionally
+// restricted to the snapshots of one workspace.
+func (c *Client) ListWorkspaceSnapshots(ctx context.Context, workspace string) ([]buildapi.WorkspaceSnapshotResponse, error) {
+	endpoint := c.resolve("/v1/workspace-snapshots")
+	if workspace != "" {
+		endpoint += "?workspace=" + url.QueryEscape(workspace)
+	}
+	var out []buildapi.WorkspaceSnapshotResponse
+	if err := c.listJSON(ctx, endpoint, "list workspace snapshots", &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetWorkspaceSnapshot gets a workspace snapshot by name.
+func (c *Client) GetWorkspaceSnapshot(ctx context.Context, name string) (*buildapi.WorkspaceSnapshotResponse, error) {
+	return c.workspaceSnapshotRequest(ctx, http.MethodGet, path.Join("/v1/workspace-snapshots", url.PathEscape(name)),
+		nil, http.StatusOK, "get workspace snapshot")
+}
+
+// DeleteWorkspaceSnapshot deletes a workspace snapshot and its stored data.
+func (c *Client) DeleteWorkspaceSnapshot(ctx context.Context, name string) error {
+	endpoint := c.resolve(path.Join("/v1/workspace-snapshots", url.PathEscape(name)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	c.setHeaders(req.Header)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("delete workspace snapshot failed: %s: %s", resp.Status, string(b))
+	}
+	return nil
+}
+
+// workspaceSnapshotRequest performs a request against a workspace snapshot
+// endpoint and decodes a WorkspaceSnapshotResponse.
+func (c *Client) workspaceSnapshotRequest(
+	ctx context.Context, method, endpoint string, body []byte, wantStatus int, operation string,
+) (*buildapi.WorkspaceSnapshotResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.resolve(endpoint), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.setHeaders(req.Header)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != wantStatus {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s failed: %s: %s", operation, resp.Status, string(b))
+	}
+	var out buildapi.WorkspaceSnapshotResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	return &out, nil
+}
+
 // SyncPlan sends a file manifest and returns which files need uploading.
 func (c *Client) SyncPlan(ctx context.Context, name string, req buildapi.SyncPlanRequest) (*buildapi.SyncPlanResponse, error) {
 	body, err := json.Marshal(req)
@@ -920,9 +1088,7 @@ func (c *Client) SyncPlan(ctx context.Context, name string, req buildapi.SyncPla
 		return nil, err
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
-	if c.authToken != "" {
-		httpReq.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(httpReq.Header)
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, err
@@ -947,9 +1113,7 @@ func (c *Client) SyncWorkspace(ctx context.Context, name string, body io.Reader)
 		return err
 	}
 	req.Header.Set("Content-Type", "application/octet-stream")
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
@@ -962,6 +1126,59 @@ func (c *Client) SyncWorkspace(ctx context.Context, name string, body io.Reader)
 	return nil
 }
 
+// SyncFinish completes a sync: it deletes the given files from the workspace
+// and records the synced tree for the next plan.
+func (c *Client) SyncFinish(ctx context.Context, name string, req buildapi.SyncFinishRequest) (*buildapi.SyncFinishResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := c.resolve(path.Join("/v1/workspaces", url.PathEscape(name), "sync", "finish"))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	c.setHeaders(httpReq.Header)
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("sync finish failed: %s: %s", resp.Status, string(b))
+	}
+	var out buildapi.SyncFinishResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	return &out, nil
+}
+
+// PullWorkspace downloads a file or directory of a workspace as a tar stream.
+// Relative paths are resolved against /workspace/src. The caller must close the
+// returned reader.
+func (c *Client) PullWorkspace(ctx context.Context, name, remotePath string) (io.ReadCloser, error) {
+	endpoint := c.resolve(path.Join("/v1/workspaces", url.PathEscape(name), "sync", "pull")) +
+		"?path=" + url.QueryEscape(remotePath)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(req.Header)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer func() { _ = resp.Body.Close() }()
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("pull workspace failed: %s: %s", resp.Status, string(b))
+	}
+	return resp.Body, nil
+}
+
 // workspaceStreamRequest performs a POST with a JSON body and returns the streaming response body.
 func (c *Client) workspaceStreamRequest(ctx context.Context, name, action string, reqBody interface{}) (io.ReadCloser, error) {
 	body, err := json.Marshal(reqBody)
@@ -974,9 +1191,7 @@ func (c *Client) workspaceStreamRequest(ctx context.Context, name, action string
 		return nil, err
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
-	if c.authToken != "" {
-		httpReq.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(httpReq.Header)
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, err
@@ -1003,13 +1218,36 @@ func (c *Client) DeployWorkspace(ctx context.Context, name string, req buildapi.
 // Returns the raw WebSocket connection for the caller to read/write.
 func (c *Client) ShellWorkspace(ctx context.Context, name string) (*websocket.Conn, error) {
 	endpoint := c.resolve(path.Join("/v1/workspaces", url.PathEscape(name), "shell"))
+	return c.dialWebSocket(ctx, endpoint, "shell")
+}
+
+// PortForwardWorkspace opens a WebSocket relaying a single TCP connection to
+// port of the workspace pod, or of the workspace's leased board when board is set.
+func (c *Client) PortForwardWorkspace(ctx context.Context, name string, port int, board bool) (*websocket.Conn, error) {
+	endpoint := c.resolve(path.Join("/v1/workspaces", url.PathEscape(name), "port-forward")) +
+		"?port=" + strconv.Itoa(port)
+	if board {
+		endpoint += "&board=true"
+	}
+	return c.dialWebSocket(ctx, endpoint, "port-forward")
+}
+
+// DebugWorkspace opens a WebSocket to a gdb session against a program running
+// under gdbserver on the workspace's leased board. In DAP mode the connection
+// carries Debug Adapter Protocol messages instead of a terminal.
+func (c *Client) DebugWorkspace(ctx context.Context, name string, req buildapi.WorkspaceDebugRequest) (*websocket.Conn, error) {
+	endpoint := c.resolve(path.Join("/v1/workspaces", url.PathEscape(name), "debug")) +
+		"?" + req.QueryValues().Encode()
+	return c.dialWebSocket(ctx, endpoint, "debug")
+}
+
+// dialWebSocket connects to a WebSocket endpoint of the Build API.
+func (c *Client) dialWebSocket(ctx context.Context, endpoint, operation string) (*websocket.Conn, error) {
 	// Convert http(s) to ws(s)
 	wsURL := strings.Replace(strings.Replace(endpoint, "https://", "wss://", 1), "http://", "ws://", 1)
 
 	header := http.Header{}
-	if c.authToken != "" {
-		header.Set("Authorization", "Bearer "+c.authToken)
-	}
+	c.setHeaders(header)
 
 	dialer := websocket.Dialer{}
 	if t, ok := c.httpClient.Transport.(*http.Transport); ok && t.TLSClientConfig != nil {
@@ -1021,9 +1259,238 @@ func (c *Client) ShellWorkspace(ctx context.Context, name string) (*websocket.Co
 		if resp != nil {
 			b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
 			_ = resp.Body.Close()
-			return nil, fmt.Errorf("shell websocket failed (%s): %s", resp.Status, string(b))
+			return nil, fmt.Errorf("%s websocket failed (%s): %s", operation, resp.Status, string(b))
 		}
-		return nil, fmt.Errorf("shell websocket failed: %w", err)
+		return nil, fmt.Errorf("%s websocket failed: %w", operation, err)
 	}
 	return conn, nil
 }
+
+// ListLeases retrieves the Jumpstarter leases held by the caller's builds,
+// flash jobs and workspaces. Ended leases are included when all is set.
+func (c *Client) ListLeases(ctx context.Context, all bool) ([]buildapi.LeaseResponse, error) {
+	endpoint := c.resolve("/v1/leases")
+	if all {
+		endpoint += "?all=true"
+	}
+	var out []buildapi.LeaseResponse
+	if err := c.listJSON(ctx, endpoint, "list leases", &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetLease retrieves a single lease by name.
+func (c *Client) GetLease(ctx context.Context, name string) (*buildapi.LeaseResponse, error) {
+	return c.leaseRequest(ctx, http.MethodGet, path.Join("/v1/leases", url.PathEscape(name)), nil, "get lease")
+}
+
+// ExtendLease adds the given duration (e.g. "1h") to a lease.
+func (c *Client) ExtendLease(ctx context.Context, name, duration string) (*buildapi.LeaseResponse, error) {
+	body, err := json.Marshal(buildapi.ExtendLeaseRequest{Duration: duration})
+	if err != nil {
+		return nil, err
+	}
+	return c.leaseRequest(ctx, http.MethodPost, path.Join("/v1/leases", url.PathEscape(name), "extend"), body, "extend lease")
+}
+
+// ReleaseLease ends a lease and frees its exporter.
+func (c *Client) ReleaseLease(ctx context.Context, name string) (*buildapi.LeaseResponse, error) {
+	return c.leaseRequest(ctx, http.MethodPost, path.Join("/v1/leases", url.PathEscape(name), "release"), nil, "release lease")
+}
+
+// GetQuota retrieves the usage of the caller and its team against their quotas.
+func (c *Client) GetQuota(ctx context.Context) (*buildapi.QuotaResponse, error) {
+	var out buildapi.QuotaResponse
+	if err := c.listJSON(ctx, c.resolve("/v1/quota"), "get quota", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// leaseRequest performs a request against a lease endpoint and decodes a LeaseResponse.
+func (c *Client) leaseRequest(ctx context.Context, method, endpoint string, body []byte, operation string) (*buildapi.LeaseResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.resolve(endpoint), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.setHeaders(req.Header)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close response body: %v\n", err)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s failed: %s: %s", operation, resp.Status, string(b))
+	}
+	var out buildapi.LeaseResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ErrEventsUnavailable is returned by WatchEvents and WatchBuild when the server
+// does not stream events, so that callers can fall back to polling.
+var ErrEventsUnavailable = errors.New("event stream not available")
+
+const (
+	defaultEventRetry = 3 * time.Second
+	// maxEventReconnects bounds consecutive reconnects that receive no event.
+	maxEventReconnects = 5
+)
+
+// EventFilter limits WatchEvents to one kind ("ImageBuild", "ContainerBuild",
+// "ImageReseal" or "FlashJob") and name of resource.
+type EventFilter struct {
+	Kind string
+	Name string
+}
+
+// WatchEvents calls fn with each status change of the builds, container builds,
+// reseals and flash jobs matching filter, until ctx is done or fn returns an
+// error. Dropped streams are resumed after the last event received.
+func (c *Client) WatchEvents(ctx context.Context, filter EventFilter, fn func(buildapi.BuildEvent) error) error {
+	query := url.Values{}
+	if filter.Kind != "" {
+		query.Set("kind", filter.Kind)
+	}
+	if filter.Name != "" {
+		query.Set("name", filter.Name)
+	}
+	endpoint := c.resolve("/v1/events")
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	return c.watchEvents(ctx, endpoint, "watch events", fn)
+}
+
+// WatchBuild calls fn with the current status of a build and with each of its
+// changes, until ctx is done or fn returns an error.
+func (c *Client) WatchBuild(ctx context.Context, name string, fn func(buildapi.BuildEvent) error) error {
+	return c.watchEvents(ctx, c.resolve(path.Join("/v1/builds", url.PathEscape(name), "watch")), "watch build", fn)
+}
+
+// watchEvents reads the server-sent events of endpoint, reconnecting with the
+// ID of the last event when the stream ends.
+func (c *Client) watchEvents(ctx context.Context, endpoint, operation string, fn func(buildapi.BuildEvent) error) error {
+	s := &eventStream{retry: defaultEventRetry}
+	failures := 0
+	for {
+		received, err := c.readEventStream(ctx, endpoint, operation, s, fn)
+		var fatal *fatalEventError
+		if errors.As(err, &fatal) {
+			return fatal.err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if received {
+			failures = 0
+		} else if failures++; failures >= maxEventReconnects {
+			if err == nil {
+				err = fmt.Errorf("%s: stream ended without events", operation)
+			}
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.retry):
+		}
+	}
+}
+
+// eventStream is the state kept across reconnects of a stream.
+type eventStream struct {
+	lastEventID string
+	retry       time.Duration
+}
+
+// fatalEventError ends a watch instead of reconnecting.
+type fatalEventError struct{ err error }
+
+func (e *fatalEventError) Error() string { return e.err.Error() }
+
+// readEventStream reads one connection of an event stream and reports whether
+// it delivered any event.
+func (c *Client) readEventStream(
+	ctx context.Context,
+	endpoint, operation string,
+	s *eventStream,
+	fn func(buildapi.BuildEvent) error,
+) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false, &fatalEventError{err}
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if s.lastEventID != "" {
+		req.Header.Set("Last-Event-ID", s.lastEventID)
+	}
+	c.setHeaders(req.Header)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusNotImplemented, http.StatusServiceUnavailable:
+		return false, &fatalEventError{fmt.Errorf("%w: %s", ErrEventsUnavailable, resp.Status)}
+	default:
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return false, &fatalEventError{fmt.Errorf("%s failed: %s: %s", operation, resp.Status, string(b))}
+	}
+
+	received := false
+	var id, data string
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if data != "" {
+				var ev buildapi.BuildEvent
+				if err := json.Unmarshal([]byte(data), &ev); err != nil {
+					return received, fmt.Errorf("decoding event: %w", err)
+				}
+				if id != "" {
+					s.lastEventID = id
+				}
+				received = true
+				if err := fn(ev); err != nil {
+					return received, &fatalEventError{err}
+				}
+			}
+			id, data = "", ""
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			id = value
+		case "data":
+			if data != "" {
+				data += "\n"
+			}
+			data += value
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				s.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	return received, scanner.Err()
+}
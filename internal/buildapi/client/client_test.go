@@ -3,12 +3,15 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
 
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+	"github.com/gorilla/websocket"
 	. "github.com/onsi/ginkgo/v2" //nolint:revive
 	. "github.com/onsi/gomega"    //nolint:revive
 )
@@ -315,3 +318,531 @@ var _ = Describe("Workspace Start/Stop", func() {
 		})
 	})
 })
+
+var _ = Describe("RebuildBuild", func() {
+	var mockServer *httptest.Server
+
+	AfterEach(func() {
+		if mockServer != nil {
+			mockServer.Close()
+		}
+	})
+
+	It("should POST the overrides to the rebuild endpoint", func() {
+		mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodPost))
+			Expect(r.URL.Path).To(Equal("/v1/builds/my-build-ab12c/rebuild"))
+
+			var req buildapi.RebuildRequest
+			Expect(json.NewDecoder(r.Body).Decode(&req)).To(Succeed())
+			Expect(req.CustomDefs).To(Equal([]string{"debug=true"}))
+			Expect(req.Target).To(Equal(buildapi.Target("ridesx4")))
+
+			w.WriteHeader(http.StatusAccepted)
+			_ = json.NewEncoder(w).Encode(buildapi.BuildResponse{
+				Name:        "my-build-de34f",
+				Phase:       "Building",
+				ParentBuild: "my-build-ab12c",
+			})
+		}))
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		resp, err := apiClient.RebuildBuild(context.Background(), "my-build-ab12c", buildapi.RebuildRequest{
+			CustomDefs: []string{"debug=true"},
+			Target:     "ridesx4",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Name).To(Equal("my-build-de34f"))
+		Expect(resp.ParentBuild).To(Equal("my-build-ab12c"))
+	})
+
+	It("should return error on non-202 response", func() {
+		mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "build not found"}`))
+		}))
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		resp, err := apiClient.RebuildBuild(context.Background(), "missing", buildapi.RebuildRequest{})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("build not found"))
+		Expect(resp).To(BeNil())
+	})
+})
+
+var _ = Describe("DiffBuilds", func() {
+	var mockServer *httptest.Server
+
+	AfterEach(func() {
+		if mockServer != nil {
+			mockServer.Close()
+		}
+	})
+
+	It("should pass both builds as query parameters", func() {
+		mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodGet))
+			Expect(r.URL.Path).To(Equal("/v1/builds/diff"))
+			Expect(r.URL.Query().Get("from")).To(Equal("nightly-ab12c"))
+			Expect(r.URL.Query().Get("to")).To(Equal("nightly-de34f"))
+
+			_ = json.NewEncoder(w).Encode(buildapi.BuildDiffResponse{
+				From:       buildapi.BuildDiffSide{Name: "nightly-ab12c"},
+				To:         buildapi.BuildDiffSide{Name: "nightly-de34f"},
+				CustomDefs: []buildapi.DiffEntry{{Name: "debug", From: "false", To: "true"}},
+			})
+		}))
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		resp, err := apiClient.DiffBuilds(context.Background(), "nightly-ab12c", "nightly-de34f")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.To.Name).To(Equal("nightly-de34f"))
+		Expect(resp.CustomDefs).To(HaveLen(1))
+	})
+
+	It("should return error on non-200 response", func() {
+		mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "build not found"}`))
+		}))
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		resp, err := apiClient.DiffBuilds(context.Background(), "missing", "nightly-de34f")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("build not found"))
+		Expect(resp).To(BeNil())
+	})
+})
+
+var _ = Describe("GetBuildTests", func() {
+	var mockServer *httptest.Server
+
+	AfterEach(func() {
+		if mockServer != nil {
+			mockServer.Close()
+		}
+	})
+
+	It("should decode the device test results", func() {
+		mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodGet))
+			Expect(r.URL.Path).To(Equal("/v1/builds/nightly-ab12c/tests"))
+
+			_ = json.NewEncoder(w).Encode(buildapi.BuildTestsResponse{
+				Name:  "nightly-ab12c",
+				Phase: "Failed",
+				Tests: &buildapi.DeviceTestResults{
+					Booted:      true,
+					Total:       3,
+					Passed:      2,
+					Failed:      1,
+					FailedCases: []buildapi.DeviceTestCase{{Suite: "smoke", Name: "network", Message: "no route"}},
+				},
+			})
+		}))
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		resp, err := apiClient.GetBuildTests(context.Background(), "nightly-ab12c")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Tests).NotTo(BeNil())
+		Expect(resp.Tests.Failed).To(Equal(int32(1)))
+		Expect(resp.Tests.FailedCases[0].Name).To(Equal("network"))
+	})
+
+	It("should return error on non-200 response", func() {
+		mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "build nightly-ab12c has no device tests"}`))
+		}))
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		resp, err := apiClient.GetBuildTests(context.Background(), "nightly-ab12c")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("has no device tests"))
+		Expect(resp).To(BeNil())
+	})
+})
+
+var _ = Describe("Leases", func() {
+	var mockServer *httptest.Server
+
+	AfterEach(func() {
+		if mockServer != nil {
+			mockServer.Close()
+		}
+	})
+
+	It("should list leases including ended ones", func() {
+		mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodGet))
+			Expect(r.URL.Path).To(Equal("/v1/leases"))
+			Expect(r.URL.Query().Get("all")).To(Equal("true"))
+
+			_ = json.NewEncoder(w).Encode([]buildapi.LeaseResponse{{
+				Name:   "lease-1",
+				Active: true,
+				Owners: []buildapi.LeaseOwner{{Kind: "FlashJob", Name: "flash-1"}},
+			}})
+		}))
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		leases, err := apiClient.ListLeases(context.Background(), true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(leases).To(HaveLen(1))
+		Expect(leases[0].Owners[0].Name).To(Equal("flash-1"))
+	})
+
+	It("should send the extension duration", func() {
+		mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodPost))
+			Expect(r.URL.Path).To(Equal("/v1/leases/lease-1/extend"))
+
+			var req buildapi.ExtendLeaseRequest
+			Expect(json.NewDecoder(r.Body).Decode(&req)).To(Succeed())
+			Expect(req.Duration).To(Equal("1h"))
+
+			_ = json.NewEncoder(w).Encode(buildapi.LeaseResponse{Name: "lease-1", Duration: "2h0m0s", Active: true})
+		}))
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		lease, err := apiClient.ExtendLease(context.Background(), "lease-1", "1h")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lease.Duration).To(Equal("2h0m0s"))
+	})
+
+	It("should return error on non-200 response", func() {
+		mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "lease lease-1 not found"}`))
+		}))
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		lease, err := apiClient.ReleaseLease(context.Background(), "lease-1")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("not found"))
+		Expect(lease).To(BeNil())
+	})
+})
+
+var _ = Describe("Workspace Snapshots", func() {
+	var mockServer *httptest.Server
+
+	AfterEach(func() {
+		if mockServer != nil {
+			mockServer.Close()
+		}
+	})
+
+	It("should create a snapshot of a workspace", func() {
+		mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodPost))
+			Expect(r.URL.Path).To(Equal("/v1/workspaces/dev/snapshots"))
+
+			var req buildapi.WorkspaceSnapshotRequest
+			Expect(json.NewDecoder(r.Body).Decode(&req)).To(Succeed())
+			Expect(req.Name).To(Equal("dev-snap"))
+			Expect(req.Method).To(Equal("OCI"))
+
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(buildapi.WorkspaceSnapshotResponse{Name: "dev-snap", Workspace: "dev", Phase: "Pending"})
+		}))
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		snapshot, err := apiClient.SnapshotWorkspace(context.Background(), "dev",
+			buildapi.WorkspaceSnapshotRequest{Name: "dev-snap", Method: "OCI"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(snapshot.Name).To(Equal("dev-snap"))
+		Expect(snapshot.Phase).To(Equal("Pending"))
+	})
+
+	It("should filter listed snapshots by workspace", func() {
+		mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodGet))
+			Expect(r.URL.Path).To(Equal("/v1/workspace-snapshots"))
+			Expect(r.URL.Query().Get("workspace")).To(Equal("dev"))
+
+			_ = json.NewEncoder(w).Encode([]buildapi.WorkspaceSnapshotResponse{{Name: "dev-snap", Workspace: "dev", Phase: "Ready"}})
+		}))
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		snapshots, err := apiClient.ListWorkspaceSnapshots(context.Background(), "dev")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(snapshots).To(HaveLen(1))
+		Expect(snapshots[0].Phase).To(Equal("Ready"))
+	})
+
+	It("should return error on failed delete", func() {
+		mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodDelete))
+			Expect(r.URL.Path).To(Equal("/v1/workspace-snapshots/dev-snap"))
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "workspace snapshot \"dev-snap\" not found"}`))
+		}))
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = apiClient.DeleteWorkspaceSnapshot(context.Background(), "dev-snap")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("not found"))
+	})
+})
+
+var _ = Describe("Workspace Sync", func() {
+	var mockServer *httptest.Server
+
+	AfterEach(func() {
+		if mockServer != nil {
+			mockServer.Close()
+		}
+	})
+
+	It("should send the synced tree and deletions", func() {
+		mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodPost))
+			Expect(r.URL.Path).To(Equal("/v1/workspaces/my-app/sync/finish"))
+
+			var req buildapi.SyncFinishRequest
+			Expect(json.NewDecoder(r.Body).Decode(&req)).To(Succeed())
+			Expect(req.Files).To(Equal([]string{"main.c"}))
+			Expect(req.Delete).To(Equal([]string{"old.c"}))
+
+			_ = json.NewEncoder(w).Encode(buildapi.SyncFinishResponse{Deleted: 1})
+		}))
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		resp, err := apiClient.SyncFinish(context.Background(), "my-app",
+			buildapi.SyncFinishRequest{Files: []string{"main.c"}, Delete: []string{"old.c"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Deleted).To(Equal(1))
+	})
+
+	It("should stream a pulled path", func() {
+		mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodGet))
+			Expect(r.URL.Path).To(Equal("/v1/workspaces/my-app/sync/pull"))
+			Expect(r.URL.Query().Get("path")).To(Equal("build/app"))
+			_, _ = w.Write([]byte("tar-data"))
+		}))
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		body, err := apiClient.PullWorkspace(context.Background(), "my-app", "build/app")
+		Expect(err).NotTo(HaveOccurred())
+		defer func() { _ = body.Close() }()
+		data, err := io.ReadAll(body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("tar-data"))
+	})
+
+	It("should return error when the pulled path does not exist", func() {
+		mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "/workspace/src/build not found"}`))
+		}))
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		body, err := apiClient.PullWorkspace(context.Background(), "my-app", "build")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("not found"))
+		Expect(body).To(BeNil())
+	})
+})
+
+var _ = Describe("Workspace Port Forward", func() {
+	It("should request the port and target over a WebSocket", func() {
+		upgrader := websocket.Upgrader{}
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/v1/workspaces/my-app/port-forward"))
+			Expect(r.URL.Query().Get("port")).To(Equal("2345"))
+			Expect(r.URL.Query().Get("board")).To(Equal("true"))
+			Expect(r.Header.Get("Authorization")).To(Equal("Bearer test-token"))
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}))
+		defer mockServer.Close()
+
+		apiClient, err := New(mockServer.URL, WithAuthToken("test-token"))
+		Expect(err).NotTo(HaveOccurred())
+
+		conn, err := apiClient.PortForwardWorkspace(context.Background(), "my-app", 2345, true)
+		Expect(err).NotTo(HaveOccurred())
+		_ = conn.Close()
+	})
+})
+
+var _ = Describe("Workspace Debug", func() {
+	It("should send the debug request as query parameters", func() {
+		upgrader := websocket.Upgrader{}
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/v1/workspaces/my-app/debug"))
+			Expect(r.URL.Query().Get("binary")).To(Equal("/usr/bin/app"))
+			Expect(r.URL.Query()["arg"]).To(Equal([]string{"-v", "a b"}))
+			Expect(r.URL.Query().Get("mode")).To(Equal(buildapi.DebugModeDAP))
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}))
+		defer mockServer.Close()
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		conn, err := apiClient.DebugWorkspace(context.Background(), "my-app", buildapi.WorkspaceDebugRequest{
+			Binary: "/usr/bin/app",
+			Args:   []string{"-v", "a b"},
+			Mode:   buildapi.DebugModeDAP,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		_ = conn.Close()
+	})
+
+	It("should report errors returned before the upgrade", func() {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"no Jumpstarter lease associated with this workspace"}`))
+		}))
+		defer mockServer.Close()
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = apiClient.DebugWorkspace(context.Background(), "my-app", buildapi.WorkspaceDebugRequest{Binary: "/usr/bin/app"})
+		Expect(err).To(MatchError(ContainSubstring("no Jumpstarter lease")))
+	})
+})
+
+var _ = Describe("WithProject", func() {
+	It("should send the project with every request", func() {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("Authorization")).To(Equal("Bearer test-token"))
+			Expect(r.Header.Get(buildapi.ProjectHeader)).To(Equal("team-a"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[]`))
+		}))
+		defer mockServer.Close()
+
+		apiClient, err := New(mockServer.URL, WithAuthToken("test-token"), WithProject("team-a"))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = apiClient.ListBuilds(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should leave the project to the server by default", func() {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Values(buildapi.ProjectHeader)).To(BeEmpty())
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[]`))
+		}))
+		defer mockServer.Close()
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = apiClient.ListBuilds(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+var _ = Describe("Event streams", func() {
+	It("should resume dropped streams after the last event", func() {
+		var lastEventIDs []string
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/v1/builds/my-build/watch"))
+			lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+			w.Header().Set("Content-Type", "text/event-stream")
+			if len(lastEventIDs) == 1 {
+				_, _ = io.WriteString(w, "retry: 10\n\n: keep-alive\n\n"+
+					"id: 1-1\nevent: status\ndata: {\"name\":\"my-build\",\"phase\":\"Queued\"}\n\n"+
+					"id: 1-2\nevent: status\ndata: {\"name\":\"my-build\",\"phase\":\"Building\"}\n\n")
+				return
+			}
+			_, _ = io.WriteString(w, "id: 1-5\nevent: status\ndata: {\"name\":\"my-build\",\"phase\":\"Completed\"}\n\n")
+		}))
+		defer mockServer.Close()
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		var phases []string
+		done := errors.New("done")
+		err = apiClient.WatchBuild(context.Background(), "my-build", func(ev buildapi.BuildEvent) error {
+			phases = append(phases, ev.Phase)
+			if ev.Phase == "Completed" {
+				return done
+			}
+			return nil
+		})
+		Expect(err).To(MatchError(done))
+		Expect(phases).To(Equal([]string{"Queued", "Building", "Completed"}))
+		Expect(lastEventIDs).To(Equal([]string{"", "1-2"}))
+	})
+
+	It("should filter events by kind and name", func() {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/v1/events"))
+			Expect(r.URL.Query().Get("kind")).To(Equal("FlashJob"))
+			Expect(r.URL.Query().Get("name")).To(Equal("my-flash"))
+			_, _ = io.WriteString(w, "id: 1-1\nevent: status\ndata: {\"kind\":\"FlashJob\",\"name\":\"my-flash\"}\n\n")
+		}))
+		defer mockServer.Close()
+
+		apiClient, err := New(mockServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		done := errors.New("done")
+		err = apiClient.WatchEvents(context.Background(), EventFilter{Kind: "FlashJob", Name: "my-flash"},
+			func(ev buildapi.BuildEvent) error {
+				Expect(ev.Kind).To(Equal("FlashJob"))
+				return done
+			})
+		Expect(err).To(MatchError(done))
+	})
+
+	It("should report servers without event streams", func() {
+		for _, status := range []int{http.StatusNotFound, http.StatusServiceUnavailable} {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(status)
+			}))
+
+			apiClient, err := New(mockServer.URL)
+			Expect(err).NotTo(HaveOccurred())
+			err = apiClient.WatchBuild(context.Background(), "my-build", func(buildapi.BuildEvent) error { return nil })
+			Expect(errors.Is(err, ErrEventsUnavailable)).To(BeTrue(), "status %d: %v", status, err)
+			mockServer.Close()
+		}
+	})
+})
@@ -22,6 +22,7 @@ const clientTokenPrefix = "ado-build-api-client-"
 // oidcAuthResult represents the result of OIDC authentication attempt
 type oidcAuthResult struct {
 	username string
+	groups   []string
 	ok       bool
 	err      error
 }
@@ -39,10 +40,10 @@ func (a *APIServer) authenticateExternalJWT(c *gin.Context, token string, authn
 	if username == "" {
 		return oidcAuthResult{ok: false}
 	}
-	return oidcAuthResult{username: username, ok: true}
+	return oidcAuthResult{username: username, groups: resp.User.GetGroups(), ok: true}
 }
 
-func (a *APIServer) ensureClientTokenSecret(c *gin.Context, username string, oidcToken string) error {
+func (a *APIServer) ensureClientTokenSecret(c *gin.Context, username string, groups []string, oidcToken string) error {
 	k8sClient, err := getClientFromRequest(c)
 	if err != nil {
 		return err
@@ -51,7 +52,7 @@ func (a *APIServer) ensureClientTokenSecret(c *gin.Context, username string, oid
 	key := types.NamespacedName{Name: secretName, Namespace: resolveNamespace()}
 
 	// Generate internal JWT token
-	internalToken, expiresAt, err := a.signClientToken(username)
+	internalToken, expiresAt, err := a.signClientToken(username, groups)
 	if err != nil {
 		return err
 	}
@@ -99,7 +100,7 @@ func (a *APIServer) ensureClientTokenSecret(c *gin.Context, username string, oid
 	return k8sClient.Create(c.Request.Context(), secret)
 }
 
-func (a *APIServer) signClientToken(username string) (string, time.Time, error) {
+func (a *APIServer) signClientToken(username string, groups []string) (string, time.Time, error) {
 	if a.internalJWT == nil {
 		return "", time.Time{}, fmt.Errorf("internal JWT is not configured")
 	}
@@ -109,13 +110,16 @@ func (a *APIServer) signClientToken(username string) (string, time.Time, error)
 	if audience == "" {
 		audience = "ado-build-api"
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
-		Issuer:    a.internalJWT.issuer,
-		Subject:   username,
-		Audience:  jwt.ClaimStrings{audience},
-		IssuedAt:  jwt.NewNumericDate(time.Now().Add(-1 * time.Minute)),
-		NotBefore: jwt.NewNumericDate(time.Now().Add(-1 * time.Minute)),
-		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, clientTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    a.internalJWT.issuer,
+			Subject:   username,
+			Audience:  jwt.ClaimStrings{audience},
+			IssuedAt:  jwt.NewNumericDate(time.Now().Add(-1 * time.Minute)),
+			NotBefore: jwt.NewNumericDate(time.Now().Add(-1 * time.Minute)),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Groups: groups,
 	})
 	signed, err := token.SignedString(a.internalJWT.key)
 	if err != nil {
@@ -0,0 +1,61 @@
+package buildapi
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/tasks"
+)
+
+// maxConsoleCaptureDuration bounds how long a flash holds the serial console.
+const maxConsoleCaptureDuration = time.Hour
+
+// validateConsole checks the optional serial console capture of a flash request.
+func validateConsole(console *ConsoleCaptureRequest) error {
+	if console == nil || console.Duration == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(console.Duration)
+	if err != nil || d < time.Second {
+		return fmt.Errorf("invalid console capture: duration %q must be a positive duration (e.g. 5m)", console.Duration)
+	}
+	if d > maxConsoleCaptureDuration {
+		return fmt.Errorf("invalid console capture: duration %s exceeds %s", console.Duration, maxConsoleCaptureDuration)
+	}
+	return nil
+}
+
+// consoleSpec converts a request console capture to the resource spec.
+func consoleSpec(console *ConsoleCaptureRequest) *automotivev1alpha1.ConsoleCaptureSpec {
+	if console == nil {
+		return nil
+	}
+	return &automotivev1alpha1.ConsoleCaptureSpec{
+		Duration:       console.Duration,
+		SkipPowerCycle: console.SkipPowerCycle,
+		SkipUpload:     console.SkipUpload,
+	}
+}
+
+// streamFlashConsole follows the serial console recorded by the console
+// sidecar of a flash TaskRun.
+func (a *APIServer) streamFlashConsole(c *gin.Context, name string) {
+	k8sClient, err := getK8sClientOrFail(c)
+	if err != nil {
+		return
+	}
+	flashJob := &automotivev1alpha1.FlashJob{}
+	if err := getResourceOrFail(c.Request.Context(), c, k8sClient, name, requestNamespace(c), flashJob, "flash"); err != nil {
+		return
+	}
+	if flashJob.Spec.Console == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("flash %s does not capture the serial console", name)})
+		return
+	}
+
+	a.streamFlashContainer(c, k8sClient, flashJob, tasks.FlashConsoleContainer, "Serial Console")
+}
@@ -27,7 +27,7 @@ import (
 )
 
 func (a *APIServer) streamContainerBuildLogs(c *gin.Context, name string) {
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 
 	k8sClient, err := getK8sClientOrFail(c)
 	if err != nil {
@@ -109,7 +109,7 @@ func (a *APIServer) streamContainerBuildLogs(c *gin.Context, name string) {
 				streamedContainers[pod.Name] = make(map[string]bool)
 			}
 
-			processPodLogs(ctx, c, cs, pod, namespace, sinceTime, streamedContainers[pod.Name], &hadStream)
+			processPodLogs(ctx, c, cs, pod, podTaskName(pod), namespace, sinceTime, streamedContainers[pod.Name], &hadStream)
 
 			stepNames := getStepContainerNames(pod)
 			if len(streamedContainers[pod.Name]) == len(stepNames) &&
@@ -184,7 +184,7 @@ func (a *APIServer) createContainerBuild(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 	requestedBy := a.resolveRequester(c)
 
 	// Track created resources for cleanup on failure
@@ -218,7 +218,7 @@ func (a *APIServer) createContainerBuild(c *gin.Context) {
 			return
 		}
 
-		tokenLifetime := resolveTokenLifetime(ctx, k8sClient, namespace)
+		tokenLifetime := resolveTokenLifetime(ctx, k8sClient, resolveNamespace())
 		secretName, err := createInternalRegistrySecretFn(ctx, restCfg, namespace, req.Name, tokenLifetime)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to create internal registry secret: %v", err)})
@@ -305,7 +305,7 @@ func (a *APIServer) createContainerBuild(c *gin.Context) {
 
 	outputImage := req.Output
 	if req.UseInternalRegistry {
-		externalRoute, _ := getExternalRegistryRoute(ctx, k8sClient, namespace)
+		externalRoute, _ := getExternalRegistryRoute(ctx, k8sClient, resolveNamespace())
 		if externalRoute != "" {
 			outputImage = translateToExternalURL(outputImage, externalRoute)
 		}
@@ -321,7 +321,6 @@ func (a *APIServer) createContainerBuild(c *gin.Context) {
 }
 
 func listContainerBuilds(c *gin.Context) {
-	namespace := resolveNamespace()
 	limit, offset := parsePagination(c)
 
 	k8sClient, err := getK8sClientOrFail(c)
@@ -331,7 +330,7 @@ func listContainerBuilds(c *gin.Context) {
 
 	ctx := c.Request.Context()
 	cbList := &automotivev1alpha1.ContainerBuildList{}
-	if err := k8sClient.List(ctx, cbList, client.InNamespace(namespace)); err != nil {
+	if err := listInNamespaces(ctx, c, k8sClient, cbList); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error listing container builds: %v", err)})
 		return
 	}
@@ -365,7 +364,7 @@ func listContainerBuilds(c *gin.Context) {
 }
 
 func (a *APIServer) getContainerBuild(c *gin.Context, name string) {
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 
 	k8sClient, err := getK8sClientOrFail(c)
 	if err != nil {
@@ -380,7 +379,7 @@ func (a *APIServer) getContainerBuild(c *gin.Context, name string) {
 
 	outputImage := cb.Spec.Output
 	if cb.Spec.UseServiceAccountAuth {
-		externalRoute, _ := getExternalRegistryRoute(ctx, k8sClient, namespace)
+		externalRoute, _ := getExternalRegistryRoute(ctx, k8sClient, resolveNamespace())
 		if externalRoute != "" {
 			outputImage = translateToExternalURL(outputImage, externalRoute)
 		}
@@ -404,13 +403,11 @@ func (a *APIServer) getContainerBuild(c *gin.Context, name string) {
 	}
 
 	// Mint a fresh registry token for completed/failed internal registry builds
-	// that belong to the requesting user
-	requester := a.resolveRequester(c)
-	buildOwner := cb.Annotations[labels.RequestedBy]
-	if requester == buildOwner &&
+	// that belong to the requesting user, or for releasers
+	if a.canActOn(c, cb.Annotations[labels.RequestedBy], automotivev1alpha1.BuildAPIRoleReleaser) &&
 		cb.Spec.UseServiceAccountAuth &&
 		isTerminalPhase(cb.Status.Phase) {
-		tokenLifetime := resolveTokenLifetime(ctx, k8sClient, namespace)
+		tokenLifetime := resolveTokenLifetime(ctx, k8sClient, resolveNamespace())
 		token, _, tokenErr := a.mintRegistryToken(ctx, c, namespace, tokenLifetime)
 		if tokenErr != nil {
 			a.log.Error(tokenErr, "failed to mint registry token for container build", "build", name)
@@ -486,7 +483,7 @@ func findWaiterContainer(pod *corev1.Pod) string {
 }
 
 func (a *APIServer) uploadContainerBuildContext(c *gin.Context, name string) {
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 
 	k8sClient, err := getK8sClientOrFail(c)
 	if err != nil {
@@ -0,0 +1,121 @@
+package buildapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+// maxDeviceTestScriptSize bounds the inline test script stored on the resource.
+const maxDeviceTestScriptSize = 64 * 1024
+
+func validateDeviceTest(test *DeviceTestRequest) error {
+	if test == nil {
+		return nil
+	}
+	if strings.TrimSpace(test.Script) == "" {
+		return fmt.Errorf("invalid device test: script is required")
+	}
+	if len(test.Script) > maxDeviceTestScriptSize {
+		return fmt.Errorf("invalid device test: script is %d bytes, exceeds %d byte limit",
+			len(test.Script), maxDeviceTestScriptSize)
+	}
+	switch test.BootCheck {
+	case "", automotivev1alpha1.DeviceTestBootCheckConsole, automotivev1alpha1.DeviceTestBootCheckSSH:
+	default:
+		return fmt.Errorf("invalid device test: bootCheck %q must be %s or %s", test.BootCheck,
+			automotivev1alpha1.DeviceTestBootCheckConsole, automotivev1alpha1.DeviceTestBootCheckSSH)
+	}
+	if test.BootTimeout != "" {
+		if d, err := time.ParseDuration(test.BootTimeout); err != nil || d <= 0 {
+			return fmt.Errorf("invalid device test: bootTimeout %q must be a positive duration (e.g. 5m)", test.BootTimeout)
+		}
+	}
+	return nil
+}
+
+// deviceTestSpec converts a request device test to the resource spec.
+func deviceTestSpec(test *DeviceTestRequest) *automotivev1alpha1.DeviceTestSpec {
+	if test == nil {
+		return nil
+	}
+	return &automotivev1alpha1.DeviceTestSpec{
+		Script:         test.Script,
+		Image:          test.Image,
+		BootCheck:      test.BootCheck,
+		BootMarker:     test.BootMarker,
+		BootTimeout:    test.BootTimeout,
+		SkipPowerCycle: test.SkipPowerCycle,
+	}
+}
+
+// requestDeviceTest converts a device test spec back into request form for build templates.
+func requestDeviceTest(test *automotivev1alpha1.DeviceTestSpec) *DeviceTestRequest {
+	if test == nil {
+		return nil
+	}
+	return &DeviceTestRequest{
+		Script:         test.Script,
+		Image:          test.Image,
+		BootCheck:      test.BootCheck,
+		BootMarker:     test.BootMarker,
+		BootTimeout:    test.BootTimeout,
+		SkipPowerCycle: test.SkipPowerCycle,
+	}
+}
+
+// deviceTestResults converts device test results from a resource status.
+func deviceTestResults(results *automotivev1alpha1.DeviceTestResults) *DeviceTestResults {
+	if results == nil {
+		return nil
+	}
+	out := &DeviceTestResults{
+		Booted:  results.Booted,
+		Total:   results.Total,
+		Passed:  results.Passed,
+		Failed:  results.Failed,
+		Skipped: results.Skipped,
+		Message: results.Message,
+	}
+	for _, tc := range results.FailedCases {
+		out.FailedCases = append(out.FailedCases, DeviceTestCase{
+			Suite:   tc.Suite,
+			Name:    tc.Name,
+			Message: tc.Message,
+		})
+	}
+	return out
+}
+
+// getBuildTests returns the on-device test results of a build.
+func (a *APIServer) getBuildTests(c *gin.Context, name string) {
+	ctx, span := apiTracer.Start(c.Request.Context(), "getBuildTests")
+	defer span.End()
+
+	k8sClient, err := getK8sClientOrFail(c)
+	if err != nil {
+		spanError(span, err)
+		return
+	}
+
+	build := &automotivev1alpha1.ImageBuild{}
+	if err := getResourceOrFail(ctx, c, k8sClient, name, resolveNamespace(), build, "build"); err != nil {
+		spanError(span, err)
+		return
+	}
+	if build.Spec.GetFlashTest() == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("build %s has no device tests", name)})
+		return
+	}
+
+	writeJSON(c, http.StatusOK, BuildTestsResponse{
+		Name:  build.Name,
+		Phase: build.Status.Phase,
+		Tests: deviceTestResults(build.Status.DeviceTests),
+	})
+}
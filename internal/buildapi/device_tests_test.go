@@ -0,0 +1,138 @@
+package buildapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+var _ = Describe("validateDeviceTest", func() {
+	It("accepts a nil test", func() {
+		Expect(validateDeviceTest(nil)).To(Succeed())
+	})
+
+	It("accepts a script with defaults", func() {
+		Expect(validateDeviceTest(&DeviceTestRequest{Script: "./smoke.sh"})).To(Succeed())
+	})
+
+	It("requires a script", func() {
+		err := validateDeviceTest(&DeviceTestRequest{Script: "  "})
+		Expect(err).To(MatchError(ContainSubstring("script is required")))
+	})
+
+	It("rejects an unknown boot check", func() {
+		err := validateDeviceTest(&DeviceTestRequest{Script: "true", BootCheck: "ping"})
+		Expect(err).To(MatchError(ContainSubstring(`bootCheck "ping"`)))
+	})
+
+	It("rejects an invalid boot timeout", func() {
+		for _, timeout := range []string{"ten minutes", "0s", "-5m"} {
+			err := validateDeviceTest(&DeviceTestRequest{Script: "true", BootTimeout: timeout})
+			Expect(err).To(MatchError(ContainSubstring("bootTimeout")), timeout)
+		}
+	})
+
+	It("requires flash for build requests", func() {
+		req := &BuildRequest{
+			Name:      "smoke",
+			Manifest:  "name: test\n",
+			FlashTest: &DeviceTestRequest{Script: "true"},
+		}
+		Expect(validateBuildRequest(req)).To(MatchError(ContainSubstring("requires flash")))
+
+		req.FlashEnabled = true
+		Expect(validateBuildRequest(req)).To(Succeed())
+	})
+})
+
+var _ = Describe("getBuildTests", func() {
+	var (
+		server                         *APIServer
+		originalGetClientFromRequestFn func(*gin.Context) (ctrlclient.Client, error)
+	)
+
+	newBuild := func(test *automotivev1alpha1.DeviceTestSpec) *automotivev1alpha1.ImageBuild {
+		return &automotivev1alpha1.ImageBuild{
+			ObjectMeta: metav1.ObjectMeta{Name: "smoke", Namespace: "test-ns"},
+			Spec: automotivev1alpha1.ImageBuildSpec{
+				Flash: &automotivev1alpha1.FlashSpec{ClientConfigSecretRef: "smoke-jumpstarter-client", Test: test},
+			},
+		}
+	}
+
+	getTests := func(objs ...ctrlclient.Object) *httptest.ResponseRecorder {
+		scheme := runtime.NewScheme()
+		Expect(automotivev1alpha1.AddToScheme(scheme)).To(Succeed())
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+		getClientFromRequestFn = func(_ *gin.Context) (ctrlclient.Client, error) {
+			return fakeClient, nil
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/v1/builds/smoke/tests", nil)
+		server.getBuildTests(c, "smoke")
+		return w
+	}
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		server = NewAPIServer(":0", logr.Discard())
+		originalGetClientFromRequestFn = getClientFromRequestFn
+		Expect(os.Setenv("BUILD_API_NAMESPACE", "test-ns")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		getClientFromRequestFn = originalGetClientFromRequestFn
+		Expect(os.Unsetenv("BUILD_API_NAMESPACE")).To(Succeed())
+	})
+
+	It("returns 404 for a build without device tests", func() {
+		w := getTests(newBuild(nil))
+		Expect(w.Code).To(Equal(http.StatusNotFound))
+		Expect(w.Body.String()).To(ContainSubstring("has no device tests"))
+	})
+
+	It("returns no results while the tests are pending", func() {
+		build := newBuild(&automotivev1alpha1.DeviceTestSpec{Script: "true"})
+		build.Status.Phase = phaseFlashing
+
+		w := getTests(build)
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var resp BuildTestsResponse
+		Expect(json.Unmarshal(w.Body.Bytes(), &resp)).To(Succeed())
+		Expect(resp.Phase).To(Equal(phaseFlashing))
+		Expect(resp.Tests).To(BeNil())
+	})
+
+	It("returns the recorded results", func() {
+		build := newBuild(&automotivev1alpha1.DeviceTestSpec{Script: "true"})
+		build.Status.Phase = phaseFailed
+		build.Status.DeviceTests = &automotivev1alpha1.DeviceTestResults{
+			Booted: true, Total: 2, Passed: 1, Failed: 1,
+			Message:     "1 of 2 tests failed",
+			FailedCases: []automotivev1alpha1.DeviceTestCase{{Suite: "smoke", Name: "network", Message: "no route"}},
+		}
+
+		w := getTests(build)
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var resp BuildTestsResponse
+		Expect(json.Unmarshal(w.Body.Bytes(), &resp)).To(Succeed())
+		Expect(resp.Tests).NotTo(BeNil())
+		Expect(resp.Tests.Failed).To(Equal(int32(1)))
+		Expect(resp.Tests.FailedCases).To(ConsistOf(DeviceTestCase{Suite: "smoke", Name: "network", Message: "no route"}))
+	})
+})
@@ -0,0 +1,263 @@
+package buildapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pmezard/go-difflib/difflib"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+// maxLineageDepth bounds the walk along parent-build annotations.
+const maxLineageDepth = 50
+
+// Keys of the manifest ConfigMap that do not hold the manifest itself.
+const (
+	manifestConfigMapDefinesKey   = "custom-definitions.env"
+	manifestConfigMapExtraArgsKey = "aib-extra-args.txt"
+)
+
+// buildInputs are the AIB inputs a build was run with.
+type buildInputs struct {
+	manifestFileName string
+	manifest         string
+	customDefs       []string
+	aibExtraArgs     []string
+}
+
+// diffBuilds compares the inputs and provenance of two builds.
+func diffBuilds(c *gin.Context) {
+	ctx, span := apiTracer.Start(c.Request.Context(), "diffBuilds")
+	defer span.End()
+
+	fromName, toName := c.Query("from"), c.Query("to")
+	if fromName == "" || toName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to query parameters are required"})
+		return
+	}
+
+	k8sClient, err := getK8sClientOrFail(c)
+	if err != nil {
+		spanError(span, err)
+		return
+	}
+
+	namespace := requestNamespace(c)
+	from := &automotivev1alpha1.ImageBuild{}
+	if err := getResourceOrFail(ctx, c, k8sClient, fromName, namespace, from, "build"); err != nil {
+		spanError(span, err)
+		return
+	}
+	to := &automotivev1alpha1.ImageBuild{}
+	if err := getResourceOrFail(ctx, c, k8sClient, toName, namespace, to, "build"); err != nil {
+		spanError(span, err)
+		return
+	}
+
+	diff, err := buildDiff(ctx, k8sClient, from, to)
+	if err != nil {
+		spanError(span, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	writeJSON(c, http.StatusOK, diff)
+}
+
+// buildDiff compares two builds of the same namespace.
+func buildDiff(
+	ctx context.Context, k8sClient client.Client, from, to *automotivev1alpha1.ImageBuild,
+) (*BuildDiffResponse, error) {
+	fromInputs, err := storedBuildInputs(ctx, k8sClient, from)
+	if err != nil {
+		return nil, err
+	}
+	toInputs, err := storedBuildInputs(ctx, k8sClient, to)
+	if err != nil {
+		return nil, err
+	}
+
+	externalRoute, _ := getExternalRegistryRoute(ctx, k8sClient, resolveNamespace())
+	resp := &BuildDiffResponse{
+		From:       diffSide(ctx, k8sClient, from, externalRoute),
+		To:         diffSide(ctx, k8sClient, to, externalRoute),
+		Parameters: DiffValues(diffParameters(from, fromInputs), diffParameters(to, toInputs)),
+		CustomDefs: DiffValues(defineValues(fromInputs.customDefs), defineValues(toInputs.customDefs)),
+	}
+
+	resp.Lineage = buildLineage(ctx, k8sClient, to, from.Name)
+	if resp.Lineage == nil {
+		resp.Lineage = buildLineage(ctx, k8sClient, from, to.Name)
+	}
+
+	resp.Manifest, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(fromInputs.manifest),
+		B:        difflib.SplitLines(toInputs.manifest),
+		FromFile: from.Name + "/" + fromInputs.manifestFileName,
+		ToFile:   to.Name + "/" + toInputs.manifestFileName,
+		Context:  3,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff manifests: %w", err)
+	}
+	return resp, nil
+}
+
+// storedBuildInputs returns the inputs of a build from its manifest ConfigMap,
+// which holds what the pipeline consumed, falling back to the spec once the
+// ConfigMap has been cleaned up.
+func storedBuildInputs(
+	ctx context.Context, k8sClient client.Client, build *automotivev1alpha1.ImageBuild,
+) (buildInputs, error) {
+	inputs := buildInputs{
+		manifestFileName: build.Spec.GetManifestFileName(),
+		manifest:         build.Spec.GetManifest(),
+		customDefs:       build.Spec.GetCustomDefs(),
+		aibExtraArgs:     build.Spec.GetAIBExtraArgs(),
+	}
+	if inputs.manifestFileName == "" {
+		inputs.manifestFileName = "manifest.aib.yml"
+	}
+
+	list := &corev1.ConfigMapList{}
+	if err := k8sClient.List(ctx, list, client.InNamespace(build.Namespace), client.MatchingLabels{
+		"automotive.sdv.cloud.redhat.com/build-name":    build.Name,
+		"automotive.sdv.cloud.redhat.com/resource-type": "manifest",
+	}); err != nil {
+		return inputs, fmt.Errorf("error listing manifest ConfigMaps of build %s: %w", build.Name, err)
+	}
+	if len(list.Items) == 0 {
+		return inputs, nil
+	}
+
+	inputs.customDefs, inputs.aibExtraArgs = nil, nil
+	for key, value := range list.Items[0].Data {
+		switch key {
+		case manifestConfigMapDefinesKey:
+			inputs.customDefs = strings.Split(value, "\n")
+		case manifestConfigMapExtraArgsKey:
+			inputs.aibExtraArgs = strings.Split(value, "\n")
+		default:
+			inputs.manifestFileName, inputs.manifest = key, value
+		}
+	}
+	return inputs, nil
+}
+
+// diffParameters returns the compared provenance and parameters of a build.
+func diffParameters(build *automotivev1alpha1.ImageBuild, inputs buildInputs) map[string]string {
+	aibImage := build.Status.AIBImageUsed
+	if aibImage == "" {
+		aibImage = build.Spec.GetAIBImage()
+	}
+	architecture := build.Spec.Architecture
+	if archs := multiArchList(build); len(archs) > 0 {
+		architecture = strings.Join(archs, ",")
+	}
+
+	return map[string]string{
+		"aibImage":      aibImage,
+		"builderImage":  build.Status.BuilderImageUsed,
+		"taskBundleRef": build.Spec.TaskBundleRef,
+		"distro":        build.Spec.GetDistro(),
+		"target":        build.Spec.GetTarget(),
+		"architecture":  architecture,
+		"mode":          build.Spec.GetMode(),
+		"exportFormat":  build.Spec.GetExportFormat(),
+		"aibExtraArgs":  strings.Join(inputs.aibExtraArgs, " "),
+	}
+}
+
+// defineValues maps KEY=VALUE defines by key.
+func defineValues(defines []string) map[string]string {
+	values := make(map[string]string, len(defines))
+	for _, def := range defines {
+		if def == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(def, "=")
+		values[key] = value
+	}
+	return values
+}
+
+// diffSide describes one of the compared builds.
+func diffSide(
+	ctx context.Context, k8sClient client.Client, build *automotivev1alpha1.ImageBuild, externalRoute string,
+) BuildDiffSide {
+	side := BuildDiffSide{
+		Name:         build.Name,
+		Phase:        build.Status.Phase,
+		CreatedAt:    build.CreationTimestamp.Format(time.RFC3339),
+		ParentBuild:  build.Annotations[automotivev1alpha1.AnnotationParentBuild],
+		DiskArtifact: diskArtifactReference(ctx, k8sClient, build),
+		Reproducible: build.Spec.Reproducible,
+	}
+	if side.DiskArtifact != "" && build.Spec.GetUseServiceAccountAuth() && externalRoute != "" {
+		side.DiskArtifact = translateToExternalURL(side.DiskArtifact, externalRoute)
+	}
+	return side
+}
+
+// diskArtifactReference returns the digest-pinned reference of the disk image
+// pushed by a build, read from its PipelineRun results. Tags may be reused by
+// later builds, the digest identifies what this build pushed.
+func diskArtifactReference(ctx context.Context, k8sClient client.Client, build *automotivev1alpha1.ImageBuild) string {
+	if build.Status.PipelineRunName == "" {
+		return ""
+	}
+	pipelineRun := &tektonv1.PipelineRun{}
+	key := types.NamespacedName{Name: build.Status.PipelineRunName, Namespace: build.Namespace}
+	if err := k8sClient.Get(ctx, key, pipelineRun); err != nil {
+		return ""
+	}
+
+	var repo, digest string
+	for _, result := range pipelineRun.Status.Results {
+		switch result.Name {
+		case "disk-artifact-url":
+			repo = result.Value.StringVal
+		case "disk-artifact-digest":
+			digest = result.Value.StringVal
+		}
+	}
+	if repo == "" || digest == "" {
+		return ""
+	}
+	if i := strings.Index(repo, "@"); i >= 0 {
+		repo = repo[:i]
+	} else if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo = repo[:i]
+	}
+	return repo + "@" + digest
+}
+
+// buildLineage follows the parent-build annotations from descendant and
+// returns the chain of build names down to ancestor, or nil if ancestor is not
+// an ancestor of descendant.
+func buildLineage(
+	ctx context.Context, k8sClient client.Client, descendant *automotivev1alpha1.ImageBuild, ancestor string,
+) []string {
+	chain := []string{descendant.Name}
+	parent := descendant.Annotations[automotivev1alpha1.AnnotationParentBuild]
+	for i := 0; parent != "" && i < maxLineageDepth; i++ {
+		chain = append(chain, parent)
+		if parent == ancestor {
+			return chain
+		}
+		build := &automotivev1alpha1.ImageBuild{}
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: parent, Namespace: descendant.Namespace}, build); err != nil {
+			return nil
+		}
+		parent = build.Annotations[automotivev1alpha1.AnnotationParentBuild]
+	}
+	return nil
+}
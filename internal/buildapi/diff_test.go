@@ -0,0 +1,157 @@
+package buildapi
+
+import (
+	"context"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+var _ = Describe("buildDiff", func() {
+	newBuild := func(name, parent string) *automotivev1alpha1.ImageBuild {
+		build := &automotivev1alpha1.ImageBuild{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-ns"},
+			Spec: automotivev1alpha1.ImageBuildSpec{
+				Architecture: "amd64",
+				AIB: &automotivev1alpha1.AIBSpec{
+					Distro:     "autosd",
+					Target:     "qemu",
+					Mode:       "bootc",
+					Manifest:   "name: test\n",
+					CustomDefs: []string{"debug=false"},
+				},
+			},
+			Status: automotivev1alpha1.ImageBuildStatus{
+				Phase:            "Completed",
+				AIBImageUsed:     "quay.io/centos-sig-automotive/automotive-image-builder:1.0",
+				BuilderImageUsed: "quay.io/org/builder@sha256:aaa",
+			},
+		}
+		if parent != "" {
+			build.Annotations = map[string]string{automotivev1alpha1.AnnotationParentBuild: parent}
+		}
+		return build
+	}
+
+	newClient := func(objs ...ctrlclient.Object) ctrlclient.Client {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(tektonv1.AddToScheme(scheme)).To(Succeed())
+		Expect(automotivev1alpha1.AddToScheme(scheme)).To(Succeed())
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	}
+
+	manifestConfigMap := func(build, manifest, defines string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      build + "-manifest",
+				Namespace: "test-ns",
+				Labels: map[string]string{
+					"automotive.sdv.cloud.redhat.com/build-name":    build,
+					"automotive.sdv.cloud.redhat.com/resource-type": "manifest",
+				},
+			},
+			Data: map[string]string{
+				"nightly.aib.yml":        manifest,
+				"custom-definitions.env": defines,
+			},
+		}
+	}
+
+	It("reports no differences for identical builds", func() {
+		from, to := newBuild("nightly-ab12c", ""), newBuild("nightly-de34f", "")
+
+		diff, err := buildDiff(context.Background(), newClient(from, to), from, to)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.From.Name).To(Equal("nightly-ab12c"))
+		Expect(diff.To.Name).To(Equal("nightly-de34f"))
+		Expect(diff.Manifest).To(BeEmpty())
+		Expect(diff.Parameters).To(BeEmpty())
+		Expect(diff.CustomDefs).To(BeEmpty())
+		Expect(diff.Lineage).To(BeEmpty())
+	})
+
+	It("compares the stored manifests, defines and provenance", func() {
+		from, to := newBuild("nightly-ab12c", ""), newBuild("nightly-de34f", "")
+		to.Status.BuilderImageUsed = "quay.io/org/builder@sha256:bbb"
+		to.Spec.TaskBundleRef = "quay.io/org/tasks@sha256:ccc"
+
+		diff, err := buildDiff(context.Background(), newClient(
+			from, to,
+			manifestConfigMap("nightly-ab12c", "name: test\nversion: 1\n", "debug=false\nold=1"),
+			manifestConfigMap("nightly-de34f", "name: test\nversion: 2\n", "debug=true\nnew=2"),
+		), from, to)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(diff.Manifest).To(ContainSubstring("--- nightly-ab12c/nightly.aib.yml"))
+		Expect(diff.Manifest).To(ContainSubstring("-version: 1"))
+		Expect(diff.Manifest).To(ContainSubstring("+version: 2"))
+		Expect(diff.CustomDefs).To(Equal([]DiffEntry{
+			{Name: "debug", From: "false", To: "true"},
+			{Name: "new", To: "2"},
+			{Name: "old", From: "1"},
+		}))
+		Expect(diff.Parameters).To(Equal([]DiffEntry{
+			{Name: "builderImage", From: "quay.io/org/builder@sha256:aaa", To: "quay.io/org/builder@sha256:bbb"},
+			{Name: "taskBundleRef", To: "quay.io/org/tasks@sha256:ccc"},
+		}))
+	})
+
+	It("follows parent builds to report lineage", func() {
+		root := newBuild("nightly-ab12c", "")
+		middle := newBuild("nightly-de34f", "nightly-ab12c")
+		leaf := newBuild("nightly-f0a91", "nightly-de34f")
+		k8sClient := newClient(root, middle, leaf)
+
+		diff, err := buildDiff(context.Background(), k8sClient, root, leaf)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.Lineage).To(Equal([]string{"nightly-f0a91", "nightly-de34f", "nightly-ab12c"}))
+		Expect(diff.To.ParentBuild).To(Equal("nightly-de34f"))
+
+		diff, err = buildDiff(context.Background(), k8sClient, leaf, root)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.Lineage).To(Equal([]string{"nightly-f0a91", "nightly-de34f", "nightly-ab12c"}))
+	})
+
+	It("pins the disk artifact to the digest pushed by the build", func() {
+		build := newBuild("nightly-ab12c", "")
+		build.Spec.Reproducible = true
+		build.Status.PipelineRunName = "nightly-ab12c-run"
+		pipelineRun := &tektonv1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly-ab12c-run", Namespace: "test-ns"},
+			Status: tektonv1.PipelineRunStatus{PipelineRunStatusFields: tektonv1.PipelineRunStatusFields{
+				Results: []tektonv1.PipelineRunResult{
+					{Name: "disk-artifact-url", Value: *tektonv1.NewStructuredValues("registry.example.com:5000/org/disk:nightly")},
+					{Name: "disk-artifact-digest", Value: *tektonv1.NewStructuredValues("sha256:ddd")},
+				},
+			}},
+		}
+
+		diff, err := buildDiff(context.Background(), newClient(build, pipelineRun), build, build)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.From.DiskArtifact).To(Equal("registry.example.com:5000/org/disk@sha256:ddd"))
+		Expect(diff.From.Reproducible).To(BeTrue())
+	})
+})
+
+var _ = Describe("DiffValues", func() {
+	It("reports added, removed and changed values sorted by name", func() {
+		Expect(DiffValues(
+			map[string]string{"a": "1", "b": "2", "c": "3"},
+			map[string]string{"a": "1", "b": "4", "d": "5"},
+		)).To(Equal([]DiffEntry{
+			{Name: "b", From: "2", To: "4"},
+			{Name: "c", From: "3"},
+			{Name: "d", To: "5"},
+		}))
+	})
+})
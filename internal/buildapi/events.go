@@ -0,0 +1,665 @@
+package buildapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+)
+
+const (
+	eventTypeStatus  = "status"
+	eventTypeDeleted = "deleted"
+
+	// eventBufferSize is the number of events kept for resuming streams.
+	eventBufferSize = 1000
+	// eventSubscriberQueue is the number of events a stream may fall behind
+	// before it is closed and has to resume from the buffer.
+	eventSubscriberQueue = 64
+	eventKeepAlive       = 15 * time.Second
+	eventRetry           = 3 * time.Second
+	// eventNamespaceResync is how often the watched tenant namespaces are
+	// compared with the OperatorConfig.
+	eventNamespaceResync = time.Minute
+
+	pipelineTaskSelector = "tekton.dev/memberOf=tasks"
+)
+
+var errEventsUnavailable = errors.New("event informers are not running")
+
+// eventKind is a kind of resource whose status changes are streamed.
+type eventKind struct {
+	kind      string
+	newObject func() client.Object
+	newList   func() client.ObjectList
+}
+
+var eventKinds = []eventKind{
+	{
+		kind:      "ImageBuild",
+		newObject: func() client.Object { return &automotivev1alpha1.ImageBuild{} },
+		newList:   func() client.ObjectList { return &automotivev1alpha1.ImageBuildList{} },
+	},
+	{
+		kind:      "ContainerBuild",
+		newObject: func() client.Object { return &automotivev1alpha1.ContainerBuild{} },
+		newList:   func() client.ObjectList { return &automotivev1alpha1.ContainerBuildList{} },
+	},
+	{
+		kind:      "ImageReseal",
+		newObject: func() client.Object { return &automotivev1alpha1.ImageReseal{} },
+		newList:   func() client.ObjectList { return &automotivev1alpha1.ImageResealList{} },
+	},
+	{
+		kind:      "FlashJob",
+		newObject: func() client.Object { return &automotivev1alpha1.FlashJob{} },
+		newList:   func() client.ObjectList { return &automotivev1alpha1.FlashJobList{} },
+	},
+}
+
+func isEventKind(kind string) bool {
+	return slices.ContainsFunc(eventKinds, func(k eventKind) bool { return k.kind == kind })
+}
+
+// eventFilter selects the events of a stream.
+type eventFilter struct {
+	namespaces []string
+	kind       string
+	name       string
+}
+
+func (f eventFilter) matches(ev *BuildEvent) bool {
+	return slices.Contains(f.namespaces, ev.Namespace) &&
+		(f.kind == "" || f.kind == ev.Kind) &&
+		(f.name == "" || f.name == ev.Name)
+}
+
+type eventSubscriber struct {
+	filter eventFilter
+	ch     chan BuildEvent
+	// cursor is the ID of the last event published when the subscriber was added.
+	cursor string
+}
+
+type bufferedEvent struct {
+	seq   uint64
+	event BuildEvent
+}
+
+// eventHub turns the informer notifications of builds, reseals and flash jobs
+// into a sequence of status events. Consecutive identical statuses of a
+// resource are published once. The latest events are buffered so a stream can
+// resume after the ID it last received; IDs carry the epoch of the hub, so
+// streams of a restarted Build API start over from a snapshot.
+type eventHub struct {
+	log   logr.Logger
+	epoch string
+
+	mu          sync.Mutex
+	reader      client.Reader
+	seq         uint64
+	buffer      []bufferedEvent
+	last        map[string]string
+	subscribers map[*eventSubscriber]struct{}
+}
+
+func newEventHub(log logr.Logger) *eventHub {
+	return &eventHub{
+		log:         log,
+		epoch:       strconv.FormatInt(time.Now().Unix(), 10),
+		last:        map[string]string{},
+		subscribers: map[*eventSubscriber]struct{}{},
+	}
+}
+
+func (h *eventHub) eventID(seq uint64) string {
+	return h.epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+func (h *eventHub) parseEventID(id string) (uint64, bool) {
+	epoch, seq, ok := strings.Cut(id, "-")
+	if !ok || epoch != h.epoch {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	return n, err == nil
+}
+
+func (h *eventHub) setReader(reader client.Reader) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.reader = reader
+}
+
+func (h *eventHub) currentReader() client.Reader {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.reader
+}
+
+// ready reports whether the informers are synced and streams can be served.
+func (h *eventHub) ready() bool {
+	return h != nil && h.currentReader() != nil
+}
+
+// publish adds ev to the buffer and sends it to the matching subscribers, unless
+// it repeats the last status of its resource. Statuses listed when the
+// informers start are only recorded, so that starting or restarting them does
+// not publish every existing resource.
+func (h *eventHub) publish(ev BuildEvent, initial bool) {
+	key := ev.Kind + "/" + ev.Namespace + "/" + ev.Name
+	fingerprint := eventFingerprint(ev)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	previous, known := h.last[key]
+	if ev.Type == eventTypeDeleted {
+		delete(h.last, key)
+	} else {
+		if known && previous == fingerprint {
+			return
+		}
+		h.last[key] = fingerprint
+		if initial && !known {
+			return
+		}
+	}
+
+	h.seq++
+	ev.ID = h.eventID(h.seq)
+	if ev.Time == "" {
+		ev.Time = time.Now().UTC().Format(time.RFC3339)
+	}
+	if len(h.buffer) >= eventBufferSize {
+		h.buffer = h.buffer[1:]
+	}
+	h.buffer = append(h.buffer, bufferedEvent{seq: h.seq, event: ev})
+
+	for sub := range h.subscribers {
+		if !sub.filter.matches(&ev) {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			// The stream fell behind; closing it makes the client reconnect
+			// and resume from the buffer.
+			close(sub.ch)
+			delete(h.subscribers, sub)
+		}
+	}
+}
+
+// subscribe adds a subscriber for filter. When lastEventID is still buffered,
+// the events published after it are returned to be replayed and resumed is true.
+func (h *eventHub) subscribe(filter eventFilter, lastEventID string) (*eventSubscriber, []BuildEvent, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub := &eventSubscriber{
+		filter: filter,
+		ch:     make(chan BuildEvent, eventSubscriberQueue),
+		cursor: h.eventID(h.seq),
+	}
+	h.subscribers[sub] = struct{}{}
+
+	seq, ok := h.parseEventID(lastEventID)
+	if !ok || seq > h.seq || seq < h.seq-uint64(len(h.buffer)) {
+		return sub, nil, false
+	}
+	var replay []BuildEvent
+	for _, buffered := range h.buffer {
+		if buffered.seq > seq && filter.matches(&buffered.event) {
+			replay = append(replay, buffered.event)
+		}
+	}
+	return sub, replay, true
+}
+
+func (h *eventHub) unsubscribe(sub *eventSubscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subscribers, sub)
+}
+
+// snapshot returns the current status of every resource matching filter, with
+// cursor as their ID.
+func (h *eventHub) snapshot(ctx context.Context, filter eventFilter, cursor string) ([]BuildEvent, error) {
+	reader := h.currentReader()
+	if reader == nil {
+		return nil, errEventsUnavailable
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	var events []BuildEvent
+	for _, k := range eventKinds {
+		if filter.kind != "" && filter.kind != k.kind {
+			continue
+		}
+		for _, namespace := range filter.namespaces {
+			var objs []client.Object
+			if filter.name != "" {
+				obj := k.newObject()
+				if err := reader.Get(ctx, client.ObjectKey{Name: filter.name, Namespace: namespace}, obj); err != nil {
+					if k8serrors.IsNotFound(err) {
+						continue
+					}
+					return nil, err
+				}
+				objs = append(objs, obj)
+			} else {
+				list := k.newList()
+				if err := reader.List(ctx, list, client.InNamespace(namespace)); err != nil {
+					return nil, err
+				}
+				if err := apimeta.EachListItem(list, func(o runtime.Object) error {
+					objs = append(objs, o.(client.Object))
+					return nil
+				}); err != nil {
+					return nil, err
+				}
+			}
+			for _, obj := range objs {
+				if ev, ok := statusEvent(ctx, reader, obj); ok {
+					ev.ID = cursor
+					ev.Time = now
+					events = append(events, ev)
+				}
+			}
+		}
+	}
+	return events, nil
+}
+
+// run keeps informers on the watched namespaces running until ctx is done,
+// restarting them when the namespaces change.
+func (h *eventHub) run(ctx context.Context, namespaces func() []string) {
+	var current []string
+	var stop context.CancelFunc
+	defer func() {
+		if stop != nil {
+			stop()
+		}
+	}()
+
+	resync := time.NewTicker(eventNamespaceResync)
+	defer resync.Stop()
+	for {
+		if want := namespaces(); !slices.Equal(want, current) {
+			if stop != nil {
+				stop()
+				h.setReader(nil)
+			}
+			informerCtx, cancel := context.WithCancel(ctx)
+			if err := h.startInformers(informerCtx, want); err != nil {
+				cancel()
+				current, stop = nil, nil
+				h.log.Error(err, "failed to start event informers, event streams are unavailable", "namespaces", want)
+			} else {
+				current, stop = want, cancel
+				h.log.Info("event informers started", "namespaces", want)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-resync.C:
+		}
+	}
+}
+
+// startInformers starts an informer cache of the streamed kinds, and of the
+// pipeline task pods reporting build progress, and waits for it to sync.
+func (h *eventHub) startInformers(ctx context.Context, namespaces []string) error {
+	cfg, err := getRESTConfigFromRequest(nil)
+	if err != nil {
+		return err
+	}
+	// Watches are long-lived
+	cfg.Timeout = 0
+
+	scheme := runtime.NewScheme()
+	if err := automotivev1alpha1.AddToScheme(scheme); err != nil {
+		return fmt.Errorf("failed to add automotive scheme: %w", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return fmt.Errorf("failed to add core scheme: %w", err)
+	}
+	taskPods, err := k8slabels.Parse(pipelineTaskSelector)
+	if err != nil {
+		return err
+	}
+	defaultNamespaces := make(map[string]cache.Config, len(namespaces))
+	for _, namespace := range namespaces {
+		defaultNamespaces[namespace] = cache.Config{}
+	}
+
+	informers, err := cache.New(cfg, cache.Options{
+		Scheme:            scheme,
+		DefaultNamespaces: defaultNamespaces,
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.Pod{}: {Label: taskPods},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create informer cache: %w", err)
+	}
+
+	handler := toolscache.ResourceEventHandlerDetailedFuncs{
+		AddFunc:    func(obj any, initial bool) { h.observe(ctx, informers, obj, initial) },
+		UpdateFunc: func(_, obj any) { h.observe(ctx, informers, obj, false) },
+		DeleteFunc: h.observeDeletion,
+	}
+	for _, k := range eventKinds {
+		informer, err := informers.GetInformer(ctx, k.newObject())
+		if err != nil {
+			return fmt.Errorf("failed to get %s informer: %w", k.kind, err)
+		}
+		if _, err := informer.AddEventHandler(handler); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", k.kind, err)
+		}
+	}
+	podInformer, err := informers.GetInformer(ctx, &corev1.Pod{})
+	if err != nil {
+		return fmt.Errorf("failed to get pod informer: %w", err)
+	}
+	if _, err := podInformer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(_, obj any) { h.observeTaskPod(ctx, informers, obj) },
+	}); err != nil {
+		return fmt.Errorf("failed to watch pods: %w", err)
+	}
+
+	go func() {
+		if err := informers.Start(ctx); err != nil {
+			h.log.Error(err, "event informers stopped")
+		}
+	}()
+	if !informers.WaitForCacheSync(ctx) {
+		return fmt.Errorf("event informers did not sync")
+	}
+	h.setReader(informers)
+	return nil
+}
+
+func (h *eventHub) observe(ctx context.Context, reader client.Reader, obj any, initial bool) {
+	o, ok := obj.(client.Object)
+	if !ok {
+		return
+	}
+	if ev, ok := statusEvent(ctx, reader, o); ok {
+		h.publish(ev, initial)
+	}
+}
+
+func (h *eventHub) observeDeletion(obj any) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	o, ok := obj.(client.Object)
+	if !ok {
+		return
+	}
+	ev, ok := statusEvent(context.Background(), nil, o)
+	if !ok {
+		return
+	}
+	h.publish(BuildEvent{
+		Type:        eventTypeDeleted,
+		Kind:        ev.Kind,
+		Name:        ev.Name,
+		Namespace:   ev.Namespace,
+		RequestedBy: ev.RequestedBy,
+	}, false)
+}
+
+// observeTaskPod republishes the ImageBuild a pipeline task pod belongs to, whose
+// progress step may have changed with the pod's progress annotation.
+func (h *eventHub) observeTaskPod(ctx context.Context, reader client.Reader, obj any) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	pipelineRun := pod.Labels["tekton.dev/pipelineRun"]
+	if pipelineRun == "" {
+		return
+	}
+	builds := &automotivev1alpha1.ImageBuildList{}
+	if err := reader.List(ctx, builds, client.InNamespace(pod.Namespace)); err != nil {
+		return
+	}
+	for i := range builds.Items {
+		build := &builds.Items[i]
+		if build.Status.PipelineRunName == pipelineRun || slices.ContainsFunc(build.Status.Architectures,
+			func(as automotivev1alpha1.ArchitectureBuildStatus) bool { return as.PipelineRunName == pipelineRun }) {
+			h.observe(ctx, reader, build, false)
+		}
+	}
+}
+
+// statusEvent returns the current status of obj as an event without ID and time.
+// The progress step of ImageBuilds is read from their task pods through reader,
+// when set.
+func statusEvent(ctx context.Context, reader client.Reader, obj client.Object) (BuildEvent, bool) {
+	ev := BuildEvent{
+		Type:        eventTypeStatus,
+		Name:        obj.GetName(),
+		Namespace:   obj.GetNamespace(),
+		RequestedBy: obj.GetAnnotations()[labels.RequestedBy],
+	}
+	switch o := obj.(type) {
+	case *automotivev1alpha1.ImageBuild:
+		ev.Kind = "ImageBuild"
+		ev.Phase, ev.Message, ev.Conditions = o.Status.Phase, o.Status.Message, o.Status.Conditions
+		ev.Step = imageBuildStep(ctx, reader, o)
+	case *automotivev1alpha1.ContainerBuild:
+		ev.Kind = "ContainerBuild"
+		ev.Phase, ev.Message, ev.Conditions = o.Status.Phase, o.Status.Message, o.Status.Conditions
+	case *automotivev1alpha1.ImageReseal:
+		ev.Kind = "ImageReseal"
+		ev.Phase, ev.Message = o.Status.Phase, o.Status.Message
+	case *automotivev1alpha1.FlashJob:
+		ev.Kind = "FlashJob"
+		ev.Phase, ev.Message, ev.Conditions = o.Status.Phase, o.Status.Message, o.Status.Conditions
+	default:
+		return ev, false
+	}
+	return ev, true
+}
+
+// imageBuildStep computes the progress step of build like GET
+// /v1/builds/{name}/progress, without the builder preparation estimate: the
+// step total settles once the first task reports its markers.
+func imageBuildStep(ctx context.Context, reader client.Reader, build *automotivev1alpha1.ImageBuild) *BuildStep {
+	active := isProgressActive(build.Status.Phase)
+	if !build.Spec.IsMultiArch() {
+		tasks := pipelineTaskProgress(ctx, reader, build.Namespace, build.Status.PipelineRunName, active)
+		return buildProgressStep(build, tasks, false)
+	}
+	archTasks := map[string][]taskProgress{}
+	for _, as := range build.Status.Architectures {
+		archActive := active && as.Phase == phaseBuilding
+		archTasks[as.Architecture] = pipelineTaskProgress(ctx, reader, build.Namespace, as.PipelineRunName, archActive)
+	}
+	return combineArchitectureSteps(build, architectureProgress(build, archTasks, false))
+}
+
+func pipelineTaskProgress(ctx context.Context, reader client.Reader, namespace, pipelineRunName string, active bool) []taskProgress {
+	if reader == nil || !active || strings.TrimSpace(pipelineRunName) == "" {
+		return nil
+	}
+	pods := &corev1.PodList{}
+	if err := reader.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels{
+		"tekton.dev/pipelineRun": pipelineRunName,
+		"tekton.dev/memberOf":    "tasks",
+	}); err != nil {
+		return nil
+	}
+	return taskProgressFromPods(pods.Items)
+}
+
+// eventFingerprint identifies the status carried by ev, to skip repeated statuses.
+func eventFingerprint(ev BuildEvent) string {
+	data, err := json.Marshal(struct {
+		Type       string             `json:"type"`
+		Phase      string             `json:"phase"`
+		Message    string             `json:"message"`
+		Step       *BuildStep         `json:"step"`
+		Conditions []metav1.Condition `json:"conditions"`
+	}{ev.Type, ev.Phase, ev.Message, ev.Step, ev.Conditions})
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// watchedNamespaces returns the Build API's namespace followed by the tenant
+// namespaces of the OperatorConfig.
+func (a *APIServer) watchedNamespaces() []string {
+	a.refreshAuthConfigIfNeeded()
+	a.authConfigMu.RLock()
+	tenancy := a.tenancy
+	a.authConfigMu.RUnlock()
+
+	namespaces := []string{resolveNamespace()}
+	if tenancy.IsEnabled() {
+		for _, namespace := range tenancy.Namespaces() {
+			if !slices.Contains(namespaces, namespace) {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+	}
+	return namespaces
+}
+
+// handleEvents streams the status changes of the builds, container builds,
+// reseals and flash jobs of the request's namespaces, optionally limited to one
+// kind and name.
+func (a *APIServer) handleEvents(c *gin.Context) {
+	filter := eventFilter{
+		namespaces: requestNamespaces(c),
+		kind:       c.Query("kind"),
+		name:       c.Query("name"),
+	}
+	if filter.kind != "" && !isEventKind(filter.kind) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unsupported kind %q", filter.kind)})
+		return
+	}
+	a.streamEvents(c, filter, false)
+}
+
+// handleWatchBuild streams the status changes of a build, starting with its
+// current status.
+func (a *APIServer) handleWatchBuild(c *gin.Context) {
+	a.streamEvents(c, eventFilter{
+		namespaces: []string{requestNamespace(c)},
+		kind:       "ImageBuild",
+		name:       c.Param("name"),
+	}, true)
+}
+
+// streamEvents streams the events matching filter as server-sent events. A
+// stream resumed with Last-Event-ID replays the events published after it;
+// when those are no longer buffered, or when snapshot is set for a new stream,
+// it starts with the current status of every matching resource.
+func (a *APIServer) streamEvents(c *gin.Context, filter eventFilter, snapshot bool) {
+	if !a.events.ready() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "event stream not available"})
+		return
+	}
+
+	lastEventID := strings.TrimSpace(c.GetHeader("Last-Event-ID"))
+	sub, events, resumed := a.events.subscribe(filter, lastEventID)
+	defer a.events.unsubscribe(sub)
+
+	streamDuration := time.Duration(a.limits.MaxLogStreamDurationMinutes) * time.Minute
+	ctx, cancel := context.WithTimeout(c.Request.Context(), streamDuration)
+	defer cancel()
+
+	if !resumed && (snapshot || lastEventID != "") {
+		current, err := a.events.snapshot(ctx, filter, sub.cursor)
+		if err != nil {
+			a.log.Error(err, "failed to read current status for event stream", "kind", filter.kind, "name", filter.name)
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "event stream not available"})
+			return
+		}
+		if snapshot && lastEventID == "" && filter.name != "" && len(current) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		events = current
+	}
+
+	setupEventStreamHeaders(c)
+	if _, err := fmt.Fprintf(c.Writer, "retry: %d\n\n", eventRetry.Milliseconds()); err != nil {
+		return
+	}
+	for _, ev := range events {
+		if err := writeEvent(c.Writer, ev); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-sub.ch:
+			if !ok {
+				return
+			}
+			if err := writeEvent(c.Writer, ev); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := io.WriteString(c.Writer, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+func setupEventStreamHeaders(c *gin.Context) {
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	c.Writer.Header().Set("X-Accel-Buffering", "no")
+	c.Writer.WriteHeader(http.StatusOK)
+}
+
+// writeEvent writes ev as a server-sent event.
+func writeEvent(w io.Writer, ev BuildEvent) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, data)
+	return err
+}
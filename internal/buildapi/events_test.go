@@ -0,0 +1,241 @@
+package buildapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+)
+
+var _ = Describe("Event streams", func() {
+	statusOf := func(name, phase string) BuildEvent {
+		return BuildEvent{Type: eventTypeStatus, Kind: "ImageBuild", Name: name, Namespace: "test-ns", Phase: phase}
+	}
+	allOf := eventFilter{namespaces: []string{"test-ns"}}
+
+	Describe("eventHub", func() {
+		var hub *eventHub
+
+		BeforeEach(func() {
+			hub = newEventHub(logr.Discard())
+		})
+
+		It("should publish status changes once", func() {
+			hub.publish(statusOf("build", "Building"), false)
+			hub.publish(statusOf("build", "Building"), false)
+			hub.publish(statusOf("build", "Completed"), false)
+			Expect(hub.buffer).To(HaveLen(2))
+			Expect(hub.buffer[1].event.ID).To(Equal(hub.epoch + "-2"))
+			Expect(hub.buffer[1].event.Time).NotTo(BeEmpty())
+		})
+
+		It("should only record statuses listed when the informers start", func() {
+			hub.publish(statusOf("build", "Building"), true)
+			Expect(hub.buffer).To(BeEmpty())
+
+			hub.publish(statusOf("build", "Building"), false)
+			Expect(hub.buffer).To(BeEmpty())
+			hub.publish(statusOf("build", "Completed"), true)
+			Expect(hub.buffer).To(HaveLen(1))
+		})
+
+		It("should send matching events to subscribers", func() {
+			sub, _, _ := hub.subscribe(eventFilter{namespaces: []string{"test-ns"}, name: "mine"}, "")
+			hub.publish(statusOf("other", "Building"), false)
+			hub.publish(statusOf("mine", "Building"), false)
+			other := statusOf("mine", "Building")
+			other.Namespace = "other-ns"
+			hub.publish(other, false)
+
+			Expect(sub.ch).To(HaveLen(1))
+			Expect((<-sub.ch).Name).To(Equal("mine"))
+		})
+
+		It("should replay the events after Last-Event-ID", func() {
+			hub.publish(statusOf("a", "Building"), false)
+			hub.publish(statusOf("b", "Building"), false)
+			hub.publish(statusOf("a", "Completed"), false)
+
+			_, replay, resumed := hub.subscribe(allOf, hub.eventID(1))
+			Expect(resumed).To(BeTrue())
+			Expect(replay).To(HaveLen(2))
+			Expect(replay[0].Name).To(Equal("b"))
+			Expect(replay[1].Phase).To(Equal("Completed"))
+
+			_, replay, resumed = hub.subscribe(allOf, hub.eventID(3))
+			Expect(resumed).To(BeTrue())
+			Expect(replay).To(BeEmpty())
+		})
+
+		It("should not resume unknown or evicted IDs", func() {
+			for _, id := range []string{"", "garbage", "1-1", hub.eventID(5)} {
+				_, _, resumed := hub.subscribe(allOf, id)
+				Expect(resumed).To(BeFalse(), id)
+			}
+
+			for i := 0; i <= eventBufferSize; i++ {
+				hub.publish(statusOf("build", strconv.Itoa(i)), false)
+			}
+			_, _, resumed := hub.subscribe(allOf, hub.eventID(0))
+			Expect(resumed).To(BeFalse())
+			_, _, resumed = hub.subscribe(allOf, hub.eventID(1))
+			Expect(resumed).To(BeTrue())
+		})
+
+		It("should close subscribers that fall behind", func() {
+			sub, _, _ := hub.subscribe(allOf, "")
+			for i := 0; i <= eventSubscriberQueue; i++ {
+				hub.publish(statusOf("build", strconv.Itoa(i)), false)
+			}
+			Expect(hub.subscribers).NotTo(HaveKey(sub))
+			Eventually(sub.ch).Should(BeClosed())
+		})
+	})
+
+	Describe("statusEvent", func() {
+		It("should compute the progress step from the task pods", func() {
+			scheme := runtime.NewScheme()
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "build-run-build-image-pod",
+					Namespace: "test-ns",
+					Labels: map[string]string{
+						"tekton.dev/pipelineRun":  "build-run",
+						"tekton.dev/memberOf":     "tasks",
+						"tekton.dev/pipelineTask": "build-image",
+					},
+					Annotations: map[string]string{labels.Progress: "Building image|2|5"},
+				},
+			}).Build()
+
+			build := &automotivev1alpha1.ImageBuild{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "build",
+					Namespace:   "test-ns",
+					Annotations: map[string]string{labels.RequestedBy: "alice"},
+				},
+				Status: automotivev1alpha1.ImageBuildStatus{
+					Phase:           phaseBuilding,
+					Message:         "Building",
+					PipelineRunName: "build-run",
+				},
+			}
+			ev, ok := statusEvent(context.Background(), reader, build)
+			Expect(ok).To(BeTrue())
+			Expect(ev.Kind).To(Equal("ImageBuild"))
+			Expect(ev.RequestedBy).To(Equal("alice"))
+			Expect(ev.Step).To(Equal(&BuildStep{Stage: "Building image", Done: 2, Total: 5}))
+
+			_, ok = statusEvent(context.Background(), reader, &corev1.Pod{})
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("GET /v1/builds/:name/watch", func() {
+		var (
+			server               *APIServer
+			ts                   *httptest.Server
+			originalNamespace    string
+			hasOriginalNamespace bool
+		)
+
+		BeforeEach(func() {
+			gin.SetMode(gin.TestMode)
+			originalNamespace, hasOriginalNamespace = os.LookupEnv("BUILD_API_NAMESPACE")
+			Expect(os.Setenv("BUILD_API_NAMESPACE", "test-ns")).To(Succeed())
+
+			scheme := runtime.NewScheme()
+			Expect(automotivev1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&automotivev1alpha1.ImageBuild{
+				ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "test-ns"},
+				Status:     automotivev1alpha1.ImageBuildStatus{Phase: phaseQueued},
+			}).Build()
+
+			server = NewAPIServer(":0", logr.Discard())
+			server.events.setReader(reader)
+			router := gin.New()
+			router.GET("/v1/builds/:name/watch", server.handleWatchBuild)
+			ts = httptest.NewServer(router)
+		})
+
+		AfterEach(func() {
+			ts.Close()
+			if hasOriginalNamespace {
+				Expect(os.Setenv("BUILD_API_NAMESPACE", originalNamespace)).To(Succeed())
+			} else {
+				Expect(os.Unsetenv("BUILD_API_NAMESPACE")).To(Succeed())
+			}
+		})
+
+		readEvent := func(r *bufio.Reader) BuildEvent {
+			var ev BuildEvent
+			for {
+				line, err := r.ReadString('\n')
+				Expect(err).NotTo(HaveOccurred())
+				if data, ok := strings.CutPrefix(line, "data: "); ok {
+					Expect(json.Unmarshal([]byte(data), &ev)).To(Succeed())
+				}
+				if line == "\n" && ev.ID != "" {
+					return ev
+				}
+			}
+		}
+
+		It("should stream the current status and its changes", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/v1/builds/build/watch", nil)
+			Expect(err).NotTo(HaveOccurred())
+			resp, err := http.DefaultClient.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = resp.Body.Close() }()
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(resp.Header.Get("Content-Type")).To(Equal("text/event-stream"))
+
+			r := bufio.NewReader(resp.Body)
+			snapshot := readEvent(r)
+			Expect(snapshot.Phase).To(Equal(phaseQueued))
+			Expect(snapshot.Step).NotTo(BeNil())
+
+			server.events.publish(statusOf("other", phaseBuilding), false)
+			server.events.publish(statusOf("build", phaseBuilding), false)
+			update := readEvent(r)
+			Expect(update.Name).To(Equal("build"))
+			Expect(update.Phase).To(Equal(phaseBuilding))
+			Expect(update.ID).To(Equal(server.events.eventID(2)))
+		})
+
+		It("should return 404 for unknown builds", func() {
+			resp, err := http.Get(ts.URL + "/v1/builds/missing/watch")
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = resp.Body.Close() }()
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		})
+
+		It("should return 503 while the informers are not running", func() {
+			server.events.setReader(nil)
+			resp, err := http.Get(ts.URL + "/v1/builds/build/watch")
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = resp.Body.Close() }()
+			Expect(resp.StatusCode).To(Equal(http.StatusServiceUnavailable))
+		})
+	})
+})
@@ -8,7 +8,7 @@ import (
 	"net/http"
 	"os"
 	"sort"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -21,7 +21,6 @@ import (
 
 	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
-	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/tasks"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 )
 
@@ -60,6 +59,19 @@ func (a *APIServer) createFlash(c *gin.Context) {
 		return
 	}
 
+	if err := validateDeviceTest(req.Test); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateFleetRequest(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateConsole(req.Console); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	k8sClient, err := getK8sClientOrFail(c)
 	if err != nil {
 		return
@@ -70,12 +82,12 @@ func (a *APIServer) createFlash(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 	requestedBy := a.resolveRequester(c)
 
-	// Load OperatorConfig for target mappings, image overrides, and lease duration defaults
+	// Load OperatorConfig to validate the target mapping
 	operatorConfig := &automotivev1alpha1.OperatorConfig{}
-	if err := k8sClient.Get(ctx, types.NamespacedName{Name: "config", Namespace: namespace}, operatorConfig); err != nil {
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: "config", Namespace: resolveNamespace()}, operatorConfig); err != nil {
 		if !k8serrors.IsNotFound(err) {
 			a.log.Error(err, "failed to load OperatorConfig for flash, using defaults")
 		}
@@ -89,12 +101,6 @@ func (a *APIServer) createFlash(c *gin.Context) {
 		return
 	}
 
-	// Replace placeholders in flash command
-	if flashCmd != "" {
-		flashCmd = strings.ReplaceAll(flashCmd, "{image_uri}", req.ImageRef)
-		flashCmd = strings.ReplaceAll(flashCmd, "{artifact_url}", req.ImageRef)
-	}
-
 	// Create Jumpstarter client config secret
 	secretName, createdSecret, secretErr := createFlashClientConfigSecret(ctx, clientset, namespace, req)
 	if secretErr != nil {
@@ -110,95 +116,56 @@ func (a *APIServer) createFlash(c *gin.Context) {
 		return
 	}
 
-	// Build task config from OperatorConfig for flash task generation
-	var flashBuildConfig *tasks.BuildConfig
-	if operatorConfig.Spec.OSBuilds != nil {
-		flashBuildConfig = &tasks.BuildConfig{
-			FlashTimeoutMinutes:  operatorConfig.Spec.OSBuilds.GetFlashTimeoutMinutes(),
-			DefaultLeaseDuration: operatorConfig.Spec.Jumpstarter.GetDefaultLeaseDuration(),
-		}
-	}
-
-	// Get the flash task spec
-	flashTask := tasks.GenerateFlashTask(namespace, flashBuildConfig)
-
-	// Lease duration: only resolve when not using an existing lease
-	// Fallback: request > FlashTimeoutMinutes (as HH:MM:SS) > Jumpstarter default > constant
-	leaseDuration := req.LeaseDuration
-	if req.LeaseName == "" && leaseDuration == "" {
-		if operatorConfig.Spec.OSBuilds != nil && operatorConfig.Spec.OSBuilds.FlashTimeoutMinutes > 0 {
-			m := operatorConfig.Spec.OSBuilds.FlashTimeoutMinutes
-			leaseDuration = fmt.Sprintf("%02d:%02d:00", m/60, m%60)
-		} else {
-			leaseDuration = operatorConfig.Spec.Jumpstarter.GetDefaultLeaseDuration()
-		}
-	}
-
-	// Build workspace bindings
-	workspaces := []tektonv1.WorkspaceBinding{
-		{
-			Name: "jumpstarter-client",
-			Secret: &corev1.SecretVolumeSource{
-				SecretName: secretName,
-			},
-		},
-	}
-	if flashOCIAuthSecretName != "" {
-		workspaces = append(workspaces, tektonv1.WorkspaceBinding{
-			Name: "flash-oci-auth",
-			Secret: &corev1.SecretVolumeSource{
-				SecretName: flashOCIAuthSecretName,
-			},
-		})
-	}
-
-	// Create the flash TaskRun
-	taskRun := &tektonv1.TaskRun{
+	// The FlashJob controller runs the flash TaskRun and resolves the lease
+	// duration and flash command defaults from OperatorConfig.
+	flashJob := &automotivev1alpha1.FlashJob{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      req.Name,
 			Namespace: namespace,
 			Labels: map[string]string{
-				labels.ManagedBy:    labels.ValueBuildAPI,
-				labels.PartOf:       labels.ValueAutomotiveDev,
-				labels.Name:         "flash-taskrun",
-				labels.FlashTaskRun: req.Name,
+				labels.ManagedBy: labels.ValueBuildAPI,
+				labels.PartOf:    labels.ValueAutomotiveDev,
 			},
 			Annotations: map[string]string{
 				labels.RequestedBy: requestedBy,
-				labels.ImageRef:    req.ImageRef,
 			},
 		},
-		Spec: tektonv1.TaskRunSpec{
-			ServiceAccountName: automotivev1alpha1.BuildServiceAccountName,
-			TaskSpec:           &flashTask.Spec,
-			Params: []tektonv1.Param{
-				{Name: "image-ref", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: req.ImageRef}},
-				{Name: "exporter-selector", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: exporterSelector}},
-				{Name: "flash-cmd", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: flashCmd}},
-				{Name: "lease-duration", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: leaseDuration}},
-				{Name: "lease-name", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: req.LeaseName}},
-			},
-			Workspaces: workspaces,
+		Spec: automotivev1alpha1.FlashJobSpec{
+			ImageRef:              req.ImageRef,
+			Target:                req.Target,
+			ExporterSelector:      exporterSelector,
+			FlashCmd:              flashCmd,
+			LeaseDuration:         req.LeaseDuration,
+			LeaseName:             req.LeaseName,
+			ClientConfigSecretRef: secretName,
+			RegistryAuthSecretRef: flashOCIAuthSecretName,
+			Test:                  deviceTestSpec(req.Test),
+			Fleet:                 fleetSpec(req.Fleet),
+			Console:               consoleSpec(req.Console),
 		},
 	}
 
-	if err := k8sClient.Create(ctx, taskRun); err != nil {
-		// Clean up secrets if TaskRun creation fails
+	if err := k8sClient.Create(ctx, flashJob); err != nil {
+		// Clean up secrets if FlashJob creation fails
 		_ = clientset.CoreV1().Secrets(namespace).Delete(ctx, secretName, metav1.DeleteOptions{})
 		if flashOCIAuthSecretName != "" {
 			_ = clientset.CoreV1().Secrets(namespace).Delete(ctx, flashOCIAuthSecretName, metav1.DeleteOptions{})
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to create flash TaskRun: %v", err)})
+		if k8serrors.IsAlreadyExists(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("flash %s already exists", req.Name)})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to create FlashJob: %v", err)})
 		return
 	}
 
-	// Set owner reference on secrets for automatic cleanup
+	// Set owner reference on secrets so they are deleted with the FlashJob
 	ownerRef := []metav1.OwnerReference{
 		{
-			APIVersion: "tekton.dev/v1",
-			Kind:       "TaskRun",
-			Name:       taskRun.Name,
-			UID:        taskRun.UID,
+			APIVersion: automotivev1alpha1.GroupVersion.String(),
+			Kind:       "FlashJob",
+			Name:       flashJob.Name,
+			UID:        flashJob.UID,
 		},
 	}
 	createdSecret.OwnerReferences = ownerRef
@@ -217,14 +184,12 @@ func (a *APIServer) createFlash(c *gin.Context) {
 	writeJSON(c, http.StatusAccepted, FlashResponse{
 		Name:        req.Name,
 		Phase:       phasePending,
-		Message:     "Flash TaskRun created",
+		Message:     "FlashJob created",
 		RequestedBy: requestedBy,
-		TaskRunName: taskRun.Name,
 	})
 }
 
 func (a *APIServer) listFlash(c *gin.Context) {
-	namespace := resolveNamespace()
 	limit, offset := parsePagination(c)
 
 	k8sClient, err := getK8sClientOrFail(c)
@@ -234,33 +199,33 @@ func (a *APIServer) listFlash(c *gin.Context) {
 
 	ctx := c.Request.Context()
 
-	// List TaskRuns with flash label
-	taskRunList := &tektonv1.TaskRunList{}
-	if err := k8sClient.List(ctx, taskRunList, client.InNamespace(namespace), client.HasLabels{labels.FlashTaskRun}); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to list flash TaskRuns: %v", err)})
+	flashJobList := &automotivev1alpha1.FlashJobList{}
+	if err := listInNamespaces(ctx, c, k8sClient, flashJobList); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to list FlashJobs: %v", err)})
 		return
 	}
 
 	// Sort by creation time, newest first
-	sort.Slice(taskRunList.Items, func(i, j int) bool {
-		return taskRunList.Items[j].CreationTimestamp.Before(&taskRunList.Items[i].CreationTimestamp)
+	sort.Slice(flashJobList.Items, func(i, j int) bool {
+		return flashJobList.Items[j].CreationTimestamp.Before(&flashJobList.Items[i].CreationTimestamp)
 	})
 
-	page := applyPagination(taskRunList.Items, limit, offset)
+	page := applyPagination(flashJobList.Items, limit, offset)
 
 	resp := make([]FlashListItem, 0, len(page))
-	for _, tr := range page {
-		phase, message := getTaskRunStatus(&tr)
+	for _, fj := range page {
+		phase, message := getFlashJobStatus(&fj)
 		var compStr string
-		if tr.Status.CompletionTime != nil {
-			compStr = tr.Status.CompletionTime.Format(time.RFC3339)
+		if fj.Status.CompletionTime != nil {
+			compStr = fj.Status.CompletionTime.Format(time.RFC3339)
 		}
 		resp = append(resp, FlashListItem{
-			Name:           tr.Name,
+			Name:           fj.Name,
 			Phase:          phase,
 			Message:        message,
-			RequestedBy:    tr.Annotations[labels.RequestedBy],
-			CreatedAt:      tr.CreationTimestamp.Format(time.RFC3339),
+			RequestedBy:    fj.Annotations[labels.RequestedBy],
+			Exporter:       fj.Status.Exporter,
+			CreatedAt:      fj.CreationTimestamp.Format(time.RFC3339),
 			CompletionTime: compStr,
 		})
 	}
@@ -268,7 +233,7 @@ func (a *APIServer) listFlash(c *gin.Context) {
 }
 
 func (a *APIServer) getFlash(c *gin.Context, name string) {
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 
 	k8sClient, err := getK8sClientOrFail(c)
 	if err != nil {
@@ -276,82 +241,159 @@ func (a *APIServer) getFlash(c *gin.Context, name string) {
 	}
 
 	ctx := c.Request.Context()
-	taskRun := &tektonv1.TaskRun{}
-	if err := getResourceOrFail(ctx, c, k8sClient, name, namespace, taskRun, "flash TaskRun"); err != nil {
+	flashJob := &automotivev1alpha1.FlashJob{}
+	if err := getResourceOrFail(ctx, c, k8sClient, name, namespace, flashJob, "flash"); err != nil {
 		return
 	}
 
-	// Verify it's a flash TaskRun
-	if taskRun.Labels[labels.FlashTaskRun] == "" {
-		c.JSON(http.StatusNotFound, gin.H{"error": "flash TaskRun not found"})
-		return
-	}
-
-	phase, message := getTaskRunStatus(taskRun)
+	phase, message := getFlashJobStatus(flashJob)
 	var startStr, compStr string
-	if taskRun.Status.StartTime != nil {
-		startStr = taskRun.Status.StartTime.Format(time.RFC3339)
+	if flashJob.Status.StartTime != nil {
+		startStr = flashJob.Status.StartTime.Format(time.RFC3339)
 	}
-	if taskRun.Status.CompletionTime != nil {
-		compStr = taskRun.Status.CompletionTime.Format(time.RFC3339)
+	if flashJob.Status.CompletionTime != nil {
+		compStr = flashJob.Status.CompletionTime.Format(time.RFC3339)
 	}
 
 	writeJSON(c, http.StatusOK, FlashResponse{
-		Name:           taskRun.Name,
+		Name:           flashJob.Name,
 		Phase:          phase,
 		Message:        message,
-		RequestedBy:    taskRun.Annotations[labels.RequestedBy],
+		RequestedBy:    flashJob.Annotations[labels.RequestedBy],
 		StartTime:      startStr,
 		CompletionTime: compStr,
-		TaskRunName:    taskRun.Name,
+		TaskRunName:    flashJob.Status.TaskRunName,
+		LeaseID:        flashJob.Status.LeaseID,
+		Exporter:       flashJob.Status.Exporter,
+		DeviceTests:    deviceTestResults(flashJob.Status.DeviceTests),
+		ConsoleRef:     flashJob.Status.ConsoleRef,
+		Boards:         flashBoards(flashJob.Status.Boards),
 	})
 }
 
-func getTaskRunStatus(tr *tektonv1.TaskRun) (phase, message string) {
-	if tr.Status.CompletionTime != nil {
-		for _, cond := range tr.Status.Conditions {
-			if cond.Type == "Succeeded" {
-				if cond.Status == corev1.ConditionTrue {
-					return phaseCompleted, "Flash completed successfully"
-				}
-				if cond.Message == "" {
-					return phaseFailed, "Flash failed"
-				}
-				return phaseFailed, cond.Message
-			}
-		}
-		return phaseFailed, "Flash failed"
+// validateFleetRequest checks that a fleet flash selects its boards in exactly one way.
+func validateFleetRequest(req *FlashRequest) error {
+	fleet := req.Fleet
+	if fleet == nil {
+		return nil
+	}
+	if req.LeaseName != "" {
+		return fmt.Errorf("fleet flash cannot use an existing lease")
+	}
+	if fleet.Count < 0 || fleet.MaxParallel < 0 {
+		return fmt.Errorf("fleet count and maxParallel must not be negative")
 	}
+	if (fleet.Count > 0) == fleet.AllExporters {
+		return fmt.Errorf("fleet requires exactly one of count and allExporters")
+	}
+	return nil
+}
 
-	if tr.Status.StartTime != nil {
-		return phaseRunning, "Flash in progress"
+func fleetSpec(fleet *FleetFlashRequest) *automotivev1alpha1.FleetFlashSpec {
+	if fleet == nil {
+		return nil
 	}
+	return &automotivev1alpha1.FleetFlashSpec{
+		Count:        fleet.Count,
+		AllExporters: fleet.AllExporters,
+		MaxParallel:  fleet.MaxParallel,
+	}
+}
 
-	return phasePending, "Waiting to start"
+func flashBoards(boards []automotivev1alpha1.FleetBoardStatus) []FlashBoardStatus {
+	if len(boards) == 0 {
+		return nil
+	}
+	out := make([]FlashBoardStatus, 0, len(boards))
+	for _, board := range boards {
+		out = append(out, FlashBoardStatus{
+			Board:       board.Board,
+			Phase:       board.Phase,
+			Message:     board.Message,
+			TaskRunName: board.TaskRunName,
+			LeaseID:     board.LeaseID,
+			Exporter:    board.Exporter,
+			DeviceTests: deviceTestResults(board.DeviceTests),
+			ConsoleRef:  board.ConsoleRef,
+		})
+	}
+	return out
 }
 
-func (a *APIServer) streamFlashLogs(c *gin.Context, name string) {
-	namespace := resolveNamespace()
+// flashTaskRunName returns the TaskRun whose logs are streamed for a FlashJob.
+// Fleet flashes need the board selected with the "board" query parameter.
+func flashTaskRunName(c *gin.Context, flashJob *automotivev1alpha1.FlashJob) (string, bool) {
+	if flashJob.Spec.Fleet == nil {
+		return flashJob.Status.TaskRunName, true
+	}
+	boardParam := c.Query("board")
+	if boardParam == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("flash %s is a fleet flash, select a board with ?board=N", flashJob.Name)})
+		return "", false
+	}
+	board, err := strconv.Atoi(boardParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid board %q", boardParam)})
+		return "", false
+	}
+	for _, status := range flashJob.Status.Boards {
+		if int(status.Board) == board {
+			return status.TaskRunName, true
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("flash %s has no board %d", flashJob.Name, board)})
+	return "", false
+}
 
+// getFlashJobStatus returns the phase and message of a FlashJob, treating a
+// job not yet picked up by the controller as pending.
+func getFlashJobStatus(fj *automotivev1alpha1.FlashJob) (phase, message string) {
+	if fj.Status.Phase == "" {
+		return phasePending, "Waiting to start"
+	}
+	return fj.Status.Phase, fj.Status.Message
+}
+
+func (a *APIServer) streamFlashLogs(c *gin.Context, name string) {
 	k8sClient, err := getK8sClientOrFail(c)
 	if err != nil {
 		return
 	}
+	flashJob := &automotivev1alpha1.FlashJob{}
+	if err := getResourceOrFail(c.Request.Context(), c, k8sClient, name, requestNamespace(c), flashJob, "flash"); err != nil {
+		return
+	}
+
+	// TaskRun pods use step containers with naming convention "step-<step-name>"
+	a.streamFlashContainer(c, k8sClient, flashJob, "step-flash", "Flash TaskRun Logs")
+}
+
+// streamFlashContainer follows the logs of one container of the flash TaskRun
+// pod of a FlashJob.
+func (a *APIServer) streamFlashContainer(
+	c *gin.Context,
+	k8sClient client.Client,
+	flashJob *automotivev1alpha1.FlashJob,
+	containerName, banner string,
+) {
+	namespace := flashJob.Namespace
 	clientset, err := getClientsetOrFail(c)
 	if err != nil {
 		return
 	}
 
 	ctx := c.Request.Context()
-
-	// Verify the TaskRun exists and is a flash TaskRun
-	taskRun := &tektonv1.TaskRun{}
-	if err := getResourceOrFail(ctx, c, k8sClient, name, namespace, taskRun, "flash TaskRun"); err != nil {
+	taskRunName, ok := flashTaskRunName(c, flashJob)
+	if !ok {
 		return
 	}
-	if taskRun.Labels[labels.FlashTaskRun] == "" {
-		c.JSON(http.StatusNotFound, gin.H{"error": "flash TaskRun not found"})
-		return
+	taskRun := &tektonv1.TaskRun{}
+	if taskRunName != "" {
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: taskRunName, Namespace: namespace}, taskRun); err != nil &&
+			!k8serrors.IsNotFound(err) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get flash TaskRun: %v", err)})
+			return
+		}
 	}
 
 	sinceTime := parseSinceTime(c.Query("since"))
@@ -368,9 +410,6 @@ func (a *APIServer) streamFlashLogs(c *gin.Context, name string) {
 
 	setupLogStreamHeaders(c)
 
-	// TaskRun pods use step containers with naming convention "step-<step-name>"
-	containerName := "step-flash"
-
 	// Stream logs, retrying while the container is still initializing
 	logReq := clientset.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
 		Container: containerName,
@@ -403,7 +442,7 @@ func (a *APIServer) streamFlashLogs(c *gin.Context, name string) {
 		}
 	}()
 
-	_, _ = c.Writer.Write([]byte("\n===== Flash TaskRun Logs =====\n\n"))
+	_, _ = fmt.Fprintf(c.Writer, "\n===== %s =====\n\n", banner)
 	c.Writer.Flush()
 
 	scanner := bufio.NewScanner(stream)
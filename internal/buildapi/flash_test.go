@@ -12,11 +12,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
-	apis "knative.dev/pkg/apis"
-	duckv1 "knative.dev/pkg/apis/duck/v1"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
 	"github.com/gin-gonic/gin"
 	"github.com/go-logr/logr"
@@ -37,6 +36,7 @@ var _ = Describe("Flash", func() {
 		scheme := runtime.NewScheme()
 		Expect(corev1.AddToScheme(scheme)).To(Succeed())
 		Expect(tektonv1.AddToScheme(scheme)).To(Succeed())
+		Expect(automotivev1alpha1.AddToScheme(scheme)).To(Succeed())
 		builder := fake.NewClientBuilder().WithScheme(scheme)
 		for _, obj := range objs {
 			builder = builder.WithObjects(obj)
@@ -44,51 +44,44 @@ var _ = Describe("Flash", func() {
 		return builder.Build()
 	}
 
-	newFlashTaskRun := func(name, requestedBy, phase string) *tektonv1.TaskRun {
-		tr := &tektonv1.TaskRun{
+	newFlashJob := func(name, requestedBy, phase string) *automotivev1alpha1.FlashJob {
+		fj := &automotivev1alpha1.FlashJob{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      name,
 				Namespace: "test-ns",
-				Labels: map[string]string{
-					labels.FlashTaskRun: name,
-				},
 				Annotations: map[string]string{
 					labels.RequestedBy: requestedBy,
 				},
 				CreationTimestamp: metav1.NewTime(time.Now()),
 			},
+			Spec: automotivev1alpha1.FlashJobSpec{
+				ImageRef:              "quay.io/org/disk:v1",
+				ClientConfigSecretRef: name + "-jumpstarter-client",
+			},
 		}
+		now := metav1.Now()
 		switch phase {
 		case "running":
-			now := metav1.Now()
-			tr.Status.StartTime = &now
+			fj.Status.Phase = automotivev1alpha1.FlashJobPhaseRunning
+			fj.Status.Message = "Flash in progress"
+			fj.Status.TaskRunName = name
+			fj.Status.StartTime = &now
 		case "completed":
-			now := metav1.Now()
-			tr.Status.StartTime = &now
-			tr.Status.CompletionTime = &now
-			tr.Status.Status = duckv1.Status{
-				Conditions: duckv1.Conditions{
-					{
-						Type:   apis.ConditionSucceeded,
-						Status: corev1.ConditionTrue,
-					},
-				},
-			}
+			fj.Status.Phase = automotivev1alpha1.FlashJobPhaseCompleted
+			fj.Status.Message = "Flash completed successfully"
+			fj.Status.TaskRunName = name
+			fj.Status.StartTime = &now
+			fj.Status.CompletionTime = &now
+			fj.Status.LeaseID = "lease-123"
+			fj.Status.Exporter = "board-01"
 		case "failed":
-			now := metav1.Now()
-			tr.Status.StartTime = &now
-			tr.Status.CompletionTime = &now
-			tr.Status.Status = duckv1.Status{
-				Conditions: duckv1.Conditions{
-					{
-						Type:    apis.ConditionSucceeded,
-						Status:  corev1.ConditionFalse,
-						Message: "build step failed",
-					},
-				},
-			}
+			fj.Status.Phase = automotivev1alpha1.FlashJobPhaseFailed
+			fj.Status.Message = "build step failed"
+			fj.Status.TaskRunName = name
+			fj.Status.StartTime = &now
+			fj.Status.CompletionTime = &now
 		}
-		return tr
+		return fj
 	}
 
 	BeforeEach(func() {
@@ -110,47 +103,22 @@ var _ = Describe("Flash", func() {
 		}
 	})
 
-	Context("getTaskRunStatus", func() {
-		It("should return pending for TaskRun with no start time", func() {
-			tr := newFlashTaskRun("test-flash", "alice", "pending")
-			phase, msg := getTaskRunStatus(tr)
+	Context("getFlashJobStatus", func() {
+		It("should return pending for a FlashJob not yet reconciled", func() {
+			phase, msg := getFlashJobStatus(newFlashJob("test-flash", "alice", ""))
 			Expect(phase).To(Equal(phasePending))
 			Expect(msg).To(Equal("Waiting to start"))
 		})
 
-		It("should return running for started TaskRun", func() {
-			tr := newFlashTaskRun("test-flash", "alice", "running")
-			phase, msg := getTaskRunStatus(tr)
-			Expect(phase).To(Equal(phaseRunning))
-			Expect(msg).To(Equal("Flash in progress"))
-		})
-
-		It("should return completed for successful TaskRun", func() {
-			tr := newFlashTaskRun("test-flash", "alice", "completed")
-			phase, msg := getTaskRunStatus(tr)
-			Expect(phase).To(Equal(phaseCompleted))
-			Expect(msg).To(Equal("Flash completed successfully"))
-		})
-
-		It("should return failed with message for failed TaskRun", func() {
-			tr := newFlashTaskRun("test-flash", "alice", "failed")
-			phase, msg := getTaskRunStatus(tr)
+		It("should return the FlashJob phase and message", func() {
+			phase, msg := getFlashJobStatus(newFlashJob("test-flash", "alice", "failed"))
 			Expect(phase).To(Equal(phaseFailed))
 			Expect(msg).To(Equal("build step failed"))
 		})
-
-		It("should return failed when completed with no Succeeded condition", func() {
-			tr := newFlashTaskRun("test-flash", "alice", "pending")
-			now := metav1.Now()
-			tr.Status.CompletionTime = &now
-			phase, msg := getTaskRunStatus(tr)
-			Expect(phase).To(Equal(phaseFailed))
-			Expect(msg).To(Equal("Flash failed"))
-		})
 	})
 
 	Context("getFlash", func() {
-		It("should return 404 for nonexistent flash TaskRun", func() {
+		It("should return 404 for nonexistent FlashJob", func() {
 			fakeClient := newFakeClient()
 			getClientFromRequestFn = func(_ *gin.Context) (ctrlclient.Client, error) {
 				return fakeClient, nil
@@ -163,10 +131,10 @@ var _ = Describe("Flash", func() {
 			server.getFlash(c, "nonexistent")
 
 			Expect(w.Code).To(Equal(http.StatusNotFound))
-			Expect(w.Body.String()).To(ContainSubstring("flash TaskRun not found"))
+			Expect(w.Body.String()).To(ContainSubstring("flash not found"))
 		})
 
-		It("should return 404 for TaskRun without flash label", func() {
+		It("should return 404 for a TaskRun without FlashJob", func() {
 			tr := &tektonv1.TaskRun{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "not-a-flash",
@@ -188,9 +156,9 @@ var _ = Describe("Flash", func() {
 			Expect(w.Code).To(Equal(http.StatusNotFound))
 		})
 
-		It("should return flash details for valid flash TaskRun", func() {
-			tr := newFlashTaskRun("my-flash", "alice", "running")
-			fakeClient := newFakeClient(tr)
+		It("should return flash details for a FlashJob", func() {
+			fj := newFlashJob("my-flash", "alice", "completed")
+			fakeClient := newFakeClient(fj)
 			getClientFromRequestFn = func(_ *gin.Context) (ctrlclient.Client, error) {
 				return fakeClient, nil
 			}
@@ -205,13 +173,65 @@ var _ = Describe("Flash", func() {
 			var resp FlashResponse
 			Expect(json.Unmarshal(w.Body.Bytes(), &resp)).To(Succeed())
 			Expect(resp.Name).To(Equal("my-flash"))
-			Expect(resp.Phase).To(Equal(phaseRunning))
+			Expect(resp.Phase).To(Equal(phaseCompleted))
 			Expect(resp.RequestedBy).To(Equal("alice"))
+			Expect(resp.TaskRunName).To(Equal("my-flash"))
+			Expect(resp.LeaseID).To(Equal("lease-123"))
+			Expect(resp.Exporter).To(Equal("board-01"))
+		})
+
+		It("should return per-board state for a fleet flash", func() {
+			fj := newFlashJob("fleet-flash", "alice", "running")
+			fj.Spec.Fleet = &automotivev1alpha1.FleetFlashSpec{Count: 2}
+			fj.Status.TaskRunName = ""
+			fj.Status.Boards = []automotivev1alpha1.FleetBoardStatus{
+				{Board: 1, Phase: automotivev1alpha1.FlashJobPhaseCompleted, TaskRunName: "fleet-flash-board-1", Exporter: "rack-01"},
+				{Board: 2, Phase: automotivev1alpha1.FlashJobPhaseRunning, TaskRunName: "fleet-flash-board-2"},
+			}
+			fakeClient := newFakeClient(fj)
+			getClientFromRequestFn = func(_ *gin.Context) (ctrlclient.Client, error) {
+				return fakeClient, nil
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/v1/flashes/fleet-flash", nil)
+
+			server.getFlash(c, "fleet-flash")
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var resp FlashResponse
+			Expect(json.Unmarshal(w.Body.Bytes(), &resp)).To(Succeed())
+			Expect(resp.Boards).To(HaveLen(2))
+			Expect(resp.Boards[0].Exporter).To(Equal("rack-01"))
+			Expect(resp.Boards[1].Phase).To(Equal(phaseRunning))
+		})
+	})
+
+	Context("validateFleetRequest", func() {
+		It("accepts a request without fleet", func() {
+			Expect(validateFleetRequest(&FlashRequest{})).To(Succeed())
+		})
+
+		It("accepts a count or all exporters", func() {
+			Expect(validateFleetRequest(&FlashRequest{Fleet: &FleetFlashRequest{Count: 12, MaxParallel: 4}})).To(Succeed())
+			Expect(validateFleetRequest(&FlashRequest{Fleet: &FleetFlashRequest{AllExporters: true}})).To(Succeed())
+		})
+
+		It("requires exactly one of count and allExporters", func() {
+			Expect(validateFleetRequest(&FlashRequest{Fleet: &FleetFlashRequest{}})).To(MatchError(ContainSubstring("exactly one")))
+			Expect(validateFleetRequest(&FlashRequest{Fleet: &FleetFlashRequest{Count: 2, AllExporters: true}})).
+				To(MatchError(ContainSubstring("exactly one")))
+		})
+
+		It("rejects an existing lease", func() {
+			req := &FlashRequest{LeaseName: "lease-1", Fleet: &FleetFlashRequest{Count: 2}}
+			Expect(validateFleetRequest(req)).To(MatchError(ContainSubstring("existing lease")))
 		})
 	})
 
 	Context("listFlash", func() {
-		It("should return empty list when no flash TaskRuns exist", func() {
+		It("should return empty list when no FlashJobs exist", func() {
 			fakeClient := newFakeClient()
 			getClientFromRequestFn = func(_ *gin.Context) (ctrlclient.Client, error) {
 				return fakeClient, nil
@@ -229,13 +249,13 @@ var _ = Describe("Flash", func() {
 			Expect(resp).To(BeEmpty())
 		})
 
-		It("should list flash TaskRuns sorted by creation time", func() {
-			tr1 := newFlashTaskRun("flash-old", "alice", "completed")
-			tr1.CreationTimestamp = metav1.NewTime(time.Now().Add(-1 * time.Hour))
-			tr2 := newFlashTaskRun("flash-new", "bob", "running")
-			tr2.CreationTimestamp = metav1.NewTime(time.Now())
+		It("should list FlashJobs sorted by creation time", func() {
+			fj1 := newFlashJob("flash-old", "alice", "completed")
+			fj1.CreationTimestamp = metav1.NewTime(time.Now().Add(-1 * time.Hour))
+			fj2 := newFlashJob("flash-new", "bob", "running")
+			fj2.CreationTimestamp = metav1.NewTime(time.Now())
 
-			fakeClient := newFakeClient(tr1, tr2)
+			fakeClient := newFakeClient(fj1, fj2)
 			getClientFromRequestFn = func(_ *gin.Context) (ctrlclient.Client, error) {
 				return fakeClient, nil
 			}
@@ -251,7 +271,9 @@ var _ = Describe("Flash", func() {
 			Expect(json.Unmarshal(w.Body.Bytes(), &resp)).To(Succeed())
 			Expect(resp).To(HaveLen(2))
 			Expect(resp[0].Name).To(Equal("flash-new"))
+			Expect(resp[0].Phase).To(Equal(phaseRunning))
 			Expect(resp[1].Name).To(Equal("flash-old"))
+			Expect(resp[1].Exporter).To(Equal("board-01"))
 		})
 	})
 
@@ -265,7 +287,7 @@ var _ = Describe("Flash", func() {
 			}
 		})
 
-		It("should return 404 for nonexistent flash TaskRun", func() {
+		It("should return 404 for nonexistent FlashJob", func() {
 			fakeClient := newFakeClient()
 			getClientFromRequestFn = func(_ *gin.Context) (ctrlclient.Client, error) {
 				return fakeClient, nil
@@ -281,8 +303,9 @@ var _ = Describe("Flash", func() {
 		})
 
 		It("should return 503 when flash pod is not ready", func() {
-			tr := newFlashTaskRun("my-flash", "alice", "pending")
-			fakeClient := newFakeClient(tr)
+			fj := newFlashJob("my-flash", "alice", "running")
+			tr := &tektonv1.TaskRun{ObjectMeta: metav1.ObjectMeta{Name: "my-flash", Namespace: "test-ns"}}
+			fakeClient := newFakeClient(fj, tr)
 			getClientFromRequestFn = func(_ *gin.Context) (ctrlclient.Client, error) {
 				return fakeClient, nil
 			}
@@ -296,5 +319,86 @@ var _ = Describe("Flash", func() {
 			Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
 			Expect(w.Body.String()).To(ContainSubstring("flash pod not ready"))
 		})
+
+		It("should require a board for a fleet flash", func() {
+			fj := newFlashJob("fleet-flash", "alice", "running")
+			fj.Spec.Fleet = &automotivev1alpha1.FleetFlashSpec{Count: 2}
+			fj.Status.Boards = []automotivev1alpha1.FleetBoardStatus{
+				{Board: 1, Phase: automotivev1alpha1.FlashJobPhaseRunning, TaskRunName: "fleet-flash-board-1"},
+			}
+			fakeClient := newFakeClient(fj)
+			getClientFromRequestFn = func(_ *gin.Context) (ctrlclient.Client, error) {
+				return fakeClient, nil
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/v1/flashes/fleet-flash/logs", nil)
+			server.streamFlashLogs(c, "fleet-flash")
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			Expect(w.Body.String()).To(ContainSubstring("?board=N"))
+
+			w = httptest.NewRecorder()
+			c, _ = gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/v1/flashes/fleet-flash/logs?board=3", nil)
+			server.streamFlashLogs(c, "fleet-flash")
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("validateConsole", func() {
+		It("accepts no console, the default duration and an explicit duration", func() {
+			Expect(validateConsole(nil)).To(Succeed())
+			Expect(validateConsole(&ConsoleCaptureRequest{})).To(Succeed())
+			Expect(validateConsole(&ConsoleCaptureRequest{Duration: "10m"})).To(Succeed())
+		})
+
+		It("rejects invalid and excessive durations", func() {
+			Expect(validateConsole(&ConsoleCaptureRequest{Duration: "soon"})).To(MatchError(ContainSubstring("positive duration")))
+			Expect(validateConsole(&ConsoleCaptureRequest{Duration: "-5m"})).To(MatchError(ContainSubstring("positive duration")))
+			Expect(validateConsole(&ConsoleCaptureRequest{Duration: "2h"})).To(MatchError(ContainSubstring("exceeds")))
+		})
+	})
+
+	Context("streamFlashConsole", func() {
+		BeforeEach(func() {
+			getRESTConfigFromRequestFn = func(_ *gin.Context) (*rest.Config, error) {
+				return &rest.Config{Host: "https://fake-k8s:6443"}, nil
+			}
+		})
+
+		It("should return 404 when the flash does not capture the console", func() {
+			fakeClient := newFakeClient(newFlashJob("my-flash", "alice", "running"))
+			getClientFromRequestFn = func(_ *gin.Context) (ctrlclient.Client, error) {
+				return fakeClient, nil
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/v1/flash/my-flash/console", nil)
+
+			server.streamFlashConsole(c, "my-flash")
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+			Expect(w.Body.String()).To(ContainSubstring("does not capture the serial console"))
+		})
+
+		It("should return 503 when flash pod is not ready", func() {
+			fj := newFlashJob("my-flash", "alice", "running")
+			fj.Spec.Console = &automotivev1alpha1.ConsoleCaptureSpec{}
+			tr := &tektonv1.TaskRun{ObjectMeta: metav1.ObjectMeta{Name: "my-flash", Namespace: "test-ns"}}
+			fakeClient := newFakeClient(fj, tr)
+			getClientFromRequestFn = func(_ *gin.Context) (ctrlclient.Client, error) {
+				return fakeClient, nil
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/v1/flash/my-flash/console", nil)
+
+			server.streamFlashConsole(c, "my-flash")
+
+			Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+		})
 	})
 })
@@ -34,13 +34,29 @@ func loadInternalJWTConfig() (*internalJWTConfig, error) {
 	}, nil
 }
 
+// clientTokenClaims are the claims of internal tokens issued to OIDC users.
+// Groups carries the OIDC groups so tenant routing works with internal tokens.
+type clientTokenClaims struct {
+	jwt.RegisteredClaims
+	Groups []string `json:"groups,omitempty"`
+}
+
 func validateInternalJWT(tokenString string, cfg *internalJWTConfig) (string, bool) {
+	claims, ok := parseInternalJWT(tokenString, cfg)
+	if !ok {
+		return "", false
+	}
+	return claims.Subject, true
+}
+
+// parseInternalJWT validates an internal token and returns its claims.
+func parseInternalJWT(tokenString string, cfg *internalJWTConfig) (*clientTokenClaims, bool) {
 	// Defensive nil check (function is currently always called after nil check, but safe to guard)
 	if cfg == nil {
-		return "", false
+		return nil, false
 	}
 
-	claims := &jwt.RegisteredClaims{}
+	claims := &clientTokenClaims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
 		if token.Method != jwt.SigningMethodHS256 {
 			return nil, fmt.Errorf("unexpected signing method: %s", token.Header["alg"])
@@ -48,29 +64,29 @@ func validateInternalJWT(tokenString string, cfg *internalJWTConfig) (string, bo
 		return cfg.key, nil
 	})
 	if err != nil || !token.Valid {
-		return "", false
+		return nil, false
 	}
 
 	if claims.Issuer != cfg.issuer {
-		return "", false
+		return nil, false
 	}
 	if cfg.audience != "" && !audienceContains(claims.Audience, cfg.audience) {
-		return "", false
+		return nil, false
 	}
 	now := time.Now()
 	if claims.ExpiresAt == nil || now.After(claims.ExpiresAt.Time) {
-		return "", false
+		return nil, false
 	}
 	if claims.NotBefore != nil && now.Before(claims.NotBefore.Time) {
-		return "", false
+		return nil, false
 	}
 
 	// Reject tokens with empty subject - they don't represent a valid authenticated identity
 	if claims.Subject == "" {
-		return "", false
+		return nil, false
 	}
 
-	return claims.Subject, true
+	return claims, true
 }
 
 func audienceContains(audiences jwt.ClaimStrings, audience string) bool {
@@ -0,0 +1,217 @@
+package buildapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/jumpstarter"
+)
+
+// maxLeaseExtension bounds how much a single extend request adds to a lease.
+const maxLeaseExtension = 24 * time.Hour
+
+// registerLeaseRoutes registers the lease API routes on the v1 group.
+func (a *APIServer) registerLeaseRoutes(v1 *gin.RouterGroup) {
+	leaseGroup := v1.Group("/leases")
+	leaseGroup.Use(a.authMiddleware(), a.authorizeMiddleware())
+	{
+		leaseGroup.GET("", a.wrapHandler("list leases", a.listLeases))
+		leaseGroup.GET("/:name", a.wrapNamedHandler("get lease", a.getLease))
+		leaseGroup.POST("/:name/extend", a.wrapNamedHandler("extend lease", a.extendLease))
+		leaseGroup.POST("/:name/release", a.wrapNamedHandler("release lease", a.releaseLease))
+	}
+}
+
+// listLeases returns the leases held by the requester's builds, flash jobs and
+// workspaces. Ended leases are only included with ?all=true.
+func (a *APIServer) listLeases(c *gin.Context) {
+	k8sClient, err := getK8sClientOrFail(c)
+	if err != nil {
+		return
+	}
+
+	ctx := c.Request.Context()
+	requester := a.resolveRequester(c)
+	includeEnded := c.Query("all") == "true"
+
+	fallback := leaseFallbackNamespace(ctx, k8sClient)
+	leases := make([]LeaseResponse, 0)
+	for _, namespace := range requestNamespaces(c) {
+		owners, err := jumpstarter.FindOwners(ctx, k8sClient, namespace)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to find leases: %v", err)})
+			return
+		}
+		leaseIDs := make([]string, 0, len(owners))
+		for leaseID, leaseOwners := range owners {
+			if jumpstarter.OwnedBy(leaseOwners, requester) {
+				leaseIDs = append(leaseIDs, leaseID)
+			}
+		}
+		sort.Strings(leaseIDs)
+
+		for _, leaseID := range leaseIDs {
+			leaseNamespace := jumpstarter.LeaseNamespace(ctx, k8sClient, namespace, owners[leaseID], fallback)
+			lease, err := jumpstarter.GetLease(ctx, k8sClient, leaseNamespace, leaseID)
+			if err != nil {
+				if k8serrors.IsNotFound(err) {
+					continue
+				}
+				writeLeaseError(c, leaseID, err)
+				return
+			}
+			if !lease.Active() && !includeEnded {
+				continue
+			}
+			leases = append(leases, leaseResponse(lease, owners[leaseID]))
+		}
+	}
+
+	c.JSON(http.StatusOK, leases)
+}
+
+func (a *APIServer) getLease(c *gin.Context, name string) {
+	_, lease, owners, err := a.getOwnedLease(c, name)
+	if err != nil {
+		return // response already sent
+	}
+	c.JSON(http.StatusOK, leaseResponse(lease, owners))
+}
+
+func (a *APIServer) extendLease(c *gin.Context, name string) {
+	var req ExtendLeaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON request"})
+		return
+	}
+	extra, err := time.ParseDuration(req.Duration)
+	if err != nil || extra <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("duration %q must be a positive duration (e.g. 1h)", req.Duration)})
+		return
+	}
+	if extra > maxLeaseExtension {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("duration %s exceeds %s", req.Duration, maxLeaseExtension)})
+		return
+	}
+
+	k8sClient, lease, owners, err := a.getOwnedLease(c, name)
+	if err != nil {
+		return
+	}
+	if !lease.Active() {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("lease %s has ended", name)})
+		return
+	}
+
+	extended, err := jumpstarter.ExtendLease(c.Request.Context(), k8sClient, lease.Namespace, name, extra)
+	if err != nil {
+		writeLeaseError(c, name, err)
+		return
+	}
+	a.log.Info("Extended lease", "lease", name, "by", extra, "requester", a.resolveRequester(c))
+	c.JSON(http.StatusOK, leaseResponse(extended, owners))
+}
+
+func (a *APIServer) releaseLease(c *gin.Context, name string) {
+	k8sClient, lease, owners, err := a.getOwnedLease(c, name)
+	if err != nil {
+		return
+	}
+	if lease.Active() {
+		if err := jumpstarter.ReleaseLease(c.Request.Context(), k8sClient, lease.Namespace, name); err != nil {
+			writeLeaseError(c, name, err)
+			return
+		}
+		lease.Released = true
+		a.log.Info("Released lease", "lease", name, "requester", a.resolveRequester(c))
+	}
+	c.JSON(http.StatusOK, leaseResponse(lease, owners))
+}
+
+// getOwnedLease returns a lease held by one of the requester's builds, flash
+// jobs or workspaces. Leases the requester has no resource for are reported
+// as not found.
+func (a *APIServer) getOwnedLease(
+	c *gin.Context, name string,
+) (client.Client, *jumpstarter.Lease, []jumpstarter.Owner, error) {
+	k8sClient, err := getK8sClientOrFail(c)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	ctx := c.Request.Context()
+	namespace := requestNamespace(c)
+	owners, err := jumpstarter.FindOwners(ctx, k8sClient, namespace)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to find leases: %v", err)})
+		return nil, nil, nil, err
+	}
+	leaseOwners := owners[name]
+	if !jumpstarter.OwnedBy(leaseOwners, a.resolveRequester(c)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("lease %s not found", name)})
+		return nil, nil, nil, fmt.Errorf("lease %s not owned by requester", name)
+	}
+
+	leaseNamespace := jumpstarter.LeaseNamespace(ctx, k8sClient, namespace, leaseOwners,
+		leaseFallbackNamespace(ctx, k8sClient))
+	lease, err := jumpstarter.GetLease(ctx, k8sClient, leaseNamespace, name)
+	if err != nil {
+		writeLeaseError(c, name, err)
+		return nil, nil, nil, err
+	}
+	return k8sClient, lease, leaseOwners, nil
+}
+
+// leaseFallbackNamespace returns the Jumpstarter namespace from the
+// OperatorConfig, used for leases whose client config is not stored.
+func leaseFallbackNamespace(ctx context.Context, k8sClient client.Client) string {
+	operatorConfig, err := loadOperatorConfigFn(ctx, k8sClient, resolveNamespace())
+	if err != nil || operatorConfig == nil || operatorConfig.Spec.Jumpstarter == nil {
+		return ""
+	}
+	return operatorConfig.Spec.Jumpstarter.Namespace
+}
+
+func writeLeaseError(c *gin.Context, name string, err error) {
+	switch {
+	case errors.Is(err, jumpstarter.ErrNotInstalled):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case k8serrors.IsNotFound(err):
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("lease %s not found", name)})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to access lease %s: %v", name, err)})
+	}
+}
+
+func leaseResponse(lease *jumpstarter.Lease, owners []jumpstarter.Owner) LeaseResponse {
+	resp := LeaseResponse{
+		Name:      lease.Name,
+		Namespace: lease.Namespace,
+		Client:    lease.Client,
+		Exporter:  lease.Exporter,
+		Active:    lease.Active(),
+		Released:  lease.Released,
+		Owners:    make([]LeaseOwner, 0, len(owners)),
+	}
+	if lease.Duration > 0 {
+		resp.Duration = lease.Duration.String()
+	}
+	if lease.BeginTime != nil {
+		resp.BeginTime = lease.BeginTime.Format(time.RFC3339)
+	}
+	if expires := lease.ExpiresAt(); expires != nil {
+		resp.ExpiresAt = expires.Format(time.RFC3339)
+	}
+	for _, owner := range owners {
+		resp.Owners = append(resp.Owners, LeaseOwner{Kind: owner.Kind, Name: owner.Name})
+	}
+	return resp
+}
@@ -0,0 +1,218 @@
+package buildapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/jumpstarter"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+	"github.com/gin-gonic/gin"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+)
+
+var _ = Describe("Leases", func() {
+	var (
+		server                         *APIServer
+		originalGetClientFromRequestFn func(*gin.Context) (ctrlclient.Client, error)
+		originalNamespace              string
+		hasOriginalNamespace           bool
+	)
+
+	newLease := func(name string, ended bool) *unstructured.Unstructured {
+		lease := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"clientRef": map[string]interface{}{"name": "alice"},
+				"duration":  "1h0m0s",
+			},
+			"status": map[string]interface{}{
+				"beginTime":   "2026-01-01T10:00:00Z",
+				"exporterRef": map[string]interface{}{"name": "rack-01"},
+				"ended":       ended,
+			},
+		}}
+		lease.SetGroupVersionKind(jumpstarter.LeaseGVK)
+		lease.SetName(name)
+		lease.SetNamespace("lab")
+		return lease
+	}
+
+	newFlashJob := func(name, requestedBy, leaseID string) *automotivev1alpha1.FlashJob {
+		return &automotivev1alpha1.FlashJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "test-ns",
+				Annotations: map[string]string{labels.RequestedBy: requestedBy},
+			},
+			Spec: automotivev1alpha1.FlashJobSpec{
+				ImageRef:              "quay.io/org/disk:v1",
+				ClientConfigSecretRef: "jumpstarter-client",
+			},
+			Status: automotivev1alpha1.FlashJobStatus{LeaseID: leaseID},
+		}
+	}
+
+	newFakeClient := func(objs ...ctrlclient.Object) ctrlclient.Client {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(automotivev1alpha1.AddToScheme(scheme)).To(Succeed())
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "jumpstarter-client", Namespace: "test-ns"},
+			Data:       map[string][]byte{"client.yaml": []byte("metadata:\n  namespace: lab\n")},
+		}
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).WithObjects(objs...).Build()
+	}
+
+	useClient := func(k8sClient ctrlclient.Client) {
+		getClientFromRequestFn = func(_ *gin.Context) (ctrlclient.Client, error) {
+			return k8sClient, nil
+		}
+	}
+
+	newContext := func(method, target string, body []byte) (*gin.Context, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(method, target, bytes.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("requester", "alice")
+		return c, w
+	}
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		server = NewAPIServer(":0", logr.Discard())
+		originalGetClientFromRequestFn = getClientFromRequestFn
+		originalNamespace, hasOriginalNamespace = os.LookupEnv("BUILD_API_NAMESPACE")
+		Expect(os.Setenv("BUILD_API_NAMESPACE", "test-ns")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		getClientFromRequestFn = originalGetClientFromRequestFn
+		if hasOriginalNamespace {
+			Expect(os.Setenv("BUILD_API_NAMESPACE", originalNamespace)).To(Succeed())
+		} else {
+			Expect(os.Unsetenv("BUILD_API_NAMESPACE")).To(Succeed())
+		}
+	})
+
+	It("should list the requester's active leases with their owners", func() {
+		useClient(newFakeClient(
+			newFlashJob("flash-1", "alice", "lease-1"),
+			newFlashJob("flash-2", "alice", "lease-2"),
+			newFlashJob("flash-3", "bob", "lease-3"),
+			newLease("lease-1", false),
+			newLease("lease-2", true),
+			newLease("lease-3", false),
+		))
+
+		c, w := newContext(http.MethodGet, "/v1/leases", nil)
+		server.listLeases(c)
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var leases []LeaseResponse
+		Expect(json.Unmarshal(w.Body.Bytes(), &leases)).To(Succeed())
+		Expect(leases).To(HaveLen(1))
+		Expect(leases[0].Name).To(Equal("lease-1"))
+		Expect(leases[0].Namespace).To(Equal("lab"))
+		Expect(leases[0].Exporter).To(Equal("rack-01"))
+		Expect(leases[0].ExpiresAt).To(Equal("2026-01-01T11:00:00Z"))
+		Expect(leases[0].Owners).To(ConsistOf(LeaseOwner{Kind: jumpstarter.OwnerFlashJob, Name: "flash-1"}))
+
+		c, w = newContext(http.MethodGet, "/v1/leases?all=true", nil)
+		server.listLeases(c)
+		Expect(json.Unmarshal(w.Body.Bytes(), &leases)).To(Succeed())
+		Expect(leases).To(HaveLen(2))
+	})
+
+	It("should return 404 for a lease held by another user", func() {
+		useClient(newFakeClient(newFlashJob("flash-3", "bob", "lease-3"), newLease("lease-3", false)))
+
+		c, w := newContext(http.MethodGet, "/v1/leases/lease-3", nil)
+		server.getLease(c, "lease-3")
+
+		Expect(w.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should extend a lease", func() {
+		useClient(newFakeClient(newFlashJob("flash-1", "alice", "lease-1"), newLease("lease-1", false)))
+
+		c, w := newContext(http.MethodPost, "/v1/leases/lease-1/extend", []byte(`{"duration":"30m"}`))
+		server.extendLease(c, "lease-1")
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var lease LeaseResponse
+		Expect(json.Unmarshal(w.Body.Bytes(), &lease)).To(Succeed())
+		Expect(lease.Duration).To(Equal("1h30m0s"))
+		Expect(lease.ExpiresAt).To(Equal("2026-01-01T11:30:00Z"))
+	})
+
+	It("should reject invalid extensions", func() {
+		useClient(newFakeClient(
+			newFlashJob("flash-1", "alice", "lease-1"),
+			newFlashJob("flash-2", "alice", "lease-2"),
+			newLease("lease-1", false),
+			newLease("lease-2", true),
+		))
+
+		c, w := newContext(http.MethodPost, "/v1/leases/lease-1/extend", []byte(`{"duration":"-1h"}`))
+		server.extendLease(c, "lease-1")
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+
+		c, w = newContext(http.MethodPost, "/v1/leases/lease-1/extend", []byte(`{"duration":"48h"}`))
+		server.extendLease(c, "lease-1")
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+
+		c, w = newContext(http.MethodPost, "/v1/leases/lease-2/extend", []byte(`{"duration":"1h"}`))
+		server.extendLease(c, "lease-2")
+		Expect(w.Code).To(Equal(http.StatusConflict))
+	})
+
+	It("should release a lease", func() {
+		k8sClient := newFakeClient(newFlashJob("flash-1", "alice", "lease-1"), newLease("lease-1", false))
+		useClient(k8sClient)
+
+		c, w := newContext(http.MethodPost, "/v1/leases/lease-1/release", nil)
+		server.releaseLease(c, "lease-1")
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		lease, err := jumpstarter.GetLease(context.Background(), k8sClient, "lab", "lease-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lease.Released).To(BeTrue())
+	})
+
+	It("should return 503 when Jumpstarter is not installed", func() {
+		scheme := runtime.NewScheme()
+		Expect(automotivev1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		useClient(fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(newFlashJob("flash-1", "alice", "lease-1")).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, c ctrlclient.WithWatch, key ctrlclient.ObjectKey, obj ctrlclient.Object, opts ...ctrlclient.GetOption) error {
+					if _, ok := obj.(*unstructured.Unstructured); ok {
+						return &apimeta.NoKindMatchError{GroupKind: jumpstarter.LeaseGVK.GroupKind()}
+					}
+					return c.Get(ctx, key, obj, opts...)
+				},
+			}).Build())
+
+		c, w := newContext(http.MethodGet, "/v1/leases/lease-1", nil)
+		server.getLease(c, "lease-1")
+
+		Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+	})
+})
@@ -19,6 +19,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
 )
 
 var isTerminalPhase = automotivev1alpha1.IsTerminalBuildPhase
@@ -57,6 +58,16 @@ func podTaskName(pod corev1.Pod) string {
 	return pod.Name
 }
 
+// archPodTaskName qualifies the task name with the pod's architecture so the
+// interleaved logs of a multi-arch build can be told apart.
+func archPodTaskName(pod corev1.Pod) string {
+	taskName := podTaskName(pod)
+	if arch := pod.Labels[labels.Architecture]; arch != "" {
+		return taskName + "[" + arch + "]"
+	}
+	return taskName
+}
+
 func logStreamHeader(taskName, containerName string) string {
 	return "\n===== Logs from " + taskName + "/" + strings.TrimPrefix(containerName, "step-") + " =====\n\n"
 }
@@ -193,11 +204,10 @@ func streamContainerLogs(
 
 func processPodLogs(
 	ctx context.Context, c *gin.Context, cs *kubernetes.Clientset,
-	pod corev1.Pod, namespace string, sinceTime *metav1.Time,
+	pod corev1.Pod, taskName, namespace string, sinceTime *metav1.Time,
 	streamedContainers map[string]bool, hadStream *bool,
 ) {
 	stepNames := getStepContainerNames(pod)
-	taskName := podTaskName(pod)
 
 	for _, cName := range stepNames {
 		if streamedContainers[cName] {
@@ -218,7 +228,7 @@ func processPodLogs(
 }
 
 func (a *APIServer) streamLogs(c *gin.Context, name string) {
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 
 	k8sClient, err := getK8sClientOrFail(c)
 	if err != nil {
@@ -235,10 +245,23 @@ func (a *APIServer) streamLogs(c *gin.Context, name string) {
 		return
 	}
 
-	tr := strings.TrimSpace(ib.Status.PipelineRunName)
-	if tr == "" {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "logs not available yet"})
-		return
+	// Multi-arch builds run one PipelineRun per architecture plus the image
+	// index TaskRun, all labelled with the owning ImageBuild.
+	multiArch := ib.Spec.IsMultiArch()
+	var podSelector string
+	if multiArch {
+		if len(ib.Status.Architectures) == 0 {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "logs not available yet"})
+			return
+		}
+		podSelector = labels.ImageBuildName + "=" + ib.Name
+	} else {
+		tr := strings.TrimSpace(ib.Status.PipelineRunName)
+		if tr == "" {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "logs not available yet"})
+			return
+		}
+		podSelector = "tekton.dev/pipelineRun=" + tr + ",tekton.dev/memberOf=tasks"
 	}
 
 	cs, err := getClientsetOrFail(c)
@@ -248,7 +271,6 @@ func (a *APIServer) streamLogs(c *gin.Context, name string) {
 
 	setupLogStreamHeaders(c)
 
-	pipelineRunSelector := "tekton.dev/pipelineRun=" + tr + ",tekton.dev/memberOf=tasks"
 	var hadStream bool
 	var lastKeepalive time.Time
 	streamedContainers := make(map[string]map[string]bool)
@@ -261,7 +283,7 @@ func (a *APIServer) streamLogs(c *gin.Context, name string) {
 		default:
 		}
 
-		pods, err := cs.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: pipelineRunSelector})
+		pods, err := cs.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: podSelector})
 		if err != nil {
 			if _, writeErr := fmt.Fprintf(c.Writer, "\n[Error listing pods: %v]\n", err); writeErr != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to write error message: %v\n", writeErr)
@@ -295,7 +317,11 @@ func (a *APIServer) streamLogs(c *gin.Context, name string) {
 				streamedContainers[pod.Name] = make(map[string]bool)
 			}
 
-			processPodLogs(ctx, c, cs, pod, namespace, sinceTime, streamedContainers[pod.Name], &hadStream)
+			taskName := podTaskName(pod)
+			if multiArch {
+				taskName = archPodTaskName(pod)
+			}
+			processPodLogs(ctx, c, cs, pod, taskName, namespace, sinceTime, streamedContainers[pod.Name], &hadStream)
 
 			stepNames := getStepContainerNames(pod)
 			if len(streamedContainers[pod.Name]) == len(stepNames) &&
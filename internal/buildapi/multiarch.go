@@ -0,0 +1,108 @@
+package buildapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// architectureStrings converts the request architectures to the CRD representation.
+func architectureStrings(archs []Architecture) []string {
+	if len(archs) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(archs))
+	for _, arch := range archs {
+		out = append(out, string(arch))
+	}
+	return out
+}
+
+// multiArchList returns the requested architectures for multi-arch builds, nil otherwise.
+func multiArchList(build *automotivev1alpha1.ImageBuild) []string {
+	if !build.Spec.IsMultiArch() {
+		return nil
+	}
+	return build.Spec.GetArchitectures()
+}
+
+// requestArchitectures converts a multi-arch spec back into request form for build templates.
+func requestArchitectures(build *automotivev1alpha1.ImageBuild) []Architecture {
+	var out []Architecture
+	for _, arch := range multiArchList(build) {
+		out = append(out, Architecture(arch))
+	}
+	return out
+}
+
+// architectureStatuses reports the per-architecture state of a multi-arch build.
+// Image references are only returned once that architecture has completed.
+func architectureStatuses(build *automotivev1alpha1.ImageBuild, externalRoute string) []ArchitectureStatus {
+	if !build.Spec.IsMultiArch() {
+		return nil
+	}
+	out := make([]ArchitectureStatus, 0, len(build.Status.Architectures))
+	for _, as := range build.Status.Architectures {
+		item := ArchitectureStatus{
+			Architecture: as.Architecture,
+			Phase:        as.Phase,
+			Message:      as.Message,
+		}
+		if as.StartTime != nil {
+			item.StartTime = as.StartTime.Format(time.RFC3339)
+		}
+		if as.CompletionTime != nil {
+			item.CompletionTime = as.CompletionTime.Format(time.RFC3339)
+		}
+		if as.Phase == phaseCompleted {
+			item.ContainerImage = as.ContainerImage
+			item.DiskImage = as.DiskImage
+			if build.Spec.GetUseServiceAccountAuth() && externalRoute != "" {
+				if item.ContainerImage != "" {
+					item.ContainerImage = translateToExternalURL(item.ContainerImage, externalRoute)
+				}
+				if item.DiskImage != "" {
+					item.DiskImage = translateToExternalURL(item.DiskImage, externalRoute)
+				}
+			}
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+// cancelArchitecturePipelineRuns cancels every per-architecture PipelineRun that is still running.
+func cancelArchitecturePipelineRuns(
+	ctx context.Context,
+	k8sClient client.Client,
+	build *automotivev1alpha1.ImageBuild,
+) error {
+	for _, as := range build.Status.Architectures {
+		if as.PipelineRunName == "" {
+			continue
+		}
+		pipelineRun := &tektonv1.PipelineRun{}
+		prKey := types.NamespacedName{Name: as.PipelineRunName, Namespace: build.Namespace}
+		if err := k8sClient.Get(ctx, prKey, pipelineRun); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("error fetching PipelineRun for %s: %w", as.Architecture, err)
+		}
+		if pipelineRun.Status.CompletionTime != nil {
+			continue
+		}
+		pipelineRun.Spec.Status = tektonv1.PipelineRunSpecStatusCancelled
+		if err := k8sClient.Update(ctx, pipelineRun); err != nil {
+			return fmt.Errorf("failed to cancel PipelineRun for %s: %w", as.Architecture, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package buildapi
+
+import (
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+func newMultiArchBuild(phase string, archs ...automotivev1alpha1.ArchitectureBuildStatus) *automotivev1alpha1.ImageBuild {
+	return &automotivev1alpha1.ImageBuild{
+		Spec: automotivev1alpha1.ImageBuildSpec{
+			Architectures: []string{"amd64", "arm64"},
+		},
+		Status: automotivev1alpha1.ImageBuildStatus{
+			Phase:         phase,
+			Architectures: archs,
+		},
+	}
+}
+
+var _ = Describe("architectureStatuses", func() {
+	It("returns nil for single-arch builds", func() {
+		build := &automotivev1alpha1.ImageBuild{
+			Spec: automotivev1alpha1.ImageBuildSpec{Architecture: "amd64"},
+		}
+		Expect(architectureStatuses(build, "")).To(BeNil())
+	})
+
+	It("only reports images for completed architectures", func() {
+		build := newMultiArchBuild(phaseBuilding,
+			automotivev1alpha1.ArchitectureBuildStatus{
+				Architecture:   "amd64",
+				Phase:          phaseCompleted,
+				ContainerImage: "quay.io/org/image:v1-amd64",
+			},
+			automotivev1alpha1.ArchitectureBuildStatus{
+				Architecture:   "arm64",
+				Phase:          phaseBuilding,
+				ContainerImage: "quay.io/org/image:v1-arm64",
+			},
+		)
+		statuses := architectureStatuses(build, "")
+		Expect(statuses).To(HaveLen(2))
+		Expect(statuses[0].ContainerImage).To(Equal("quay.io/org/image:v1-amd64"))
+		Expect(statuses[1].ContainerImage).To(BeEmpty())
+		Expect(statuses[1].Phase).To(Equal(phaseBuilding))
+	})
+})
+
+var _ = Describe("combineArchitectureSteps", func() {
+	It("sums per-architecture steps and names the first active architecture", func() {
+		build := newMultiArchBuild(phaseBuilding)
+		step := combineArchitectureSteps(build, []ArchitectureProgress{
+			{Architecture: "amd64", Phase: phaseCompleted, Step: &BuildStep{Stage: "Complete", Done: 4, Total: 4}},
+			{Architecture: "arm64", Phase: phaseBuilding, Step: &BuildStep{Stage: "Building image", Done: 2, Total: 4}},
+		})
+		Expect(*step).To(Equal(BuildStep{Stage: "arm64: Building image", Done: 6, Total: 8}))
+	})
+
+	It("reports index publishing once every architecture is done", func() {
+		build := newMultiArchBuild(phaseBuilding)
+		step := combineArchitectureSteps(build, []ArchitectureProgress{
+			{Architecture: "amd64", Phase: phaseCompleted, Step: &BuildStep{Stage: "Complete", Done: 4, Total: 4}},
+			{Architecture: "arm64", Phase: phaseCompleted, Step: &BuildStep{Stage: "Complete", Done: 4, Total: 4}},
+		})
+		Expect(step.Stage).To(Equal("Publishing image index"))
+		Expect(step.Done).To(Equal(8))
+	})
+
+	It("reports completion for completed builds", func() {
+		build := newMultiArchBuild(phaseCompleted)
+		step := combineArchitectureSteps(build, []ArchitectureProgress{
+			{Architecture: "amd64", Phase: phaseCompleted, Step: &BuildStep{Stage: "Complete", Done: 4, Total: 4}},
+		})
+		Expect(*step).To(Equal(BuildStep{Stage: "Complete", Done: 4, Total: 4}))
+	})
+})
@@ -63,6 +63,16 @@ func pruneProgressCache(cache map[string]progressCacheEntry, now time.Time) {
 type BuildProgress struct {
 	Phase string     `json:"phase"`
 	Step  *BuildStep `json:"step,omitempty"`
+
+	// Architectures reports per-architecture progress for multi-arch builds.
+	Architectures []ArchitectureProgress `json:"architectures,omitempty"`
+}
+
+// ArchitectureProgress is the progress of a single architecture in a multi-arch build.
+type ArchitectureProgress struct {
+	Architecture string     `json:"architecture"`
+	Phase        string     `json:"phase"`
+	Step         *BuildStep `json:"step,omitempty"`
 }
 
 // BuildStep represents a progress checkpoint emitted by the build script.
@@ -116,24 +126,33 @@ func stageForPipelineTask(taskName string) string {
 func readTaskProgressFromPods(ctx context.Context, cs *kubernetes.Clientset, pipelineRunName, namespace string) []taskProgress {
 	selector := "tekton.dev/pipelineRun=" + pipelineRunName + ",tekton.dev/memberOf=tasks"
 	pods, err := cs.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
-	if err != nil || len(pods.Items) == 0 {
+	if err != nil {
+		return nil
+	}
+	return taskProgressFromPods(pods.Items)
+}
+
+// taskProgressFromPods returns the progress markers of the pipeline task pods
+// of a PipelineRun, as described for readTaskProgressFromPods.
+func taskProgressFromPods(pods []corev1.Pod) []taskProgress {
+	if len(pods) == 0 {
 		return nil
 	}
 
 	// Sort pods by start time so earlier tasks come first.
-	sort.Slice(pods.Items, func(i, j int) bool {
-		if pods.Items[i].Status.StartTime == nil {
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].Status.StartTime == nil {
 			return false
 		}
-		if pods.Items[j].Status.StartTime == nil {
+		if pods[j].Status.StartTime == nil {
 			return true
 		}
-		return pods.Items[i].Status.StartTime.Before(pods.Items[j].Status.StartTime)
+		return pods[i].Status.StartTime.Before(pods[j].Status.StartTime)
 	})
 
 	var results []taskProgress
 
-	for _, pod := range pods.Items {
+	for _, pod := range pods {
 		taskName := pod.Labels["tekton.dev/pipelineTask"]
 
 		if ann, ok := pod.Annotations[labels.Progress]; ok {
@@ -246,6 +265,13 @@ func buildProgressStep(
 	case "", phasePending, phaseUploading:
 		return &BuildStep{Stage: "Waiting to start", Done: 0, Total: pipelineTotal}
 
+	case phaseQueued:
+		stage := "Queued"
+		if build.Status.QueuePosition > 0 {
+			stage = "Queued (position " + strconv.Itoa(int(build.Status.QueuePosition)) + ")"
+		}
+		return &BuildStep{Stage: stage, Done: 0, Total: pipelineTotal}
+
 	case phaseBuilding, phaseRunning:
 		if len(tasks) > 0 {
 			return &BuildStep{Stage: activeStage, Done: clampDone(combinedDone, pipelineTotal), Total: pipelineTotal}
@@ -278,7 +304,7 @@ func buildProgressStep(
 
 func (a *APIServer) handleGetProgress(c *gin.Context) {
 	name := c.Param("name")
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 
 	k8sClient, err := getClientFromRequest(c)
 	if err != nil {
@@ -303,48 +329,23 @@ func (a *APIServer) handleGetProgress(c *gin.Context) {
 	// reading pod metadata is cheap (single list call, no streaming).
 	var tasks []taskProgress
 	hasClusterRegistryRoute := false
-	tr := strings.TrimSpace(build.Status.PipelineRunName)
-	phase := build.Status.Phase
-	if tr != "" && (phase == phaseBuilding || phase == phaseRunning || phase == "Pushing" || phase == "Flashing") {
-		cacheKey := namespace + "/" + tr
-		a.progressCacheMu.RLock()
-		cached, ok := a.progressCache[cacheKey]
-		a.progressCacheMu.RUnlock()
-		if ok && time.Since(cached.time) < progressCacheTTL {
-			tasks = cached.tasks
-		} else {
-			restCfg, err := getRESTConfigFromRequest(c)
-			if err != nil {
-				a.log.Error(err, "failed to get REST config for progress pod read")
-			} else {
-				cs, err := kubernetes.NewForConfig(restCfg)
-				if err != nil {
-					a.log.Error(err, "failed to create kubernetes client for progress pod read")
-				} else {
-					tasks = readTaskProgressFromPods(ctx, cs, tr, namespace)
-					a.progressCacheMu.Lock()
-					if a.progressCache == nil {
-						a.progressCache = make(map[string]progressCacheEntry)
-					}
-					now := time.Now()
-					a.progressCache[cacheKey] = progressCacheEntry{tasks: tasks, time: now}
-					pruneProgressCache(a.progressCache, now)
-					a.progressCacheMu.Unlock()
-				}
-			}
+	active := isProgressActive(build.Status.Phase)
+	archTasks := map[string][]taskProgress{}
+	if build.Spec.IsMultiArch() {
+		for _, as := range build.Status.Architectures {
+			archActive := active && as.Phase == phaseBuilding
+			archTasks[as.Architecture] = a.readCachedTaskProgress(c, namespace, as.PipelineRunName, archActive)
+			tasks = append(tasks, archTasks[as.Architecture]...)
 		}
-	} else if tr != "" {
-		cacheKey := namespace + "/" + tr
-		a.progressCacheMu.Lock()
-		delete(a.progressCache, cacheKey)
-		a.progressCacheMu.Unlock()
+	} else {
+		tasks = a.readCachedTaskProgress(c, namespace, build.Status.PipelineRunName, active)
 	}
 
 	// Estimate builder-prepare steps only when no task markers exist yet.
 	if len(tasks) == 0 {
 		mode := Mode(build.Spec.GetMode())
 		if build.Spec.GetBuilderImage() == "" && (mode == ModeBootc || mode == ModeDisk) {
-			if route, err := getExternalRegistryRoute(ctx, k8sClient, namespace); err == nil && strings.TrimSpace(route) != "" {
+			if route, err := getExternalRegistryRoute(ctx, k8sClient, resolveNamespace()); err == nil && strings.TrimSpace(route) != "" {
 				hasClusterRegistryRoute = true
 			}
 		}
@@ -354,6 +355,109 @@ func (a *APIServer) handleGetProgress(c *gin.Context) {
 		Phase: build.Status.Phase,
 		Step:  buildProgressStep(build, tasks, hasClusterRegistryRoute),
 	}
+	if build.Spec.IsMultiArch() {
+		progress.Architectures = architectureProgress(build, archTasks, hasClusterRegistryRoute)
+		progress.Step = combineArchitectureSteps(build, progress.Architectures)
+	}
 
 	c.JSON(http.StatusOK, progress)
 }
+
+// isProgressActive reports whether pipeline task pods report progress in phase.
+func isProgressActive(phase string) bool {
+	return phase == phaseBuilding || phase == phaseRunning || phase == "Pushing" || phase == "Flashing"
+}
+
+// readCachedTaskProgress returns the task markers for a PipelineRun, served from
+// the progress cache while the run is active and evicted once it is not.
+func (a *APIServer) readCachedTaskProgress(c *gin.Context, namespace, pipelineRunName string, active bool) []taskProgress {
+	tr := strings.TrimSpace(pipelineRunName)
+	if tr == "" {
+		return nil
+	}
+	cacheKey := namespace + "/" + tr
+	if !active {
+		a.progressCacheMu.Lock()
+		delete(a.progressCache, cacheKey)
+		a.progressCacheMu.Unlock()
+		return nil
+	}
+
+	a.progressCacheMu.RLock()
+	cached, ok := a.progressCache[cacheKey]
+	a.progressCacheMu.RUnlock()
+	if ok && time.Since(cached.time) < progressCacheTTL {
+		return cached.tasks
+	}
+
+	restCfg, err := getRESTConfigFromRequest(c)
+	if err != nil {
+		a.log.Error(err, "failed to get REST config for progress pod read")
+		return nil
+	}
+	cs, err := kubernetes.NewForConfig(restCfg)
+	if err != nil {
+		a.log.Error(err, "failed to create kubernetes client for progress pod read")
+		return nil
+	}
+	tasks := readTaskProgressFromPods(c.Request.Context(), cs, tr, namespace)
+	a.progressCacheMu.Lock()
+	if a.progressCache == nil {
+		a.progressCache = make(map[string]progressCacheEntry)
+	}
+	now := time.Now()
+	a.progressCache[cacheKey] = progressCacheEntry{tasks: tasks, time: now}
+	pruneProgressCache(a.progressCache, now)
+	a.progressCacheMu.Unlock()
+	return tasks
+}
+
+// architectureProgress computes a progress step for each architecture of a multi-arch build.
+// Architecture phases share the build phase vocabulary, so each one is rendered
+// through buildProgressStep as if it were a single-arch build.
+func architectureProgress(
+	build *automotivev1alpha1.ImageBuild,
+	archTasks map[string][]taskProgress,
+	hasClusterRegistryRoute bool,
+) []ArchitectureProgress {
+	out := make([]ArchitectureProgress, 0, len(build.Status.Architectures))
+	for _, as := range build.Status.Architectures {
+		view := build.DeepCopy()
+		view.Status.Phase = as.Phase
+		out = append(out, ArchitectureProgress{
+			Architecture: as.Architecture,
+			Phase:        as.Phase,
+			Step:         buildProgressStep(view, archTasks[as.Architecture], hasClusterRegistryRoute),
+		})
+	}
+	return out
+}
+
+// combineArchitectureSteps sums the per-architecture steps into a build-wide step.
+// The stage reflects the first architecture that is still in progress.
+func combineArchitectureSteps(build *automotivev1alpha1.ImageBuild, archs []ArchitectureProgress) *BuildStep {
+	if len(archs) == 0 {
+		return &BuildStep{Stage: "Waiting to start"}
+	}
+	var done, total int
+	var stage string
+	for _, ap := range archs {
+		if ap.Step == nil {
+			continue
+		}
+		done += ap.Step.Done
+		total += ap.Step.Total
+		if stage == "" && ap.Phase != phaseCompleted {
+			stage = ap.Architecture + ": " + ap.Step.Stage
+		}
+	}
+	switch {
+	case build.Status.Phase == phaseCompleted:
+		return &BuildStep{Stage: "Complete", Done: total, Total: total}
+	case stage == "" && build.Status.Phase == phaseBuilding:
+		stage = "Publishing image index"
+	case stage == "":
+		stage = build.Status.Phase
+	}
+	return &BuildStep{Stage: stage, Done: clampDone(done, total), Total: total}
+}
@@ -0,0 +1,454 @@
+package buildapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+)
+
+// QuotaResponse shows the usage of the requester and its team against their quotas.
+type QuotaResponse struct {
+	User        string     `json:"user"`
+	Role        string     `json:"role"`
+	Team        string     `json:"team"`
+	MaxBuildTTL string     `json:"maxBuildTTL,omitempty"`
+	UserUsage   QuotaUsage `json:"userUsage"`
+	TeamUsage   QuotaUsage `json:"teamUsage"`
+}
+
+// QuotaUsage is the usage of a quota. Empty or zero limits are unlimited.
+type QuotaUsage struct {
+	ConcurrentBuilds      int32  `json:"concurrentBuilds"`
+	MaxConcurrentBuilds   int32  `json:"maxConcurrentBuilds,omitempty"`
+	BuildStorage          string `json:"buildStorage"`
+	MaxBuildStorage       string `json:"maxBuildStorage,omitempty"`
+	Workspaces            int32  `json:"workspaces"`
+	MaxWorkspaces         int32  `json:"maxWorkspaces,omitempty"`
+	WorkspaceStorage      string `json:"workspaceStorage"`
+	MaxWorkspaceStorage   string `json:"maxWorkspaceStorage,omitempty"`
+	buildStorageBytes     resource.Quantity
+	workspaceStorageBytes resource.Quantity
+}
+
+// quotaError is a quota that a request would exceed.
+type quotaError struct {
+	status int
+	reason string
+	msg    string
+}
+
+func (e *quotaError) Error() string {
+	return e.msg
+}
+
+func (a *APIServer) registerQuotaRoutes(v1 *gin.RouterGroup) {
+	quotaGroup := v1.Group("/quota")
+	quotaGroup.Use(a.authMiddleware(), a.authorizeMiddleware())
+	{
+		quotaGroup.GET("", a.wrapHandler("get quota", a.getQuota))
+	}
+}
+
+func (a *APIServer) getQuota(c *gin.Context) {
+	k8sClient, err := getK8sClientOrFail(c)
+	if err != nil {
+		return
+	}
+
+	ctx := c.Request.Context()
+	operatorConfig, cfgErr := loadOperatorConfigFn(ctx, k8sClient, resolveNamespace())
+	if cfgErr != nil && !k8serrors.IsNotFound(cfgErr) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load operator config"})
+		return
+	}
+
+	namespace := requestNamespace(c)
+	requester := a.resolveRequester(c)
+	user, team, err := measureQuotaUsage(ctx, k8sClient, operatorConfig, namespace, requester)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	quotas := quotaConfig(operatorConfig)
+	role := requesterRole(c)
+	user.setLimits(quotas.User)
+	team.setLimits(quotas.Team)
+	c.JSON(http.StatusOK, QuotaResponse{
+		User:        requester,
+		Role:        string(role),
+		Team:        namespace,
+		MaxBuildTTL: quotas.GetMaxBuildTTL(role),
+		UserUsage:   *user,
+		TeamUsage:   *team,
+	})
+}
+
+// admitBuild checks that a build with the given number of architectures fits
+// the quotas of the requester and its team, and returns the TTL the build
+// gets under the maximum TTL of the requester's role.
+func (a *APIServer) admitBuild(
+	ctx context.Context,
+	c *gin.Context,
+	k8sClient client.Client,
+	namespace string,
+	architectures int,
+	ttl string,
+) (string, error) {
+	operatorConfig, cfgErr := loadOperatorConfigFn(ctx, k8sClient, resolveNamespace())
+	if cfgErr != nil && !k8serrors.IsNotFound(cfgErr) {
+		return "", fmt.Errorf("failed to load OperatorConfig: %w", cfgErr)
+	}
+	quotas := quotaConfig(operatorConfig)
+
+	ttl, err := clampTTLForRole(quotas, requesterRole(c), ttl, osBuildsConfig(operatorConfig).GetDefaultBuildTTL())
+	if err != nil {
+		return "", err
+	}
+	if quotas.User == nil && quotas.Team == nil {
+		return ttl, nil
+	}
+
+	requester := a.resolveRequester(c)
+	user, team, err := measureQuotaUsage(ctx, k8sClient, operatorConfig, namespace, requester)
+	if err != nil {
+		return "", err
+	}
+	storage := buildStorage(operatorConfig, architectures)
+	if err := checkBuildQuota(quotas.User, user, storage, "user "+requester); err != nil {
+		return "", err
+	}
+	if err := checkBuildQuota(quotas.Team, team, storage, "team "+namespace); err != nil {
+		return "", err
+	}
+	return ttl, nil
+}
+
+// admitWorkspace checks that a workspace of pvcSize fits the quotas of the
+// requester and its team.
+func (a *APIServer) admitWorkspace(
+	ctx context.Context,
+	c *gin.Context,
+	k8sClient client.Client,
+	operatorConfig *automotivev1alpha1.OperatorConfig,
+	namespace, pvcSize string,
+) error {
+	quotas := quotaConfig(operatorConfig)
+	if quotas.User == nil && quotas.Team == nil {
+		return nil
+	}
+
+	size, err := resource.ParseQuantity(pvcSize)
+	if err != nil {
+		return fmt.Errorf("invalid workspace PVC size %q: %w", pvcSize, err)
+	}
+	requester := a.resolveRequester(c)
+	user, team, err := measureQuotaUsage(ctx, k8sClient, operatorConfig, namespace, requester)
+	if err != nil {
+		return err
+	}
+	if err := checkWorkspaceQuota(quotas.User, user, size, "user "+requester); err != nil {
+		return err
+	}
+	return checkWorkspaceQuota(quotas.Team, team, size, "team "+namespace)
+}
+
+// clampTTLForRole applies the maximum build TTL of role. A build without a
+// TTL gets the default TTL, shortened to the maximum when it is longer.
+func clampTTLForRole(quotas *automotivev1alpha1.QuotaConfig, role automotivev1alpha1.BuildAPIRole, ttl, defaultTTL string) (string, error) {
+	maxStr := quotas.GetMaxBuildTTL(role)
+	if maxStr == "" || maxStr == "0" {
+		return ttl, nil
+	}
+	maxDur, err := time.ParseDuration(maxStr)
+	if err != nil || maxDur <= 0 {
+		return "", fmt.Errorf("invalid maxBuildTTL %q for role %s in OperatorConfig", maxStr, role)
+	}
+
+	effective := ttl
+	if effective == "" {
+		effective = defaultTTL
+	}
+	if effective == "0" {
+		if ttl == "" {
+			return maxStr, nil
+		}
+		return "", &quotaError{
+			status: http.StatusForbidden,
+			reason: "max_build_ttl",
+			msg:    fmt.Sprintf("builds that never expire are not allowed for the %s role (maximum TTL %s)", role, maxStr),
+		}
+	}
+	dur, err := time.ParseDuration(effective)
+	if err != nil {
+		return "", fmt.Errorf("invalid TTL %q: %w", effective, err)
+	}
+	if dur <= maxDur {
+		return ttl, nil
+	}
+	if ttl == "" {
+		return maxStr, nil
+	}
+	return "", &quotaError{
+		status: http.StatusForbidden,
+		reason: "max_build_ttl",
+		msg:    fmt.Sprintf("requested TTL %q exceeds the maximum of %s for the %s role", ttl, maxStr, role),
+	}
+}
+
+func checkBuildQuota(limits *automotivev1alpha1.QuotaLimits, usage *QuotaUsage, storage resource.Quantity, scope string) error {
+	if limits == nil {
+		return nil
+	}
+	if limits.MaxConcurrentBuilds > 0 && usage.ConcurrentBuilds >= limits.MaxConcurrentBuilds {
+		// Running builds finish on their own, so the request can be retried
+		return &quotaError{
+			status: http.StatusTooManyRequests,
+			reason: "max_concurrent_builds",
+			msg: fmt.Sprintf("%s has %d of %d concurrent builds, wait for a build to finish",
+				scope, usage.ConcurrentBuilds, limits.MaxConcurrentBuilds),
+		}
+	}
+	if limits.MaxBuildStorage != "" {
+		maxStorage, err := resource.ParseQuantity(limits.MaxBuildStorage)
+		if err != nil {
+			return fmt.Errorf("invalid maxBuildStorage %q in OperatorConfig: %w", limits.MaxBuildStorage, err)
+		}
+		total := usage.buildStorageBytes.DeepCopy()
+		total.Add(storage)
+		if total.Cmp(maxStorage) > 0 {
+			return &quotaError{
+				status: http.StatusForbidden,
+				reason: "max_build_storage",
+				msg: fmt.Sprintf("%s uses %s of %s build storage and the build needs %s, delete builds to free storage",
+					scope, usage.BuildStorage, limits.MaxBuildStorage, storage.String()),
+			}
+		}
+	}
+	return nil
+}
+
+func checkWorkspaceQuota(limits *automotivev1alpha1.QuotaLimits, usage *QuotaUsage, size resource.Quantity, scope string) error {
+	if limits == nil {
+		return nil
+	}
+	if limits.MaxWorkspaces > 0 && usage.Workspaces >= limits.MaxWorkspaces {
+		return &quotaError{
+			status: http.StatusForbidden,
+			reason: "max_workspaces",
+			msg:    fmt.Sprintf("%s has %d of %d workspaces, delete a workspace first", scope, usage.Workspaces, limits.MaxWorkspaces),
+		}
+	}
+	if limits.MaxWorkspaceStorage != "" {
+		maxStorage, err := resource.ParseQuantity(limits.MaxWorkspaceStorage)
+		if err != nil {
+			return fmt.Errorf("invalid maxWorkspaceStorage %q in OperatorConfig: %w", limits.MaxWorkspaceStorage, err)
+		}
+		total := usage.workspaceStorageBytes.DeepCopy()
+		total.Add(size)
+		if total.Cmp(maxStorage) > 0 {
+			return &quotaError{
+				status: http.StatusForbidden,
+				reason: "max_workspace_storage",
+				msg: fmt.Sprintf("%s uses %s of %s workspace storage and the workspace needs %s, delete a workspace first",
+					scope, usage.WorkspaceStorage, limits.MaxWorkspaceStorage, size.String()),
+			}
+		}
+	}
+	return nil
+}
+
+// measureQuotaUsage returns the usage of requester and of everyone in namespace.
+func measureQuotaUsage(
+	ctx context.Context,
+	k8sClient client.Client,
+	operatorConfig *automotivev1alpha1.OperatorConfig,
+	namespace, requester string,
+) (*QuotaUsage, *QuotaUsage, error) {
+	user, team := &QuotaUsage{}, &QuotaUsage{}
+
+	builds := &automotivev1alpha1.ImageBuildList{}
+	if err := k8sClient.List(ctx, builds, client.InNamespace(namespace)); err != nil {
+		return nil, nil, fmt.Errorf("failed to list builds: %w", err)
+	}
+	claimed, err := measureBuildStorage(ctx, k8sClient, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range builds.Items {
+		build := &builds.Items[i]
+		if build.DeletionTimestamp != nil {
+			continue
+		}
+		storage, ok := claimed[build.Name]
+		running := !automotivev1alpha1.IsTerminalBuildPhase(build.Status.Phase)
+		if !ok && running {
+			// Reserve the storage the build is about to claim.
+			storage = buildStorage(operatorConfig, len(build.Spec.Architectures))
+		}
+		team.addBuild(storage, running)
+		if build.Annotations[labels.RequestedBy] == requester {
+			user.addBuild(storage, running)
+		}
+	}
+
+	workspaces := &automotivev1alpha1.WorkspaceList{}
+	if err := k8sClient.List(ctx, workspaces, client.InNamespace(namespace)); err != nil {
+		return nil, nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	var wsConfig *automotivev1alpha1.WorkspacesConfig
+	if operatorConfig != nil {
+		wsConfig = operatorConfig.Spec.Workspaces
+	}
+	for i := range workspaces.Items {
+		ws := &workspaces.Items[i]
+		if ws.DeletionTimestamp != nil {
+			continue
+		}
+		pvcSize := ws.Spec.PVCSize
+		if pvcSize == "" {
+			pvcSize = wsConfig.GetPVCSize()
+		}
+		size, err := resource.ParseQuantity(pvcSize)
+		if err != nil {
+			continue
+		}
+		team.addWorkspace(size)
+		if ws.Spec.Owner == requester {
+			user.addWorkspace(size)
+		}
+	}
+
+	user.formatStorage()
+	team.formatStorage()
+	return user, team, nil
+}
+
+// measureBuildStorage returns the storage requested by the existing PVCs of
+// each build in namespace, keyed by build name: its workspace PVC and the PVCs
+// claimed for its PipelineRuns. Builds whose PVCs were deleted, e.g. on expiry,
+// use no storage.
+func measureBuildStorage(
+	ctx context.Context,
+	k8sClient client.Client,
+	namespace string,
+) (map[string]resource.Quantity, error) {
+	pipelineRuns := &tektonv1.PipelineRunList{}
+	if err := k8sClient.List(ctx, pipelineRuns,
+		client.InNamespace(namespace),
+		client.HasLabels{automotivev1alpha1.LabelImageBuildName},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list pipeline runs: %w", err)
+	}
+	runBuilds := make(map[string]string, len(pipelineRuns.Items))
+	for i := range pipelineRuns.Items {
+		run := &pipelineRuns.Items[i]
+		runBuilds[run.Name] = run.Labels[automotivev1alpha1.LabelImageBuildName]
+	}
+
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	if err := k8sClient.List(ctx, pvcs, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list PVCs: %w", err)
+	}
+	claimed := make(map[string]resource.Quantity)
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		if pvc.DeletionTimestamp != nil {
+			continue
+		}
+		build := pvc.Labels[automotivev1alpha1.LabelImageBuildName]
+		for _, ref := range pvc.OwnerReferences {
+			if build == "" && ref.Kind == "PipelineRun" {
+				build = runBuilds[ref.Name]
+			}
+		}
+		if build == "" {
+			continue
+		}
+		storage := claimed[build]
+		storage.Add(pvc.Spec.Resources.Requests[corev1.ResourceStorage])
+		claimed[build] = storage
+	}
+	return claimed, nil
+}
+
+func (u *QuotaUsage) addBuild(storage resource.Quantity, running bool) {
+	if running {
+		u.ConcurrentBuilds++
+	}
+	u.buildStorageBytes.Add(storage)
+}
+
+func (u *QuotaUsage) addWorkspace(size resource.Quantity) {
+	u.Workspaces++
+	u.workspaceStorageBytes.Add(size)
+}
+
+func (u *QuotaUsage) formatStorage() {
+	u.BuildStorage = u.buildStorageBytes.String()
+	u.WorkspaceStorage = u.workspaceStorageBytes.String()
+}
+
+func (u *QuotaUsage) setLimits(limits *automotivev1alpha1.QuotaLimits) {
+	if limits == nil {
+		return
+	}
+	u.MaxConcurrentBuilds = limits.MaxConcurrentBuilds
+	u.MaxBuildStorage = limits.MaxBuildStorage
+	u.MaxWorkspaces = limits.MaxWorkspaces
+	u.MaxWorkspaceStorage = limits.MaxWorkspaceStorage
+}
+
+// buildStorage returns the PVC storage of a build: one build PVC per architecture.
+func buildStorage(operatorConfig *automotivev1alpha1.OperatorConfig, architectures int) resource.Quantity {
+	size, err := resource.ParseQuantity(osBuildsConfig(operatorConfig).GetPVCSize())
+	if err != nil {
+		size = resource.MustParse(automotivev1alpha1.DefaultBuildPVCSize)
+	}
+	total := resource.Quantity{Format: size.Format}
+	for range max(architectures, 1) {
+		total.Add(size)
+	}
+	return total
+}
+
+func quotaConfig(operatorConfig *automotivev1alpha1.OperatorConfig) *automotivev1alpha1.QuotaConfig {
+	if operatorConfig != nil && operatorConfig.Spec.BuildAPI != nil && operatorConfig.Spec.BuildAPI.Quotas != nil {
+		return operatorConfig.Spec.BuildAPI.Quotas
+	}
+	return &automotivev1alpha1.QuotaConfig{}
+}
+
+func osBuildsConfig(operatorConfig *automotivev1alpha1.OperatorConfig) *automotivev1alpha1.OSBuildsConfig {
+	if operatorConfig != nil {
+		return operatorConfig.Spec.OSBuilds
+	}
+	return nil
+}
+
+// writeQuotaError writes the response of a request that a quota rejected,
+// or of a failure to check the quota.
+func writeQuotaError(c *gin.Context, err error) {
+	var qErr *quotaError
+	if !errors.As(err, &qErr) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(qErr.status, gin.H{
+		"error":   "quota_exceeded",
+		"reason":  qErr.reason,
+		"details": qErr.msg,
+	})
+}
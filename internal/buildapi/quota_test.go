@@ -0,0 +1,244 @@
+package buildapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+	"github.com/gin-gonic/gin"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+)
+
+var _ = Describe("Quotas", func() {
+	Describe("clampTTLForRole", func() {
+		quotas := &automotivev1alpha1.QuotaConfig{MaxBuildTTL: map[automotivev1alpha1.BuildAPIRole]string{
+			automotivev1alpha1.BuildAPIRoleBuilder: "72h",
+		}}
+
+		It("should allow TTLs up to the maximum of the role", func() {
+			ttl, err := clampTTLForRole(quotas, automotivev1alpha1.BuildAPIRoleBuilder, "48h", "24h")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(Equal("48h"))
+		})
+
+		It("should not limit roles without a maximum", func() {
+			ttl, err := clampTTLForRole(quotas, automotivev1alpha1.BuildAPIRoleAdmin, "0", "24h")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(Equal("0"))
+		})
+
+		It("should shorten a longer default TTL to the maximum", func() {
+			ttl, err := clampTTLForRole(quotas, automotivev1alpha1.BuildAPIRoleBuilder, "", "0")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(Equal("72h"))
+		})
+
+		It("should reject longer TTLs and builds that never expire", func() {
+			for _, requested := range []string{"168h", "0"} {
+				_, err := clampTTLForRole(quotas, automotivev1alpha1.BuildAPIRoleBuilder, requested, "24h")
+				var qErr *quotaError
+				Expect(err).To(BeAssignableToTypeOf(qErr))
+				Expect(err.(*quotaError).status).To(Equal(http.StatusForbidden))
+				Expect(err.(*quotaError).reason).To(Equal("max_build_ttl"))
+			}
+		})
+	})
+
+	Describe("admission", func() {
+		var (
+			server                         *APIServer
+			k8sClient                      ctrlclient.Client
+			originalLoadOperatorConfigFn   func(context.Context, ctrlclient.Client, string) (*automotivev1alpha1.OperatorConfig, error)
+			originalGetClientFromRequestFn func(*gin.Context) (ctrlclient.Client, error)
+			originalNamespace              string
+			hasOriginalNamespace           bool
+		)
+
+		newBuild := func(name, owner string, phase string, architectures ...string) *automotivev1alpha1.ImageBuild {
+			return &automotivev1alpha1.ImageBuild{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        name,
+					Namespace:   "test-ns",
+					Annotations: map[string]string{labels.RequestedBy: owner},
+				},
+				Spec:   automotivev1alpha1.ImageBuildSpec{Architectures: architectures},
+				Status: automotivev1alpha1.ImageBuildStatus{Phase: phase},
+			}
+		}
+
+		newClaim := func(name string, objectLabels map[string]string, owner string, size string) *corev1.PersistentVolumeClaim {
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-ns", Labels: objectLabels},
+				Spec: corev1.PersistentVolumeClaimSpec{Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+				}},
+			}
+			if owner != "" {
+				pvc.OwnerReferences = []metav1.OwnerReference{{Kind: "PipelineRun", Name: owner}}
+			}
+			return pvc
+		}
+
+		newPipelineRun := func(name, build string) *tektonv1.PipelineRun {
+			return &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test-ns",
+				Labels:    map[string]string{automotivev1alpha1.LabelImageBuildName: build},
+			}}
+		}
+
+		newWorkspace := func(name, owner, size string) *automotivev1alpha1.Workspace {
+			return &automotivev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-ns"},
+				Spec:       automotivev1alpha1.WorkspaceSpec{Owner: owner, PVCSize: size},
+			}
+		}
+
+		newContext := func() (*httptest.ResponseRecorder, *gin.Context) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/v1/quota", nil)
+			c.Set("requester", "alice")
+			c.Set("role", string(automotivev1alpha1.BuildAPIRoleBuilder))
+			return w, c
+		}
+
+		BeforeEach(func() {
+			gin.SetMode(gin.TestMode)
+			server = NewAPIServer(":0", logr.Discard())
+			originalLoadOperatorConfigFn = loadOperatorConfigFn
+			originalGetClientFromRequestFn = getClientFromRequestFn
+			loadOperatorConfigFn = func(_ context.Context, _ ctrlclient.Client, _ string) (*automotivev1alpha1.OperatorConfig, error) {
+				return &automotivev1alpha1.OperatorConfig{Spec: automotivev1alpha1.OperatorConfigSpec{
+					OSBuilds: &automotivev1alpha1.OSBuildsConfig{PVCSize: "10Gi"},
+					BuildAPI: &automotivev1alpha1.BuildAPIConfig{Quotas: &automotivev1alpha1.QuotaConfig{
+						User: &automotivev1alpha1.QuotaLimits{MaxConcurrentBuilds: 2, MaxWorkspaces: 2, MaxWorkspaceStorage: "30Gi"},
+						Team: &automotivev1alpha1.QuotaLimits{MaxBuildStorage: "50Gi"},
+					}},
+				}}, nil
+			}
+			originalNamespace, hasOriginalNamespace = os.LookupEnv("BUILD_API_NAMESPACE")
+			Expect(os.Setenv("BUILD_API_NAMESPACE", "test-ns")).To(Succeed())
+
+			scheme := runtime.NewScheme()
+			Expect(automotivev1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			Expect(tektonv1.AddToScheme(scheme)).To(Succeed())
+			k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				newBuild("running", "alice", "Building"),
+				newBuild("done", "alice", "Completed", "amd64", "arm64"),
+				newPipelineRun("done-build-amd64", "done"),
+				newPipelineRun("done-build-arm64", "done"),
+				newClaim("pvc-done-amd64", nil, "done-build-amd64", "10Gi"),
+				newClaim("pvc-done-arm64", nil, "done-build-arm64", "10Gi"),
+				newBuild("other", "bob", "Completed"),
+				newClaim("other-ws", map[string]string{automotivev1alpha1.LabelImageBuildName: "other"}, "", "10Gi"),
+				newBuild("pruned", "alice", "Completed"),
+				newClaim("build-cache", map[string]string{labels.Component: "build-cache"}, "", "20Gi"),
+				newWorkspace("dev", "alice", "20Gi"),
+				newWorkspace("bobs", "bob", "10Gi"),
+			).Build()
+			getClientFromRequestFn = func(_ *gin.Context) (ctrlclient.Client, error) {
+				return k8sClient, nil
+			}
+		})
+
+		AfterEach(func() {
+			loadOperatorConfigFn = originalLoadOperatorConfigFn
+			getClientFromRequestFn = originalGetClientFromRequestFn
+			if hasOriginalNamespace {
+				Expect(os.Setenv("BUILD_API_NAMESPACE", originalNamespace)).To(Succeed())
+			} else {
+				Expect(os.Unsetenv("BUILD_API_NAMESPACE")).To(Succeed())
+			}
+		})
+
+		It("should report usage against the limits", func() {
+			w, c := newContext()
+			server.getQuota(c)
+			Expect(w.Code).To(Equal(http.StatusOK), w.Body.String())
+
+			var resp QuotaResponse
+			Expect(json.Unmarshal(w.Body.Bytes(), &resp)).To(Succeed())
+			Expect(resp.User).To(Equal("alice"))
+			Expect(resp.Team).To(Equal("test-ns"))
+			Expect(resp.UserUsage.ConcurrentBuilds).To(Equal(int32(1)))
+			Expect(resp.UserUsage.MaxConcurrentBuilds).To(Equal(int32(2)))
+			Expect(resp.UserUsage.BuildStorage).To(Equal("30Gi"))
+			Expect(resp.UserUsage.Workspaces).To(Equal(int32(1)))
+			Expect(resp.TeamUsage.BuildStorage).To(Equal("40Gi"))
+			Expect(resp.TeamUsage.MaxBuildStorage).To(Equal("50Gi"))
+			Expect(resp.TeamUsage.WorkspaceStorage).To(Equal("30Gi"))
+		})
+
+		It("should not count the storage of expired builds", func() {
+			expired := newBuild("expired", "alice", "Expired", "amd64", "arm64")
+			expired.Status.PVCName = "expired-ws"
+			Expect(k8sClient.Create(context.Background(), expired)).To(Succeed())
+
+			cfg, err := loadOperatorConfigFn(context.Background(), k8sClient, "test-ns")
+			Expect(err).NotTo(HaveOccurred())
+			user, team, err := measureQuotaUsage(context.Background(), k8sClient, cfg, "test-ns", "alice")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(user.BuildStorage).To(Equal("30Gi"))
+			Expect(team.BuildStorage).To(Equal("40Gi"))
+		})
+
+		It("should admit builds within the quotas", func() {
+			_, c := newContext()
+			ttl, err := server.admitBuild(context.Background(), c, k8sClient, "test-ns", 1, "48h")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(Equal("48h"))
+		})
+
+		It("should reject builds over the team's storage", func() {
+			_, c := newContext()
+			_, err := server.admitBuild(context.Background(), c, k8sClient, "test-ns", 2, "")
+			Expect(err).To(HaveOccurred())
+
+			w, c := newContext()
+			writeQuotaError(c, err)
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(w.Body.String()).To(ContainSubstring("max_build_storage"))
+			Expect(w.Body.String()).To(ContainSubstring("team test-ns"))
+		})
+
+		It("should throttle builds over the concurrent builds", func() {
+			Expect(k8sClient.Create(context.Background(), newBuild("second", "alice", "Queued"))).To(Succeed())
+			_, c := newContext()
+			_, err := server.admitBuild(context.Background(), c, k8sClient, "test-ns", 1, "")
+			Expect(err).To(HaveOccurred())
+
+			w, c := newContext()
+			writeQuotaError(c, err)
+			Expect(w.Code).To(Equal(http.StatusTooManyRequests))
+			Expect(w.Body.String()).To(ContainSubstring("max_concurrent_builds"))
+		})
+
+		It("should reject workspaces over the user's storage", func() {
+			_, c := newContext()
+			Expect(server.admitWorkspace(context.Background(), c, k8sClient, nil, "test-ns", "20Gi")).To(Succeed())
+
+			cfg, err := loadOperatorConfigFn(context.Background(), k8sClient, "test-ns")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.admitWorkspace(context.Background(), c, k8sClient, cfg, "test-ns", "10Gi")).To(Succeed())
+			err = server.admitWorkspace(context.Background(), c, k8sClient, cfg, "test-ns", "20Gi")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("user alice uses 20Gi of 30Gi workspace storage"))
+		})
+	})
+})
@@ -0,0 +1,199 @@
+package buildapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/storage"
+)
+
+// buildNameSuffix matches the random suffix createBuild appends to build names.
+var buildNameSuffix = regexp.MustCompile(`-[0-9a-f]{5}$`)
+
+// rebuildBuild creates a new ImageBuild from the inputs of an existing build,
+// applying the overrides of a RebuildRequest.
+func (a *APIServer) rebuildBuild(c *gin.Context, name string) {
+	ctx, span := apiTracer.Start(c.Request.Context(), "rebuildBuild")
+	defer span.End()
+	c.Request = c.Request.WithContext(ctx)
+
+	var overrides RebuildRequest
+	if err := c.ShouldBindJSON(&overrides); err != nil && !errors.Is(err, io.EOF) {
+		spanError(span, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON request"})
+		return
+	}
+
+	k8sClient, err := getK8sClientOrFail(c)
+	if err != nil {
+		spanError(span, err)
+		return
+	}
+
+	parent := &automotivev1alpha1.ImageBuild{}
+	if err := getResourceOrFail(ctx, c, k8sClient, name, requestNamespace(c), parent, "build"); err != nil {
+		spanError(span, err)
+		return
+	}
+	span.SetAttributes(attribute.String("build.parent", parent.Name))
+
+	req, err := rebuildRequest(ctx, k8sClient, parent, &overrides)
+	if err != nil {
+		spanError(span, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	a.submitBuild(c, span, req, parent.Name)
+}
+
+// rebuildRequest returns the request that rebuilds parent: the parent's inputs
+// and push destinations with the overrides applied.
+func rebuildRequest(
+	ctx context.Context, k8sClient client.Client, parent *automotivev1alpha1.ImageBuild, overrides *RebuildRequest,
+) (*BuildRequest, error) {
+	req := templateRequest(parent)
+	req.Name = buildNameSuffix.ReplaceAllString(parent.Name, "")
+	req.ContainerRef = parent.Spec.GetContainerRef()
+	req.StorageClass = parent.Spec.StorageClass
+	req.BuilderImage = parent.Spec.GetBuilderImage()
+	req.BuildDiskImage = parent.Spec.GetBuildDiskImage()
+	req.HasLocalFiles = overrides.HasLocalFiles || parent.Spec.GetInputFilesServer()
+	req.Workspace = parent.Spec.Workspace
+	// The workspace URL define is added again when the workspace is resolved.
+	req.CustomDefs = removeDefine(req.CustomDefs, "workspace_url")
+
+	req.ContainerPush = parent.Spec.GetContainerPush()
+	req.ExportOCI = parent.Spec.GetExportOCI()
+	if parent.Spec.GetUseServiceAccountAuth() {
+		applyInternalRegistryRebuild(&req, parent)
+	}
+
+	if loc := diskLocationResponse(parent); loc != nil {
+		creds, err := storageExportCredentials(ctx, k8sClient, parent, loc.SecretRef)
+		if err != nil {
+			return nil, err
+		}
+		if creds != nil {
+			loc.Credentials = creds
+			loc.SecretRef = ""
+		}
+		req.ExportLocation = loc
+	}
+
+	if overrides.Name != "" {
+		req.Name = overrides.Name
+	}
+	req.CustomDefs = mergeDefines(req.CustomDefs, overrides.CustomDefs)
+	if overrides.Target != "" {
+		req.Target = overrides.Target
+	}
+	if overrides.Architecture != "" {
+		req.Architecture = overrides.Architecture
+		req.Architectures = nil
+	}
+	if overrides.AutomotiveImageBuilder != "" {
+		req.AutomotiveImageBuilder = overrides.AutomotiveImageBuilder
+	}
+	if overrides.TTL != "" {
+		req.TTL = overrides.TTL
+	}
+	req.RegistryCredentials = overrides.RegistryCredentials
+
+	return &req, nil
+}
+
+// applyInternalRegistryRebuild configures an internal registry push for a
+// rebuild. Internal registry references are generated from the build name, so
+// they are dropped and only a non-default image name or tag is carried over.
+func applyInternalRegistryRebuild(req *BuildRequest, parent *automotivev1alpha1.ImageBuild) {
+	req.UseInternalRegistry = true
+
+	internalRef := req.ExportOCI
+	if isInternalRegistryRef(req.ContainerPush) {
+		internalRef = req.ContainerPush
+		req.ContainerPush = ""
+	}
+	req.ExportOCI = ""
+
+	if internalRef == "" {
+		return
+	}
+	repo, tag, _ := strings.Cut(internalRef[strings.LastIndex(internalRef, "/")+1:], ":")
+	if repo != parent.Name {
+		req.InternalRegistryImageName = repo
+	}
+	if tag != "bootc" && tag != "disk" {
+		req.InternalRegistryTag = tag
+	}
+}
+
+func isInternalRegistryRef(ref string) bool {
+	return strings.HasPrefix(ref, defaultInternalRegistryURL+"/")
+}
+
+// storageExportCredentials returns the inline credentials a build was
+// submitted with for its storage export. They live in a build-owned secret;
+// user-provided secrets are referenced by name and return nil.
+func storageExportCredentials(
+	ctx context.Context, k8sClient client.Client, build *automotivev1alpha1.ImageBuild, secretRef string,
+) (*StorageCredentials, error) {
+	if secretRef == "" || secretRef != fmt.Sprintf("%s-export-auth", build.Name) {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: secretRef, Namespace: build.Namespace}, secret); err != nil {
+		return nil, fmt.Errorf("failed to read export credentials of build %s: %w", build.Name, err)
+	}
+	return &StorageCredentials{
+		AccessKeyID:     string(secret.Data[storage.SecretKeyAccessKeyID]),
+		SecretAccessKey: string(secret.Data[storage.SecretKeySecretAccessKey]),
+		Token:           string(secret.Data[storage.SecretKeyToken]),
+		Username:        string(secret.Data[storage.SecretKeyUsername]),
+		Password:        string(secret.Data[storage.SecretKeyPassword]),
+	}, nil
+}
+
+// mergeDefines returns defines with overrides applied. An override replaces
+// the define with the same key in place; other overrides are appended.
+func mergeDefines(defines, overrides []string) []string {
+	merged := append([]string(nil), defines...)
+	for _, override := range overrides {
+		key, _, _ := strings.Cut(override, "=")
+		replaced := false
+		for i, define := range merged {
+			if k, _, _ := strings.Cut(define, "="); k == key {
+				merged[i] = override
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, override)
+		}
+	}
+	return merged
+}
+
+// removeDefine returns defines without the define with the given key.
+func removeDefine(defines []string, key string) []string {
+	var out []string
+	for _, define := range defines {
+		if k, _, _ := strings.Cut(define, "="); k != key {
+			out = append(out, define)
+		}
+	}
+	return out
+}
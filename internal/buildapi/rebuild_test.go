@@ -0,0 +1,168 @@
+package buildapi
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/storage"
+)
+
+var _ = Describe("rebuildRequest", func() {
+	newParent := func() *automotivev1alpha1.ImageBuild {
+		return &automotivev1alpha1.ImageBuild{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-build-ab12c", Namespace: "test-ns"},
+			Spec: automotivev1alpha1.ImageBuildSpec{
+				Architecture: "amd64",
+				StorageClass: "fast",
+				AIB: &automotivev1alpha1.AIBSpec{
+					Distro:           "autosd",
+					Target:           "qemu",
+					Mode:             "bootc",
+					Manifest:         "name: test\n",
+					ManifestFileName: "test.aib.yml",
+					Image:            "quay.io/centos-sig-automotive/automotive-image-builder:1.0",
+					CustomDefs:       []string{"debug=false", "workspace_url=http://ws:8080", "extra=1"},
+					InputFilesServer: true,
+				},
+				Export: &automotivev1alpha1.ExportSpec{
+					Format:         "qcow2",
+					BuildDiskImage: true,
+					Container:      "quay.io/org/app:bootc",
+					Disk:           &automotivev1alpha1.DiskExport{OCI: "quay.io/org/app:disk"},
+				},
+				Workspace: "dev",
+				TTL:       "24h",
+				Priority:  "high",
+			},
+		}
+	}
+
+	newClient := func() *fake.ClientBuilder {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		return fake.NewClientBuilder().WithScheme(scheme)
+	}
+
+	It("clones the parent inputs and push destinations", func() {
+		req, err := rebuildRequest(context.Background(), newClient().Build(), newParent(), &RebuildRequest{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(req.Name).To(Equal("my-build"))
+		Expect(req.Manifest).To(Equal("name: test\n"))
+		Expect(req.ManifestFileName).To(Equal("test.aib.yml"))
+		Expect(req.Target).To(Equal(Target("qemu")))
+		Expect(req.Architecture).To(Equal(Architecture("amd64")))
+		Expect(req.StorageClass).To(Equal("fast"))
+		Expect(req.ContainerPush).To(Equal("quay.io/org/app:bootc"))
+		Expect(req.ExportOCI).To(Equal("quay.io/org/app:disk"))
+		Expect(req.BuildDiskImage).To(BeTrue())
+		Expect(req.Workspace).To(Equal("dev"))
+		Expect(req.Priority).To(Equal("high"))
+		Expect(req.HasLocalFiles).To(BeTrue())
+		Expect(req.CustomDefs).To(Equal([]string{"debug=false", "extra=1"}))
+	})
+
+	It("applies overrides", func() {
+		req, err := rebuildRequest(context.Background(), newClient().Build(), newParent(), &RebuildRequest{
+			Name:                   "tweaked",
+			CustomDefs:             []string{"debug=true", "new=2"},
+			Target:                 "ridesx4",
+			Architecture:           "arm64",
+			AutomotiveImageBuilder: "quay.io/org/aib:next",
+			TTL:                    "1h",
+			RegistryCredentials:    &RegistryCredentials{Enabled: true, Username: "user"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(req.Name).To(Equal("tweaked"))
+		Expect(req.CustomDefs).To(Equal([]string{"debug=true", "extra=1", "new=2"}))
+		Expect(req.Target).To(Equal(Target("ridesx4")))
+		Expect(req.Architecture).To(Equal(Architecture("arm64")))
+		Expect(req.Architectures).To(BeEmpty())
+		Expect(req.AutomotiveImageBuilder).To(Equal("quay.io/org/aib:next"))
+		Expect(req.TTL).To(Equal("1h"))
+		Expect(req.RegistryCredentials.Username).To(Equal("user"))
+	})
+
+	It("regenerates internal registry references for the new build", func() {
+		parent := newParent()
+		parent.Spec.Export.UseServiceAccountAuth = true
+		parent.Spec.Export.Container = defaultInternalRegistryURL + "/test-ns/my-build-ab12c:bootc"
+		parent.Spec.Export.Disk.OCI = defaultInternalRegistryURL + "/test-ns/my-build-ab12c:disk"
+
+		req, err := rebuildRequest(context.Background(), newClient().Build(), parent, &RebuildRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(req.UseInternalRegistry).To(BeTrue())
+		Expect(req.ContainerPush).To(BeEmpty())
+		Expect(req.ExportOCI).To(BeEmpty())
+		Expect(req.InternalRegistryImageName).To(BeEmpty())
+		Expect(req.InternalRegistryTag).To(BeEmpty())
+
+		parent.Spec.Export.Container = "quay.io/org/app:bootc"
+		parent.Spec.Export.Disk.OCI = defaultInternalRegistryURL + "/test-ns/custom:v2"
+		req, err = rebuildRequest(context.Background(), newClient().Build(), parent, &RebuildRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(req.ContainerPush).To(Equal("quay.io/org/app:bootc"))
+		Expect(req.InternalRegistryImageName).To(Equal("custom"))
+		Expect(req.InternalRegistryTag).To(Equal("v2"))
+	})
+
+	It("reuses the inline credentials of the parent's storage export", func() {
+		parent := newParent()
+		parent.Spec.Export.Disk = &automotivev1alpha1.DiskExport{S3: &automotivev1alpha1.S3Location{
+			Endpoint:  "http://minio:9000",
+			Bucket:    "images",
+			Key:       "disk.qcow2",
+			SecretRef: "my-build-ab12c-export-auth",
+		}}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-build-ab12c-export-auth", Namespace: "test-ns"},
+			Data: map[string][]byte{
+				storage.SecretKeyAccessKeyID:     []byte("minio"),
+				storage.SecretKeySecretAccessKey: []byte("minio123"),
+			},
+		}
+
+		req, err := rebuildRequest(context.Background(), newClient().WithObjects(secret).Build(), parent, &RebuildRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(req.ExportLocation).NotTo(BeNil())
+		Expect(req.ExportLocation.URL).To(Equal("s3://images/disk.qcow2"))
+		Expect(req.ExportLocation.SecretRef).To(BeEmpty())
+		Expect(req.ExportLocation.Credentials.AccessKeyID).To(Equal("minio"))
+		Expect(req.ExportLocation.Credentials.SecretAccessKey).To(Equal("minio123"))
+	})
+
+	It("keeps references to user-provided export secrets", func() {
+		parent := newParent()
+		parent.Spec.Export.Disk = &automotivev1alpha1.DiskExport{HTTP: &automotivev1alpha1.HTTPLocation{
+			URL:       "https://files.example.com/disk.raw",
+			SecretRef: "upload-creds",
+		}}
+
+		req, err := rebuildRequest(context.Background(), newClient().Build(), parent, &RebuildRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(req.ExportLocation.SecretRef).To(Equal("upload-creds"))
+		Expect(req.ExportLocation.Credentials).To(BeNil())
+	})
+})
+
+var _ = Describe("mergeDefines", func() {
+	It("replaces defines with the same key and appends new ones", func() {
+		Expect(mergeDefines([]string{"a=1", "b=2"}, []string{"b=3", "c=4"})).
+			To(Equal([]string{"a=1", "b=3", "c=4"}))
+	})
+
+	It("does not modify the parent defines", func() {
+		defines := []string{"a=1"}
+		_ = mergeDefines(defines, []string{"a=2"})
+		Expect(defines).To(Equal([]string{"a=1"}))
+	})
+})
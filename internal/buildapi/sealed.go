@@ -99,7 +99,7 @@ func validateSealedRequest(req *SealedRequest) ([]string, string) {
 func (a *APIServer) registerSealedRoutes(v1 *gin.RouterGroup) {
 	for _, opPath := range []string{"/prepare-reseals", "/reseals", "/extract-for-signings", "/inject-signeds"} {
 		grp := v1.Group(opPath)
-		grp.Use(sealedMetricsMiddleware(), a.authMiddleware())
+		grp.Use(sealedMetricsMiddleware(), a.authMiddleware(), a.authorizeMiddleware())
 		{
 			grp.POST("", a.handleCreateSealed)
 			grp.GET("", a.wrapHandler("list reseal jobs", a.listSealed))
@@ -158,7 +158,7 @@ func (a *APIServer) createSealed(c *gin.Context, pathOp SealedOperation) {
 		SealedCreateRequestsTotal.WithLabelValues(opLabel, "error").Inc()
 		return
 	}
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 	requestedBy := a.resolveRequester(c)
 
 	refs, err := createSealedSecrets(ctx, clientset, namespace, &req)
@@ -243,7 +243,6 @@ func (a *APIServer) listSealed(c *gin.Context) {
 	ctx, span := apiTracer.Start(c.Request.Context(), "listSealed")
 	defer span.End()
 
-	namespace := resolveNamespace()
 	limit, offset := parsePagination(c)
 
 	k8sClient, err := getK8sClientOrFail(c)
@@ -252,7 +251,7 @@ func (a *APIServer) listSealed(c *gin.Context) {
 		return
 	}
 	list := &automotivev1alpha1.ImageResealList{}
-	if err := k8sClient.List(ctx, list, client.InNamespace(namespace)); err != nil {
+	if err := listInNamespaces(ctx, c, k8sClient, list); err != nil {
 		spanError(span, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to list ImageReseal: %v", err)})
 		return
@@ -287,7 +286,7 @@ func (a *APIServer) getSealed(c *gin.Context, name string) {
 	defer span.End()
 	span.SetAttributes(attribute.String("sealed.name", name))
 
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 	k8sClient, err := getK8sClientOrFail(c)
 	if err != nil {
 		spanError(span, err)
@@ -323,7 +322,7 @@ func (a *APIServer) streamSealedLogs(c *gin.Context, name string) {
 	defer span.End()
 	span.SetAttributes(attribute.String("sealed.name", name))
 
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 	k8sClient, err := getK8sClientOrFail(c)
 	if err != nil {
 		spanError(span, err)
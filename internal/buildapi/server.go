@@ -76,6 +76,7 @@ const (
 	phaseFailed    = automotivev1alpha1.ImageBuildPhaseFailed
 	phasePending   = automotivev1alpha1.ImageBuildPhasePending
 	phaseUploading = automotivev1alpha1.ImageBuildPhaseUploading
+	phaseQueued    = automotivev1alpha1.ImageBuildPhaseQueued
 	phaseBuilding  = automotivev1alpha1.ImageBuildPhaseBuilding
 	phasePushing   = automotivev1alpha1.ImageBuildPhasePushing
 	phaseFlashing  = automotivev1alpha1.ImageBuildPhaseFlashing
@@ -216,10 +217,13 @@ type APIServer struct {
 	internalPrefix      string
 	authConfig          *AuthenticationConfiguration // Store raw config for API exposure
 	oidcClientID        string
-	authConfigMu        sync.RWMutex // Protects externalJWT, authConfig, internalPrefix, oidcClientID
+	tenancy             *automotivev1alpha1.TenancyConfig
+	authorization       *automotivev1alpha1.AuthorizationConfig
+	authConfigMu        sync.RWMutex // Protects externalJWT, authConfig, internalPrefix, oidcClientID, tenancy, authorization
 	lastAuthConfigCheck time.Time    // Last time we checked OperatorConfig
 	progressCache       map[string]progressCacheEntry
 	progressCacheMu     sync.RWMutex
+	events              *eventHub
 }
 
 //go:embed openapi.yaml
@@ -238,7 +242,7 @@ func NewAPIServerWithLimits(addr string, logger logr.Logger, limits APILimits) *
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	a := &APIServer{addr: addr, log: logger, limits: limits}
+	a := &APIServer{addr: addr, log: logger, limits: limits, events: newEventHub(logger.WithName("events"))}
 	if clientID := strings.TrimSpace(os.Getenv("BUILD_API_OIDC_CLIENT_ID")); clientID != "" {
 		a.oidcClientID = clientID
 	}
@@ -314,6 +318,7 @@ func LoadLimitsFromConfig(cfg *automotivev1alpha1.BuildAPIConfig) APILimits {
 
 // Start implements manager.Runnable
 func (a *APIServer) Start(ctx context.Context) error {
+	go a.events.run(ctx, a.watchedNamespaces)
 
 	go func() {
 		a.log.Info("build-api listening", "addr", a.addr)
@@ -372,37 +377,49 @@ func (a *APIServer) createRouter() *gin.Engine {
 		v1.GET("/auth/config", a.handleGetAuthConfig)
 
 		buildsGroup := v1.Group("/builds")
-		buildsGroup.Use(a.authMiddleware())
+		buildsGroup.Use(a.authMiddleware(), a.authorizeMiddleware())
 		{
 			buildsGroup.POST("", a.wrapHandler("create build", a.createBuild))
 			buildsGroup.GET("", a.wrapHandler("list builds", listBuilds))
+			buildsGroup.GET("/diff", a.wrapHandler("diff builds", diffBuilds))
 			buildsGroup.GET("/:name", a.wrapNamedHandler("get build", a.getBuild))
 			buildsGroup.GET("/:name/logs", a.wrapNamedHandler("logs requested", a.streamLogs))
 			buildsGroup.GET("/:name/progress", a.handleGetProgress)
+			buildsGroup.GET("/:name/watch", a.handleWatchBuild)
 			buildsGroup.GET("/:name/template", a.wrapNamedHandler("template requested", getBuildTemplate))
+			buildsGroup.GET("/:name/tests", a.wrapNamedHandler("get build tests", a.getBuildTests))
+			buildsGroup.GET("/:name/disk", a.wrapNamedHandler("disk download requested", a.downloadDisk))
 			buildsGroup.POST("/:name/uploads", a.wrapNamedHandler("uploads", a.uploadFiles))
 			buildsGroup.POST("/:name/token", a.handleCreateBuildToken)
 			buildsGroup.POST("/:name/cancel", a.wrapNamedHandler("cancel build", a.cancelBuild))
+			buildsGroup.POST("/:name/rebuild", a.wrapNamedHandler("rebuild build", a.rebuildBuild))
 			buildsGroup.DELETE("/:name", a.wrapNamedHandler("delete build", a.deleteBuild))
 		}
 
 		flashGroup := v1.Group("/flash")
-		flashGroup.Use(flashMetricsMiddleware(), a.authMiddleware())
+		flashGroup.Use(flashMetricsMiddleware(), a.authMiddleware(), a.authorizeMiddleware())
 		{
 			flashGroup.POST("", a.wrapHandler("create flash", a.createFlash))
 			flashGroup.GET("", a.wrapHandler("list flash jobs", a.listFlash))
 			flashGroup.GET("/:name", a.wrapNamedHandler("get flash", a.getFlash))
 			flashGroup.GET("/:name/logs", a.wrapNamedHandler("flash logs requested", a.streamFlashLogs))
+			flashGroup.GET("/:name/console", a.wrapNamedHandler("flash console requested", a.streamFlashConsole))
+		}
+
+		eventsGroup := v1.Group("/events")
+		eventsGroup.Use(a.authMiddleware(), a.authorizeMiddleware())
+		{
+			eventsGroup.GET("", a.handleEvents)
 		}
 
 		configGroup := v1.Group("/config")
-		configGroup.Use(a.authMiddleware())
+		configGroup.Use(a.authMiddleware(), a.authorizeMiddleware())
 		{
 			configGroup.GET("", a.handleGetOperatorConfig)
 		}
 
 		containerBuildsGroup := v1.Group("/container-builds")
-		containerBuildsGroup.Use(a.authMiddleware())
+		containerBuildsGroup.Use(a.authMiddleware(), a.authorizeMiddleware())
 		{
 			containerBuildsGroup.POST("", a.wrapHandler("create container build", a.createContainerBuild))
 			containerBuildsGroup.GET("", a.wrapHandler("list container builds", listContainerBuilds))
@@ -414,14 +431,19 @@ func (a *APIServer) createRouter() *gin.Engine {
 		a.registerSealedRoutes(v1)
 
 		a.registerWorkspaceRoutes(v1)
+		a.registerWorkspaceSnapshotRoutes(v1)
+
+		a.registerLeaseRoutes(v1)
+
+		a.registerQuotaRoutes(v1)
 
-		// Register catalog routes with authentication
+		// Register catalog routes with authentication and authorization
 		catalogClient, err := a.getCatalogClient()
 		if err != nil {
 			a.log.Error(err, "failed to create catalog client, catalog routes will not be available")
 		} else if catalogClient != nil {
 			a.log.Info("registering catalog routes")
-			catalog.RegisterRoutes(v1, catalogClient, a.log)
+			catalog.RegisterRoutes(v1, catalogClient, a.log, a.authMiddleware(), a.authorizeMiddleware())
 		}
 	}
 
@@ -478,7 +500,7 @@ func (a *APIServer) handleCreateBuildToken(c *gin.Context) {
 	name := c.Param("name")
 	a.log.Info("token requested", "build", name, "reqID", c.GetString("reqID"))
 
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 	k8sClient, err := getK8sClientOrFail(c)
 	if err != nil {
 		return
@@ -490,11 +512,9 @@ func (a *APIServer) handleCreateBuildToken(c *gin.Context) {
 		return
 	}
 
-	// Verify the requesting user owns this build
-	requester := a.resolveRequester(c)
-	owner := build.Annotations[labels.RequestedBy]
-	if owner != requester {
-		c.JSON(http.StatusForbidden, gin.H{"error": "you can only request tokens for your own builds"})
+	// Verify the requesting user owns this build or releases builds
+	if !a.canActOn(c, build.Annotations[labels.RequestedBy], automotivev1alpha1.BuildAPIRoleReleaser) {
+		forbidden(c, reasonNotOwner, "you can only request tokens for your own builds")
 		return
 	}
 
@@ -518,7 +538,7 @@ func (a *APIServer) handleCreateBuildToken(c *gin.Context) {
 		return
 	}
 
-	tokenLifetime := resolveTokenLifetime(ctx, k8sClient, namespace)
+	tokenLifetime := resolveTokenLifetime(ctx, k8sClient, resolveNamespace())
 	token, expiresAt, err := a.mintRegistryToken(ctx, c, namespace, tokenLifetime)
 	if err != nil {
 		a.log.Error(err, "failed to mint registry token", "build", name)
@@ -527,7 +547,7 @@ func (a *APIServer) handleCreateBuildToken(c *gin.Context) {
 	}
 
 	registryHost := ""
-	externalRoute, routeErr := getExternalRegistryRoute(ctx, k8sClient, namespace)
+	externalRoute, routeErr := getExternalRegistryRoute(ctx, k8sClient, resolveNamespace())
 	if routeErr == nil && externalRoute != "" {
 		imageRef = translateToExternalURL(imageRef, externalRoute)
 		registryHost = externalRoute
@@ -550,7 +570,7 @@ func (a *APIServer) deleteBuild(c *gin.Context, name string) {
 		return
 	}
 
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 	ctx := c.Request.Context()
 
 	build := &automotivev1alpha1.ImageBuild{}
@@ -558,10 +578,8 @@ func (a *APIServer) deleteBuild(c *gin.Context, name string) {
 		return
 	}
 
-	requester := a.resolveRequester(c)
-	owner := build.Annotations[labels.RequestedBy]
-	if owner != requester {
-		c.JSON(http.StatusForbidden, gin.H{"error": "you can only delete your own builds"})
+	if !a.canActOn(c, build.Annotations[labels.RequestedBy], automotivev1alpha1.BuildAPIRoleAdmin) {
+		forbidden(c, reasonNotOwner, "you can only delete your own builds")
 		return
 	}
 
@@ -610,7 +628,7 @@ func (a *APIServer) cancelBuild(c *gin.Context, name string) {
 		return
 	}
 
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 	ctx := c.Request.Context()
 
 	build := &automotivev1alpha1.ImageBuild{}
@@ -618,15 +636,13 @@ func (a *APIServer) cancelBuild(c *gin.Context, name string) {
 		return
 	}
 
-	requester := a.resolveRequester(c)
-	owner := build.Annotations[labels.RequestedBy]
-	if owner != requester {
-		c.JSON(http.StatusForbidden, gin.H{"error": "you can only cancel your own builds"})
+	if !a.canActOn(c, build.Annotations[labels.RequestedBy], automotivev1alpha1.BuildAPIRoleAdmin) {
+		forbidden(c, reasonNotOwner, "you can only cancel your own builds")
 		return
 	}
 
 	switch build.Status.Phase {
-	case "", phasePending, phaseUploading, phaseBuilding, phasePushing, phaseFlashing:
+	case "", phasePending, phaseUploading, phaseQueued, phaseBuilding, phasePushing, phaseFlashing:
 		// cancellable
 	default:
 		c.JSON(http.StatusConflict, gin.H{
@@ -635,7 +651,12 @@ func (a *APIServer) cancelBuild(c *gin.Context, name string) {
 		return
 	}
 
-	if build.Status.PipelineRunName != "" {
+	if build.Spec.IsMultiArch() {
+		if err := cancelArchitecturePipelineRuns(ctx, k8sClient, build); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	} else if build.Status.PipelineRunName != "" {
 		pipelineRun := &tektonv1.PipelineRun{}
 		prKey := types.NamespacedName{Name: build.Status.PipelineRunName, Namespace: namespace}
 		if err := k8sClient.Get(ctx, prKey, pipelineRun); err != nil {
@@ -734,7 +755,7 @@ func (a *APIServer) setupInternalRegistryBuild(
 		return "", "", fmt.Errorf("validation error")
 	}
 	// Resolve external route (validates registry is reachable)
-	if _, err := getExternalRegistryRoute(ctx, k8sClient, namespace); err != nil {
+	if _, err := getExternalRegistryRoute(ctx, k8sClient, resolveNamespace()); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return "", "", err
 	}
@@ -795,7 +816,7 @@ func (a *APIServer) setupInternalRegistryBuild(
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error getting REST config: %v", err)})
 		return "", "", err
 	}
-	tokenLifetime := resolveTokenLifetime(ctx, k8sClient, namespace)
+	tokenLifetime := resolveTokenLifetime(ctx, k8sClient, resolveNamespace())
 	secretName, err := createInternalRegistrySecret(ctx, restCfg, namespace, req.Name, tokenLifetime)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -809,12 +830,11 @@ func (a *APIServer) setupInternalRegistryBuild(
 // buildExportSpec creates ExportSpec configuration from build request
 // resolveExtraRepos processes --extra-repo flags (workspace:path pairs), starts HTTP
 // servers in the workspace pods, and injects extra_repos into the build's CustomDefs.
-func (a *APIServer) resolveExtraRepos(ctx context.Context, k8sClient client.Client, restCfg *rest.Config, req *BuildRequest) error {
+func (a *APIServer) resolveExtraRepos(ctx context.Context, k8sClient client.Client, restCfg *rest.Config, namespace string, req *BuildRequest) error {
 	if len(req.ExtraRepos) == 0 {
 		return nil
 	}
 
-	namespace := resolveNamespace()
 	basePort := 8080
 
 	type repoEntry struct {
@@ -880,7 +900,7 @@ func (a *APIServer) resolveExtraRepos(ctx context.Context, k8sClient client.Clie
 // - Starts an HTTP file server in the workspace pod and injects workspace_url as a custom define
 // Returns the build-cache PVC name.
 func (a *APIServer) resolveWorkspaceForBuild(ctx context.Context, k8sClient client.Client, restCfg *rest.Config, namespace, wsName, requester string, req *BuildRequest) (string, error) {
-	operatorConfig, _ := loadOperatorConfigFn(ctx, k8sClient, namespace)
+	operatorConfig, _ := loadOperatorConfigFn(ctx, k8sClient, resolveNamespace())
 	var wsConfig *automotivev1alpha1.WorkspacesConfig
 	if operatorConfig != nil {
 		wsConfig = operatorConfig.Spec.Workspaces
@@ -1013,7 +1033,7 @@ func resolveTaskBundleRef(ctx context.Context, k8sClient client.Client, namespac
 		return "", 0, nil
 	}
 
-	operatorConfig, err := loadOperatorConfigFn(ctx, k8sClient, namespace)
+	operatorConfig, err := loadOperatorConfigFn(ctx, k8sClient, resolveNamespace())
 	if err != nil {
 		return "", http.StatusInternalServerError, fmt.Errorf("secureBuild requested but OperatorConfig could not be read: %w", err)
 	}
@@ -1096,15 +1116,34 @@ func (a *APIServer) createBuild(c *gin.Context) {
 		return
 	}
 
+	a.submitBuild(c, span, &req, "")
+}
+
+// submitBuild validates a build request and creates its ImageBuild. A non-empty
+// parentBuild links the new build to the build it was cloned from.
+func (a *APIServer) submitBuild(c *gin.Context, span trace.Span, req *BuildRequest, parentBuild string) {
+	ctx := c.Request.Context()
 	needsUpload := req.HasLocalFiles || manifestNeedsUpload(req.Manifest)
 
-	if err := validateBuildRequest(&req); err != nil {
+	if err := validateBuildRequest(req); err != nil {
+		spanError(span, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := applyBuildDefaults(req); err != nil {
+		spanError(span, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validateMultiArchRequest(req, needsUpload); err != nil {
 		spanError(span, err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := applyBuildDefaults(&req); err != nil {
+	if err := validateExportLocation(req); err != nil {
 		spanError(span, err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -1116,14 +1155,25 @@ func (a *APIServer) createBuild(c *gin.Context) {
 		return
 	}
 
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 
-	effectiveTTL, ttlErr := resolveAndClampTTL(ctx, k8sClient, namespace, req.TTL)
+	effectiveTTL, ttlErr := resolveAndClampTTL(ctx, k8sClient, resolveNamespace(), req.TTL)
 	if ttlErr != nil {
 		spanError(span, ttlErr)
 		c.JSON(http.StatusBadRequest, gin.H{"error": ttlErr.Error()})
 		return
 	}
+	if err := validatePriority(ctx, k8sClient, resolveNamespace(), req.Priority); err != nil {
+		spanError(span, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	effectiveTTL, err = a.admitBuild(ctx, c, k8sClient, namespace, len(req.Architectures), effectiveTTL)
+	if err != nil {
+		spanError(span, err)
+		writeQuotaError(c, err)
+		return
+	}
 
 	// Resolve --extra-repo workspace:path pairs into extra_repos custom defines
 	if len(req.ExtraRepos) > 0 {
@@ -1133,7 +1183,7 @@ func (a *APIServer) createBuild(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get kubernetes config"})
 			return
 		}
-		if err := a.resolveExtraRepos(ctx, k8sClient, restCfgForRepos, &req); err != nil {
+		if err := a.resolveExtraRepos(ctx, k8sClient, restCfgForRepos, namespace, req); err != nil {
 			spanError(span, err)
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
@@ -1146,14 +1196,14 @@ func (a *APIServer) createBuild(c *gin.Context) {
 
 	requestedBy := a.resolveRequester(c)
 
-	taskBundleRef, bundleStatus, bundleErr := resolveTaskBundleRef(ctx, k8sClient, namespace, &req)
+	taskBundleRef, bundleStatus, bundleErr := resolveTaskBundleRef(ctx, k8sClient, namespace, req)
 	if bundleErr != nil {
 		spanError(span, bundleErr)
 		c.JSON(bundleStatus, gin.H{"error": bundleErr.Error()})
 		return
 	}
 
-	if err := validateRestoreSourcesRef(&req); err != nil {
+	if err := validateRestoreSourcesRef(req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -1167,7 +1217,7 @@ func (a *APIServer) createBuild(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get kubernetes config"})
 			return
 		}
-		pvcName, wsErr := a.resolveWorkspaceForBuild(ctx, k8sClient, restCfg, namespace, req.Workspace, requestedBy, &req)
+		pvcName, wsErr := a.resolveWorkspaceForBuild(ctx, k8sClient, restCfg, namespace, req.Workspace, requestedBy, req)
 		if wsErr != nil {
 			spanError(span, wsErr)
 			c.JSON(http.StatusBadRequest, gin.H{"error": wsErr.Error()})
@@ -1197,12 +1247,25 @@ func (a *APIServer) createBuild(c *gin.Context) {
 		labels.Architecture: string(req.Architecture),
 	}
 
-	envSecretRef, pushSecretName, apiErr := a.resolveRegistryForBuild(ctx, c, k8sClient, namespace, &req)
+	envSecretRef, pushSecretName, apiErr := a.resolveRegistryForBuild(ctx, c, k8sClient, namespace, req)
 	if apiErr != nil {
 		spanError(span, apiErr)
 		return
 	}
 
+	exportSpec := buildExportSpec(req)
+	var exportSecretName string
+	if req.ExportLocation != nil {
+		var exportErr error
+		exportSecretName, exportErr = createStorageExportSecret(ctx, k8sClient, namespace, req.Name, req.ExportLocation.Credentials)
+		if exportErr != nil {
+			spanError(span, exportErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": exportErr.Error()})
+			return
+		}
+		exportSpec.Disk = storageDiskExport(req.ExportLocation, exportSecretName)
+	}
+
 	var flashSpec *automotivev1alpha1.FlashSpec
 	var flashSecretName string
 	if req.FlashEnabled {
@@ -1224,6 +1287,7 @@ func (a *APIServer) createBuild(c *gin.Context) {
 			LeaseName:             req.FlashLeaseName,
 			FlashCmd:              req.FlashCmd,
 			ExporterSelector:      req.FlashExporterSelector,
+			Test:                  deviceTestSpec(req.FlashTest),
 		}
 	}
 
@@ -1235,6 +1299,12 @@ func (a *APIServer) createBuild(c *gin.Context) {
 	if req.Reproducible && taskBundleRef != "" {
 		annotations[automotivev1alpha1.AnnotationTaskBundleRef] = taskBundleRef
 	}
+	if parentBuild != "" {
+		annotations[automotivev1alpha1.AnnotationParentBuild] = parentBuild
+	}
+	if req.Notify {
+		annotations[automotivev1alpha1.AnnotationNotify] = "true"
+	}
 
 	imageBuild := &automotivev1alpha1.ImageBuild{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1244,20 +1314,24 @@ func (a *APIServer) createBuild(c *gin.Context) {
 			Annotations: annotations,
 		},
 		Spec: automotivev1alpha1.ImageBuildSpec{
-			Architecture:      string(req.Architecture),
-			StorageClass:      req.StorageClass,
-			SecretRef:         envSecretRef,
-			PushSecretRef:     pushSecretName,
-			AIB:               buildAIBSpec(&req, req.Manifest, req.ManifestFileName, needsUpload),
-			Export:            buildExportSpec(&req),
-			Flash:             flashSpec,
-			BuildCachePVC:     buildCachePVCName,
-			Workspace:         req.Workspace,
-			SecureBuild:       req.SecureBuild,
-			Reproducible:      req.Reproducible,
-			TaskBundleRef:     taskBundleRef,
-			RestoreSourcesRef: req.RestoreSourcesRef,
-			TTL:               effectiveTTL,
+			Architecture:        string(req.Architecture),
+			Architectures:       architectureStrings(req.Architectures),
+			StorageClass:        req.StorageClass,
+			SecretRef:           envSecretRef,
+			PushSecretRef:       pushSecretName,
+			AIB:                 buildAIBSpec(req, req.Manifest, req.ManifestFileName, needsUpload),
+			Export:              exportSpec,
+			Flash:               flashSpec,
+			BuildCachePVC:       buildCachePVCName,
+			Workspace:           req.Workspace,
+			SecureBuild:         req.SecureBuild,
+			Reproducible:        req.Reproducible,
+			TaskBundleRef:       taskBundleRef,
+			RestoreSourcesRef:   req.RestoreSourcesRef,
+			TTL:                 effectiveTTL,
+			Priority:            req.Priority,
+			RetryPolicy:         retryPolicySpec(req.RetryPolicy),
+			SigningKeySecretRef: req.SigningKeySecretRef,
 		},
 	}
 	if err := k8sClient.Create(ctx, imageBuild); err != nil {
@@ -1287,6 +1361,16 @@ func (a *APIServer) createBuild(c *gin.Context) {
 		}
 	}
 
+	if exportSecretName != "" {
+		if err := setSecretOwnerRef(ctx, k8sClient, namespace, exportSecretName, imageBuild); err != nil {
+			log.Printf(
+				"WARNING: failed to set owner reference on export secret %s: %v "+
+					"(cleanup may require manual intervention)",
+				exportSecretName, err,
+			)
+		}
+	}
+
 	if flashSecretName != "" {
 		if err := setSecretOwnerRef(ctx, k8sClient, namespace, flashSecretName, imageBuild); err != nil {
 			log.Printf(
@@ -1303,11 +1387,11 @@ func (a *APIServer) createBuild(c *gin.Context) {
 		Message:     "Build triggered",
 		RequestedBy: requestedBy,
 		TraceID:     traceID,
+		ParentBuild: parentBuild,
 	})
 }
 
 func listBuilds(c *gin.Context) {
-	namespace := resolveNamespace()
 	limit, offset := parsePagination(c)
 
 	k8sClient, err := getK8sClientOrFail(c)
@@ -1317,7 +1401,7 @@ func listBuilds(c *gin.Context) {
 
 	ctx := c.Request.Context()
 	list := &automotivev1alpha1.ImageBuildList{}
-	if err := k8sClient.List(ctx, list, client.InNamespace(namespace)); err != nil {
+	if err := listInNamespaces(ctx, c, k8sClient, list); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error listing builds: %v", err)})
 		return
 	}
@@ -1331,7 +1415,7 @@ func listBuilds(c *gin.Context) {
 	page := applyPagination(list.Items, limit, offset)
 
 	// Resolve external route once for translating internal registry URLs
-	externalRoute, _ := getExternalRegistryRoute(ctx, k8sClient, namespace)
+	externalRoute, _ := getExternalRegistryRoute(ctx, k8sClient, resolveNamespace())
 
 	resp := make([]BuildListItem, 0, len(page))
 	for _, b := range page {
@@ -1346,7 +1430,9 @@ func listBuilds(c *gin.Context) {
 		var containerImage, diskImage string
 		if buildProducedArtifacts(&b) {
 			containerImage = b.Spec.GetContainerPush()
-			diskImage = b.Spec.GetExportOCI()
+			if !b.Spec.IsMultiArch() {
+				diskImage = b.Spec.GetExportOCI()
+			}
 		}
 		if b.Spec.GetUseServiceAccountAuth() && externalRoute != "" {
 			if containerImage != "" {
@@ -1373,7 +1459,7 @@ func listBuilds(c *gin.Context) {
 }
 
 func (a *APIServer) getBuild(c *gin.Context, name string) {
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 	k8sClient, err := getK8sClientOrFail(c)
 	if err != nil {
 		return
@@ -1390,16 +1476,25 @@ func (a *APIServer) getBuild(c *gin.Context, name string) {
 	var containerImage, diskImage string
 	if buildProducedArtifacts(build) {
 		containerImage = build.Spec.GetContainerPush()
-		diskImage = build.Spec.GetExportOCI()
+		// Multi-arch builds have no combined disk image; each architecture reports its own.
+		if !build.Spec.IsMultiArch() {
+			diskImage = build.Spec.GetExportOCI()
+		}
+	}
+	var diskLocation *ArtifactLocation
+	if build.Status.Phase == phaseCompleted {
+		diskLocation = diskLocationResponse(build)
 	}
 	var warning string
+	var externalRoute string
 
 	if build.Spec.GetUseServiceAccountAuth() {
-		externalRoute, err := getExternalRegistryRoute(ctx, k8sClient, namespace)
+		route, err := getExternalRegistryRoute(ctx, k8sClient, resolveNamespace())
 		if err != nil {
 			a.log.Error(err, "failed to resolve external registry route, returning internal URLs", "build", name)
 			warning = fmt.Sprintf("external registry route lookup failed: %v; returning internal URLs", err)
-		} else if externalRoute != "" {
+		} else if route != "" {
+			externalRoute = route
 			if containerImage != "" {
 				containerImage = translateToExternalURL(containerImage, externalRoute)
 			}
@@ -1414,7 +1509,7 @@ func (a *APIServer) getBuild(c *gin.Context, name string) {
 	var jumpstarterInfo *JumpstarterInfo
 	if isTerminalPhase(build.Status.Phase) {
 		operatorConfig := &automotivev1alpha1.OperatorConfig{}
-		if err := k8sClient.Get(ctx, types.NamespacedName{Name: "config", Namespace: namespace}, operatorConfig); err == nil {
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: "config", Namespace: resolveNamespace()}, operatorConfig); err == nil {
 			if operatorConfig.Status.JumpstarterAvailable {
 				jumpstarterInfo = &JumpstarterInfo{Available: true}
 				// Include lease ID if flash was executed
@@ -1447,15 +1542,13 @@ func (a *APIServer) getBuild(c *gin.Context, name string) {
 	}
 
 	// Mint a fresh registry token only for completed/failed internal registry builds
-	// that belong to the requesting user
+	// that belong to the requesting user, or for releasers
 	var registryToken string
-	requester := a.resolveRequester(c)
-	buildOwner := build.Annotations[labels.RequestedBy]
-	if requester == buildOwner &&
+	if a.canActOn(c, build.Annotations[labels.RequestedBy], automotivev1alpha1.BuildAPIRoleReleaser) &&
 		build.Spec.GetUseServiceAccountAuth() &&
 		isTerminalPhase(build.Status.Phase) {
 		var tokenErr error
-		tokenLifetime := resolveTokenLifetime(ctx, k8sClient, namespace)
+		tokenLifetime := resolveTokenLifetime(ctx, k8sClient, resolveNamespace())
 		registryToken, _, tokenErr = a.mintRegistryToken(ctx, c, namespace, tokenLifetime)
 		if tokenErr != nil {
 			a.log.Error(tokenErr, "failed to mint registry token", "build", name)
@@ -1496,9 +1589,22 @@ func (a *APIServer) getBuild(c *gin.Context, name string) {
 			}
 			return ""
 		}(),
-		Jumpstarter: jumpstarterInfo,
+		Jumpstarter:   jumpstarterInfo,
+		Architectures: architectureStatuses(build, externalRoute),
+		DiskLocation:  diskLocation,
+		QueuePosition: build.Status.QueuePosition,
+		Attempts:      buildAttempts(build),
+		ParentBuild:   build.Annotations[automotivev1alpha1.AnnotationParentBuild],
+		DeviceTests:   deviceTestResults(build.Status.DeviceTests),
+		NextRetryTime: func() string {
+			if build.Status.NextRetryTime != nil {
+				return build.Status.NextRetryTime.Format(time.RFC3339)
+			}
+			return ""
+		}(),
 		Parameters: &BuildParameters{
 			Architecture:           build.Spec.Architecture,
+			Architectures:          multiArchList(build),
 			Distro:                 build.Spec.GetDistro(),
 			Target:                 build.Spec.GetTarget(),
 			Mode:                   build.Spec.GetMode(),
@@ -1513,13 +1619,44 @@ func (a *APIServer) getBuild(c *gin.Context, name string) {
 			FlashLeaseDuration:     build.Spec.GetFlashLeaseDuration(),
 			FlashLeaseName:         build.Spec.GetFlashLeaseName(),
 			UseServiceAccountAuth:  build.Spec.GetUseServiceAccountAuth(),
+			Priority:               build.Spec.Priority,
+			MaxAttempts:            maxAttempts(build),
+			ContainerPush:          build.Spec.GetContainerPush(),
+			ExportOCI:              build.Spec.GetExportOCI(),
 		},
 	})
 }
 
+// buildAttempts converts the failed attempts recorded on an ImageBuild.
+func buildAttempts(build *automotivev1alpha1.ImageBuild) []BuildAttempt {
+	var attempts []BuildAttempt
+	for _, a := range build.Status.Attempts {
+		attempt := BuildAttempt{
+			Attempt:         a.Attempt,
+			Stage:           a.Stage,
+			PipelineRunName: a.PipelineRunName,
+			TaskRunName:     a.TaskRunName,
+			FailureClass:    a.FailureClass,
+			FailureReason:   a.FailureReason,
+		}
+		if a.CompletionTime != nil {
+			attempt.CompletionTime = a.CompletionTime.Format(time.RFC3339)
+		}
+		attempts = append(attempts, attempt)
+	}
+	return attempts
+}
+
+func maxAttempts(build *automotivev1alpha1.ImageBuild) int32 {
+	if build.Spec.RetryPolicy == nil {
+		return 0
+	}
+	return build.Spec.RetryPolicy.MaxAttempts
+}
+
 // getBuildTemplate returns a BuildRequest-like struct representing the inputs that produced a given build
 func getBuildTemplate(c *gin.Context, name string) {
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 	k8sClient, err := getK8sClientOrFail(c)
 	if err != nil {
 		return
@@ -1531,36 +1668,43 @@ func getBuildTemplate(c *gin.Context, name string) {
 		return
 	}
 
-	manifest := build.Spec.GetManifest()
+	writeJSON(c, http.StatusOK, BuildTemplateResponse{
+		BuildRequest: templateRequest(build),
+		SourceFiles:  extractManifestSourceFiles(build.Spec.GetManifest()),
+	})
+}
+
+// templateRequest reconstructs the BuildRequest inputs that produced a build.
+// Credentials and push destinations are not included.
+func templateRequest(build *automotivev1alpha1.ImageBuild) BuildRequest {
 	manifestFileName := build.Spec.GetManifestFileName()
 	if manifestFileName == "" {
 		manifestFileName = "manifest.aib.yml"
 	}
 
-	sourceFiles := extractManifestSourceFiles(manifest)
-
-	writeJSON(c, http.StatusOK, BuildTemplateResponse{
-		BuildRequest: BuildRequest{
-			Name:                   build.Name,
-			Manifest:               manifest,
-			ManifestFileName:       manifestFileName,
-			Distro:                 Distro(build.Spec.GetDistro()),
-			Target:                 Target(build.Spec.GetTarget()),
-			Architecture:           Architecture(build.Spec.Architecture),
-			ExportFormat:           ExportFormat(build.Spec.GetExportFormat()),
-			Mode:                   Mode(build.Spec.GetMode()),
-			AutomotiveImageBuilder: build.Spec.GetAIBImage(),
-			CustomDefs:             build.Spec.GetCustomDefs(),
-			AIBExtraArgs:           build.Spec.GetAIBExtraArgs(),
-			Compression:            Compression(build.Spec.GetCompression()),
-			SecureBuild:            build.Spec.SecureBuild,
-			Reproducible:           build.Spec.Reproducible,
-			TaskBundleRef:          build.Spec.TaskBundleRef,
-			RestoreSourcesRef:      build.Spec.RestoreSourcesRef,
-			TTL:                    build.Spec.GetTTL(),
-		},
-		SourceFiles: sourceFiles,
-	})
+	return BuildRequest{
+		Name:                   build.Name,
+		Manifest:               build.Spec.GetManifest(),
+		ManifestFileName:       manifestFileName,
+		Distro:                 Distro(build.Spec.GetDistro()),
+		Target:                 Target(build.Spec.GetTarget()),
+		Architecture:           Architecture(build.Spec.Architecture),
+		Architectures:          requestArchitectures(build),
+		ExportFormat:           ExportFormat(build.Spec.GetExportFormat()),
+		Mode:                   Mode(build.Spec.GetMode()),
+		AutomotiveImageBuilder: build.Spec.GetAIBImage(),
+		CustomDefs:             build.Spec.GetCustomDefs(),
+		AIBExtraArgs:           build.Spec.GetAIBExtraArgs(),
+		Compression:            Compression(build.Spec.GetCompression()),
+		SecureBuild:            build.Spec.SecureBuild,
+		Reproducible:           build.Spec.Reproducible,
+		TaskBundleRef:          build.Spec.TaskBundleRef,
+		RestoreSourcesRef:      build.Spec.RestoreSourcesRef,
+		TTL:                    build.Spec.GetTTL(),
+		Priority:               build.Spec.Priority,
+		RetryPolicy:            requestRetryPolicy(build.Spec.RetryPolicy),
+		SigningKeySecretRef:    build.Spec.SigningKeySecretRef,
+	}
 }
 
 func (a *APIServer) handleGetOperatorConfig(c *gin.Context) {
@@ -1614,5 +1758,16 @@ func (a *APIServer) handleGetOperatorConfig(c *gin.Context) {
 		response.TargetDefaults = targetDefaults
 	}
 
+	// Serve the public key matching the artifact signing key, for `caib image verify`
+	if osBuilds := operatorConfig.Spec.OSBuilds; osBuilds != nil && osBuilds.Signing != nil && osBuilds.Signing.PublicKeyRef != nil {
+		pubKeyPEM, err := bundleverify.FetchCosignPublicKey(ctx, k8sClient, osBuilds.Signing.PublicKeyRef, namespace)
+		if err != nil {
+			a.log.Error(err, "failed to load signing public key", "reqID", reqID, "namespace", namespace)
+			// Non-fatal: clients must then provide the key themselves
+		} else {
+			response.SigningPublicKey = string(pubKeyPEM)
+		}
+	}
+
 	c.JSON(http.StatusOK, response)
 }
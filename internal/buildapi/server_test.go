@@ -10,6 +10,7 @@ import (
 	"time"
 
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -92,6 +93,8 @@ var _ = Describe("APIServer", func() {
 			{"GET", "/v1/builds/test-build/template"},
 			{"POST", "/v1/builds/test-build/uploads"},
 			{"POST", "/v1/builds/test-build/cancel"},
+			{"POST", "/v1/builds/test-build/rebuild"},
+			{"GET", "/v1/builds/diff?from=a&to=b"},
 			{"DELETE", "/v1/builds/test-build"},
 		}
 
@@ -622,6 +625,53 @@ var _ = Describe("APIServer", func() {
 				ExtraArgs:    []string{"--separate-partitions"},
 			}))
 		})
+
+		It("should return the signing public key when signing is configured", func() {
+			config := &automotivev1alpha1.OperatorConfig{
+				Spec: automotivev1alpha1.OperatorConfigSpec{
+					OSBuilds: &automotivev1alpha1.OSBuildsConfig{
+						Signing: &automotivev1alpha1.ArtifactSigningConfig{
+							KeySecretRef: "cosign-signing-key",
+							PublicKeyRef: &corev1.ConfigMapKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "cosign-signing-pub"},
+								Key:                  "cosign.pub",
+							},
+						},
+					},
+				},
+			}
+			pubKey := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "cosign-signing-pub", Namespace: "default"},
+				Data:       map[string]string{"cosign.pub": "-----BEGIN PUBLIC KEY-----\ntest\n-----END PUBLIC KEY-----\n"},
+			}
+			scheme := runtime.NewScheme()
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pubKey).Build()
+
+			getClientFromRequestFn = func(_ *gin.Context) (ctrlclient.Client, error) {
+				return k8sClient, nil
+			}
+			loadOperatorConfigFn = func(_ context.Context, _ ctrlclient.Client, _ string) (*automotivev1alpha1.OperatorConfig, error) {
+				return config, nil
+			}
+			loadTargetDefaultsFn = func(_ context.Context, _ ctrlclient.Client, _ string) (map[string]TargetDefaults, error) {
+				return nil, nil
+			}
+
+			req, err := http.NewRequest(http.MethodGet, "/v1/config", nil)
+			Expect(err).NotTo(HaveOccurred())
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			c.Set("reqID", "test-req-id")
+
+			server.handleGetOperatorConfig(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response OperatorConfigResponse
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.SigningPublicKey).To(ContainSubstring("BEGIN PUBLIC KEY"))
+		})
 	})
 
 	Context("Server Lifecycle", func() {
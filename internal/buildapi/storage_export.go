@@ -0,0 +1,337 @@
+package buildapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/storage"
+)
+
+const (
+	diskReaderContainer = "reader"
+	diskReaderMountPath = "/data"
+	diskReaderTimeout   = 2 * time.Minute
+)
+
+// validateExportLocation checks an s3, http or pvc disk export destination.
+// It runs after applyBuildDefaults so the build mode is known.
+func validateExportLocation(req *BuildRequest) error {
+	loc := req.ExportLocation
+	if loc == nil {
+		return nil
+	}
+	loc.Type = strings.ToLower(strings.TrimSpace(loc.Type))
+	loc.URL = strings.TrimSpace(loc.URL)
+	loc.Endpoint = strings.TrimSpace(loc.Endpoint)
+
+	switch {
+	case req.ExportOCI != "":
+		return fmt.Errorf("exportLocation and exportOci are mutually exclusive")
+	case req.UseInternalRegistry:
+		return fmt.Errorf("useInternalRegistry cannot be used with exportLocation")
+	case req.Mode.IsBootc() && !req.BuildDiskImage:
+		return fmt.Errorf("exportLocation requires buildDiskImage in bootc mode")
+	}
+
+	switch loc.Type {
+	case automotivev1alpha1.LocationTypeS3:
+		if _, _, err := storage.ParseS3URL(loc.URL); err != nil {
+			return err
+		}
+		if !isHTTPURL(loc.Endpoint) {
+			return fmt.Errorf("s3 exports require an http:// or https:// endpoint")
+		}
+	case automotivev1alpha1.LocationTypeHTTP:
+		if !isHTTPURL(loc.URL) {
+			return fmt.Errorf("http exports require an http:// or https:// URL, got %q", loc.URL)
+		}
+	case automotivev1alpha1.LocationTypePVC:
+		if loc.ClaimName == "" {
+			return fmt.Errorf("pvc exports require claimName")
+		}
+		if err := validatePVCPath(loc.URL); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid exportLocation type %q: must be s3, http or pvc", loc.Type)
+	}
+	return nil
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && u.Host != "" && (u.Scheme == "http" || u.Scheme == "https")
+}
+
+// validatePVCPath requires a relative file path that stays inside the claim.
+func validatePVCPath(p string) error {
+	if p == "" || strings.HasPrefix(p, "/") || path.Clean(p) != p || p == "." ||
+		strings.HasPrefix(p, "../") || p == ".." {
+		return fmt.Errorf("invalid pvc path %q: must be a clean path relative to the claim root", p)
+	}
+	return nil
+}
+
+// createStorageExportSecret stores inline export credentials in a build-owned
+// secret using the keys read by the push task and the Image controller.
+func createStorageExportSecret(
+	ctx context.Context, k8sClient client.Client, namespace, buildName string, creds *StorageCredentials,
+) (string, error) {
+	if creds == nil {
+		return "", nil
+	}
+	data := map[string][]byte{}
+	for key, value := range map[string]string{
+		storage.SecretKeyAccessKeyID:     creds.AccessKeyID,
+		storage.SecretKeySecretAccessKey: creds.SecretAccessKey,
+		storage.SecretKeyToken:           creds.Token,
+		storage.SecretKeyUsername:        creds.Username,
+		storage.SecretKeyPassword:        creds.Password,
+	} {
+		if value != "" {
+			data[key] = []byte(value)
+		}
+	}
+	if len(data) == 0 {
+		return "", nil
+	}
+
+	secretName := fmt.Sprintf("%s-export-auth", buildName)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				labels.ManagedBy:    labels.ValueBuildAPI,
+				labels.PartOf:       labels.ValueAutomotiveDev,
+				labels.CreatedBy:    labels.ValueBuildAPICreator,
+				labels.ResourceType: "export-auth",
+				labels.BuildName:    buildName,
+				labels.Transient:    labels.ValueTrue,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+	if err := k8sClient.Create(ctx, secret); err != nil {
+		return "", fmt.Errorf("failed to create export secret: %w", err)
+	}
+	return secretName, nil
+}
+
+// storageDiskExport maps a validated export location onto the ImageBuild disk export.
+func storageDiskExport(loc *ArtifactLocation, secretName string) *automotivev1alpha1.DiskExport {
+	if secretName == "" {
+		secretName = loc.SecretRef
+	}
+	switch loc.Type {
+	case automotivev1alpha1.LocationTypeS3:
+		bucket, key, _ := storage.ParseS3URL(loc.URL)
+		return &automotivev1alpha1.DiskExport{S3: &automotivev1alpha1.S3Location{
+			Endpoint:  loc.Endpoint,
+			Region:    loc.Region,
+			Bucket:    bucket,
+			Key:       key,
+			SecretRef: secretName,
+		}}
+	case automotivev1alpha1.LocationTypeHTTP:
+		return &automotivev1alpha1.DiskExport{HTTP: &automotivev1alpha1.HTTPLocation{
+			URL:       loc.URL,
+			SecretRef: secretName,
+		}}
+	case automotivev1alpha1.LocationTypePVC:
+		return &automotivev1alpha1.DiskExport{PVC: &automotivev1alpha1.PVCLocation{
+			ClaimName: loc.ClaimName,
+			Path:      loc.URL,
+		}}
+	}
+	return nil
+}
+
+// diskLocationResponse reports where a build exported its disk image outside
+// of a registry. Credentials are never included.
+func diskLocationResponse(build *automotivev1alpha1.ImageBuild) *ArtifactLocation {
+	export := build.Spec.GetDiskStorageExport()
+	if export == nil {
+		return nil
+	}
+	switch {
+	case export.S3 != nil:
+		return &ArtifactLocation{
+			Type:      automotivev1alpha1.LocationTypeS3,
+			URL:       "s3://" + export.S3.Bucket + "/" + export.S3.Key,
+			Endpoint:  export.S3.Endpoint,
+			Region:    export.S3.GetRegion(),
+			SecretRef: export.S3.SecretRef,
+		}
+	case export.HTTP != nil:
+		return &ArtifactLocation{
+			Type:      automotivev1alpha1.LocationTypeHTTP,
+			URL:       export.HTTP.URL,
+			SecretRef: export.HTTP.SecretRef,
+		}
+	case export.PVC != nil:
+		return &ArtifactLocation{
+			Type:      automotivev1alpha1.LocationTypePVC,
+			URL:       export.PVC.Path,
+			ClaimName: export.PVC.ClaimName,
+		}
+	}
+	return nil
+}
+
+var waitForDiskReaderPodFn = waitForPodRunning
+
+// downloadDisk streams a disk image exported to a PVC. The claim is mounted
+// read-only into a short-lived reader pod and the file is copied out with cat.
+func (a *APIServer) downloadDisk(c *gin.Context, name string) {
+	namespace := requestNamespace(c)
+	k8sClient, err := getK8sClientOrFail(c)
+	if err != nil {
+		return
+	}
+	ctx := c.Request.Context()
+
+	build := &automotivev1alpha1.ImageBuild{}
+	if err := getResourceOrFail(ctx, c, k8sClient, name, namespace, build, "build"); err != nil {
+		return
+	}
+	if build.Status.Phase != phaseCompleted {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("build %s is not completed (phase: %s)", name, build.Status.Phase)})
+		return
+	}
+	export := build.Spec.GetDiskStorageExport()
+	if export == nil || export.PVC == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("build %s did not export its disk image to a PVC", name)})
+		return
+	}
+	restCfg, err := getRESTConfigFromRequestFn(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get kubernetes config"})
+		return
+	}
+
+	pod := diskReaderPod(build, export.PVC.ClaimName)
+	if err := k8sClient.Create(ctx, pod); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error creating disk reader pod: %v", err)})
+		return
+	}
+	defer func() {
+		// Use a fresh context: the request context is canceled once the client disconnects
+		if err := k8sClient.Delete(context.Background(), pod); err != nil && !k8serrors.IsNotFound(err) {
+			a.log.Error(err, "failed to delete disk reader pod", "pod", pod.Name)
+		}
+	}()
+
+	waitCtx, cancel := context.WithTimeout(ctx, diskReaderTimeout)
+	defer cancel()
+	if err := waitForDiskReaderPodFn(waitCtx, k8sClient, namespace, pod.Name); err != nil {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": fmt.Sprintf("disk reader pod did not start: %v", err)})
+		return
+	}
+
+	filePath := path.Join(diskReaderMountPath, export.PVC.Path)
+	cmd := []string{"cat", "--", filePath}
+	executor, err := newPodExecExecutorFn(restCfg, namespace, pod.Name, diskReaderContainer, cmd)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error reading disk image: %v", err)})
+		return
+	}
+
+	c.Header("Content-Type", "application/octet-stream")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(export.PVC.Path)))
+	c.Status(http.StatusOK)
+	var stderr strings.Builder
+	streamOpts := remotecommand.StreamOptions{
+		Stdin:  strings.NewReader(""),
+		Stdout: newFlushWriter(c.Writer),
+		Stderr: &stderr,
+	}
+	if err := executor.StreamWithContext(ctx, streamOpts); err != nil {
+		// Headers are already sent; abort the connection so the client sees a truncated transfer
+		a.log.Error(err, "disk download failed", "build", name, "stderr", stderr.String())
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func diskReaderPod(build *automotivev1alpha1.ImageBuild, claimName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-disk-reader-%s", build.Name, uuid.New().String()[:5]),
+			Namespace: build.Namespace,
+			Labels: map[string]string{
+				labels.ManagedBy:    labels.ValueBuildAPI,
+				labels.PartOf:       labels.ValueAutomotiveDev,
+				labels.ResourceType: "disk-reader",
+				labels.BuildName:    build.Name,
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsUser:    ptr.To[int64](1000),
+				RunAsGroup:   ptr.To[int64](1000),
+				RunAsNonRoot: ptr.To(true),
+			},
+			Containers: []corev1.Container{{
+				Name:    diskReaderContainer,
+				Image:   "registry.access.redhat.com/ubi10-minimal:latest",
+				Command: []string{"sleep", "infinity"},
+				VolumeMounts: []corev1.VolumeMount{{
+					Name:      "export",
+					MountPath: diskReaderMountPath,
+					ReadOnly:  true,
+				}},
+			}},
+			Volumes: []corev1.Volume{{
+				Name: "export",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: claimName,
+						ReadOnly:  true,
+					},
+				},
+			}},
+		},
+	}
+}
+
+// waitForPodRunning polls until the pod is running or ctx expires.
+func waitForPodRunning(ctx context.Context, k8sClient client.Client, namespace, name string) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		pod := &corev1.Pod{}
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, pod); err != nil {
+			return err
+		}
+		switch pod.Status.Phase {
+		case corev1.PodRunning:
+			return nil
+		case corev1.PodFailed, corev1.PodSucceeded:
+			return fmt.Errorf("pod %s exited (phase %s)", name, pod.Status.Phase)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
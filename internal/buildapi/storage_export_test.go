@@ -0,0 +1,231 @@
+package buildapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+var _ = Describe("validateExportLocation", func() {
+	newRequest := func(loc *ArtifactLocation) *BuildRequest {
+		return &BuildRequest{Mode: ModeImage, ExportLocation: loc}
+	}
+
+	It("accepts s3, http and pvc destinations", func() {
+		Expect(validateExportLocation(newRequest(&ArtifactLocation{
+			Type: "S3", URL: "s3://images/disk.qcow2", Endpoint: "http://minio:9000",
+		}))).To(Succeed())
+		Expect(validateExportLocation(newRequest(&ArtifactLocation{
+			Type: "http", URL: "https://files.example.com/disk.raw",
+		}))).To(Succeed())
+		Expect(validateExportLocation(newRequest(&ArtifactLocation{
+			Type: "pvc", ClaimName: "images", URL: "builds/disk.raw",
+		}))).To(Succeed())
+	})
+
+	It("normalizes the location type", func() {
+		req := newRequest(&ArtifactLocation{Type: " S3 ", URL: "s3://images/disk.qcow2", Endpoint: "http://minio:9000"})
+		Expect(validateExportLocation(req)).To(Succeed())
+		Expect(req.ExportLocation.Type).To(Equal(automotivev1alpha1.LocationTypeS3))
+	})
+
+	It("rejects invalid destinations", func() {
+		for _, loc := range []*ArtifactLocation{
+			{Type: "ftp", URL: "ftp://host/disk.raw"},
+			{Type: "s3", URL: "images/disk.qcow2", Endpoint: "http://minio:9000"},
+			{Type: "s3", URL: "s3://images/disk.qcow2"},
+			{Type: "http", URL: "files.example.com/disk.raw"},
+			{Type: "pvc", URL: "disk.raw"},
+			{Type: "pvc", ClaimName: "images", URL: "../disk.raw"},
+			{Type: "pvc", ClaimName: "images", URL: "/disk.raw"},
+		} {
+			Expect(validateExportLocation(newRequest(loc))).NotTo(Succeed(), "location %+v", loc)
+		}
+	})
+
+	It("rejects combining a storage export with registry exports", func() {
+		loc := &ArtifactLocation{Type: "http", URL: "https://files.example.com/disk.raw"}
+
+		req := newRequest(loc)
+		req.ExportOCI = "quay.io/org/disk:v1"
+		Expect(validateExportLocation(req)).To(MatchError(ContainSubstring("mutually exclusive")))
+
+		req = newRequest(loc)
+		req.UseInternalRegistry = true
+		Expect(validateExportLocation(req)).To(MatchError(ContainSubstring("useInternalRegistry")))
+	})
+
+	It("requires a disk image in bootc mode", func() {
+		req := &BuildRequest{
+			Mode:           ModeBootc,
+			ExportLocation: &ArtifactLocation{Type: "http", URL: "https://files.example.com/disk.raw"},
+		}
+		Expect(validateExportLocation(req)).To(MatchError(ContainSubstring("buildDiskImage")))
+		req.BuildDiskImage = true
+		Expect(validateExportLocation(req)).To(Succeed())
+	})
+})
+
+var _ = Describe("storageDiskExport", func() {
+	It("splits s3 URLs and prefers the build-owned secret", func() {
+		export := storageDiskExport(&ArtifactLocation{
+			Type:      automotivev1alpha1.LocationTypeS3,
+			URL:       "s3://images/rhivos/disk.qcow2",
+			Endpoint:  "http://minio:9000",
+			SecretRef: "existing",
+		}, "build-export-auth")
+		Expect(export.S3).To(Equal(&automotivev1alpha1.S3Location{
+			Endpoint:  "http://minio:9000",
+			Bucket:    "images",
+			Key:       "rhivos/disk.qcow2",
+			SecretRef: "build-export-auth",
+		}))
+	})
+
+	It("round-trips through diskLocationResponse without credentials", func() {
+		loc := &ArtifactLocation{
+			Type:        automotivev1alpha1.LocationTypeHTTP,
+			URL:         "https://files.example.com/disk.raw",
+			SecretRef:   "upload-token",
+			Credentials: &StorageCredentials{Token: "secret"},
+		}
+		build := &automotivev1alpha1.ImageBuild{
+			Spec: automotivev1alpha1.ImageBuildSpec{
+				Export: &automotivev1alpha1.ExportSpec{Disk: storageDiskExport(loc, "")},
+			},
+		}
+		Expect(diskLocationResponse(build)).To(Equal(&ArtifactLocation{
+			Type:      automotivev1alpha1.LocationTypeHTTP,
+			URL:       "https://files.example.com/disk.raw",
+			SecretRef: "upload-token",
+		}))
+	})
+})
+
+var _ = Describe("downloadDisk", func() {
+	var (
+		server                             *APIServer
+		originalGetClientFromRequestFn     func(*gin.Context) (ctrlclient.Client, error)
+		originalGetRESTConfigFromRequestFn func(*gin.Context) (*rest.Config, error)
+		originalNewPodExecExecutorFn       func(*rest.Config, string, string, string, []string) (remotecommand.Executor, error)
+		originalWaitForDiskReaderPodFn     func(context.Context, ctrlclient.Client, string, string) error
+		originalNamespace                  string
+		hasOriginalNamespace               bool
+		fakeClient                         ctrlclient.Client
+		execCmd                            []string
+	)
+
+	newBuild := func(phase string, disk *automotivev1alpha1.DiskExport) *automotivev1alpha1.ImageBuild {
+		return &automotivev1alpha1.ImageBuild{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-build", Namespace: "test-ns"},
+			Spec: automotivev1alpha1.ImageBuildSpec{
+				Export: &automotivev1alpha1.ExportSpec{Disk: disk},
+			},
+			Status: automotivev1alpha1.ImageBuildStatus{Phase: phase},
+		}
+	}
+
+	useClient := func(objs ...ctrlclient.Object) {
+		scheme := runtime.NewScheme()
+		Expect(automotivev1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	}
+
+	download := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/v1/builds/my-build/disk", nil)
+		server.downloadDisk(c, "my-build")
+		return w
+	}
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		server = NewAPIServer(":0", logr.Discard())
+		originalGetClientFromRequestFn = getClientFromRequestFn
+		originalGetRESTConfigFromRequestFn = getRESTConfigFromRequestFn
+		originalNewPodExecExecutorFn = newPodExecExecutorFn
+		originalWaitForDiskReaderPodFn = waitForDiskReaderPodFn
+		originalNamespace, hasOriginalNamespace = os.LookupEnv("BUILD_API_NAMESPACE")
+		Expect(os.Setenv("BUILD_API_NAMESPACE", "test-ns")).To(Succeed())
+
+		getClientFromRequestFn = func(_ *gin.Context) (ctrlclient.Client, error) {
+			return fakeClient, nil
+		}
+		getRESTConfigFromRequestFn = func(_ *gin.Context) (*rest.Config, error) {
+			return &rest.Config{}, nil
+		}
+		waitForDiskReaderPodFn = func(context.Context, ctrlclient.Client, string, string) error {
+			return nil
+		}
+		execCmd = nil
+		newPodExecExecutorFn = func(_ *rest.Config, _, _, _ string, cmd []string) (remotecommand.Executor, error) {
+			execCmd = cmd
+			return &fakeRemoteExecutor{
+				streamWithContextFn: func(_ context.Context, opts remotecommand.StreamOptions) error {
+					_, err := io.WriteString(opts.Stdout, "disk-bytes")
+					return err
+				},
+			}, nil
+		}
+	})
+
+	AfterEach(func() {
+		getClientFromRequestFn = originalGetClientFromRequestFn
+		getRESTConfigFromRequestFn = originalGetRESTConfigFromRequestFn
+		newPodExecExecutorFn = originalNewPodExecExecutorFn
+		waitForDiskReaderPodFn = originalWaitForDiskReaderPodFn
+		if hasOriginalNamespace {
+			Expect(os.Setenv("BUILD_API_NAMESPACE", originalNamespace)).To(Succeed())
+		} else {
+			Expect(os.Unsetenv("BUILD_API_NAMESPACE")).To(Succeed())
+		}
+	})
+
+	It("streams the file through a reader pod and removes the pod", func() {
+		useClient(newBuild(phaseCompleted, &automotivev1alpha1.DiskExport{
+			PVC: &automotivev1alpha1.PVCLocation{ClaimName: "images", Path: "builds/disk.raw"},
+		}))
+
+		w := download()
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Body.String()).To(Equal("disk-bytes"))
+		Expect(w.Header().Get("Content-Disposition")).To(ContainSubstring(`"disk.raw"`))
+		Expect(execCmd).To(Equal([]string{"cat", "--", "/data/builds/disk.raw"}))
+
+		pods := &corev1.PodList{}
+		Expect(fakeClient.List(context.Background(), pods)).To(Succeed())
+		Expect(pods.Items).To(BeEmpty())
+	})
+
+	It("rejects builds that did not export to a PVC", func() {
+		useClient(newBuild(phaseCompleted, &automotivev1alpha1.DiskExport{OCI: "quay.io/org/disk:v1"}))
+		Expect(download().Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("rejects builds that are not completed", func() {
+		useClient(newBuild(phaseBuilding, &automotivev1alpha1.DiskExport{
+			PVC: &automotivev1alpha1.PVCLocation{ClaimName: "images", Path: "disk.raw"},
+		}))
+		Expect(download().Code).To(Equal(http.StatusConflict))
+	})
+})
@@ -0,0 +1,162 @@
+package buildapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+// ProjectHeader selects the project, or tenant namespace, of a request when
+// tenancy is enabled in the OperatorConfig.
+const ProjectHeader = "X-Caib-Project"
+
+// namedResources maps the routes of named resources to the objects they
+// serve, so that requests without the project header find them in any of the
+// caller's tenants.
+var namedResources = map[string]func() client.Object{
+	"/v1/builds/":               func() client.Object { return &automotivev1alpha1.ImageBuild{} },
+	"/v1/flash/":                func() client.Object { return &automotivev1alpha1.FlashJob{} },
+	"/v1/container-builds/":     func() client.Object { return &automotivev1alpha1.ContainerBuild{} },
+	"/v1/prepare-reseals/":      func() client.Object { return &automotivev1alpha1.ImageReseal{} },
+	"/v1/reseals/":              func() client.Object { return &automotivev1alpha1.ImageReseal{} },
+	"/v1/extract-for-signings/": func() client.Object { return &automotivev1alpha1.ImageReseal{} },
+	"/v1/inject-signeds/":       func() client.Object { return &automotivev1alpha1.ImageReseal{} },
+	"/v1/workspaces/":           func() client.Object { return &automotivev1alpha1.Workspace{} },
+	"/v1/workspace-snapshots/":  func() client.Object { return &automotivev1alpha1.WorkspaceSnapshot{} },
+}
+
+// resolveTenant routes a request to the tenant namespaces of its caller when
+// tenancy is enabled. The caller's tenants are those whose users or groups
+// include the caller. With the project header, the request is limited to that
+// tenant; without it, new resources go to the caller's first tenant, named
+// resources are looked up in all of them and listings cover all of them.
+// Callers outside any tenant are rejected.
+func (a *APIServer) resolveTenant(c *gin.Context) bool {
+	a.authConfigMu.RLock()
+	tenancy := a.tenancy
+	a.authConfigMu.RUnlock()
+	if !tenancy.IsEnabled() {
+		return true
+	}
+
+	namespaces := tenancy.NamespacesFor(c.GetString("requester"), c.GetStringSlice("groups"))
+	if project := strings.TrimSpace(c.GetHeader(ProjectHeader)); project != "" {
+		if !slices.Contains(namespaces, project) {
+			forbidden(c, reasonNotProjectMember, fmt.Sprintf("not a member of project %q", project))
+			return false
+		}
+		namespaces = []string{project}
+	}
+	if len(namespaces) == 0 {
+		forbidden(c, reasonNotProjectMember, "not a member of any project")
+		return false
+	}
+
+	namespace := namespaces[0]
+	if len(namespaces) > 1 {
+		var ok bool
+		if namespace, ok = a.locateNamedResource(c, namespaces); !ok {
+			return false
+		}
+	}
+	c.Set("namespace", namespace)
+	c.Set("namespaces", namespaces)
+	return true
+}
+
+// locateNamedResource returns the namespace of the resource named by the
+// request, or the first namespace when the request names no resource or none
+// of the namespaces holds it. A name held by several namespaces is ambiguous
+// and rejected with 409 Conflict.
+func (a *APIServer) locateNamedResource(c *gin.Context, namespaces []string) (string, bool) {
+	name := c.Param("name")
+	if name == "" {
+		return namespaces[0], true
+	}
+	var newObject func() client.Object
+	for prefix, fn := range namedResources {
+		if strings.HasPrefix(c.Request.URL.Path, prefix) {
+			newObject = fn
+			break
+		}
+	}
+	if newObject == nil {
+		return namespaces[0], true
+	}
+	k8sClient, err := getClientFromRequestFn(c)
+	if err != nil {
+		return namespaces[0], true
+	}
+
+	var found []string
+	for _, namespace := range namespaces {
+		err := k8sClient.Get(c.Request.Context(), client.ObjectKey{Namespace: namespace, Name: name}, newObject())
+		switch {
+		case err == nil:
+			found = append(found, namespace)
+		case !k8serrors.IsNotFound(err):
+			a.log.Error(err, "failed to look up resource in tenant", "name", name, "namespace", namespace)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return namespaces[0], true
+	case 1:
+		return found[0], true
+	}
+	c.JSON(http.StatusConflict, gin.H{
+		"error": fmt.Sprintf("%q exists in projects %s, select one with the %s header",
+			name, strings.Join(found, ", "), ProjectHeader),
+	})
+	c.Abort()
+	return "", false
+}
+
+// requestNamespace returns the namespace a request works in: its tenant
+// namespace, or the Build API's namespace when tenancy is disabled.
+func requestNamespace(c *gin.Context) string {
+	if namespace := c.GetString("namespace"); namespace != "" {
+		return namespace
+	}
+	return resolveNamespace()
+}
+
+// requestNamespaces returns the namespaces a listing of the request covers.
+func requestNamespaces(c *gin.Context) []string {
+	if namespaces := c.GetStringSlice("namespaces"); len(namespaces) > 0 {
+		return namespaces
+	}
+	return []string{requestNamespace(c)}
+}
+
+// listInNamespaces lists objects into list from every namespace of the request.
+func listInNamespaces(ctx context.Context, c *gin.Context, k8sClient client.Client, list client.ObjectList, opts ...client.ListOption) error {
+	namespaces := requestNamespaces(c)
+	if len(namespaces) == 1 {
+		return k8sClient.List(ctx, list, append(slices.Clone(opts), client.InNamespace(namespaces[0]))...)
+	}
+
+	var items []runtime.Object
+	for _, namespace := range namespaces {
+		part := list.DeepCopyObject().(client.ObjectList)
+		if err := k8sClient.List(ctx, part, append(slices.Clone(opts), client.InNamespace(namespace))...); err != nil {
+			return err
+		}
+		objs, err := meta.ExtractList(part)
+		if err != nil {
+			return err
+		}
+		items = append(items, objs...)
+	}
+	return meta.SetList(list, items)
+}
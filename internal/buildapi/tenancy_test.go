@@ -0,0 +1,168 @@
+package buildapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/gin-gonic/gin"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+)
+
+var _ = Describe("Tenancy", func() {
+	var (
+		server               *APIServer
+		originalNamespace    string
+		hasOriginalNamespace bool
+	)
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		server = NewAPIServer(":0", logr.Discard())
+		originalNamespace, hasOriginalNamespace = os.LookupEnv("BUILD_API_NAMESPACE")
+		Expect(os.Setenv("BUILD_API_NAMESPACE", "home-ns")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		if hasOriginalNamespace {
+			Expect(os.Setenv("BUILD_API_NAMESPACE", originalNamespace)).To(Succeed())
+		} else {
+			Expect(os.Unsetenv("BUILD_API_NAMESPACE")).To(Succeed())
+		}
+	})
+
+	resolve := func(user string, groups []string, project string) (*httptest.ResponseRecorder, *gin.Context, bool) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/v1/builds", nil)
+		if project != "" {
+			c.Request.Header.Set(ProjectHeader, project)
+		}
+		c.Set("requester", user)
+		c.Set("groups", groups)
+		return w, c, server.resolveTenant(c)
+	}
+
+	It("should use the Build API namespace when tenancy is disabled", func() {
+		_, c, ok := resolve("alice", nil, "")
+		Expect(ok).To(BeTrue())
+		Expect(requestNamespace(c)).To(Equal("home-ns"))
+		Expect(requestNamespaces(c)).To(Equal([]string{"home-ns"}))
+	})
+
+	Context("with tenants", func() {
+		BeforeEach(func() {
+			server.tenancy = &automotivev1alpha1.TenancyConfig{Tenants: []automotivev1alpha1.TenantConfig{
+				{Namespace: "team-a", Groups: []string{"team-a-devs"}},
+				{Namespace: "team-b", Users: []string{"alice"}},
+			}}
+		})
+
+		It("should route to the caller's tenants", func() {
+			_, c, ok := resolve("alice", []string{"team-a-devs"}, "")
+			Expect(ok).To(BeTrue())
+			Expect(requestNamespace(c)).To(Equal("team-a"))
+			Expect(requestNamespaces(c)).To(Equal([]string{"team-a", "team-b"}))
+		})
+
+		It("should limit the request to the selected project", func() {
+			_, c, ok := resolve("alice", []string{"team-a-devs"}, "team-b")
+			Expect(ok).To(BeTrue())
+			Expect(requestNamespace(c)).To(Equal("team-b"))
+			Expect(requestNamespaces(c)).To(Equal([]string{"team-b"}))
+		})
+
+		It("should reject projects the caller is not a member of", func() {
+			w, _, ok := resolve("bob", []string{"team-a-devs"}, "team-b")
+			Expect(ok).To(BeFalse())
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+
+			w, _, ok = resolve("bob", []string{"other"}, "")
+			Expect(ok).To(BeFalse())
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+
+		Describe("named resources", func() {
+			var originalGetClientFromRequestFn func(*gin.Context) (ctrlclient.Client, error)
+
+			BeforeEach(func() {
+				scheme := runtime.NewScheme()
+				Expect(automotivev1alpha1.AddToScheme(scheme)).To(Succeed())
+				k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+					&automotivev1alpha1.ImageBuild{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "team-b"}},
+					&automotivev1alpha1.ImageBuild{ObjectMeta: metav1.ObjectMeta{Name: "both", Namespace: "team-a"}},
+					&automotivev1alpha1.ImageBuild{ObjectMeta: metav1.ObjectMeta{Name: "both", Namespace: "team-b"}},
+				).Build()
+				originalGetClientFromRequestFn = getClientFromRequestFn
+				getClientFromRequestFn = func(*gin.Context) (ctrlclient.Client, error) { return k8sClient, nil }
+			})
+
+			AfterEach(func() {
+				getClientFromRequestFn = originalGetClientFromRequestFn
+			})
+
+			resolveNamed := func(name, project string) (*httptest.ResponseRecorder, *gin.Context, bool) {
+				w := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(w)
+				c.Request, _ = http.NewRequest(http.MethodGet, "/v1/builds/"+name, nil)
+				if project != "" {
+					c.Request.Header.Set(ProjectHeader, project)
+				}
+				c.Params = gin.Params{{Key: "name", Value: name}}
+				c.Set("requester", "alice")
+				c.Set("groups", []string{"team-a-devs"})
+				return w, c, server.resolveTenant(c)
+			}
+
+			It("should find them in any of the caller's tenants", func() {
+				_, c, ok := resolveNamed("b", "")
+				Expect(ok).To(BeTrue())
+				Expect(requestNamespace(c)).To(Equal("team-b"))
+
+				_, c, ok = resolveNamed("missing", "")
+				Expect(ok).To(BeTrue())
+				Expect(requestNamespace(c)).To(Equal("team-a"))
+			})
+
+			It("should reject ambiguous names without the project header", func() {
+				w, _, ok := resolveNamed("both", "")
+				Expect(ok).To(BeFalse())
+				Expect(w.Code).To(Equal(http.StatusConflict))
+				Expect(w.Body.String()).To(ContainSubstring(ProjectHeader))
+
+				_, c, ok := resolveNamed("both", "team-b")
+				Expect(ok).To(BeTrue())
+				Expect(requestNamespace(c)).To(Equal("team-b"))
+			})
+		})
+
+		It("should list across the caller's tenants", func() {
+			scheme := runtime.NewScheme()
+			Expect(automotivev1alpha1.AddToScheme(scheme)).To(Succeed())
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&automotivev1alpha1.ImageBuild{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "team-a"}},
+				&automotivev1alpha1.ImageBuild{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "team-b"}},
+				&automotivev1alpha1.ImageBuild{ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "team-c"}},
+			).Build()
+
+			_, c, ok := resolve("alice", []string{"team-a-devs"}, "")
+			Expect(ok).To(BeTrue())
+			list := &automotivev1alpha1.ImageBuildList{}
+			Expect(listInNamespaces(context.Background(), c, k8sClient, list)).To(Succeed())
+			var names []string
+			for _, build := range list.Items {
+				names = append(names, build.Namespace+"/"+build.Name)
+			}
+			Expect(names).To(ConsistOf("team-a/a", "team-b/b"))
+		})
+	})
+})
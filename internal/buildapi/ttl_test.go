@@ -136,3 +136,45 @@ var _ = Describe("resolveAndClampTTL", func() {
 		Expect(result).To(Equal("48h"))
 	})
 })
+
+var _ = Describe("validatePriority", func() {
+	var origFn func(context.Context, ctrlclient.Client, string) (*automotivev1alpha1.OperatorConfig, error)
+
+	BeforeEach(func() {
+		origFn = loadOperatorConfigFn
+		loadOperatorConfigFn = func(_ context.Context, _ ctrlclient.Client, _ string) (*automotivev1alpha1.OperatorConfig, error) {
+			return &automotivev1alpha1.OperatorConfig{
+				Spec: automotivev1alpha1.OperatorConfigSpec{
+					OSBuilds: &automotivev1alpha1.OSBuildsConfig{
+						PriorityClasses: []automotivev1alpha1.BuildPriorityClass{
+							{Name: "release", Value: 1000},
+							{Name: "normal", Value: 100, Default: true},
+						},
+					},
+				},
+			}, nil
+		}
+	})
+
+	AfterEach(func() {
+		loadOperatorConfigFn = origFn
+	})
+
+	It("accepts an empty or configured priority", func() {
+		Expect(validatePriority(context.Background(), nil, "test-ns", "")).To(Succeed())
+		Expect(validatePriority(context.Background(), nil, "test-ns", "release")).To(Succeed())
+	})
+
+	It("rejects unknown priorities and lists the configured classes", func() {
+		err := validatePriority(context.Background(), nil, "test-ns", "urgent")
+		Expect(err).To(MatchError(ContainSubstring("must be one of release, normal")))
+	})
+
+	It("rejects priorities when no classes are configured", func() {
+		loadOperatorConfigFn = func(_ context.Context, _ ctrlclient.Client, _ string) (*automotivev1alpha1.OperatorConfig, error) {
+			return nil, nil
+		}
+		err := validatePriority(context.Background(), nil, "test-ns", "release")
+		Expect(err).To(MatchError(ContainSubstring("no priority classes are configured")))
+	})
+})
@@ -2,7 +2,10 @@ package buildapi
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Distro represents the OS distribution to build (e.g., cs9, autosd10-sig).
@@ -160,6 +163,7 @@ type BuildRequest struct {
 	Distro                 Distro               `json:"distro"`
 	Target                 Target               `json:"target"`
 	Architecture           Architecture         `json:"architecture"`
+	Architectures          []Architecture       `json:"architectures,omitempty"` // Multi-arch build: one PipelineRun per entry, overrides Architecture
 	ExportFormat           ExportFormat         `json:"exportFormat"`
 	Mode                   Mode                 `json:"mode"`
 	AutomotiveImageBuilder string               `json:"automotiveImageBuilder"`
@@ -179,6 +183,10 @@ type BuildRequest struct {
 	RebuildBuilder bool   `json:"rebuildBuilder,omitempty"` // Force rebuild of bootc builder image
 	HasLocalFiles  bool   `json:"hasLocalFiles,omitempty"`  // Client has local files to upload (source_path/source_glob)
 
+	// ExportLocation exports the disk image to S3-compatible storage, an HTTP endpoint or a PVC
+	// instead of a registry (mutually exclusive with ExportOCI)
+	ExportLocation *ArtifactLocation `json:"exportLocation,omitempty"`
+
 	// Internal registry push configuration
 	UseInternalRegistry       bool   `json:"useInternalRegistry,omitempty"`       // Push to OpenShift internal registry
 	InternalRegistryImageName string `json:"internalRegistryImageName,omitempty"` // Override image name (default: build name)
@@ -200,6 +208,20 @@ type BuildRequest struct {
 	// TTL is the time-to-live for the build. Empty uses server default, "0" disables expiry.
 	TTL string `json:"ttl,omitempty"`
 
+	// Priority names a build priority class from OperatorConfig. Empty uses the default class.
+	Priority string `json:"priority,omitempty"`
+
+	// RetryPolicy retries the failed stage of builds that fail with a transient error
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// SigningKeySecretRef names a Secret holding the cosign key used to sign the
+	// pushed artifacts. Empty uses the OperatorConfig signing key, if any.
+	SigningKeySecretRef string `json:"signingKeySecretRef,omitempty"`
+
+	// Notify opts the build in to the OperatorConfig notification targets that
+	// only notify about builds that asked for it
+	Notify bool `json:"notify,omitempty"`
+
 	// Flash configuration for Jumpstarter device flashing after build
 	FlashEnabled          bool   `json:"flashEnabled,omitempty"`          // Enable flashing after build
 	FlashClientConfig     string `json:"flashClientConfig,omitempty"`     // Base64-encoded Jumpstarter client config
@@ -207,6 +229,63 @@ type BuildRequest struct {
 	FlashLeaseName        string `json:"flashLeaseName,omitempty"`        // Existing lease name (mutually exclusive with FlashLeaseDuration)
 	FlashCmd              string `json:"flashCmd,omitempty"`              // Override flash command from OperatorConfig
 	FlashExporterSelector string `json:"flashExporterSelector,omitempty"` // Override exporter selector from OperatorConfig
+
+	// FlashTest runs smoke tests on the device after flashing (requires FlashEnabled)
+	FlashTest *DeviceTestRequest `json:"flashTest,omitempty"`
+}
+
+// RebuildRequest is the payload to rebuild an existing build. The new build
+// clones the parent build's inputs and push destinations; set fields override them.
+type RebuildRequest struct {
+	Name                   string               `json:"name,omitempty"`                   // Name of the new build (default: parent build name)
+	CustomDefs             []string             `json:"customDefs,omitempty"`             // KEY=VALUE defines, replacing parent defines with the same key
+	Target                 Target               `json:"target,omitempty"`                 // Override the build target
+	Architecture           Architecture         `json:"architecture,omitempty"`           // Override the architecture (rebuilds multi-arch builds for one architecture)
+	AutomotiveImageBuilder string               `json:"automotiveImageBuilder,omitempty"` // Override the AIB container image
+	TTL                    string               `json:"ttl,omitempty"`                    // Override the build TTL
+	RegistryCredentials    *RegistryCredentials `json:"registryCredentials,omitempty"`    // Credentials for the parent's push destinations
+	HasLocalFiles          bool                 `json:"hasLocalFiles,omitempty"`          // Client will upload the parent's local input files
+}
+
+// RetryPolicy configures automatic retries of transiently failed builds.
+type RetryPolicy struct {
+	MaxAttempts int32    `json:"maxAttempts"`       // Total attempts including the first one (1-10)
+	Backoff     string   `json:"backoff,omitempty"` // Delay before the first retry, doubled per retry (default 30s)
+	RetryOn     []string `json:"retryOn,omitempty"` // MirrorDownload, RegistryServerError, Timeout
+}
+
+// BuildAttempt describes one failed attempt of a build stage.
+type BuildAttempt struct {
+	Attempt         int32  `json:"attempt"`
+	Stage           string `json:"stage"`
+	PipelineRunName string `json:"pipelineRunName,omitempty"`
+	TaskRunName     string `json:"taskRunName,omitempty"`
+	FailureClass    string `json:"failureClass,omitempty"`
+	FailureReason   string `json:"failureReason,omitempty"`
+	CompletionTime  string `json:"completionTime,omitempty"`
+}
+
+// ArtifactLocation describes a disk image stored outside of a container registry.
+type ArtifactLocation struct {
+	Type      string `json:"type"`                // s3, http or pvc
+	URL       string `json:"url"`                 // s3://bucket/key, http(s):// URL, or the file path within the PVC
+	Endpoint  string `json:"endpoint,omitempty"`  // S3 service endpoint (s3 only)
+	Region    string `json:"region,omitempty"`    // S3 signing region (s3 only, default us-east-1)
+	ClaimName string `json:"claimName,omitempty"` // PersistentVolumeClaim name (pvc only)
+	SecretRef string `json:"secretRef,omitempty"` // Existing secret with storage credentials in the build namespace
+
+	// Credentials are stored in a build-owned secret; never returned by the API
+	Credentials *StorageCredentials `json:"credentials,omitempty"`
+}
+
+// StorageCredentials authenticate uploads to S3-compatible storage (access key pair)
+// or HTTP endpoints (token, or username and password).
+type StorageCredentials struct {
+	AccessKeyID     string `json:"accessKeyId,omitempty"`
+	SecretAccessKey string `json:"secretAccessKey,omitempty"`
+	Token           string `json:"token,omitempty"`
+	Username        string `json:"username,omitempty"`
+	Password        string `json:"password,omitempty"`
 }
 
 // RegistryCredentials contains authentication details for container registries.
@@ -252,6 +331,41 @@ type FlashRequest struct {
 	LeaseName string `json:"leaseName,omitempty"`
 	// RegistryCredentials contains OCI registry auth for pulling the flash image on the exporter
 	RegistryCredentials *RegistryCredentials `json:"registryCredentials,omitempty"`
+	// Test runs smoke tests on the device after flashing
+	Test *DeviceTestRequest `json:"test,omitempty"`
+	// Fleet flashes the image to several boards matching the selector, each under its own lease
+	Fleet *FleetFlashRequest `json:"fleet,omitempty"`
+	// Console records the device serial console after flashing
+	Console *ConsoleCaptureRequest `json:"console,omitempty"`
+}
+
+// ConsoleCaptureRequest records the serial console of a flashed device
+type ConsoleCaptureRequest struct {
+	Duration       string `json:"duration,omitempty"`       // How long to record after flashing (default: 5m)
+	SkipPowerCycle bool   `json:"skipPowerCycle,omitempty"` // Record without power-cycling the device first
+	SkipUpload     bool   `json:"skipUpload,omitempty"`     // Keep the transcript in the TaskRun logs only
+}
+
+// FleetFlashRequest selects the boards of a fleet flash. Exactly one of Count and AllExporters must be set.
+type FleetFlashRequest struct {
+	// Count is the number of boards to flash
+	Count int32 `json:"count,omitempty"`
+	// AllExporters flashes every online exporter matching the selector
+	AllExporters bool `json:"allExporters,omitempty"`
+	// MaxParallel limits how many boards are flashed at the same time (0 flashes all at once)
+	MaxParallel int32 `json:"maxParallel,omitempty"`
+}
+
+// FlashBoardStatus reports the flash state of one board of a fleet flash
+type FlashBoardStatus struct {
+	Board       int32              `json:"board"`
+	Phase       string             `json:"phase"`
+	Message     string             `json:"message,omitempty"`
+	TaskRunName string             `json:"taskRunName,omitempty"`
+	LeaseID     string             `json:"leaseId,omitempty"`
+	Exporter    string             `json:"exporter,omitempty"`
+	DeviceTests *DeviceTestResults `json:"deviceTests,omitempty"`
+	ConsoleRef  string             `json:"consoleRef,omitempty"`
 }
 
 // FlashResponse is returned by flash operations
@@ -263,14 +377,25 @@ type FlashResponse struct {
 	StartTime      string `json:"startTime,omitempty"`
 	CompletionTime string `json:"completionTime,omitempty"`
 	TaskRunName    string `json:"taskRunName,omitempty"`
+	// LeaseID is the Jumpstarter lease used for the flash
+	LeaseID string `json:"leaseId,omitempty"`
+	// Exporter is the Jumpstarter exporter the lease was granted on
+	Exporter string `json:"exporter,omitempty"`
+	// DeviceTests summarizes the on-device test results, if tests were requested
+	DeviceTests *DeviceTestResults `json:"deviceTests,omitempty"`
+	// ConsoleRef is the OCI referrer holding the serial console transcript, if it was uploaded
+	ConsoleRef string `json:"consoleRef,omitempty"`
+	// Boards reports per-board state for fleet flashes
+	Boards []FlashBoardStatus `json:"boards,omitempty"`
 }
 
-// FlashListItem represents a flash TaskRun in the list API
+// FlashListItem represents a FlashJob in the list API
 type FlashListItem struct {
 	Name           string `json:"name"`
 	Phase          string `json:"phase"`
 	Message        string `json:"message"`
 	RequestedBy    string `json:"requestedBy,omitempty"`
+	Exporter       string `json:"exporter,omitempty"`
 	CreatedAt      string `json:"createdAt"`
 	CompletionTime string `json:"completionTime,omitempty"`
 }
@@ -291,6 +416,74 @@ type BuildResponse struct {
 	ExpiresAt      string           `json:"expiresAt,omitempty"`
 	Jumpstarter    *JumpstarterInfo `json:"jumpstarter,omitempty"`
 	Parameters     *BuildParameters `json:"parameters,omitempty"`
+
+	// Architectures reports per-architecture state for multi-architecture builds
+	Architectures []ArchitectureStatus `json:"architectures,omitempty"`
+
+	// DiskLocation is reported instead of DiskImage when the disk was exported to s3, http or pvc storage
+	DiskLocation *ArtifactLocation `json:"diskLocation,omitempty"`
+
+	// QueuePosition is the 1-based position of a Queued build in the build queue
+	QueuePosition int32 `json:"queuePosition,omitempty"`
+
+	// Attempts lists the failed attempts of a build with a retry policy
+	Attempts []BuildAttempt `json:"attempts,omitempty"`
+
+	// NextRetryTime is when the failed stage will be retried
+	NextRetryTime string `json:"nextRetryTime,omitempty"`
+
+	// ParentBuild is the build this build was rebuilt from
+	ParentBuild string `json:"parentBuild,omitempty"`
+
+	// DeviceTests summarizes the on-device test results of a build with flash tests
+	DeviceTests *DeviceTestResults `json:"deviceTests,omitempty"`
+}
+
+// DeviceTestRequest configures smoke tests run on the device after it is flashed.
+type DeviceTestRequest struct {
+	Script         string `json:"script"`                   // Test script run against the flashed device
+	Image          string `json:"image,omitempty"`          // Container image the script runs in (default: Jumpstarter image)
+	BootCheck      string `json:"bootCheck,omitempty"`      // console or ssh (default: console)
+	BootMarker     string `json:"bootMarker,omitempty"`     // Regular expression awaited on the console (default: "login:")
+	BootTimeout    string `json:"bootTimeout,omitempty"`    // How long to wait for the boot check (default: 10m)
+	SkipPowerCycle bool   `json:"skipPowerCycle,omitempty"` // Skip power-cycling the device before the boot check
+}
+
+// DeviceTestResults summarizes the JUnit results of on-device tests.
+type DeviceTestResults struct {
+	Booted      bool             `json:"booted"`
+	Total       int32            `json:"total"`
+	Passed      int32            `json:"passed"`
+	Failed      int32            `json:"failed"`
+	Skipped     int32            `json:"skipped"`
+	Message     string           `json:"message,omitempty"`
+	FailedCases []DeviceTestCase `json:"failedCases,omitempty"`
+}
+
+// DeviceTestCase is a failed test case from the JUnit results.
+type DeviceTestCase struct {
+	Suite   string `json:"suite,omitempty"`
+	Name    string `json:"name"`
+	Message string `json:"message,omitempty"`
+}
+
+// BuildTestsResponse is returned by the build tests endpoint.
+type BuildTestsResponse struct {
+	Name  string `json:"name"`
+	Phase string `json:"phase"`
+	// Tests is empty until the on-device tests finished
+	Tests *DeviceTestResults `json:"tests,omitempty"`
+}
+
+// ArchitectureStatus is the state of one architecture in a multi-architecture build
+type ArchitectureStatus struct {
+	Architecture   string `json:"architecture"`
+	Phase          string `json:"phase"`
+	Message        string `json:"message,omitempty"`
+	StartTime      string `json:"startTime,omitempty"`
+	CompletionTime string `json:"completionTime,omitempty"`
+	ContainerImage string `json:"containerImage,omitempty"`
+	DiskImage      string `json:"diskImage,omitempty"`
 }
 
 // BuildParameters describes the key input parameters that produced an ImageBuild.
@@ -310,6 +503,20 @@ type BuildParameters struct {
 	FlashLeaseDuration     string `json:"flashLeaseDuration,omitempty"`
 	FlashLeaseName         string `json:"flashLeaseName,omitempty"`
 	UseServiceAccountAuth  bool   `json:"useServiceAccountAuth,omitempty"`
+
+	// Architectures lists every target architecture of a multi-architecture build
+	Architectures []string `json:"architectures,omitempty"`
+
+	// Priority is the build priority class, empty for the default class
+	Priority string `json:"priority,omitempty"`
+
+	// MaxAttempts is the retry policy's attempt limit, zero when retries are disabled
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+
+	// ContainerPush and ExportOCI are the configured push destinations, set
+	// even when the build did not produce an image
+	ContainerPush string `json:"containerPush,omitempty"`
+	ExportOCI     string `json:"exportOci,omitempty"`
 }
 
 // TokenResponse is returned by the token endpoint for internal registry builds
@@ -353,6 +560,8 @@ type OperatorConfigResponse struct {
 	JumpstarterTargets map[string]JumpstarterTarget `json:"jumpstarterTargets,omitempty"`
 	// TargetDefaults contains build defaults per target (from ConfigMap)
 	TargetDefaults map[string]TargetDefaults `json:"targetDefaults,omitempty"`
+	// SigningPublicKey is the PEM-encoded cosign public key that verifies signed build artifacts
+	SigningPublicKey string `json:"signingPublicKey,omitempty"`
 }
 
 type (
@@ -368,6 +577,58 @@ type BuildTemplateResponse struct {
 	SourceFiles  []string `json:"sourceFiles,omitempty"`
 }
 
+// BuildDiffResponse describes what changed between two builds.
+type BuildDiffResponse struct {
+	From BuildDiffSide `json:"from"`
+	To   BuildDiffSide `json:"to"`
+	// Lineage lists the builds from the descendant back to its ancestor when one
+	// build was rebuilt, directly or indirectly, from the other
+	Lineage []string `json:"lineage,omitempty"`
+	// Manifest is a unified diff of the AIB manifests, empty if they are identical
+	Manifest   string      `json:"manifest,omitempty"`
+	Parameters []DiffEntry `json:"parameters,omitempty"` // AIB/builder images, task bundle and build parameters
+	CustomDefs []DiffEntry `json:"customDefs,omitempty"` // Custom defines keyed by name
+	// Packages compares the RPMs listed in the osbuild manifest referrers of the
+	// disk artifacts. The API server leaves it empty since it has no registry
+	// credentials; caib fills it in when it can read both referrers.
+	Packages []DiffEntry `json:"packages,omitempty"`
+}
+
+// BuildDiffSide identifies one of the builds being compared.
+type BuildDiffSide struct {
+	Name         string `json:"name"`
+	Phase        string `json:"phase"`
+	CreatedAt    string `json:"createdAt"`
+	ParentBuild  string `json:"parentBuild,omitempty"`
+	DiskArtifact string `json:"diskArtifact,omitempty"` // Digest-pinned reference of the pushed disk image
+	Reproducible bool   `json:"reproducible,omitempty"`
+}
+
+// DiffEntry is one changed value. From is empty for added values, To for removed ones.
+type DiffEntry struct {
+	Name string `json:"name"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// DiffValues compares two sets of named values and returns the entries that
+// differ, sorted by name.
+func DiffValues(from, to map[string]string) []DiffEntry {
+	var entries []DiffEntry
+	for name, fromValue := range from {
+		if toValue, ok := to[name]; !ok || toValue != fromValue {
+			entries = append(entries, DiffEntry{Name: name, From: fromValue, To: to[name]})
+		}
+	}
+	for name, toValue := range to {
+		if _, ok := from[name]; !ok {
+			entries = append(entries, DiffEntry{Name: name, To: toValue})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
 // ContainerBuildRequest is the payload to create a container build via the REST API
 type ContainerBuildRequest struct {
 	// Name is the build identifier (auto-generated if omitted)
@@ -492,3 +753,48 @@ type SealedListItem struct {
 	CreatedAt      string `json:"createdAt"`
 	CompletionTime string `json:"completionTime,omitempty"`
 }
+
+// LeaseOwner is a build, flash job or workspace holding a lease
+type LeaseOwner struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// LeaseResponse describes a Jumpstarter lease held by the requester's builds,
+// flash jobs or workspaces
+type LeaseResponse struct {
+	Name      string       `json:"name"`
+	Namespace string       `json:"namespace"`
+	Client    string       `json:"client,omitempty"`
+	Exporter  string       `json:"exporter,omitempty"`
+	Duration  string       `json:"duration,omitempty"`
+	BeginTime string       `json:"beginTime,omitempty"`
+	ExpiresAt string       `json:"expiresAt,omitempty"`
+	Active    bool         `json:"active"`
+	Released  bool         `json:"released,omitempty"`
+	Owners    []LeaseOwner `json:"owners"`
+}
+
+// ExtendLeaseRequest is the payload to extend a lease
+type ExtendLeaseRequest struct {
+	// Duration is added to the lease in Go duration format (e.g. "1h")
+	Duration string `json:"duration"`
+}
+
+// BuildEvent is a status change of an ImageBuild, ContainerBuild, ImageReseal or
+// FlashJob, streamed by GET /v1/events and GET /v1/builds/{name}/watch
+type BuildEvent struct {
+	// ID can be sent back in the Last-Event-ID header to resume the stream after it
+	ID string `json:"id"`
+	// Type is "status" for the current status of a resource, or "deleted"
+	Type        string             `json:"type"`
+	Kind        string             `json:"kind"`
+	Name        string             `json:"name"`
+	Namespace   string             `json:"namespace"`
+	Phase       string             `json:"phase,omitempty"`
+	Message     string             `json:"message,omitempty"`
+	Step        *BuildStep         `json:"step,omitempty"`
+	Conditions  []metav1.Condition `json:"conditions,omitempty"`
+	RequestedBy string             `json:"requestedBy,omitempty"`
+	Time        string             `json:"time"`
+}
@@ -129,7 +129,7 @@ func findRunningUploadPod(ctx context.Context, k8sClient client.Client, namespac
 }
 
 func (a *APIServer) uploadFiles(c *gin.Context, name string) {
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 
 	k8sClient, err := getK8sClientOrFail(c)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -50,11 +51,15 @@ type WorkspaceRequest struct {
 	Memory                  string `json:"memory,omitempty"`                  // Memory request (e.g., "2Gi", "512Mi")
 	TmpfsBuildDir           bool   `json:"tmpfsBuildDir,omitempty"`           // Mount tmpfs at /tmp/build for fast compilation
 	AutoPauseTimeoutMinutes *int32 `json:"autoPauseTimeoutMinutes,omitempty"` // nil = use global default, 0 = disable
+	FromSnapshot            string `json:"fromSnapshot,omitempty"`            // WorkspaceSnapshot to clone the workspace volume from
+	Target                  string `json:"target,omitempty"`                  // Board target, selects the board credentials of its Jumpstarter target mapping
+	BoardCredentials        string `json:"boardCredentials,omitempty"`        // Secret with board SSH credentials, overrides the target mapping
 }
 
 // WorkspaceResponse is returned by workspace operations.
 type WorkspaceResponse struct {
 	Name             string `json:"name"`
+	Owner            string `json:"owner,omitempty"`
 	Phase            string `json:"phase"`
 	Lease            string `json:"lease,omitempty"`
 	Arch             string `json:"architecture"`
@@ -62,6 +67,8 @@ type WorkspaceResponse struct {
 	Age              string `json:"age,omitempty"`
 	AutoPauseTimeout string `json:"autoPauseTimeout,omitempty"` // e.g., "30m", "disabled"
 	LastActivity     string `json:"lastActivity,omitempty"`     // e.g., "2m ago", "just now"
+	FromSnapshot     string `json:"fromSnapshot,omitempty"`
+	BoardCredentials string `json:"boardCredentials,omitempty"`
 }
 
 // WorkspaceExecRequest is the payload to execute a command in a workspace.
@@ -76,8 +83,9 @@ type SyncPlanRequest struct {
 
 // SyncPlanResponse tells the client which files need uploading.
 type SyncPlanResponse struct {
-	Changed   []string `json:"changed"`   // files to upload (new or modified)
-	Unchanged int      `json:"unchanged"` // count of files already up to date
+	Changed   []string `json:"changed"`           // files to upload (new or modified)
+	Unchanged int      `json:"unchanged"`         // count of files already up to date
+	Deleted   []string `json:"deleted,omitempty"` // files of the previous sync that no longer exist locally
 }
 
 // ArtifactMapping maps a source path inside the workspace to a destination on the board.
@@ -89,13 +97,12 @@ type ArtifactMapping struct {
 // WorkspaceDeployRequest is the payload to deploy artifacts to a board.
 type WorkspaceDeployRequest struct {
 	Artifacts []ArtifactMapping `json:"artifacts"`
-	Password  string            `json:"password,omitempty"` // SSH password for key injection (default: "password")
 }
 
 // registerWorkspaceRoutes registers the workspace API routes on the v1 group.
 func (a *APIServer) registerWorkspaceRoutes(v1 *gin.RouterGroup) {
 	workspaceGroup := v1.Group("/workspaces")
-	workspaceGroup.Use(a.authMiddleware())
+	workspaceGroup.Use(a.authMiddleware(), a.authorizeMiddleware())
 	{
 		workspaceGroup.POST("", a.wrapHandler("create workspace", a.createWorkspace))
 		workspaceGroup.GET("", a.wrapHandler("list workspaces", a.listWorkspaces))
@@ -105,9 +112,14 @@ func (a *APIServer) registerWorkspaceRoutes(v1 *gin.RouterGroup) {
 		workspaceGroup.POST("/:name/stop", a.wrapNamedHandler("stop workspace", a.stopWorkspace))
 		workspaceGroup.POST("/:name/sync", a.wrapNamedHandler("sync workspace", a.syncWorkspace))
 		workspaceGroup.POST("/:name/sync/plan", a.wrapNamedHandler("sync plan workspace", a.syncPlanWorkspace))
+		workspaceGroup.POST("/:name/sync/finish", a.wrapNamedHandler("sync finish workspace", a.syncFinishWorkspace))
+		workspaceGroup.GET("/:name/sync/pull", a.wrapNamedHandler("pull workspace", a.pullWorkspace))
 		workspaceGroup.POST("/:name/exec", a.wrapNamedHandler("exec workspace", a.execWorkspace))
 		workspaceGroup.GET("/:name/shell", a.wrapNamedHandler("shell workspace", a.shellWorkspace))
+		workspaceGroup.GET("/:name/port-forward", a.wrapNamedHandler("port-forward workspace", a.portForwardWorkspace))
+		workspaceGroup.GET("/:name/debug", a.wrapNamedHandler("debug workspace", a.debugWorkspace))
 		workspaceGroup.POST("/:name/deploy", a.wrapNamedHandler("deploy workspace", a.deployWorkspace))
+		workspaceGroup.POST("/:name/snapshots", a.wrapNamedHandler("snapshot workspace", a.createWorkspaceSnapshot))
 		workspaceGroup.PUT("/:name/lease", a.handleSetWorkspaceLease)
 	}
 }
@@ -137,11 +149,11 @@ func (a *APIServer) createWorkspace(c *gin.Context) {
 		return
 	}
 
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 	requester := a.resolveRequester(c)
 
 	// Load workspace configuration from OperatorConfig
-	operatorConfig, cfgErr := loadOperatorConfigFn(c.Request.Context(), k8sClient, namespace)
+	operatorConfig, cfgErr := loadOperatorConfigFn(c.Request.Context(), k8sClient, resolveNamespace())
 	if cfgErr != nil && !k8serrors.IsNotFound(cfgErr) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load operator config"})
 		return
@@ -151,11 +163,32 @@ func (a *APIServer) createWorkspace(c *gin.Context) {
 		wsConfig = operatorConfig.Spec.Workspaces
 	}
 
+	// A clone defaults to the settings of the snapshotted workspace
+	var source automotivev1alpha1.WorkspaceSnapshotSource
+	var snapshotMethod string
+	if req.FromSnapshot != "" {
+		snapshot, status, snapErr := resolveSnapshotForClone(c, k8sClient, namespace, req.FromSnapshot, requester)
+		if snapErr != nil {
+			c.JSON(status, gin.H{"error": snapErr.Error()})
+			return
+		}
+		if snapshot.Status.Source != nil {
+			source = *snapshot.Status.Source
+		}
+		snapshotMethod = snapshot.Status.Method
+	}
+
 	arch := req.Arch
+	if arch == "" {
+		arch = source.Architecture
+	}
 	if arch == "" {
 		arch = wsConfig.GetDefaultArchitecture()
 	}
 	image := req.Image
+	if image == "" {
+		image = source.Image
+	}
 	if image == "" {
 		image = wsConfig.GetToolchainImage()
 	}
@@ -168,15 +201,48 @@ func (a *APIServer) createWorkspace(c *gin.Context) {
 		return
 	}
 	pvcSize := wsConfig.GetPVCSize()
+	storageClass := wsConfig.GetStorageClass()
+	if req.FromSnapshot != "" {
+		// The restored volume must hold the snapshot, and a VolumeSnapshot can
+		// only be restored by the CSI driver that took it
+		if snapshotSize, sizeErr := resource.ParseQuantity(source.PVCSize); sizeErr == nil {
+			if configSize, cfgSizeErr := resource.ParseQuantity(pvcSize); cfgSizeErr != nil || snapshotSize.Cmp(configSize) > 0 {
+				pvcSize = source.PVCSize
+			}
+		}
+		if snapshotMethod == automotivev1alpha1.SnapshotMethodVolumeSnapshot && source.StorageClass != "" {
+			storageClass = source.StorageClass
+		}
+	}
+	if err := a.admitWorkspace(c.Request.Context(), c, k8sClient, operatorConfig, namespace, pvcSize); err != nil {
+		writeQuotaError(c, err)
+		return
+	}
 
-	// Resolve lease from ImageBuild if --from-build was used
+	// Resolve lease and target from ImageBuild if --from-build was used
 	leaseID := req.Lease
+	target := req.Target
 	if leaseID == "" && req.FromBuild != "" {
-		leaseID, err = a.resolveLeaseFromBuild(c.Request.Context(), k8sClient, namespace, req.FromBuild, requester)
+		var buildTarget string
+		leaseID, buildTarget, err = a.resolveLeaseFromBuild(c.Request.Context(), k8sClient, namespace, req.FromBuild, requester)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to resolve lease from build %q: %v", req.FromBuild, err)})
 			return
 		}
+		if target == "" {
+			target = buildTarget
+		}
+	}
+
+	boardCredentials, err := resolveBoardCredentialsSecret(c.Request.Context(), k8sClient, namespace, operatorConfig,
+		req.BoardCredentials, target, requester)
+	if errors.Is(err, errBoardCredentialsNotAllowed) {
+		forbidden(c, reasonNotOwner, err.Error())
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
 	// Create secret for Jumpstarter client config if provided
@@ -240,17 +306,19 @@ func (a *APIServer) createWorkspace(c *gin.Context) {
 			Namespace: namespace,
 		},
 		Spec: automotivev1alpha1.WorkspaceSpec{
-			Architecture:            arch,
-			Image:                   image,
-			LeaseID:                 leaseID,
-			Owner:                   requester,
-			ClientConfigSecretRef:   jmpClientSecret,
-			PVCSize:                 pvcSize,
-			Resources:               resources,
-			StorageClass:            wsConfig.GetStorageClass(),
-			NodeSelector:            wsConfig.GetNodeSelector(),
-			TmpfsBuildDir:           req.TmpfsBuildDir,
-			AutoPauseTimeoutMinutes: req.AutoPauseTimeoutMinutes,
+			Architecture:              arch,
+			Image:                     image,
+			LeaseID:                   leaseID,
+			Owner:                     requester,
+			ClientConfigSecretRef:     jmpClientSecret,
+			PVCSize:                   pvcSize,
+			Resources:                 resources,
+			StorageClass:              storageClass,
+			NodeSelector:              wsConfig.GetNodeSelector(),
+			TmpfsBuildDir:             req.TmpfsBuildDir,
+			AutoPauseTimeoutMinutes:   req.AutoPauseTimeoutMinutes,
+			FromSnapshot:              req.FromSnapshot,
+			BoardCredentialsSecretRef: boardCredentials,
 		},
 	}
 	if err := k8sClient.Create(c.Request.Context(), ws); err != nil {
@@ -267,12 +335,17 @@ func (a *APIServer) listWorkspaces(c *gin.Context) {
 		return
 	}
 
-	namespace := resolveNamespace()
 	requester := a.resolveRequester(c)
 	limit, offset := parsePagination(c)
+	// Admins list the workspaces of all users with ?all=true
+	all := c.Query("all") == "true"
+	if all && !requesterRole(c).Includes(automotivev1alpha1.BuildAPIRoleAdmin) {
+		forbidden(c, reasonInsufficientRole, "listing the workspaces of all users requires the admin role")
+		return
+	}
 
 	wsList := &automotivev1alpha1.WorkspaceList{}
-	if err := k8sClient.List(c.Request.Context(), wsList, &client.ListOptions{Namespace: namespace}); err != nil {
+	if err := listInNamespaces(c.Request.Context(), c, k8sClient, wsList); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list workspaces"})
 		return
 	}
@@ -286,7 +359,7 @@ func (a *APIServer) listWorkspaces(c *gin.Context) {
 	owned := make([]WorkspaceResponse, 0, len(wsList.Items))
 	for i := range wsList.Items {
 		ws := &wsList.Items[i]
-		if ws.Spec.Owner != requester {
+		if !all && ws.Spec.Owner != requester {
 			continue
 		}
 		owned = append(owned, workspaceResponseFromCR(ws))
@@ -398,7 +471,7 @@ func (a *APIServer) getOwnedWorkspace(c *gin.Context, name string) (*automotivev
 		return nil, fmt.Errorf("failed to create kubernetes client")
 	}
 
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 	requester := a.resolveRequester(c)
 
 	ws := &automotivev1alpha1.Workspace{}
@@ -411,7 +484,8 @@ func (a *APIServer) getOwnedWorkspace(c *gin.Context, name string) (*automotivev
 		return nil, err
 	}
 
-	if ws.Spec.Owner != requester {
+	// Other users' workspaces are hidden from everyone but admins
+	if !a.canActOn(c, ws.Spec.Owner, automotivev1alpha1.BuildAPIRoleAdmin) {
 		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("workspace %q not found", name)})
 		return nil, fmt.Errorf("workspace %q not owned by %q", name, requester)
 	}
@@ -459,7 +533,6 @@ func (a *APIServer) syncWorkspace(c *gin.Context, name string) {
 
 	// Spool the tar stream to a temp file so that EOF propagates cleanly to
 	// the SPDY executor, without buffering the entire upload in memory.
-	const maxSyncSize int64 = 512 << 20 // 512 MiB
 	tmpFile, err := os.CreateTemp("", "workspace-sync-*.tar")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create temp file for upload"})
@@ -468,13 +541,13 @@ func (a *APIServer) syncWorkspace(c *gin.Context, name string) {
 	defer func() { _ = os.Remove(tmpFile.Name()) }()
 	defer func() { _ = tmpFile.Close() }()
 
-	written, err := io.Copy(tmpFile, io.LimitReader(c.Request.Body, maxSyncSize+1))
+	written, err := io.Copy(tmpFile, io.LimitReader(c.Request.Body, MaxSyncUploadSize+1))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to read upload: %v", err)})
 		return
 	}
-	if written > maxSyncSize {
-		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("upload exceeds maximum size of %d MiB", maxSyncSize>>20)})
+	if written > MaxSyncUploadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("upload exceeds maximum size of %d MiB", MaxSyncUploadSize>>20)})
 		return
 	}
 	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
@@ -482,7 +555,7 @@ func (a *APIServer) syncWorkspace(c *gin.Context, name string) {
 		return
 	}
 
-	if err := copyToPod(c.Request.Context(), restCfg, namespace, podName, workspaceContainerName, tmpFile, "/workspace/src/"); err != nil {
+	if err := copyToPod(c.Request.Context(), restCfg, namespace, podName, workspaceContainerName, tmpFile, workspaceSrcDir+"/"); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to sync files: %v", err)})
 		return
 	}
@@ -519,7 +592,7 @@ func (a *APIServer) syncPlanWorkspace(c *gin.Context, name string) {
 	// Build a shell script that hashes only the files the client cares about.
 	// This avoids scanning build artifacts or other untracked content.
 	var scriptBuf strings.Builder
-	scriptBuf.WriteString("cd /workspace/src\n")
+	scriptBuf.WriteString("cd " + workspaceSrcDir + "\n")
 	for path := range req.Files {
 		// Only hash regular files that exist; skip missing ones silently
 		scriptBuf.WriteString(fmt.Sprintf("[ -f %s ] && sha256sum %s\n", shellQuote(path), shellQuote(path)))
@@ -544,6 +617,14 @@ func (a *APIServer) syncPlanWorkspace(c *gin.Context, name string) {
 		remote[parts[1]] = parts[0]
 	}
 
+	// Files recorded by the previous sync but gone locally are to be deleted
+	var previousManifest bytes.Buffer
+	readManifest := []string{"/bin/sh", "-c", "cat " + syncManifestPath + " 2>/dev/null; true"}
+	if err := podExec(c.Request.Context(), restCfg, ws.Namespace, ws.Status.PodName, workspaceContainerName, readManifest, &previousManifest); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to read sync manifest: %v", err)})
+		return
+	}
+
 	// Compare client manifest against remote
 	var changed []string
 	unchanged := 0
@@ -558,6 +639,7 @@ func (a *APIServer) syncPlanWorkspace(c *gin.Context, name string) {
 	c.JSON(http.StatusOK, SyncPlanResponse{
 		Changed:   changed,
 		Unchanged: unchanged,
+		Deleted:   syncDeletedFiles(previousManifest.String(), req.Files),
 	})
 }
 
@@ -750,88 +832,55 @@ func (a *APIServer) deployWorkspace(c *gin.Context, name string) {
 		return
 	}
 
+	k8sClient, err := getK8sClientOrFail(c)
+	if err != nil {
+		return
+	}
+
 	// Use jmp shell to forward a local TCP port to the board, then SSH/rsync through it.
 	// The board's IP is behind the Jumpstarter lab network and not directly routable
 	// from the workspace pod, so we must tunnel through Jumpstarter.
-	sshPassword := req.Password
-	if sshPassword == "" {
-		sshPassword = "password"
+	//
+	// The tunnel is a long-lived background process that persists across deploys. On
+	// first deploy it takes ~8s to start (lease acquisition + TCP forwarding);
+	// subsequent deploys reuse it (~0s). It runs in a separate exec from the deploy to
+	// avoid SPDY fd inheritance: background processes inherit SPDY pipe fds (even with
+	// >/dev/null redirects), keeping the stream open until the process exits. PID is
+	// exchanged via /tmp/.tunnel.pid (not stdout) because SPDY has a race condition
+	// where the stream closes before output is delivered for fast execs.
+	access, err := prepareBoardAccess(c.Request.Context(), k8sClient, restCfg, ws)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
 	}
 
-	// Ensure a Jumpstarter tunnel is running. The tunnel is a long-lived background
-	// process that persists across deploys. On first deploy it takes ~8s to start
-	// (lease acquisition + TCP forwarding); subsequent deploys reuse it (~0s).
-	//
-	// The tunnel runs in a separate exec from the deploy to avoid SPDY fd inheritance:
-	// background processes inherit SPDY pipe fds (even with >/dev/null redirects),
-	// keeping the stream open until the process exits. By splitting into two execs,
-	// phase 2 has zero background processes so its SPDY stream closes immediately.
-	// PID is exchanged via /tmp/.tunnel.pid (not stdout) because SPDY has a race
-	// condition where the stream closes before output is delivered for fast execs.
-	tunnelScript := fmt.Sprintf(
-		`SSH_OPTS="-o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null -o LogLevel=ERROR -o ConnectTimeout=3"
-SSH_KEY="-i /workspace/.ssh/id_ed25519"
-
-# Reuse existing tunnel if it's alive and reachable
-if [ -f /tmp/.tunnel.pid ]; then
-  OLD_PID=$(cat /tmp/.tunnel.pid)
-  if kill -0 $OLD_PID 2>/dev/null && ssh -p 2222 $SSH_OPTS $SSH_KEY root@127.0.0.1 true 2>/dev/null; then
-    exit 0
-  fi
-  kill $OLD_PID 2>/dev/null; pkill -P $OLD_PID 2>/dev/null; sleep 1
-fi
-jmp shell --lease %s -- j tcp forward-tcp --address 0.0.0.0 2222 </dev/null >/dev/null 2>&1 &
-echo $! > /tmp/.tunnel.pid`,
-		shellQuote(ws.Spec.LeaseID))
-
-	tunnelCtx, tunnelCancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer tunnelCancel()
-	_ = podExec(tunnelCtx, restCfg, ws.Namespace, ws.Status.PodName, workspaceContainerName,
-		[]string{"/bin/sh", "-c", tunnelScript}, io.Discard)
-
-	// Phase 2: Deploy artifacts (streaming, no background processes).
-	// Reads tunnel PID from /tmp/.tunnel.pid. The tunnel is left running for reuse.
+	// Deploy artifacts (streaming, no background processes). The tunnel is left
+	// running for reuse.
 	setupLogStreamHeaders(c)
 
-	// Build rsync commands for each artifact mapping
+	cmd := []string{"/bin/sh", "-c", deployScript(access, req.Artifacts)}
+	if err := execInPod(c.Request.Context(), restCfg, ws.Namespace, ws.Status.PodName, workspaceContainerName, cmd, c.Writer); err != nil {
+		_, _ = fmt.Fprintf(c.Writer, "\n[deploy failed: %v]\n", err)
+	}
+}
+
+// deployScript copies artifacts to the leased board with rsync over a shared
+// SSH connection.
+func deployScript(access *boardAccess, artifacts []ArtifactMapping) string {
 	var rsyncCmds strings.Builder
-	for _, a := range req.Artifacts {
+	for _, a := range artifacts {
 		fmt.Fprintf(&rsyncCmds, "echo \"Deploying %s -> %s\"\n", a.Src, a.Dest)
-		fmt.Fprintf(&rsyncCmds, "rsync -avz --chmod=+x -e \"ssh -p $LOCAL_PORT $SSH_OPTS $SSH_KEY\" %s root@127.0.0.1:%s\n",
+		fmt.Fprintf(&rsyncCmds, "rsync -avz --chmod=+x -e \"$SSH_CMD $SSH_KEY\" %s \"$BOARD\":%s\n",
 			shellQuote(a.Src), shellQuote(a.Dest))
 	}
 
-	deployScript := fmt.Sprintf(
-		`set -e
-LOCAL_PORT=2222
+	return boardSSHScript(access) + fmt.Sprintf(`set -e
 CTL_PATH="/tmp/.ssh-mux-%%r@%%h:%%p"
-SSH_OPTS="-o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null -o LogLevel=ERROR -o ConnectTimeout=3 -o ControlMaster=auto -o ControlPath=$CTL_PATH -o ControlPersist=60"
-SSH_CMD="ssh -p $LOCAL_PORT $SSH_OPTS"
-SSH_KEY="-i /workspace/.ssh/id_ed25519"
-TUNNEL_PID=$(cat /tmp/.tunnel.pid 2>/dev/null)
-if [ -z "$TUNNEL_PID" ]; then echo "Failed to start Jumpstarter tunnel"; exit 1; fi
-
-echo "Waiting for tunnel..."
-SSH_READY=false
-for i in $(seq 1 60); do
-  if ! kill -0 $TUNNEL_PID 2>/dev/null; then echo "Tunnel process died"; exit 1; fi
-  if $SSH_CMD $SSH_KEY root@127.0.0.1 true 2>/dev/null; then SSH_READY=true; break; fi
-  if cat /workspace/.ssh/id_ed25519.pub | sshpass -p %s $SSH_CMD root@127.0.0.1 'mkdir -p ~/.ssh && cat >> ~/.ssh/authorized_keys' 2>/dev/null; then
-    echo "SSH key injected"; SSH_READY=true; break
-  fi
-  sleep 1
-done
-if [ "$SSH_READY" != "true" ]; then echo "Timed out waiting for SSH"; exit 1; fi
-echo "Tunnel ready"
+SSH_CMD="$SSH_CMD -o ControlMaster=auto -o ControlPath=$CTL_PATH -o ControlPersist=60"
 %s
 echo "Deploy complete: %d artifact(s)"
-ssh -p $LOCAL_PORT -o ControlPath=$CTL_PATH -O exit root@127.0.0.1 2>/dev/null; true`,
-		shellQuote(sshPassword), rsyncCmds.String(), len(req.Artifacts))
-
-	cmd := []string{"/bin/sh", "-c", deployScript}
-	if err := execInPod(c.Request.Context(), restCfg, ws.Namespace, ws.Status.PodName, workspaceContainerName, cmd, c.Writer); err != nil {
-		_, _ = fmt.Fprintf(c.Writer, "\n[deploy failed: %v]\n", err)
-	}
+ssh -p 2222 -o ControlPath=$CTL_PATH -O exit "$BOARD" 2>/dev/null; true`,
+		rsyncCmds.String(), len(artifacts))
 }
 
 // buildWorkspaceResources constructs resource requirements from user input and config.
@@ -930,6 +979,7 @@ func workspaceResponseFromCR(ws *automotivev1alpha1.Workspace) WorkspaceResponse
 
 	return WorkspaceResponse{
 		Name:             ws.Name,
+		Owner:            ws.Spec.Owner,
 		Phase:            phase,
 		Lease:            ws.Spec.LeaseID,
 		Arch:             ws.Spec.Architecture,
@@ -937,22 +987,25 @@ func workspaceResponseFromCR(ws *automotivev1alpha1.Workspace) WorkspaceResponse
 		Age:              age,
 		AutoPauseTimeout: autoPauseTimeout,
 		LastActivity:     lastActivity,
+		FromSnapshot:     ws.Spec.FromSnapshot,
+		BoardCredentials: ws.Spec.BoardCredentialsSecretRef,
 	}
 }
 
-// buildFlashInfo holds lease and client config extracted from an ImageBuild.
-func (a *APIServer) resolveLeaseFromBuild(ctx context.Context, k8sClient client.Client, namespace, buildName, requester string) (string, error) {
+// resolveLeaseFromBuild returns the lease of a flashed ImageBuild owned by the
+// requester, and the build's target, which selects the board credentials.
+func (a *APIServer) resolveLeaseFromBuild(ctx context.Context, k8sClient client.Client, namespace, buildName, requester string) (string, string, error) {
 	build := &automotivev1alpha1.ImageBuild{}
 	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: buildName}, build); err != nil {
-		return "", fmt.Errorf("ImageBuild %q not found: %w", buildName, err)
+		return "", "", fmt.Errorf("ImageBuild %q not found: %w", buildName, err)
 	}
 	if owner := build.Annotations[labels.RequestedBy]; owner != requester {
-		return "", fmt.Errorf("ImageBuild %q is owned by a different user", buildName)
+		return "", "", fmt.Errorf("ImageBuild %q is owned by a different user", buildName)
 	}
 	if build.Status.LeaseID == "" {
-		return "", fmt.Errorf("ImageBuild %q has no lease ID (was --flash used?)", buildName)
+		return "", "", fmt.Errorf("ImageBuild %q has no lease ID (was --flash used?)", buildName)
 	}
-	return build.Status.LeaseID, nil
+	return build.Status.LeaseID, build.Spec.GetTarget(), nil
 }
 
 func copyToPod(ctx context.Context, restCfg *rest.Config, namespace, podName, containerName string, body io.Reader, destDir string) error {
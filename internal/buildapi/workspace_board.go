@@ -0,0 +1,302 @@
+package buildapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Keys of a board credentials secret, see JumpstarterTargetMapping.BoardCredentialsSecretRef.
+// The private key uses the kubernetes.io/ssh-auth key, corev1.SSHAuthPrivateKey.
+const (
+	boardCredentialsUsernameKey = "username"
+	boardCredentialsPasswordKey = "password"
+	boardCredentialsHostKeysKey = "host-keys"
+)
+
+// boardCredentialsResourceType is the resource-type label value that marks a
+// Secret as board credentials. Unmarked secrets are never read as such.
+const boardCredentialsResourceType = "board-credentials"
+
+// errBoardCredentialsNotAllowed is returned for secrets a workspace owner may
+// not use as board credentials.
+var errBoardCredentialsNotAllowed = errors.New("not allowed as board credentials")
+
+const (
+	defaultBoardSSHUser = "root"
+	boardKnownHostsPath = "/workspace/.ssh/known_hosts"
+	boardAccessTimeout  = 90 * time.Second
+)
+
+var (
+	boardUserPattern   = regexp.MustCompile(`^[a-z_][a-z0-9_.-]*$`)
+	unsafeAliasPattern = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+)
+
+// boardCredentials are the SSH credentials of a workspace's leased board. They
+// only leave the API server on the stdin of the exec that installs the
+// workspace key on the board.
+type boardCredentials struct {
+	Username   string
+	Password   string
+	PrivateKey []byte
+	HostKeys   []string // "<type> <base64 key>"
+}
+
+// boardAccess tells the scripts of a workspace how to reach its leased board
+// once the workspace key is installed there.
+type boardAccess struct {
+	User  string // SSH user on the board
+	Alias string // name the board's host key is pinned under in known_hosts
+}
+
+// boardHostKeyAlias is the name the leased board is known by in known_hosts.
+// Every board is reached on the same tunnel port, so host keys are pinned per
+// lease: a new lease is usually another board, or a freshly flashed one.
+func boardHostKeyAlias(leaseID string) string {
+	return "board-" + unsafeAliasPattern.ReplaceAllString(leaseID, "_")
+}
+
+// loadBoardCredentials reads the board credentials secret of a workspace.
+// Without one, only the workspace key is tried, as user root. The secret is
+// checked again here because the workspace may not have been created through
+// the Build API.
+func loadBoardCredentials(ctx context.Context, k8sClient client.Client, ws *automotivev1alpha1.Workspace) (*boardCredentials, error) {
+	creds := &boardCredentials{Username: defaultBoardSSHUser}
+	ref := ws.Spec.BoardCredentialsSecretRef
+	if ref == "" {
+		return creds, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: ws.Namespace, Name: ref}, secret); err != nil {
+		return nil, fmt.Errorf("failed to read board credentials secret %q: %w", ref, err)
+	}
+	operatorConfig, err := loadOperatorConfigFn(ctx, k8sClient, resolveNamespace())
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to load operator config: %w", err)
+	}
+	if err := checkBoardCredentialsSecret(secret, ws.Spec.Owner, operatorConfig); err != nil {
+		return nil, err
+	}
+	if user := strings.TrimSpace(string(secret.Data[boardCredentialsUsernameKey])); user != "" {
+		if !boardUserPattern.MatchString(user) {
+			return nil, fmt.Errorf("board credentials secret %q has an invalid username", ref)
+		}
+		creds.Username = user
+	}
+	creds.Password = string(secret.Data[boardCredentialsPasswordKey])
+	if strings.ContainsAny(creds.Password, "\r\n") {
+		return nil, fmt.Errorf("board credentials secret %q has a multi-line password", ref)
+	}
+	creds.PrivateKey = secret.Data[corev1.SSHAuthPrivateKey]
+	hostKeys, err := parseBoardHostKeys(string(secret.Data[boardCredentialsHostKeysKey]))
+	if err != nil {
+		return nil, fmt.Errorf("board credentials secret %q: %w", ref, err)
+	}
+	creds.HostKeys = hostKeys
+	return creds, nil
+}
+
+// parseBoardHostKeys accepts public host keys as in /etc/ssh/ssh_host_*.pub,
+// or known_hosts lines, whose host names are dropped.
+func parseBoardHostKeys(data string) ([]string, error) {
+	var keys []string
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) >= 3 && !isSSHKeyType(fields[0]) {
+			fields = fields[1:]
+		}
+		if len(fields) < 2 || !isSSHKeyType(fields[0]) {
+			return nil, fmt.Errorf("invalid host key %q", line)
+		}
+		keys = append(keys, fields[0]+" "+fields[1])
+	}
+	return keys, nil
+}
+
+func isSSHKeyType(s string) bool {
+	return strings.HasPrefix(s, "ssh-") || strings.HasPrefix(s, "ecdsa-") || strings.HasPrefix(s, "sk-")
+}
+
+// authorizeBoardKeyScript makes sure the workspace key is authorized on the
+// leased board. The board's host key is pinned first, from the credentials or
+// on first contact; a changed key fails rather than being trusted. If the
+// workspace key is refused, it is installed with the provisioning key or
+// password, which are read from stdin so they never appear in a command line.
+// Errors are written to stdout.
+func authorizeBoardKeyScript(creds *boardCredentials, access *boardAccess) string {
+	return fmt.Sprintf(`KNOWN_HOSTS=%[1]s
+ALIAS=%[2]s
+BOARD=%[3]s@127.0.0.1
+PINNED=%[4]s
+SSH_CMD="ssh -p 2222 -o StrictHostKeyChecking=yes -o UserKnownHostsFile=$KNOWN_HOSTS -o HostKeyAlias=$ALIAS -o LogLevel=ERROR -o ConnectTimeout=3"
+INSTALL_KEY='umask 077; mkdir -p ~/.ssh && cat >> ~/.ssh/authorized_keys'
+IFS= read -r BOARD_PASSWORD
+PROVISION_KEY=$(mktemp)
+trap 'rm -f "$PROVISION_KEY"' EXIT
+cat > "$PROVISION_KEY"
+touch "$KNOWN_HOSTS"
+if [ -n "$PINNED" ]; then
+  ssh-keygen -R "$ALIAS" -f "$KNOWN_HOSTS" >/dev/null 2>&1
+  printf '%%s\n' "$PINNED" | sed "s|^|$ALIAS |" >> "$KNOWN_HOSTS"
+fi
+TUNNEL_PID=$(cat /tmp/.tunnel.pid 2>/dev/null)
+if [ -z "$TUNNEL_PID" ]; then echo "Jumpstarter tunnel is not running"; exit 1; fi
+for i in $(seq 1 60); do
+  if ! kill -0 $TUNNEL_PID 2>/dev/null; then echo "Jumpstarter tunnel died"; exit 1; fi
+  if ! ssh-keygen -F "$ALIAS" -f "$KNOWN_HOSTS" >/dev/null 2>&1; then
+    # First contact with the board of this lease: pin the host key it presents
+    KEYS=$(ssh-keyscan -p 2222 -T 3 127.0.0.1 2>/dev/null | sed "s|^[^ ]*|$ALIAS|")
+    if [ -z "$KEYS" ]; then sleep 1; continue; fi
+    printf '%%s\n' "$KEYS" >> "$KNOWN_HOSTS"
+  fi
+  ERR=$($SSH_CMD -o BatchMode=yes -i /workspace/.ssh/id_ed25519 "$BOARD" true </dev/null 2>&1 >/dev/null) && exit 0
+  case "$ERR" in
+  *"Host key verification failed"*|*"IDENTIFICATION HAS CHANGED"*)
+    echo "host key of the board does not match the one pinned as $ALIAS; if the board was reflashed, remove it with: ssh-keygen -R $ALIAS -f $KNOWN_HOSTS"
+    exit 1;;
+  *"Permission denied"*)
+    if [ -s "$PROVISION_KEY" ] && $SSH_CMD -o BatchMode=yes -i "$PROVISION_KEY" "$BOARD" "$INSTALL_KEY" </workspace/.ssh/id_ed25519.pub >/dev/null 2>&1; then exit 0; fi
+    if [ -n "$BOARD_PASSWORD" ] && SSHPASS="$BOARD_PASSWORD" sshpass -e $SSH_CMD "$BOARD" "$INSTALL_KEY" </workspace/.ssh/id_ed25519.pub >/dev/null 2>&1; then exit 0; fi
+    echo "the workspace key is not authorized on the board and the board credentials could not install it"
+    exit 1;;
+  esac
+  sleep 1
+done
+echo "timed out waiting for SSH to the board"
+exit 1
+`, boardKnownHostsPath, shellQuote(access.Alias), shellQuote(access.User), shellQuote(strings.Join(creds.HostKeys, "\n")))
+}
+
+// boardCredentialsInput is the stdin of authorizeBoardKeyScript: the password
+// on the first line, followed by the private key.
+func boardCredentialsInput(creds *boardCredentials) io.Reader {
+	var buf bytes.Buffer
+	buf.WriteString(creds.Password)
+	buf.WriteByte('\n')
+	buf.Write(creds.PrivateKey)
+	return &buf
+}
+
+// prepareBoardAccess starts the Jumpstarter tunnel to the workspace's leased
+// board and makes sure the workspace key is authorized there.
+func prepareBoardAccess(ctx context.Context, k8sClient client.Client, restCfg *rest.Config,
+	ws *automotivev1alpha1.Workspace) (*boardAccess, error) {
+	creds, err := loadBoardCredentials(ctx, k8sClient, ws)
+	if err != nil {
+		return nil, err
+	}
+	access := &boardAccess{User: creds.Username, Alias: boardHostKeyAlias(ws.Spec.LeaseID)}
+
+	ensureBoardTunnel(ctx, restCfg, ws)
+
+	authCtx, cancel := context.WithTimeout(ctx, boardAccessTimeout)
+	defer cancel()
+	var out bytes.Buffer
+	if err := podExecStreams(authCtx, restCfg, ws.Namespace, ws.Status.PodName, workspaceContainerName,
+		[]string{"/bin/sh", "-c", authorizeBoardKeyScript(creds, access)}, boardCredentialsInput(creds), &out); err != nil {
+		if msg := strings.TrimSpace(out.String()); msg != "" {
+			return nil, fmt.Errorf("board access failed: %s", msg)
+		}
+		return nil, fmt.Errorf("board access failed: %w", err)
+	}
+	return access, nil
+}
+
+// ensureBoardTunnel starts the Jumpstarter tunnel to the workspace's leased
+// board if it is not running. It is a separate exec so that the background
+// tunnel does not hold the caller's stream open.
+func ensureBoardTunnel(ctx context.Context, restCfg *rest.Config, ws *automotivev1alpha1.Workspace) {
+	tunnelCtx, tunnelCancel := context.WithTimeout(ctx, 10*time.Second)
+	defer tunnelCancel()
+	_ = podExec(tunnelCtx, restCfg, ws.Namespace, ws.Status.PodName, workspaceContainerName,
+		[]string{"/bin/sh", "-c", boardTunnelScript(ws.Spec.LeaseID)}, io.Discard)
+}
+
+// boardTunnelScript starts, or reuses, a Jumpstarter TCP tunnel in the
+// workspace pod that forwards 127.0.0.1:2222 to the SSH port of the leased board.
+func boardTunnelScript(leaseID string) string {
+	return fmt.Sprintf(
+		`# Reuse existing tunnel if it's alive and an SSH server answers through it
+if [ -f /tmp/.tunnel.pid ]; then
+  OLD_PID=$(cat /tmp/.tunnel.pid)
+  if kill -0 $OLD_PID 2>/dev/null && [ -n "$(ssh-keyscan -p 2222 -T 3 127.0.0.1 2>/dev/null)" ]; then
+    exit 0
+  fi
+  kill $OLD_PID 2>/dev/null; pkill -P $OLD_PID 2>/dev/null; sleep 1
+fi
+jmp shell --lease %s -- j tcp forward-tcp --address 0.0.0.0 2222 </dev/null >/dev/null 2>&1 &
+echo $! > /tmp/.tunnel.pid`,
+		shellQuote(leaseID))
+}
+
+// boardSSHScript defines BOARD, SSH_OPTS, SSH_CMD and SSH_KEY for commands to
+// the leased board through the Jumpstarter tunnel, once prepareBoardAccess has
+// authorized the workspace key. Host keys are checked against the pinned ones.
+func boardSSHScript(access *boardAccess) string {
+	return fmt.Sprintf(`BOARD=%s@127.0.0.1
+SSH_OPTS="-o StrictHostKeyChecking=yes -o UserKnownHostsFile=%s -o HostKeyAlias=%s -o LogLevel=ERROR -o ConnectTimeout=3"
+SSH_CMD="ssh -p 2222 $SSH_OPTS"
+SSH_KEY="-i /workspace/.ssh/id_ed25519"
+`, shellQuote(access.User), boardKnownHostsPath, access.Alias)
+}
+
+// checkBoardCredentialsSecret reports whether owner may use secret as the
+// board credentials of a workspace. The secret must be marked as board
+// credentials and either belong to owner or be the board credentials of a
+// Jumpstarter target mapping. Otherwise its values could be read from the
+// workspace by anyone naming it.
+func checkBoardCredentialsSecret(secret *corev1.Secret, owner string, operatorConfig *automotivev1alpha1.OperatorConfig) error {
+	if secret.Labels[labels.ResourceType] != boardCredentialsResourceType {
+		return fmt.Errorf("secret %q is %w: it is not labeled %s=%s",
+			secret.Name, errBoardCredentialsNotAllowed, labels.ResourceType, boardCredentialsResourceType)
+	}
+	if owner != "" && secret.Annotations[labels.RequestedBy] == owner {
+		return nil
+	}
+	if operatorConfig != nil && operatorConfig.Spec.Jumpstarter != nil {
+		for _, mapping := range operatorConfig.Spec.Jumpstarter.TargetMappings {
+			if mapping.BoardCredentialsSecretRef == secret.Name {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("secret %q is %w: it belongs to another user", secret.Name, errBoardCredentialsNotAllowed)
+}
+
+// resolveBoardCredentialsSecret returns the board credentials secret of a new
+// workspace: the requested one, which must exist and be usable by owner, or
+// else the one of the Jumpstarter target mapping of the board's target.
+func resolveBoardCredentialsSecret(ctx context.Context, k8sClient client.Client, namespace string,
+	operatorConfig *automotivev1alpha1.OperatorConfig, requested, target, owner string) (string, error) {
+	if requested != "" {
+		secret := &corev1.Secret{}
+		if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: requested}, secret); err != nil {
+			return "", fmt.Errorf("board credentials secret %q not found", requested)
+		}
+		if err := checkBoardCredentialsSecret(secret, owner, operatorConfig); err != nil {
+			return "", err
+		}
+		return requested, nil
+	}
+	if target == "" || operatorConfig == nil || operatorConfig.Spec.Jumpstarter == nil {
+		return "", nil
+	}
+	return operatorConfig.Spec.Jumpstarter.TargetMappings[target].BoardCredentialsSecretRef, nil
+}
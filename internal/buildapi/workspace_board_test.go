@@ -0,0 +1,257 @@
+package buildapi
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+	"github.com/gin-gonic/gin"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+)
+
+var _ = Describe("Workspace Board Credentials", func() {
+	const hostKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIHostKey"
+
+	newFakeClient := func(objs ...ctrlclient.Object) ctrlclient.Client {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(automotivev1alpha1.AddToScheme(scheme)).To(Succeed())
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	}
+
+	newSecret := func(name string, data map[string]string) *corev1.Secret {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "test-ns",
+				Labels:      map[string]string{labels.ResourceType: boardCredentialsResourceType},
+				Annotations: map[string]string{labels.RequestedBy: "alice"},
+			},
+			Data: map[string][]byte{},
+		}
+		for k, v := range data {
+			secret.Data[k] = []byte(v)
+		}
+		return secret
+	}
+
+	newWorkspace := func(secretRef string) *automotivev1alpha1.Workspace {
+		return &automotivev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "dev", Namespace: "test-ns"},
+			Spec: automotivev1alpha1.WorkspaceSpec{
+				Owner:                     "alice",
+				LeaseID:                   "0198-lease",
+				BoardCredentialsSecretRef: secretRef,
+			},
+		}
+	}
+
+	It("should default to the workspace key as root", func() {
+		creds, err := loadBoardCredentials(context.Background(), newFakeClient(), newWorkspace(""))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(creds.Username).To(Equal("root"))
+		Expect(creds.Password).To(BeEmpty())
+		Expect(creds.PrivateKey).To(BeEmpty())
+	})
+
+	It("should load the credentials secret", func() {
+		k8sClient := newFakeClient(newSecret("board-creds", map[string]string{
+			"username":       "admin",
+			"password":       "s3cret",
+			"ssh-privatekey": "PRIVATE KEY",
+			"host-keys":      "# lab boards\n" + hostKey + " root@board\n[board]:22 ecdsa-sha2-nistp256 AAAAE2Vj\n",
+		}))
+
+		creds, err := loadBoardCredentials(context.Background(), k8sClient, newWorkspace("board-creds"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(creds.Username).To(Equal("admin"))
+		Expect(creds.Password).To(Equal("s3cret"))
+		Expect(string(creds.PrivateKey)).To(Equal("PRIVATE KEY"))
+		Expect(creds.HostKeys).To(Equal([]string{hostKey, "ecdsa-sha2-nistp256 AAAAE2Vj"}))
+
+		input, err := io.ReadAll(boardCredentialsInput(creds))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(input)).To(Equal("s3cret\nPRIVATE KEY"))
+	})
+
+	It("should reject invalid credentials", func() {
+		for _, data := range []map[string]string{
+			{"username": "root; reboot"},
+			{"password": "line1\nline2"},
+			{"host-keys": "not a key"},
+		} {
+			k8sClient := newFakeClient(newSecret("board-creds", data))
+			_, err := loadBoardCredentials(context.Background(), k8sClient, newWorkspace("board-creds"))
+			Expect(err).To(HaveOccurred(), "%v", data)
+		}
+
+		_, err := loadBoardCredentials(context.Background(), newFakeClient(), newWorkspace("missing"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should only read board credentials the workspace owner may use", func() {
+		bobs := newSecret("bobs-creds", map[string]string{"password": "bobs"})
+		bobs.Annotations[labels.RequestedBy] = "bob"
+		unmarked := newSecret("opaque", map[string]string{"password": "s3cret"})
+		delete(unmarked.Labels, labels.ResourceType)
+		k8sClient := newFakeClient(bobs, unmarked)
+
+		for _, ref := range []string{"bobs-creds", "opaque"} {
+			_, err := loadBoardCredentials(context.Background(), k8sClient, newWorkspace(ref))
+			Expect(err).To(MatchError(errBoardCredentialsNotAllowed), ref)
+		}
+	})
+
+	It("should pin host keys and keep credentials off the command line", func() {
+		creds := &boardCredentials{Username: "root", Password: "s3cret", PrivateKey: []byte("PRIVATE KEY"), HostKeys: []string{hostKey}}
+		access := &boardAccess{User: "root", Alias: boardHostKeyAlias("0198/lease")}
+		Expect(access.Alias).To(Equal("board-0198_lease"))
+
+		script := authorizeBoardKeyScript(creds, access)
+		Expect(script).NotTo(ContainSubstring("s3cret"))
+		Expect(script).NotTo(ContainSubstring("PRIVATE KEY"))
+		Expect(script).To(ContainSubstring("PINNED='" + hostKey + "'"))
+		Expect(script).To(ContainSubstring("StrictHostKeyChecking=yes"))
+		Expect(script).To(ContainSubstring("HostKeyAlias=$ALIAS"))
+		Expect(script).To(ContainSubstring("sshpass -e"))
+
+		session := boardSSHScript(access)
+		Expect(session).To(ContainSubstring("BOARD='root'@127.0.0.1"))
+		Expect(session).To(ContainSubstring("-o HostKeyAlias=board-0198_lease"))
+		Expect(session).NotTo(ContainSubstring("StrictHostKeyChecking=no"))
+	})
+
+	It("should deploy with the pinned host key", func() {
+		script := deployScript(&boardAccess{User: "admin", Alias: "board-x"}, []ArtifactMapping{{Src: "build/app", Dest: "/usr/bin/app"}})
+		Expect(script).To(ContainSubstring(`rsync -avz --chmod=+x -e "$SSH_CMD $SSH_KEY" 'build/app' "$BOARD":'/usr/bin/app'`))
+		Expect(script).To(ContainSubstring("BOARD='admin'@127.0.0.1"))
+		Expect(script).NotTo(ContainSubstring("sshpass"))
+	})
+
+	Context("Workspace creation", func() {
+		var (
+			server                         *APIServer
+			originalGetClientFromRequestFn func(*gin.Context) (ctrlclient.Client, error)
+			originalLoadOperatorConfigFn   func(context.Context, ctrlclient.Client, string) (*automotivev1alpha1.OperatorConfig, error)
+			originalNamespace              string
+			hasOriginalNamespace           bool
+		)
+
+		BeforeEach(func() {
+			gin.SetMode(gin.TestMode)
+			server = NewAPIServer(":0", logr.Discard())
+			originalGetClientFromRequestFn = getClientFromRequestFn
+			originalLoadOperatorConfigFn = loadOperatorConfigFn
+			loadOperatorConfigFn = func(_ context.Context, _ ctrlclient.Client, _ string) (*automotivev1alpha1.OperatorConfig, error) {
+				return &automotivev1alpha1.OperatorConfig{
+					Spec: automotivev1alpha1.OperatorConfigSpec{
+						Jumpstarter: &automotivev1alpha1.JumpstarterConfig{
+							TargetMappings: map[string]automotivev1alpha1.JumpstarterTargetMapping{
+								"j784s4evm": {Selector: "board-type=j784s4evm", BoardCredentialsSecretRef: "j784s4evm-creds"},
+							},
+						},
+					},
+				}, nil
+			}
+			originalNamespace, hasOriginalNamespace = os.LookupEnv("BUILD_API_NAMESPACE")
+			Expect(os.Setenv("BUILD_API_NAMESPACE", "test-ns")).To(Succeed())
+		})
+
+		AfterEach(func() {
+			getClientFromRequestFn = originalGetClientFromRequestFn
+			loadOperatorConfigFn = originalLoadOperatorConfigFn
+			if hasOriginalNamespace {
+				Expect(os.Setenv("BUILD_API_NAMESPACE", originalNamespace)).To(Succeed())
+			} else {
+				Expect(os.Unsetenv("BUILD_API_NAMESPACE")).To(Succeed())
+			}
+		})
+
+		create := func(k8sClient ctrlclient.Client, body string) (*httptest.ResponseRecorder, *automotivev1alpha1.Workspace) {
+			getClientFromRequestFn = func(_ *gin.Context) (ctrlclient.Client, error) {
+				return k8sClient, nil
+			}
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/v1/workspaces", bytes.NewReader([]byte(body)))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("requester", "alice")
+			server.createWorkspace(c)
+
+			ws := &automotivev1alpha1.Workspace{}
+			if w.Code == http.StatusCreated {
+				Expect(k8sClient.Get(context.Background(), ctrlclient.ObjectKey{Namespace: "test-ns", Name: "dev"}, ws)).To(Succeed())
+			}
+			return w, ws
+		}
+
+		It("should use the credentials of the build's target", func() {
+			build := &automotivev1alpha1.ImageBuild{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "os-build",
+					Namespace:   "test-ns",
+					Annotations: map[string]string{labels.RequestedBy: "alice"},
+				},
+				Spec:   automotivev1alpha1.ImageBuildSpec{AIB: &automotivev1alpha1.AIBSpec{Target: "j784s4evm"}},
+				Status: automotivev1alpha1.ImageBuildStatus{LeaseID: "lease-1"},
+			}
+			w, ws := create(newFakeClient(build), `{"name":"dev","fromBuild":"os-build"}`)
+			Expect(w.Code).To(Equal(http.StatusCreated), w.Body.String())
+			Expect(ws.Spec.LeaseID).To(Equal("lease-1"))
+			Expect(ws.Spec.BoardCredentialsSecretRef).To(Equal("j784s4evm-creds"))
+		})
+
+		It("should prefer the requested credentials secret", func() {
+			w, ws := create(newFakeClient(newSecret("my-creds", nil)),
+				`{"name":"dev","lease":"lease-1","target":"j784s4evm","boardCredentials":"my-creds"}`)
+			Expect(w.Code).To(Equal(http.StatusCreated), w.Body.String())
+			Expect(ws.Spec.BoardCredentialsSecretRef).To(Equal("my-creds"))
+
+			w, _ = create(newFakeClient(), `{"name":"dev","lease":"lease-1","boardCredentials":"missing"}`)
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should accept the credentials of a target mapping", func() {
+			shared := newSecret("j784s4evm-creds", nil)
+			shared.Annotations = nil
+			w, ws := create(newFakeClient(shared), `{"name":"dev","lease":"lease-1","boardCredentials":"j784s4evm-creds"}`)
+			Expect(w.Code).To(Equal(http.StatusCreated), w.Body.String())
+			Expect(ws.Spec.BoardCredentialsSecretRef).To(Equal("j784s4evm-creds"))
+		})
+
+		It("should refuse another user's secrets", func() {
+			bobsKey := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "bobs-ws-ssh-key",
+					Namespace:   "test-ns",
+					Labels:      map[string]string{labels.Workspace: "bobs-ws"},
+					Annotations: map[string]string{labels.RequestedBy: "bob"},
+				},
+				Type: corev1.SecretTypeSSHAuth,
+				Data: map[string][]byte{corev1.SSHAuthPrivateKey: []byte("BOBS KEY")},
+			}
+			bobsCreds := newSecret("bobs-creds", nil)
+			bobsCreds.Annotations[labels.RequestedBy] = "bob"
+
+			for _, ref := range []string{"bobs-ws-ssh-key", "bobs-creds"} {
+				w, _ := create(newFakeClient(bobsKey, bobsCreds),
+					`{"name":"dev","lease":"lease-1","boardCredentials":"`+ref+`"}`)
+				Expect(w.Code).To(Equal(http.StatusForbidden), ref)
+				Expect(w.Body.String()).To(ContainSubstring(reasonNotOwner))
+			}
+		})
+	})
+})
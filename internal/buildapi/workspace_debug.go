@@ -0,0 +1,185 @@
+package buildapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// DebugModeGDB runs an interactive gdb session on a TTY.
+	DebugModeGDB = "gdb"
+	// DebugModeDAP runs gdb as a Debug Adapter Protocol server on stdin/stdout.
+	DebugModeDAP = "dap"
+
+	// DefaultGDBServerPort is the port gdbserver listens on, on the board and
+	// forwarded to the same port in the workspace pod.
+	DefaultGDBServerPort = 2345
+)
+
+// WorkspaceDebugRequest describes a debug session. It is sent as query
+// parameters of the debug WebSocket.
+type WorkspaceDebugRequest struct {
+	Binary  string   // Path of the program on the board
+	Args    []string // Program arguments
+	Symbols string   // Unstripped binary in the workspace; read from the board if empty
+	Port    int      // gdbserver port, DefaultGDBServerPort if zero
+	Mode    string   // DebugModeGDB (default) or DebugModeDAP
+}
+
+// QueryValues encodes the request as WebSocket query parameters.
+func (r WorkspaceDebugRequest) QueryValues() url.Values {
+	q := url.Values{"binary": {r.Binary}}
+	if len(r.Args) > 0 {
+		q["arg"] = r.Args
+	}
+	if r.Symbols != "" {
+		q["symbols"] = []string{r.Symbols}
+	}
+	if r.Port != 0 {
+		q["port"] = []string{strconv.Itoa(r.Port)}
+	}
+	if r.Mode != "" {
+		q["mode"] = []string{r.Mode}
+	}
+	return q
+}
+
+// parseDebugRequest reads and validates a debug request from the query.
+func parseDebugRequest(c *gin.Context) (*WorkspaceDebugRequest, error) {
+	req := &WorkspaceDebugRequest{
+		Binary: c.Query("binary"),
+		Args:   c.QueryArray("arg"),
+		Mode:   c.DefaultQuery("mode", DebugModeGDB),
+		Port:   DefaultGDBServerPort,
+	}
+	if req.Binary == "" || !path.IsAbs(req.Binary) {
+		return nil, fmt.Errorf("binary must be an absolute path on the board")
+	}
+	if req.Mode != DebugModeGDB && req.Mode != DebugModeDAP {
+		return nil, fmt.Errorf("mode must be %s or %s", DebugModeGDB, DebugModeDAP)
+	}
+	if p := c.Query("port"); p != "" {
+		port, err := parseForwardPort(p)
+		if err != nil {
+			return nil, err
+		}
+		req.Port = port
+	}
+	if s := c.Query("symbols"); s != "" {
+		symbols, err := resolveWorkspacePath(s)
+		if err != nil {
+			return nil, err
+		}
+		req.Symbols = symbols
+	}
+	return req, nil
+}
+
+// debugCommand starts gdbserver on the board, forwards its port into the
+// workspace pod over SSH and runs gdb against it. gdbserver and the forward
+// are stopped when gdb exits.
+func debugCommand(req *WorkspaceDebugRequest, board *boardAccess) []string {
+	remote := []string{"gdbserver", "--once", fmt.Sprintf("127.0.0.1:%d", req.Port), shellQuote(req.Binary)}
+	for _, arg := range req.Args {
+		remote = append(remote, shellQuote(arg))
+	}
+
+	// Without symbols gdb reads the program from the board through gdbserver
+	gdb := []string{"gdb", "-q"}
+	if req.Mode == DebugModeDAP {
+		gdb = append(gdb, "-i=dap")
+	}
+	gdb = append(gdb, "-ex", shellQuote("set sysroot target:"))
+	if req.Mode == DebugModeGDB {
+		gdb = append(gdb, "-ex", shellQuote(fmt.Sprintf("target remote 127.0.0.1:%d", req.Port)))
+	}
+	if req.Symbols != "" {
+		gdb = append(gdb, shellQuote(req.Symbols))
+	}
+
+	script := boardSSHScript(board) + fmt.Sprintf(`if ! $SSH_CMD $SSH_KEY "$BOARD" 'command -v gdbserver' </dev/null >/dev/null 2>&1; then
+  echo "gdbserver is not installed on the board" >&2; exit 1
+fi
+LOG=$(mktemp)
+# The remote command is a single argument, as ssh joins its arguments with spaces
+$SSH_CMD $SSH_KEY -o ExitOnForwardFailure=yes -L 127.0.0.1:%[1]d:127.0.0.1:%[1]d "$BOARD" %[2]s </dev/null >"$LOG" 2>&1 &
+SSH_PID=$!
+trap 'kill $SSH_PID 2>/dev/null; rm -f "$LOG"' EXIT
+for i in $(seq 1 30); do
+  grep -q "Listening on port" "$LOG" && break
+  if ! kill -0 $SSH_PID 2>/dev/null; then cat "$LOG" >&2; exit 1; fi
+  sleep 1
+done
+if ! grep -q "Listening on port" "$LOG"; then echo "timed out waiting for gdbserver" >&2; cat "$LOG" >&2; exit 1; fi
+%[3]s
+`, req.Port, shellQuote(strings.Join(remote, " ")), strings.Join(gdb, " "))
+	return []string{"/bin/bash", "-c", script}
+}
+
+// debugWorkspace runs a gdb session against a program on the leased board
+// over a WebSocket: an interactive gdb on a TTY, or a DAP server for IDEs.
+func (a *APIServer) debugWorkspace(c *gin.Context, name string) {
+	req, err := parseDebugRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ws, err := a.getOwnedWorkspace(c, name)
+	if err != nil {
+		return
+	}
+	if ws.Status.Phase != phaseRunning {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("workspace %q is not running (phase: %s)", name, ws.Status.Phase)})
+		return
+	}
+	if ws.Spec.LeaseID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no Jumpstarter lease associated with this workspace"})
+		return
+	}
+	a.touchWorkspaceActivity(c, ws)
+
+	restCfg, err := getRESTConfigFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get kubernetes config"})
+		return
+	}
+
+	k8sClient, err := getK8sClientOrFail(c)
+	if err != nil {
+		return
+	}
+	access, err := prepareBoardAccess(c.Request.Context(), k8sClient, restCfg, ws)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	wsConn, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		a.log.Error(err, "websocket upgrade failed", "workspace", name)
+		return
+	}
+	defer func() { _ = wsConn.Close() }()
+
+	// The DAP stream must stay free of anything but protocol messages, so
+	// only the interactive session runs on a TTY
+	err = relayWebSocketExec(c.Request.Context(), restCfg, ws.Namespace, ws.Status.PodName, workspaceContainerName,
+		debugCommand(req, access), req.Mode == DebugModeGDB, wsConn)
+
+	closeMsg := "debug session ended"
+	closeCode := websocket.CloseNormalClosure
+	if err != nil {
+		a.log.Error(err, "debug session ended with error", "workspace", name, "binary", req.Binary)
+		closeMsg = truncateCloseReason(err.Error())
+		closeCode = websocket.CloseInternalServerErr
+	}
+	_ = wsConn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(closeCode, closeMsg))
+}
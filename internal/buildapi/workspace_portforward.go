@@ -0,0 +1,228 @@
+package buildapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// portRelayScript relays stdin/stdout to a TCP port of the workspace pod.
+// python3 is part of the toolchain image; bash /dev/tcp cannot half-close.
+const portRelayScript = `import os, socket, sys, threading
+try:
+    s = socket.create_connection(("127.0.0.1", int(sys.argv[1])))
+except OSError as e:
+    sys.stderr.write("connect to port %s failed: %s\n" % (sys.argv[1], e.strerror))
+    sys.exit(1)
+def upstream():
+    while True:
+        b = os.read(0, 65536)
+        if not b:
+            break
+        s.sendall(b)
+    s.shutdown(socket.SHUT_WR)
+threading.Thread(target=upstream, daemon=True).start()
+while True:
+    b = s.recv(65536)
+    if not b:
+        break
+    os.write(1, b)
+`
+
+// portForwardCommand returns the command that connects its stdin/stdout to
+// the given port, of the leased board if board is set or else of the
+// workspace pod.
+func portForwardCommand(port int, board *boardAccess) []string {
+	if board == nil {
+		return []string{"python3", "-c", portRelayScript, strconv.Itoa(port)}
+	}
+	// Let ssh relay the connection to the board (-W)
+	script := boardSSHScript(board) + fmt.Sprintf(`exec $SSH_CMD $SSH_KEY -W 127.0.0.1:%d "$BOARD"`, port)
+	return []string{"/bin/sh", "-c", script}
+}
+
+// parseForwardPort validates the remote port of a port-forward request.
+func parseForwardPort(s string) (int, error) {
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("port must be a number between 1 and 65535, got %q", s)
+	}
+	return port, nil
+}
+
+// portForwardWorkspace relays one TCP connection over a WebSocket to a port
+// of the workspace pod, or of the leased board when board=true. Clients open
+// one WebSocket per forwarded connection.
+func (a *APIServer) portForwardWorkspace(c *gin.Context, name string) {
+	port, err := parseForwardPort(c.Query("port"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	board := c.Query("board") == "true"
+
+	ws, err := a.getOwnedWorkspace(c, name)
+	if err != nil {
+		return
+	}
+	if ws.Status.Phase != phaseRunning {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("workspace %q is not running (phase: %s)", name, ws.Status.Phase)})
+		return
+	}
+	if board && ws.Spec.LeaseID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no Jumpstarter lease associated with this workspace"})
+		return
+	}
+	a.touchWorkspaceActivity(c, ws)
+
+	restCfg, err := getRESTConfigFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get kubernetes config"})
+		return
+	}
+
+	var access *boardAccess
+	if board {
+		k8sClient, err := getK8sClientOrFail(c)
+		if err != nil {
+			return
+		}
+		access, err = prepareBoardAccess(c.Request.Context(), k8sClient, restCfg, ws)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	wsConn, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		a.log.Error(err, "websocket upgrade failed", "workspace", name)
+		return
+	}
+	defer func() { _ = wsConn.Close() }()
+
+	err = relayWebSocketExec(c.Request.Context(), restCfg, ws.Namespace, ws.Status.PodName, workspaceContainerName,
+		portForwardCommand(port, access), false, wsConn)
+
+	closeMsg := "connection closed"
+	closeCode := websocket.CloseNormalClosure
+	if err != nil {
+		closeMsg = truncateCloseReason(err.Error())
+		closeCode = websocket.CloseInternalServerErr
+	}
+	_ = wsConn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(closeCode, closeMsg))
+}
+
+// relayWebSocketExec runs cmd in a pod and relays WebSocket binary messages
+// to its stdin and its stdout back as binary messages. Without a TTY, stderr
+// is returned as part of the error; with one, it is part of the output.
+//
+// The client half-closes the connection with an empty message rather than a
+// close frame, since a close frame ends the WebSocket in both directions.
+func relayWebSocketExec(ctx context.Context, restCfg *rest.Config, namespace, podName, containerName string,
+	cmd []string, tty bool, wsConn *websocket.Conn) error {
+	clientset, err := kubernetes.NewForConfig(restCfg)
+	if err != nil {
+		return fmt.Errorf("creating clientset: %w", err)
+	}
+
+	execReq := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").Name(podName).Namespace(namespace).SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: containerName,
+			Command:   cmd,
+			Stdin:     true,
+			Stdout:    true,
+			Stderr:    !tty,
+			TTY:       tty,
+		}, kscheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(restCfg, http.MethodPost, execReq.URL())
+	if err != nil {
+		return fmt.Errorf("creating executor: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stdinR, stdinW := io.Pipe()
+	stdoutR, stdoutW := io.Pipe()
+
+	// WS -> stdin. An empty message is a half-close: the relay's input ends
+	// but its output is still delivered. Losing the client stops the relay.
+	go func() {
+		defer func() { _ = stdinW.Close() }()
+		for {
+			_, msg, rerr := wsConn.ReadMessage()
+			if rerr != nil {
+				cancel()
+				return
+			}
+			if len(msg) == 0 {
+				_ = stdinW.Close()
+				continue
+			}
+			// Fails once the input is closed; keep reading to notice the client going away
+			_, _ = stdinW.Write(msg)
+		}
+	}()
+
+	// stdout -> WS
+	stdoutDone := make(chan struct{})
+	go func() {
+		defer close(stdoutDone)
+		buf := make([]byte, 32*1024)
+		for {
+			n, rerr := stdoutR.Read(buf)
+			if n > 0 {
+				if werr := wsConn.WriteMessage(websocket.BinaryMessage, buf[:n]); werr != nil {
+					_ = stdoutR.CloseWithError(werr)
+					return
+				}
+			}
+			if rerr != nil {
+				return
+			}
+		}
+	}()
+
+	var stderr bytes.Buffer
+	streamOpts := remotecommand.StreamOptions{Stdin: stdinR, Stdout: stdoutW, Tty: tty}
+	if !tty {
+		streamOpts.Stderr = &stderr
+	}
+	err = executor.StreamWithContext(ctx, streamOpts)
+	_ = stdoutW.Close()
+	<-stdoutDone
+	_ = stdinR.Close()
+
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s", msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// truncateCloseReason keeps a WebSocket close reason within the 123 bytes
+// allowed by RFC 6455.
+func truncateCloseReason(s string) string {
+	const maxLen = 123
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen-3] + "..."
+}
@@ -0,0 +1,260 @@
+package buildapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+)
+
+// WorkspaceSnapshotRequest is the payload to snapshot a workspace.
+type WorkspaceSnapshotRequest struct {
+	Name   string `json:"name,omitempty"`   // Defaults to <workspace>-<timestamp>
+	Method string `json:"method,omitempty"` // VolumeSnapshot, OCI, or empty to pick from the storage class
+	TTL    string `json:"ttl,omitempty"`    // Go duration, "0" disables expiry
+}
+
+// WorkspaceSnapshotResponse is returned by workspace snapshot operations.
+type WorkspaceSnapshotResponse struct {
+	Name         string `json:"name"`
+	Workspace    string `json:"workspace"`
+	Phase        string `json:"phase"`
+	Message      string `json:"message,omitempty"`
+	Method       string `json:"method,omitempty"`
+	ArtifactRef  string `json:"artifactRef,omitempty"`
+	Arch         string `json:"architecture,omitempty"`
+	PVCSize      string `json:"pvcSize,omitempty"`
+	Age          string `json:"age,omitempty"`
+	ExpiresAt    string `json:"expiresAt,omitempty"`
+	CreationTime string `json:"creationTime,omitempty"`
+}
+
+// registerWorkspaceSnapshotRoutes registers the workspace snapshot API routes on the v1 group.
+// Snapshots are created through POST /v1/workspaces/:name/snapshots.
+func (a *APIServer) registerWorkspaceSnapshotRoutes(v1 *gin.RouterGroup) {
+	snapshotGroup := v1.Group("/workspace-snapshots")
+	snapshotGroup.Use(a.authMiddleware(), a.authorizeMiddleware())
+	{
+		snapshotGroup.GET("", a.wrapHandler("list workspace snapshots", a.listWorkspaceSnapshots))
+		snapshotGroup.GET("/:name", a.wrapNamedHandler("get workspace snapshot", a.getWorkspaceSnapshot))
+		snapshotGroup.DELETE("/:name", a.wrapNamedHandler("delete workspace snapshot", a.deleteWorkspaceSnapshot))
+	}
+}
+
+func (a *APIServer) createWorkspaceSnapshot(c *gin.Context, name string) {
+	var req WorkspaceSnapshotRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON request"})
+			return
+		}
+	}
+	switch req.Method {
+	case "", automotivev1alpha1.SnapshotMethodVolumeSnapshot, automotivev1alpha1.SnapshotMethodOCI:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf(
+			"method must be %s or %s", automotivev1alpha1.SnapshotMethodVolumeSnapshot, automotivev1alpha1.SnapshotMethodOCI)})
+		return
+	}
+	snapshotName := strings.TrimSpace(req.Name)
+	if snapshotName == "" {
+		snapshotName = fmt.Sprintf("%s-%s", name, time.Now().UTC().Format("20060102-150405"))
+	}
+	if errs := validation.IsDNS1123Subdomain(snapshotName); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid snapshot name %q: %s", snapshotName, strings.Join(errs, ", "))})
+		return
+	}
+
+	ws, err := a.getOwnedWorkspace(c, name)
+	if err != nil {
+		return // response already sent
+	}
+	k8sClient, err := getK8sClientOrFail(c)
+	if err != nil {
+		return
+	}
+
+	ttl, err := resolveAndClampTTL(c.Request.Context(), k8sClient, resolveNamespace(), req.TTL)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	snapshot := &automotivev1alpha1.WorkspaceSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      snapshotName,
+			Namespace: ws.Namespace,
+			Labels: map[string]string{
+				labels.ManagedBy:                      labels.ValueBuildAPI,
+				automotivev1alpha1.LabelWorkspaceName: ws.Name,
+			},
+			Annotations: map[string]string{labels.RequestedBy: ws.Spec.Owner},
+		},
+		Spec: automotivev1alpha1.WorkspaceSnapshotSpec{
+			Workspace: ws.Name,
+			Owner:     ws.Spec.Owner,
+			Method:    req.Method,
+			TTL:       ttl,
+		},
+	}
+	if err := k8sClient.Create(c.Request.Context(), snapshot); err != nil {
+		if k8serrors.IsAlreadyExists(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("workspace snapshot %q already exists", snapshotName)})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to create workspace snapshot: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, workspaceSnapshotResponseFromCR(snapshot))
+}
+
+func (a *APIServer) listWorkspaceSnapshots(c *gin.Context) {
+	k8sClient, err := getK8sClientOrFail(c)
+	if err != nil {
+		return
+	}
+
+	requester := a.resolveRequester(c)
+	limit, offset := parsePagination(c)
+	workspace := c.Query("workspace")
+
+	list := &automotivev1alpha1.WorkspaceSnapshotList{}
+	if err := listInNamespaces(c.Request.Context(), c, k8sClient, list); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list workspace snapshots"})
+		return
+	}
+
+	// Sort by creation time (newest first) for stable pagination
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[j].CreationTimestamp.Before(&list.Items[i].CreationTimestamp)
+	})
+
+	owned := make([]WorkspaceSnapshotResponse, 0, len(list.Items))
+	for i := range list.Items {
+		snapshot := &list.Items[i]
+		if snapshot.Spec.Owner != requester || (workspace != "" && snapshot.Spec.Workspace != workspace) {
+			continue
+		}
+		owned = append(owned, workspaceSnapshotResponseFromCR(snapshot))
+	}
+
+	c.JSON(http.StatusOK, applyPagination(owned, limit, offset))
+}
+
+func (a *APIServer) getWorkspaceSnapshot(c *gin.Context, name string) {
+	snapshot, err := a.getOwnedWorkspaceSnapshot(c, name)
+	if err != nil {
+		return // response already sent
+	}
+	c.JSON(http.StatusOK, workspaceSnapshotResponseFromCR(snapshot))
+}
+
+func (a *APIServer) deleteWorkspaceSnapshot(c *gin.Context, name string) {
+	snapshot, err := a.getOwnedWorkspaceSnapshot(c, name)
+	if err != nil {
+		return // response already sent
+	}
+	k8sClient, err := getK8sClientOrFail(c)
+	if err != nil {
+		return
+	}
+
+	// The controller removes the VolumeSnapshot or registry artifact
+	if err := k8sClient.Delete(c.Request.Context(), snapshot); err != nil && !k8serrors.IsNotFound(err) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to delete workspace snapshot: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("workspace snapshot %q deleted", name)})
+}
+
+// getOwnedWorkspaceSnapshot fetches a snapshot owned by the requester. Snapshots
+// of other users are reported as not found.
+func (a *APIServer) getOwnedWorkspaceSnapshot(c *gin.Context, name string) (*automotivev1alpha1.WorkspaceSnapshot, error) {
+	k8sClient, err := getK8sClientOrFail(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client")
+	}
+
+	requester := a.resolveRequester(c)
+	snapshot := &automotivev1alpha1.WorkspaceSnapshot{}
+	if err := k8sClient.Get(c.Request.Context(), client.ObjectKey{Namespace: requestNamespace(c), Name: name}, snapshot); err != nil {
+		if k8serrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("workspace snapshot %q not found", name)})
+			return nil, err
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get workspace snapshot"})
+		return nil, err
+	}
+
+	if !a.canActOn(c, snapshot.Spec.Owner, automotivev1alpha1.BuildAPIRoleAdmin) {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("workspace snapshot %q not found", name)})
+		return nil, fmt.Errorf("workspace snapshot %q not owned by %q", name, requester)
+	}
+
+	return snapshot, nil
+}
+
+// resolveSnapshotForClone returns the ready snapshot a new workspace of the
+// requester is cloned from.
+func resolveSnapshotForClone(c *gin.Context, k8sClient client.Client, namespace, name, requester string) (*automotivev1alpha1.WorkspaceSnapshot, int, error) {
+	snapshot := &automotivev1alpha1.WorkspaceSnapshot{}
+	if err := k8sClient.Get(c.Request.Context(), client.ObjectKey{Namespace: namespace, Name: name}, snapshot); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, http.StatusNotFound, fmt.Errorf("workspace snapshot %q not found", name)
+		}
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get workspace snapshot: %w", err)
+	}
+	if snapshot.Spec.Owner != requester {
+		return nil, http.StatusNotFound, fmt.Errorf("workspace snapshot %q not found", name)
+	}
+	if snapshot.Status.Phase != automotivev1alpha1.WorkspaceSnapshotPhaseReady {
+		phase := snapshot.Status.Phase
+		if phase == "" {
+			phase = automotivev1alpha1.WorkspaceSnapshotPhasePending
+		}
+		return nil, http.StatusConflict, fmt.Errorf("workspace snapshot %q is not ready (phase %s)", name, phase)
+	}
+	return snapshot, 0, nil
+}
+
+func workspaceSnapshotResponseFromCR(snapshot *automotivev1alpha1.WorkspaceSnapshot) WorkspaceSnapshotResponse {
+	phase := snapshot.Status.Phase
+	if phase == "" {
+		phase = automotivev1alpha1.WorkspaceSnapshotPhasePending
+	}
+	resp := WorkspaceSnapshotResponse{
+		Name:        snapshot.Name,
+		Workspace:   snapshot.Spec.Workspace,
+		Phase:       phase,
+		Message:     snapshot.Status.Message,
+		Method:      snapshot.Status.Method,
+		ArtifactRef: snapshot.Status.ArtifactRef,
+	}
+	if resp.Method == "" {
+		resp.Method = snapshot.Spec.Method
+	}
+	if src := snapshot.Status.Source; src != nil {
+		resp.Arch = src.Architecture
+		resp.PVCSize = src.PVCSize
+	}
+	if !snapshot.CreationTimestamp.IsZero() {
+		resp.CreationTime = snapshot.CreationTimestamp.UTC().Format(time.RFC3339)
+		resp.Age = time.Since(snapshot.CreationTimestamp.Time).Truncate(time.Second).String()
+	}
+	if snapshot.Status.ExpiresAt != nil {
+		resp.ExpiresAt = snapshot.Status.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	return resp
+}
@@ -0,0 +1,214 @@
+package buildapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/gin-gonic/gin"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+)
+
+var _ = Describe("Workspace Snapshots", func() {
+	var (
+		server                         *APIServer
+		originalGetClientFromRequestFn func(*gin.Context) (ctrlclient.Client, error)
+		originalLoadOperatorConfigFn   func(context.Context, ctrlclient.Client, string) (*automotivev1alpha1.OperatorConfig, error)
+		originalNamespace              string
+		hasOriginalNamespace           bool
+	)
+
+	newWorkspace := func(name, owner string) *automotivev1alpha1.Workspace {
+		return &automotivev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-ns"},
+			Spec:       automotivev1alpha1.WorkspaceSpec{Owner: owner, Architecture: "amd64"},
+		}
+	}
+
+	newSnapshot := func(name, owner, phase string) *automotivev1alpha1.WorkspaceSnapshot {
+		return &automotivev1alpha1.WorkspaceSnapshot{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-ns"},
+			Spec:       automotivev1alpha1.WorkspaceSnapshotSpec{Workspace: "dev", Owner: owner},
+			Status: automotivev1alpha1.WorkspaceSnapshotStatus{
+				Phase:              phase,
+				Method:             automotivev1alpha1.SnapshotMethodVolumeSnapshot,
+				VolumeSnapshotName: name,
+				Source: &automotivev1alpha1.WorkspaceSnapshotSource{
+					Architecture: "arm64",
+					Image:        automotivev1alpha1.DefaultToolchainImage,
+					PVCSize:      "200Gi",
+					StorageClass: "ceph-rbd",
+				},
+			},
+		}
+	}
+
+	newFakeClient := func(objs ...ctrlclient.Object) ctrlclient.Client {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(automotivev1alpha1.AddToScheme(scheme)).To(Succeed())
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	}
+
+	useClient := func(k8sClient ctrlclient.Client) {
+		getClientFromRequestFn = func(_ *gin.Context) (ctrlclient.Client, error) {
+			return k8sClient, nil
+		}
+	}
+
+	newContext := func(method, target string, body []byte) (*gin.Context, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(method, target, bytes.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("requester", "alice")
+		return c, w
+	}
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		server = NewAPIServer(":0", logr.Discard())
+		originalGetClientFromRequestFn = getClientFromRequestFn
+		originalLoadOperatorConfigFn = loadOperatorConfigFn
+		loadOperatorConfigFn = func(_ context.Context, _ ctrlclient.Client, _ string) (*automotivev1alpha1.OperatorConfig, error) {
+			return nil, nil
+		}
+		originalNamespace, hasOriginalNamespace = os.LookupEnv("BUILD_API_NAMESPACE")
+		Expect(os.Setenv("BUILD_API_NAMESPACE", "test-ns")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		getClientFromRequestFn = originalGetClientFromRequestFn
+		loadOperatorConfigFn = originalLoadOperatorConfigFn
+		if hasOriginalNamespace {
+			Expect(os.Setenv("BUILD_API_NAMESPACE", originalNamespace)).To(Succeed())
+		} else {
+			Expect(os.Unsetenv("BUILD_API_NAMESPACE")).To(Succeed())
+		}
+	})
+
+	It("should require authentication for all snapshot endpoints", func() {
+		for _, tc := range []struct{ method, path string }{
+			{"POST", "/v1/workspaces/dev/snapshots"},
+			{"GET", "/v1/workspace-snapshots"},
+			{"GET", "/v1/workspace-snapshots/dev-snap"},
+			{"DELETE", "/v1/workspace-snapshots/dev-snap"},
+		} {
+			req, err := http.NewRequest(tc.method, tc.path, nil)
+			Expect(err).NotTo(HaveOccurred())
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusUnauthorized), "%s %s", tc.method, tc.path)
+		}
+	})
+
+	It("should snapshot an owned workspace", func() {
+		k8sClient := newFakeClient(newWorkspace("dev", "alice"))
+		useClient(k8sClient)
+
+		c, w := newContext(http.MethodPost, "/v1/workspaces/dev/snapshots",
+			[]byte(`{"name":"dev-snap","method":"OCI","ttl":"24h"}`))
+		server.createWorkspaceSnapshot(c, "dev")
+
+		Expect(w.Code).To(Equal(http.StatusCreated))
+		snapshot := &automotivev1alpha1.WorkspaceSnapshot{}
+		Expect(k8sClient.Get(context.Background(), ctrlclient.ObjectKey{Namespace: "test-ns", Name: "dev-snap"}, snapshot)).To(Succeed())
+		Expect(snapshot.Spec.Workspace).To(Equal("dev"))
+		Expect(snapshot.Spec.Owner).To(Equal("alice"))
+		Expect(snapshot.Spec.Method).To(Equal(automotivev1alpha1.SnapshotMethodOCI))
+		Expect(snapshot.Spec.TTL).To(Equal("24h"))
+	})
+
+	It("should reject invalid snapshot requests", func() {
+		useClient(newFakeClient(newWorkspace("dev", "alice"), newWorkspace("other", "bob")))
+
+		c, w := newContext(http.MethodPost, "/v1/workspaces/dev/snapshots", []byte(`{"method":"Rsync"}`))
+		server.createWorkspaceSnapshot(c, "dev")
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+
+		c, w = newContext(http.MethodPost, "/v1/workspaces/dev/snapshots", []byte(`{"ttl":"soon"}`))
+		server.createWorkspaceSnapshot(c, "dev")
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+
+		c, w = newContext(http.MethodPost, "/v1/workspaces/other/snapshots", nil)
+		server.createWorkspaceSnapshot(c, "other")
+		Expect(w.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should list only the requester's snapshots", func() {
+		useClient(newFakeClient(
+			newSnapshot("snap-1", "alice", automotivev1alpha1.WorkspaceSnapshotPhaseReady),
+			newSnapshot("snap-2", "bob", automotivev1alpha1.WorkspaceSnapshotPhaseReady),
+		))
+
+		c, w := newContext(http.MethodGet, "/v1/workspace-snapshots", nil)
+		server.listWorkspaceSnapshots(c)
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var snapshots []WorkspaceSnapshotResponse
+		Expect(json.Unmarshal(w.Body.Bytes(), &snapshots)).To(Succeed())
+		Expect(snapshots).To(HaveLen(1))
+		Expect(snapshots[0].Name).To(Equal("snap-1"))
+		Expect(snapshots[0].Arch).To(Equal("arm64"))
+
+		c, w = newContext(http.MethodGet, "/v1/workspace-snapshots/snap-2", nil)
+		server.getWorkspaceSnapshot(c, "snap-2")
+		Expect(w.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should delete an owned snapshot", func() {
+		k8sClient := newFakeClient(newSnapshot("snap-1", "alice", automotivev1alpha1.WorkspaceSnapshotPhaseReady))
+		useClient(k8sClient)
+
+		c, w := newContext(http.MethodDelete, "/v1/workspace-snapshots/snap-1", nil)
+		server.deleteWorkspaceSnapshot(c, "snap-1")
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		err := k8sClient.Get(context.Background(), ctrlclient.ObjectKey{Namespace: "test-ns", Name: "snap-1"},
+			&automotivev1alpha1.WorkspaceSnapshot{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should clone a workspace from a ready snapshot", func() {
+		k8sClient := newFakeClient(newSnapshot("snap-1", "alice", automotivev1alpha1.WorkspaceSnapshotPhaseReady))
+		useClient(k8sClient)
+
+		c, w := newContext(http.MethodPost, "/v1/workspaces", []byte(`{"name":"clone","fromSnapshot":"snap-1"}`))
+		server.createWorkspace(c)
+
+		Expect(w.Code).To(Equal(http.StatusCreated))
+		ws := &automotivev1alpha1.Workspace{}
+		Expect(k8sClient.Get(context.Background(), ctrlclient.ObjectKey{Namespace: "test-ns", Name: "clone"}, ws)).To(Succeed())
+		Expect(ws.Spec.FromSnapshot).To(Equal("snap-1"))
+		Expect(ws.Spec.Architecture).To(Equal("arm64"))
+		Expect(ws.Spec.PVCSize).To(Equal("200Gi"))
+		Expect(ws.Spec.StorageClass).To(Equal("ceph-rbd"))
+	})
+
+	It("should refuse to clone from a snapshot that is not ready or not owned", func() {
+		useClient(newFakeClient(
+			newSnapshot("snap-1", "alice", automotivev1alpha1.WorkspaceSnapshotPhaseCreating),
+			newSnapshot("snap-2", "bob", automotivev1alpha1.WorkspaceSnapshotPhaseReady),
+		))
+
+		c, w := newContext(http.MethodPost, "/v1/workspaces", []byte(`{"name":"clone","fromSnapshot":"snap-1"}`))
+		server.createWorkspace(c)
+		Expect(w.Code).To(Equal(http.StatusConflict))
+
+		c, w = newContext(http.MethodPost, "/v1/workspaces", []byte(`{"name":"clone","fromSnapshot":"snap-2"}`))
+		server.createWorkspace(c)
+		Expect(w.Code).To(Equal(http.StatusNotFound))
+	})
+})
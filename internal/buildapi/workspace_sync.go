@@ -0,0 +1,233 @@
+package buildapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+const (
+	// MaxSyncUploadSize is the largest tar archive accepted by a single sync upload.
+	// Clients split bigger trees into several uploads.
+	MaxSyncUploadSize int64 = 512 << 20 // 512 MiB
+
+	workspaceSrcDir = "/workspace/src"
+	workspaceRoot   = "/workspace"
+
+	// syncManifestPath records the files of the last completed sync, so that
+	// files removed locally can be removed from the workspace on the next one.
+	// It lives outside /workspace/src so that it is never synced itself.
+	syncManifestPath = "/workspace/.caib-sync-manifest"
+)
+
+// SyncFinishRequest completes a sync once all changed files are uploaded.
+type SyncFinishRequest struct {
+	Files  []string `json:"files"`            // every file of the local tree, relative to /workspace/src
+	Delete []string `json:"delete,omitempty"` // files to remove from the workspace
+}
+
+// SyncFinishResponse reports the outcome of a completed sync.
+type SyncFinishResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+func (a *APIServer) syncFinishWorkspace(c *gin.Context, name string) {
+	var req SyncFinishRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON request"})
+		return
+	}
+	for _, p := range append(append([]string{}, req.Files...), req.Delete...) {
+		if err := validateSyncPath(p); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ws, err := a.getOwnedWorkspace(c, name)
+	if err != nil {
+		return
+	}
+	if ws.Status.Phase != phaseRunning {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("workspace %q is not running (phase: %s)", name, ws.Status.Phase)})
+		return
+	}
+	a.touchWorkspaceActivity(c, ws)
+
+	restCfg, err := getRESTConfigFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get kubernetes config"})
+		return
+	}
+	ctx := c.Request.Context()
+
+	// Paths are passed on stdin rather than in the script, so that large trees
+	// do not run into the argument length limit.
+	if len(req.Delete) > 0 {
+		script := "cd " + workspaceSrcDir + " || exit 1\n" +
+			"list=$(mktemp) && cat > \"$list\"\n" +
+			"xargs -r -d '\\n' rm -f -- < \"$list\"\n" +
+			// Remove directories left empty by the deletions
+			"sed -n 's|/[^/]*$||p' \"$list\" | sort -u | xargs -r -d '\\n' rmdir -p --ignore-fail-on-non-empty -- 2>/dev/null\n" +
+			"rm -f \"$list\"\n"
+		stdin := strings.NewReader(strings.Join(req.Delete, "\n") + "\n")
+		if err := podExecStreams(ctx, restCfg, ws.Namespace, ws.Status.PodName, workspaceContainerName,
+			[]string{"/bin/sh", "-c", script}, stdin, io.Discard); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to delete files: %v", err)})
+			return
+		}
+	}
+
+	files := append([]string{}, req.Files...)
+	sort.Strings(files)
+	script := fmt.Sprintf("cat > %[1]s.tmp && mv %[1]s.tmp %[1]s", syncManifestPath)
+	stdin := strings.NewReader(strings.Join(files, "\n") + "\n")
+	if err := podExecStreams(ctx, restCfg, ws.Namespace, ws.Status.PodName, workspaceContainerName,
+		[]string{"/bin/sh", "-c", script}, stdin, io.Discard); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to record sync manifest: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, SyncFinishResponse{Deleted: len(req.Delete)})
+}
+
+func (a *APIServer) pullWorkspace(c *gin.Context, name string) {
+	remotePath, err := resolveWorkspacePath(c.Query("path"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ws, err := a.getOwnedWorkspace(c, name)
+	if err != nil {
+		return
+	}
+	if ws.Status.Phase != phaseRunning {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("workspace %q is not running (phase: %s)", name, ws.Status.Phase)})
+		return
+	}
+	a.touchWorkspaceActivity(c, ws)
+
+	restCfg, err := getRESTConfigFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get kubernetes config"})
+		return
+	}
+	ctx := c.Request.Context()
+
+	// Check the path up front: once the archive starts streaming the status
+	// code can no longer be changed.
+	var out bytes.Buffer
+	check := []string{"/bin/sh", "-c", "test -e " + shellQuote(remotePath) + " && echo ok; true"}
+	if err := podExec(ctx, restCfg, ws.Namespace, ws.Status.PodName, workspaceContainerName, check, &out); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to stat %s: %v", remotePath, err)})
+		return
+	}
+	if strings.TrimSpace(out.String()) != "ok" {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("%s not found in workspace %q", remotePath, name)})
+		return
+	}
+
+	c.Header("Content-Type", "application/x-tar")
+	c.Status(http.StatusOK)
+	cmd := []string{"tar", "-cf", "-", "-C", path.Dir(remotePath), path.Base(remotePath)}
+	if remotePath == workspaceRoot {
+		cmd = []string{"tar", "-cf", "-", "-C", workspaceRoot, "."}
+	}
+	if err := podExecStreams(ctx, restCfg, ws.Namespace, ws.Status.PodName, workspaceContainerName,
+		cmd, nil, newFlushWriter(c.Writer)); err != nil {
+		// The client detects the truncated archive
+		a.log.Error(err, "workspace pull failed", "workspace", name, "path", remotePath)
+	}
+}
+
+// syncDeletedFiles returns the files recorded by the previous sync that are
+// no longer part of the local tree.
+func syncDeletedFiles(previousManifest string, files map[string]string) []string {
+	var deleted []string
+	for _, line := range strings.Split(previousManifest, "\n") {
+		if line == "" {
+			continue
+		}
+		if _, ok := files[line]; !ok {
+			deleted = append(deleted, line)
+		}
+	}
+	sort.Strings(deleted)
+	return deleted
+}
+
+// validateSyncPath rejects paths that would escape /workspace/src.
+func validateSyncPath(p string) error {
+	if p == "" || strings.ContainsAny(p, "\n\x00") {
+		return fmt.Errorf("invalid path %q", p)
+	}
+	if path.IsAbs(p) || path.Clean(p) != p || p == ".." || strings.HasPrefix(p, "../") {
+		return fmt.Errorf("path %q must be relative to %s", p, workspaceSrcDir)
+	}
+	return nil
+}
+
+// resolveWorkspacePath resolves a pull path to an absolute path inside the
+// workspace volume. Relative paths are taken from /workspace/src.
+func resolveWorkspacePath(p string) (string, error) {
+	if strings.TrimSpace(p) == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	if !path.IsAbs(p) {
+		p = path.Join(workspaceSrcDir, p)
+	}
+	p = path.Clean(p)
+	if p != workspaceRoot && !strings.HasPrefix(p, workspaceRoot+"/") {
+		return "", fmt.Errorf("path %q is outside %s", p, workspaceRoot)
+	}
+	return p, nil
+}
+
+// podExecStreams runs a command in a pod with optional stdin. Unlike podExec,
+// stderr is kept out of stdout so binary output stays intact; it is returned
+// as part of the error instead.
+func podExecStreams(ctx context.Context, restCfg *rest.Config, namespace, podName, containerName string,
+	cmd []string, stdin io.Reader, stdout io.Writer) error {
+	clientset, err := kubernetes.NewForConfig(restCfg)
+	if err != nil {
+		return fmt.Errorf("creating clientset: %w", err)
+	}
+
+	execReq := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").Name(podName).Namespace(namespace).SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: containerName,
+			Command:   cmd,
+			Stdin:     stdin != nil,
+			Stdout:    true,
+			Stderr:    true,
+			TTY:       false,
+		}, kscheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(restCfg, http.MethodPost, execReq.URL())
+	if err != nil {
+		return fmt.Errorf("creating executor: %w", err)
+	}
+
+	var stderr bytes.Buffer
+	if err := executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdin: stdin, Stdout: stdout, Stderr: &stderr}); err != nil {
+		if stderr.Len() > 0 {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return err
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-logr/logr"
@@ -41,8 +42,12 @@ var _ = Describe("Workspace API", func() {
 			{"POST", "/v1/workspaces/my-app/start"},
 			{"POST", "/v1/workspaces/my-app/stop"},
 			{"POST", "/v1/workspaces/my-app/sync"},
+			{"POST", "/v1/workspaces/my-app/sync/finish"},
+			{"GET", "/v1/workspaces/my-app/sync/pull?path=build"},
 			{"POST", "/v1/workspaces/my-app/exec"},
 			{"POST", "/v1/workspaces/my-app/deploy"},
+			{"GET", "/v1/workspaces/my-app/port-forward?port=8080"},
+			{"GET", "/v1/workspaces/my-app/debug?binary=/usr/bin/app"},
 		}
 
 		It("should require authentication for all workspace endpoints", func() {
@@ -113,4 +118,131 @@ var _ = Describe("Workspace API", func() {
 			Expect(w.Code).To(Equal(http.StatusUnauthorized))
 		})
 	})
+
+	Context("Sync Workspace", func() {
+		It("should report previously synced files that are gone locally", func() {
+			previous := "README.md\nsrc/main.c\nsrc/old.c\n"
+			deleted := syncDeletedFiles(previous, map[string]string{"README.md": "a", "src/main.c": "b", "src/new.c": "c"})
+			Expect(deleted).To(Equal([]string{"src/old.c"}))
+			Expect(syncDeletedFiles("", map[string]string{"README.md": "a"})).To(BeEmpty())
+		})
+
+		It("should only accept paths inside the source directory", func() {
+			Expect(validateSyncPath("src/main.c")).To(Succeed())
+			for _, p := range []string{"", "/etc/passwd", "../secret", "src/../../x", "./src/main.c", "a\nb"} {
+				Expect(validateSyncPath(p)).NotTo(Succeed(), "path %q", p)
+			}
+		})
+
+		It("should resolve pull paths inside the workspace volume", func() {
+			for in, want := range map[string]string{
+				"build/app":              "/workspace/src/build/app",
+				"/workspace/src/build/":  "/workspace/src/build",
+				"/workspace":             "/workspace",
+				"../.cache/ccache/stats": "/workspace/.cache/ccache/stats",
+			} {
+				got, err := resolveWorkspacePath(in)
+				Expect(err).NotTo(HaveOccurred(), "path %q", in)
+				Expect(got).To(Equal(want))
+			}
+			for _, p := range []string{"", "/etc", "../../etc", "/workspace-other"} {
+				_, err := resolveWorkspacePath(p)
+				Expect(err).To(HaveOccurred(), "path %q", p)
+			}
+		})
+	})
+
+	Context("Port Forward", func() {
+		It("should validate the remote port", func() {
+			port, err := parseForwardPort("2345")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(port).To(Equal(2345))
+			for _, p := range []string{"", "0", "65536", "http"} {
+				_, err := parseForwardPort(p)
+				Expect(err).To(HaveOccurred(), "port %q", p)
+			}
+		})
+
+		It("should relay to the pod or through SSH to the board", func() {
+			cmd := portForwardCommand(8080, nil)
+			Expect(cmd[0]).To(Equal("python3"))
+			Expect(cmd[len(cmd)-1]).To(Equal("8080"))
+
+			cmd = portForwardCommand(2345, &boardAccess{User: "root", Alias: "board-lease"})
+			Expect(cmd[len(cmd)-1]).To(ContainSubstring(`-W 127.0.0.1:2345 "$BOARD"`))
+			Expect(cmd[len(cmd)-1]).To(ContainSubstring("BOARD='root'@127.0.0.1"))
+		})
+
+		It("should keep close reasons within the WebSocket limit", func() {
+			Expect(truncateCloseReason("short")).To(Equal("short"))
+			Expect(len(truncateCloseReason(strings.Repeat("x", 300)))).To(Equal(123))
+		})
+	})
+
+	Context("Debug", func() {
+		parse := func(query string) (*WorkspaceDebugRequest, error) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/v1/workspaces/my-app/debug?"+query, nil)
+			return parseDebugRequest(c)
+		}
+
+		It("should parse a debug request", func() {
+			req, err := parse(WorkspaceDebugRequest{
+				Binary:  "/usr/bin/app",
+				Args:    []string{"--verbose", "a b"},
+				Symbols: "build/app",
+				Port:    3000,
+				Mode:    DebugModeDAP,
+			}.QueryValues().Encode())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(req.Binary).To(Equal("/usr/bin/app"))
+			Expect(req.Args).To(Equal([]string{"--verbose", "a b"}))
+			Expect(req.Symbols).To(Equal("/workspace/src/build/app"))
+			Expect(req.Port).To(Equal(3000))
+			Expect(req.Mode).To(Equal(DebugModeDAP))
+
+			req, err = parse("binary=/usr/bin/app")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(req.Port).To(Equal(DefaultGDBServerPort))
+			Expect(req.Mode).To(Equal(DebugModeGDB))
+		})
+
+		It("should reject invalid debug requests", func() {
+			for _, query := range []string{
+				"",
+				"binary=app",
+				"binary=/usr/bin/app&mode=lldb",
+				"binary=/usr/bin/app&port=0",
+				"binary=/usr/bin/app&symbols=../../etc/passwd",
+			} {
+				_, err := parse(query)
+				Expect(err).To(HaveOccurred(), "query %q", query)
+			}
+		})
+
+		It("should run gdbserver on the board and gdb in the pod", func() {
+			cmd := debugCommand(&WorkspaceDebugRequest{
+				Binary: "/usr/bin/app",
+				Args:   []string{"it's"},
+				Port:   2345,
+				Mode:   DebugModeGDB,
+			}, &boardAccess{User: "root", Alias: "board-lease"})
+			script := cmd[len(cmd)-1]
+			Expect(script).To(ContainSubstring(`-L 127.0.0.1:2345:127.0.0.1:2345 "$BOARD"`))
+			Expect(script).To(ContainSubstring("gdbserver --once 127.0.0.1:2345"))
+			Expect(script).To(ContainSubstring("-ex 'target remote 127.0.0.1:2345'"))
+			Expect(script).NotTo(ContainSubstring("-i=dap"))
+
+			cmd = debugCommand(&WorkspaceDebugRequest{
+				Binary:  "/usr/bin/app",
+				Symbols: "/workspace/src/build/app",
+				Port:    2345,
+				Mode:    DebugModeDAP,
+			}, &boardAccess{User: "root", Alias: "board-lease"})
+			script = cmd[len(cmd)-1]
+			Expect(script).To(ContainSubstring("gdb -q -i=dap"))
+			Expect(script).NotTo(ContainSubstring("target remote"))
+			Expect(script).To(HaveSuffix("'/workspace/src/build/app'\n"))
+		})
+	})
 })
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 
 	"github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/config/configfile"
@@ -50,6 +51,20 @@ func KeychainFromPullSecrets(ctx context.Context, k8sClient client.Reader, names
 	return authn.NewMultiKeychain(keychains...), nil
 }
 
+// KeychainFromAuthFile builds an authn.Keychain from a Docker/Podman auth file,
+// falling back to authn.DefaultKeychain for registries the file does not list.
+func KeychainFromAuthFile(path string) (authn.Keychain, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading auth file %q: %w", path, err)
+	}
+	cf, err := config.LoadFromReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing auth file %q: %w", path, err)
+	}
+	return authn.NewMultiKeychain(&configFileKeychain{cf: cf}, authn.DefaultKeychain), nil
+}
+
 type configFileKeychain struct {
 	cf *configfile.ConfigFile
 }
@@ -3,6 +3,8 @@ package bundleverify
 import (
 	"context"
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-containerregistry/pkg/authn"
@@ -116,3 +118,41 @@ func TestKeychainFromPullSecrets_UnknownRegistryFallsThrough(t *testing.T) {
 		t.Fatal("expected non-nil authenticator")
 	}
 }
+
+func TestKeychainFromAuthFile_ResolvesCredentials(t *testing.T) {
+	authFile := filepath.Join(t.TempDir(), "auth.json")
+	cfgBytes, _ := json.Marshal(map[string]interface{}{
+		"auths": map[string]interface{}{
+			"registry.example.com": map[string]string{
+				"username": "testuser",
+				"password": "testpass",
+			},
+		},
+	})
+	if err := os.WriteFile(authFile, cfgBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	kc, err := KeychainFromAuthFile(authFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reg, _ := name.NewRegistry("registry.example.com")
+	auth, err := kc.Resolve(reg)
+	if err != nil {
+		t.Fatalf("resolve error: %v", err)
+	}
+	cfg, err := auth.Authorization()
+	if err != nil {
+		t.Fatalf("authorization error: %v", err)
+	}
+	if cfg.Username != "testuser" || cfg.Password != "testpass" {
+		t.Errorf("got user=%q pass=%q, want testuser/testpass", cfg.Username, cfg.Password)
+	}
+}
+
+func TestKeychainFromAuthFile_MissingFile(t *testing.T) {
+	if _, err := KeychainFromAuthFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatal("expected error for a missing auth file")
+	}
+}
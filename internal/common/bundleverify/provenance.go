@@ -0,0 +1,84 @@
+package bundleverify
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v3/pkg/cosign"
+	ociremote "github.com/sigstore/cosign/v3/pkg/oci/remote"
+)
+
+// SLSAProvenanceV1PredicateType is the in-toto predicate type of the SLSA v1
+// provenance attached to signed build artifacts.
+const SLSAProvenanceV1PredicateType = "https://slsa.dev/provenance/v1"
+
+// VerifyProvenance verifies the cosign attestations of an OCI image reference
+// using the given cosign public key (PEM-encoded) and returns the predicate of
+// the first SLSA v1 provenance attestation whose subject is the image.
+func VerifyProvenance(
+	ctx context.Context, imageRef string, cosignPubKeyPEM []byte, registryOpts ...ociremote.Option,
+) (json.RawMessage, error) {
+	verifier, err := loadVerifier(cosignPubKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	ref, err := name.ParseReference(imageRef)
+	if err != nil {
+		return nil, fmt.Errorf("parsing image reference %q: %w", imageRef, err)
+	}
+
+	checkOpts := &cosign.CheckOpts{
+		SigVerifier:        verifier,
+		IgnoreTlog:         true,
+		IgnoreSCT:          true,
+		ExperimentalOCI11:  true,
+		ClaimVerifier:      cosign.IntotoSubjectClaimVerifier,
+		RegistryClientOpts: registryOpts,
+	}
+	attestations, _, err := cosign.VerifyImageAttestations(ctx, ref, checkOpts)
+	if err != nil {
+		return nil, fmt.Errorf("attestation verification failed for %q: %w", ref.String(), err)
+	}
+
+	for _, att := range attestations {
+		payload, err := att.Payload()
+		if err != nil {
+			continue
+		}
+		predicate, err := provenancePredicate(payload)
+		if err == nil {
+			return predicate, nil
+		}
+	}
+	return nil, fmt.Errorf("no SLSA provenance attestation found for %q", ref.String())
+}
+
+// provenancePredicate extracts the SLSA v1 provenance predicate from a DSSE
+// envelope wrapping an in-toto statement.
+func provenancePredicate(envelope []byte) (json.RawMessage, error) {
+	var env struct {
+		Payload string `json:"payload"`
+	}
+	if err := json.Unmarshal(envelope, &env); err != nil {
+		return nil, fmt.Errorf("parsing DSSE envelope: %w", err)
+	}
+	statementJSON, err := base64.StdEncoding.DecodeString(env.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("decoding DSSE payload: %w", err)
+	}
+	var statement struct {
+		PredicateType string          `json:"predicateType"`
+		Predicate     json.RawMessage `json:"predicate"`
+	}
+	if err := json.Unmarshal(statementJSON, &statement); err != nil {
+		return nil, fmt.Errorf("parsing in-toto statement: %w", err)
+	}
+	if statement.PredicateType != SLSAProvenanceV1PredicateType {
+		return nil, fmt.Errorf("unexpected predicate type %q", statement.PredicateType)
+	}
+	return statement.Predicate, nil
+}
@@ -0,0 +1,59 @@
+package bundleverify
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+)
+
+func dsseEnvelope(t *testing.T, statement string) []byte {
+	t.Helper()
+	envelope, err := json.Marshal(map[string]string{
+		"payloadType": "application/vnd.in-toto+json",
+		"payload":     base64.StdEncoding.EncodeToString([]byte(statement)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return envelope
+}
+
+func TestProvenancePredicate(t *testing.T) {
+	predicate, err := provenancePredicate(dsseEnvelope(t, `{
+		"_type": "https://in-toto.io/Statement/v1",
+		"predicateType": "https://slsa.dev/provenance/v1",
+		"predicate": {"buildDefinition": {"externalParameters": {"distro": "autosd"}}}
+	}`))
+	if err != nil {
+		t.Fatalf("provenancePredicate() error = %v", err)
+	}
+	var parsed struct {
+		BuildDefinition struct {
+			ExternalParameters map[string]string `json:"externalParameters"`
+		} `json:"buildDefinition"`
+	}
+	if err := json.Unmarshal(predicate, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.BuildDefinition.ExternalParameters["distro"] != "autosd" {
+		t.Errorf("unexpected predicate %s", predicate)
+	}
+}
+
+func TestProvenancePredicate_OtherPredicateType(t *testing.T) {
+	_, err := provenancePredicate(dsseEnvelope(t, `{
+		"predicateType": "https://spdx.dev/Document",
+		"predicate": {}
+	}`))
+	if err == nil {
+		t.Fatal("expected error for a non-provenance predicate")
+	}
+}
+
+func TestVerifyProvenance_InvalidPEM(t *testing.T) {
+	_, err := VerifyProvenance(context.Background(), "quay.io/test/img@sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", []byte("not-a-pem"))
+	if err == nil {
+		t.Fatal("expected error for invalid PEM key")
+	}
+}
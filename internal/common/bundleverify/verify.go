@@ -43,14 +43,9 @@ func FetchCosignPublicKey(ctx context.Context, k8sClient client.Reader, keyRef *
 //
 // Tries v3 bundle format (OCI referrers) first, falls back to legacy tag-based signatures.
 func VerifyBundle(ctx context.Context, bundleRef string, cosignPubKeyPEM []byte, registryOpts ...ociremote.Option) error {
-	pubKey, err := cryptoutils.UnmarshalPEMToPublicKey(cosignPubKeyPEM)
+	verifier, err := loadVerifier(cosignPubKeyPEM)
 	if err != nil {
-		return fmt.Errorf("parsing cosign public key: %w", err)
-	}
-
-	verifier, err := signature.LoadDefaultVerifier(pubKey)
-	if err != nil {
-		return fmt.Errorf("creating verifier: %w", err)
+		return err
 	}
 
 	ref, err := name.ParseReference(bundleRef)
@@ -69,6 +64,19 @@ func VerifyBundle(ctx context.Context, bundleRef string, cosignPubKeyPEM []byte,
 	return nil
 }
 
+// loadVerifier creates a signature verifier from a PEM-encoded cosign public key.
+func loadVerifier(cosignPubKeyPEM []byte) (signature.Verifier, error) {
+	pubKey, err := cryptoutils.UnmarshalPEMToPublicKey(cosignPubKeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parsing cosign public key: %w", err)
+	}
+	verifier, err := signature.LoadDefaultVerifier(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("creating verifier: %w", err)
+	}
+	return verifier, nil
+}
+
 // verifyV3Bundles fetches sigstore v3 bundles via OCI referrers and verifies with sigstore-go.
 func verifyV3Bundles(ctx context.Context, ref name.Reference, verifier signature.Verifier, registryOpts []ociremote.Option) error {
 	bundles, hash, err := cosign.GetBundles(ctx, ref, registryOpts)
@@ -0,0 +1,210 @@
+// Package jumpstarter manages the Jumpstarter leases acquired for builds,
+// flash jobs and workspaces. Leases are jumpstarter.dev resources in the
+// namespace of the Jumpstarter client that requested them.
+package jumpstarter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+)
+
+// LeaseGVK and LeaseListGVK identify the Jumpstarter Lease resources.
+var (
+	LeaseGVK     = schema.GroupVersionKind{Group: "jumpstarter.dev", Version: "v1alpha1", Kind: "Lease"}
+	LeaseListGVK = schema.GroupVersionKind{Group: "jumpstarter.dev", Version: "v1alpha1", Kind: "LeaseList"}
+)
+
+// ErrNotInstalled is returned when the cluster has no Jumpstarter Lease resources.
+var ErrNotInstalled = errors.New("jumpstarter leases are not available in this cluster")
+
+// Lease is the state of a Jumpstarter lease.
+type Lease struct {
+	Name      string
+	Namespace string
+	// Client is the Jumpstarter client that requested the lease
+	Client string
+	// Exporter is the exporter the lease was granted on, empty while waiting
+	Exporter  string
+	Duration  time.Duration
+	BeginTime *time.Time
+	EndTime   *time.Time
+	Ended     bool
+	Released  bool
+	// OwnerNamespace is the namespace of the resources the lease was claimed for
+	OwnerNamespace string
+}
+
+// Active reports whether the lease is held or still waiting for an exporter.
+func (l *Lease) Active() bool {
+	return !l.Ended && !l.Released
+}
+
+// ExpiresAt returns when the lease ends, or nil while it waits for an exporter.
+func (l *Lease) ExpiresAt() *time.Time {
+	if l.EndTime != nil {
+		return l.EndTime
+	}
+	if l.BeginTime == nil {
+		return nil
+	}
+	expires := l.BeginTime.Add(l.Duration)
+	return &expires
+}
+
+// GetLease returns the lease with the given name. Missing leases return a
+// NotFound error.
+func GetLease(ctx context.Context, c client.Client, namespace, name string) (*Lease, error) {
+	obj, err := getLease(ctx, c, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return leaseFromUnstructured(obj), nil
+}
+
+// ReleaseLease asks Jumpstarter to end the lease and free its exporter.
+func ReleaseLease(ctx context.Context, c client.Client, namespace, name string) error {
+	obj, err := getLease(ctx, c, namespace, name)
+	if err != nil {
+		return err
+	}
+	patch := client.MergeFrom(obj.DeepCopy())
+	if err := unstructured.SetNestedField(obj.Object, true, "spec", "release"); err != nil {
+		return err
+	}
+	return c.Patch(ctx, obj, patch)
+}
+
+// ExtendLease adds extra time to an active lease and returns its new state.
+func ExtendLease(ctx context.Context, c client.Client, namespace, name string, extra time.Duration) (*Lease, error) {
+	if extra <= 0 {
+		return nil, fmt.Errorf("lease extension must be positive, got %s", extra)
+	}
+	obj, err := getLease(ctx, c, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	lease := leaseFromUnstructured(obj)
+	if !lease.Active() {
+		return nil, fmt.Errorf("lease %s has ended", name)
+	}
+
+	patch := client.MergeFrom(obj.DeepCopy())
+	if err := unstructured.SetNestedField(obj.Object, (lease.Duration + extra).String(), "spec", "duration"); err != nil {
+		return nil, err
+	}
+	if err := c.Patch(ctx, obj, patch); err != nil {
+		return nil, err
+	}
+	return leaseFromUnstructured(obj), nil
+}
+
+// ClaimLease labels a lease as acquired for resources of ownerNamespace, so
+// it is released once none of them references it anymore.
+func ClaimLease(ctx context.Context, c client.Client, namespace, name, ownerNamespace string) error {
+	obj, err := getLease(ctx, c, namespace, name)
+	if err != nil {
+		return err
+	}
+	current := obj.GetLabels()
+	if current[labels.ManagedBy] == labels.ValueOperator && current[labels.LeaseOwnerNS] == ownerNamespace {
+		return nil
+	}
+
+	patch := client.MergeFrom(obj.DeepCopy())
+	updated := make(map[string]string, len(current)+2)
+	for k, v := range current {
+		updated[k] = v
+	}
+	updated[labels.ManagedBy] = labels.ValueOperator
+	updated[labels.LeaseOwnerNS] = ownerNamespace
+	obj.SetLabels(updated)
+	return c.Patch(ctx, obj, patch)
+}
+
+// ListClaimedLeases returns the leases claimed for resources of ownerNamespace
+// across all Jumpstarter namespaces.
+func ListClaimedLeases(ctx context.Context, c client.Client, ownerNamespace string) ([]Lease, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(LeaseListGVK)
+	if err := c.List(ctx, list, client.MatchingLabels{
+		labels.ManagedBy:    labels.ValueOperator,
+		labels.LeaseOwnerNS: ownerNamespace,
+	}); err != nil {
+		return nil, notInstalled(err)
+	}
+	leases := make([]Lease, 0, len(list.Items))
+	for i := range list.Items {
+		leases = append(leases, *leaseFromUnstructured(&list.Items[i]))
+	}
+	return leases, nil
+}
+
+// ClientNamespace returns the namespace of the Jumpstarter client in a client
+// config file, or "" if it does not name one.
+func ClientNamespace(clientConfig []byte) string {
+	var config struct {
+		Metadata struct {
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+	}
+	if err := yaml.Unmarshal(clientConfig, &config); err != nil {
+		return ""
+	}
+	return config.Metadata.Namespace
+}
+
+func getLease(ctx context.Context, c client.Client, namespace, name string) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(LeaseGVK)
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, obj); err != nil {
+		return nil, notInstalled(err)
+	}
+	return obj, nil
+}
+
+func notInstalled(err error) error {
+	if apimeta.IsNoMatchError(err) {
+		return ErrNotInstalled
+	}
+	return err
+}
+
+func leaseFromUnstructured(obj *unstructured.Unstructured) *Lease {
+	lease := &Lease{
+		Name:           obj.GetName(),
+		Namespace:      obj.GetNamespace(),
+		OwnerNamespace: obj.GetLabels()[labels.LeaseOwnerNS],
+		BeginTime:      nestedTime(obj, "status", "beginTime"),
+		EndTime:        nestedTime(obj, "status", "endTime"),
+	}
+	lease.Client, _, _ = unstructured.NestedString(obj.Object, "spec", "clientRef", "name")
+	lease.Exporter, _, _ = unstructured.NestedString(obj.Object, "status", "exporterRef", "name")
+	lease.Released, _, _ = unstructured.NestedBool(obj.Object, "spec", "release")
+	lease.Ended, _, _ = unstructured.NestedBool(obj.Object, "status", "ended")
+	if duration, _, _ := unstructured.NestedString(obj.Object, "spec", "duration"); duration != "" {
+		lease.Duration, _ = time.ParseDuration(duration)
+	}
+	return lease
+}
+
+func nestedTime(obj *unstructured.Unstructured, fields ...string) *time.Time {
+	value, _, _ := unstructured.NestedString(obj.Object, fields...)
+	if value == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
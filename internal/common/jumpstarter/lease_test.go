@@ -0,0 +1,95 @@
+package jumpstarter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newLease(name string, spec, status map[string]interface{}) *unstructured.Unstructured {
+	lease := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec, "status": status}}
+	lease.SetGroupVersionKind(LeaseGVK)
+	lease.SetName(name)
+	lease.SetNamespace("lab")
+	return lease
+}
+
+func TestGetLease(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithObjects(newLease("lease-1",
+		map[string]interface{}{"clientRef": map[string]interface{}{"name": "alice"}, "duration": "1h0m0s"},
+		map[string]interface{}{"beginTime": "2026-01-01T10:00:00Z", "exporterRef": map[string]interface{}{"name": "rack-01"}},
+	)).Build()
+
+	lease, err := GetLease(context.Background(), c, "lab", "lease-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lease.Client != "alice" || lease.Exporter != "rack-01" || lease.Duration != time.Hour || !lease.Active() {
+		t.Errorf("unexpected lease %+v", lease)
+	}
+	if expires := lease.ExpiresAt(); expires == nil || !expires.Equal(time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("ExpiresAt() = %v", expires)
+	}
+}
+
+func TestExtendAndReleaseLease(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithObjects(
+		newLease("lease-1", map[string]interface{}{"duration": "1h0m0s"}, map[string]interface{}{}),
+		newLease("lease-2", map[string]interface{}{"duration": "1h0m0s"}, map[string]interface{}{"ended": true}),
+	).Build()
+
+	lease, err := ExtendLease(ctx, c, "lab", "lease-1", 30*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lease.Duration != 90*time.Minute {
+		t.Errorf("duration = %s, want 1h30m", lease.Duration)
+	}
+	if _, err := ExtendLease(ctx, c, "lab", "lease-2", time.Hour); err == nil {
+		t.Error("expected error extending an ended lease")
+	}
+
+	if err := ReleaseLease(ctx, c, "lab", "lease-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lease, err = GetLease(ctx, c, "lab", "lease-1")
+	if err != nil || !lease.Released || lease.Active() {
+		t.Errorf("expected released lease, got %+v, %v", lease, err)
+	}
+}
+
+func TestClaimLease(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithObjects(
+		newLease("lease-1", map[string]interface{}{}, map[string]interface{}{}),
+	).Build()
+
+	if err := ClaimLease(ctx, c, "lab", "lease-1", "builds"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	claimed, err := ListClaimedLeases(ctx, c, "builds")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].Name != "lease-1" || claimed[0].OwnerNamespace != "builds" {
+		t.Errorf("unexpected claimed leases %+v", claimed)
+	}
+	if claimed, _ := ListClaimedLeases(ctx, c, "other"); len(claimed) != 0 {
+		t.Errorf("expected no leases claimed for another namespace, got %+v", claimed)
+	}
+}
+
+func TestClientNamespace(t *testing.T) {
+	config := []byte("apiVersion: jumpstarter.dev/v1alpha1\nkind: ClientConfig\nmetadata:\n  namespace: lab\n  name: alice\n")
+	if got := ClientNamespace(config); got != "lab" {
+		t.Errorf("ClientNamespace() = %q, want lab", got)
+	}
+	if got := ClientNamespace([]byte("not: [yaml")); got != "" {
+		t.Errorf("ClientNamespace() = %q for invalid config", got)
+	}
+}
@@ -0,0 +1,130 @@
+package jumpstarter
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+)
+
+// Kinds of the resources holding leases.
+const (
+	OwnerImageBuild = "ImageBuild"
+	OwnerFlashJob   = "FlashJob"
+	OwnerWorkspace  = "Workspace"
+)
+
+// clientConfigKey is the key of the Jumpstarter client config in client config secrets.
+const clientConfigKey = "client.yaml"
+
+// Owner is a build, flash job or workspace holding a lease.
+type Owner struct {
+	Kind        string
+	Name        string
+	RequestedBy string
+	// ClientConfigSecretRef is the secret holding the client config the lease was requested with
+	ClientConfigSecretRef string
+	// Acquired is set when the lease was acquired for the owner, rather than
+	// passed in by the user
+	Acquired bool
+}
+
+// OwnedBy reports whether any of the owners belongs to the user.
+func OwnedBy(owners []Owner, user string) bool {
+	for _, owner := range owners {
+		if owner.RequestedBy == user {
+			return true
+		}
+	}
+	return false
+}
+
+// Acquired reports whether the lease was acquired for any of the owners.
+func Acquired(owners []Owner) bool {
+	for _, owner := range owners {
+		if owner.Acquired {
+			return true
+		}
+	}
+	return false
+}
+
+// FindOwners maps the IDs of the leases referenced by the builds, flash jobs
+// and workspaces of a namespace to the resources referencing them.
+func FindOwners(ctx context.Context, c client.Client, namespace string) (map[string][]Owner, error) {
+	owners := map[string][]Owner{}
+	add := func(leaseID string, owner Owner) {
+		if leaseID != "" {
+			owners[leaseID] = append(owners[leaseID], owner)
+		}
+	}
+
+	builds := &automotivev1alpha1.ImageBuildList{}
+	if err := c.List(ctx, builds, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list image builds: %w", err)
+	}
+	for _, build := range builds.Items {
+		add(build.Status.LeaseID, Owner{
+			Kind:                  OwnerImageBuild,
+			Name:                  build.Name,
+			RequestedBy:           build.Annotations[labels.RequestedBy],
+			ClientConfigSecretRef: build.Spec.GetFlashClientConfigSecretRef(),
+			Acquired:              build.Spec.GetFlashLeaseName() == "",
+		})
+	}
+
+	flashJobs := &automotivev1alpha1.FlashJobList{}
+	if err := c.List(ctx, flashJobs, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list flash jobs: %w", err)
+	}
+	for _, flashJob := range flashJobs.Items {
+		owner := Owner{
+			Kind:                  OwnerFlashJob,
+			Name:                  flashJob.Name,
+			RequestedBy:           flashJob.Annotations[labels.RequestedBy],
+			ClientConfigSecretRef: flashJob.Spec.ClientConfigSecretRef,
+			Acquired:              flashJob.Spec.LeaseName == "",
+		}
+		add(flashJob.Status.LeaseID, owner)
+		for _, board := range flashJob.Status.Boards {
+			add(board.LeaseID, owner)
+		}
+	}
+
+	workspaces := &automotivev1alpha1.WorkspaceList{}
+	if err := c.List(ctx, workspaces, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	for _, ws := range workspaces.Items {
+		add(ws.Spec.LeaseID, Owner{
+			Kind:                  OwnerWorkspace,
+			Name:                  ws.Name,
+			RequestedBy:           ws.Spec.Owner,
+			ClientConfigSecretRef: ws.Spec.ClientConfigSecretRef,
+		})
+	}
+	return owners, nil
+}
+
+// LeaseNamespace returns the namespace of the leases requested with the
+// owners' client configs, falling back to the given namespace (usually the
+// OperatorConfig Jumpstarter namespace).
+func LeaseNamespace(ctx context.Context, c client.Client, namespace string, owners []Owner, fallback string) string {
+	for _, owner := range owners {
+		if owner.ClientConfigSecretRef == "" {
+			continue
+		}
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: owner.ClientConfigSecretRef}, secret); err != nil {
+			continue
+		}
+		if ns := ClientNamespace(secret.Data[clientConfigKey]); ns != "" {
+			return ns
+		}
+	}
+	return fallback
+}
@@ -14,6 +14,9 @@ const (
 	Workspace       = "automotive.sdv.cloud.redhat.com/workspace"
 	UploadsComplete = "automotive.sdv.cloud.redhat.com/uploads-complete"
 	FlashTaskRun    = "automotive.sdv.cloud.redhat.com/flash-taskrun"
+	FlashJob        = "automotive.sdv.cloud.redhat.com/flashjob"
+	FleetBoard      = "automotive.sdv.cloud.redhat.com/fleet-board"
+	LeaseOwnerNS    = "automotive.sdv.cloud.redhat.com/lease-owner-namespace"
 	Progress        = "automotive.sdv.cloud.redhat.com/progress"
 	Username        = "automotive.sdv.cloud.redhat.com/username"
 	TaskType        = "automotive.sdv.cloud.redhat.com/task-type"
@@ -22,6 +25,7 @@ const (
 	Distro          = "automotive.sdv.cloud.redhat.com/distro"
 	Target          = "automotive.sdv.cloud.redhat.com/target"
 	Architecture    = "automotive.sdv.cloud.redhat.com/architecture"
+	Tenant          = "automotive.sdv.cloud.redhat.com/tenant"
 )
 
 // ManagedBy and related constants are standard Kubernetes label keys.
@@ -0,0 +1,95 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+)
+
+// maxDeadLetters bounds the dead-letter ConfigMap; the oldest records are dropped first.
+const maxDeadLetters = 100
+
+var invalidKeyChars = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+
+// DeadLetter records a notification that could not be delivered.
+type DeadLetter struct {
+	Target   string    `json:"target"`
+	Event    Event     `json:"event"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failedAt"`
+}
+
+// recordDeadLetter adds an undelivered notification to the dead-letter ConfigMap,
+// creating it when needed. Keys start with the failure time so they sort oldest first.
+func (n *Notifier) recordDeadLetter(
+	ctx context.Context,
+	name string,
+	target *automotivev1alpha1.NotificationTarget,
+	event Event,
+	attempts int,
+	deliveryErr error,
+) error {
+	now := time.Now().UTC()
+	record, err := json.Marshal(DeadLetter{
+		Target:   target.Name,
+		Event:    event,
+		Attempts: attempts,
+		Error:    deliveryErr.Error(),
+		FailedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+	key := invalidKeyChars.ReplaceAllString(
+		fmt.Sprintf("%s-%s-%s-%s", now.Format("20060102T150405.000000000"), target.Name, event.Kind, event.Name), "-")
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm := &corev1.ConfigMap{}
+		err := n.Client.Get(ctx, client.ObjectKey{Name: name, Namespace: n.Namespace}, cm)
+		if k8serrors.IsNotFound(err) {
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: n.Namespace,
+					Labels: map[string]string{
+						labels.ManagedBy: labels.ValueOperator,
+					},
+				},
+				Data: map[string]string{key: string(record)},
+			}
+			return n.Client.Create(ctx, cm)
+		}
+		if err != nil {
+			return err
+		}
+
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[key] = string(record)
+		if len(cm.Data) > maxDeadLetters {
+			keys := make([]string, 0, len(cm.Data))
+			for k := range cm.Data {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys[:len(keys)-maxDeadLetters] {
+				delete(cm.Data, k)
+			}
+		}
+		return n.Client.Update(ctx, cm)
+	})
+}
@@ -0,0 +1,372 @@
+// Package notifications delivers lifecycle transitions of builds, reseals, flash
+// jobs and catalog images to the webhooks configured in the OperatorConfig.
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+const (
+	// SignatureHeader carries the HMAC-SHA256 signature of signed payloads.
+	SignatureHeader = "X-Signature-256"
+	// EventHeader carries the kind and phase of the transition, e.g. "ImageBuild.Completed".
+	EventHeader = "X-Notification-Event"
+
+	urlSecretKey     = "url"
+	signingSecretKey = "secret"
+
+	defaultTimeout = 10 * time.Second
+	defaultBackoff = 2 * time.Second
+
+	// queueSize bounds the deliveries waiting for a worker.
+	queueSize       = 256
+	deliveryWorkers = 4
+	// drainTimeout bounds delivery on shutdown, within the manager's default
+	// graceful shutdown timeout of 30s.
+	drainTimeout      = 20 * time.Second
+	deadLetterTimeout = 5 * time.Second
+)
+
+// Event is a lifecycle transition of a resource.
+type Event struct {
+	Kind          string    `json:"kind"`
+	Name          string    `json:"name"`
+	Namespace     string    `json:"namespace"`
+	Phase         string    `json:"phase"`
+	PreviousPhase string    `json:"previousPhase,omitempty"`
+	Message       string    `json:"message,omitempty"`
+	Requester     string    `json:"requester,omitempty"`
+	Time          time.Time `json:"time"`
+
+	// OptedIn is set when the resource requested notifications.
+	OptedIn bool `json:"-"`
+}
+
+// EventFor returns the transition of obj into phase, taking the requester and the
+// notification opt-in from its annotations.
+func EventFor(obj client.Object, kind, previousPhase, phase, message string) Event {
+	annotations := obj.GetAnnotations()
+	return Event{
+		Kind:          kind,
+		Name:          obj.GetName(),
+		Namespace:     obj.GetNamespace(),
+		Phase:         phase,
+		PreviousPhase: previousPhase,
+		Message:       message,
+		Requester:     annotations[automotivev1alpha1.AnnotationRequestedBy],
+		Time:          time.Now().UTC(),
+		OptedIn:       annotations[automotivev1alpha1.AnnotationNotify] == "true",
+	}
+}
+
+// Notifier sends events to the notification targets of the OperatorConfig.
+// It runs as a manager.Runnable: Notify queues deliveries and a pool of workers
+// sends them until the manager shuts down. A nil Notifier drops all events, so
+// reconcilers can be built without one.
+type Notifier struct {
+	// Client reads the OperatorConfig and the target Secrets; it should be the
+	// manager's cached client.
+	Client     client.Client
+	Log        logr.Logger
+	HTTPClient *http.Client
+	// Namespace holds the OperatorConfig, the target Secrets and the dead-letter ConfigMap.
+	Namespace string
+	// Backoff is the delay before the first retry, doubled for every further retry.
+	Backoff time.Duration
+
+	queue   chan delivery
+	mu      sync.Mutex
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// delivery is an event on its way to one target.
+type delivery struct {
+	config *automotivev1alpha1.NotificationsConfig
+	target *automotivev1alpha1.NotificationTarget
+	event  Event
+}
+
+var (
+	errQueueFull = errors.New("notification queue is full")
+	errStopped   = errors.New("notifier is shut down")
+)
+
+// New returns a Notifier reading its configuration from the OperatorConfig in namespace.
+func New(c client.Client, log logr.Logger, namespace string) *Notifier {
+	return &Notifier{
+		Client:     c,
+		Log:        log,
+		HTTPClient: &http.Client{Timeout: defaultTimeout},
+		Namespace:  namespace,
+		Backoff:    defaultBackoff,
+		queue:      make(chan delivery, queueSize),
+	}
+}
+
+// SetupWithManager runs the delivery workers on the leader, next to the
+// reconcilers that report the transitions.
+func (n *Notifier) SetupWithManager(mgr ctrl.Manager) error {
+	return mgr.Add(n)
+}
+
+// Start delivers queued notifications until ctx is done. On shutdown it stops
+// accepting events and gives the deliveries in flight and still queued
+// drainTimeout to finish; those that cannot are recorded in the dead-letter
+// ConfigMap.
+func (n *Notifier) Start(ctx context.Context) error {
+	deliveryCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+
+	var workers sync.WaitGroup
+	for range deliveryWorkers {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for {
+				select {
+				case d := <-n.queue:
+					n.deliver(deliveryCtx, d)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	n.mu.Lock()
+	n.stopped = true
+	n.mu.Unlock()
+
+	timer := time.AfterFunc(drainTimeout, cancel)
+	defer timer.Stop()
+	workers.Wait()
+	for {
+		select {
+		case d := <-n.queue:
+			n.deliver(deliveryCtx, d)
+		default:
+			return nil
+		}
+	}
+}
+
+// Notify queues event for every matching target. Deliveries are retried and
+// recorded in the dead-letter ConfigMap when they keep failing, or when the
+// queue is full, so reconcilers never wait for, or fail because of, a webhook.
+func (n *Notifier) Notify(ctx context.Context, event Event) {
+	if n == nil || event.Phase == "" {
+		return
+	}
+
+	operatorConfig := &automotivev1alpha1.OperatorConfig{}
+	if err := n.Client.Get(ctx, client.ObjectKey{Name: "config", Namespace: n.Namespace}, operatorConfig); err != nil {
+		return
+	}
+	config := operatorConfig.Spec.Notifications
+	if config == nil {
+		return
+	}
+
+	for i := range config.Targets {
+		target := config.Targets[i]
+		if !Matches(&target, event) {
+			continue
+		}
+		n.enqueue(ctx, delivery{config: config, target: &target, event: event})
+	}
+}
+
+func (n *Notifier) enqueue(ctx context.Context, d delivery) {
+	n.mu.Lock()
+	err := errStopped
+	if !n.stopped {
+		n.wg.Add(1)
+		select {
+		case n.queue <- d:
+			err = nil
+		default:
+			n.wg.Done()
+			err = errQueueFull
+		}
+	}
+	n.mu.Unlock()
+
+	if err != nil {
+		n.Log.Error(err, "Dropping notification", "target", d.target.Name, "kind", d.event.Kind, "name", d.event.Name)
+		n.deadLetter(ctx, d, 0, err)
+	}
+}
+
+// Wait blocks until all queued deliveries have finished.
+func (n *Notifier) Wait() {
+	if n != nil {
+		n.wg.Wait()
+	}
+}
+
+// Matches reports whether target is interested in event.
+func Matches(target *automotivev1alpha1.NotificationTarget, event Event) bool {
+	if target.OptIn && !event.OptedIn {
+		return false
+	}
+	if len(target.Kinds) > 0 && !slices.Contains(target.Kinds, event.Kind) {
+		return false
+	}
+	if len(target.Phases) > 0 && !slices.Contains(target.Phases, event.Phase) {
+		return false
+	}
+	if len(target.Requesters) > 0 && !slices.Contains(target.Requesters, event.Requester) {
+		return false
+	}
+	return true
+}
+
+// deliver posts an event to its target, retrying transient failures, and
+// records it in the dead-letter ConfigMap when it cannot be delivered.
+func (n *Notifier) deliver(ctx context.Context, d delivery) {
+	defer n.wg.Done()
+	log := n.Log.WithValues("target", d.target.Name, "kind", d.event.Kind, "name", d.event.Name, "phase", d.event.Phase)
+
+	attempts, err := n.send(ctx, d.config.GetMaxRetries(), d.target, d.event)
+	if err == nil {
+		log.V(1).Info("Delivered notification", "attempts", attempts)
+		return
+	}
+	log.Error(err, "Failed to deliver notification", "attempts", attempts)
+	n.deadLetter(ctx, d, attempts, err)
+}
+
+// deadLetter records an undelivered notification. It outlives the
+// cancellation of ctx so deliveries cut short by a shutdown are still recorded.
+func (n *Notifier) deadLetter(ctx context.Context, d delivery, attempts int, deliveryErr error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deadLetterTimeout)
+	defer cancel()
+	if err := n.recordDeadLetter(ctx, d.config.GetDeadLetterConfigMap(), d.target, d.event, attempts, deliveryErr); err != nil {
+		n.Log.Error(err, "Failed to record undelivered notification", "target", d.target.Name, "kind", d.event.Kind, "name", d.event.Name)
+	}
+}
+
+// send posts event to target up to maxRetries+1 times and returns the number of attempts.
+func (n *Notifier) send(
+	ctx context.Context,
+	maxRetries int,
+	target *automotivev1alpha1.NotificationTarget,
+	event Event,
+) (int, error) {
+	url, err := n.targetURL(ctx, target)
+	if err != nil {
+		return 0, err
+	}
+	body, err := Payload(target, event)
+	if err != nil {
+		return 0, err
+	}
+	var signingKey []byte
+	if target.SigningSecretRef != "" {
+		if signingKey, err = n.secretValue(ctx, target.SigningSecretRef, signingSecretKey); err != nil {
+			return 0, err
+		}
+	}
+
+	backoff := n.Backoff
+	attempts := 0
+	for {
+		attempts++
+		retryable, err := n.post(ctx, url, body, signingKey, event)
+		if err == nil {
+			return attempts, nil
+		}
+		if !retryable || attempts > maxRetries {
+			return attempts, err
+		}
+		select {
+		case <-ctx.Done():
+			return attempts, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post sends one request and reports whether a failure is worth retrying.
+func (n *Notifier) post(ctx context.Context, url string, body, signingKey []byte, event Event) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event.Kind+"."+event.Phase)
+	if len(signingKey) > 0 {
+		req.Header.Set(SignatureHeader, Sign(signingKey, body))
+	}
+
+	httpClient := n.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("webhook returned %s", resp.Status)
+}
+
+// Sign returns the X-Signature-256 header value of body signed with key.
+func Sign(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (n *Notifier) targetURL(ctx context.Context, target *automotivev1alpha1.NotificationTarget) (string, error) {
+	if target.URLSecretRef == "" {
+		if target.URL == "" {
+			return "", fmt.Errorf("notification target %s has no url", target.Name)
+		}
+		return target.URL, nil
+	}
+	url, err := n.secretValue(ctx, target.URLSecretRef, urlSecretKey)
+	if err != nil {
+		return "", err
+	}
+	return string(url), nil
+}
+
+func (n *Notifier) secretValue(ctx context.Context, name, key string) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := n.Client.Get(ctx, client.ObjectKey{Name: name, Namespace: n.Namespace}, secret); err != nil {
+		return nil, fmt.Errorf("getting secret %s: %w", name, err)
+	}
+	value := secret.Data[key]
+	if len(value) == 0 {
+		return nil, fmt.Errorf("secret %s has no %q key", name, key)
+	}
+	return value, nil
+}
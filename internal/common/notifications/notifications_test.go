@@ -0,0 +1,312 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+const testNamespace = "operator-ns"
+
+func newNotifier(t *testing.T, config *automotivev1alpha1.NotificationsConfig, objs ...client.Object) *Notifier {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := automotivev1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	objs = append(objs, &automotivev1alpha1.OperatorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: testNamespace},
+		Spec:       automotivev1alpha1.OperatorConfigSpec{Notifications: config},
+	})
+	n := New(fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(), logr.Discard(), testNamespace)
+	n.Backoff = time.Millisecond
+	return n
+}
+
+// startNotifier runs the delivery workers of n until the test ends.
+func startNotifier(t *testing.T, n *Notifier) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = n.Start(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func deadLetters(t *testing.T, n *Notifier) []DeadLetter {
+	t.Helper()
+	cm := &corev1.ConfigMap{}
+	key := client.ObjectKey{Name: automotivev1alpha1.DefaultNotificationDeadLetterConfigMap, Namespace: testNamespace}
+	if err := n.Client.Get(context.Background(), key, cm); err != nil {
+		t.Fatalf("dead-letter ConfigMap not created: %v", err)
+	}
+	var records []DeadLetter
+	for _, v := range cm.Data {
+		var record DeadLetter
+		if err := json.Unmarshal([]byte(v), &record); err != nil {
+			t.Fatalf("invalid dead letter %s: %v", v, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func testEvent() Event {
+	return Event{
+		Kind:          "ImageBuild",
+		Name:          "my-build",
+		Namespace:     "team-a",
+		Phase:         "Completed",
+		PreviousPhase: "Building",
+		Message:       "Build completed successfully",
+		Requester:     "alice",
+		Time:          time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestEventFor(t *testing.T) {
+	build := &automotivev1alpha1.ImageBuild{ObjectMeta: metav1.ObjectMeta{
+		Name:      "my-build",
+		Namespace: "team-a",
+		Annotations: map[string]string{
+			automotivev1alpha1.AnnotationRequestedBy: "alice",
+			automotivev1alpha1.AnnotationNotify:      "true",
+		},
+	}}
+
+	event := EventFor(build, "ImageBuild", "Building", "Completed", "done")
+	if event.Requester != "alice" || !event.OptedIn || event.Namespace != "team-a" || event.PreviousPhase != "Building" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	event := testEvent()
+	tests := []struct {
+		name   string
+		target automotivev1alpha1.NotificationTarget
+		want   bool
+	}{
+		{"no filters", automotivev1alpha1.NotificationTarget{}, true},
+		{"matching filters", automotivev1alpha1.NotificationTarget{
+			Kinds: []string{"ImageBuild"}, Phases: []string{"Completed", "Failed"}, Requesters: []string{"alice"},
+		}, true},
+		{"other kind", automotivev1alpha1.NotificationTarget{Kinds: []string{"FlashJob"}}, false},
+		{"other phase", automotivev1alpha1.NotificationTarget{Phases: []string{"Failed"}}, false},
+		{"other requester", automotivev1alpha1.NotificationTarget{Requesters: []string{"bob"}}, false},
+		{"not opted in", automotivev1alpha1.NotificationTarget{OptIn: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Matches(&tt.target, event); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	event.OptedIn = true
+	if !Matches(&automotivev1alpha1.NotificationTarget{OptIn: true}, event) {
+		t.Error("expected opted-in event to match opt-in target")
+	}
+}
+
+func TestPayload(t *testing.T) {
+	event := testEvent()
+	summary := "ImageBuild team-a/my-build: Building -> Completed (requested by alice): Build completed successfully"
+
+	tests := []struct {
+		format automotivev1alpha1.NotificationFormat
+		key    string
+	}{
+		{automotivev1alpha1.NotificationFormatSlack, "text"},
+		{automotivev1alpha1.NotificationFormatTeams, "text"},
+		{automotivev1alpha1.NotificationFormatMatrix, "body"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			body, err := Payload(&automotivev1alpha1.NotificationTarget{Format: tt.format}, event)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var payload map[string]string
+			if err := json.Unmarshal(body, &payload); err != nil {
+				t.Fatalf("invalid payload %s: %v", body, err)
+			}
+			if payload[tt.key] != summary {
+				t.Errorf("%s = %q, want %q", tt.key, payload[tt.key], summary)
+			}
+		})
+	}
+
+	body, err := Payload(&automotivev1alpha1.NotificationTarget{}, event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var generic Event
+	if err := json.Unmarshal(body, &generic); err != nil || generic.Phase != "Completed" || generic.Requester != "alice" {
+		t.Errorf("unexpected generic payload %s: %v", body, err)
+	}
+
+	body, err = Payload(&automotivev1alpha1.NotificationTarget{
+		Format:   automotivev1alpha1.NotificationFormatSlack,
+		Template: ":white_check_mark: {{.Name}} is {{.Phase}}",
+	}, event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(body), ":white_check_mark: my-build is Completed") {
+		t.Errorf("template not applied: %s", body)
+	}
+}
+
+func TestNotifyRetriesAndSigns(t *testing.T) {
+	var calls atomic.Int32
+	var signature, eventHeader string
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		signature = r.Header.Get(SignatureHeader)
+		eventHeader = r.Header.Get(EventHeader)
+		received, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	n := newNotifier(t, &automotivev1alpha1.NotificationsConfig{
+		Targets: []automotivev1alpha1.NotificationTarget{
+			{Name: "ci", URLSecretRef: "ci-webhook", SigningSecretRef: "ci-signing"},
+			{Name: "failures", URL: server.URL, Phases: []string{"Failed"}},
+		},
+	},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ci-webhook", Namespace: testNamespace},
+			Data:       map[string][]byte{"url": []byte(server.URL)},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ci-signing", Namespace: testNamespace},
+			Data:       map[string][]byte{"secret": []byte("s3cr3t")},
+		},
+	)
+
+	startNotifier(t, n)
+	n.Notify(context.Background(), testEvent())
+	n.Wait()
+
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("expected 2 deliveries (one retry), got %d", got)
+	}
+	if want := Sign([]byte("s3cr3t"), received); signature != want {
+		t.Errorf("signature = %q, want %q", signature, want)
+	}
+	if eventHeader != "ImageBuild.Completed" {
+		t.Errorf("event header = %q", eventHeader)
+	}
+}
+
+func TestNotifyRecordsDeadLetter(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	maxRetries := int32(2)
+	n := newNotifier(t, &automotivev1alpha1.NotificationsConfig{
+		MaxRetries: &maxRetries,
+		Targets:    []automotivev1alpha1.NotificationTarget{{Name: "chat", URL: server.URL}},
+	})
+
+	startNotifier(t, n)
+	n.Notify(context.Background(), testEvent())
+	n.Wait()
+
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+	records := deadLetters(t, n)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(records))
+	}
+	for _, record := range records {
+		if record.Target != "chat" || record.Attempts != 3 || record.Event.Name != "my-build" ||
+			!strings.Contains(record.Error, "502") {
+			t.Errorf("unexpected dead letter: %+v", record)
+		}
+	}
+}
+
+func TestNotifyDoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	n := newNotifier(t, &automotivev1alpha1.NotificationsConfig{
+		Targets: []automotivev1alpha1.NotificationTarget{{Name: "gone", URL: server.URL}},
+	})
+
+	startNotifier(t, n)
+	n.Notify(context.Background(), testEvent())
+	n.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected a single attempt, got %d", got)
+	}
+}
+
+func TestStartDrainsQueueOnShutdown(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	n := newNotifier(t, &automotivev1alpha1.NotificationsConfig{
+		Targets: []automotivev1alpha1.NotificationTarget{{Name: "chat", URL: server.URL}},
+	})
+	n.Notify(context.Background(), testEvent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := n.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected the queued notification to be delivered on shutdown, got %d deliveries", got)
+	}
+
+	n.Notify(context.Background(), testEvent())
+	n.Wait()
+	records := deadLetters(t, n)
+	if len(records) != 1 || !strings.Contains(records[0].Error, errStopped.Error()) {
+		t.Errorf("expected notifications after shutdown to be dead-lettered, got %+v", records)
+	}
+}
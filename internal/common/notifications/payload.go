@@ -0,0 +1,87 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+// Payload renders event in the format of target.
+func Payload(target *automotivev1alpha1.NotificationTarget, event Event) ([]byte, error) {
+	format := target.GetFormat()
+	if format == automotivev1alpha1.NotificationFormatGeneric {
+		return json.Marshal(event)
+	}
+
+	text, err := Message(target.Template, event)
+	if err != nil {
+		return nil, fmt.Errorf("rendering template of notification target %s: %w", target.Name, err)
+	}
+	switch format {
+	case automotivev1alpha1.NotificationFormatSlack:
+		return json.Marshal(map[string]string{"text": text})
+	case automotivev1alpha1.NotificationFormatTeams:
+		return json.Marshal(map[string]string{
+			"@type":      "MessageCard",
+			"@context":   "https://schema.org/extensions",
+			"summary":    fmt.Sprintf("%s %s %s", event.Kind, event.Name, event.Phase),
+			"themeColor": themeColor(event.Phase),
+			"text":       text,
+		})
+	case automotivev1alpha1.NotificationFormatMatrix:
+		// "body" is read by the Matrix client-server send API, "text" by
+		// hookshot-style generic webhooks
+		return json.Marshal(map[string]string{"msgtype": "m.text", "body": text, "text": text})
+	default:
+		return nil, fmt.Errorf("notification target %s has unknown format %q", target.Name, format)
+	}
+}
+
+// Message renders the chat message of event with tmpl, or with a one-line summary
+// of the transition when tmpl is empty.
+func Message(tmpl string, event Event) (string, error) {
+	if tmpl == "" {
+		return summary(event), nil
+	}
+	t, err := template.New("notification").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, event); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func summary(event Event) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s/%s", event.Kind, event.Namespace, event.Name)
+	if event.PreviousPhase != "" {
+		fmt.Fprintf(&b, ": %s -> %s", event.PreviousPhase, event.Phase)
+	} else {
+		fmt.Fprintf(&b, ": %s", event.Phase)
+	}
+	if event.Requester != "" {
+		fmt.Fprintf(&b, " (requested by %s)", event.Requester)
+	}
+	if event.Message != "" {
+		fmt.Fprintf(&b, ": %s", event.Message)
+	}
+	return b.String()
+}
+
+func themeColor(phase string) string {
+	switch phase {
+	case "Failed", "Unavailable":
+		return "D13438"
+	case "Completed", "Available":
+		return "2EB67D"
+	default:
+		return "0078D7"
+	}
+}
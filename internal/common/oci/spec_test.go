@@ -51,6 +51,8 @@ func TestReferrerFileMap(t *testing.T) {
 		"application/vnd.automotive.manifest.v1+yaml":    "manifest.aib.yml",
 		"application/vnd.automotive.sources.v1+tar+gzip": "build-sources.tar.gz",
 		"application/vnd.osbuild.manifest.v1+json":       "image.json",
+		"application/spdx+json":                          "sbom.spdx.json",
+		"application/vnd.cyclonedx+json":                 "sbom.cdx.json",
 	}
 	for artType, filename := range expected {
 		if m[artType] != filename {
@@ -0,0 +1,221 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// S3DigestHeader carries the sha256 of the object, written by the export task as user metadata.
+	S3DigestHeader = "X-Amz-Meta-Sha256"
+
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	amzDateFormat    = "20060102T150405Z"
+)
+
+// ParseS3URL splits an s3://bucket/key URL into its bucket and key.
+func ParseS3URL(raw string) (bucket, key string, err error) {
+	rest, ok := strings.CutPrefix(raw, "s3://")
+	if !ok {
+		return "", "", fmt.Errorf("invalid s3 URL %q: must start with s3://", raw)
+	}
+	bucket, key, _ = strings.Cut(rest, "/")
+	if bucket == "" || key == "" {
+		return "", "", fmt.Errorf("invalid s3 URL %q: expected s3://bucket/key", raw)
+	}
+	return bucket, key, nil
+}
+
+// S3Options configures an S3Backend.
+type S3Options struct {
+	// Endpoint is the service base URL; objects are addressed path-style.
+	Endpoint    string
+	Region      string
+	Bucket      string
+	Key         string
+	Credentials Credentials
+	// HTTPClient defaults to http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// S3Backend reads an object from S3-compatible storage using SigV4-signed requests.
+// Requests are sent anonymously when no access key is configured.
+type S3Backend struct {
+	opts      S3Options
+	objectURL *url.URL
+	now       func() time.Time
+}
+
+// NewS3Backend validates opts and returns a backend for the object they describe.
+func NewS3Backend(opts S3Options) (*S3Backend, error) {
+	if opts.Endpoint == "" || opts.Bucket == "" || opts.Key == "" {
+		return nil, fmt.Errorf("s3 location requires endpoint, bucket and key")
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(opts.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("invalid s3 endpoint %q: must be an http:// or https:// URL", opts.Endpoint)
+	}
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	objectURL := *endpoint
+	objectURL.Path = endpoint.Path + "/" + opts.Bucket + "/" + strings.TrimPrefix(opts.Key, "/")
+	// Send the path exactly as it is canonicalized for signing
+	objectURL.RawPath = awsURIEncode(objectURL.Path, false)
+	return &S3Backend{opts: opts, objectURL: &objectURL, now: time.Now}, nil
+}
+
+// String returns the s3:// form of the object location.
+func (b *S3Backend) String() string {
+	return "s3://" + b.opts.Bucket + "/" + strings.TrimPrefix(b.opts.Key, "/")
+}
+
+// Stat issues a HEAD request for the object.
+func (b *S3Backend) Stat(ctx context.Context) (*ObjectInfo, error) {
+	resp, err := b.do(ctx, http.MethodHead)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	return objectInfo(resp, S3DigestHeader), nil
+}
+
+// Open issues a GET request for the object.
+func (b *S3Backend) Open(ctx context.Context) (io.ReadCloser, *ObjectInfo, error) {
+	resp, err := b.do(ctx, http.MethodGet)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Body, objectInfo(resp, S3DigestHeader), nil
+}
+
+func (b *S3Backend) do(ctx context.Context, method string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, b.objectURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if b.opts.Credentials.AccessKeyID != "" {
+		signV4(req, b.opts.Credentials, b.opts.Region, "s3", b.now().UTC())
+	}
+	return doRequest(b.opts.HTTPClient, req, b.String())
+}
+
+// signV4 adds AWS Signature Version 4 headers to a request without a body.
+// The host, range and all x-amz-* headers are signed.
+func signV4(req *http.Request, creds Credentials, region, service string, now time.Time) {
+	amzDate := now.Format(amzDateFormat)
+	shortDate := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+	if creds.Token != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.Token)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "range" || lower == "content-type" {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalURI := awsURIEncode(req.URL.Path, false)
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		emptyPayloadHash,
+	}, "\n")
+
+	scope := shortDate + "/" + region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), shortDate)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func canonicalQuery(values url.Values) string {
+	if len(values) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		vals := append([]string(nil), values[k]...)
+		sort.Strings(vals)
+		for _, v := range vals {
+			parts = append(parts, awsURIEncode(k, true)+"="+awsURIEncode(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// awsURIEncode percent-encodes everything except the RFC 3986 unreserved
+// characters, and '/' unless encodeSlash is set.
+func awsURIEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,137 @@
+// Package storage provides access to disk images kept outside of container
+// registries: S3-compatible object storage and plain HTTP(S) servers.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+// Secret keys read by CredentialsFromSecret.
+const (
+	SecretKeyAccessKeyID     = "accessKeyId"
+	SecretKeySecretAccessKey = "secretAccessKey"
+	SecretKeyToken           = "token"
+	SecretKeyUsername        = "username"
+	SecretKeyPassword        = "password"
+)
+
+// ErrNotFound is returned when the object does not exist at the location.
+var ErrNotFound = errors.New("object not found")
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	// SizeBytes is the object size, or -1 when the server did not report it
+	SizeBytes int64
+	// Digest is the "sha256:<hex>" digest recorded alongside the object, if any
+	Digest string
+}
+
+// Backend reads a single stored object.
+type Backend interface {
+	// Stat returns information about the object without downloading it.
+	Stat(ctx context.Context) (*ObjectInfo, error)
+	// Open returns a reader for the object contents. The caller must close it.
+	Open(ctx context.Context) (io.ReadCloser, *ObjectInfo, error)
+}
+
+// Credentials authenticate requests to a storage backend. S3 uses the access
+// key pair (with Token as an optional session token); HTTP uses Token (bearer)
+// or Username/Password (basic).
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	Token           string
+	Username        string
+	Password        string
+}
+
+// CredentialsFromSecret reads storage credentials from a Kubernetes secret.
+func CredentialsFromSecret(secret *corev1.Secret) Credentials {
+	if secret == nil {
+		return Credentials{}
+	}
+	get := func(key string) string {
+		return strings.TrimSpace(string(secret.Data[key]))
+	}
+	return Credentials{
+		AccessKeyID:     get(SecretKeyAccessKeyID),
+		SecretAccessKey: get(SecretKeySecretAccessKey),
+		Token:           get(SecretKeyToken),
+		Username:        get(SecretKeyUsername),
+		Password:        get(SecretKeyPassword),
+	}
+}
+
+// Factory creates a Backend for an image location. It is the extension point
+// used by callers that need to substitute a different implementation in tests.
+type Factory func(location *automotivev1alpha1.ImageLocation, creds Credentials) (Backend, error)
+
+// NewBackend is the default Factory. It supports s3 and http locations; PVC
+// locations are only reachable from inside the cluster and are not handled here.
+func NewBackend(location *automotivev1alpha1.ImageLocation, creds Credentials) (Backend, error) {
+	switch location.Type {
+	case automotivev1alpha1.LocationTypeS3:
+		if location.S3 == nil {
+			return nil, fmt.Errorf("s3 location configuration is missing")
+		}
+		return NewS3Backend(S3Options{
+			Endpoint:    location.S3.Endpoint,
+			Region:      location.S3.GetRegion(),
+			Bucket:      location.S3.Bucket,
+			Key:         location.S3.Key,
+			Credentials: creds,
+		})
+	case automotivev1alpha1.LocationTypeHTTP:
+		if location.HTTP == nil {
+			return nil, fmt.Errorf("http location configuration is missing")
+		}
+		return NewHTTPBackend(location.HTTP.URL, creds, nil)
+	default:
+		return nil, fmt.Errorf("unsupported storage location type %q", location.Type)
+	}
+}
+
+// statusError converts a non-success HTTP response into an error.
+func statusError(resp *http.Response, target string) error {
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return fmt.Errorf("%s: %w", target, ErrNotFound)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%s: access denied (HTTP %d)", target, resp.StatusCode)
+	default:
+		return fmt.Errorf("%s: unexpected HTTP status %d", target, resp.StatusCode)
+	}
+}
+
+// objectInfo extracts size and digest from a response. digestHeader names the
+// header carrying a hex sha256 digest.
+func objectInfo(resp *http.Response, digestHeader string) *ObjectInfo {
+	info := &ObjectInfo{SizeBytes: resp.ContentLength}
+	if sum := strings.ToLower(strings.TrimSpace(resp.Header.Get(digestHeader))); sum != "" {
+		info.Digest = "sha256:" + strings.TrimPrefix(sum, "sha256:")
+	}
+	return info
+}
+
+// doRequest sends req and returns the response when the server reports success.
+func doRequest(client *http.Client, req *http.Request, target string) (*http.Response, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", target, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		_ = resp.Body.Close()
+		return nil, statusError(resp, target)
+	}
+	return resp, nil
+}
@@ -0,0 +1,85 @@
+package tasks
+
+import (
+	"testing"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+func TestConsoleParams(t *testing.T) {
+	params, err := ConsoleParams(nil)
+	if err != nil || params != nil {
+		t.Fatalf("expected no params without console capture, got %v, %v", params, err)
+	}
+
+	params, err = ConsoleParams(&automotivev1alpha1.ConsoleCaptureSpec{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := map[string]string{}
+	for _, p := range params {
+		got[p.Name] = p.Value.StringVal
+	}
+	want := map[string]string{
+		"console-enabled":     "true",
+		"console-seconds":     "300",
+		"console-power-cycle": "true",
+		"console-upload":      "true",
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("param %s = %q, want %q", name, got[name], value)
+		}
+	}
+
+	params, err = ConsoleParams(&automotivev1alpha1.ConsoleCaptureSpec{
+		Duration: "90s", SkipPowerCycle: true, SkipUpload: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got = map[string]string{}
+	for _, p := range params {
+		got[p.Name] = p.Value.StringVal
+	}
+	if got["console-seconds"] != "90" || got["console-power-cycle"] != "false" || got["console-upload"] != "false" {
+		t.Errorf("unexpected params: %v", got)
+	}
+
+	for _, duration := range []string{"soon", "-1m", "10ms"} {
+		if _, err := ConsoleParams(&automotivev1alpha1.ConsoleCaptureSpec{Duration: duration}); err == nil {
+			t.Errorf("expected error for duration %q", duration)
+		}
+	}
+}
+
+func TestGenerateFlashTask_ConsoleSidecar(t *testing.T) {
+	task := GenerateFlashTask("test-ns", &BuildConfig{})
+
+	if len(task.Spec.Sidecars) != 1 || task.Spec.Sidecars[0].Name != "console" {
+		t.Fatalf("expected a console sidecar, got %+v", task.Spec.Sidecars)
+	}
+	if task.Spec.Sidecars[0].Script != ConsoleCaptureScript {
+		t.Error("expected the console sidecar to run the console capture script")
+	}
+
+	params := map[string]string{}
+	for _, p := range task.Spec.Params {
+		if p.Default != nil {
+			params[p.Name] = p.Default.StringVal
+		}
+	}
+	if params["console-enabled"] != "false" {
+		t.Errorf("expected console capture to be disabled by default, got %q", params["console-enabled"])
+	}
+
+	hasResult := false
+	for _, result := range task.Spec.Results {
+		if result.Name == FlashResultConsoleRef {
+			hasResult = true
+		}
+	}
+	if !hasResult {
+		t.Errorf("expected %s result on the flash task", FlashResultConsoleRef)
+	}
+}
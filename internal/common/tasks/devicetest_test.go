@@ -0,0 +1,134 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+func TestDeviceTestParams_Disabled(t *testing.T) {
+	params, err := DeviceTestParams(nil, automotivev1alpha1.DefaultJumpstarterImage)
+	if err != nil || params != nil {
+		t.Fatalf("expected no params without tests, got %v, %v", params, err)
+	}
+}
+
+func TestDeviceTestParams_Defaults(t *testing.T) {
+	params, err := DeviceTestParams(&automotivev1alpha1.DeviceTestSpec{Script: "true"}, "quay.io/jumpstarter-dev/jumpstarter:v1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := map[string]string{}
+	for _, p := range params {
+		got[p.Name] = p.Value.StringVal
+	}
+	want := map[string]string{
+		"test-enabled":      "true",
+		"test-script":       "true",
+		"test-image":        "quay.io/jumpstarter-dev/jumpstarter:v1",
+		"test-boot-check":   automotivev1alpha1.DeviceTestBootCheckConsole,
+		"test-boot-marker":  automotivev1alpha1.DefaultDeviceTestBootMarker,
+		"test-boot-timeout": "600",
+		"test-power-cycle":  "true",
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("param %s = %q, want %q", name, got[name], value)
+		}
+	}
+}
+
+func TestDeviceTestParams_Overrides(t *testing.T) {
+	params, err := DeviceTestParams(&automotivev1alpha1.DeviceTestSpec{
+		Script:         "pytest",
+		Image:          "quay.io/org/smoke:latest",
+		BootCheck:      automotivev1alpha1.DeviceTestBootCheckSSH,
+		BootTimeout:    "90s",
+		SkipPowerCycle: true,
+	}, automotivev1alpha1.DefaultJumpstarterImage)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := map[string]string{}
+	for _, p := range params {
+		got[p.Name] = p.Value.StringVal
+	}
+	if got["test-image"] != "quay.io/org/smoke:latest" || got["test-boot-check"] != "ssh" ||
+		got["test-boot-timeout"] != "90" || got["test-power-cycle"] != "false" {
+		t.Errorf("unexpected params: %v", got)
+	}
+
+	if _, err := DeviceTestParams(&automotivev1alpha1.DeviceTestSpec{Script: "true", BootTimeout: "soon"}, ""); err == nil {
+		t.Error("expected error for invalid boot timeout")
+	}
+}
+
+func TestParseDeviceTestSummary(t *testing.T) {
+	results, err := ParseDeviceTestSummary("")
+	if err != nil || results != nil {
+		t.Fatalf("expected nil results for empty summary, got %v, %v", results, err)
+	}
+
+	results, err = ParseDeviceTestSummary(
+		`{"booted":true,"total":2,"passed":1,"failed":1,"skipped":0,"message":"1 of 2 tests failed",` +
+			`"failedCases":[{"suite":"smoke","name":"network","message":"no route"}]}`,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results.Succeeded() || results.Failed != 1 || len(results.FailedCases) != 1 || results.FailedCases[0].Name != "network" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+
+	if _, err := ParseDeviceTestSummary("not json"); err == nil {
+		t.Error("expected error for invalid summary")
+	}
+}
+
+func TestGenerateFlashTask_DeviceTestSteps(t *testing.T) {
+	task := GenerateFlashTask("test-ns", &BuildConfig{})
+
+	var steps []string
+	for _, step := range task.Spec.Steps {
+		steps = append(steps, step.Name)
+	}
+	joined := strings.Join(steps, ",")
+	if !strings.HasSuffix(joined, "boot-device,run-tests,save-console,collect-test-results") {
+		t.Errorf("expected device test steps after flashing, got %s", joined)
+	}
+	for _, step := range task.Spec.Steps {
+		if step.Name == "run-tests" && step.Image != "$(params.test-image)" {
+			t.Errorf("expected run-tests to use the test image param, got %s", step.Image)
+		}
+	}
+
+	hasResult := false
+	for _, result := range task.Spec.Results {
+		if result.Name == FlashResultTestSummary {
+			hasResult = true
+		}
+	}
+	if !hasResult {
+		t.Errorf("expected %s result on the flash task", FlashResultTestSummary)
+	}
+}
+
+func TestGenerateTektonPipeline_PassesDeviceTestParams(t *testing.T) {
+	pipeline := GenerateTektonPipeline("test-pipeline", "test-ns", &BuildConfig{})
+
+	for _, pt := range pipeline.Spec.Tasks {
+		if pt.Name != "flash-image" {
+			continue
+		}
+		params := map[string]string{}
+		for _, p := range pt.Params {
+			params[p.Name] = p.Value.StringVal
+		}
+		if params["test-script"] != "$(params.test-script)" {
+			t.Errorf("expected test-script to be passed through, got %q", params["test-script"])
+		}
+		return
+	}
+	t.Fatal("flash-image task not found in pipeline")
+}
@@ -0,0 +1,62 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratePushImageIndexTask(t *testing.T) {
+	task := GeneratePushImageIndexTask("test-ns", &BuildConfig{
+		AutomotiveImageBuilderImage: "quay.io/example/aib:pinned",
+	})
+
+	if task.Name != PushImageIndexTaskName || task.Namespace != "test-ns" {
+		t.Fatalf("unexpected task identity %s/%s", task.Namespace, task.Name)
+	}
+
+	params := map[string]string{}
+	for _, p := range task.Spec.Params {
+		def := ""
+		if p.Default != nil {
+			def = p.Default.StringVal
+		}
+		params[p.Name] = def
+	}
+	for _, name := range []string{"image-index", "images", "aib-image", "insecure-registry", traceIDParamName} {
+		if _, ok := params[name]; !ok {
+			t.Errorf("missing param %q", name)
+		}
+	}
+	if params["aib-image"] != "quay.io/example/aib:pinned" {
+		t.Errorf("aib-image default = %q, want configured image", params["aib-image"])
+	}
+
+	if len(task.Spec.Workspaces) != 1 || task.Spec.Workspaces[0].Name != "registry-auth" || !task.Spec.Workspaces[0].Optional {
+		t.Errorf("expected a single optional registry-auth workspace, got %+v", task.Spec.Workspaces)
+	}
+
+	if len(task.Spec.Steps) != 1 {
+		t.Fatalf("expected 1 step, got %d", len(task.Spec.Steps))
+	}
+	script := task.Spec.Steps[0].Script
+	if !strings.Contains(script, "buildah manifest push") {
+		t.Errorf("step script does not push a manifest list")
+	}
+	if !strings.Contains(script, "setup_registry_auth()") {
+		t.Errorf("step script is missing common.sh helpers")
+	}
+}
+
+func TestGeneratePushImageIndexTask_NilConfigUsesDefaults(t *testing.T) {
+	task := GeneratePushImageIndexTask("test-ns", nil)
+
+	for _, vol := range task.Spec.Volumes {
+		if vol.Name == customCAVolumeName {
+			if vol.ConfigMap == nil || vol.ConfigMap.Name != DefaultTrustedCABundleConfigMap {
+				t.Fatalf("expected default trusted CA configmap, got %+v", vol.VolumeSource)
+			}
+			return
+		}
+	}
+	t.Fatalf("%s volume not found in generated image index task", customCAVolumeName)
+}
@@ -22,6 +22,8 @@ func TestNoHardcodedOCIStringsInScripts(t *testing.T) {
 		"application/vnd.automotive.manifest.",
 		"application/vnd.automotive.sources.",
 		"application/vnd.osbuild.manifest.",
+		"application/spdx+json",
+		"application/vnd.cyclonedx+json",
 	}
 
 	allKeys := spec.AllManifestAnnotationKeys()
@@ -52,6 +54,23 @@ func TestNoHardcodedOCIStringsInScripts(t *testing.T) {
 	}
 }
 
+func TestSBOMReferrersInScripts(t *testing.T) {
+	shellVars := oci.Get().ShellVars()
+	for _, v := range []string{"SBOM_SPDX", "SBOM_CYCLONEDX"} {
+		for _, name := range []string{"OCI_REFERRER_TYPE_" + v, "OCI_REFERRER_FILE_" + v} {
+			if !strings.Contains(shellVars, name+"=") {
+				t.Errorf("spec shell vars do not define %s", name)
+			}
+			if !strings.Contains(BuildImageScript, "$"+name) {
+				t.Errorf("BuildImageScript does not use $%s", name)
+			}
+			if !strings.Contains(PushArtifactScript, "$"+name) {
+				t.Errorf("PushArtifactScript does not use $%s", name)
+			}
+		}
+	}
+}
+
 func stripGeneratedBlock(script, block string) string {
 	idx := strings.Index(script, block)
 	if idx < 0 {
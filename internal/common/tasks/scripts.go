@@ -40,13 +40,62 @@ var flashImageScript string
 // FlashImageScript contains the embedded shell script for flashing images via Jumpstarter.
 var FlashImageScript = ""
 
+//go:embed scripts/boot_device.sh
+var bootDeviceScript string
+
+// BootDeviceScript contains the embedded script that power-cycles a flashed
+// device and waits for it to boot.
+var BootDeviceScript = ""
+
+//go:embed scripts/run_device_tests.sh
+
+// RunDeviceTestsScript contains the embedded script that runs the user test
+// script. It runs in the user's test image and is not prefixed with common.sh.
+var RunDeviceTestsScript string
+
+//go:embed scripts/console_capture.sh
+var consoleCaptureScript string
+
+// ConsoleCaptureScript contains the embedded script of the flash task sidecar
+// that records the device serial console.
+var ConsoleCaptureScript = ""
+
+//go:embed scripts/save_console.sh
+var saveConsoleScript string
+
+// SaveConsoleScript contains the embedded script that attaches the serial
+// console transcript to the flashed image.
+var SaveConsoleScript = ""
+
+//go:embed scripts/collect_test_results.sh
+var collectTestResultsScript string
+
+// CollectTestResultsScript contains the embedded script that summarizes the
+// JUnit results of on-device tests.
+var CollectTestResultsScript = ""
+
+//go:embed scripts/workspace_snapshot.sh
+var workspaceSnapshotScript string
+
+// WorkspaceSnapshotScript contains the embedded script that exports a
+// workspace volume to the cluster registry and restores it into new workspaces.
+var WorkspaceSnapshotScript = ""
+
 func init() {
 	ociVars := oci.Get().ShellVars()
 	BuildImageScript = commonScript + "\n" + ociVars + "\n" + buildImageScript
 	BuildBuilderScript = commonScript + "\n" + buildBuilderScript
 	PushArtifactScript = commonScript + "\n" + ociVars + "\n" + pushArtifactScript
 	FlashImageScript = commonScript + "\n" + flashImageScript
+	BootDeviceScript = commonScript + "\n" + bootDeviceScript
+	ConsoleCaptureScript = commonScript + "\n" + consoleCaptureScript
+	SaveConsoleScript = commonScript + "\n" + saveConsoleScript
+	CollectTestResultsScript = commonScript + "\n" + collectTestResultsScript
 	SealedOperationScript = commonScript + "\n" + ociVars + "\n" + sealedOperationScript
+	PushImageIndexScript = commonScript + "\n" + pushImageIndexScript
+	PushArtifactStorageScript = commonScript + "\n" + pushArtifactStorageScript
+	SignArtifactsScript = commonScript + "\n" + signArtifactsScript
+	WorkspaceSnapshotScript = commonScript + "\n" + workspaceSnapshotScript
 }
 
 //go:embed scripts/sealed_operation.sh
@@ -55,3 +104,24 @@ var sealedOperationScript string
 // SealedOperationScript contains the embedded script for AIB sealed operations.
 // It is the concatenation of common.sh and sealed_operation.sh.
 var SealedOperationScript string
+
+//go:embed scripts/push_image_index.sh
+var pushImageIndexScript string
+
+// PushImageIndexScript contains the embedded script that assembles and pushes
+// the OCI image index of a multi-architecture build.
+var PushImageIndexScript string
+
+//go:embed scripts/push_artifact_storage.sh
+var pushArtifactStorageScript string
+
+// PushArtifactStorageScript contains the embedded script that exports disk
+// images to S3-compatible storage, HTTP endpoints or a PVC.
+var PushArtifactStorageScript string
+
+//go:embed scripts/sign_artifacts.sh
+var signArtifactsScript string
+
+// SignArtifactsScript contains the embedded script that signs the pushed
+// container and disk artifact with cosign and attests their SLSA provenance.
+var SignArtifactsScript string
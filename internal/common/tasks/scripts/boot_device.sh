@@ -0,0 +1,120 @@
+# NOTE: common.sh is prepended to this script at embed time.
+set +e
+set -uo pipefail
+
+TEST_ENABLED="${TEST_ENABLED:-false}"
+CONSOLE_ENABLED="${CONSOLE_ENABLED:-false}"
+if [[ "${TEST_ENABLED}" != "true" ]] && [[ "${CONSOLE_ENABLED}" != "true" ]]; then
+    echo "Device tests not enabled, skipping boot check"
+    exit 0
+fi
+
+emit_progress "Booting device" 0 1
+
+export JMP_CLIENT_CONFIG="${JMP_CLIENT_CONFIG:-/workspace/jumpstarter-client/client.yaml}"
+TEST_RESULTS_DIR="${TEST_RESULTS_DIR:-/workspace/test-results}"
+mkdir -p "${TEST_RESULTS_DIR}"
+
+LEASE_NAME=""
+if [[ -f "${LEASE_ID_PATH:-}" ]]; then
+    LEASE_NAME=$(cat "${LEASE_ID_PATH}")
+fi
+if [[ -z "${LEASE_NAME}" ]]; then
+    echo "ERROR: No Jumpstarter lease available to boot the device"
+    exit 1
+fi
+
+BOOT_CHECK="${BOOT_CHECK:-console}"
+BOOT_MARKER="${BOOT_MARKER:-login:}"
+BOOT_TIMEOUT="${BOOT_TIMEOUT:-600}"
+POWER_CYCLE="${POWER_CYCLE:-true}"
+CONSOLE_DIR="${CONSOLE_DIR:-/workspace/console}"
+CONSOLE_POWER_CYCLE="${CONSOLE_POWER_CYCLE:-true}"
+
+if [[ "${TEST_ENABLED}" != "true" ]]; then
+    POWER_CYCLE=false
+fi
+if [[ "${CONSOLE_ENABLED}" == "true" ]] && [[ "${CONSOLE_POWER_CYCLE}" == "true" ]]; then
+    POWER_CYCLE=true
+fi
+
+echo "=== Device Boot Check ==="
+echo "Lease: ${LEASE_NAME}"
+echo "Boot check: ${BOOT_CHECK} (timeout ${BOOT_TIMEOUT}s)"
+
+JMP_SHELL_ARGS="--client-config ${JMP_CLIENT_CONFIG} --lease ${LEASE_NAME}"
+
+if [[ "${CONSOLE_ENABLED}" == "true" ]]; then
+    # Hand the lease to the console sidecar and let it attach before the
+    # power cycle so the transcript covers the whole boot
+    echo "Starting serial console capture..."
+    echo -n "${LEASE_NAME}" > "${CONSOLE_DIR}/lease"
+    for _ in $(seq 1 30); do
+        [[ -f "${CONSOLE_DIR}/.capturing" ]] && break
+        sleep 1
+    done
+    if [[ ! -f "${CONSOLE_DIR}/.capturing" ]]; then
+        echo "WARNING: Serial console capture did not start"
+    fi
+    sleep 5
+fi
+
+if [[ "${POWER_CYCLE}" == "true" ]]; then
+    echo "Power-cycling device..."
+    # shellcheck disable=SC2086
+    if ! jmp shell ${JMP_SHELL_ARGS} -- j power cycle; then
+        echo "ERROR: Failed to power-cycle device"
+        exit 1
+    fi
+fi
+
+if [[ "${TEST_ENABLED}" != "true" ]]; then
+    echo "Device tests not enabled, skipping boot check"
+    emit_progress "Booting device" 1 1
+    exit 0
+fi
+
+BOOT_START=$(date +%s)
+case "${BOOT_CHECK}" in
+    console)
+        echo "Waiting for boot marker on console: ${BOOT_MARKER}"
+        if [[ "${CONSOLE_ENABLED}" == "true" ]]; then
+            # The console sidecar holds the serial port, follow its transcript
+            timeout "${BOOT_TIMEOUT}" tail -n +1 -F "${CONSOLE_DIR}/console.log" 2>/dev/null | \
+                grep -m1 -E -- "${BOOT_MARKER}" > /dev/null
+            BOOT_EXIT=${PIPESTATUS[1]}
+        else
+            # shellcheck disable=SC2086
+            timeout "${BOOT_TIMEOUT}" jmp shell ${JMP_SHELL_ARGS} -- j serial pipe 2>&1 | \
+                tee "${TEST_RESULTS_DIR}/console.log" | grep -m1 -E -- "${BOOT_MARKER}" > /dev/null
+            # The console pipe is cut off once grep matches, only grep's status counts
+            BOOT_EXIT=${PIPESTATUS[2]}
+        fi
+        ;;
+    ssh)
+        echo "Waiting for device to accept SSH connections"
+        BOOT_EXIT=1
+        DEADLINE=$((BOOT_START + BOOT_TIMEOUT))
+        while [[ $(date +%s) -lt ${DEADLINE} ]]; do
+            # shellcheck disable=SC2086
+            if timeout 60 jmp shell ${JMP_SHELL_ARGS} -- j ssh -- true > /dev/null 2>&1; then
+                BOOT_EXIT=0
+                break
+            fi
+            sleep 10
+        done
+        ;;
+    *)
+        echo "ERROR: Unknown boot check: ${BOOT_CHECK}"
+        exit 1
+        ;;
+esac
+
+if [[ ${BOOT_EXIT} -ne 0 ]]; then
+    echo "ERROR: Device did not boot within ${BOOT_TIMEOUT}s"
+    exit 1
+fi
+
+echo "Device booted after $(($(date +%s) - BOOT_START))s"
+touch "${TEST_RESULTS_DIR}/.booted"
+emit_progress "Booting device" 1 1
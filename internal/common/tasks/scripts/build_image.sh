@@ -87,21 +87,23 @@ else
   chmod g-s "/_build" 2>/dev/null || true
 fi
 
-RESTORE_SOURCES_REF="$(params.restore-sources-ref)"
-if [ -n "$RESTORE_SOURCES_REF" ]; then
-  echo "=== Restoring sources from $RESTORE_SOURCES_REF ==="
-
-  ORAS_VERSION="1.2.0"
+# install_oras downloads the ORAS CLI to $HOME/bin after verifying its checksum.
+install_oras() {
+  if command -v oras >/dev/null 2>&1; then
+    return 0
+  fi
+  local ORAS_VERSION="1.2.0" ORAS_ARCH
   case "$(uname -m)" in
     x86_64) ORAS_ARCH="amd64" ;;
     aarch64|arm64) ORAS_ARCH="arm64" ;;
-    *) echo "ERROR: Unsupported architecture: $(uname -m)" >&2; exit 1 ;;
+    *) echo "ERROR: Unsupported architecture: $(uname -m)" >&2; return 1 ;;
   esac
-  ORAS_TARBALL="oras_${ORAS_VERSION}_linux_${ORAS_ARCH}.tar.gz"
-  ORAS_BASE_URL="https://github.com/oras-project/oras/releases/download/v${ORAS_VERSION}"
-  ORAS_CHECKSUMS="oras_${ORAS_VERSION}_checksums.txt"
+  local ORAS_TARBALL="oras_${ORAS_VERSION}_linux_${ORAS_ARCH}.tar.gz"
+  local ORAS_BASE_URL="https://github.com/oras-project/oras/releases/download/v${ORAS_VERSION}"
+  local ORAS_CHECKSUMS="oras_${ORAS_VERSION}_checksums.txt"
   curl -sLO "${ORAS_BASE_URL}/${ORAS_TARBALL}"
   curl -sLO "${ORAS_BASE_URL}/${ORAS_CHECKSUMS}"
+  local expected_checksum actual_checksum
   expected_checksum=$(grep "${ORAS_TARBALL}" "${ORAS_CHECKSUMS}" | cut -d' ' -f1)
   if command -v sha256sum >/dev/null; then
     actual_checksum=$(sha256sum "${ORAS_TARBALL}" | cut -d' ' -f1)
@@ -109,13 +111,22 @@ if [ -n "$RESTORE_SOURCES_REF" ]; then
     actual_checksum=$(shasum -a 256 "${ORAS_TARBALL}" | cut -d' ' -f1)
   fi
   if [ "$expected_checksum" != "$actual_checksum" ]; then
-    echo "ERROR: ORAS checksum verification failed" >&2; exit 1
+    echo "ERROR: ORAS checksum verification failed" >&2
+    rm -f "$ORAS_TARBALL" "$ORAS_CHECKSUMS"
+    return 1
   fi
   tar -zxf "$ORAS_TARBALL" oras
   mkdir -p "$HOME/bin"
   mv oras "$HOME/bin/"
   rm -f "$ORAS_TARBALL" "$ORAS_CHECKSUMS"
   export PATH="$HOME/bin:$PATH"
+}
+
+RESTORE_SOURCES_REF="$(params.restore-sources-ref)"
+if [ -n "$RESTORE_SOURCES_REF" ]; then
+  echo "=== Restoring sources from $RESTORE_SOURCES_REF ==="
+
+  install_oras || exit 1
 
   ORAS_AUTH_FLAGS=()
   if [ -n "$REGISTRY_AUTH_FILE" ] && [ -f "$REGISTRY_AUTH_FILE" ]; then
@@ -668,6 +679,136 @@ fi
 
 cp "$BUILD_DIR/image.json" "$WORKSPACE_PATH/image.json" || echo "Failed to copy image.json"
 
+# Generate SPDX and CycloneDX SBOMs from the RPMs installed into the image.
+# The set is read from the rpm stages of the osbuild manifest (the buildroot
+# pipeline is skipped), resolved to NEVRAs through the manifest sources.
+if [ -f "$BUILD_DIR/image.json" ]; then
+  python3 - "$BUILD_DIR/image.json" "$WORKSPACE_PATH/$OCI_REFERRER_FILE_SBOM_SPDX" \
+    "$WORKSPACE_PATH/$OCI_REFERRER_FILE_SBOM_CYCLONEDX" "$(params.distro)" "$(params.target)" <<'PYEOF' \
+    || echo "WARNING: SBOM generation failed (non-fatal)"
+import json, re, sys, uuid
+from datetime import datetime, timezone
+from urllib.parse import quote, unquote, urlsplit
+
+manifest_path, spdx_path, cdx_path, distro, target = sys.argv[1:6]
+with open(manifest_path) as f:
+    manifest = json.load(f)
+
+locations = {}
+for source in manifest.get("sources", {}).values():
+    for checksum, item in source.get("items", {}).items():
+        if isinstance(item, dict):
+            item = item.get("url") or item.get("path") or ""
+        if isinstance(item, str):
+            locations[checksum] = item
+
+installed = []
+for pipeline in manifest.get("pipelines", []):
+    if pipeline.get("name") == "build":
+        continue
+    for stage in pipeline.get("stages", []):
+        if stage.get("type") != "org.osbuild.rpm":
+            continue
+        refs = stage.get("inputs", {}).get("packages", {}).get("references", {})
+        if isinstance(refs, dict):
+            refs = list(refs)
+        for ref in refs:
+            installed.append(ref.get("id") if isinstance(ref, dict) else ref)
+
+packages = {}
+for checksum in installed:
+    location = locations.get(checksum, "")
+    filename = unquote(urlsplit(location).path.rsplit("/", 1)[-1])
+    m = re.match(r"^(.+)-([^-]+)-([^-]+)\.([^.]+)\.rpm$", filename)
+    if not m:
+        continue
+    name, version, release, arch = m.groups()
+    packages[(name, arch)] = {
+        "name": name, "version": version, "release": release, "arch": arch,
+        "checksum": checksum,
+        "purl": "pkg:rpm/%s@%s-%s?arch=%s&distro=%s" % (quote(name, safe=""), version, release, arch, distro),
+    }
+if not packages:
+    print("No installed RPMs found in osbuild manifest, skipping SBOM generation")
+    sys.exit(0)
+
+image_name = "%s-%s" % (distro, target)
+created = datetime.now(timezone.utc).strftime("%Y-%m-%dT%H:%M:%SZ")
+sorted_packages = [packages[k] for k in sorted(packages)]
+
+def hashes(pkg):
+    algo, _, value = pkg["checksum"].partition(":")
+    return algo.upper(), value
+
+spdx_packages = []
+for i, pkg in enumerate(sorted_packages):
+    algo, value = hashes(pkg)
+    spdx_pkg = {
+        "SPDXID": "SPDXRef-Package-%d" % i,
+        "name": pkg["name"],
+        "versionInfo": "%s-%s" % (pkg["version"], pkg["release"]),
+        "downloadLocation": "NOASSERTION",
+        "licenseConcluded": "NOASSERTION",
+        "licenseDeclared": "NOASSERTION",
+        "copyrightText": "NOASSERTION",
+        "filesAnalyzed": False,
+        "externalRefs": [{
+            "referenceCategory": "PACKAGE-MANAGER",
+            "referenceType": "purl",
+            "referenceLocator": pkg["purl"],
+        }],
+    }
+    if algo == "SHA256":
+        spdx_pkg["checksums"] = [{"algorithm": algo, "checksumValue": value}]
+    spdx_packages.append(spdx_pkg)
+spdx = {
+    "spdxVersion": "SPDX-2.3",
+    "dataLicense": "CC0-1.0",
+    "SPDXID": "SPDXRef-DOCUMENT",
+    "name": image_name,
+    "documentNamespace": "https://automotive.sdv.cloud.redhat.com/spdx/%s-%s" % (image_name, uuid.uuid4()),
+    "creationInfo": {"created": created, "creators": ["Tool: automotive-dev-operator"]},
+    "packages": spdx_packages,
+    "relationships": [
+        {"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": p["SPDXID"]}
+        for p in spdx_packages
+    ],
+}
+
+components = []
+for pkg in sorted_packages:
+    algo, value = hashes(pkg)
+    component = {
+        "type": "library",
+        "bom-ref": pkg["purl"],
+        "name": pkg["name"],
+        "version": "%s-%s" % (pkg["version"], pkg["release"]),
+        "purl": pkg["purl"],
+    }
+    if algo == "SHA256":
+        component["hashes"] = [{"alg": "SHA-256", "content": value}]
+    components.append(component)
+cdx = {
+    "bomFormat": "CycloneDX",
+    "specVersion": "1.5",
+    "serialNumber": "urn:uuid:%s" % uuid.uuid4(),
+    "version": 1,
+    "metadata": {
+        "timestamp": created,
+        "tools": {"components": [{"type": "application", "name": "automotive-dev-operator"}]},
+        "component": {"type": "operating-system", "bom-ref": image_name, "name": image_name},
+    },
+    "components": components,
+}
+
+with open(spdx_path, "w") as f:
+    json.dump(spdx, f, indent=2)
+with open(cdx_path, "w") as f:
+    json.dump(cdx, f, indent=2)
+print("Generated SBOMs for %d installed packages" % len(sorted_packages))
+PYEOF
+fi
+
 COMPRESSION="$(params.compression)"
 if [ "$BUILD_DISK_IMAGE" = "true" ] || [ "$BUILD_MODE" = "image" ] || [ "$BUILD_MODE" = "package" ] || [ "$BUILD_MODE" = "disk" ]; then
   emit_progress "Compressing artifacts" "$((STEP_FINALIZE - 1))" "$PROGRESS_TOTAL"
@@ -901,6 +1042,37 @@ if [ -n "${CONTAINER_PUSH:-}" ]; then
   mkdir -p "$WORKSPACE_PATH/.chains/container"
   echo -n "$CONTAINER_PUSH" > "$WORKSPACE_PATH/.chains/container/url"
   echo -n "$PUSHED_DIGEST" > "$WORKSPACE_PATH/.chains/container/digest"
+
+  # Attach the SBOMs to the pushed container as OCI referrers
+  if [ -n "$PUSHED_DIGEST" ] && [ -f "$WORKSPACE_PATH/$OCI_REFERRER_FILE_SBOM_SPDX" ]; then
+    if install_oras; then
+      SBOM_ORAS_ARGS=()
+      if [ -n "$REGISTRY_AUTH_FILE" ] && [ -f "$REGISTRY_AUTH_FILE" ]; then
+        SBOM_ORAS_ARGS=(--registry-config "$REGISTRY_AUTH_FILE")
+      fi
+      if [ "$INSECURE_REGISTRY" = "true" ]; then
+        read -ra SBOM_PROTOCOL_ARGS <<< "$(detect_registry_protocol "${CONTAINER_PUSH%%/*}")"
+        SBOM_ORAS_ARGS+=("${SBOM_PROTOCOL_ARGS[@]}")
+      fi
+      CONTAINER_REPO="${CONTAINER_PUSH%@*}"
+      if [[ "${CONTAINER_REPO##*/}" == *:* ]]; then
+        CONTAINER_REPO="${CONTAINER_REPO%:*}"
+      fi
+      pushd "$WORKSPACE_PATH" >/dev/null
+      for sbom in "$OCI_REFERRER_FILE_SBOM_SPDX:$OCI_REFERRER_TYPE_SBOM_SPDX" \
+        "$OCI_REFERRER_FILE_SBOM_CYCLONEDX:$OCI_REFERRER_TYPE_SBOM_CYCLONEDX"; do
+        sbom_file="${sbom%%:*}"
+        sbom_type="${sbom#*:}"
+        echo "Attaching ${sbom_file} to ${CONTAINER_REPO}@${PUSHED_DIGEST}"
+        oras attach "${SBOM_ORAS_ARGS[@]}" --artifact-type "$sbom_type" \
+          "${CONTAINER_REPO}@${PUSHED_DIGEST}" "./${sbom_file}:${sbom_type}" \
+          || echo "WARNING: Failed to attach ${sbom_file} — registry may not support OCI referrers (non-fatal)"
+      done
+      popd >/dev/null
+    else
+      echo "WARNING: ORAS unavailable, SBOMs not attached to container (non-fatal)"
+    fi
+  fi
 fi
 
 # Package osbuild sources and manifest for reproducible builds.
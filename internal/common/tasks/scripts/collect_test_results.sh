@@ -0,0 +1,87 @@
+# NOTE: common.sh is prepended to this script at embed time.
+set -uo pipefail
+
+if [[ "${TEST_ENABLED:-false}" != "true" ]]; then
+    exit 0
+fi
+
+TEST_RESULTS_DIR="${TEST_RESULTS_DIR:-/workspace/test-results}"
+
+echo "=== Device Test Results ==="
+find "${TEST_RESULTS_DIR}" -name '*.xml' -type f | sort | while read -r junit; do
+    echo "--- ${junit#"${TEST_RESULTS_DIR}"/} ---"
+    cat "${junit}"
+    echo ""
+done
+
+# Summarize the JUnit results into the test-summary result. Tekton results
+# are size limited, so only the first failed cases are listed.
+python3 - "${TEST_RESULTS_DIR}" "${RESULTS_TEST_SUMMARY_PATH:-/dev/null}" <<'PYEOF'
+import glob
+import json
+import os
+import sys
+import xml.etree.ElementTree as ET
+
+results_dir, summary_path = sys.argv[1], sys.argv[2]
+MAX_FAILED_CASES = 10
+MAX_MESSAGE = 200
+
+summary = {
+    "booted": os.path.exists(os.path.join(results_dir, ".booted")),
+    "total": 0, "passed": 0, "failed": 0, "skipped": 0,
+}
+failed_cases = []
+
+
+def add_failure(suite, name, message):
+    summary["failed"] += 1
+    if len(failed_cases) < MAX_FAILED_CASES:
+        failed_cases.append({"suite": suite, "name": name, "message": message[:MAX_MESSAGE]})
+
+
+files = sorted(glob.glob(os.path.join(results_dir, "**", "*.xml"), recursive=True))
+for path in files:
+    try:
+        root = ET.parse(path).getroot()
+    except ET.ParseError as e:
+        add_failure(os.path.basename(path), "parse", "invalid JUnit XML: %s" % e)
+        continue
+    suites = [root] if root.tag == "testsuite" else root.iter("testsuite")
+    for suite in suites:
+        for case in suite.iter("testcase"):
+            summary["total"] += 1
+            problem = case.find("failure")
+            if problem is None:
+                problem = case.find("error")
+            if problem is not None:
+                message = problem.get("message") or (problem.text or "").strip()
+                add_failure(suite.get("name", ""), case.get("name", ""), message)
+            elif case.find("skipped") is not None:
+                summary["skipped"] += 1
+            else:
+                summary["passed"] += 1
+
+exit_code_file = os.path.join(results_dir, ".exit-code")
+if not summary["booted"]:
+    summary["message"] = "Device did not boot"
+elif not files:
+    # No JUnit output: report the test script as a single test case
+    exit_code = open(exit_code_file).read().strip() if os.path.exists(exit_code_file) else "unknown"
+    summary["total"] = 1
+    if exit_code == "0":
+        summary["passed"] = 1
+    else:
+        add_failure("device-test", "test-script", "test script exited with code %s" % exit_code)
+
+if "message" not in summary:
+    summary["message"] = "%d passed, %d failed, %d skipped" % (
+        summary["passed"], summary["failed"], summary["skipped"])
+if failed_cases:
+    summary["failedCases"] = failed_cases
+
+with open(summary_path, "w") as f:
+    json.dump(summary, f, separators=(",", ":"))
+print("Summary: " + summary["message"])
+sys.exit(0 if summary["booted"] and summary["failed"] == 0 else 1)
+PYEOF
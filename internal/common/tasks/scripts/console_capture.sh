@@ -0,0 +1,26 @@
+# NOTE: common.sh is prepended to this script at embed time.
+# Runs as a sidecar of the flash task. Once boot-device hands over the lease,
+# the device serial console is mirrored to this container's log, which the
+# Build API streams live, and recorded for the save-console step.
+set -uo pipefail
+
+if [[ "${CONSOLE_ENABLED:-false}" != "true" ]]; then
+    exit 0
+fi
+
+export JMP_CLIENT_CONFIG="${JMP_CLIENT_CONFIG:-/workspace/jumpstarter-client/client.yaml}"
+CONSOLE_DIR="${CONSOLE_DIR:-/workspace/console}"
+CONSOLE_SECONDS="${CONSOLE_SECONDS:-300}"
+
+echo "Waiting for the device to be flashed..."
+while [[ ! -s "${CONSOLE_DIR}/lease" ]]; do
+    sleep 2
+done
+LEASE_NAME=$(cat "${CONSOLE_DIR}/lease")
+
+echo "=== Serial console (lease ${LEASE_NAME}, ${CONSOLE_SECONDS}s) ==="
+touch "${CONSOLE_DIR}/.capturing"
+timeout "${CONSOLE_SECONDS}" jmp shell --client-config "${JMP_CLIENT_CONFIG}" --lease "${LEASE_NAME}" \
+    -- j serial pipe 2>&1 | tee "${CONSOLE_DIR}/console.log"
+echo "=== End of serial console ==="
+touch "${CONSOLE_DIR}/.done"
@@ -61,6 +61,33 @@ echo ""
 if [[ -n "${RESULTS_LEASE_ID_PATH:-}" ]]; then
     echo -n "${LEASE_NAME}" > "${RESULTS_LEASE_ID_PATH}"
 fi
+if [[ -n "${RESULTS_LEASE_RELEASED_PATH:-}" ]]; then
+    echo -n "false" > "${RESULTS_LEASE_RELEASED_PATH}"
+fi
+
+# Record the exporter the lease was granted on (best effort)
+EXPORTER_NAME=$(jmp get leases --client-config "${JMP_CLIENT_CONFIG}" -o json 2>/dev/null | python3 -c '
+import json
+import sys
+
+lease_name = sys.argv[1]
+try:
+    data = json.load(sys.stdin)
+except ValueError:
+    sys.exit(0)
+leases = data.get("leases", data.get("items", [])) if isinstance(data, dict) else data
+for lease in leases:
+    name = lease.get("name") or lease.get("metadata", {}).get("name", "")
+    if name == lease_name or name.endswith("/" + lease_name):
+        print(lease.get("exporter") or lease.get("status", {}).get("exporter", ""))
+        break
+' "${LEASE_NAME}" 2>/dev/null || true)
+if [[ -n "${EXPORTER_NAME}" ]]; then
+    echo "Exporter: ${EXPORTER_NAME}"
+    if [[ -n "${RESULTS_EXPORTER_PATH:-}" ]]; then
+        echo -n "${EXPORTER_NAME}" > "${RESULTS_EXPORTER_PATH}"
+    fi
+fi
 
 FLASH_SUCCESS=false
 
@@ -68,7 +95,10 @@ cleanup() {
     if [[ "${FLASH_SUCCESS}" != "true" ]] && [[ "${USER_PROVIDED_LEASE}" != "true" ]]; then
         echo ""
         echo "Releasing lease ${LEASE_NAME} due to failure..."
-        jmp delete leases --client-config "${JMP_CLIENT_CONFIG}" "${LEASE_NAME}" || true
+        if jmp delete leases --client-config "${JMP_CLIENT_CONFIG}" "${LEASE_NAME}" && \
+            [[ -n "${RESULTS_LEASE_RELEASED_PATH:-}" ]]; then
+            echo -n "true" > "${RESULTS_LEASE_RELEASED_PATH}"
+        fi
     fi
 }
 trap cleanup EXIT
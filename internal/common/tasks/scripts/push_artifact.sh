@@ -528,6 +528,31 @@ else
   echo "No osbuild manifest found or no digest available, skipping manifest attach"
 fi
 
+# Attach the SBOMs generated by the build task as OCI referrers.
+if [ -f "/workspace/shared/$OCI_REFERRER_FILE_SBOM_SPDX" ] && [ -n "$DISK_DIGEST" ]; then
+  pushd /workspace/shared >/dev/null || { echo "ERROR: cannot cd to /workspace/shared"; exit 1; }
+  for sbom in "$OCI_REFERRER_FILE_SBOM_SPDX:$OCI_REFERRER_TYPE_SBOM_SPDX" \
+    "$OCI_REFERRER_FILE_SBOM_CYCLONEDX:$OCI_REFERRER_TYPE_SBOM_CYCLONEDX"; do
+    sbom_file="${sbom%%:*}"
+    sbom_type="${sbom#*:}"
+    # Use a relative path so ORAS stores the file name as the OCI title
+    echo "Attaching ${sbom_file} to ${repo_url}@${DISK_DIGEST}"
+    if ! "$HOME/bin/oras" attach "${ORAS_EXTRA_ARGS[@]}" \
+      --artifact-type "$sbom_type" \
+      "${repo_url}@${DISK_DIGEST}" \
+      "./${sbom_file}:${sbom_type}" 2>&1; then
+      if [ "$SECURE_BUILD" = "true" ]; then
+        echo "ERROR: Failed to attach ${sbom_file} (fatal in secure build mode)"
+        exit 1
+      fi
+      echo "WARNING: Failed to attach ${sbom_file} — registry may not support OCI referrers (non-fatal)"
+    fi
+  done
+  popd >/dev/null
+else
+  echo "No SBOM found or no digest available, skipping SBOM attach"
+fi
+
 # attach_referrer FILE ARTIFACT_TYPE LABEL
 # Attaches a file as an OCI referrer. Fatal on failure in reproducible mode.
 attach_referrer() {
@@ -0,0 +1,130 @@
+# NOTE: common.sh is prepended to this script at embed time.
+#
+# Uploads the disk image produced by the build task to S3-compatible object
+# storage, an HTTP(S) endpoint (PUT) or a PersistentVolumeClaim.
+# Expects EXPORT_TYPE, EXPORT_URL, S3_ENDPOINT, S3_REGION, ARTIFACT_FILENAME,
+# EXPECTED_DIGEST and the RESULT_*_PATH variables in the environment.
+
+AUTH_DIR="/workspace/export-auth"
+PVC_DIR="/workspace/export-pvc"
+
+read_auth() {
+  local key="$1"
+  if [ -f "$AUTH_DIR/$key" ]; then
+    tr -d '\n' < "$AUTH_DIR/$key"
+  fi
+}
+
+# curl options shared by the s3 and http uploads; trusts the custom CA bundle when mounted
+CURL_COMMON=(-fsS --retry 3)
+if ls /etc/pki/ca-trust/custom/*.pem /etc/pki/ca-trust/custom/*.crt >/dev/null 2>&1; then
+  ca_bundle="$(mktemp)"
+  cat /etc/pki/tls/certs/ca-bundle.crt /etc/pki/ca-trust/custom/*.pem /etc/pki/ca-trust/custom/*.crt > "$ca_bundle" 2>/dev/null || true
+  CURL_COMMON+=(--cacert "$ca_bundle")
+fi
+
+exportFile=$(echo "$ARTIFACT_FILENAME" | tr -d '[:space:]')
+if [ -z "$exportFile" ]; then
+  echo "ERROR: artifact-filename param is empty" >&2
+  ls -la /workspace/shared/
+  exit 1
+fi
+
+cd /workspace/shared
+if [ ! -f "$exportFile" ]; then
+  echo "ERROR: Artifact file not found: ${exportFile}" >&2
+  echo "Storage exports upload a single file; multi-part artifacts are published as the combined archive." >&2
+  ls -la /workspace/shared/
+  exit 1
+fi
+
+artifact_sha=$(sha256sum "$exportFile" | cut -d' ' -f1)
+artifact_size=$(stat -L -c %s "$exportFile")
+
+if [ -n "$EXPECTED_DIGEST" ]; then
+  actual_digest=$(compute_artifact_digest "${exportFile}-parts" "$exportFile")
+  if [ -n "$actual_digest" ] && [ "$EXPECTED_DIGEST" != "$actual_digest" ]; then
+    echo "ERROR: Artifact integrity check failed!" >&2
+    echo "  Expected: $EXPECTED_DIGEST" >&2
+    echo "  Actual:   $actual_digest" >&2
+    exit 1
+  fi
+  echo "Integrity verified: ${actual_digest:-skipped}"
+fi
+
+echo "=== Storage Export Configuration ==="
+echo "  Artifact file: ${exportFile} (${artifact_size} bytes, sha256:${artifact_sha})"
+echo "  Export type:   ${EXPORT_TYPE}"
+echo "  Destination:   ${EXPORT_URL}"
+echo ""
+
+emit_progress "Exporting artifact" 0 1
+
+case "$EXPORT_TYPE" in
+  s3)
+    if [ -z "$S3_ENDPOINT" ]; then
+      echo "ERROR: s3 export requires an endpoint" >&2
+      exit 1
+    fi
+    object_url="${S3_ENDPOINT%/}/${EXPORT_URL#/}"
+    result_url="s3://${EXPORT_URL#/}"
+    access_key=$(read_auth accessKeyId)
+    secret_key=$(read_auth secretAccessKey)
+    session_token=$(read_auth token)
+    curl_args=("${CURL_COMMON[@]}" -X PUT -T "$exportFile"
+      -H "x-amz-meta-sha256: ${artifact_sha}"
+      -H "x-amz-content-sha256: UNSIGNED-PAYLOAD")
+    if [ -n "$access_key" ]; then
+      curl_args+=(--aws-sigv4 "aws:amz:${S3_REGION:-us-east-1}:s3" --user "${access_key}:${secret_key}")
+    fi
+    if [ -n "$session_token" ]; then
+      curl_args+=(-H "x-amz-security-token: ${session_token}")
+    fi
+    echo "Uploading to ${object_url}"
+    curl "${curl_args[@]}" "$object_url"
+    ;;
+  http)
+    result_url="$EXPORT_URL"
+    token=$(read_auth token)
+    username=$(read_auth username)
+    password=$(read_auth password)
+    curl_args=("${CURL_COMMON[@]}" -T "$exportFile" -H "X-Checksum-Sha256: ${artifact_sha}")
+    if [ -n "$token" ]; then
+      curl_args+=(-H "Authorization: Bearer ${token}")
+    elif [ -n "$username" ]; then
+      curl_args+=(--user "${username}:${password}")
+    fi
+    echo "Uploading to ${EXPORT_URL}"
+    curl "${curl_args[@]}" "$EXPORT_URL"
+    ;;
+  pvc)
+    if [ ! -d "$PVC_DIR" ]; then
+      echo "ERROR: pvc export requires the export-pvc workspace" >&2
+      exit 1
+    fi
+    dest_path="${EXPORT_URL#/}"
+    case "/${dest_path}/" in
+      */../*)
+        echo "ERROR: pvc export path must not contain '..': ${EXPORT_URL}" >&2
+        exit 1
+        ;;
+    esac
+    result_url="pvc:${dest_path}"
+    mkdir -p "$(dirname "${PVC_DIR}/${dest_path}")"
+    echo "Copying to ${PVC_DIR}/${dest_path}"
+    cp -L "$exportFile" "${PVC_DIR}/${dest_path}.partial"
+    mv -f "${PVC_DIR}/${dest_path}.partial" "${PVC_DIR}/${dest_path}"
+    ;;
+  *)
+    echo "ERROR: unsupported export type: ${EXPORT_TYPE}" >&2
+    exit 1
+    ;;
+esac
+
+emit_progress "Exporting artifact" 1 1
+
+echo -n "$result_url" > "$RESULT_URL_PATH"
+echo -n "sha256:${artifact_sha}" > "$RESULT_DIGEST_PATH"
+echo -n "$artifact_size" > "$RESULT_SIZE_PATH"
+
+echo "=== Artifact exported to ${result_url} ==="
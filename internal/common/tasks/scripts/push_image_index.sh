@@ -0,0 +1,45 @@
+#!/bin/bash
+set -e
+
+# Assemble an OCI image index from the per-architecture bootc containers of a
+# multi-architecture build and push it to the unsuffixed reference.
+# Env: IMAGE_INDEX (target ref), IMAGES (newline-separated per-arch refs),
+#      INSECURE_REGISTRY, RESULT_URL_PATH, RESULT_DIGEST_PATH
+
+validate_container_ref "$IMAGE_INDEX"
+
+install_custom_ca_certs
+setup_container_config
+setup_cluster_auth
+read_registry_creds "/workspace/registry-auth"
+setup_registry_auth || echo "No custom registry auth found, using cluster auth only"
+
+TLS_ARGS=()
+if [ "$INSECURE_REGISTRY" = "true" ]; then
+  TLS_ARGS=(--tls-verify=false)
+fi
+
+LOCAL_INDEX="localhost/image-index:latest"
+buildah manifest create "$LOCAL_INDEX"
+
+count=0
+while IFS= read -r ref; do
+  [ -z "$ref" ] && continue
+  validate_container_ref "$ref"
+  echo "Adding $ref to image index"
+  buildah manifest add "${TLS_ARGS[@]}" --authfile="$REGISTRY_AUTH_FILE" "$LOCAL_INDEX" "docker://$ref"
+  count=$((count + 1))
+done <<< "$IMAGES"
+
+if [ "$count" -eq 0 ]; then
+  echo "ERROR: No per-architecture images provided"
+  exit 1
+fi
+
+echo "Pushing image index with $count architectures to $IMAGE_INDEX"
+buildah manifest push "${TLS_ARGS[@]}" --authfile="$REGISTRY_AUTH_FILE" --all --format oci \
+  --digestfile /tmp/image-index-digest "$LOCAL_INDEX" "docker://$IMAGE_INDEX"
+
+echo -n "$IMAGE_INDEX" > "$RESULT_URL_PATH"
+cat /tmp/image-index-digest > "$RESULT_DIGEST_PATH"
+echo "Image index digest: $(cat /tmp/image-index-digest)"
@@ -0,0 +1,40 @@
+#!/bin/sh
+# Runs in the user-supplied test image, which may only provide a POSIX shell.
+set -u
+
+if [ "${TEST_ENABLED:-false}" != "true" ]; then
+    echo "Device tests not enabled, skipping"
+    exit 0
+fi
+
+TEST_RESULTS_DIR="${TEST_RESULTS_DIR:-/workspace/test-results}"
+export TEST_RESULTS_DIR
+if [ ! -f "${TEST_RESULTS_DIR}/.booted" ]; then
+    echo "Device did not boot, skipping tests"
+    exit 0
+fi
+
+if [ -f "${LEASE_ID_PATH:-}" ]; then
+    JMP_LEASE=$(cat "${LEASE_ID_PATH}")
+    export JMP_LEASE
+fi
+export JMP_CLIENT_CONFIG="${JMP_CLIENT_CONFIG:-/workspace/jumpstarter-client/client.yaml}"
+
+echo "=== Device Tests ==="
+echo "Lease: ${JMP_LEASE:-}"
+
+TEST_SCRIPT_FILE="$(mktemp)"
+printf '%s\n' "${TEST_SCRIPT}" > "${TEST_SCRIPT_FILE}"
+chmod +x "${TEST_SCRIPT_FILE}"
+
+# Honor the script's interpreter line, default to sh
+if [ "$(head -c 2 "${TEST_SCRIPT_FILE}")" = "#!" ]; then
+    "${TEST_SCRIPT_FILE}"
+else
+    sh "${TEST_SCRIPT_FILE}"
+fi
+TEST_EXIT=$?
+
+echo "${TEST_EXIT}" > "${TEST_RESULTS_DIR}/.exit-code"
+echo "Test script exited with code ${TEST_EXIT}"
+exit "${TEST_EXIT}"
@@ -0,0 +1,79 @@
+# NOTE: common.sh is prepended to this script at embed time.
+# Waits for the console sidecar to finish recording and attaches the
+# transcript to the flashed image as an OCI referrer.
+set -uo pipefail
+
+if [[ "${CONSOLE_ENABLED:-false}" != "true" ]]; then
+    exit 0
+fi
+
+CONSOLE_DIR="${CONSOLE_DIR:-/workspace/console}"
+CONSOLE_SECONDS="${CONSOLE_SECONDS:-300}"
+TRANSCRIPT="${CONSOLE_DIR}/console.log"
+
+if [[ ! -f "${CONSOLE_DIR}/.capturing" ]]; then
+    echo "Serial console was not recorded, the device was not flashed"
+    exit 0
+fi
+
+echo "Waiting for the serial console recording to finish..."
+DEADLINE=$(($(date +%s) + CONSOLE_SECONDS + 120))
+while [[ ! -f "${CONSOLE_DIR}/.done" ]] && [[ $(date +%s) -lt ${DEADLINE} ]]; do
+    sleep 5
+done
+if [[ ! -s "${TRANSCRIPT}" ]]; then
+    echo "WARNING: Serial console transcript is empty"
+    exit 0
+fi
+echo "Recorded $(wc -c < "${TRANSCRIPT}") bytes of serial console output"
+
+if [[ "${CONSOLE_UPLOAD:-true}" != "true" ]]; then
+    echo "Transcript upload disabled, the console is only kept in the TaskRun logs"
+    exit 0
+fi
+
+emit_progress "Uploading console transcript" 0 1
+
+ORAS_VERSION="1.2.0"
+case "$(uname -m)" in
+    x86_64) ORAS_ARCH="amd64" ;;
+    aarch64|arm64) ORAS_ARCH="arm64" ;;
+    *) echo "ERROR: Unsupported architecture $(uname -m)"; exit 1 ;;
+esac
+ORAS_TARBALL="oras_${ORAS_VERSION}_linux_${ORAS_ARCH}.tar.gz"
+ORAS_BASE_URL="https://github.com/oras-project/oras/releases/download/v${ORAS_VERSION}"
+ORAS_CHECKSUMS="oras_${ORAS_VERSION}_checksums.txt"
+
+cd /tmp
+curl -sSLf -o "${ORAS_TARBALL}" "${ORAS_BASE_URL}/${ORAS_TARBALL}" || {
+    echo "ERROR: Failed to download oras"; exit 1
+}
+curl -sSLf -o "${ORAS_CHECKSUMS}" "${ORAS_BASE_URL}/${ORAS_CHECKSUMS}" || {
+    echo "ERROR: Failed to download oras checksums"; exit 1
+}
+EXPECTED_CHECKSUM=$(grep " ${ORAS_TARBALL}\$" "${ORAS_CHECKSUMS}" | cut -d' ' -f1)
+ACTUAL_CHECKSUM=$(sha256sum "${ORAS_TARBALL}" | cut -d' ' -f1)
+if [[ -z "${EXPECTED_CHECKSUM}" ]] || [[ "${EXPECTED_CHECKSUM}" != "${ACTUAL_CHECKSUM}" ]]; then
+    echo "ERROR: Checksum verification failed for ${ORAS_TARBALL}"
+    exit 1
+fi
+tar -xzf "${ORAS_TARBALL}" oras
+
+ORAS_LOGIN_ARGS=()
+if [[ -f "${FLASH_OCI_AUTH_PATH:-}/username" ]] && [[ -f "${FLASH_OCI_AUTH_PATH:-}/password" ]]; then
+    ORAS_LOGIN_ARGS=(--username "$(cat "${FLASH_OCI_AUTH_PATH}/username")" \
+        --password "$(cat "${FLASH_OCI_AUTH_PATH}/password")")
+fi
+
+# Attach from the console dir so the layer is titled console.log
+if ! ATTACH_OUTPUT=$(cd "${CONSOLE_DIR}" && /tmp/oras attach "${ORAS_LOGIN_ARGS[@]}" \
+    --artifact-type "${CONSOLE_ARTIFACT_TYPE}" --format json \
+    "${IMAGE_REF}" "console.log:text/plain"); then
+    echo "WARNING: Failed to attach the console transcript to ${IMAGE_REF}"
+    exit 1
+fi
+CONSOLE_REF=$(echo "${ATTACH_OUTPUT}" | python3 -c 'import json,sys; print(json.load(sys.stdin)["reference"])')
+
+echo "Console transcript attached: ${CONSOLE_REF}"
+echo -n "${CONSOLE_REF}" > "${RESULTS_CONSOLE_REF_PATH}"
+emit_progress "Uploading console transcript" 1 1
@@ -0,0 +1,182 @@
+# NOTE: common.sh is prepended to this script at embed time.
+
+# Signs the bootc container and disk artifact pushed by the build with cosign
+# and attaches SLSA v1 provenance, built from the PipelineRun, as a signed
+# attestation. Artifacts are read from the workspace files written by the
+# build-image and push-disk-artifact tasks, so skipped pushes are skipped here.
+
+WORKSPACE_PATH="$(workspaces.shared-workspace.path)"
+CHAINS_DIR="$WORKSPACE_PATH/.chains"
+SIGNING_KEY="/workspace/signing-key/cosign.key"
+SIGNING_PASSWORD_FILE="/workspace/signing-key/cosign.password"
+
+if [ ! -f "$SIGNING_KEY" ]; then
+  echo "ERROR: signing key secret does not contain cosign.key" >&2
+  exit 1
+fi
+
+install_custom_ca_certs
+
+COSIGN_VERSION="2.4.1"
+case "$(uname -m)" in
+  x86_64) COSIGN_ARCH="amd64" ;;
+  aarch64|arm64) COSIGN_ARCH="arm64" ;;
+  *)
+    echo "ERROR: Unsupported architecture: $(uname -m)" >&2
+    exit 1
+    ;;
+esac
+COSIGN_BINARY="cosign-linux-${COSIGN_ARCH}"
+COSIGN_BASE_URL="https://github.com/sigstore/cosign/releases/download/v${COSIGN_VERSION}"
+COSIGN_CHECKSUMS="cosign_checksums.txt"
+
+echo "Downloading cosign ${COSIGN_VERSION} with integrity verification..."
+cd "$(mktemp -d)"
+curl -sLO "${COSIGN_BASE_URL}/${COSIGN_BINARY}" || {
+  echo "ERROR: Failed to download cosign" >&2
+  exit 1
+}
+curl -sLO "${COSIGN_BASE_URL}/${COSIGN_CHECKSUMS}" || {
+  echo "ERROR: Failed to download cosign checksums" >&2
+  exit 1
+}
+
+expected_checksum=$(grep " ${COSIGN_BINARY}\$" "${COSIGN_CHECKSUMS}" | cut -d' ' -f1)
+if [ -z "$expected_checksum" ]; then
+  echo "ERROR: Could not find checksum for ${COSIGN_BINARY} in checksums file" >&2
+  exit 1
+fi
+if command -v sha256sum >/dev/null; then
+  actual_checksum=$(sha256sum "${COSIGN_BINARY}" | cut -d' ' -f1)
+else
+  actual_checksum=$(shasum -a 256 "${COSIGN_BINARY}" | cut -d' ' -f1)
+fi
+if [ "$expected_checksum" != "$actual_checksum" ]; then
+  echo "ERROR: Checksum verification failed for ${COSIGN_BINARY}" >&2
+  echo "  Expected: $expected_checksum" >&2
+  echo "  Actual:   $actual_checksum" >&2
+  exit 1
+fi
+echo "Checksum verification passed: $expected_checksum"
+
+mkdir -p "$HOME/bin"
+mv "$COSIGN_BINARY" "$HOME/bin/cosign"
+chmod +x "$HOME/bin/cosign"
+rm -f "$COSIGN_CHECKSUMS"
+export PATH="$HOME/bin:$PATH"
+
+# cosign reads registry credentials from the docker config
+setup_cluster_auth
+read_registry_creds "/workspace/registry-auth"
+setup_registry_auth || echo "No custom registry auth found, using cluster auth only"
+mkdir -p "$HOME/.docker"
+(umask 0177; cp "$REGISTRY_AUTH_FILE" "$HOME/.docker/config.json")
+export DOCKER_CONFIG="$HOME/.docker"
+
+COSIGN_PASSWORD=""
+if [ -f "$SIGNING_PASSWORD_FILE" ]; then
+  COSIGN_PASSWORD=$(cat "$SIGNING_PASSWORD_FILE")
+fi
+export COSIGN_PASSWORD
+
+# Build the SLSA v1 provenance predicate shared by both artifacts
+PREDICATE_FILE="$(mktemp)"
+PIPELINE_RUN_NAME="$(params.pipeline-run-name)" \
+PIPELINE_RUN_UID="$(params.pipeline-run-uid)" \
+PIPELINE_RUN_NAMESPACE="$(params.pipeline-run-namespace)" \
+BUILD_DISTRO="$(params.distro)" \
+BUILD_TARGET="$(params.target)" \
+BUILD_ARCH="$(params.arch)" \
+BUILD_MODE="$(params.mode)" \
+BUILD_EXPORT_FORMAT="$(params.export-format)" \
+BUILD_CUSTOM_DEFINES="$(params.custom-defines)" \
+BUILD_AIB_EXTRA_ARGS="$(params.aib-extra-args)" \
+BUILDER_IMAGE="$(params.builder-image)" \
+AIB_IMAGE="$(params.automotive-image-builder)" \
+TASK_BUNDLE_REF="$(params.task-bundle-ref)" \
+python3 - "$PREDICATE_FILE" <<'PYEOF'
+import datetime
+import json
+import os
+import sys
+
+def lines(value):
+    return [line for line in value.splitlines() if line.strip()]
+
+env = os.environ
+dependencies = []
+for image in (env.get("BUILDER_IMAGE", ""), env.get("AIB_IMAGE", ""), env.get("TASK_BUNDLE_REF", "")):
+    if image:
+        dependencies.append({"uri": "oci://" + image})
+
+metadata = {
+    "invocationId": env.get("PIPELINE_RUN_UID", ""),
+    "finishedOn": datetime.datetime.now(datetime.timezone.utc).strftime("%Y-%m-%dT%H:%M:%SZ"),
+}
+
+predicate = {
+    "buildDefinition": {
+        "buildType": "https://github.com/centos-automotive-suite/automotive-dev-operator/ImageBuild@v1",
+        "externalParameters": {
+            "distro": env.get("BUILD_DISTRO", ""),
+            "target": env.get("BUILD_TARGET", ""),
+            "architecture": env.get("BUILD_ARCH", ""),
+            "mode": env.get("BUILD_MODE", ""),
+            "exportFormat": env.get("BUILD_EXPORT_FORMAT", ""),
+            "customDefines": lines(env.get("BUILD_CUSTOM_DEFINES", "")),
+            "aibExtraArgs": lines(env.get("BUILD_AIB_EXTRA_ARGS", "")),
+        },
+        "internalParameters": {
+            "pipelineRun": env.get("PIPELINE_RUN_NAMESPACE", "") + "/" + env.get("PIPELINE_RUN_NAME", ""),
+        },
+        "resolvedDependencies": dependencies,
+    },
+    "runDetails": {
+        "builder": {"id": "https://github.com/centos-automotive-suite/automotive-dev-operator"},
+        "metadata": metadata,
+    },
+}
+with open(sys.argv[1], "w") as f:
+    json.dump(predicate, f, indent=2)
+PYEOF
+
+# sign_artifact signs a pushed artifact and attests its provenance.
+# Args: $1 - artifact kind, the .chains subdirectory (container or disk)
+sign_artifact() {
+  local kind="$1" url digest repo
+  if [ ! -f "$CHAINS_DIR/$kind/url" ] || [ ! -f "$CHAINS_DIR/$kind/digest" ]; then
+    echo "No pushed $kind artifact, skipping"
+    return 0
+  fi
+  url=$(cat "$CHAINS_DIR/$kind/url")
+  digest=$(cat "$CHAINS_DIR/$kind/digest")
+  if [ -z "$url" ] || [ -z "$digest" ]; then
+    echo "No $kind digest recorded, skipping"
+    return 0
+  fi
+
+  # Sign the digest, never the tag
+  repo="${url%%@*}"
+  if [[ "${repo##*/}" == *:* ]]; then
+    repo="${repo%:*}"
+  fi
+  local ref="${repo}@${digest}"
+
+  local cosign_flags=(--key "$SIGNING_KEY" --tlog-upload=false --yes)
+  if [ "$(params.insecure-registry)" = "true" ]; then
+    cosign_flags+=(--allow-insecure-registry)
+    if [[ "$(detect_registry_protocol "${repo%%/*}")" == *--plain-http* ]]; then
+      cosign_flags+=(--allow-http-registry)
+    fi
+  fi
+
+  echo "Signing $kind artifact $ref"
+  cosign sign "${cosign_flags[@]}" "$ref"
+  echo "Attaching SLSA provenance to $ref"
+  cosign attest "${cosign_flags[@]}" --type slsaprovenance1 --predicate "$PREDICATE_FILE" "$ref"
+}
+
+sign_artifact container
+sign_artifact disk
+rm -f "$PREDICATE_FILE"
+echo "Signing completed"
@@ -0,0 +1,94 @@
+# NOTE: common.sh is prepended to this script at embed time.
+# Exports a workspace volume to the cluster registry as an OCI artifact, or
+# restores a previously exported archive into a fresh workspace volume.
+#
+# Environment:
+#   SNAPSHOT_MODE           export | restore
+#   SNAPSHOT_REF            OCI reference of the snapshot archive
+#   SNAPSHOT_ARTIFACT_TYPE  OCI artifact type of the archive
+#   WORKSPACE_DIR           mounted workspace volume (default /workspace)
+set -euo pipefail
+
+WORKSPACE_DIR="${WORKSPACE_DIR:-/workspace}"
+SNAPSHOT_ARCHIVE="workspace.tar.gz"
+RESTORED_MARKER="${WORKSPACE_DIR}/.snapshot-restored"
+
+if [[ -z "${SNAPSHOT_REF:-}" ]]; then
+    echo "ERROR: SNAPSHOT_REF is not set"
+    exit 1
+fi
+
+ORAS_VERSION="1.2.0"
+case "$(uname -m)" in
+    x86_64) ORAS_ARCH="amd64" ;;
+    aarch64|arm64) ORAS_ARCH="arm64" ;;
+    *) echo "ERROR: Unsupported architecture $(uname -m)"; exit 1 ;;
+esac
+ORAS_TARBALL="oras_${ORAS_VERSION}_linux_${ORAS_ARCH}.tar.gz"
+ORAS_BASE_URL="https://github.com/oras-project/oras/releases/download/v${ORAS_VERSION}"
+ORAS_CHECKSUMS="oras_${ORAS_VERSION}_checksums.txt"
+
+WORK_DIR=$(mktemp -d)
+trap 'rm -rf "${WORK_DIR}"' EXIT
+cd "${WORK_DIR}"
+
+curl -sSLf -o "${ORAS_TARBALL}" "${ORAS_BASE_URL}/${ORAS_TARBALL}" || {
+    echo "ERROR: Failed to download oras"; exit 1
+}
+curl -sSLf -o "${ORAS_CHECKSUMS}" "${ORAS_BASE_URL}/${ORAS_CHECKSUMS}" || {
+    echo "ERROR: Failed to download oras checksums"; exit 1
+}
+EXPECTED_CHECKSUM=$(grep " ${ORAS_TARBALL}\$" "${ORAS_CHECKSUMS}" | cut -d' ' -f1)
+ACTUAL_CHECKSUM=$(sha256sum "${ORAS_TARBALL}" | cut -d' ' -f1)
+if [[ -z "${EXPECTED_CHECKSUM}" ]] || [[ "${EXPECTED_CHECKSUM}" != "${ACTUAL_CHECKSUM}" ]]; then
+    echo "ERROR: Checksum verification failed for ${ORAS_TARBALL}"
+    exit 1
+fi
+tar -xzf "${ORAS_TARBALL}" oras
+rm -f "${ORAS_TARBALL}" "${ORAS_CHECKSUMS}"
+ORAS="${WORK_DIR}/oras"
+
+# The cluster registry accepts the pod service account token
+REGISTRY_HOST="${SNAPSHOT_REF%%/*}"
+read -ra ORAS_FLAGS <<< "$(detect_registry_protocol "${REGISTRY_HOST}")"
+SA_TOKEN=$(cat /var/run/secrets/kubernetes.io/serviceaccount/token)
+echo "${SA_TOKEN}" | "${ORAS}" login "${ORAS_FLAGS[@]}" --username serviceaccount --password-stdin "${REGISTRY_HOST}"
+
+case "${SNAPSHOT_MODE:-}" in
+    export)
+        emit_progress "Exporting workspace" 0 2
+        echo "Archiving ${WORKSPACE_DIR}..."
+        # The restore marker belongs to the source workspace, not to its clones
+        tar --numeric-owner --xattrs -czf "${SNAPSHOT_ARCHIVE}" \
+            --exclude="./.snapshot-restored" -C "${WORKSPACE_DIR}" .
+        echo "Archive size: $(du -h "${SNAPSHOT_ARCHIVE}" | cut -f1)"
+
+        emit_progress "Exporting workspace" 1 2
+        echo "Pushing snapshot to ${SNAPSHOT_REF}..."
+        "${ORAS}" push "${ORAS_FLAGS[@]}" \
+            --artifact-type "${SNAPSHOT_ARTIFACT_TYPE}" \
+            "${SNAPSHOT_REF}" "${SNAPSHOT_ARCHIVE}:application/gzip"
+        emit_progress "Exporting workspace" 2 2
+        ;;
+    restore)
+        if [[ -f "${RESTORED_MARKER}" ]]; then
+            echo "Workspace already restored from $(cat "${RESTORED_MARKER}")"
+            exit 0
+        fi
+        emit_progress "Restoring workspace" 0 2
+        echo "Pulling snapshot ${SNAPSHOT_REF}..."
+        "${ORAS}" pull "${ORAS_FLAGS[@]}" -o "${WORK_DIR}" "${SNAPSHOT_REF}"
+
+        emit_progress "Restoring workspace" 1 2
+        echo "Extracting into ${WORKSPACE_DIR}..."
+        tar --numeric-owner --xattrs -xzf "${WORK_DIR}/${SNAPSHOT_ARCHIVE}" -C "${WORKSPACE_DIR}"
+        echo -n "${SNAPSHOT_REF}" > "${RESTORED_MARKER}"
+        emit_progress "Restoring workspace" 2 2
+        ;;
+    *)
+        echo "ERROR: SNAPSHOT_MODE must be export or restore, got '${SNAPSHOT_MODE:-}'"
+        exit 1
+        ;;
+esac
+
+echo "Workspace snapshot ${SNAPSHOT_MODE} complete"
@@ -0,0 +1,77 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateSignArtifactsTask(t *testing.T) {
+	task := GenerateSignArtifactsTask("test-ns", &BuildConfig{})
+
+	if task.Name != SignArtifactsTaskName || task.Namespace != "test-ns" {
+		t.Fatalf("unexpected task identity %s/%s", task.Namespace, task.Name)
+	}
+
+	workspaces := map[string]bool{}
+	for _, ws := range task.Spec.Workspaces {
+		workspaces[ws.Name] = ws.Optional
+	}
+	if optional, ok := workspaces["signing-key"]; !ok || optional {
+		t.Error("expected a required signing-key workspace")
+	}
+	if optional, ok := workspaces["registry-auth"]; !ok || !optional {
+		t.Error("expected an optional registry-auth workspace")
+	}
+
+	script := task.Spec.Steps[0].Script
+	for _, want := range []string{
+		"cosign sign", "cosign attest", "--type slsaprovenance1", "--tlog-upload=false",
+		".chains", "cosign.password", "setup_registry_auth()",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("step script does not contain %q", want)
+		}
+	}
+}
+
+func TestGenerateTektonPipeline_SignArtifactsTask(t *testing.T) {
+	pipeline := GenerateTektonPipeline("test-pipeline", "test-ns", &BuildConfig{})
+
+	declared := map[string]bool{}
+	for _, p := range pipeline.Spec.Params {
+		declared[p.Name] = true
+	}
+	if !declared["sign-artifacts"] {
+		t.Error("pipeline is missing param \"sign-artifacts\"")
+	}
+	workspaces := map[string]bool{}
+	for _, ws := range pipeline.Spec.Workspaces {
+		workspaces[ws.Name] = ws.Optional
+	}
+	if optional, ok := workspaces["signing-key"]; !ok || !optional {
+		t.Error("expected an optional signing-key pipeline workspace")
+	}
+
+	for _, task := range pipeline.Spec.Tasks {
+		if task.Name != "sign-artifacts" {
+			continue
+		}
+		if len(task.RunAfter) != 1 || task.RunAfter[0] != "push-disk-artifact" {
+			t.Errorf("sign-artifacts should run after push-disk-artifact, got %v", task.RunAfter)
+		}
+		if len(task.When) != 1 || task.When[0].Input != "$(params.sign-artifacts)" {
+			t.Errorf("sign-artifacts should only run when sign-artifacts is set, got %+v", task.When)
+		}
+		for _, p := range task.Params {
+			ref := p.Value.StringVal
+			if strings.HasPrefix(ref, "$(params.") {
+				name := strings.TrimSuffix(strings.TrimPrefix(ref, "$(params."), ")")
+				if !declared[name] {
+					t.Errorf("param %q references undeclared pipeline param %q", p.Name, name)
+				}
+			}
+		}
+		return
+	}
+	t.Fatal("pipeline should have sign-artifacts task")
+}
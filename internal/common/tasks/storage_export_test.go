@@ -0,0 +1,85 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratePushArtifactStorageTask(t *testing.T) {
+	task := GeneratePushArtifactStorageTask("test-ns", &BuildConfig{})
+
+	if task.Name != PushArtifactStorageTaskName || task.Namespace != "test-ns" {
+		t.Fatalf("unexpected task identity %s/%s", task.Namespace, task.Name)
+	}
+
+	params := map[string]bool{}
+	for _, p := range task.Spec.Params {
+		params[p.Name] = true
+	}
+	for _, name := range []string{"export-type", "export-url", "artifact-filename", "s3-endpoint", "s3-region", traceIDParamName} {
+		if !params[name] {
+			t.Errorf("missing param %q", name)
+		}
+	}
+
+	workspaces := map[string]bool{}
+	for _, ws := range task.Spec.Workspaces {
+		workspaces[ws.Name] = ws.Optional
+	}
+	if optional, ok := workspaces["export-auth"]; !ok || !optional {
+		t.Error("expected an optional export-auth workspace")
+	}
+	if optional, ok := workspaces["export-pvc"]; !ok || !optional {
+		t.Error("expected an optional export-pvc workspace")
+	}
+
+	script := task.Spec.Steps[0].Script
+	for _, want := range []string{"--aws-sigv4", "x-amz-meta-sha256", "X-Checksum-Sha256", "compute_artifact_digest()"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("step script does not contain %q", want)
+		}
+	}
+}
+
+func TestGenerateTektonPipeline_StorageExportTask(t *testing.T) {
+	pipeline := GenerateTektonPipeline("test-pipeline", "test-ns", &BuildConfig{})
+
+	declared := map[string]bool{}
+	for _, p := range pipeline.Spec.Params {
+		declared[p.Name] = true
+	}
+	for _, name := range []string{"disk-export-type", "disk-export-url", "disk-export-endpoint", "disk-export-region"} {
+		if !declared[name] {
+			t.Errorf("pipeline is missing param %q", name)
+		}
+	}
+
+	for _, task := range pipeline.Spec.Tasks {
+		if task.Name != "export-disk-artifact" {
+			continue
+		}
+		var refName string
+		for _, p := range task.TaskRef.Params {
+			if p.Name == "name" {
+				refName = p.Value.StringVal
+			}
+		}
+		if refName != PushArtifactStorageTaskName {
+			t.Errorf("export-disk-artifact should reference %s, got %q", PushArtifactStorageTaskName, refName)
+		}
+		if len(task.When) != 1 || task.When[0].Input != "$(params.disk-export-type)" {
+			t.Errorf("export-disk-artifact should only run when disk-export-type is set, got %+v", task.When)
+		}
+		for _, p := range task.Params {
+			ref := p.Value.StringVal
+			if strings.HasPrefix(ref, "$(params.") {
+				name := strings.TrimSuffix(strings.TrimPrefix(ref, "$(params."), ")")
+				if !declared[name] {
+					t.Errorf("param %q references undeclared pipeline param %q", p.Name, name)
+				}
+			}
+		}
+		return
+	}
+	t.Fatal("pipeline should have export-disk-artifact task")
+}
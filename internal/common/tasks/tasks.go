@@ -2,7 +2,9 @@ package tasks
 
 import (
 	_ "embed" // Required for go:embed directives
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -903,7 +905,7 @@ func GenerateTektonPipeline(name, namespace string, buildConfig *BuildConfig) *t
 			},
 		},
 		Spec: tektonv1.PipelineSpec{
-			Params: []tektonv1.ParamSpec{
+			Params: append([]tektonv1.ParamSpec{
 				{
 					Name: "distro",
 					Type: tektonv1.ParamTypeString,
@@ -1193,14 +1195,62 @@ func GenerateTektonPipeline(name, namespace string, buildConfig *BuildConfig) *t
 						StringVal: "",
 					},
 				},
+				{
+					Name:        "sign-artifacts",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Sign the pushed container and disk artifact with cosign (true/false)",
+					Default: &tektonv1.ParamValue{
+						Type:      tektonv1.ParamTypeString,
+						StringVal: "false",
+					},
+				},
+				{
+					Name:        "disk-export-type",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Non-registry disk export type (s3, http or pvc); empty to skip",
+					Default: &tektonv1.ParamValue{
+						Type:      tektonv1.ParamTypeString,
+						StringVal: "",
+					},
+				},
+				{
+					Name:        "disk-export-url",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Disk export destination: bucket/key for s3, the full URL for http, the path within the volume for pvc",
+					Default: &tektonv1.ParamValue{
+						Type:      tektonv1.ParamTypeString,
+						StringVal: "",
+					},
+				},
+				{
+					Name:        "disk-export-endpoint",
+					Type:        tektonv1.ParamTypeString,
+					Description: "S3 endpoint for s3 disk exports",
+					Default: &tektonv1.ParamValue{
+						Type:      tektonv1.ParamTypeString,
+						StringVal: "",
+					},
+				},
+				{
+					Name:        "disk-export-region",
+					Type:        tektonv1.ParamTypeString,
+					Description: "S3 signing region for s3 disk exports",
+					Default: &tektonv1.ParamValue{
+						Type:      tektonv1.ParamTypeString,
+						StringVal: "us-east-1",
+					},
+				},
 				traceIDParamSpec(),
-			},
+			}, deviceTestParamSpecs()...),
 			Workspaces: []tektonv1.PipelineWorkspaceDeclaration{
 				{Name: workspaceNameShared},
 				{Name: "manifest-config-workspace"},
 				{Name: "registry-auth", Optional: true},
 				{Name: "flash-oci-auth", Optional: true},
 				{Name: "jumpstarter-client", Optional: true},
+				{Name: "disk-export-auth", Optional: true},
+				{Name: "disk-export-pvc", Optional: true},
+				{Name: "signing-key", Optional: true},
 			},
 			Results: []tektonv1.PipelineResult{
 				{
@@ -1218,6 +1268,14 @@ func GenerateTektonPipeline(name, namespace string, buildConfig *BuildConfig) *t
 					Description: "The Jumpstarter lease ID acquired during flash (empty if flash not enabled)",
 					Value:       tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(tasks.flash-image.results.lease-id)"},
 				},
+				{
+					Name:        FlashResultTestSummary,
+					Description: "JSON summary of the on-device test results (empty if tests are not enabled)",
+					Value: tektonv1.ParamValue{
+						Type:      tektonv1.ParamTypeString,
+						StringVal: "$(tasks.flash-image.results." + FlashResultTestSummary + ")",
+					},
+				},
 				{
 					Name:        "build-timing",
 					Description: "JSON timing breakdown of build phases in seconds",
@@ -1243,6 +1301,16 @@ func GenerateTektonPipeline(name, namespace string, buildConfig *BuildConfig) *t
 					Description: "Pushed disk artifact OCI digest",
 					Value:       tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(tasks.push-disk-artifact.results.IMAGE_DIGEST)"},
 				},
+				{
+					Name:        "disk-export-url",
+					Description: "Location of the disk image exported to s3, http or pvc storage",
+					Value:       tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(tasks.export-disk-artifact.results.ARTIFACT_URL)"},
+				},
+				{
+					Name:        "disk-export-digest",
+					Description: "SHA-256 digest of the disk image exported to s3, http or pvc storage",
+					Value:       tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(tasks.export-disk-artifact.results.ARTIFACT_DIGEST)"},
+				},
 				{
 					Name:        "IMAGES",
 					Description: "Newline-separated image@digest list for Tekton Chains attestation",
@@ -1434,10 +1502,116 @@ func GenerateTektonPipeline(name, namespace string, buildConfig *BuildConfig) *t
 						},
 					},
 				},
+				{
+					Name:    "export-disk-artifact",
+					TaskRef: buildTaskRef(PushArtifactStorageTaskName, namespace, buildConfig),
+					Params: []tektonv1.Param{
+						{
+							Name:  "export-type",
+							Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(params.disk-export-type)"},
+						},
+						{
+							Name:  "export-url",
+							Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(params.disk-export-url)"},
+						},
+						{
+							Name:  "s3-endpoint",
+							Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(params.disk-export-endpoint)"},
+						},
+						{
+							Name:  "s3-region",
+							Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(params.disk-export-region)"},
+						},
+						{
+							Name: "artifact-filename",
+							Value: tektonv1.ParamValue{
+								Type:      tektonv1.ParamTypeString,
+								StringVal: "$(tasks.build-image.results.artifact-filename)",
+							},
+						},
+						{
+							Name: "expected-artifact-digest",
+							Value: tektonv1.ParamValue{
+								Type:      tektonv1.ParamTypeString,
+								StringVal: "$(tasks.build-image.results.ARTIFACT_INTEGRITY_DIGEST)",
+							},
+						},
+						{
+							Name:  "yq-helper-image",
+							Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(params.yq-helper-image)"},
+						},
+						traceIDPipelineParam(),
+					},
+					Workspaces: []tektonv1.WorkspacePipelineTaskBinding{
+						{Name: workspaceNameShared, Workspace: workspaceNameShared},
+						{Name: "export-auth", Workspace: "disk-export-auth"},
+						{Name: "export-pvc", Workspace: "disk-export-pvc"},
+					},
+					RunAfter: []string{"build-image"},
+					When: []tektonv1.WhenExpression{
+						{
+							Input:    "$(params.disk-export-type)",
+							Operator: "notin",
+							Values:   []string{""},
+						},
+					},
+				},
+				{
+					Name:    "sign-artifacts",
+					TaskRef: buildTaskRef(SignArtifactsTaskName, namespace, buildConfig),
+					Params: append(
+						[]tektonv1.Param{
+							{
+								Name:  "builder-image",
+								Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(tasks.build-image.results.builder-image)"},
+							},
+							{
+								Name: "automotive-image-builder",
+								Value: tektonv1.ParamValue{
+									Type:      tektonv1.ParamTypeString,
+									StringVal: "$(tasks.build-image.results.automotive-image-builder)",
+								},
+							},
+							{
+								Name:  "pipeline-run-name",
+								Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(context.pipelineRun.name)"},
+							},
+							{
+								Name:  "pipeline-run-uid",
+								Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(context.pipelineRun.uid)"},
+							},
+							{
+								Name:  "pipeline-run-namespace",
+								Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(context.pipelineRun.namespace)"},
+							},
+						},
+						append(
+							pipelinePassthroughParams(
+								"distro", "target", "arch", "mode", "export-format", "custom-defines",
+								"aib-extra-args", "task-bundle-ref", "insecure-registry", "yq-helper-image",
+							),
+							traceIDPipelineParam(),
+						)...,
+					),
+					Workspaces: []tektonv1.WorkspacePipelineTaskBinding{
+						{Name: workspaceNameShared, Workspace: workspaceNameShared},
+						{Name: "signing-key", Workspace: "signing-key"},
+						{Name: "registry-auth", Workspace: "registry-auth"},
+					},
+					// Signs after push-disk-artifact (if it ran) so both digests are recorded
+					RunAfter: []string{"push-disk-artifact"},
+					When: []tektonv1.WhenExpression{
+						{
+							Input:    "$(params.sign-artifacts)",
+							Operator: "in",
+							Values:   []string{"true"},
+						},
+					},
+				},
 				{
 					Name:    "flash-image",
 					TaskRef: buildTaskRef("flash-image", namespace, buildConfig),
-					Params: []tektonv1.Param{
+					Params: append([]tektonv1.Param{
 						{
 							Name: "image-ref",
 							Value: tektonv1.ParamValue{
@@ -1481,7 +1655,10 @@ func GenerateTektonPipeline(name, namespace string, buildConfig *BuildConfig) *t
 							},
 						},
 						traceIDPipelineParam(),
-					},
+					}, pipelinePassthroughParams(
+						"test-enabled", "test-script", "test-image", "test-boot-check",
+						"test-boot-marker", "test-boot-timeout", "test-power-cycle",
+					)...),
 					Workspaces: []tektonv1.WorkspacePipelineTaskBinding{
 						{Name: "jumpstarter-client", Workspace: "jumpstarter-client"},
 						{Name: "flash-oci-auth", Workspace: "flash-oci-auth"},
@@ -1786,6 +1963,145 @@ func GeneratePrepareBuilderTask(namespace string, buildConfig *BuildConfig) *tek
 	return task
 }
 
+// Results written by the flash task.
+const (
+	FlashResultLeaseID       = "lease-id"
+	FlashResultExporter      = "exporter"
+	FlashResultLeaseReleased = "lease-released"
+	FlashResultTestSummary   = "test-summary"
+	FlashResultConsoleRef    = "console-ref"
+)
+
+// deviceTestResultsDir is where the flash task's test steps exchange JUnit results.
+const deviceTestResultsDir = "/workspace/test-results"
+
+// consoleDir is where the console sidecar hands the serial console transcript
+// to the flash task's steps.
+const consoleDir = "/workspace/console"
+
+// FlashConsoleContainer is the pod container of the flash TaskRun that streams
+// the device serial console.
+const FlashConsoleContainer = "sidecar-console"
+
+// DeviceTestParams returns the flash task params that enable on-device tests.
+// Tests are disabled when test is nil. The pipeline passes params of the same
+// names through to the flash-image task.
+func DeviceTestParams(test *automotivev1alpha1.DeviceTestSpec, jumpstarterImage string) ([]tektonv1.Param, error) {
+	if test == nil {
+		return nil, nil
+	}
+	bootTimeout, err := time.ParseDuration(test.GetBootTimeout())
+	if err != nil || bootTimeout <= 0 {
+		return nil, fmt.Errorf("invalid device test boot timeout %q", test.BootTimeout)
+	}
+	image := test.Image
+	if image == "" {
+		image = jumpstarterImage
+	}
+	powerCycle := "true"
+	if test.SkipPowerCycle {
+		powerCycle = "false"
+	}
+	str := func(name, value string) tektonv1.Param {
+		return tektonv1.Param{Name: name, Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: value}}
+	}
+	return []tektonv1.Param{
+		str("test-enabled", "true"),
+		str("test-script", test.Script),
+		str("test-image", image),
+		str("test-boot-check", test.GetBootCheck()),
+		str("test-boot-marker", test.GetBootMarker()),
+		str("test-boot-timeout", strconv.Itoa(int(bootTimeout.Seconds()))),
+		str("test-power-cycle", powerCycle),
+	}, nil
+}
+
+// ParseDeviceTestSummary decodes the test-summary result of the flash task.
+// It returns nil for an empty summary.
+func ParseDeviceTestSummary(summary string) (*automotivev1alpha1.DeviceTestResults, error) {
+	if strings.TrimSpace(summary) == "" {
+		return nil, nil
+	}
+	results := &automotivev1alpha1.DeviceTestResults{}
+	if err := json.Unmarshal([]byte(summary), results); err != nil {
+		return nil, fmt.Errorf("invalid device test summary: %w", err)
+	}
+	return results, nil
+}
+
+// deviceTestParamSpecs declares the on-device test params of the flash task.
+func deviceTestParamSpecs() []tektonv1.ParamSpec {
+	str := func(name, description, def string) tektonv1.ParamSpec {
+		return tektonv1.ParamSpec{
+			Name:        name,
+			Type:        tektonv1.ParamTypeString,
+			Description: description,
+			Default:     &tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: def},
+		}
+	}
+	return []tektonv1.ParamSpec{
+		str("test-enabled", "Run on-device tests after flashing (true/false)", "false"),
+		str("test-script", "Test script run against the flashed device", ""),
+		str("test-image", "Container image the test script runs in", automotivev1alpha1.DefaultJumpstarterImage),
+		str("test-boot-check", "How a successful boot is detected (console/ssh)",
+			automotivev1alpha1.DeviceTestBootCheckConsole),
+		str("test-boot-marker", "Regular expression awaited on the serial console",
+			automotivev1alpha1.DefaultDeviceTestBootMarker),
+		str("test-boot-timeout", "Seconds to wait for the device to boot", "600"),
+		str("test-power-cycle", "Power-cycle the device before the boot check (true/false)", "true"),
+	}
+}
+
+// ConsoleParams returns the flash task params that record the device serial
+// console after flashing. Capture is disabled when console is nil.
+func ConsoleParams(console *automotivev1alpha1.ConsoleCaptureSpec) ([]tektonv1.Param, error) {
+	if console == nil {
+		return nil, nil
+	}
+	duration, err := time.ParseDuration(console.GetDuration())
+	if err != nil || duration < time.Second {
+		return nil, fmt.Errorf("invalid console capture duration %q", console.Duration)
+	}
+	str := func(name, value string) tektonv1.Param {
+		return tektonv1.Param{Name: name, Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: value}}
+	}
+	return []tektonv1.Param{
+		str("console-enabled", "true"),
+		str("console-seconds", strconv.Itoa(int(duration.Seconds()))),
+		str("console-power-cycle", strconv.FormatBool(!console.SkipPowerCycle)),
+		str("console-upload", strconv.FormatBool(!console.SkipUpload)),
+	}, nil
+}
+
+// consoleParamSpecs declares the serial console capture params of the flash task.
+func consoleParamSpecs(buildConfig *BuildConfig) []tektonv1.ParamSpec {
+	str := func(name, description, def string) tektonv1.ParamSpec {
+		return tektonv1.ParamSpec{
+			Name:        name,
+			Type:        tektonv1.ParamTypeString,
+			Description: description,
+			Default:     &tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: def},
+		}
+	}
+	return []tektonv1.ParamSpec{
+		str("console-enabled", "Record the device serial console after flashing (true/false)", "false"),
+		str("console-seconds", "Seconds of serial console to record", "300"),
+		str("console-power-cycle", "Power-cycle the device so the recording covers the boot (true/false)", "true"),
+		str("console-upload", "Attach the transcript to the flashed image as an OCI referrer (true/false)", "true"),
+		str("yq-helper-image", "Container image providing curl and python3 for the transcript upload",
+			buildConfig.getYQHelperImage()),
+	}
+}
+
+// consoleEnv returns the environment shared by the console capture containers of the flash task.
+func consoleEnv() []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: "CONSOLE_ENABLED", Value: "$(params.console-enabled)"},
+		{Name: "CONSOLE_SECONDS", Value: "$(params.console-seconds)"},
+		{Name: "CONSOLE_DIR", Value: consoleDir},
+	}
+}
+
 // GenerateFlashTask creates a Tekton Task for flashing images to hardware via Jumpstarter
 func GenerateFlashTask(namespace string, buildConfig *BuildConfig) *tektonv1.Task {
 	return &tektonv1.Task{
@@ -1802,7 +2118,7 @@ func GenerateFlashTask(namespace string, buildConfig *BuildConfig) *tektonv1.Tas
 			},
 		},
 		Spec: tektonv1.TaskSpec{
-			Params: []tektonv1.ParamSpec{
+			Params: append([]tektonv1.ParamSpec{
 				{
 					Name:        "image-ref",
 					Type:        tektonv1.ParamTypeString,
@@ -1850,13 +2166,33 @@ func GenerateFlashTask(namespace string, buildConfig *BuildConfig) *tektonv1.Tas
 					},
 				},
 				traceIDParamSpec(),
-			},
+			}, append(deviceTestParamSpecs(), consoleParamSpecs(buildConfig)...)...),
 			Results: []tektonv1.TaskResult{
 				{
-					Name:        "lease-id",
+					Name:        FlashResultLeaseID,
 					Type:        tektonv1.ResultsTypeString,
 					Description: "The Jumpstarter lease ID acquired for the device",
 				},
+				{
+					Name:        FlashResultExporter,
+					Type:        tektonv1.ResultsTypeString,
+					Description: "The Jumpstarter exporter the lease was granted on",
+				},
+				{
+					Name:        FlashResultLeaseReleased,
+					Type:        tektonv1.ResultsTypeString,
+					Description: "Whether the lease was released when the task finished (true/false)",
+				},
+				{
+					Name:        FlashResultTestSummary,
+					Type:        tektonv1.ResultsTypeString,
+					Description: "JSON summary of the on-device test results (empty if tests are not enabled)",
+				},
+				{
+					Name:        FlashResultConsoleRef,
+					Type:        tektonv1.ResultsTypeString,
+					Description: "OCI reference of the serial console transcript (empty if it was not uploaded)",
+				},
 			},
 			Workspaces: []tektonv1.WorkspaceDeclaration{
 				{
@@ -1907,13 +2243,438 @@ func GenerateFlashTask(namespace string, buildConfig *BuildConfig) *tektonv1.Tas
 						},
 						{
 							Name:  "RESULTS_LEASE_ID_PATH",
-							Value: "$(results.lease-id.path)",
+							Value: "$(results." + FlashResultLeaseID + ".path)",
+						},
+						{
+							Name:  "RESULTS_EXPORTER_PATH",
+							Value: "$(results." + FlashResultExporter + ".path)",
+						},
+						{
+							Name:  "RESULTS_LEASE_RELEASED_PATH",
+							Value: "$(results." + FlashResultLeaseReleased + ".path)",
 						},
 						traceIDEnvVar(),
 					},
 					Script:  FlashImageScript,
 					Timeout: &metav1.Duration{Duration: time.Duration(buildConfig.getFlashTimeoutMinutes()) * time.Minute},
 				},
+				{
+					Name:  "boot-device",
+					Image: "$(params.jumpstarter-image)",
+					Env: append(append(deviceTestEnv(),
+						corev1.EnvVar{Name: "BOOT_CHECK", Value: "$(params.test-boot-check)"},
+						corev1.EnvVar{Name: "BOOT_MARKER", Value: "$(params.test-boot-marker)"},
+						corev1.EnvVar{Name: "BOOT_TIMEOUT", Value: "$(params.test-boot-timeout)"},
+						corev1.EnvVar{Name: "POWER_CYCLE", Value: "$(params.test-power-cycle)"},
+						corev1.EnvVar{Name: "CONSOLE_POWER_CYCLE", Value: "$(params.console-power-cycle)"},
+						traceIDEnvVar(),
+					), consoleEnv()...),
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "test-results", MountPath: deviceTestResultsDir},
+						{Name: "console", MountPath: consoleDir},
+					},
+					Script: BootDeviceScript,
+					// Boot and test failures are reported by collect-test-results
+					OnError: tektonv1.Continue,
+				},
+				{
+					Name:  "run-tests",
+					Image: "$(params.test-image)",
+					Env: append(deviceTestEnv(),
+						corev1.EnvVar{Name: "TEST_SCRIPT", Value: "$(params.test-script)"},
+					),
+					VolumeMounts: []corev1.VolumeMount{{Name: "test-results", MountPath: deviceTestResultsDir}},
+					Script:       RunDeviceTestsScript,
+					OnError:      tektonv1.Continue,
+				},
+				{
+					Name:  "save-console",
+					Image: "$(params.yq-helper-image)",
+					Env: append(consoleEnv(),
+						corev1.EnvVar{Name: "CONSOLE_UPLOAD", Value: "$(params.console-upload)"},
+						corev1.EnvVar{Name: "CONSOLE_ARTIFACT_TYPE", Value: automotivev1alpha1.ConsoleArtifactType},
+						corev1.EnvVar{Name: "IMAGE_REF", Value: "$(params.image-ref)"},
+						corev1.EnvVar{Name: "FLASH_OCI_AUTH_PATH", Value: "/workspace/flash-oci-auth"},
+						corev1.EnvVar{Name: "RESULTS_CONSOLE_REF_PATH", Value: "$(results." + FlashResultConsoleRef + ".path)"},
+					),
+					VolumeMounts: []corev1.VolumeMount{{Name: "console", MountPath: consoleDir}},
+					Script:       SaveConsoleScript,
+					// A missing transcript must not hide the flash and test outcome
+					OnError: tektonv1.Continue,
+				},
+				{
+					Name:  "collect-test-results",
+					Image: "$(params.jumpstarter-image)",
+					Env: append(deviceTestEnv(),
+						corev1.EnvVar{Name: "RESULTS_TEST_SUMMARY_PATH", Value: "$(results." + FlashResultTestSummary + ".path)"},
+					),
+					VolumeMounts: []corev1.VolumeMount{{Name: "test-results", MountPath: deviceTestResultsDir}},
+					Script:       CollectTestResultsScript,
+				},
+			},
+			Sidecars: []tektonv1.Sidecar{
+				{
+					// Holds the serial console for the whole capture window so the
+					// Build API can stream it while the steps boot and test the device
+					Name:  "console",
+					Image: "$(params.jumpstarter-image)",
+					Env: append(consoleEnv(),
+						corev1.EnvVar{Name: "JMP_CLIENT_CONFIG", Value: "/workspace/jumpstarter-client/client.yaml"},
+					),
+					VolumeMounts: []corev1.VolumeMount{{Name: "console", MountPath: consoleDir}},
+					Script:       ConsoleCaptureScript,
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name:         "test-results",
+					VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+				},
+				{
+					Name:         "console",
+					VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+				},
+			},
+		},
+	}
+}
+
+// deviceTestEnv returns the environment shared by the on-device test steps of the flash task.
+func deviceTestEnv() []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: "TEST_ENABLED", Value: "$(params.test-enabled)"},
+		{Name: "TEST_RESULTS_DIR", Value: deviceTestResultsDir},
+		{Name: "LEASE_ID_PATH", Value: "$(results." + FlashResultLeaseID + ".path)"},
+		{Name: "JMP_CLIENT_CONFIG", Value: "/workspace/jumpstarter-client/client.yaml"},
+	}
+}
+
+// PushImageIndexTaskName is the name of the Task that publishes multi-architecture image indexes.
+const PushImageIndexTaskName = "push-image-index"
+
+// GeneratePushImageIndexTask creates a Tekton Task that combines the per-architecture
+// bootc containers of a multi-architecture build into a single OCI image index.
+func GeneratePushImageIndexTask(namespace string, buildConfig *BuildConfig) *tektonv1.Task {
+	return &tektonv1.Task{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "tekton.dev/v1",
+			Kind:       "Task",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PushImageIndexTaskName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "automotive-dev-operator",
+				"app.kubernetes.io/part-of":    "automotive-dev",
+			},
+		},
+		Spec: tektonv1.TaskSpec{
+			Params: []tektonv1.ParamSpec{
+				{
+					Name:        "image-index",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Container reference to push the image index to",
+				},
+				{
+					Name:        "images",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Newline-separated per-architecture container references to include in the index",
+				},
+				{
+					Name:        "aib-image",
+					Type:        tektonv1.ParamTypeString,
+					Description: "AIB container image providing buildah",
+					Default: &tektonv1.ParamValue{
+						Type:      tektonv1.ParamTypeString,
+						StringVal: buildConfig.getAutomotiveImageBuilderImage(),
+					},
+				},
+				{
+					Name:        "insecure-registry",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Use insecure (skip TLS verify) for registry operations (true/false)",
+					Default: &tektonv1.ParamValue{
+						Type:      tektonv1.ParamTypeString,
+						StringVal: "false",
+					},
+				},
+				traceIDParamSpec(),
+			},
+			Results: []tektonv1.TaskResult{
+				{
+					Name:        "IMAGE_URL",
+					Description: "Reference of the pushed image index",
+				},
+				{
+					Name:        "IMAGE_DIGEST",
+					Description: "Digest of the pushed image index",
+				},
+			},
+			Workspaces: []tektonv1.WorkspaceDeclaration{
+				{Name: "registry-auth", Description: "Optional registry credentials", MountPath: "/workspace/registry-auth", Optional: true},
+			},
+			StepTemplate: &tektonv1.StepTemplate{
+				SecurityContext: &corev1.SecurityContext{
+					Privileged: ptr.To(true),
+					SELinuxOptions: &corev1.SELinuxOptions{
+						Type: "unconfined_t",
+					},
+				},
+			},
+			Steps: []tektonv1.Step{
+				{
+					Name:  "push-index",
+					Image: "$(params.aib-image)",
+					Env: []corev1.EnvVar{
+						{Name: "IMAGE_INDEX", Value: "$(params.image-index)"},
+						{Name: "IMAGES", Value: "$(params.images)"},
+						{Name: "INSECURE_REGISTRY", Value: "$(params.insecure-registry)"},
+						{Name: "RESULT_URL_PATH", Value: "$(results.IMAGE_URL.path)"},
+						{Name: "RESULT_DIGEST_PATH", Value: "$(results.IMAGE_DIGEST.path)"},
+						traceIDEnvVar(),
+					},
+					Script: PushImageIndexScript,
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      volumeNameContainerStorage,
+							MountPath: "/var/lib/containers/storage",
+						},
+						{
+							Name:      "custom-ca",
+							MountPath: "/etc/pki/ca-trust/custom",
+							ReadOnly:  true,
+						},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: volumeNameContainerStorage,
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
+				{
+					Name:         "custom-ca",
+					VolumeSource: trustedCABundleVolumeSource(buildConfig),
+				},
+			},
+		},
+	}
+}
+
+// PushArtifactStorageTaskName is the name of the Task that exports disk images
+// to S3-compatible storage, HTTP endpoints or a PVC.
+const PushArtifactStorageTaskName = "push-artifact-storage"
+
+// GeneratePushArtifactStorageTask creates a Tekton Task that uploads the built disk
+// image to a non-registry storage location and reports its URL, digest and size.
+func GeneratePushArtifactStorageTask(namespace string, buildConfig *BuildConfig) *tektonv1.Task {
+	stringParam := func(name, description, def string) tektonv1.ParamSpec {
+		return tektonv1.ParamSpec{
+			Name:        name,
+			Type:        tektonv1.ParamTypeString,
+			Description: description,
+			Default:     &tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: def},
+		}
+	}
+	return &tektonv1.Task{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "tekton.dev/v1",
+			Kind:       "Task",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PushArtifactStorageTaskName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "automotive-dev-operator",
+				"app.kubernetes.io/part-of":    "automotive-dev",
+			},
+		},
+		Spec: tektonv1.TaskSpec{
+			Params: []tektonv1.ParamSpec{
+				{
+					Name:        "export-type",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Storage type to export to (s3, http or pvc)",
+				},
+				{
+					Name:        "export-url",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Destination: bucket/key for s3, the full URL for http, the path within the volume for pvc",
+				},
+				{
+					Name:        "artifact-filename",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Filename of the artifact to export",
+				},
+				stringParam("s3-endpoint", "Base URL of the S3-compatible service", ""),
+				stringParam("s3-region", "Region used to sign S3 requests", "us-east-1"),
+				stringParam("expected-artifact-digest",
+					"Expected SHA-256 digest of the artifact(s) from the build task, for integrity verification", ""),
+				stringParam("yq-helper-image", "Container image providing curl for the upload", buildConfig.getYQHelperImage()),
+				traceIDParamSpec(),
+			},
+			Results: []tektonv1.TaskResult{
+				{
+					Name:        "ARTIFACT_URL",
+					Description: "Location of the exported disk image",
+				},
+				{
+					Name:        "ARTIFACT_DIGEST",
+					Description: "SHA-256 digest of the exported disk image",
+				},
+				{
+					Name:        "ARTIFACT_SIZE",
+					Description: "Size of the exported disk image in bytes",
+				},
+			},
+			Workspaces: []tektonv1.WorkspaceDeclaration{
+				{
+					Name:        workspaceNameShared,
+					Description: "Workspace containing the build artifacts",
+					MountPath:   "/workspace/shared",
+				},
+				{
+					Name:        "export-auth",
+					Description: "Optional storage credentials (accessKeyId/secretAccessKey, token, or username/password)",
+					MountPath:   "/workspace/export-auth",
+					ReadOnly:    true,
+					Optional:    true,
+				},
+				{
+					Name:        "export-pvc",
+					Description: "Destination volume for pvc exports",
+					MountPath:   "/workspace/export-pvc",
+					Optional:    true,
+				},
+			},
+			Steps: []tektonv1.Step{
+				{
+					Name:  "export-artifact",
+					Image: "$(params.yq-helper-image)",
+					Env: []corev1.EnvVar{
+						{Name: "EXPORT_TYPE", Value: "$(params.export-type)"},
+						{Name: "EXPORT_URL", Value: "$(params.export-url)"},
+						{Name: "S3_ENDPOINT", Value: "$(params.s3-endpoint)"},
+						{Name: "S3_REGION", Value: "$(params.s3-region)"},
+						{Name: "ARTIFACT_FILENAME", Value: "$(params.artifact-filename)"},
+						{Name: "EXPECTED_DIGEST", Value: "$(params.expected-artifact-digest)"},
+						{Name: "RESULT_URL_PATH", Value: "$(results.ARTIFACT_URL.path)"},
+						{Name: "RESULT_DIGEST_PATH", Value: "$(results.ARTIFACT_DIGEST.path)"},
+						{Name: "RESULT_SIZE_PATH", Value: "$(results.ARTIFACT_SIZE.path)"},
+						traceIDEnvVar(),
+					},
+					Script:     PushArtifactStorageScript,
+					WorkingDir: "/workspace/shared",
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      "custom-ca",
+							MountPath: "/etc/pki/ca-trust/custom",
+							ReadOnly:  true,
+						},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name:         "custom-ca",
+					VolumeSource: trustedCABundleVolumeSource(buildConfig),
+				},
+			},
+		},
+	}
+}
+
+// SignArtifactsTaskName is the name of the Task that signs pushed artifacts
+// with cosign.
+const SignArtifactsTaskName = "sign-artifacts"
+
+// GenerateSignArtifactsTask creates a Tekton Task that signs the pushed bootc
+// container and disk artifact with cosign and attaches SLSA provenance built
+// from the PipelineRun as a signed attestation.
+func GenerateSignArtifactsTask(namespace string, buildConfig *BuildConfig) *tektonv1.Task {
+	stringParam := func(name, description, def string) tektonv1.ParamSpec {
+		return tektonv1.ParamSpec{
+			Name:        name,
+			Type:        tektonv1.ParamTypeString,
+			Description: description,
+			Default:     &tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: def},
+		}
+	}
+	return &tektonv1.Task{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "tekton.dev/v1",
+			Kind:       "Task",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SignArtifactsTaskName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "automotive-dev-operator",
+				"app.kubernetes.io/part-of":    "automotive-dev",
+			},
+		},
+		Spec: tektonv1.TaskSpec{
+			Params: []tektonv1.ParamSpec{
+				stringParam("distro", "Distro the image was built for", ""),
+				stringParam("target", "Target the image was built for", ""),
+				stringParam("arch", "Architecture the image was built for", ""),
+				stringParam("mode", "Build mode (package, image, bootc, disk)", ""),
+				stringParam("export-format", "Export format of the disk image", ""),
+				stringParam("custom-defines", "Newline-separated custom build definitions (key=value pairs)", ""),
+				stringParam("aib-extra-args", "Newline-separated extra arguments passed to AIB", ""),
+				stringParam("builder-image", "Builder image used for the build", ""),
+				stringParam("automotive-image-builder", "automotive-image-builder image used for the build", ""),
+				stringParam("task-bundle-ref", "Digest-pinned OCI reference to the Tekton task bundle used for the build", ""),
+				stringParam("pipeline-run-name", "Name of the PipelineRun recorded in the provenance", ""),
+				stringParam("pipeline-run-uid", "UID of the PipelineRun recorded as the provenance invocation ID", ""),
+				stringParam("pipeline-run-namespace", "Namespace of the PipelineRun recorded in the provenance", ""),
+				stringParam("insecure-registry", "Use insecure (skip TLS verify) for registry operations (true/false)", "false"),
+				stringParam("yq-helper-image", "Container image providing curl and python3 for signing", buildConfig.getYQHelperImage()),
+				traceIDParamSpec(),
+			},
+			Workspaces: []tektonv1.WorkspaceDeclaration{
+				{
+					Name:        workspaceNameShared,
+					Description: "Workspace recording the pushed artifact digests",
+					MountPath:   "/workspace/shared",
+				},
+				{
+					Name:        "signing-key",
+					Description: "Secret containing cosign.key and optionally cosign.password",
+					MountPath:   "/workspace/signing-key",
+					ReadOnly:    true,
+				},
+				{
+					Name:        "registry-auth",
+					Description: "Optional registry credentials for the pushed artifacts",
+					MountPath:   "/workspace/registry-auth",
+					ReadOnly:    true,
+					Optional:    true,
+				},
+			},
+			Steps: []tektonv1.Step{
+				{
+					Name:       "sign-artifacts",
+					Image:      "$(params.yq-helper-image)",
+					Env:        []corev1.EnvVar{traceIDEnvVar()},
+					Script:     SignArtifactsScript,
+					WorkingDir: "/workspace/shared",
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      "custom-ca",
+							MountPath: "/etc/pki/ca-trust/custom",
+							ReadOnly:  true,
+						},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name:         "custom-ca",
+					VolumeSource: trustedCABundleVolumeSource(buildConfig),
+				},
 			},
 		},
 	}
@@ -2212,3 +2973,74 @@ func GenerateBuildBuilderJob(namespace, distro, targetRegistry, aibImage string)
 		},
 	}
 }
+
+// WorkspaceSnapshotImageStream is the ImageStream holding exported workspace snapshots.
+const WorkspaceSnapshotImageStream = "workspace-snapshots"
+
+// Workspace snapshot pod modes
+const (
+	WorkspaceSnapshotExport  = "export"
+	WorkspaceSnapshotRestore = "restore"
+)
+
+// WorkspaceSnapshotRef returns the internal registry reference of an exported workspace snapshot.
+func WorkspaceSnapshotRef(namespace, snapshot string) string {
+	return fmt.Sprintf("%s/%s/%s:%s", DefaultInternalRegistryURL, namespace, WorkspaceSnapshotImageStream, snapshot)
+}
+
+// GenerateWorkspaceSnapshotPod creates a pod that exports the workspace volume
+// pvcName to snapshotRef, or restores snapshotRef into it, depending on mode.
+// It runs as the build service account, which can push to the internal registry.
+func GenerateWorkspaceSnapshotPod(namespace, name, mode, pvcName, snapshotRef, image string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "automotive-dev-operator",
+				"app.kubernetes.io/component":  "workspace-snapshot",
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:      corev1.RestartPolicyNever,
+			ServiceAccountName: automotivev1alpha1.BuildServiceAccountName,
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsUser: ptr.To[int64](0),
+			},
+			Containers: []corev1.Container{
+				{
+					Name:    "snapshot",
+					Image:   image,
+					Command: []string{"/bin/bash", "-c", WorkspaceSnapshotScript},
+					Env: []corev1.EnvVar{
+						{Name: "SNAPSHOT_MODE", Value: mode},
+						{Name: "SNAPSHOT_REF", Value: snapshotRef},
+						{Name: "SNAPSHOT_ARTIFACT_TYPE", Value: automotivev1alpha1.WorkspaceSnapshotArtifactType},
+						{Name: "WORKSPACE_DIR", Value: "/workspace"},
+					},
+					SecurityContext: &corev1.SecurityContext{
+						AllowPrivilegeEscalation: ptr.To(false),
+						Capabilities: &corev1.Capabilities{
+							Drop: []corev1.Capability{"ALL"},
+							Add:  []corev1.Capability{"CHOWN", "DAC_OVERRIDE", "DAC_READ_SEARCH", "FOWNER", "FSETID"},
+						},
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "workspace", MountPath: "/workspace"},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: "workspace",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: pvcName,
+						},
+					},
+				},
+			},
+			TerminationGracePeriodSeconds: ptr.To[int64](5),
+		},
+	}
+}
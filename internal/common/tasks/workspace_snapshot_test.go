@@ -0,0 +1,49 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+func TestWorkspaceSnapshotRef(t *testing.T) {
+	want := DefaultInternalRegistryURL + "/ado/workspace-snapshots:dev-snap"
+	if got := WorkspaceSnapshotRef("ado", "dev-snap"); got != want {
+		t.Errorf("WorkspaceSnapshotRef() = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateWorkspaceSnapshotPod(t *testing.T) {
+	ref := WorkspaceSnapshotRef("ado", "dev-snap")
+	pod := GenerateWorkspaceSnapshotPod("ado", "dev-snap-export", WorkspaceSnapshotExport, "dev-workspace", ref, "toolchain:latest")
+
+	if pod.Name != "dev-snap-export" || pod.Namespace != "ado" {
+		t.Fatalf("unexpected pod identity %s/%s", pod.Namespace, pod.Name)
+	}
+	if pod.Spec.ServiceAccountName != automotivev1alpha1.BuildServiceAccountName {
+		t.Errorf("ServiceAccountName = %q, want the build service account", pod.Spec.ServiceAccountName)
+	}
+	if claim := pod.Spec.Volumes[0].PersistentVolumeClaim; claim == nil || claim.ClaimName != "dev-workspace" {
+		t.Errorf("expected the workspace PVC to be mounted, got %+v", pod.Spec.Volumes[0])
+	}
+
+	container := pod.Spec.Containers[0]
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	if env["SNAPSHOT_MODE"] != WorkspaceSnapshotExport || env["SNAPSHOT_REF"] != ref {
+		t.Errorf("unexpected snapshot env %v", env)
+	}
+	if env["SNAPSHOT_ARTIFACT_TYPE"] != automotivev1alpha1.WorkspaceSnapshotArtifactType {
+		t.Errorf("unexpected artifact type %q", env["SNAPSHOT_ARTIFACT_TYPE"])
+	}
+
+	script := container.Command[len(container.Command)-1]
+	for _, want := range []string{"emit_progress()", "detect_registry_protocol()", "\"${ORAS}\" push", "\"${ORAS}\" pull", ".snapshot-restored"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("snapshot script does not contain %q", want)
+		}
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/notifications"
 )
 
 const (
@@ -47,6 +48,7 @@ type CatalogImageReconciler struct {
 	Scheme         *runtime.Scheme
 	Log            logr.Logger
 	RegistryClient RegistryClient
+	Notifier       *notifications.Notifier
 }
 
 // +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,namespace=system,resources=catalogimages,verbs=get;list;watch;create;update;patch;delete
@@ -189,6 +191,7 @@ func (r *CatalogImageReconciler) handleVerifyingPhase(
 	}
 
 	// Update phase
+	wasAvailable := meta.IsStatusConditionTrue(catalogImage.Status.Conditions, automotivev1alpha1.CatalogImageConditionAvailable)
 	catalogImage.Status.Phase = automotivev1alpha1.CatalogImagePhaseAvailable
 	catalogImage.Status.ObservedGeneration = catalogImage.Generation
 
@@ -218,6 +221,10 @@ func (r *CatalogImageReconciler) handleVerifyingPhase(
 	if err := r.Status().Update(ctx, catalogImage); err != nil {
 		return ctrl.Result{}, err
 	}
+	// Periodic re-verifications pass through Verifying; only notify when the image became available
+	if !wasAvailable {
+		r.notify(ctx, catalogImage, "Image is accessible in registry")
+	}
 
 	// Requeue for periodic verification
 	return ctrl.Result{RequeueAfter: r.getVerificationInterval(catalogImage)}, nil
@@ -309,6 +316,7 @@ func (r *CatalogImageReconciler) transitionToUnavailable(
 	catalogImage *automotivev1alpha1.CatalogImage,
 	reason, message string,
 ) (ctrl.Result, error) {
+	previous := meta.FindStatusCondition(catalogImage.Status.Conditions, automotivev1alpha1.CatalogImageConditionAvailable)
 	r.setCondition(catalogImage, automotivev1alpha1.CatalogImageConditionAvailable, metav1.ConditionFalse, reason, message)
 	r.setCondition(catalogImage, automotivev1alpha1.CatalogImageConditionReady, metav1.ConditionFalse, reason, message)
 
@@ -318,6 +326,10 @@ func (r *CatalogImageReconciler) transitionToUnavailable(
 	if err := r.Status().Update(ctx, catalogImage); err != nil {
 		return ctrl.Result{}, err
 	}
+	// Retries pass through Verifying; only notify when the image became unavailable or the reason changed
+	if previous == nil || previous.Status != metav1.ConditionFalse || previous.Reason != reason {
+		r.notify(ctx, catalogImage, message)
+	}
 
 	return ctrl.Result{RequeueAfter: unavailableRetryInterval}, nil
 }
@@ -337,10 +349,18 @@ func (r *CatalogImageReconciler) transitionToFailed(
 	if err := r.Status().Update(ctx, catalogImage); err != nil {
 		return ctrl.Result{}, err
 	}
+	r.notify(ctx, catalogImage, message)
 
 	return ctrl.Result{}, nil
 }
 
+// notify sends the transition of catalogImage from Verifying into its current phase
+// to the notification targets of the OperatorConfig
+func (r *CatalogImageReconciler) notify(ctx context.Context, catalogImage *automotivev1alpha1.CatalogImage, message string) {
+	r.Notifier.Notify(ctx, notifications.EventFor(catalogImage, "CatalogImage",
+		string(automotivev1alpha1.CatalogImagePhaseVerifying), string(catalogImage.Status.Phase), message))
+}
+
 // setCondition sets a condition on the CatalogImage status
 func (r *CatalogImageReconciler) setCondition(
 	catalogImage *automotivev1alpha1.CatalogImage,
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -27,6 +28,8 @@ import (
 	"github.com/containers/image/v5/docker"
 	"github.com/containers/image/v5/manifest"
 	"github.com/containers/image/v5/types"
+	"github.com/docker/distribution/registry/api/errcode"
+	v2 "github.com/docker/distribution/registry/api/v2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8stypes "k8s.io/apimachinery/pkg/types"
@@ -362,6 +365,26 @@ func (c *DefaultRegistryClient) VerifyDigest(
 	return match, actualDigestStr, nil
 }
 
+// IsImageNotFound reports whether a registry error means the manifest does not exist,
+// as opposed to the registry being unreachable or rejecting credentials.
+func IsImageNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	var ec errcode.ErrorCoder
+	if errors.As(err, &ec) && ec.ErrorCode() == v2.ErrorCodeManifestUnknown {
+		return true
+	}
+	// Some registries answer with an untyped "not found" error instead.
+	var e errcode.Error
+	if errors.As(err, &e) && e.ErrorCode() == errcode.ErrorCodeUnknown &&
+		strings.Contains(strings.ToLower(e.Message), "not found") {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "manifest unknown") || strings.Contains(msg, "404 (not found)")
+}
+
 // NormalizeArchitecture normalizes architecture names to OCI standard
 func NormalizeArchitecture(arch string) string {
 	return controllerutils.NormalizeArchToK8s(arch)
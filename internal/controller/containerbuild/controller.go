@@ -9,6 +9,7 @@ import (
 	"time"
 
 	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/notifications"
 	controllerutils "github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/controllerutils"
 	"github.com/go-logr/logr"
 	shipwrightv1beta1 "github.com/shipwright-io/build/pkg/apis/build/v1beta1"
@@ -62,6 +63,7 @@ type ContainerBuildReconciler struct {
 	Scheme   *runtime.Scheme
 	Log      logr.Logger
 	Recorder record.EventRecorder
+	Notifier *notifications.Notifier
 }
 
 //+kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,namespace=system,resources=containerbuilds,verbs=get;list;watch;create;update;patch;delete
@@ -218,6 +220,7 @@ func (r *ContainerBuildReconciler) reconcileUploading(
 			log.Info("source upload completed successfully", "container", waiterContainer.Name)
 			now := metav1.Now()
 			original := cb.DeepCopy()
+			oldPhase := cb.Status.Phase
 			cb.Status.Phase = phaseBuilding
 			cb.Status.Message = "Source uploaded, build in progress"
 			cb.Status.StartTime = &now
@@ -231,6 +234,7 @@ func (r *ContainerBuildReconciler) reconcileUploading(
 				"Source upload completed, build started: buildRun=%s",
 				cb.Status.BuildRunName,
 			)
+			r.notifyPhaseChange(ctx, cb, oldPhase)
 			return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 		}
 		// Waiter failed
@@ -280,6 +284,7 @@ func (r *ContainerBuildReconciler) reconcileBuilding(
 					return ctrl.Result{}, err
 				}
 				r.emitContainerLifecycleEvent(cb, phaseBuilding, phaseCompleted, cb.Status.Message)
+				r.notifyPhaseChange(ctx, cb, phaseBuilding)
 				return ctrl.Result{}, nil
 			}
 			if condition.Status == corev1.ConditionFalse {
@@ -293,6 +298,7 @@ func (r *ContainerBuildReconciler) reconcileBuilding(
 					return ctrl.Result{}, err
 				}
 				r.emitContainerLifecycleEvent(cb, phaseBuilding, phaseFailed, cb.Status.Message)
+				r.notifyPhaseChange(ctx, cb, phaseBuilding)
 				return ctrl.Result{}, nil
 			}
 		}
@@ -334,6 +340,7 @@ func (r *ContainerBuildReconciler) updatePhase(
 			cb.Status.BuildRunName,
 		)
 		r.emitContainerLifecycleEvent(cb, oldPhase, phase, message)
+		r.notifyPhaseChange(ctx, cb, oldPhase)
 	}
 	if phase == phaseFailed || phase == phaseCompleted {
 		return ctrl.Result{}, nil
@@ -394,6 +401,19 @@ func (r *ContainerBuildReconciler) emitContainerLifecycleEvent(
 	}
 }
 
+// notifyPhaseChange sends the transition of cb into its current phase to the
+// notification targets of the OperatorConfig.
+func (r *ContainerBuildReconciler) notifyPhaseChange(
+	ctx context.Context,
+	cb *automotivev1alpha1.ContainerBuild,
+	oldPhase string,
+) {
+	if oldPhase == cb.Status.Phase {
+		return
+	}
+	r.Notifier.Notify(ctx, notifications.EventFor(cb, "ContainerBuild", oldPhase, cb.Status.Phase, cb.Status.Message))
+}
+
 func eventTypeForContainerPhase(phase string) string {
 	if phase == phaseFailed {
 		return corev1.EventTypeWarning
@@ -1,9 +1,14 @@
 package controllerutils
 
 import (
+	"context"
 	"os"
 	"strings"
 	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
 )
 
 const fallbackNamespace = "automotive-dev-operator-system"
@@ -32,3 +37,16 @@ func OperatorNamespace() string {
 	})
 	return resolvedNS
 }
+
+// TenantNamespaces returns the tenant namespaces configured in the OperatorConfig
+// of namespace, or nil when tenancy is disabled or the config cannot be read.
+func TenantNamespaces(ctx context.Context, c client.Reader, namespace string) []string {
+	operatorConfig := &automotivev1alpha1.OperatorConfig{}
+	if err := c.Get(ctx, client.ObjectKey{Name: "config", Namespace: namespace}, operatorConfig); err != nil {
+		return nil
+	}
+	if operatorConfig.Spec.BuildAPI == nil {
+		return nil
+	}
+	return operatorConfig.Spec.BuildAPI.Tenancy.Namespaces()
+}
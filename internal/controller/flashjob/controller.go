@@ -0,0 +1,517 @@
+// Package flashjob provides the controller for FlashJob resources.
+package flashjob
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/apis"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/notifications"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/tasks"
+	controllerutils "github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/controllerutils"
+)
+
+// Reconciler reconciles a FlashJob object
+type Reconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+	Recorder record.EventRecorder
+	Notifier *notifications.Notifier
+}
+
+// +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,namespace=system,resources=flashjobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,namespace=system,resources=flashjobs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,namespace=system,resources=flashjobs/finalizers,verbs=update
+// +kubebuilder:rbac:groups=tekton.dev,namespace=system,resources=taskruns,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",namespace=system,resources=secrets,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups="",namespace=system,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=jumpstarter.dev,resources=exporters,verbs=get;list
+
+// Reconcile handles reconciliation of FlashJob resources.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	flashJob := &automotivev1alpha1.FlashJob{}
+	if err := r.Get(ctx, req.NamespacedName, flashJob); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	switch flashJob.Status.Phase {
+	case "", automotivev1alpha1.FlashJobPhasePending:
+		return r.handlePending(ctx, flashJob)
+	case automotivev1alpha1.FlashJobPhaseRunning:
+		return r.handleRunning(ctx, flashJob)
+	case automotivev1alpha1.FlashJobPhaseCompleted, automotivev1alpha1.FlashJobPhaseFailed:
+		return r.checkExpiry(ctx, flashJob)
+	default:
+		r.Log.Info("Unknown phase", "flashjob", req.NamespacedName, "phase", flashJob.Status.Phase)
+		return ctrl.Result{}, nil
+	}
+}
+
+func (r *Reconciler) handlePending(ctx context.Context, flashJob *automotivev1alpha1.FlashJob) (ctrl.Result, error) {
+	if flashJob.Spec.LeaseName != "" && flashJob.Spec.LeaseDuration != "" {
+		return r.fail(ctx, flashJob, "spec.leaseName and spec.leaseDuration are mutually exclusive")
+	}
+	if err := validateFleet(&flashJob.Spec); err != nil {
+		return r.fail(ctx, flashJob, err.Error())
+	}
+
+	operatorConfig, err := r.loadOperatorConfig(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	exporterSelector, flashCmd := resolveTargetConfig(&flashJob.Spec, operatorConfig)
+	if exporterSelector == "" {
+		return r.fail(ctx, flashJob, fmt.Sprintf("no exporter selector: set spec.exporterSelector or a target mapped in OperatorConfig (target %q)", flashJob.Spec.Target))
+	}
+
+	if flashJob.Spec.Fleet != nil {
+		return r.handleFleetPending(ctx, flashJob, operatorConfig, exporterSelector, flashCmd)
+	}
+
+	taskRun, err := r.createTaskRun(ctx, flashJob, operatorConfig, flashJob.Name, 0, exporterSelector, flashCmd)
+	if err != nil {
+		return r.fail(ctx, flashJob, fmt.Sprintf("Failed to create flash TaskRun: %v", err))
+	}
+	r.emitEventf(flashJob, corev1.EventTypeNormal, "TaskRunCreated",
+		"Flash TaskRun created: name=%s exporterSelector=%s", taskRun.Name, exporterSelector)
+
+	now := metav1.Now()
+	flashJob.Status.TaskRunName = taskRun.Name
+	flashJob.Status.StartTime = &now
+	leaseMessage := fmt.Sprintf("Requesting a lease on an exporter matching %s", exporterSelector)
+	if flashJob.Spec.LeaseName != "" {
+		leaseMessage = fmt.Sprintf("Using existing lease %s", flashJob.Spec.LeaseName)
+	}
+	setCondition(flashJob, automotivev1alpha1.FlashJobConditionLeaseAcquired, metav1.ConditionUnknown, "LeaseRequested", leaseMessage)
+	return r.updateStatus(ctx, flashJob, automotivev1alpha1.FlashJobPhaseRunning, "Flash in progress")
+}
+
+func (r *Reconciler) handleRunning(ctx context.Context, flashJob *automotivev1alpha1.FlashJob) (ctrl.Result, error) {
+	if flashJob.Spec.Fleet != nil {
+		return r.handleFleetRunning(ctx, flashJob)
+	}
+
+	taskRun := &tektonv1.TaskRun{}
+	if err := r.Get(ctx, client.ObjectKey{Name: flashJob.Status.TaskRunName, Namespace: flashJob.Namespace}, taskRun); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return r.fail(ctx, flashJob, "flash TaskRun not found")
+		}
+		return ctrl.Result{}, err
+	}
+	if !taskRun.IsDone() {
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	applyTaskRunResults(flashJob, taskRun)
+	if err := r.cleanupTransientSecrets(ctx, flashJob); err != nil {
+		r.Log.Error(err, "Failed to delete transient flash secret", "flashjob", flashJob.Name)
+	}
+
+	now := metav1.Now()
+	flashJob.Status.CompletionTime = &now
+	deviceTests := flashJob.Status.DeviceTests
+	if taskRun.IsSuccessful() {
+		message := "Flash completed successfully"
+		if deviceTests != nil {
+			message += "; device tests passed: " + deviceTests.Message
+		}
+		return r.updateStatus(ctx, flashJob, automotivev1alpha1.FlashJobPhaseCompleted, message)
+	}
+	if deviceTests != nil && !deviceTests.Succeeded() {
+		return r.updateStatus(ctx, flashJob, automotivev1alpha1.FlashJobPhaseFailed, "Device tests failed: "+deviceTests.Message)
+	}
+	return r.updateStatus(ctx, flashJob, automotivev1alpha1.FlashJobPhaseFailed, taskRunFailureMessage(taskRun))
+}
+
+// applyTaskRunResults records the lease, exporter, device test results and
+// console transcript reported by a finished flash TaskRun and sets the
+// conditions from them.
+func applyTaskRunResults(flashJob *automotivev1alpha1.FlashJob, taskRun *tektonv1.TaskRun) {
+	results := taskRunResults(taskRun)
+	flashJob.Status.LeaseID = results[tasks.FlashResultLeaseID]
+	flashJob.Status.Exporter = results[tasks.FlashResultExporter]
+	flashJob.Status.ConsoleRef = results[tasks.FlashResultConsoleRef]
+	if flashJob.Spec.Test != nil {
+		deviceTests, err := tasks.ParseDeviceTestSummary(results[tasks.FlashResultTestSummary])
+		if err == nil {
+			flashJob.Status.DeviceTests = deviceTests
+		}
+	}
+	// Device tests only run once the image was flashed
+	flashed := taskRun.IsSuccessful() || flashJob.Status.DeviceTests != nil
+
+	leaseID := flashJob.Status.LeaseID
+	if leaseID == "" {
+		setCondition(flashJob, automotivev1alpha1.FlashJobConditionLeaseAcquired, metav1.ConditionFalse,
+			"LeaseNotAcquired", taskRunFailureMessage(taskRun))
+		setCondition(flashJob, automotivev1alpha1.FlashJobConditionFlashed, metav1.ConditionFalse,
+			"NoLease", "No lease was acquired, the device was not flashed")
+		return
+	}
+
+	acquired := fmt.Sprintf("Lease %s acquired", leaseID)
+	if flashJob.Status.Exporter != "" {
+		acquired = fmt.Sprintf("Lease %s acquired on exporter %s", leaseID, flashJob.Status.Exporter)
+	}
+	setCondition(flashJob, automotivev1alpha1.FlashJobConditionLeaseAcquired, metav1.ConditionTrue, "LeaseAcquired", acquired)
+
+	if flashed {
+		setCondition(flashJob, automotivev1alpha1.FlashJobConditionFlashed, metav1.ConditionTrue,
+			"FlashSucceeded", fmt.Sprintf("Flashed %s", flashJob.Spec.ImageRef))
+	} else {
+		setCondition(flashJob, automotivev1alpha1.FlashJobConditionFlashed, metav1.ConditionFalse,
+			"FlashFailed", taskRunFailureMessage(taskRun))
+	}
+
+	switch {
+	case flashJob.Spec.LeaseName != "":
+		setCondition(flashJob, automotivev1alpha1.FlashJobConditionLeaseReleased, metav1.ConditionFalse,
+			"UserLease", fmt.Sprintf("Lease %s is managed by its owner", leaseID))
+	case results[tasks.FlashResultLeaseReleased] == "true":
+		setCondition(flashJob, automotivev1alpha1.FlashJobConditionLeaseReleased, metav1.ConditionTrue,
+			"ReleasedOnFailure", fmt.Sprintf("Lease %s released after the flash failed", leaseID))
+	case flashed:
+		setCondition(flashJob, automotivev1alpha1.FlashJobConditionLeaseReleased, metav1.ConditionFalse,
+			"LeaseHeld", fmt.Sprintf("Lease %s is kept for access to the device until it expires", leaseID))
+	default:
+		setCondition(flashJob, automotivev1alpha1.FlashJobConditionLeaseReleased, metav1.ConditionFalse,
+			"ReleaseFailed", fmt.Sprintf("Lease %s could not be released and is held until it expires", leaseID))
+	}
+
+	if deviceTests := flashJob.Status.DeviceTests; deviceTests != nil {
+		switch {
+		case !deviceTests.Booted:
+			setCondition(flashJob, automotivev1alpha1.FlashJobConditionTested, metav1.ConditionFalse,
+				"BootFailed", deviceTests.Message)
+		case deviceTests.Failed > 0:
+			setCondition(flashJob, automotivev1alpha1.FlashJobConditionTested, metav1.ConditionFalse,
+				"TestsFailed", deviceTests.Message)
+		default:
+			setCondition(flashJob, automotivev1alpha1.FlashJobConditionTested, metav1.ConditionTrue,
+				"TestsPassed", deviceTests.Message)
+		}
+	}
+}
+
+// taskRunResults returns the results of a TaskRun by name.
+func taskRunResults(taskRun *tektonv1.TaskRun) map[string]string {
+	results := make(map[string]string, len(taskRun.Status.Results))
+	for _, result := range taskRun.Status.Results {
+		results[result.Name] = strings.TrimSpace(result.Value.StringVal)
+	}
+	return results
+}
+
+// taskRunFailureMessage returns the message of the Succeeded condition of a failed TaskRun.
+func taskRunFailureMessage(taskRun *tektonv1.TaskRun) string {
+	if cond := taskRun.Status.GetCondition(apis.ConditionSucceeded); cond != nil && cond.Message != "" {
+		return cond.Message
+	}
+	return "Flash failed"
+}
+
+// loadOperatorConfig returns the OperatorConfig, or an empty one when it does not exist.
+func (r *Reconciler) loadOperatorConfig(ctx context.Context) (*automotivev1alpha1.OperatorConfig, error) {
+	operatorConfig := &automotivev1alpha1.OperatorConfig{}
+	if err := r.Get(ctx, client.ObjectKey{Name: "config", Namespace: controllerutils.OperatorNamespace()}, operatorConfig); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to load OperatorConfig: %w", err)
+		}
+		return &automotivev1alpha1.OperatorConfig{}, nil
+	}
+	return operatorConfig, nil
+}
+
+// resolveTargetConfig resolves the exporter selector and flash command from the
+// spec, falling back to the OperatorConfig mapping of the spec target.
+func resolveTargetConfig(spec *automotivev1alpha1.FlashJobSpec, operatorConfig *automotivev1alpha1.OperatorConfig) (string, string) {
+	exporterSelector := spec.ExporterSelector
+	flashCmd := spec.FlashCmd
+	if spec.Target != "" && operatorConfig.Spec.Jumpstarter != nil {
+		if mapping, ok := operatorConfig.Spec.Jumpstarter.TargetMappings[spec.Target]; ok {
+			if exporterSelector == "" {
+				exporterSelector = mapping.Selector
+			}
+			if flashCmd == "" {
+				flashCmd = mapping.FlashCmd
+			}
+		}
+	}
+	flashCmd = strings.ReplaceAll(flashCmd, "{image_uri}", spec.ImageRef)
+	flashCmd = strings.ReplaceAll(flashCmd, "{artifact_url}", spec.ImageRef)
+	return exporterSelector, flashCmd
+}
+
+// resolveLeaseDuration returns the lease duration to request, or empty when an
+// existing lease is used.
+// Fallback: spec > FlashTimeoutMinutes (as HH:MM:SS) > Jumpstarter default.
+func resolveLeaseDuration(spec *automotivev1alpha1.FlashJobSpec, operatorConfig *automotivev1alpha1.OperatorConfig) string {
+	if spec.LeaseName != "" || spec.LeaseDuration != "" {
+		return spec.LeaseDuration
+	}
+	if operatorConfig.Spec.OSBuilds != nil && operatorConfig.Spec.OSBuilds.FlashTimeoutMinutes > 0 {
+		m := operatorConfig.Spec.OSBuilds.FlashTimeoutMinutes
+		return fmt.Sprintf("%02d:%02d:00", m/60, m%60)
+	}
+	return operatorConfig.Spec.Jumpstarter.GetDefaultLeaseDuration()
+}
+
+// createTaskRun creates the flash TaskRun with the given name. board is the
+// 1-based fleet board the TaskRun flashes, or 0 for a single-board flash.
+func (r *Reconciler) createTaskRun(
+	ctx context.Context,
+	flashJob *automotivev1alpha1.FlashJob,
+	operatorConfig *automotivev1alpha1.OperatorConfig,
+	name string,
+	board int32,
+	exporterSelector, flashCmd string,
+) (*tektonv1.TaskRun, error) {
+	existing := &tektonv1.TaskRun{}
+	if err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: flashJob.Namespace}, existing); err == nil {
+		if !metav1.IsControlledBy(existing, flashJob) {
+			return nil, fmt.Errorf("TaskRun %s already exists and is not owned by this FlashJob", existing.Name)
+		}
+		return existing, nil
+	} else if !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	var buildConfig *tasks.BuildConfig
+	if operatorConfig.Spec.OSBuilds != nil {
+		buildConfig = &tasks.BuildConfig{
+			FlashTimeoutMinutes:  operatorConfig.Spec.OSBuilds.GetFlashTimeoutMinutes(),
+			DefaultLeaseDuration: operatorConfig.Spec.Jumpstarter.GetDefaultLeaseDuration(),
+		}
+	}
+	flashTask := tasks.GenerateFlashTask(flashJob.Namespace, buildConfig)
+	testParams, err := tasks.DeviceTestParams(flashJob.Spec.Test, operatorConfig.Spec.Jumpstarter.GetJumpstarterImage())
+	if err != nil {
+		return nil, err
+	}
+	consoleParams, err := tasks.ConsoleParams(flashJob.Spec.Console)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces := []tektonv1.WorkspaceBinding{
+		{
+			Name:   "jumpstarter-client",
+			Secret: &corev1.SecretVolumeSource{SecretName: flashJob.Spec.ClientConfigSecretRef},
+		},
+	}
+	if flashJob.Spec.RegistryAuthSecretRef != "" {
+		workspaces = append(workspaces, tektonv1.WorkspaceBinding{
+			Name:   "flash-oci-auth",
+			Secret: &corev1.SecretVolumeSource{SecretName: flashJob.Spec.RegistryAuthSecretRef},
+		})
+	}
+
+	taskRun := &tektonv1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: flashJob.Namespace,
+			Labels: map[string]string{
+				labels.ManagedBy: labels.ValueOperator,
+				labels.PartOf:    labels.ValueAutomotiveDev,
+				labels.TaskType:  "flash",
+				labels.FlashJob:  flashJob.Name,
+			},
+			Annotations: map[string]string{
+				labels.ImageRef: flashJob.Spec.ImageRef,
+			},
+		},
+		Spec: tektonv1.TaskRunSpec{
+			ServiceAccountName: automotivev1alpha1.BuildServiceAccountName,
+			TaskSpec:           &flashTask.Spec,
+			Params: append([]tektonv1.Param{
+				{Name: "image-ref", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: flashJob.Spec.ImageRef}},
+				{Name: "exporter-selector", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: exporterSelector}},
+				{Name: "flash-cmd", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: flashCmd}},
+				{Name: "lease-duration", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: resolveLeaseDuration(&flashJob.Spec, operatorConfig)}},
+				{Name: "lease-name", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: flashJob.Spec.LeaseName}},
+			}, append(testParams, consoleParams...)...),
+			Workspaces: workspaces,
+		},
+	}
+	if board > 0 {
+		taskRun.Labels[labels.FleetBoard] = strconv.Itoa(int(board))
+	}
+	if requestedBy := flashJob.Annotations[labels.RequestedBy]; requestedBy != "" {
+		taskRun.Annotations[labels.RequestedBy] = requestedBy
+	}
+	if err := controllerutil.SetControllerReference(flashJob, taskRun, r.Scheme); err != nil {
+		return nil, fmt.Errorf("set owner reference: %w", err)
+	}
+	if err := r.Create(ctx, taskRun); err != nil {
+		return nil, err
+	}
+	return taskRun, nil
+}
+
+// checkExpiry deletes a finished FlashJob once its TTL has passed. Its TaskRun
+// and secrets are garbage collected through their owner references.
+func (r *Reconciler) checkExpiry(ctx context.Context, flashJob *automotivev1alpha1.FlashJob) (ctrl.Result, error) {
+	if !flashJob.Status.IsFinished() || flashJob.Status.CompletionTime == nil {
+		return ctrl.Result{}, nil
+	}
+
+	var ttl time.Duration
+	if flashJob.Annotations[automotivev1alpha1.NoExpireAnnotation] != "true" {
+		var err error
+		if ttl, err = r.resolveEffectiveTTL(ctx, flashJob); err != nil {
+			r.emitEventf(flashJob, corev1.EventTypeWarning, "InvalidTTL",
+				"Failed to resolve TTL, expiry disabled for this flash job: %v", err)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	var expiresAt *metav1.Time
+	if ttl > 0 {
+		t := metav1.NewTime(flashJob.Status.CompletionTime.Add(ttl).Truncate(time.Second))
+		expiresAt = &t
+	}
+	if !expiresAt.Equal(flashJob.Status.ExpiresAt) {
+		flashJob.Status.ExpiresAt = expiresAt
+		if err := r.Status().Update(ctx, flashJob); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if expiresAt == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if remaining := time.Until(expiresAt.Time); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+	r.Log.Info("FlashJob expired, deleting", "flashjob", flashJob.Name, "ttl", ttl)
+	if err := r.Delete(ctx, flashJob); err != nil && !k8serrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// resolveEffectiveTTL returns 0 if expiry is disabled.
+func (r *Reconciler) resolveEffectiveTTL(ctx context.Context, flashJob *automotivev1alpha1.FlashJob) (time.Duration, error) {
+	ttlStr := flashJob.Spec.TTL
+	if ttlStr == "" {
+		ttlStr = automotivev1alpha1.DefaultBuildTTL
+		operatorConfig := &automotivev1alpha1.OperatorConfig{}
+		if err := r.Get(ctx, client.ObjectKey{Name: "config", Namespace: controllerutils.OperatorNamespace()}, operatorConfig); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return 0, fmt.Errorf("failed to load OperatorConfig: %w", err)
+			}
+		} else if operatorConfig.Spec.OSBuilds != nil {
+			ttlStr = operatorConfig.Spec.OSBuilds.GetDefaultBuildTTL()
+		}
+	}
+	if ttlStr == "0" {
+		return 0, nil
+	}
+
+	ttl, err := time.ParseDuration(ttlStr)
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, fmt.Errorf("TTL must not be negative: %s", ttlStr)
+	}
+	return ttl, nil
+}
+
+// cleanupTransientSecrets deletes the registry credentials created by the API
+// server for this flash once the flash finished.
+func (r *Reconciler) cleanupTransientSecrets(ctx context.Context, flashJob *automotivev1alpha1.FlashJob) error {
+	name := flashJob.Spec.RegistryAuthSecretRef
+	if name == "" {
+		return nil
+	}
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: flashJob.Namespace}, secret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if secret.Labels[labels.Transient] != labels.ValueTrue {
+		return nil
+	}
+	return client.IgnoreNotFound(r.Delete(ctx, secret))
+}
+
+func (r *Reconciler) fail(ctx context.Context, flashJob *automotivev1alpha1.FlashJob, message string) (ctrl.Result, error) {
+	now := metav1.Now()
+	flashJob.Status.CompletionTime = &now
+	return r.updateStatus(ctx, flashJob, automotivev1alpha1.FlashJobPhaseFailed, message)
+}
+
+func (r *Reconciler) updateStatus(ctx context.Context, flashJob *automotivev1alpha1.FlashJob, phase, message string) (ctrl.Result, error) {
+	oldPhase := flashJob.Status.Phase
+	flashJob.Status.Phase = phase
+	flashJob.Status.Message = message
+	if err := r.Status().Update(ctx, flashJob); err != nil {
+		return ctrl.Result{}, err
+	}
+	if oldPhase != phase {
+		eventType := corev1.EventTypeNormal
+		if phase == automotivev1alpha1.FlashJobPhaseFailed {
+			eventType = corev1.EventTypeWarning
+		}
+		r.emitEventf(flashJob, eventType, "PhaseChanged", "Phase transitioned: %s -> %s, message=%s", oldPhase, phase, message)
+		r.Notifier.Notify(ctx, notifications.EventFor(flashJob, "FlashJob", oldPhase, phase, message))
+	}
+	if phase == automotivev1alpha1.FlashJobPhaseRunning {
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	return r.checkExpiry(ctx, flashJob)
+}
+
+func setCondition(
+	flashJob *automotivev1alpha1.FlashJob,
+	conditionType string,
+	status metav1.ConditionStatus,
+	reason, message string,
+) {
+	apimeta.SetStatusCondition(&flashJob.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: flashJob.Generation,
+	})
+}
+
+func (r *Reconciler) emitEventf(
+	flashJob *automotivev1alpha1.FlashJob,
+	eventType, reason, messageFmt string,
+	args ...interface{},
+) {
+	if r.Recorder == nil || flashJob == nil {
+		return
+	}
+	r.Recorder.Eventf(flashJob, eventType, reason, messageFmt, args...)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&automotivev1alpha1.FlashJob{}).
+		Owns(&tektonv1.TaskRun{}).
+		Complete(r)
+}
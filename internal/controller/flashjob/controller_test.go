@@ -0,0 +1,412 @@
+package flashjob
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"knative.dev/pkg/apis"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/tasks"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/controllerutils"
+)
+
+func newTestReconciler(objs ...client.Object) (*Reconciler, client.Client) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(tektonv1.AddToScheme(scheme))
+	utilruntime.Must(automotivev1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&automotivev1alpha1.FlashJob{}).
+		Build()
+	return &Reconciler{Client: fakeClient, Scheme: scheme, Log: logr.Discard()}, fakeClient
+}
+
+func newFlashJob(name string) *automotivev1alpha1.FlashJob {
+	return &automotivev1alpha1.FlashJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "builds",
+			UID:         types.UID(name + "-uid"),
+			Annotations: map[string]string{labels.RequestedBy: "alice"},
+		},
+		Spec: automotivev1alpha1.FlashJobSpec{
+			ImageRef:              "quay.io/org/disk:v1",
+			Target:                "j784s4evm",
+			ClientConfigSecretRef: name + "-jumpstarter-client",
+		},
+	}
+}
+
+func reconcileFlashJob(t *testing.T, r *Reconciler, name string) ctrl.Result {
+	t.Helper()
+	result, err := r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: name, Namespace: "builds"},
+	})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	return result
+}
+
+func doneTaskRun(name string, succeeded bool, results map[string]string) *tektonv1.TaskRun {
+	tr := &tektonv1.TaskRun{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "builds"}}
+	status := corev1.ConditionTrue
+	if !succeeded {
+		status = corev1.ConditionFalse
+	}
+	tr.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: status, Message: "flash step failed"})
+	for k, v := range results {
+		tr.Status.Results = append(tr.Status.Results, tektonv1.TaskRunResult{
+			Name:  k,
+			Value: *tektonv1.NewStructuredValues(v),
+		})
+	}
+	return tr
+}
+
+func TestReconcile_CreatesTaskRunFromTargetMapping(t *testing.T) {
+	operatorConfig := &automotivev1alpha1.OperatorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: controllerutils.OperatorNamespace()},
+		Spec: automotivev1alpha1.OperatorConfigSpec{
+			Jumpstarter: &automotivev1alpha1.JumpstarterConfig{
+				TargetMappings: map[string]automotivev1alpha1.JumpstarterTargetMapping{
+					"j784s4evm": {Selector: "board=j784s4evm", FlashCmd: "j storage flash oci://{image_uri}"},
+				},
+			},
+		},
+	}
+	r, c := newTestReconciler(operatorConfig, newFlashJob("flash-1"))
+
+	reconcileFlashJob(t, r, "flash-1")
+
+	tr := &tektonv1.TaskRun{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "flash-1", Namespace: "builds"}, tr); err != nil {
+		t.Fatalf("expected TaskRun: %v", err)
+	}
+	if len(tr.OwnerReferences) != 1 || tr.OwnerReferences[0].Kind != "FlashJob" {
+		t.Errorf("expected TaskRun owned by the FlashJob, got %v", tr.OwnerReferences)
+	}
+	params := map[string]string{}
+	for _, p := range tr.Spec.Params {
+		params[p.Name] = p.Value.StringVal
+	}
+	if params["exporter-selector"] != "board=j784s4evm" {
+		t.Errorf("exporter-selector = %q", params["exporter-selector"])
+	}
+	if params["flash-cmd"] != "j storage flash oci://quay.io/org/disk:v1" {
+		t.Errorf("flash-cmd = %q", params["flash-cmd"])
+	}
+	if params["lease-duration"] == "" {
+		t.Error("expected a default lease duration")
+	}
+	if tr.Annotations[labels.RequestedBy] != "alice" {
+		t.Errorf("expected requested-by annotation, got %v", tr.Annotations)
+	}
+
+	fj := &automotivev1alpha1.FlashJob{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "flash-1", Namespace: "builds"}, fj); err != nil {
+		t.Fatal(err)
+	}
+	if fj.Status.Phase != automotivev1alpha1.FlashJobPhaseRunning || fj.Status.TaskRunName != "flash-1" {
+		t.Errorf("status = %+v", fj.Status)
+	}
+	cond := apimeta.FindStatusCondition(fj.Status.Conditions, automotivev1alpha1.FlashJobConditionLeaseAcquired)
+	if cond == nil || cond.Status != metav1.ConditionUnknown {
+		t.Errorf("expected LeaseAcquired Unknown, got %v", cond)
+	}
+}
+
+func TestReconcile_FailsWithoutExporterSelector(t *testing.T) {
+	r, c := newTestReconciler(newFlashJob("flash-1"))
+
+	reconcileFlashJob(t, r, "flash-1")
+
+	fj := &automotivev1alpha1.FlashJob{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "flash-1", Namespace: "builds"}, fj); err != nil {
+		t.Fatal(err)
+	}
+	if fj.Status.Phase != automotivev1alpha1.FlashJobPhaseFailed {
+		t.Errorf("phase = %q, want Failed", fj.Status.Phase)
+	}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "flash-1", Namespace: "builds"}, &tektonv1.TaskRun{}); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected no TaskRun, got %v", err)
+	}
+}
+
+func TestReconcile_RecordsLeaseAndExporter(t *testing.T) {
+	fj := newFlashJob("flash-1")
+	fj.Spec.TTL = "1h"
+	fj.Status.Phase = automotivev1alpha1.FlashJobPhaseRunning
+	fj.Status.TaskRunName = "flash-1"
+	tr := doneTaskRun("flash-1", true, map[string]string{
+		tasks.FlashResultLeaseID:       "lease-123",
+		tasks.FlashResultExporter:      "board-01",
+		tasks.FlashResultLeaseReleased: "false",
+	})
+	r, c := newTestReconciler(fj, tr)
+
+	result := reconcileFlashJob(t, r, "flash-1")
+
+	got := &automotivev1alpha1.FlashJob{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "flash-1", Namespace: "builds"}, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.Phase != automotivev1alpha1.FlashJobPhaseCompleted {
+		t.Errorf("phase = %q, want Completed", got.Status.Phase)
+	}
+	if got.Status.LeaseID != "lease-123" || got.Status.Exporter != "board-01" {
+		t.Errorf("lease = %q, exporter = %q", got.Status.LeaseID, got.Status.Exporter)
+	}
+	for condType, want := range map[string]metav1.ConditionStatus{
+		automotivev1alpha1.FlashJobConditionLeaseAcquired: metav1.ConditionTrue,
+		automotivev1alpha1.FlashJobConditionFlashed:       metav1.ConditionTrue,
+		automotivev1alpha1.FlashJobConditionLeaseReleased: metav1.ConditionFalse,
+	} {
+		if cond := apimeta.FindStatusCondition(got.Status.Conditions, condType); cond == nil || cond.Status != want {
+			t.Errorf("%s = %v, want %s", condType, cond, want)
+		}
+	}
+	if got.Status.ExpiresAt == nil {
+		t.Error("expected ExpiresAt to be set")
+	}
+	if result.RequeueAfter <= 0 || result.RequeueAfter > time.Hour {
+		t.Errorf("expected requeue at expiry, got %v", result.RequeueAfter)
+	}
+}
+
+func TestApplyTaskRunResults_LeaseReleased(t *testing.T) {
+	tests := []struct {
+		name      string
+		leaseName string
+		succeeded bool
+		results   map[string]string
+		acquired  metav1.ConditionStatus
+		released  *metav1.ConditionStatus
+		reason    string
+	}{
+		{
+			name:      "released after failure",
+			succeeded: false,
+			results:   map[string]string{tasks.FlashResultLeaseID: "lease-1", tasks.FlashResultLeaseReleased: "true"},
+			acquired:  metav1.ConditionTrue,
+			released:  ptrTo(metav1.ConditionTrue),
+			reason:    "ReleasedOnFailure",
+		},
+		{
+			name:      "user provided lease",
+			leaseName: "lease-1",
+			succeeded: true,
+			results:   map[string]string{tasks.FlashResultLeaseID: "lease-1"},
+			acquired:  metav1.ConditionTrue,
+			released:  ptrTo(metav1.ConditionFalse),
+			reason:    "UserLease",
+		},
+		{
+			name:      "no lease acquired",
+			succeeded: false,
+			results:   map[string]string{},
+			acquired:  metav1.ConditionFalse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fj := newFlashJob("flash-1")
+			fj.Spec.LeaseName = tt.leaseName
+
+			applyTaskRunResults(fj, doneTaskRun("flash-1", tt.succeeded, tt.results))
+
+			acquired := apimeta.FindStatusCondition(fj.Status.Conditions, automotivev1alpha1.FlashJobConditionLeaseAcquired)
+			if acquired == nil || acquired.Status != tt.acquired {
+				t.Errorf("LeaseAcquired = %v, want %s", acquired, tt.acquired)
+			}
+			released := apimeta.FindStatusCondition(fj.Status.Conditions, automotivev1alpha1.FlashJobConditionLeaseReleased)
+			if tt.released == nil {
+				if released != nil {
+					t.Errorf("expected no LeaseReleased condition, got %v", released)
+				}
+				return
+			}
+			if released == nil || released.Status != *tt.released || released.Reason != tt.reason {
+				t.Errorf("LeaseReleased = %v, want %s/%s", released, *tt.released, tt.reason)
+			}
+		})
+	}
+}
+
+func TestApplyTaskRunResults_DeviceTests(t *testing.T) {
+	tests := []struct {
+		name      string
+		succeeded bool
+		summary   string
+		flashed   metav1.ConditionStatus
+		tested    metav1.ConditionStatus
+		reason    string
+	}{
+		{
+			name:      "tests passed",
+			succeeded: true,
+			summary:   `{"booted":true,"total":2,"passed":2,"message":"2 tests passed"}`,
+			flashed:   metav1.ConditionTrue,
+			tested:    metav1.ConditionTrue,
+			reason:    "TestsPassed",
+		},
+		{
+			name:    "tests failed",
+			summary: `{"booted":true,"total":2,"passed":1,"failed":1,"message":"1 of 2 tests failed"}`,
+			flashed: metav1.ConditionTrue,
+			tested:  metav1.ConditionFalse,
+			reason:  "TestsFailed",
+		},
+		{
+			name:    "device did not boot",
+			summary: `{"booted":false,"message":"Device did not boot within 600s"}`,
+			flashed: metav1.ConditionTrue,
+			tested:  metav1.ConditionFalse,
+			reason:  "BootFailed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fj := newFlashJob("flash-1")
+			fj.Spec.Test = &automotivev1alpha1.DeviceTestSpec{Script: "true"}
+
+			applyTaskRunResults(fj, doneTaskRun("flash-1", tt.succeeded, map[string]string{
+				tasks.FlashResultLeaseID:     "lease-1",
+				tasks.FlashResultTestSummary: tt.summary,
+			}))
+
+			if fj.Status.DeviceTests == nil {
+				t.Fatal("expected device test results")
+			}
+			flashed := apimeta.FindStatusCondition(fj.Status.Conditions, automotivev1alpha1.FlashJobConditionFlashed)
+			if flashed == nil || flashed.Status != tt.flashed {
+				t.Errorf("Flashed = %v, want %s", flashed, tt.flashed)
+			}
+			tested := apimeta.FindStatusCondition(fj.Status.Conditions, automotivev1alpha1.FlashJobConditionTested)
+			if tested == nil || tested.Status != tt.tested || tested.Reason != tt.reason {
+				t.Errorf("Tested = %v, want %s/%s", tested, tt.tested, tt.reason)
+			}
+		})
+	}
+}
+
+func TestReconcile_DeviceTestsFailJob(t *testing.T) {
+	fj := newFlashJob("flash-1")
+	fj.Spec.Test = &automotivev1alpha1.DeviceTestSpec{Script: "true"}
+	fj.Status.Phase = automotivev1alpha1.FlashJobPhaseRunning
+	fj.Status.TaskRunName = "flash-1"
+	tr := doneTaskRun("flash-1", false, map[string]string{
+		tasks.FlashResultLeaseID:     "lease-123",
+		tasks.FlashResultTestSummary: `{"booted":true,"total":1,"failed":1,"message":"1 of 1 tests failed"}`,
+	})
+	r, c := newTestReconciler(fj, tr)
+
+	reconcileFlashJob(t, r, "flash-1")
+
+	got := &automotivev1alpha1.FlashJob{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "flash-1", Namespace: "builds"}, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.Phase != automotivev1alpha1.FlashJobPhaseFailed {
+		t.Errorf("phase = %q, want Failed", got.Status.Phase)
+	}
+	if !strings.Contains(got.Status.Message, "Device tests failed") {
+		t.Errorf("message = %q, want device test failure", got.Status.Message)
+	}
+	if got.Status.DeviceTests == nil || got.Status.DeviceTests.Failed != 1 {
+		t.Errorf("unexpected device tests %+v", got.Status.DeviceTests)
+	}
+}
+
+func TestReconcile_PassesConsoleParams(t *testing.T) {
+	fj := newFlashJob("flash-1")
+	fj.Spec.ExporterSelector = "board=j784s4evm"
+	fj.Spec.Console = &automotivev1alpha1.ConsoleCaptureSpec{Duration: "2m", SkipUpload: true}
+	r, c := newTestReconciler(fj)
+
+	reconcileFlashJob(t, r, "flash-1")
+
+	tr := &tektonv1.TaskRun{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "flash-1", Namespace: "builds"}, tr); err != nil {
+		t.Fatalf("expected TaskRun: %v", err)
+	}
+	params := map[string]string{}
+	for _, p := range tr.Spec.Params {
+		params[p.Name] = p.Value.StringVal
+	}
+	if params["console-enabled"] != "true" || params["console-seconds"] != "120" || params["console-upload"] != "false" {
+		t.Errorf("unexpected console params %v", params)
+	}
+}
+
+func TestApplyTaskRunResults_ConsoleRef(t *testing.T) {
+	fj := newFlashJob("flash-1")
+	fj.Spec.Console = &automotivev1alpha1.ConsoleCaptureSpec{}
+
+	applyTaskRunResults(fj, doneTaskRun("flash-1", true, map[string]string{
+		tasks.FlashResultLeaseID:    "lease-1",
+		tasks.FlashResultConsoleRef: "quay.io/org/disk@sha256:abc",
+	}))
+
+	if fj.Status.ConsoleRef != "quay.io/org/disk@sha256:abc" {
+		t.Errorf("ConsoleRef = %q", fj.Status.ConsoleRef)
+	}
+}
+
+func TestReconcile_DeletesExpiredFlashJob(t *testing.T) {
+	fj := newFlashJob("flash-1")
+	fj.Spec.TTL = "1h"
+	completed := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	fj.Status.Phase = automotivev1alpha1.FlashJobPhaseCompleted
+	fj.Status.CompletionTime = &completed
+	r, c := newTestReconciler(fj)
+
+	reconcileFlashJob(t, r, "flash-1")
+
+	err := c.Get(context.Background(), types.NamespacedName{Name: "flash-1", Namespace: "builds"}, &automotivev1alpha1.FlashJob{})
+	if !k8serrors.IsNotFound(err) {
+		t.Errorf("expected expired FlashJob to be deleted, got %v", err)
+	}
+}
+
+func TestReconcile_KeepsFlashJobWithoutTTL(t *testing.T) {
+	fj := newFlashJob("flash-1")
+	fj.Spec.TTL = "0"
+	completed := metav1.NewTime(time.Now().Add(-48 * time.Hour))
+	fj.Status.Phase = automotivev1alpha1.FlashJobPhaseFailed
+	fj.Status.CompletionTime = &completed
+	r, c := newTestReconciler(fj)
+
+	reconcileFlashJob(t, r, "flash-1")
+
+	got := &automotivev1alpha1.FlashJob{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "flash-1", Namespace: "builds"}, got); err != nil {
+		t.Fatalf("expected FlashJob to be kept: %v", err)
+	}
+	if got.Status.ExpiresAt != nil {
+		t.Errorf("expected no ExpiresAt, got %v", got.Status.ExpiresAt)
+	}
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,316 @@
+package flashjob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/tasks"
+)
+
+// maxFailedBoardsInMessage limits how many failed boards are listed in the FlashJob message.
+const maxFailedBoardsInMessage = 5
+
+// exporterListGVK is the list kind of the Jumpstarter Exporter resources.
+var exporterListGVK = schema.GroupVersionKind{Group: "jumpstarter.dev", Version: "v1alpha1", Kind: "ExporterList"}
+
+// validateFleet checks that a fleet flash selects its boards in exactly one way.
+func validateFleet(spec *automotivev1alpha1.FlashJobSpec) error {
+	fleet := spec.Fleet
+	if fleet == nil {
+		return nil
+	}
+	if spec.LeaseName != "" {
+		return errors.New("spec.fleet and spec.leaseName are mutually exclusive")
+	}
+	if (fleet.Count > 0) == fleet.AllExporters {
+		return errors.New("spec.fleet requires exactly one of count and allExporters")
+	}
+	if fleet.MaxParallel < 0 {
+		return errors.New("spec.fleet.maxParallel must not be negative")
+	}
+	return nil
+}
+
+// boardTaskRunName returns the name of the TaskRun flashing a fleet board.
+func boardTaskRunName(flashJob *automotivev1alpha1.FlashJob, board int32) string {
+	return fmt.Sprintf("%s-board-%d", flashJob.Name, board)
+}
+
+// handleFleetPending resolves the number of boards of a fleet flash, records
+// them as pending and starts the first batch.
+func (r *Reconciler) handleFleetPending(
+	ctx context.Context,
+	flashJob *automotivev1alpha1.FlashJob,
+	operatorConfig *automotivev1alpha1.OperatorConfig,
+	exporterSelector, flashCmd string,
+) (ctrl.Result, error) {
+	count := flashJob.Spec.Fleet.Count
+	if flashJob.Spec.Fleet.AllExporters {
+		exporters, err := r.countExporters(ctx, exporterSelector)
+		if err != nil {
+			return r.fail(ctx, flashJob, err.Error())
+		}
+		if exporters == 0 {
+			return r.fail(ctx, flashJob, fmt.Sprintf("no online exporters match %s", exporterSelector))
+		}
+		count = exporters
+	}
+
+	flashJob.Status.Boards = make([]automotivev1alpha1.FleetBoardStatus, count)
+	for i := range flashJob.Status.Boards {
+		flashJob.Status.Boards[i] = automotivev1alpha1.FleetBoardStatus{
+			Board: int32(i + 1),
+			Phase: automotivev1alpha1.FlashJobPhasePending,
+		}
+	}
+	if err := r.startBoards(ctx, flashJob, operatorConfig, exporterSelector, flashCmd); err != nil {
+		return r.fail(ctx, flashJob, fmt.Sprintf("Failed to create flash TaskRun: %v", err))
+	}
+
+	now := metav1.Now()
+	flashJob.Status.StartTime = &now
+	setCondition(flashJob, automotivev1alpha1.FlashJobConditionLeaseAcquired, metav1.ConditionUnknown, "LeaseRequested",
+		fmt.Sprintf("Requesting %d leases on exporters matching %s", count, exporterSelector))
+	return r.updateStatus(ctx, flashJob, automotivev1alpha1.FlashJobPhaseRunning, fleetProgressMessage(flashJob.Status.Boards))
+}
+
+// handleFleetRunning updates the boards from their TaskRuns, starts pending
+// boards as running ones finish and aggregates the result once all are done.
+func (r *Reconciler) handleFleetRunning(ctx context.Context, flashJob *automotivev1alpha1.FlashJob) (ctrl.Result, error) {
+	taskRuns := &tektonv1.TaskRunList{}
+	if err := r.List(ctx, taskRuns,
+		client.InNamespace(flashJob.Namespace),
+		client.MatchingLabels{labels.FlashJob: flashJob.Name},
+	); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list flash TaskRuns: %w", err)
+	}
+	byName := make(map[string]*tektonv1.TaskRun, len(taskRuns.Items))
+	for i := range taskRuns.Items {
+		byName[taskRuns.Items[i].Name] = &taskRuns.Items[i]
+	}
+
+	for i := range flashJob.Status.Boards {
+		board := &flashJob.Status.Boards[i]
+		if board.Phase != automotivev1alpha1.FlashJobPhaseRunning {
+			continue
+		}
+		taskRun, ok := byName[board.TaskRunName]
+		if !ok {
+			board.Phase = automotivev1alpha1.FlashJobPhaseFailed
+			board.Message = "flash TaskRun not found"
+			continue
+		}
+		if taskRun.IsDone() {
+			applyBoardResults(board, &flashJob.Spec, taskRun)
+		}
+	}
+
+	operatorConfig, err := r.loadOperatorConfig(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	exporterSelector, flashCmd := resolveTargetConfig(&flashJob.Spec, operatorConfig)
+	if err := r.startBoards(ctx, flashJob, operatorConfig, exporterSelector, flashCmd); err != nil {
+		r.Log.Error(err, "Failed to start fleet boards", "flashjob", flashJob.Name)
+	}
+
+	for _, board := range flashJob.Status.Boards {
+		if !board.IsFinished() {
+			return r.updateStatus(ctx, flashJob, automotivev1alpha1.FlashJobPhaseRunning, fleetProgressMessage(flashJob.Status.Boards))
+		}
+	}
+
+	if err := r.cleanupTransientSecrets(ctx, flashJob); err != nil {
+		r.Log.Error(err, "Failed to delete transient flash secret", "flashjob", flashJob.Name)
+	}
+	now := metav1.Now()
+	flashJob.Status.CompletionTime = &now
+	phase, message := applyFleetResults(flashJob)
+	return r.updateStatus(ctx, flashJob, phase, message)
+}
+
+// startBoards creates TaskRuns for pending boards while fewer than
+// spec.fleet.maxParallel boards are running. A board whose TaskRun cannot be
+// created is marked failed so the remaining boards still get flashed.
+func (r *Reconciler) startBoards(
+	ctx context.Context,
+	flashJob *automotivev1alpha1.FlashJob,
+	operatorConfig *automotivev1alpha1.OperatorConfig,
+	exporterSelector, flashCmd string,
+) error {
+	limit := int(flashJob.Spec.Fleet.MaxParallel)
+	if limit <= 0 {
+		limit = len(flashJob.Status.Boards)
+	}
+	running := 0
+	for _, board := range flashJob.Status.Boards {
+		if board.Phase == automotivev1alpha1.FlashJobPhaseRunning {
+			running++
+		}
+	}
+
+	var errs []error
+	for i := range flashJob.Status.Boards {
+		if running >= limit {
+			break
+		}
+		board := &flashJob.Status.Boards[i]
+		if board.Phase != automotivev1alpha1.FlashJobPhasePending {
+			continue
+		}
+		name := boardTaskRunName(flashJob, board.Board)
+		if _, err := r.createTaskRun(ctx, flashJob, operatorConfig, name, board.Board, exporterSelector, flashCmd); err != nil {
+			board.Phase = automotivev1alpha1.FlashJobPhaseFailed
+			board.Message = fmt.Sprintf("Failed to create flash TaskRun: %v", err)
+			errs = append(errs, err)
+			continue
+		}
+		r.emitEventf(flashJob, corev1.EventTypeNormal, "TaskRunCreated",
+			"Flash TaskRun created for board %d: name=%s exporterSelector=%s", board.Board, name, exporterSelector)
+		board.Phase = automotivev1alpha1.FlashJobPhaseRunning
+		board.TaskRunName = name
+		board.Message = "Flash in progress"
+		running++
+	}
+	if len(errs) == len(flashJob.Status.Boards) {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// applyBoardResults records the lease, exporter, device tests, console
+// transcript and outcome reported by the finished TaskRun of a fleet board.
+func applyBoardResults(board *automotivev1alpha1.FleetBoardStatus, spec *automotivev1alpha1.FlashJobSpec, taskRun *tektonv1.TaskRun) {
+	results := taskRunResults(taskRun)
+	board.LeaseID = results[tasks.FlashResultLeaseID]
+	board.Exporter = results[tasks.FlashResultExporter]
+	board.ConsoleRef = results[tasks.FlashResultConsoleRef]
+	if spec.Test != nil {
+		if deviceTests, err := tasks.ParseDeviceTestSummary(results[tasks.FlashResultTestSummary]); err == nil {
+			board.DeviceTests = deviceTests
+		}
+	}
+
+	switch {
+	case taskRun.IsSuccessful():
+		board.Phase = automotivev1alpha1.FlashJobPhaseCompleted
+		board.Message = "Flashed"
+		if board.DeviceTests != nil {
+			board.Message = "Flashed; device tests passed: " + board.DeviceTests.Message
+		}
+	case board.DeviceTests != nil && !board.DeviceTests.Succeeded():
+		board.Phase = automotivev1alpha1.FlashJobPhaseFailed
+		board.Message = "Device tests failed: " + board.DeviceTests.Message
+	default:
+		board.Phase = automotivev1alpha1.FlashJobPhaseFailed
+		board.Message = taskRunFailureMessage(taskRun)
+	}
+}
+
+// applyFleetResults sets the conditions of a finished fleet flash and returns
+// its phase and message. The flash fails if any board failed.
+func applyFleetResults(flashJob *automotivev1alpha1.FlashJob) (string, string) {
+	boards := flashJob.Status.Boards
+	total := len(boards)
+	var leased, flashed, tested int
+	var failed []string
+	for _, board := range boards {
+		if board.LeaseID != "" {
+			leased++
+		}
+		if board.Phase == automotivev1alpha1.FlashJobPhaseCompleted || board.DeviceTests != nil {
+			flashed++
+		}
+		if board.DeviceTests != nil && board.DeviceTests.Succeeded() {
+			tested++
+		}
+		if board.Phase == automotivev1alpha1.FlashJobPhaseFailed {
+			failed = append(failed, fmt.Sprintf("board-%d (%s)", board.Board, board.Message))
+		}
+	}
+
+	conditionStatus := func(n int) metav1.ConditionStatus {
+		if n == total {
+			return metav1.ConditionTrue
+		}
+		return metav1.ConditionFalse
+	}
+	setCondition(flashJob, automotivev1alpha1.FlashJobConditionLeaseAcquired, conditionStatus(leased),
+		"LeasesAcquired", fmt.Sprintf("%d of %d leases acquired", leased, total))
+	setCondition(flashJob, automotivev1alpha1.FlashJobConditionFlashed, conditionStatus(flashed),
+		"BoardsFlashed", fmt.Sprintf("Flashed %s to %d of %d boards", flashJob.Spec.ImageRef, flashed, total))
+	setCondition(flashJob, automotivev1alpha1.FlashJobConditionLeaseReleased, metav1.ConditionFalse,
+		"LeasesHeld", "Leases of flashed boards are kept for access to the devices until they expire")
+	if flashJob.Spec.Test != nil {
+		setCondition(flashJob, automotivev1alpha1.FlashJobConditionTested, conditionStatus(tested),
+			"BoardsTested", fmt.Sprintf("Device tests passed on %d of %d boards", tested, total))
+	}
+
+	if len(failed) == 0 {
+		return automotivev1alpha1.FlashJobPhaseCompleted, fmt.Sprintf("Flashed all %d boards", total)
+	}
+	if len(failed) > maxFailedBoardsInMessage {
+		failed = append(failed[:maxFailedBoardsInMessage], fmt.Sprintf("and %d more", len(failed)-maxFailedBoardsInMessage))
+	}
+	return automotivev1alpha1.FlashJobPhaseFailed,
+		fmt.Sprintf("%d of %d boards flashed; failed: %s", total-len(failed), total, strings.Join(failed, ", "))
+}
+
+// fleetProgressMessage summarizes the phases of the boards of a running fleet flash.
+func fleetProgressMessage(boards []automotivev1alpha1.FleetBoardStatus) string {
+	counts := map[string]int{}
+	for _, board := range boards {
+		counts[board.Phase]++
+	}
+	return fmt.Sprintf("Flashing %d boards: %d completed, %d failed, %d running, %d pending", len(boards),
+		counts[automotivev1alpha1.FlashJobPhaseCompleted], counts[automotivev1alpha1.FlashJobPhaseFailed],
+		counts[automotivev1alpha1.FlashJobPhaseRunning], counts[automotivev1alpha1.FlashJobPhasePending])
+}
+
+// countExporters returns the number of online Jumpstarter exporters matching
+// the selector. Exporters without an Online condition are counted.
+func (r *Reconciler) countExporters(ctx context.Context, exporterSelector string) (int32, error) {
+	selector, err := k8slabels.Parse(exporterSelector)
+	if err != nil {
+		return 0, fmt.Errorf("invalid exporter selector %q: %w", exporterSelector, err)
+	}
+	exporters := &unstructured.UnstructuredList{}
+	exporters.SetGroupVersionKind(exporterListGVK)
+	if err := r.List(ctx, exporters, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		if apimeta.IsNoMatchError(err) {
+			return 0, errors.New("jumpstarter exporters are not available in this cluster: set spec.fleet.count instead of allExporters")
+		}
+		return 0, fmt.Errorf("failed to list jumpstarter exporters: %w", err)
+	}
+
+	var count int32
+	for _, exporter := range exporters.Items {
+		conditions, _, _ := unstructured.NestedSlice(exporter.Object, "status", "conditions")
+		online := true
+		for _, c := range conditions {
+			cond, ok := c.(map[string]interface{})
+			if ok && cond["type"] == "Online" && cond["status"] != string(metav1.ConditionTrue) {
+				online = false
+			}
+		}
+		if online {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,254 @@
+package flashjob
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/tasks"
+)
+
+func newFleetFlashJob(name string, fleet automotivev1alpha1.FleetFlashSpec) *automotivev1alpha1.FlashJob {
+	fj := newFlashJob(name)
+	fj.Spec.ExporterSelector = "board=j784s4evm"
+	fj.Spec.Fleet = &fleet
+	return fj
+}
+
+func getFlashJob(t *testing.T, c client.Client, name string) *automotivev1alpha1.FlashJob {
+	t.Helper()
+	fj := &automotivev1alpha1.FlashJob{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "builds"}, fj); err != nil {
+		t.Fatal(err)
+	}
+	return fj
+}
+
+func boardTaskRun(name string, board string, succeeded bool, results map[string]string) *tektonv1.TaskRun {
+	tr := doneTaskRun(name, succeeded, results)
+	tr.Labels = map[string]string{labels.FlashJob: "fleet-1", labels.FleetBoard: board}
+	return tr
+}
+
+func newExporter(name, board string, online bool) *unstructured.Unstructured {
+	exporter := &unstructured.Unstructured{}
+	exporter.SetGroupVersionKind(schema.GroupVersionKind{Group: "jumpstarter.dev", Version: "v1alpha1", Kind: "Exporter"})
+	exporter.SetName(name)
+	exporter.SetNamespace("jumpstarter-lab")
+	exporter.SetLabels(map[string]string{"board": board})
+	status := "True"
+	if !online {
+		status = "False"
+	}
+	_ = unstructured.SetNestedSlice(exporter.Object, []interface{}{
+		map[string]interface{}{"type": "Online", "status": status},
+	}, "status", "conditions")
+	return exporter
+}
+
+func TestValidateFleet(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    automotivev1alpha1.FlashJobSpec
+		wantErr string
+	}{
+		{name: "no fleet", spec: automotivev1alpha1.FlashJobSpec{}},
+		{name: "count", spec: automotivev1alpha1.FlashJobSpec{Fleet: &automotivev1alpha1.FleetFlashSpec{Count: 3}}},
+		{name: "all exporters", spec: automotivev1alpha1.FlashJobSpec{Fleet: &automotivev1alpha1.FleetFlashSpec{AllExporters: true, MaxParallel: 4}}},
+		{
+			name:    "neither",
+			spec:    automotivev1alpha1.FlashJobSpec{Fleet: &automotivev1alpha1.FleetFlashSpec{}},
+			wantErr: "exactly one of count and allExporters",
+		},
+		{
+			name:    "both",
+			spec:    automotivev1alpha1.FlashJobSpec{Fleet: &automotivev1alpha1.FleetFlashSpec{Count: 2, AllExporters: true}},
+			wantErr: "exactly one of count and allExporters",
+		},
+		{
+			name:    "existing lease",
+			spec:    automotivev1alpha1.FlashJobSpec{LeaseName: "lease-1", Fleet: &automotivev1alpha1.FleetFlashSpec{Count: 2}},
+			wantErr: "mutually exclusive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFleet(&tt.spec)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReconcile_FleetStartsUpToMaxParallel(t *testing.T) {
+	r, c := newTestReconciler(newFleetFlashJob("fleet-1", automotivev1alpha1.FleetFlashSpec{Count: 3, MaxParallel: 2}))
+
+	reconcileFlashJob(t, r, "fleet-1")
+
+	taskRuns := &tektonv1.TaskRunList{}
+	if err := c.List(context.Background(), taskRuns, client.MatchingLabels{labels.FlashJob: "fleet-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(taskRuns.Items) != 2 {
+		t.Fatalf("expected 2 TaskRuns, got %d", len(taskRuns.Items))
+	}
+	for _, tr := range taskRuns.Items {
+		if !strings.HasPrefix(tr.Name, "fleet-1-board-") || tr.Labels[labels.FleetBoard] == "" {
+			t.Errorf("unexpected board TaskRun %s with labels %v", tr.Name, tr.Labels)
+		}
+	}
+
+	fj := getFlashJob(t, c, "fleet-1")
+	if fj.Status.Phase != automotivev1alpha1.FlashJobPhaseRunning {
+		t.Errorf("phase = %q, want Running", fj.Status.Phase)
+	}
+	if len(fj.Status.Boards) != 3 {
+		t.Fatalf("expected 3 boards, got %+v", fj.Status.Boards)
+	}
+	wantPhases := []string{
+		automotivev1alpha1.FlashJobPhaseRunning,
+		automotivev1alpha1.FlashJobPhaseRunning,
+		automotivev1alpha1.FlashJobPhasePending,
+	}
+	for i, board := range fj.Status.Boards {
+		if board.Board != int32(i+1) || board.Phase != wantPhases[i] {
+			t.Errorf("board %d = %+v, want phase %s", i+1, board, wantPhases[i])
+		}
+	}
+	if fj.Status.Boards[0].TaskRunName != "fleet-1-board-1" {
+		t.Errorf("board 1 TaskRun = %q", fj.Status.Boards[0].TaskRunName)
+	}
+}
+
+func TestReconcile_FleetStartsPendingBoardsAsOthersFinish(t *testing.T) {
+	fj := newFleetFlashJob("fleet-1", automotivev1alpha1.FleetFlashSpec{Count: 2, MaxParallel: 1})
+	fj.Status.Phase = automotivev1alpha1.FlashJobPhaseRunning
+	fj.Status.Boards = []automotivev1alpha1.FleetBoardStatus{
+		{Board: 1, Phase: automotivev1alpha1.FlashJobPhaseRunning, TaskRunName: "fleet-1-board-1"},
+		{Board: 2, Phase: automotivev1alpha1.FlashJobPhasePending},
+	}
+	tr := boardTaskRun("fleet-1-board-1", "1", true, map[string]string{
+		tasks.FlashResultLeaseID:  "lease-1",
+		tasks.FlashResultExporter: "rack-01",
+	})
+	r, c := newTestReconciler(fj, tr)
+
+	reconcileFlashJob(t, r, "fleet-1")
+
+	got := getFlashJob(t, c, "fleet-1")
+	if got.Status.Phase != automotivev1alpha1.FlashJobPhaseRunning {
+		t.Errorf("phase = %q, want Running", got.Status.Phase)
+	}
+	first, second := got.Status.Boards[0], got.Status.Boards[1]
+	if first.Phase != automotivev1alpha1.FlashJobPhaseCompleted || first.LeaseID != "lease-1" || first.Exporter != "rack-01" {
+		t.Errorf("board 1 = %+v", first)
+	}
+	if second.Phase != automotivev1alpha1.FlashJobPhaseRunning || second.TaskRunName != "fleet-1-board-2" {
+		t.Errorf("board 2 = %+v", second)
+	}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "fleet-1-board-2", Namespace: "builds"}, &tektonv1.TaskRun{}); err != nil {
+		t.Errorf("expected TaskRun for board 2: %v", err)
+	}
+}
+
+func TestReconcile_FleetAggregatesBoardResults(t *testing.T) {
+	fj := newFleetFlashJob("fleet-1", automotivev1alpha1.FleetFlashSpec{Count: 3})
+	fj.Spec.TTL = "0"
+	fj.Spec.Test = &automotivev1alpha1.DeviceTestSpec{Script: "true"}
+	fj.Status.Phase = automotivev1alpha1.FlashJobPhaseRunning
+	fj.Status.Boards = []automotivev1alpha1.FleetBoardStatus{
+		{Board: 1, Phase: automotivev1alpha1.FlashJobPhaseRunning, TaskRunName: "fleet-1-board-1"},
+		{Board: 2, Phase: automotivev1alpha1.FlashJobPhaseRunning, TaskRunName: "fleet-1-board-2"},
+		{Board: 3, Phase: automotivev1alpha1.FlashJobPhaseRunning, TaskRunName: "fleet-1-board-3"},
+	}
+	passed := `{"booted":true,"total":1,"passed":1,"message":"1 tests passed"}`
+	r, c := newTestReconciler(fj,
+		boardTaskRun("fleet-1-board-1", "1", true, map[string]string{
+			tasks.FlashResultLeaseID: "lease-1", tasks.FlashResultTestSummary: passed,
+		}),
+		boardTaskRun("fleet-1-board-2", "2", false, map[string]string{
+			tasks.FlashResultLeaseID:     "lease-2",
+			tasks.FlashResultTestSummary: `{"booted":false,"message":"Device did not boot within 600s"}`,
+		}),
+		boardTaskRun("fleet-1-board-3", "3", true, map[string]string{
+			tasks.FlashResultLeaseID: "lease-3", tasks.FlashResultTestSummary: passed,
+		}),
+	)
+
+	reconcileFlashJob(t, r, "fleet-1")
+
+	got := getFlashJob(t, c, "fleet-1")
+	if got.Status.Phase != automotivev1alpha1.FlashJobPhaseFailed {
+		t.Errorf("phase = %q, want Failed", got.Status.Phase)
+	}
+	want := "2 of 3 boards flashed; failed: board-2 (Device tests failed: Device did not boot within 600s)"
+	if got.Status.Message != want {
+		t.Errorf("message = %q, want %q", got.Status.Message, want)
+	}
+	if got.Status.Boards[1].DeviceTests == nil || got.Status.Boards[1].DeviceTests.Booted {
+		t.Errorf("expected board 2 device tests, got %+v", got.Status.Boards[1])
+	}
+	if got.Status.CompletionTime == nil {
+		t.Error("expected CompletionTime to be set")
+	}
+	for condType, want := range map[string]metav1.ConditionStatus{
+		automotivev1alpha1.FlashJobConditionLeaseAcquired: metav1.ConditionTrue,
+		automotivev1alpha1.FlashJobConditionFlashed:       metav1.ConditionTrue,
+		automotivev1alpha1.FlashJobConditionTested:        metav1.ConditionFalse,
+	} {
+		if cond := apimeta.FindStatusCondition(got.Status.Conditions, condType); cond == nil || cond.Status != want {
+			t.Errorf("%s = %v, want %s", condType, cond, want)
+		}
+	}
+}
+
+func TestReconcile_FleetAllExporters(t *testing.T) {
+	r, c := newTestReconciler(
+		newFleetFlashJob("fleet-1", automotivev1alpha1.FleetFlashSpec{AllExporters: true}),
+		newExporter("rack-01", "j784s4evm", true),
+		newExporter("rack-02", "j784s4evm", false),
+		newExporter("rack-03", "j784s4evm", true),
+		newExporter("rack-04", "rcar-s4", true),
+	)
+
+	reconcileFlashJob(t, r, "fleet-1")
+
+	got := getFlashJob(t, c, "fleet-1")
+	if len(got.Status.Boards) != 2 {
+		t.Errorf("expected one board per online matching exporter, got %+v", got.Status.Boards)
+	}
+}
+
+func TestReconcile_FleetAllExportersWithoutJumpstarter(t *testing.T) {
+	r, c := newTestReconciler(newFleetFlashJob("fleet-1", automotivev1alpha1.FleetFlashSpec{AllExporters: true}))
+	r.Client = interceptor.NewClient(c.(client.WithWatch), interceptor.Funcs{
+		List: func(ctx context.Context, cl client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if _, ok := list.(*unstructured.UnstructuredList); ok {
+				return &apimeta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "jumpstarter.dev", Kind: "Exporter"}}
+			}
+			return cl.List(ctx, list, opts...)
+		},
+	})
+
+	reconcileFlashJob(t, r, "fleet-1")
+
+	got := getFlashJob(t, c, "fleet-1")
+	if got.Status.Phase != automotivev1alpha1.FlashJobPhaseFailed || !strings.Contains(got.Status.Message, "set spec.fleet.count") {
+		t.Errorf("status = %s %q, want failure asking for a count", got.Status.Phase, got.Status.Message)
+	}
+}
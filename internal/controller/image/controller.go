@@ -1,14 +1,20 @@
 // Package image provides a Kubernetes controller for managing Image custom resources.
-// It reconciles Image objects by verifying registry accessibility and updating their status.
+// It reconciles Image objects by verifying that their registry, object storage,
+// HTTP or PVC location is accessible and updating their status.
 package image
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/storage"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/catalogimage"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -19,6 +25,9 @@ import (
 const (
 	phaseAvailable   = "Available"
 	phaseUnavailable = "Unavailable"
+
+	// circuitOpenRequeue is how long to wait before retrying a registry whose circuit is open.
+	circuitOpenRequeue = 5 * time.Minute
 )
 
 // ImageReconciler reconciles an Image object
@@ -28,12 +37,26 @@ type ImageReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 	Log    logr.Logger
+	// RegistryClient resolves registry locations. It is normally the circuit-breaker
+	// wrapped client shared with the CatalogImage controller.
+	RegistryClient catalogimage.RegistryClient
+	// StorageBackend opens s3 and http locations. Defaults to storage.NewBackend.
+	StorageBackend storage.Factory
+}
+
+// locationCheck is the outcome of resolving an image location.
+type locationCheck struct {
+	accessible bool
+	message    string
+	digest     string
+	sizeBytes  int64
 }
 
 // +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,namespace=system,resources=images,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,namespace=system,resources=images/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,namespace=system,resources=images/finalizers,verbs=update
 // +kubebuilder:rbac:groups="",namespace=system,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",namespace=system,resources=persistentvolumeclaims,verbs=get;list;watch
 
 // Reconcile Image
 func (r *ImageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -77,8 +100,14 @@ func (r *ImageReconciler) handleVerifyingState(
 	log := r.Log.WithValues("image", types.NamespacedName{Name: image.Name, Namespace: image.Namespace})
 
 	// Verify the image location is accessible
-	accessible, err := r.verifyImageLocation(ctx, image)
+	check, err := r.verifyImageLocation(ctx, image)
 	if err != nil {
+		var cbErr *catalogimage.CircuitBreakerError
+		if errors.As(err, &cbErr) {
+			// The registry is known to be failing; don't judge the image until it recovers.
+			log.Info("Skipping verification while registry circuit is open", "registry", cbErr.Registry)
+			return ctrl.Result{RequeueAfter: circuitOpenRequeue}, nil
+		}
 		log.Error(err, "Failed to verify image location")
 		if err := r.updateStatus(ctx, image, phaseUnavailable, fmt.Sprintf("Verification failed: %v", err)); err != nil {
 			return ctrl.Result{RequeueAfter: time.Second * 10}, nil
@@ -86,14 +115,14 @@ func (r *ImageReconciler) handleVerifyingState(
 		return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
 	}
 
-	if accessible {
-		if err := r.updateStatus(ctx, image, phaseAvailable, "Image location verified and accessible"); err != nil {
+	if check.accessible {
+		if err := r.updateVerification(ctx, image, phaseAvailable, check); err != nil {
 			return ctrl.Result{RequeueAfter: time.Second * 5}, nil
 		}
 		return ctrl.Result{RequeueAfter: time.Hour * 1}, nil // Recheck every hour
 	}
 
-	if err := r.updateStatus(ctx, image, phaseUnavailable, "Image location is not accessible"); err != nil {
+	if err := r.updateVerification(ctx, image, phaseUnavailable, check); err != nil {
 		return ctrl.Result{RequeueAfter: time.Second * 5}, nil
 	}
 	return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
@@ -104,14 +133,27 @@ func (r *ImageReconciler) handleAvailableState(
 	image *automotivev1alpha1.Image,
 ) (ctrl.Result, error) {
 	// Periodically re-verify the image is still accessible
-	accessible, err := r.verifyImageLocation(ctx, image)
-	if err != nil || !accessible {
+	check, err := r.verifyImageLocation(ctx, image)
+	var cbErr *catalogimage.CircuitBreakerError
+	if errors.As(err, &cbErr) {
+		// Keep the last known state while the registry circuit is open.
+		return ctrl.Result{RequeueAfter: circuitOpenRequeue}, nil
+	}
+	if err != nil || !check.accessible {
 		if err := r.updateStatus(ctx, image, "Verifying", "Re-verifying image location"); err != nil {
 			return ctrl.Result{RequeueAfter: time.Second * 5}, nil
 		}
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	// The tag may have moved to new content without the image disappearing.
+	if check.digest != image.Status.Digest || check.sizeBytes != image.Status.SizeBytes {
+		if err := r.updateVerification(ctx, image, phaseAvailable, check); err != nil {
+			r.Log.Error(err, "Failed to update resolved digest")
+		}
+		return ctrl.Result{RequeueAfter: time.Hour * 1}, nil
+	}
+
 	// Already Available — only update LastVerified, and only if stale (>30 min)
 	// to avoid a status-patch → watch-event → re-reconcile amplification loop.
 	if image.Status.LastVerified == nil || time.Since(image.Status.LastVerified.Time) > 30*time.Minute {
@@ -134,37 +176,186 @@ func (r *ImageReconciler) handleUnavailableState(
 	return ctrl.Result{Requeue: true}, nil
 }
 
-func (r *ImageReconciler) verifyImageLocation(ctx context.Context, image *automotivev1alpha1.Image) (bool, error) {
+func (r *ImageReconciler) verifyImageLocation(
+	ctx context.Context,
+	image *automotivev1alpha1.Image,
+) (*locationCheck, error) {
 	switch image.Spec.Location.Type {
-	case "registry":
+	case automotivev1alpha1.LocationTypeRegistry:
 		return r.verifyRegistryLocation(ctx, image)
+	case automotivev1alpha1.LocationTypeS3, automotivev1alpha1.LocationTypeHTTP:
+		return r.verifyStorageLocation(ctx, image)
+	case automotivev1alpha1.LocationTypePVC:
+		return r.verifyPVCLocation(ctx, image)
 	default:
-		return false, fmt.Errorf(
-			"unsupported location type: %s (only 'registry' is currently supported)",
-			image.Spec.Location.Type,
+		return nil, fmt.Errorf("unsupported location type: %s", image.Spec.Location.Type)
+	}
+}
+
+// verifyRegistryLocation resolves the registry manifest and compares it against the
+// expected digest. A missing manifest or a digest mismatch is reported as inaccessible;
+// transport and authentication problems are returned as errors.
+func (r *ImageReconciler) verifyRegistryLocation(
+	ctx context.Context,
+	image *automotivev1alpha1.Image,
+) (*locationCheck, error) {
+	registry := image.Spec.Location.Registry
+	if registry == nil {
+		return nil, fmt.Errorf("registry location configuration is nil")
+	}
+
+	if registry.URL == "" {
+		return nil, fmt.Errorf("registry URL is required")
+	}
+
+	var secretRef *automotivev1alpha1.AuthSecretReference
+	if registry.SecretRef != "" {
+		secretRef = &automotivev1alpha1.AuthSecretReference{Name: registry.SecretRef}
+	}
+	auth, err := catalogimage.GetAuthFromSecret(ctx, r.Client, secretRef, image.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := r.getRegistryClient().GetImageMetadata(ctx, registry.URL, auth)
+	if err != nil {
+		if catalogimage.IsImageNotFound(err) {
+			return &locationCheck{message: "Image not found in registry"}, nil
+		}
+		return nil, err
+	}
+
+	check := &locationCheck{
+		accessible: true,
+		message:    "Image location verified and accessible",
+		digest:     metadata.ResolvedDigest,
+		sizeBytes:  metadata.SizeBytes,
+	}
+	if registry.Digest != "" && metadata.ResolvedDigest != registry.Digest {
+		check.accessible = false
+		check.message = fmt.Sprintf(
+			"Digest mismatch: expected %s, registry has %s", registry.Digest, metadata.ResolvedDigest,
 		)
 	}
+	return check, nil
 }
 
-func (r *ImageReconciler) verifyRegistryLocation(_ context.Context, image *automotivev1alpha1.Image) (bool, error) {
-	if image.Spec.Location.Registry == nil {
-		return false, fmt.Errorf("registry location configuration is nil")
+// verifyStorageLocation stats an s3 or http object and records the sha256 the
+// export task stored alongside it. A missing object is reported as inaccessible;
+// transport and authentication problems are returned as errors.
+func (r *ImageReconciler) verifyStorageLocation(
+	ctx context.Context,
+	image *automotivev1alpha1.Image,
+) (*locationCheck, error) {
+	location := &image.Spec.Location
+	secretName := ""
+	switch {
+	case location.S3 != nil:
+		secretName = location.S3.SecretRef
+	case location.HTTP != nil:
+		secretName = location.HTTP.SecretRef
 	}
 
-	if image.Spec.Location.Registry.URL == "" {
-		return false, fmt.Errorf("registry URL is required")
+	creds := storage.Credentials{}
+	if secretName != "" {
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: image.Namespace}, secret); err != nil {
+			return nil, fmt.Errorf("failed to get storage secret %s: %w", secretName, err)
+		}
+		creds = storage.CredentialsFromSecret(secret)
 	}
 
-	return true, nil // Placeholder - assume accessible for now
+	factory := r.StorageBackend
+	if factory == nil {
+		factory = storage.NewBackend
+	}
+	backend, err := factory(location, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := backend.Stat(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return &locationCheck{message: fmt.Sprintf("Image not found in %s storage", location.Type)}, nil
+		}
+		return nil, err
+	}
+
+	return &locationCheck{
+		accessible: true,
+		message:    "Image location verified and accessible",
+		digest:     info.Digest,
+		sizeBytes:  max(info.SizeBytes, 0),
+	}, nil
+}
+
+// verifyPVCLocation checks that the claim exists and is bound. The file itself is
+// only reachable from a pod mounting the claim and is not inspected here.
+func (r *ImageReconciler) verifyPVCLocation(
+	ctx context.Context,
+	image *automotivev1alpha1.Image,
+) (*locationCheck, error) {
+	location := image.Spec.Location.PVC
+	if location == nil {
+		return nil, fmt.Errorf("pvc location configuration is nil")
+	}
+	if location.ClaimName == "" || location.Path == "" {
+		return nil, fmt.Errorf("pvc claimName and path are required")
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := r.Get(ctx, types.NamespacedName{Name: location.ClaimName, Namespace: image.Namespace}, pvc)
+	if k8serrors.IsNotFound(err) {
+		return &locationCheck{message: fmt.Sprintf("PersistentVolumeClaim %s not found", location.ClaimName)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if pvc.Status.Phase != corev1.ClaimBound {
+		return &locationCheck{
+			message: fmt.Sprintf("PersistentVolumeClaim %s is %s", location.ClaimName, pvc.Status.Phase),
+		}, nil
+	}
+	return &locationCheck{accessible: true, message: "PersistentVolumeClaim is bound"}, nil
+}
+
+// getRegistryClient returns the registry client (allows for testing)
+func (r *ImageReconciler) getRegistryClient() catalogimage.RegistryClient {
+	if r.RegistryClient != nil {
+		return r.RegistryClient
+	}
+	return catalogimage.NewRegistryClient()
 }
 
 func (r *ImageReconciler) updateStatus(
 	ctx context.Context,
 	image *automotivev1alpha1.Image,
 	phase, message string,
+) error {
+	return r.patchStatus(ctx, image, phase, message, nil)
+}
+
+// updateVerification records the outcome of a registry check, including the resolved
+// digest and size, in the same patch as the phase transition.
+func (r *ImageReconciler) updateVerification(
+	ctx context.Context,
+	image *automotivev1alpha1.Image,
+	phase string,
+	check *locationCheck,
+) error {
+	return r.patchStatus(ctx, image, phase, check.message, check)
+}
+
+func (r *ImageReconciler) patchStatus(
+	ctx context.Context,
+	image *automotivev1alpha1.Image,
+	phase, message string,
+	check *locationCheck,
 ) error {
 	// Skip the patch when nothing actually changed
-	if image.Status.Phase == phase && image.Status.Message == message {
+	if image.Status.Phase == phase && image.Status.Message == message &&
+		(check == nil || (image.Status.Digest == check.digest && image.Status.SizeBytes == check.sizeBytes)) {
 		return nil
 	}
 
@@ -180,6 +371,10 @@ func (r *ImageReconciler) updateStatus(
 
 	fresh.Status.Phase = phase
 	fresh.Status.Message = message
+	if check != nil {
+		fresh.Status.Digest = check.digest
+		fresh.Status.SizeBytes = check.sizeBytes
+	}
 
 	// When transitioning to Available, stamp LastVerified in the same patch
 	// so we don't need a second GET+Patch round-trip.
@@ -0,0 +1,296 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/storage"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/catalogimage"
+	"github.com/containers/image/v5/types"
+	"github.com/docker/distribution/registry/api/errcode"
+	v2 "github.com/docker/distribution/registry/api/v2"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNamespace = "test-ns"
+	testDigest    = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	otherDigest   = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+type fakeRegistryClient struct {
+	metadata *automotivev1alpha1.RegistryMetadata
+	err      error
+	lastURL  string
+	lastAuth *types.DockerAuthConfig
+}
+
+func (f *fakeRegistryClient) VerifyImageAccessible(
+	_ context.Context, _ string, _ *types.DockerAuthConfig,
+) (bool, error) {
+	return f.err == nil, f.err
+}
+
+func (f *fakeRegistryClient) GetImageMetadata(
+	_ context.Context, registryURL string, auth *types.DockerAuthConfig,
+) (*automotivev1alpha1.RegistryMetadata, error) {
+	f.lastURL = registryURL
+	f.lastAuth = auth
+	return f.metadata, f.err
+}
+
+func (f *fakeRegistryClient) VerifyDigest(
+	_ context.Context, _ string, _ string, _ *types.DockerAuthConfig,
+) (bool, string, error) {
+	return false, "", errors.New("not implemented")
+}
+
+func newTestImage(location automotivev1alpha1.RegistryLocation, phase string) *automotivev1alpha1.Image {
+	return &automotivev1alpha1.Image{
+		ObjectMeta: metav1.ObjectMeta{Name: "img", Namespace: testNamespace},
+		Spec: automotivev1alpha1.ImageSpec{
+			Location: automotivev1alpha1.ImageLocation{Type: "registry", Registry: &location},
+		},
+		Status: automotivev1alpha1.ImageStatus{Phase: phase},
+	}
+}
+
+func newTestReconciler(t *testing.T, registry catalogimage.RegistryClient, objs ...client.Object) *ImageReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := automotivev1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&automotivev1alpha1.Image{}).
+		Build()
+	return &ImageReconciler{Client: c, Scheme: scheme, Log: logr.Discard(), RegistryClient: registry}
+}
+
+func reconcileImage(t *testing.T, r *ImageReconciler) (ctrl.Result, *automotivev1alpha1.Image) {
+	t.Helper()
+	key := k8stypes.NamespacedName{Name: "img", Namespace: testNamespace}
+	res, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	got := &automotivev1alpha1.Image{}
+	if err := r.Get(context.Background(), key, got); err != nil {
+		t.Fatal(err)
+	}
+	return res, got
+}
+
+func TestVerifyingPopulatesDigestAndSize(t *testing.T) {
+	registry := &fakeRegistryClient{
+		metadata: &automotivev1alpha1.RegistryMetadata{ResolvedDigest: testDigest, SizeBytes: 4096},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: testNamespace},
+		Data:       map[string][]byte{"username": []byte("u"), "password": []byte("p")},
+	}
+	img := newTestImage(automotivev1alpha1.RegistryLocation{
+		URL:       "quay.io/org/img:v1",
+		Digest:    testDigest,
+		SecretRef: "creds",
+	}, "Verifying")
+
+	r := newTestReconciler(t, registry, img, secret)
+	res, got := reconcileImage(t, r)
+
+	if got.Status.Phase != phaseAvailable {
+		t.Fatalf("phase = %q, want %q (message %q)", got.Status.Phase, phaseAvailable, got.Status.Message)
+	}
+	if got.Status.Digest != testDigest || got.Status.SizeBytes != 4096 {
+		t.Errorf("status digest/size = %q/%d, want %q/4096", got.Status.Digest, got.Status.SizeBytes, testDigest)
+	}
+	if got.Status.LastVerified == nil {
+		t.Error("expected LastVerified to be set")
+	}
+	if registry.lastAuth == nil || registry.lastAuth.Username != "u" {
+		t.Errorf("expected credentials from SecretRef, got %+v", registry.lastAuth)
+	}
+	if res.RequeueAfter != time.Hour {
+		t.Errorf("RequeueAfter = %v, want periodic recheck", res.RequeueAfter)
+	}
+}
+
+func TestVerifyingDigestMismatchIsUnavailable(t *testing.T) {
+	registry := &fakeRegistryClient{
+		metadata: &automotivev1alpha1.RegistryMetadata{ResolvedDigest: otherDigest, SizeBytes: 10},
+	}
+	img := newTestImage(automotivev1alpha1.RegistryLocation{URL: "quay.io/org/img:v1", Digest: testDigest}, "Verifying")
+
+	_, got := reconcileImage(t, newTestReconciler(t, registry, img))
+
+	if got.Status.Phase != phaseUnavailable {
+		t.Fatalf("phase = %q, want %q", got.Status.Phase, phaseUnavailable)
+	}
+	if got.Status.Digest != otherDigest {
+		t.Errorf("status digest = %q, want the digest the registry reported", got.Status.Digest)
+	}
+}
+
+func TestVerifyingNotFoundIsUnavailable(t *testing.T) {
+	registry := &fakeRegistryClient{
+		err: errcode.Errors{v2.ErrorCodeManifestUnknown.WithMessage("manifest unknown")},
+	}
+	img := newTestImage(automotivev1alpha1.RegistryLocation{URL: "quay.io/org/gone:v1"}, "Verifying")
+
+	_, got := reconcileImage(t, newTestReconciler(t, registry, img))
+
+	if got.Status.Phase != phaseUnavailable || got.Status.Message != "Image not found in registry" {
+		t.Fatalf("got phase %q message %q, want Unavailable/not found", got.Status.Phase, got.Status.Message)
+	}
+}
+
+func TestOpenCircuitKeepsPhase(t *testing.T) {
+	breakers := catalogimage.NewCircuitBreakerRegistry(catalogimage.CircuitBreakerConfig{
+		FailureThreshold:   1,
+		RecoveryTimeout:    time.Hour,
+		HalfOpenMaxRetries: 1,
+	}, logr.Discard())
+	breakers.RecordFailure("quay.io/org/img:v1")
+	registry := catalogimage.NewCircuitBreakerRegistryClient(&fakeRegistryClient{}, breakers)
+
+	img := newTestImage(automotivev1alpha1.RegistryLocation{URL: "quay.io/org/img:v1"}, phaseAvailable)
+
+	res, got := reconcileImage(t, newTestReconciler(t, registry, img))
+
+	if got.Status.Phase != phaseAvailable {
+		t.Errorf("phase = %q, want Available to be kept while the circuit is open", got.Status.Phase)
+	}
+	if res.RequeueAfter != circuitOpenRequeue {
+		t.Errorf("RequeueAfter = %v, want %v", res.RequeueAfter, circuitOpenRequeue)
+	}
+}
+
+func TestAvailableRecordsMovedTag(t *testing.T) {
+	registry := &fakeRegistryClient{
+		metadata: &automotivev1alpha1.RegistryMetadata{ResolvedDigest: otherDigest, SizeBytes: 20},
+	}
+	img := newTestImage(automotivev1alpha1.RegistryLocation{URL: "quay.io/org/img:latest"}, phaseAvailable)
+	img.Status.Digest = testDigest
+	img.Status.Message = "Image location verified and accessible"
+
+	_, got := reconcileImage(t, newTestReconciler(t, registry, img))
+
+	if got.Status.Phase != phaseAvailable || got.Status.Digest != otherDigest || got.Status.SizeBytes != 20 {
+		t.Errorf("got phase %q digest %q size %d, want Available with the new digest",
+			got.Status.Phase, got.Status.Digest, got.Status.SizeBytes)
+	}
+}
+
+type fakeBackend struct {
+	info *storage.ObjectInfo
+	err  error
+}
+
+func (f *fakeBackend) Stat(_ context.Context) (*storage.ObjectInfo, error) {
+	return f.info, f.err
+}
+
+func (f *fakeBackend) Open(_ context.Context) (io.ReadCloser, *storage.ObjectInfo, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
+func newStorageImage(location automotivev1alpha1.ImageLocation) *automotivev1alpha1.Image {
+	return &automotivev1alpha1.Image{
+		ObjectMeta: metav1.ObjectMeta{Name: "img", Namespace: testNamespace},
+		Spec:       automotivev1alpha1.ImageSpec{Location: location},
+		Status:     automotivev1alpha1.ImageStatus{Phase: "Verifying"},
+	}
+}
+
+func TestVerifyingS3LocationUsesSecretCredentials(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "minio", Namespace: testNamespace},
+		Data: map[string][]byte{
+			storage.SecretKeyAccessKeyID:     []byte("minio"),
+			storage.SecretKeySecretAccessKey: []byte("minio123"),
+		},
+	}
+	img := newStorageImage(automotivev1alpha1.ImageLocation{
+		Type: automotivev1alpha1.LocationTypeS3,
+		S3: &automotivev1alpha1.S3Location{
+			Endpoint: "http://minio:9000", Bucket: "images", Key: "disk.qcow2", SecretRef: "minio",
+		},
+	})
+
+	var gotCreds storage.Credentials
+	r := newTestReconciler(t, nil, img, secret)
+	r.StorageBackend = func(_ *automotivev1alpha1.ImageLocation, creds storage.Credentials) (storage.Backend, error) {
+		gotCreds = creds
+		return &fakeBackend{info: &storage.ObjectInfo{SizeBytes: 2048, Digest: testDigest}}, nil
+	}
+	_, got := reconcileImage(t, r)
+
+	if got.Status.Phase != phaseAvailable || got.Status.Digest != testDigest || got.Status.SizeBytes != 2048 {
+		t.Errorf("got phase %q digest %q size %d, want Available with object metadata",
+			got.Status.Phase, got.Status.Digest, got.Status.SizeBytes)
+	}
+	if gotCreds.AccessKeyID != "minio" || gotCreds.SecretAccessKey != "minio123" {
+		t.Errorf("expected credentials from SecretRef, got %+v", gotCreds)
+	}
+}
+
+func TestVerifyingMissingHTTPObjectIsUnavailable(t *testing.T) {
+	img := newStorageImage(automotivev1alpha1.ImageLocation{
+		Type: automotivev1alpha1.LocationTypeHTTP,
+		HTTP: &automotivev1alpha1.HTTPLocation{URL: "https://files.example.com/disk.raw"},
+	})
+
+	r := newTestReconciler(t, nil, img)
+	r.StorageBackend = func(*automotivev1alpha1.ImageLocation, storage.Credentials) (storage.Backend, error) {
+		return &fakeBackend{err: storage.ErrNotFound}, nil
+	}
+	_, got := reconcileImage(t, r)
+
+	if got.Status.Phase != phaseUnavailable || got.Status.Message != "Image not found in http storage" {
+		t.Errorf("got phase %q message %q, want Unavailable/not found", got.Status.Phase, got.Status.Message)
+	}
+}
+
+func TestVerifyingPVCLocation(t *testing.T) {
+	location := automotivev1alpha1.ImageLocation{
+		Type: automotivev1alpha1.LocationTypePVC,
+		PVC:  &automotivev1alpha1.PVCLocation{ClaimName: "images", Path: "builds/disk.raw"},
+	}
+	claim := func(phase corev1.PersistentVolumeClaimPhase) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "images", Namespace: testNamespace},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: phase},
+		}
+	}
+
+	_, got := reconcileImage(t, newTestReconciler(t, nil, newStorageImage(location), claim(corev1.ClaimBound)))
+	if got.Status.Phase != phaseAvailable {
+		t.Errorf("bound claim: phase = %q, want %q", got.Status.Phase, phaseAvailable)
+	}
+
+	_, got = reconcileImage(t, newTestReconciler(t, nil, newStorageImage(location), claim(corev1.ClaimPending)))
+	if got.Status.Phase != phaseUnavailable {
+		t.Errorf("pending claim: phase = %q, want %q", got.Status.Phase, phaseUnavailable)
+	}
+
+	_, got = reconcileImage(t, newTestReconciler(t, nil, newStorageImage(location)))
+	if got.Status.Phase != phaseUnavailable {
+		t.Errorf("missing claim: phase = %q, want %q", got.Status.Phase, phaseUnavailable)
+	}
+}
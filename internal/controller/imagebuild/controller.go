@@ -14,6 +14,7 @@ import (
 
 	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/bundleverify"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/notifications"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/registryutil"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/tasks"
 	controllerutils "github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/controllerutils"
@@ -178,6 +179,7 @@ type ImageBuildReconciler struct {
 	Log        logr.Logger
 	Recorder   record.EventRecorder
 	RestConfig *rest.Config
+	Notifier   *notifications.Notifier
 }
 
 // +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,namespace=system,resources=workspaces,verbs=get;update
@@ -241,6 +243,8 @@ func (r *ImageBuildReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		phaseResult, phaseErr = r.handleInitialState(ctx, imageBuild)
 	case "Uploading":
 		phaseResult, phaseErr = r.handleUploadingState(ctx, imageBuild)
+	case phaseQueued:
+		phaseResult, phaseErr = r.handleQueuedState(ctx, imageBuild)
 	case phaseBuilding:
 		phaseResult, phaseErr = r.handleBuildingState(ctx, imageBuild)
 	case "Pushing":
@@ -389,8 +393,8 @@ func (r *ImageBuildReconciler) handleInitialState(
 		return ctrl.Result{Requeue: true}, nil
 	}
 
-	if err := r.updateStatus(ctx, imageBuild, phaseBuilding, "Build started"); err != nil {
-		log.Error(err, "Failed to update status to Building")
+	if err := r.updateStatus(ctx, imageBuild, phaseQueued, "Waiting in build queue"); err != nil {
+		log.Error(err, "Failed to update status to Queued")
 		return ctrl.Result{}, err
 	}
 	return ctrl.Result{Requeue: true}, nil
@@ -442,8 +446,8 @@ func (r *ImageBuildReconciler) handleUploadingState(
 		return ctrl.Result{}, fmt.Errorf("failed to shutdown upload server: %w", err)
 	}
 
-	if err := r.updateStatus(ctx, imageBuild, phaseBuilding, "Build started"); err != nil {
-		log.Error(err, "Failed to update status to Building")
+	if err := r.updateStatus(ctx, imageBuild, phaseQueued, "Waiting in build queue"); err != nil {
+		log.Error(err, "Failed to update status to Queued")
 		return ctrl.Result{}, err
 	}
 	return ctrl.Result{Requeue: true}, nil
@@ -457,10 +461,18 @@ func (r *ImageBuildReconciler) handleBuildingState(
 	defer controllerutils.EndSpanWithError(span, &err)
 	log := r.buildLogger(imageBuild)
 
+	if imageBuild.Spec.IsMultiArch() {
+		return r.handleMultiArchBuildingState(ctx, imageBuild)
+	}
+
 	if imageBuild.Status.PipelineRunName != "" {
 		return r.checkBuildProgress(ctx, imageBuild)
 	}
 
+	if wait := retryDelay(imageBuild); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
 	// Look for existing PipelineRuns for this ImageBuild
 	pipelineRunList := &tektonv1.PipelineRunList{}
 	if err := r.List(ctx, pipelineRunList,
@@ -472,7 +484,7 @@ func (r *ImageBuildReconciler) handleBuildingState(
 	}
 
 	for _, pr := range pipelineRunList.Items {
-		if pr.DeletionTimestamp == nil {
+		if pr.DeletionTimestamp == nil && !isFailedAttempt(imageBuild, pr.Name) {
 			log.Info("Found existing PipelineRun for this ImageBuild", "pipelineRun", pr.Name)
 
 			latestImageBuild := &automotivev1alpha1.ImageBuild{}
@@ -680,6 +692,28 @@ func (r *ImageBuildReconciler) handleExpiredState(
 	if name := imageBuild.Status.FlashTaskRunName; name != "" {
 		deleteObj(&tektonv1.TaskRun{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}}, "flash TaskRun")
 	}
+	for _, archStatus := range imageBuild.Status.Architectures {
+		if archStatus.PipelineRunName != "" {
+			deleteObj(&tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: archStatus.PipelineRunName, Namespace: ns}},
+				archStatus.Architecture+" PipelineRun")
+		}
+	}
+	if name := imageBuild.Status.ImageIndexTaskRunName; name != "" {
+		deleteObj(&tektonv1.TaskRun{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}}, "image index TaskRun")
+	}
+	// Failed attempts keep their runs, and the PVCs Tekton claimed for them,
+	// until expiry.
+	deleted := map[string]bool{imageBuild.Status.PipelineRunName: true}
+	for _, attempt := range imageBuild.Status.Attempts {
+		if name := attempt.PipelineRunName; name != "" && !deleted[name] {
+			deleted[name] = true
+			deleteObj(&tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}}, "failed attempt PipelineRun")
+		}
+		if attempt.Stage == automotivev1alpha1.BuildStagePush && attempt.TaskRunName != "" {
+			deleteObj(&tektonv1.TaskRun{ObjectMeta: metav1.ObjectMeta{Name: attempt.TaskRunName, Namespace: ns}},
+				"failed attempt push TaskRun")
+		}
+	}
 	if name := imageBuild.Status.PVCName; name != "" {
 		if name == imageBuild.Spec.BuildCachePVC {
 			log.Info("Skipping PVC deletion: shared build-cache PVC", "pvc", name)
@@ -785,11 +819,17 @@ func (r *ImageBuildReconciler) checkBuildProgress(
 		if fresh.Spec.IsFlashEnabled() {
 			fresh.Status.LeaseID = extractLeaseID(pipelineRun)
 		}
+		if fresh.Spec.GetFlashTest() != nil {
+			fresh.Status.DeviceTests = r.pipelineRunDeviceTests(ctx, pipelineRun)
+		}
 
 		// Pipeline includes push-disk-artifact and flash-image tasks (when enabled)
 		// Pipeline completion means everything succeeded
+		oldPhase := fresh.Status.Phase
 		fresh.Status.Phase = phaseCompleted
-		if fresh.Spec.IsFlashEnabled() {
+		if fresh.Status.DeviceTests != nil {
+			fresh.Status.Message = "Build and flash completed successfully; " + deviceTestMessage(fresh.Status.DeviceTests)
+		} else if fresh.Spec.IsFlashEnabled() {
 			fresh.Status.Message = "Build and flash completed successfully"
 		} else {
 			fresh.Status.Message = "Build completed successfully"
@@ -804,6 +844,7 @@ func (r *ImageBuildReconciler) checkBuildProgress(
 			return ctrl.Result{}, err
 		}
 		adjustActiveBuildsGauge(phaseBuilding, phaseCompleted)
+		r.notifyPhaseChange(ctx, fresh, oldPhase)
 		recordBuildMetrics(fresh, pipelineRun, buildStatusSuccess)
 		if fresh.Spec.IsFlashEnabled() {
 			r.recordPipelineFlashMetrics(ctx, fresh, pipelineRun, buildStatusSuccess)
@@ -835,6 +876,13 @@ func (r *ImageBuildReconciler) checkBuildProgress(
 		return ctrl.Result{}, nil
 	}
 
+	if pipelineRun.Spec.Status != tektonv1.PipelineRunSpecStatusCancelled {
+		retryResult, retried, err := r.retryFailedPipelineRun(ctx, imageBuild, pipelineRun)
+		if err != nil || retried {
+			return retryResult, err
+		}
+	}
+
 	cleanupErr := r.cleanupTransientSecrets(ctx, imageBuild, r.Log)
 
 	if pipelineRun.Spec.Status == tektonv1.PipelineRunSpecStatusCancelled {
@@ -858,7 +906,19 @@ func (r *ImageBuildReconciler) checkBuildProgress(
 		return ctrl.Result{}, nil
 	}
 
-	if err := r.updateStatus(ctx, imageBuild, phaseFailed, r.pipelineRunFailureDetail(ctx, pipelineRun)); err != nil {
+	failureDetail := r.pipelineRunFailureDetail(ctx, pipelineRun)
+	if imageBuild.Spec.GetFlashTest() != nil {
+		if deviceTests := r.pipelineRunDeviceTests(ctx, pipelineRun); deviceTests != nil {
+			if err := r.recordDeviceTests(ctx, imageBuild, deviceTests); err != nil {
+				log.Error(err, "Failed to record device test results")
+				return ctrl.Result{}, err
+			}
+			if !deviceTests.Succeeded() {
+				failureDetail = deviceTestMessage(deviceTests)
+			}
+		}
+	}
+	if err := r.updateStatus(ctx, imageBuild, phaseFailed, failureDetail); err != nil {
 		log.Error(err, "Failed to update status to Failed")
 		return ctrl.Result{}, err
 	}
@@ -881,10 +941,10 @@ func (r *ImageBuildReconciler) startNewBuild(
 
 	// PVC is now created via VolumeClaimTemplate in createBuildTaskRun
 	// to ensure proper zone affinity with WaitForFirstConsumer
-	if err := r.createBuildTaskRun(ctx, imageBuild); err != nil {
+	if _, err := r.createBuildTaskRun(ctx, imageBuild, imageBuild.Spec.Architecture); err != nil {
 		// secureBuild validation errors are terminal — set Failed status
 		// instead of returning a reconcile error that causes infinite requeue
-		if strings.Contains(err.Error(), "secureBuild") {
+		if isBuildConfigError(err) {
 			msg := fmt.Sprintf("Build configuration error: %v", err)
 			if statusErr := r.updateStatus(ctx, imageBuild, phaseFailed, msg); statusErr != nil {
 				return ctrl.Result{}, statusErr
@@ -897,24 +957,35 @@ func (r *ImageBuildReconciler) startNewBuild(
 	return ctrl.Result{RequeueAfter: time.Second * 30}, nil
 }
 
+// isBuildConfigError reports whether a createBuildTaskRun error is a terminal
+// configuration problem rather than a transient failure.
+func isBuildConfigError(err error) bool {
+	return strings.Contains(err.Error(), "secureBuild")
+}
+
+// createBuildTaskRun creates the build PipelineRun for one architecture. For
+// single-architecture builds the PipelineRun name is also recorded in status;
+// multi-architecture builds track their PipelineRuns in Status.Architectures.
+//
 //nolint:gocyclo // Complex PipelineRun builder with many optional fields based on build configuration
 func (r *ImageBuildReconciler) createBuildTaskRun(
 	ctx context.Context,
 	imageBuild *automotivev1alpha1.ImageBuild,
-) error {
-	log := r.buildLogger(imageBuild)
+	arch string,
+) (*tektonv1.PipelineRun, error) {
+	log := r.buildLogger(imageBuild).WithValues("architecture", arch)
 	log.Info("Creating PipelineRun for ImageBuild")
 
 	// Fetch OperatorConfig from the operator namespace to get build configuration
 	operatorConfig := &automotivev1alpha1.OperatorConfig{}
 	err := r.Get(ctx, types.NamespacedName{Name: "config", Namespace: controllerutils.OperatorNamespace()}, operatorConfig)
 	if err != nil && !errors.IsNotFound(err) {
-		return fmt.Errorf("failed to get OperatorConfig configuration: %w", err)
+		return nil, fmt.Errorf("failed to get OperatorConfig configuration: %w", err)
 	}
 
 	// Fail closed: secureBuild must not silently fall back to the cluster pipeline
 	if imageBuild.Spec.SecureBuild && (err != nil || operatorConfig.Spec.OSBuilds == nil) {
-		return fmt.Errorf("secureBuild requested but OperatorConfig or spec.osBuilds is not available")
+		return nil, fmt.Errorf("secureBuild requested but OperatorConfig or spec.osBuilds is not available")
 	}
 
 	var buildConfig *tasks.BuildConfig
@@ -938,19 +1009,19 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 			// not the current OperatorConfig value (which may have changed).
 			ref := strings.TrimSpace(imageBuild.Spec.TaskBundleRef)
 			if ref == "" {
-				return fmt.Errorf("secureBuild requested but taskBundleRef is not set on the ImageBuild")
+				return nil, fmt.Errorf("secureBuild requested but taskBundleRef is not set on the ImageBuild")
 			}
 			if !digestPinnedRef.MatchString(ref) {
-				return fmt.Errorf("secureBuild requires a digest-pinned taskBundleRef (must match image@sha256:<64 hex>), got %q", ref)
+				return nil, fmt.Errorf("secureBuild requires a digest-pinned taskBundleRef (must match image@sha256:<64 hex>), got %q", ref)
 			}
 
 			if operatorConfig.Spec.OSBuilds.TaskBundleVerify {
 				pubKeyPEM, err := bundleverify.FetchCosignPublicKey(ctx, r.Client, operatorConfig.Spec.OSBuilds.TaskBundleCosignKeyRef, controllerutils.OperatorNamespace())
 				if err != nil {
-					return fmt.Errorf("secureBuild: cosign key is unavailable: %w", err)
+					return nil, fmt.Errorf("secureBuild: cosign key is unavailable: %w", err)
 				}
 				if err := bundleverify.VerifyBundle(ctx, ref, pubKeyPEM); err != nil {
-					return fmt.Errorf("task bundle signature verification failed: %w", err)
+					return nil, fmt.Errorf("task bundle signature verification failed: %w", err)
 				}
 			}
 
@@ -960,11 +1031,11 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 			// Bundle tasks are exported with nil BuildConfig (defaults only).
 			// Reject settings that would silently diverge from the bundle.
 			if buildConfig.TrustedCABundleName != "" && buildConfig.TrustedCABundleName != tasks.DefaultTrustedCABundleConfigMap {
-				return fmt.Errorf("secureBuild: OperatorConfig specifies custom CA bundle %q but bundle tasks use default %q; build a custom bundle or remove the CA override",
+				return nil, fmt.Errorf("secureBuild: OperatorConfig specifies custom CA bundle %q but bundle tasks use default %q; build a custom bundle or remove the CA override",
 					buildConfig.TrustedCABundleName, tasks.DefaultTrustedCABundleConfigMap)
 			}
 			if buildConfig.TrustedCABundleKind != "" && !strings.EqualFold(buildConfig.TrustedCABundleKind, "ConfigMap") {
-				return fmt.Errorf("secureBuild: OperatorConfig specifies CA bundle kind %q but bundle tasks use ConfigMap; build a custom bundle or remove the CA override",
+				return nil, fmt.Errorf("secureBuild: OperatorConfig specifies CA bundle kind %q but bundle tasks use ConfigMap; build a custom bundle or remove the CA override",
 					buildConfig.TrustedCABundleKind)
 			}
 			if buildConfig.UseMemoryVolumes {
@@ -985,7 +1056,7 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 			Name: "arch",
 			Value: tektonv1.ParamValue{
 				Type:      tektonv1.ParamTypeString,
-				StringVal: imageBuild.Spec.Architecture,
+				StringVal: arch,
 			},
 		},
 		{
@@ -1034,7 +1105,7 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 			Name: "container-push",
 			Value: tektonv1.ParamValue{
 				Type:      tektonv1.ParamTypeString,
-				StringVal: imageBuild.Spec.GetContainerPush(),
+				StringVal: imageBuild.Spec.GetArchContainerPush(arch),
 			},
 		},
 		{
@@ -1048,7 +1119,7 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 			Name: "export-oci",
 			Value: tektonv1.ParamValue{
 				Type:      tektonv1.ParamTypeString,
-				StringVal: imageBuild.Spec.GetExportOCI(),
+				StringVal: imageBuild.Spec.GetArchExportOCI(arch),
 			},
 		},
 		{
@@ -1176,6 +1247,13 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 		})
 	}
 
+	// Add s3/http/pvc disk export params
+	params = append(params, diskStorageExportParams(imageBuild.Spec.GetDiskStorageExport())...)
+
+	// Sign pushed artifacts with the build's or the OperatorConfig's cosign key
+	signingKey := signingKeySecretRef(imageBuild, operatorConfig)
+	params = append(params, signingParams(signingKey)...)
+
 	// Add flash params if flash is enabled
 	var flashExporterSelector, flashCmd, flashOCIAuthSecretName string
 	if imageBuild.Spec.IsFlashEnabled() {
@@ -1191,7 +1269,7 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 			}
 		}
 		if flashExporterSelector == "" {
-			return fmt.Errorf("flash enabled but no Jumpstarter target mapping found for target %q; "+
+			return nil, fmt.Errorf("flash enabled but no Jumpstarter target mapping found for target %q; "+
 				"configure OperatorConfig.spec.jumpstarter.targetMappings[%q] with selector and flashCmd, "+
 				"or set flash.exporterSelector directly", target, target)
 		}
@@ -1202,7 +1280,7 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 		// Internal registry references are cluster-internal and not reachable by the flash exporter.
 		// Require an external route and fail fast if unavailable.
 		if imageBuild.Spec.GetUseServiceAccountAuth() && clusterRegistryRoute == "" {
-			return fmt.Errorf(
+			return nil, fmt.Errorf(
 				"flash with internal registry requires an external registry route; " +
 					"set OperatorConfig.spec.osBuilds.clusterRegistryRoute or expose openshift-image-registry/default-route",
 			)
@@ -1217,11 +1295,11 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 				clusterRegistryRoute, 1)
 			// Create a Secret with SA token credentials for the flash exporter
 			if r.RestConfig == nil {
-				return fmt.Errorf("RestConfig is nil, cannot create flash OCI credentials")
+				return nil, fmt.Errorf("RestConfig is nil, cannot create flash OCI credentials")
 			}
 			clientset, err := kuberneteslib.NewForConfig(r.RestConfig)
 			if err != nil {
-				return fmt.Errorf("failed to create clientset for flash OCI credentials: %w", err)
+				return nil, fmt.Errorf("failed to create clientset for flash OCI credentials: %w", err)
 			}
 			expSeconds := int64(4 * 3600)
 			tokenReq := &authnv1.TokenRequest{
@@ -1232,7 +1310,7 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 			tokenResp, err := clientset.CoreV1().ServiceAccounts(imageBuild.Namespace).
 				CreateToken(ctx, automotivev1alpha1.BuildServiceAccountName, tokenReq, metav1.CreateOptions{})
 			if err != nil {
-				return fmt.Errorf("failed to create SA token for flash OCI credentials: %w", err)
+				return nil, fmt.Errorf("failed to create SA token for flash OCI credentials: %w", err)
 			}
 			flashOCIAuthSecretName = imageBuild.Name + "-flash-oci-auth"
 			ociSecret := &corev1.Secret{
@@ -1260,13 +1338,13 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 			if errors.IsAlreadyExists(err) {
 				existing, getErr := clientset.CoreV1().Secrets(imageBuild.Namespace).Get(ctx, ociSecret.Name, metav1.GetOptions{})
 				if getErr != nil {
-					return fmt.Errorf("failed to get existing flash OCI auth secret: %w", getErr)
+					return nil, fmt.Errorf("failed to get existing flash OCI auth secret: %w", getErr)
 				}
 				existing.Data = ociSecret.Data
 				_, err = clientset.CoreV1().Secrets(imageBuild.Namespace).Update(ctx, existing, metav1.UpdateOptions{})
 			}
 			if err != nil {
-				return fmt.Errorf("failed to create/update flash OCI auth secret: %w", err)
+				return nil, fmt.Errorf("failed to create/update flash OCI auth secret: %w", err)
 			}
 		} else if imageBuild.Spec.SecretRef != "" && flashImageRef != "" {
 			// External registry: read credentials from the registry-auth secret and
@@ -1277,7 +1355,7 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 				Namespace: imageBuild.Namespace,
 				Name:      imageBuild.Spec.SecretRef,
 			}, registrySecret); err != nil {
-				return fmt.Errorf("failed to read registry secret %q for flash OCI credentials: %w", imageBuild.Spec.SecretRef, err)
+				return nil, fmt.Errorf("failed to read registry secret %q for flash OCI credentials: %w", imageBuild.Spec.SecretRef, err)
 			}
 			regUser, regPass := extractFlashCredentials(registrySecret, flashImageRef, log)
 			if len(regUser) == 0 && len(regPass) == 0 {
@@ -1310,14 +1388,14 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 					if errors.IsAlreadyExists(err) {
 						existing := &corev1.Secret{}
 						if err := r.Get(ctx, client.ObjectKey{Namespace: imageBuild.Namespace, Name: flashOCIAuthSecretName}, existing); err != nil {
-							return fmt.Errorf("failed to get existing flash OCI auth secret: %w", err)
+							return nil, fmt.Errorf("failed to get existing flash OCI auth secret: %w", err)
 						}
 						existing.Data = ociSecret.Data
 						if err := r.Update(ctx, existing); err != nil {
-							return fmt.Errorf("failed to update flash OCI auth secret: %w", err)
+							return nil, fmt.Errorf("failed to update flash OCI auth secret: %w", err)
 						}
 					} else {
-						return fmt.Errorf("failed to create flash OCI auth secret from registry credentials: %w", err)
+						return nil, fmt.Errorf("failed to create flash OCI auth secret from registry credentials: %w", err)
 					}
 				}
 			}
@@ -1354,6 +1432,11 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 			},
 		)
 
+		testParams, err := tasks.DeviceTestParams(imageBuild.Spec.GetFlashTest(), operatorConfig.Spec.Jumpstarter.GetJumpstarterImage())
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, testParams...)
 	}
 
 	// Determine the shared-workspace binding:
@@ -1409,7 +1492,7 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 	// Create an internal ConfigMap from the inline manifest content
 	manifestConfigMapName, err := r.createOrUpdateManifestConfigMap(ctx, imageBuild)
 	if err != nil {
-		return fmt.Errorf("failed to create manifest ConfigMap: %w", err)
+		return nil, fmt.Errorf("failed to create manifest ConfigMap: %w", err)
 	}
 
 	pipelineWorkspaces := []tektonv1.WorkspaceBinding{
@@ -1433,6 +1516,10 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 		})
 	}
 
+	pipelineWorkspaces = append(pipelineWorkspaces,
+		diskStorageExportWorkspaces(imageBuild.Spec.GetDiskStorageExport())...)
+	pipelineWorkspaces = append(pipelineWorkspaces, signingWorkspaces(signingKey)...)
+
 	if imageBuild.Spec.IsFlashEnabled() {
 		pipelineWorkspaces = append(pipelineWorkspaces, tektonv1.WorkspaceBinding{
 			Name: "jumpstarter-client",
@@ -1458,7 +1545,7 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 						{
 							Key:      corev1.LabelArchStable,
 							Operator: corev1.NodeSelectorOpIn,
-							Values:   []string{controllerutils.NormalizeArchToK8s(imageBuild.Spec.Architecture)},
+							Values:   []string{controllerutils.NormalizeArchToK8s(arch)},
 						},
 					},
 				},
@@ -1483,6 +1570,9 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 		log.Info("Setting RuntimeClassName from ImageBuild spec", "runtimeClassName", imageBuild.Spec.RuntimeClassName)
 		podTemplate.RuntimeClassName = &imageBuild.Spec.RuntimeClassName
 	}
+	if class := operatorConfig.Spec.OSBuilds.GetPriorityClass(imageBuild.Spec.Priority); class != nil && class.PriorityClassName != "" {
+		podTemplate.PriorityClassName = &class.PriorityClassName
+	}
 	pipelineRunSpec := tektonv1.PipelineRunSpec{
 		Params:     params,
 		Workspaces: pipelineWorkspaces,
@@ -1509,11 +1599,18 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 		}
 	}
 
+	generateName := safeDerivedName(imageBuild.Name, "-build-")
+	labels := buildLabels(imageBuild, "build")
+	if imageBuild.Spec.IsMultiArch() {
+		generateName = safeDerivedName(imageBuild.Name, "-build-"+arch+"-")
+		labels[automotivev1alpha1.LabelArchitecture] = controllerutils.SanitizeLabelValue(arch)
+	}
+
 	pipelineRun := &tektonv1.PipelineRun{
 		ObjectMeta: metav1.ObjectMeta{
-			GenerateName: safeDerivedName(imageBuild.Name, "-build-"),
+			GenerateName: generateName,
 			Namespace:    imageBuild.Namespace,
-			Labels:       buildLabels(imageBuild, "build"),
+			Labels:       labels,
 			OwnerReferences: []metav1.OwnerReference{
 				{
 					APIVersion: imageBuild.APIVersion,
@@ -1528,17 +1625,20 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 	}
 
 	if err := r.Create(ctx, pipelineRun); err != nil {
-		return fmt.Errorf("failed to create PipelineRun: %w", err)
+		return nil, fmt.Errorf("failed to create PipelineRun: %w", err)
 	}
 
 	fresh := &automotivev1alpha1.ImageBuild{}
 	if err := r.Get(ctx, types.NamespacedName{Name: imageBuild.Name, Namespace: imageBuild.Namespace}, fresh); err != nil {
-		return fmt.Errorf("failed to get fresh ImageBuild: %w", err)
+		return nil, fmt.Errorf("failed to get fresh ImageBuild: %w", err)
 	}
 
-	fresh.Status.PipelineRunName = pipelineRun.Name
-	if err := r.Status().Update(ctx, fresh); err != nil {
-		return fmt.Errorf("failed to update ImageBuild with PipelineRun name: %w", err)
+	if !fresh.Spec.IsMultiArch() {
+		fresh.Status.PipelineRunName = pipelineRun.Name
+		fresh.Status.NextRetryTime = nil
+		if err := r.Status().Update(ctx, fresh); err != nil {
+			return nil, fmt.Errorf("failed to update ImageBuild with PipelineRun name: %w", err)
+		}
 	}
 	r.emitEventf(
 		fresh,
@@ -1548,13 +1648,13 @@ func (r *ImageBuildReconciler) createBuildTaskRun(
 		pipelineRun.Name,
 		fresh.Spec.GetMode(),
 		fresh.Spec.GetTarget(),
-		fresh.Spec.Architecture,
+		arch,
 		fresh.Spec.GetBuildDiskImage(),
 		fresh.Spec.IsFlashEnabled(),
 	)
 
 	log.Info("Successfully created PipelineRun", "name", pipelineRun.Name)
-	return nil
+	return pipelineRun, nil
 }
 
 // createOrUpdateManifestConfigMap creates or updates a ConfigMap containing the inline
@@ -1797,6 +1897,7 @@ func (r *ImageBuildReconciler) createPushTaskRun(ctx context.Context, imageBuild
 	}
 
 	fresh.Status.PushTaskRunName = taskRun.Name
+	fresh.Status.NextRetryTime = nil
 	if err := r.Status().Update(ctx, fresh); err != nil {
 		return fmt.Errorf("failed to update ImageBuild with push TaskRun name: %w", err)
 	}
@@ -1815,6 +1916,10 @@ func (r *ImageBuildReconciler) handlePushingState(
 	log := r.buildLogger(imageBuild)
 
 	if imageBuild.Status.PushTaskRunName == "" {
+		if wait := retryDelay(imageBuild); wait > 0 {
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+
 		// Fetch PipelineRun to get artifact filename from results
 		pipelineRun := &tektonv1.PipelineRun{}
 		if err := r.Get(ctx, types.NamespacedName{
@@ -1824,7 +1929,7 @@ func (r *ImageBuildReconciler) handlePushingState(
 			log.Error(err, "Failed to get PipelineRun for artifact filename")
 			return ctrl.Result{}, err
 		}
-		artifactFilename := extractArtifactFilename(pipelineRun)
+		artifactFilename := r.pipelineRunArtifactFilename(ctx, pipelineRun)
 
 		// No push TaskRun yet, create one
 		if err := r.createPushTaskRun(ctx, imageBuild, artifactFilename); err != nil {
@@ -1862,6 +1967,13 @@ func (r *ImageBuildReconciler) handlePushingState(
 		return ctrl.Result{RequeueAfter: time.Second * 15}, nil
 	}
 
+	if !isTaskRunSuccessful(taskRun) {
+		retryResult, retried, err := r.retryFailedPush(ctx, imageBuild, taskRun)
+		if err != nil || retried {
+			return retryResult, err
+		}
+	}
+
 	// Push completed - cleanup transient secrets and update status
 	cleanupErr := r.cleanupTransientSecrets(ctx, imageBuild, log)
 
@@ -1871,6 +1983,7 @@ func (r *ImageBuildReconciler) handlePushingState(
 	}
 
 	patch := client.MergeFrom(fresh.DeepCopy())
+	oldPhase := fresh.Status.Phase
 
 	if isTaskRunSuccessful(taskRun) {
 		// Check if flash is enabled
@@ -1881,6 +1994,7 @@ func (r *ImageBuildReconciler) handlePushingState(
 				log.Error(err, "Failed to patch status to Flashing")
 				return ctrl.Result{}, err
 			}
+			r.notifyPhaseChange(ctx, fresh, oldPhase)
 			return ctrl.Result{Requeue: true}, nil
 		}
 
@@ -1888,7 +2002,7 @@ func (r *ImageBuildReconciler) handlePushingState(
 		fresh.Status.Message = "Build and push completed successfully"
 	} else {
 		fresh.Status.Phase = phaseFailed
-		fresh.Status.Message = "Push to registry failed"
+		fresh.Status.Message = taskRunFailureMessage(taskRun, "Push to registry failed")
 	}
 
 	if fresh.Status.CompletionTime == nil {
@@ -1900,6 +2014,7 @@ func (r *ImageBuildReconciler) handlePushingState(
 		log.Error(err, "Failed to patch status after push completion")
 		return ctrl.Result{}, err
 	}
+	r.notifyPhaseChange(ctx, fresh, oldPhase)
 
 	if cleanupErr != nil {
 		return ctrl.Result{RequeueAfter: secretCleanupRequeue}, nil
@@ -1962,12 +2077,24 @@ func (r *ImageBuildReconciler) handleFlashingState(
 	}
 
 	patch := client.MergeFrom(fresh.DeepCopy())
+	oldPhase := fresh.Status.Phase
 
 	flashSucceeded := isTaskRunSuccessful(taskRun)
-	if flashSucceeded {
+	if fresh.Spec.GetFlashTest() != nil {
+		fresh.Status.DeviceTests = r.taskRunDeviceTests(taskRun)
+	}
+	deviceTests := fresh.Status.DeviceTests
+	switch {
+	case flashSucceeded && deviceTests != nil:
+		fresh.Status.Phase = phaseCompleted
+		fresh.Status.Message = "Build, push, and flash completed successfully; " + deviceTestMessage(deviceTests)
+	case flashSucceeded:
 		fresh.Status.Phase = phaseCompleted
 		fresh.Status.Message = "Build, push, and flash completed successfully"
-	} else {
+	case deviceTests != nil && !deviceTests.Succeeded():
+		fresh.Status.Phase = phaseFailed
+		fresh.Status.Message = deviceTestMessage(deviceTests)
+	default:
 		fresh.Status.Phase = phaseFailed
 		fresh.Status.Message = taskRunFailureMessage(taskRun, "Flash to device failed")
 	}
@@ -1981,6 +2108,7 @@ func (r *ImageBuildReconciler) handleFlashingState(
 		log.Error(err, "Failed to patch status after flash completion")
 		return ctrl.Result{}, err
 	}
+	r.notifyPhaseChange(ctx, fresh, oldPhase)
 
 	if flashSucceeded {
 		recordFlashMetrics(imageBuild, taskRun, buildStatusSuccess)
@@ -2078,6 +2206,11 @@ func (r *ImageBuildReconciler) createFlashTaskRun(
 			Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: getTraceID(imageBuild)},
 		},
 	}
+	testParams, err := tasks.DeviceTestParams(imageBuild.Spec.GetFlashTest(), operatorConfig.Spec.Jumpstarter.GetJumpstarterImage())
+	if err != nil {
+		return err
+	}
+	params = append(params, testParams...)
 
 	workspaces := []tektonv1.WorkspaceBinding{
 		{
@@ -2252,29 +2385,21 @@ func pipelineRunFailureMessage(pipelineRun *tektonv1.PipelineRun) string {
 
 // pipelineTaskLabel maps pipeline task names to user-friendly labels for error messages.
 var pipelineTaskLabel = map[string]string{
-	"build-image":        "Image build failed",
-	"push-disk-artifact": "Disk image push failed",
-	"flash-image":        "Flash failed",
+	pipelineTaskBuildImage: "Image build failed",
+	pipelineTaskPushDisk:   "Disk image push failed",
+	pipelineTaskFlash:      "Flash failed",
 }
 
 func (r *ImageBuildReconciler) pipelineRunFailureDetail(ctx context.Context, pipelineRun *tektonv1.PipelineRun) string {
-	for _, child := range pipelineRun.Status.ChildReferences {
-		taskRun := &tektonv1.TaskRun{}
-		if err := r.Get(ctx, types.NamespacedName{
-			Name:      child.Name,
-			Namespace: pipelineRun.Namespace,
-		}, taskRun); err != nil {
-			continue
-		}
-		if isTaskRunCompleted(taskRun) && !isTaskRunSuccessful(taskRun) {
-			label := pipelineTaskLabel[child.PipelineTaskName]
-			if label == "" {
-				label = fmt.Sprintf("Task %q failed", child.PipelineTaskName)
-			}
-			return taskRunFailureMessage(taskRun, label)
-		}
+	taskRun, pipelineTask := r.failedChildTaskRun(ctx, pipelineRun)
+	if taskRun == nil {
+		return pipelineRunFailureMessage(pipelineRun)
 	}
-	return pipelineRunFailureMessage(pipelineRun)
+	label := pipelineTaskLabel[pipelineTask]
+	if label == "" {
+		label = fmt.Sprintf("Task %q failed", pipelineTask)
+	}
+	return taskRunFailureMessage(taskRun, label)
 }
 
 func taskRunFailureMessage(taskRun *tektonv1.TaskRun, fallback string) string {
@@ -2296,11 +2421,19 @@ type buildTiming struct {
 
 // recordBuildMetrics records Prometheus metrics from a completed build.
 func recordBuildMetrics(imageBuild *automotivev1alpha1.ImageBuild, pipelineRun *tektonv1.PipelineRun, status string) {
+	recordArchBuildMetrics(imageBuild, pipelineRun, imageBuild.Spec.Architecture, status)
+}
+
+// recordArchBuildMetrics records Prometheus metrics for one architecture's PipelineRun.
+func recordArchBuildMetrics(
+	imageBuild *automotivev1alpha1.ImageBuild,
+	pipelineRun *tektonv1.PipelineRun,
+	arch, status string,
+) {
 	mode := imageBuild.Spec.GetMode()
 	distro := imageBuild.Spec.GetDistro()
 	target := imageBuild.Spec.GetTarget()
 	format := imageBuild.Spec.GetExportFormat()
-	arch := imageBuild.Spec.Architecture
 
 	BuildTotal.WithLabelValues(mode, distro, target, format, arch, status).Inc()
 
@@ -2637,6 +2770,9 @@ func (r *ImageBuildReconciler) updateStatus(
 
 	fresh.Status.Phase = phase
 	fresh.Status.Message = message
+	if phase != phaseQueued {
+		fresh.Status.QueuePosition = 0
+	}
 
 	if phase == phaseBuilding && fresh.Status.StartTime == nil {
 		now := metav1.Now()
@@ -2645,6 +2781,9 @@ func (r *ImageBuildReconciler) updateStatus(
 		now := metav1.Now()
 		fresh.Status.CompletionTime = &now
 	}
+	if isTerminalPhase(phase) {
+		fresh.Status.NextRetryTime = nil
+	}
 
 	setImageBuildConditions(fresh, phase, message)
 
@@ -2667,6 +2806,7 @@ func (r *ImageBuildReconciler) updateStatus(
 			fresh.Spec.GetBuildDiskImage(),
 		)
 		r.emitImageBuildLifecycleEvent(fresh, oldPhase, phase, message)
+		r.notifyPhaseChange(ctx, fresh, oldPhase)
 	}
 	return nil
 }
@@ -2771,6 +2911,20 @@ func (r *ImageBuildReconciler) emitImageBuildLifecycleEvent(
 	}
 }
 
+// notifyPhaseChange sends the transition of imageBuild into its current phase to the
+// notification targets of the OperatorConfig.
+func (r *ImageBuildReconciler) notifyPhaseChange(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+	oldPhase string,
+) {
+	if oldPhase == imageBuild.Status.Phase {
+		return
+	}
+	r.Notifier.Notify(ctx, notifications.EventFor(
+		imageBuild, "ImageBuild", oldPhase, imageBuild.Status.Phase, imageBuild.Status.Message))
+}
+
 func eventTypeForPhase(phase string) string {
 	if phase == phaseFailed {
 		return corev1.EventTypeWarning
@@ -0,0 +1,76 @@
+package imagebuild
+
+import (
+	"context"
+	"fmt"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/tasks"
+)
+
+const pipelineTaskFlash = "flash-image"
+
+// pipelineRunDeviceTests returns the on-device test results of a PipelineRun.
+// Failed PipelineRuns carry no pipeline results, so the flash-image TaskRun
+// result is used as a fallback.
+func (r *ImageBuildReconciler) pipelineRunDeviceTests(
+	ctx context.Context, pipelineRun *tektonv1.PipelineRun,
+) *automotivev1alpha1.DeviceTestResults {
+	summary := ""
+	for _, result := range pipelineRun.Status.Results {
+		if result.Name == tasks.FlashResultTestSummary {
+			summary = result.Value.StringVal
+			break
+		}
+	}
+	if summary == "" {
+		summary = r.childTaskRunResult(ctx, pipelineRun, pipelineTaskFlash, tasks.FlashResultTestSummary)
+	}
+	return r.parseDeviceTests(summary)
+}
+
+// taskRunDeviceTests returns the on-device test results of a flash TaskRun.
+func (r *ImageBuildReconciler) taskRunDeviceTests(taskRun *tektonv1.TaskRun) *automotivev1alpha1.DeviceTestResults {
+	for _, result := range taskRun.Status.Results {
+		if result.Name == tasks.FlashResultTestSummary {
+			return r.parseDeviceTests(result.Value.StringVal)
+		}
+	}
+	return nil
+}
+
+func (r *ImageBuildReconciler) parseDeviceTests(summary string) *automotivev1alpha1.DeviceTestResults {
+	results, err := tasks.ParseDeviceTestSummary(summary)
+	if err != nil {
+		r.Log.Error(err, "Ignoring device test results")
+		return nil
+	}
+	return results
+}
+
+// recordDeviceTests stores on-device test results in the build status.
+func (r *ImageBuildReconciler) recordDeviceTests(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+	results *automotivev1alpha1.DeviceTestResults,
+) error {
+	fresh := &automotivev1alpha1.ImageBuild{}
+	if err := r.Get(ctx, types.NamespacedName{Name: imageBuild.Name, Namespace: imageBuild.Namespace}, fresh); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(fresh.DeepCopy())
+	fresh.Status.DeviceTests = results
+	return r.Status().Patch(ctx, fresh, patch)
+}
+
+// deviceTestMessage describes the outcome of on-device tests for the build status message.
+func deviceTestMessage(results *automotivev1alpha1.DeviceTestResults) string {
+	if results.Succeeded() {
+		return fmt.Sprintf("device tests passed: %s", results.Message)
+	}
+	return fmt.Sprintf("Device tests failed: %s", results.Message)
+}
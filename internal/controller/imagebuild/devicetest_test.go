@@ -0,0 +1,76 @@
+package imagebuild
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/tasks"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+const failedDeviceTestSummary = `{"booted":true,"total":2,"passed":1,"failed":1,"message":"1 of 2 tests failed",` +
+	`"failedCases":[{"suite":"smoke","name":"network","message":"no route"}]}`
+
+func TestPipelineRunDeviceTests_FallsBackToFlashTaskRun(t *testing.T) {
+	ib := retryingBuild(nil, "retry-build-pr-1")
+	pr := failedPipelineRun("retry-build-pr-1",
+		tektonv1.ChildStatusReference{Name: "retry-build-pr-1-flash", PipelineTaskName: pipelineTaskFlash})
+	flash := testTaskRun("retry-build-pr-1-flash", true, "")
+	flash.Status.Results = []tektonv1.TaskRunResult{{
+		Name:  tasks.FlashResultTestSummary,
+		Value: tektonv1.ResultValue{Type: tektonv1.ParamTypeString, StringVal: failedDeviceTestSummary},
+	}}
+	r := newRetryReconciler(ib, pr, flash)
+
+	results := r.pipelineRunDeviceTests(context.Background(), pr)
+	if results == nil {
+		t.Fatal("expected device test results from the flash TaskRun")
+	}
+	if results.Failed != 1 || len(results.FailedCases) != 1 {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
+
+func TestCheckBuildProgress_RecordsFailedDeviceTests(t *testing.T) {
+	ib := retryingBuild(nil, "retry-build-pr-1")
+	ib.Spec.Flash = &automotivev1alpha1.FlashSpec{
+		ClientConfigSecretRef: "jumpstarter-client",
+		Test:                  &automotivev1alpha1.DeviceTestSpec{Script: "true"},
+	}
+	pr := failedPipelineRun("retry-build-pr-1",
+		tektonv1.ChildStatusReference{Name: "retry-build-pr-1-flash", PipelineTaskName: pipelineTaskFlash})
+	flash := testTaskRun("retry-build-pr-1-flash", true, `"step-collect-test-results" exited with code 1`)
+	flash.Status.Results = []tektonv1.TaskRunResult{{
+		Name:  tasks.FlashResultTestSummary,
+		Value: tektonv1.ResultValue{Type: tektonv1.ParamTypeString, StringVal: failedDeviceTestSummary},
+	}}
+	r := newRetryReconciler(ib, pr, flash)
+
+	if _, err := r.checkBuildProgress(context.Background(), ib); err != nil {
+		t.Fatalf("checkBuildProgress() error = %v", err)
+	}
+
+	got := getRetryBuild(t, r)
+	if got.Status.Phase != phaseFailed {
+		t.Errorf("phase = %s, want Failed", got.Status.Phase)
+	}
+	if !strings.Contains(got.Status.Message, "Device tests failed: 1 of 2 tests failed") {
+		t.Errorf("message = %q, want device test failure", got.Status.Message)
+	}
+	if got.Status.DeviceTests == nil || got.Status.DeviceTests.Failed != 1 {
+		t.Errorf("unexpected device tests %+v", got.Status.DeviceTests)
+	}
+}
+
+func TestDeviceTestMessage(t *testing.T) {
+	passed := deviceTestMessage(&automotivev1alpha1.DeviceTestResults{Booted: true, Total: 3, Passed: 3, Message: "3 tests passed"})
+	if passed != "device tests passed: 3 tests passed" {
+		t.Errorf("unexpected message %q", passed)
+	}
+	notBooted := deviceTestMessage(&automotivev1alpha1.DeviceTestResults{Message: "Device did not boot within 600s"})
+	if notBooted != "Device tests failed: Device did not boot within 600s" {
+		t.Errorf("unexpected message %q", notBooted)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -467,6 +468,35 @@ func TestHandleExpiredState_DeletesFlashTaskRun(t *testing.T) {
 	}
 }
 
+func TestHandleExpiredState_DeletesFailedAttempts(t *testing.T) {
+	r, ib := newExpiredBuildWithResources(t)
+	ctx := context.Background()
+
+	ib.Status.Attempts = []automotivev1alpha1.BuildAttempt{
+		{Stage: automotivev1alpha1.BuildStageBuild, PipelineRunName: "my-build-build-old"},
+		{Stage: automotivev1alpha1.BuildStagePush, PipelineRunName: "my-build-build-abc", TaskRunName: "my-build-push-old"},
+	}
+	for _, obj := range []client.Object{
+		&tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "my-build-build-old", Namespace: "test-ns"}},
+		&tektonv1.TaskRun{ObjectMeta: metav1.ObjectMeta{Name: "my-build-push-old", Namespace: "test-ns"}},
+	} {
+		if err := r.Create(ctx, obj); err != nil {
+			t.Fatalf("failed to create %s: %v", obj.GetName(), err)
+		}
+	}
+
+	r.handleExpiredState(ctx, ib) //nolint:errcheck
+
+	pr := &tektonv1.PipelineRun{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "my-build-build-old", Namespace: "test-ns"}, pr); !errors.IsNotFound(err) {
+		t.Errorf("expected the failed attempt's PipelineRun to be deleted, got err=%v", err)
+	}
+	tr := &tektonv1.TaskRun{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "my-build-push-old", Namespace: "test-ns"}, tr); !errors.IsNotFound(err) {
+		t.Errorf("expected the failed attempt's push TaskRun to be deleted, got err=%v", err)
+	}
+}
+
 func TestCheckExpiry_AlreadyExpiredSkipsCheck(t *testing.T) {
 	ib := newTestImageBuild("already-expired", automotivev1alpha1.ImageBuildPhaseExpired, "1h", 2*time.Hour)
 	r := newExpiryReconciler(ib)
@@ -0,0 +1,465 @@
+package imagebuild
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/tasks"
+	controllerutils "github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/controllerutils"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	eventReasonImageIndexPushed = "ImageIndexPushed"
+
+	multiArchBuildRequeue = 30 * time.Second
+	imageIndexRequeue     = 10 * time.Second
+)
+
+// validateMultiArchSpec returns a non-empty reason when the spec uses a feature
+// that cannot be fanned out across architectures.
+func validateMultiArchSpec(spec *automotivev1alpha1.ImageBuildSpec) string {
+	switch {
+	case spec.IsFlashEnabled():
+		return "flash is not supported for multi-architecture builds"
+	case spec.BuildCachePVC != "":
+		return "buildCachePVC is not supported for multi-architecture builds"
+	case spec.Workspace != "":
+		return "workspace builds are not supported for multi-architecture builds"
+	case spec.GetInputFilesServer():
+		return "uploaded input files are not supported for multi-architecture builds"
+	case spec.GetDiskStorageExport() != nil:
+		return "s3, http and pvc disk exports are not supported for multi-architecture builds"
+	}
+	return ""
+}
+
+// handleMultiArchBuildingState fans a multi-architecture ImageBuild out into one
+// PipelineRun per architecture, aggregates their phases into the ImageBuild status
+// and, once every architecture has built, publishes the bootc container image index.
+func (r *ImageBuildReconciler) handleMultiArchBuildingState(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+) (result ctrl.Result, err error) {
+	ctx, span := ibTracer.Start(ctx, "ImageBuild.HandleMultiArchBuildingState")
+	defer controllerutils.EndSpanWithError(span, &err)
+	log := r.buildLogger(imageBuild)
+
+	if reason := validateMultiArchSpec(&imageBuild.Spec); reason != "" {
+		return ctrl.Result{}, r.updateStatus(ctx, imageBuild, phaseFailed, "Build configuration error: "+reason)
+	}
+
+	archs := imageBuild.Spec.GetArchitectures()
+	pipelineRuns, err := r.findArchPipelineRuns(ctx, imageBuild)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	statuses := make([]automotivev1alpha1.ArchitectureBuildStatus, 0, len(archs))
+	for _, arch := range archs {
+		pr := pipelineRuns[arch]
+		if pr == nil && imageBuild.Status.ImageIndexTaskRunName == "" {
+			pr, err = r.createBuildTaskRun(ctx, imageBuild, arch)
+			if err != nil {
+				if isBuildConfigError(err) {
+					msg := fmt.Sprintf("Build configuration error: %v", err)
+					return ctrl.Result{}, r.updateStatus(ctx, imageBuild, phaseFailed, msg)
+				}
+				return ctrl.Result{}, fmt.Errorf("failed to create build PipelineRun for %s: %w", arch, err)
+			}
+			pipelineRuns[arch] = pr
+		}
+		statuses = append(statuses, r.architectureStatus(ctx, imageBuild, arch, pr))
+	}
+
+	completed, failed, cancelled := summarizeArchitectures(statuses)
+	if failed != nil || cancelled {
+		// One architecture cannot complete, so the index can never be assembled:
+		// stop the remaining PipelineRuns instead of letting them run to the end.
+		r.cancelArchPipelineRuns(ctx, pipelineRuns)
+		reason := "Build cancelled by user"
+		if failed != nil {
+			reason = fmt.Sprintf("Cancelled after %s build failed", failed.Architecture)
+		}
+		for i := range statuses {
+			if !isTerminalPhase(statuses[i].Phase) {
+				statuses[i].Phase = phaseCancelled
+				statuses[i].Message = reason
+			}
+		}
+	}
+
+	if err := r.patchArchitectureStatus(ctx, imageBuild, statuses); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update architecture status: %w", err)
+	}
+
+	switch {
+	case failed != nil:
+		log.Info("Architecture build failed, cancelled remaining PipelineRuns", "architecture", failed.Architecture)
+		return r.finishMultiArchBuild(ctx, imageBuild, pipelineRuns, phaseFailed,
+			fmt.Sprintf("%s build failed: %s", failed.Architecture, failed.Message))
+	case cancelled:
+		return r.finishMultiArchBuild(ctx, imageBuild, pipelineRuns, phaseCancelled, "Build cancelled by user")
+	case completed < len(statuses):
+		msg := fmt.Sprintf("%d/%d architectures complete", completed, len(statuses))
+		if err := r.updateStatus(ctx, imageBuild, phaseBuilding, msg); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: multiArchBuildRequeue}, nil
+	}
+
+	if imageBuild.Spec.GetContainerPush() == "" {
+		return r.finishMultiArchBuild(ctx, imageBuild, pipelineRuns, phaseCompleted,
+			fmt.Sprintf("Build completed successfully for %d architectures", len(statuses)))
+	}
+	return r.handleImageIndex(ctx, imageBuild, pipelineRuns, statuses)
+}
+
+// findArchPipelineRuns returns the build PipelineRun of each architecture, preferring
+// the names recorded in status and falling back to a label lookup.
+func (r *ImageBuildReconciler) findArchPipelineRuns(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+) (map[string]*tektonv1.PipelineRun, error) {
+	found := make(map[string]*tektonv1.PipelineRun)
+	for _, archStatus := range imageBuild.Status.Architectures {
+		if archStatus.PipelineRunName == "" {
+			continue
+		}
+		pr := &tektonv1.PipelineRun{}
+		err := r.Get(ctx, types.NamespacedName{Name: archStatus.PipelineRunName, Namespace: imageBuild.Namespace}, pr)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get PipelineRun %s: %w", archStatus.PipelineRunName, err)
+		}
+		found[archStatus.Architecture] = pr
+	}
+
+	pipelineRunList := &tektonv1.PipelineRunList{}
+	if err := r.List(ctx, pipelineRunList,
+		client.InNamespace(imageBuild.Namespace),
+		client.MatchingLabels{
+			automotivev1alpha1.LabelImageBuildName: imageBuild.Name,
+			automotivev1alpha1.LabelTaskType:       "build",
+		}); err != nil {
+		return nil, fmt.Errorf("failed to list existing pipeline runs: %w", err)
+	}
+	for _, arch := range imageBuild.Spec.GetArchitectures() {
+		if found[arch] != nil {
+			continue
+		}
+		label := controllerutils.SanitizeLabelValue(arch)
+		for i := range pipelineRunList.Items {
+			pr := &pipelineRunList.Items[i]
+			if pr.DeletionTimestamp != nil || pr.Labels[automotivev1alpha1.LabelArchitecture] != label {
+				continue
+			}
+			if current := found[arch]; current == nil || pr.CreationTimestamp.After(current.CreationTimestamp.Time) {
+				found[arch] = pr
+			}
+		}
+	}
+	return found, nil
+}
+
+// architectureStatus derives the status entry for one architecture from its PipelineRun.
+func (r *ImageBuildReconciler) architectureStatus(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+	arch string,
+	pr *tektonv1.PipelineRun,
+) automotivev1alpha1.ArchitectureBuildStatus {
+	status := automotivev1alpha1.ArchitectureBuildStatus{
+		Architecture:   arch,
+		Phase:          automotivev1alpha1.ImageBuildPhasePending,
+		ContainerImage: imageBuild.Spec.GetArchContainerPush(arch),
+		DiskImage:      imageBuild.Spec.GetArchExportOCI(arch),
+	}
+	if pr == nil {
+		return status
+	}
+
+	status.PipelineRunName = pr.Name
+	status.StartTime = ptr.To(pr.CreationTimestamp)
+	status.CompletionTime = pr.Status.CompletionTime
+
+	if previous := imageBuild.Status.GetArchitectureStatus(arch); previous != nil &&
+		previous.PipelineRunName == pr.Name && isTerminalPhase(previous.Phase) {
+		status.Phase = previous.Phase
+		status.Message = previous.Message
+		return status
+	}
+
+	switch {
+	case !isPipelineRunCompleted(pr):
+		status.Phase = phaseBuilding
+		if pr.Spec.Status == tektonv1.PipelineRunSpecStatusCancelled {
+			status.Phase = phaseCancelled
+			status.Message = "Build cancelled by user"
+		}
+	case isPipelineRunSuccessful(pr):
+		status.Phase = phaseCompleted
+		status.Message = "Build completed successfully"
+	case pr.Spec.Status == tektonv1.PipelineRunSpecStatusCancelled:
+		status.Phase = phaseCancelled
+		status.Message = "Build cancelled by user"
+	default:
+		status.Phase = phaseFailed
+		status.Message = r.pipelineRunFailureDetail(ctx, pr)
+	}
+	return status
+}
+
+// summarizeArchitectures counts completed architectures and reports the first
+// failed one and whether any was cancelled.
+func summarizeArchitectures(
+	statuses []automotivev1alpha1.ArchitectureBuildStatus,
+) (completed int, failed *automotivev1alpha1.ArchitectureBuildStatus, cancelled bool) {
+	for i := range statuses {
+		switch statuses[i].Phase {
+		case phaseCompleted:
+			completed++
+		case phaseFailed:
+			if failed == nil {
+				failed = &statuses[i]
+			}
+		case phaseCancelled:
+			cancelled = true
+		}
+	}
+	return completed, failed, cancelled
+}
+
+func (r *ImageBuildReconciler) patchArchitectureStatus(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+	statuses []automotivev1alpha1.ArchitectureBuildStatus,
+) error {
+	fresh := &automotivev1alpha1.ImageBuild{}
+	if err := r.Get(ctx, types.NamespacedName{Name: imageBuild.Name, Namespace: imageBuild.Namespace}, fresh); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(fresh.DeepCopy())
+	fresh.Status.Architectures = statuses
+	if err := r.Status().Patch(ctx, fresh, patch); err != nil {
+		return err
+	}
+	imageBuild.Status.Architectures = statuses
+	return nil
+}
+
+// cancelArchPipelineRuns requests cancellation of every PipelineRun that is still running.
+func (r *ImageBuildReconciler) cancelArchPipelineRuns(ctx context.Context, pipelineRuns map[string]*tektonv1.PipelineRun) {
+	for arch, pr := range pipelineRuns {
+		if pr == nil || isPipelineRunCompleted(pr) || pr.Spec.Status == tektonv1.PipelineRunSpecStatusCancelled {
+			continue
+		}
+		patch := client.MergeFrom(pr.DeepCopy())
+		pr.Spec.Status = tektonv1.PipelineRunSpecStatusCancelled
+		if err := r.Patch(ctx, pr, patch); err != nil && !errors.IsNotFound(err) {
+			r.Log.Error(err, "Failed to cancel PipelineRun", "architecture", arch, "pipelineRun", pr.Name)
+		}
+	}
+}
+
+// handleImageIndex creates the image index TaskRun once all architectures have
+// built, then waits for it before completing the build.
+func (r *ImageBuildReconciler) handleImageIndex(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+	pipelineRuns map[string]*tektonv1.PipelineRun,
+	statuses []automotivev1alpha1.ArchitectureBuildStatus,
+) (ctrl.Result, error) {
+	log := r.buildLogger(imageBuild)
+
+	if imageBuild.Status.ImageIndexTaskRunName != "" {
+		taskRun := &tektonv1.TaskRun{}
+		err := r.Get(ctx, types.NamespacedName{
+			Name:      imageBuild.Status.ImageIndexTaskRunName,
+			Namespace: imageBuild.Namespace,
+		}, taskRun)
+		switch {
+		case errors.IsNotFound(err):
+			log.Info("Image index TaskRun not found, recreating", "taskRun", imageBuild.Status.ImageIndexTaskRunName)
+		case err != nil:
+			return ctrl.Result{}, err
+		case !isTaskRunCompleted(taskRun):
+			return ctrl.Result{RequeueAfter: imageIndexRequeue}, nil
+		case isTaskRunSuccessful(taskRun):
+			r.emitEventf(imageBuild, corev1.EventTypeNormal, eventReasonImageIndexPushed,
+				"Image index pushed: image=%s architectures=%s",
+				imageBuild.Spec.GetContainerPush(), strings.Join(imageBuild.Spec.GetArchitectures(), ","))
+			return r.finishMultiArchBuild(ctx, imageBuild, pipelineRuns, phaseCompleted,
+				fmt.Sprintf("Build completed successfully for %d architectures", len(statuses)))
+		default:
+			return r.finishMultiArchBuild(ctx, imageBuild, pipelineRuns, phaseFailed,
+				taskRunFailureMessage(taskRun, "Image index push failed"))
+		}
+	}
+
+	if err := r.createImageIndexTaskRun(ctx, imageBuild, statuses); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to create image index TaskRun: %w", err)
+	}
+	if err := r.updateStatus(ctx, imageBuild, phaseBuilding, "Publishing multi-architecture image index"); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: imageIndexRequeue}, nil
+}
+
+func (r *ImageBuildReconciler) createImageIndexTaskRun(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+	statuses []automotivev1alpha1.ArchitectureBuildStatus,
+) (err error) {
+	ctx, span := ibTracer.Start(ctx, "ImageBuild.CreateImageIndexTaskRun")
+	defer controllerutils.EndSpanWithError(span, &err)
+
+	images := make([]string, 0, len(statuses))
+	for _, s := range statuses {
+		images = append(images, s.ContainerImage)
+	}
+
+	insecureRegistry := false
+	operatorConfig := &automotivev1alpha1.OperatorConfig{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "config", Namespace: controllerutils.OperatorNamespace()}, operatorConfig); err == nil {
+		insecureRegistry = operatorConfig.Spec.OSBuilds != nil && operatorConfig.Spec.OSBuilds.InsecureRegistry
+	}
+
+	indexTask := tasks.GeneratePushImageIndexTask(controllerutils.OperatorNamespace(), r.resolveBuildConfig(ctx))
+	params := []tektonv1.Param{
+		{
+			Name:  "image-index",
+			Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: imageBuild.Spec.GetContainerPush()},
+		},
+		{
+			Name:  "images",
+			Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: strings.Join(images, "\n")},
+		},
+		{
+			Name:  "insecure-registry",
+			Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: fmt.Sprintf("%t", insecureRegistry)},
+		},
+		{
+			Name:  "trace-id",
+			Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: getTraceID(imageBuild)},
+		},
+	}
+	if aibImage := imageBuild.Spec.GetAIBImage(); aibImage != "" {
+		params = append(params, tektonv1.Param{
+			Name:  "aib-image",
+			Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: aibImage},
+		})
+	}
+
+	var workspaces []tektonv1.WorkspaceBinding
+	if imageBuild.Spec.SecretRef != "" {
+		workspaces = append(workspaces, tektonv1.WorkspaceBinding{
+			Name:   "registry-auth",
+			Secret: &corev1.SecretVolumeSource{SecretName: imageBuild.Spec.SecretRef},
+		})
+	}
+
+	taskRun := &tektonv1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: safeDerivedName(imageBuild.Name, "-index-"),
+			Namespace:    imageBuild.Namespace,
+			Labels:       buildLabels(imageBuild, "image-index"),
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: imageBuild.APIVersion,
+					Kind:       imageBuild.Kind,
+					Name:       imageBuild.Name,
+					UID:        imageBuild.UID,
+					Controller: ptr.To(true),
+				},
+			},
+		},
+		Spec: tektonv1.TaskRunSpec{
+			TaskSpec:           &indexTask.Spec,
+			Params:             params,
+			Workspaces:         workspaces,
+			ServiceAccountName: automotivev1alpha1.BuildServiceAccountName,
+		},
+	}
+	if err := r.Create(ctx, taskRun); err != nil {
+		return err
+	}
+
+	fresh := &automotivev1alpha1.ImageBuild{}
+	if err := r.Get(ctx, types.NamespacedName{Name: imageBuild.Name, Namespace: imageBuild.Namespace}, fresh); err != nil {
+		return fmt.Errorf("failed to get fresh ImageBuild: %w", err)
+	}
+	patch := client.MergeFrom(fresh.DeepCopy())
+	fresh.Status.ImageIndexTaskRunName = taskRun.Name
+	if err := r.Status().Patch(ctx, fresh, patch); err != nil {
+		return fmt.Errorf("failed to update ImageBuild with image index TaskRun name: %w", err)
+	}
+	imageBuild.Status.ImageIndexTaskRunName = taskRun.Name
+
+	r.buildLogger(imageBuild).Info("Successfully created image index TaskRun", "name", taskRun.Name)
+	return nil
+}
+
+// finishMultiArchBuild moves a multi-architecture build to a terminal phase,
+// recording provenance, per-architecture metrics and cleaning up transient secrets.
+func (r *ImageBuildReconciler) finishMultiArchBuild(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+	pipelineRuns map[string]*tektonv1.PipelineRun,
+	phase, message string,
+) (ctrl.Result, error) {
+	if phase == phaseCompleted {
+		if pr := pipelineRuns[imageBuild.Spec.GetArchitectures()[0]]; pr != nil {
+			fresh := &automotivev1alpha1.ImageBuild{}
+			if err := r.Get(ctx, types.NamespacedName{Name: imageBuild.Name, Namespace: imageBuild.Namespace}, fresh); err != nil {
+				return ctrl.Result{}, err
+			}
+			patch := client.MergeFrom(fresh.DeepCopy())
+			fresh.Status.AIBImageUsed, fresh.Status.BuilderImageUsed = extractProvenance(pr, fresh.Spec.GetAIBImage())
+			if err := r.Status().Patch(ctx, fresh, patch); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	if err := r.updateStatus(ctx, imageBuild, phase, message); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	fresh := &automotivev1alpha1.ImageBuild{}
+	if err := r.Get(ctx, types.NamespacedName{Name: imageBuild.Name, Namespace: imageBuild.Namespace}, fresh); err != nil {
+		return ctrl.Result{}, err
+	}
+	metricStatus := buildStatusFailure
+	if phase == phaseCompleted {
+		metricStatus = buildStatusSuccess
+		r.emitEventf(fresh, corev1.EventTypeNormal, eventReasonBuildCompleted,
+			"Build completed successfully: mode=%s target=%s arch=%s toDisk=%t",
+			fresh.Spec.GetMode(), fresh.Spec.GetTarget(),
+			strings.Join(fresh.Spec.GetArchitectures(), ","), fresh.Spec.GetBuildDiskImage())
+	}
+	for arch, pr := range pipelineRuns {
+		status := metricStatus
+		if archStatus := fresh.Status.GetArchitectureStatus(arch); archStatus != nil && archStatus.Phase == phaseCompleted {
+			status = buildStatusSuccess
+		}
+		recordArchBuildMetrics(fresh, pr, arch, status)
+	}
+
+	if err := r.cleanupTransientSecrets(ctx, imageBuild, r.Log); err != nil {
+		return ctrl.Result{RequeueAfter: secretCleanupRequeue}, nil
+	}
+	return ctrl.Result{}, nil
+}
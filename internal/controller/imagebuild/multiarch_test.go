@@ -0,0 +1,329 @@
+package imagebuild
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/go-logr/logr"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	knativev1 "knative.dev/pkg/apis/duck/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newMultiArchImageBuild() *automotivev1alpha1.ImageBuild {
+	return &automotivev1alpha1.ImageBuild{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "automotive.sdv.cloud.redhat.com/v1alpha1",
+			Kind:       "ImageBuild",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: "multi", Namespace: "test-ns", UID: "uid-multi"},
+		Spec: automotivev1alpha1.ImageBuildSpec{
+			Architecture:  "amd64",
+			Architectures: []string{"amd64", "arm64"},
+			AIB: &automotivev1alpha1.AIBSpec{
+				Distro:   "autosd",
+				Target:   "qemu",
+				Mode:     "bootc",
+				Manifest: "name: multi\n",
+			},
+			Export: &automotivev1alpha1.ExportSpec{Container: "quay.io/org/img:v1"},
+		},
+		Status: automotivev1alpha1.ImageBuildStatus{Phase: phaseBuilding},
+	}
+}
+
+func newMultiArchReconciler(ib *automotivev1alpha1.ImageBuild, objs ...client.Object) *ImageBuildReconciler {
+	scheme := newTestSchemeWithTekton()
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(append([]client.Object{ib}, objs...)...).
+		WithStatusSubresource(ib).
+		Build()
+	return &ImageBuildReconciler{
+		Client:   fakeClient,
+		Scheme:   scheme,
+		Log:      logr.Discard(),
+		Recorder: record.NewFakeRecorder(100),
+	}
+}
+
+func archPipelineRun(name, arch string, completed, succeeded bool) *tektonv1.PipelineRun {
+	pr := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test-ns",
+			Labels: map[string]string{
+				automotivev1alpha1.LabelImageBuildName: "multi",
+				automotivev1alpha1.LabelTaskType:       "build",
+				automotivev1alpha1.LabelArchitecture:   arch,
+			},
+		},
+	}
+	if completed {
+		now := metav1.Now()
+		status := corev1.ConditionTrue
+		if !succeeded {
+			status = corev1.ConditionFalse
+		}
+		pr.Status.CompletionTime = &now
+		pr.Status.Status = knativev1.Status{
+			Conditions: knativev1.Conditions{{Type: conditionSucceeded, Status: status, Message: "boom"}},
+		}
+	}
+	return pr
+}
+
+func getImageBuild(t *testing.T, r *ImageBuildReconciler) *automotivev1alpha1.ImageBuild {
+	t.Helper()
+	ib := &automotivev1alpha1.ImageBuild{}
+	if err := r.Get(context.Background(), types.NamespacedName{Name: "multi", Namespace: "test-ns"}, ib); err != nil {
+		t.Fatalf("failed to get ImageBuild: %v", err)
+	}
+	return ib
+}
+
+func paramValue(params []tektonv1.Param, name string) string {
+	for _, p := range params {
+		if p.Name == name {
+			return p.Value.StringVal
+		}
+	}
+	return ""
+}
+
+func TestValidateMultiArchSpec(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*automotivev1alpha1.ImageBuildSpec)
+		want   string
+	}{
+		{name: "plain multi-arch build is valid", mutate: func(*automotivev1alpha1.ImageBuildSpec) {}},
+		{
+			name: "flash is rejected",
+			mutate: func(s *automotivev1alpha1.ImageBuildSpec) {
+				s.Flash = &automotivev1alpha1.FlashSpec{ClientConfigSecretRef: "jmp"}
+			},
+			want: "flash",
+		},
+		{
+			name:   "build cache PVC is rejected",
+			mutate: func(s *automotivev1alpha1.ImageBuildSpec) { s.BuildCachePVC = "cache" },
+			want:   "buildCachePVC",
+		},
+		{
+			name:   "uploaded inputs are rejected",
+			mutate: func(s *automotivev1alpha1.ImageBuildSpec) { s.AIB.InputFilesServer = true },
+			want:   "uploaded input files",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := newMultiArchImageBuild().Spec
+			tt.mutate(&spec)
+			got := validateMultiArchSpec(&spec)
+			if tt.want == "" && got != "" {
+				t.Fatalf("expected no error, got %q", got)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Fatalf("validateMultiArchSpec() = %q, want it to mention %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleMultiArchBuildingState_FansOutPerArchitecture(t *testing.T) {
+	ib := newMultiArchImageBuild()
+	r := newMultiArchReconciler(ib)
+	ctx := context.Background()
+
+	if _, err := r.handleMultiArchBuildingState(ctx, ib); err != nil {
+		t.Fatalf("handleMultiArchBuildingState() error = %v", err)
+	}
+
+	prs := &tektonv1.PipelineRunList{}
+	if err := r.List(ctx, prs, client.InNamespace("test-ns")); err != nil {
+		t.Fatalf("failed to list PipelineRuns: %v", err)
+	}
+	if len(prs.Items) != 2 {
+		t.Fatalf("expected 2 PipelineRuns, got %d", len(prs.Items))
+	}
+	for _, pr := range prs.Items {
+		arch := pr.Labels[automotivev1alpha1.LabelArchitecture]
+		if got := paramValue(pr.Spec.Params, "arch"); got != arch {
+			t.Errorf("PipelineRun %s arch param = %q, want %q", pr.Name, got, arch)
+		}
+		if got := paramValue(pr.Spec.Params, "container-push"); got != "quay.io/org/img:v1-"+arch {
+			t.Errorf("PipelineRun %s container-push = %q", pr.Name, got)
+		}
+		if !strings.HasPrefix(pr.GenerateName, "multi-build-"+arch+"-") {
+			t.Errorf("PipelineRun GenerateName = %q, want arch-scoped prefix", pr.GenerateName)
+		}
+		terms := pr.Spec.TaskRunTemplate.PodTemplate.Affinity.NodeAffinity.
+			RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		if got := terms[0].MatchExpressions[0].Values[0]; got != arch {
+			t.Errorf("PipelineRun %s node affinity = %q, want %q", pr.Name, got, arch)
+		}
+	}
+
+	got := getImageBuild(t, r)
+	if got.Status.PipelineRunName != "" {
+		t.Errorf("multi-arch builds should not set Status.PipelineRunName, got %q", got.Status.PipelineRunName)
+	}
+	if len(got.Status.Architectures) != 2 {
+		t.Fatalf("expected 2 architecture statuses, got %+v", got.Status.Architectures)
+	}
+	for _, s := range got.Status.Architectures {
+		if s.PipelineRunName == "" || s.Phase != phaseBuilding {
+			t.Errorf("unexpected architecture status %+v", s)
+		}
+	}
+	if got.Status.Message != "0/2 architectures complete" {
+		t.Errorf("status message = %q", got.Status.Message)
+	}
+
+	// A second pass must reuse the recorded PipelineRuns rather than create new ones.
+	if _, err := r.handleMultiArchBuildingState(ctx, got); err != nil {
+		t.Fatalf("second handleMultiArchBuildingState() error = %v", err)
+	}
+	if err := r.List(ctx, prs, client.InNamespace("test-ns")); err != nil {
+		t.Fatalf("failed to list PipelineRuns: %v", err)
+	}
+	if len(prs.Items) != 2 {
+		t.Errorf("expected PipelineRuns to be reused, got %d", len(prs.Items))
+	}
+}
+
+func TestHandleMultiArchBuildingState_FailureCancelsOtherArchitectures(t *testing.T) {
+	ib := newMultiArchImageBuild()
+	r := newMultiArchReconciler(ib,
+		archPipelineRun("multi-build-amd64-x", "amd64", true, false),
+		archPipelineRun("multi-build-arm64-y", "arm64", false, false),
+	)
+	ctx := context.Background()
+
+	if _, err := r.handleMultiArchBuildingState(ctx, ib); err != nil {
+		t.Fatalf("handleMultiArchBuildingState() error = %v", err)
+	}
+
+	got := getImageBuild(t, r)
+	if got.Status.Phase != phaseFailed {
+		t.Fatalf("phase = %q, want Failed", got.Status.Phase)
+	}
+	if !strings.HasPrefix(got.Status.Message, "amd64 build failed") {
+		t.Errorf("message = %q, want amd64 failure", got.Status.Message)
+	}
+	if s := got.Status.GetArchitectureStatus("arm64"); s == nil || s.Phase != phaseCancelled {
+		t.Errorf("arm64 status = %+v, want Cancelled", s)
+	}
+
+	arm := &tektonv1.PipelineRun{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "multi-build-arm64-y", Namespace: "test-ns"}, arm); err != nil {
+		t.Fatalf("failed to get arm64 PipelineRun: %v", err)
+	}
+	if arm.Spec.Status != tektonv1.PipelineRunSpecStatusCancelled {
+		t.Errorf("arm64 PipelineRun spec.status = %q, want cancelled", arm.Spec.Status)
+	}
+}
+
+func TestHandleMultiArchBuildingState_PublishesImageIndex(t *testing.T) {
+	ib := newMultiArchImageBuild()
+	r := newMultiArchReconciler(ib,
+		archPipelineRun("multi-build-amd64-x", "amd64", true, true),
+		archPipelineRun("multi-build-arm64-y", "arm64", true, true),
+	)
+	ctx := context.Background()
+
+	if _, err := r.handleMultiArchBuildingState(ctx, ib); err != nil {
+		t.Fatalf("handleMultiArchBuildingState() error = %v", err)
+	}
+
+	got := getImageBuild(t, r)
+	if got.Status.Phase != phaseBuilding || got.Status.ImageIndexTaskRunName == "" {
+		t.Fatalf("expected Building with an image index TaskRun, got phase=%q taskRun=%q",
+			got.Status.Phase, got.Status.ImageIndexTaskRunName)
+	}
+
+	taskRun := &tektonv1.TaskRun{}
+	key := types.NamespacedName{Name: got.Status.ImageIndexTaskRunName, Namespace: "test-ns"}
+	if err := r.Get(ctx, key, taskRun); err != nil {
+		t.Fatalf("failed to get image index TaskRun: %v", err)
+	}
+	if got := paramValue(taskRun.Spec.Params, "image-index"); got != "quay.io/org/img:v1" {
+		t.Errorf("image-index param = %q", got)
+	}
+	if got := paramValue(taskRun.Spec.Params, "images"); got != "quay.io/org/img:v1-amd64\nquay.io/org/img:v1-arm64" {
+		t.Errorf("images param = %q", got)
+	}
+
+	now := metav1.Now()
+	taskRun.Status.CompletionTime = &now
+	taskRun.Status.Status = knativev1.Status{
+		Conditions: knativev1.Conditions{{Type: conditionSucceeded, Status: corev1.ConditionTrue}},
+	}
+	if err := r.Update(ctx, taskRun); err != nil {
+		t.Fatalf("failed to complete TaskRun: %v", err)
+	}
+
+	if _, err := r.handleMultiArchBuildingState(ctx, got); err != nil {
+		t.Fatalf("handleMultiArchBuildingState() error = %v", err)
+	}
+	got = getImageBuild(t, r)
+	if got.Status.Phase != phaseCompleted {
+		t.Fatalf("phase = %q, want Completed (message %q)", got.Status.Phase, got.Status.Message)
+	}
+	if got.Status.CompletionTime == nil {
+		t.Errorf("expected CompletionTime to be set")
+	}
+}
+
+func TestHandleMultiArchBuildingState_ConfigErrorIsTerminal(t *testing.T) {
+	ib := newMultiArchImageBuild()
+	ib.Spec.BuildCachePVC = "cache"
+	r := newMultiArchReconciler(ib)
+
+	if _, err := r.handleMultiArchBuildingState(context.Background(), ib); err != nil {
+		t.Fatalf("handleMultiArchBuildingState() error = %v", err)
+	}
+	got := getImageBuild(t, r)
+	if got.Status.Phase != phaseFailed || !strings.HasPrefix(got.Status.Message, "Build configuration error") {
+		t.Errorf("got phase=%q message=%q, want configuration failure", got.Status.Phase, got.Status.Message)
+	}
+}
+
+func TestHandleExpiredState_DeletesArchitecturePipelineRuns(t *testing.T) {
+	ib := newMultiArchImageBuild()
+	ib.Status.Phase = automotivev1alpha1.ImageBuildPhaseExpired
+	ib.Status.Architectures = []automotivev1alpha1.ArchitectureBuildStatus{
+		{Architecture: "amd64", PipelineRunName: "multi-build-amd64-x"},
+		{Architecture: "arm64", PipelineRunName: "multi-build-arm64-y"},
+	}
+	ib.Status.ImageIndexTaskRunName = "multi-index-z"
+	r := newMultiArchReconciler(ib,
+		archPipelineRun("multi-build-amd64-x", "amd64", true, true),
+		archPipelineRun("multi-build-arm64-y", "arm64", true, true),
+		&tektonv1.TaskRun{ObjectMeta: metav1.ObjectMeta{Name: "multi-index-z", Namespace: "test-ns"}},
+	)
+	ctx := context.Background()
+
+	r.handleExpiredState(ctx, ib)
+
+	for _, name := range []string{"multi-build-amd64-x", "multi-build-arm64-y"} {
+		err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: "test-ns"}, &tektonv1.PipelineRun{})
+		if !errors.IsNotFound(err) {
+			t.Errorf("expected PipelineRun %s to be deleted, got err=%v", name, err)
+		}
+	}
+	err := r.Get(ctx, types.NamespacedName{Name: "multi-index-z", Namespace: "test-ns"}, &tektonv1.TaskRun{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("expected image index TaskRun to be deleted, got err=%v", err)
+	}
+}
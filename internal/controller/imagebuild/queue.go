@@ -0,0 +1,431 @@
+package imagebuild
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	controllerutils "github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/controllerutils"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	phaseQueued = automotivev1alpha1.ImageBuildPhaseQueued
+
+	queueRequeueInterval = 15 * time.Second
+
+	eventReasonBuildPreempted = "BuildPreempted"
+)
+
+// queuePlan is the outcome of one pass over the build queue: the builds that
+// may start now and the 1-based position of every build that has to wait, keyed
+// by queueKey.
+type queuePlan struct {
+	admitted  map[string]bool
+	positions map[string]int32
+}
+
+// queueEntry is a build together with its resolved priority value.
+type queueEntry struct {
+	build    *automotivev1alpha1.ImageBuild
+	priority int32
+}
+
+// queueUsage counts running builds against the queue limits.
+type queueUsage struct {
+	total   int32
+	perUser map[string]int32
+	perArch map[string]int32
+}
+
+func newQueueUsage() *queueUsage {
+	return &queueUsage{perUser: map[string]int32{}, perArch: map[string]int32{}}
+}
+
+func (u *queueUsage) add(build *automotivev1alpha1.ImageBuild) {
+	u.total++
+	u.perUser[buildRequester(build)]++
+	for _, arch := range build.Spec.GetArchitectures() {
+		u.perArch[arch]++
+	}
+}
+
+func (u *queueUsage) fits(cfg *automotivev1alpha1.BuildQueueConfig, build *automotivev1alpha1.ImageBuild) bool {
+	if cfg == nil {
+		return true
+	}
+	if cfg.MaxConcurrentBuilds > 0 && u.total >= cfg.MaxConcurrentBuilds {
+		return false
+	}
+	if cfg.MaxConcurrentBuildsPerUser > 0 && u.perUser[buildRequester(build)] >= cfg.MaxConcurrentBuildsPerUser {
+		return false
+	}
+	for _, arch := range build.Spec.GetArchitectures() {
+		if limit := cfg.MaxConcurrentBuildsPerArchitecture[arch]; limit > 0 && u.perArch[arch] >= limit {
+			return false
+		}
+	}
+	return true
+}
+
+// queueKey identifies a build in a queue that may span several namespaces.
+func queueKey(build *automotivev1alpha1.ImageBuild) string {
+	return client.ObjectKeyFromObject(build).String()
+}
+
+func buildRequester(build *automotivev1alpha1.ImageBuild) string {
+	return build.Annotations[automotivev1alpha1.AnnotationRequestedBy]
+}
+
+// buildPriority returns the priority value of a build. Builds without a
+// matching priority class have priority zero.
+func buildPriority(osBuilds *automotivev1alpha1.OSBuildsConfig, build *automotivev1alpha1.ImageBuild) int32 {
+	if class := osBuilds.GetPriorityClass(build.Spec.Priority); class != nil {
+		return class.Value
+	}
+	return 0
+}
+
+// isQueueActivePhase reports whether a build in phase holds a build slot.
+// Flashing is excluded because it is bound by hardware leases, not build capacity.
+func isQueueActivePhase(phase string) bool {
+	return phase == phaseBuilding || phase == automotivev1alpha1.ImageBuildPhasePushing
+}
+
+// queueOrderLess orders queued builds highest priority first, then oldest first.
+func queueOrderLess(a, b queueEntry) bool {
+	if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	if !a.build.CreationTimestamp.Equal(&b.build.CreationTimestamp) {
+		return a.build.CreationTimestamp.Before(&b.build.CreationTimestamp)
+	}
+	return a.build.Name < b.build.Name
+}
+
+// planBuildQueue decides which queued builds may start. Higher-priority builds
+// always go first. Within a priority, requesters take turns: the next build
+// comes from the requester with the fewest running and already-scheduled
+// builds, so one user submitting many builds cannot starve others. A build that
+// does not fit the limits keeps its place in line while later builds that do
+// fit are admitted past it.
+func planBuildQueue(osBuilds *automotivev1alpha1.OSBuildsConfig, builds []automotivev1alpha1.ImageBuild) queuePlan {
+	plan := queuePlan{admitted: map[string]bool{}, positions: map[string]int32{}}
+	var cfg *automotivev1alpha1.BuildQueueConfig
+	if osBuilds != nil {
+		cfg = osBuilds.BuildQueue
+	}
+	usage := newQueueUsage()
+	waiting := map[string][]queueEntry{}
+
+	for i := range builds {
+		build := &builds[i]
+		switch {
+		case isQueueActivePhase(build.Status.Phase):
+			usage.add(build)
+		case build.Status.Phase == phaseQueued:
+			requester := buildRequester(build)
+			waiting[requester] = append(waiting[requester], queueEntry{build: build, priority: buildPriority(osBuilds, build)})
+		}
+	}
+	for _, queue := range waiting {
+		sort.Slice(queue, func(i, j int) bool { return queueOrderLess(queue[i], queue[j]) })
+	}
+
+	served := make(map[string]int32, len(usage.perUser))
+	for requester, running := range usage.perUser {
+		served[requester] = running
+	}
+
+	var position int32
+	for len(waiting) > 0 {
+		requester := nextRequester(waiting, served)
+		build := waiting[requester][0].build
+		if waiting[requester] = waiting[requester][1:]; len(waiting[requester]) == 0 {
+			delete(waiting, requester)
+		}
+		served[requester]++
+
+		if usage.fits(cfg, build) {
+			usage.add(build)
+			plan.admitted[queueKey(build)] = true
+			continue
+		}
+		position++
+		plan.positions[queueKey(build)] = position
+	}
+	return plan
+}
+
+// nextRequester picks the requester whose turn it is: highest-priority waiting
+// build, then fewest builds served, then the oldest waiting build, then name
+// for a stable order.
+func nextRequester(waiting map[string][]queueEntry, served map[string]int32) string {
+	var next string
+	first := true
+	for requester, queue := range waiting {
+		if first {
+			next, first = requester, false
+			continue
+		}
+		head, nextHead := queue[0], waiting[next][0]
+		switch {
+		case head.priority != nextHead.priority:
+			if head.priority > nextHead.priority {
+				next = requester
+			}
+		case served[requester] != served[next]:
+			if served[requester] < served[next] {
+				next = requester
+			}
+		case !head.build.CreationTimestamp.Equal(&nextHead.build.CreationTimestamp):
+			if queueOrderLess(head, nextHead) {
+				next = requester
+			}
+		case requester < next:
+			next = requester
+		}
+	}
+	return next
+}
+
+// selectPreemptionVictim returns the running build to preempt so that candidate
+// fits within the queue limits, or nil if no lower-priority build would free
+// enough capacity. Lower priorities are preempted first and, within a priority,
+// the most recently started build, since it has the least work to lose.
+func selectPreemptionVictim(
+	osBuilds *automotivev1alpha1.OSBuildsConfig,
+	builds []automotivev1alpha1.ImageBuild,
+	candidate *automotivev1alpha1.ImageBuild,
+) *automotivev1alpha1.ImageBuild {
+	priority := buildPriority(osBuilds, candidate)
+	var victims []queueEntry
+	for i := range builds {
+		build := &builds[i]
+		// Builds publishing their image index are nearly done; let them finish.
+		if build.Status.Phase != phaseBuilding || build.Status.ImageIndexTaskRunName != "" {
+			continue
+		}
+		if p := buildPriority(osBuilds, build); p < priority {
+			victims = append(victims, queueEntry{build: build, priority: p})
+		}
+	}
+	sort.Slice(victims, func(i, j int) bool {
+		if victims[i].priority != victims[j].priority {
+			return victims[i].priority < victims[j].priority
+		}
+		return startedAfter(victims[i].build, victims[j].build)
+	})
+
+	for _, victim := range victims {
+		usage := newQueueUsage()
+		for i := range builds {
+			if isQueueActivePhase(builds[i].Status.Phase) && queueKey(&builds[i]) != queueKey(victim.build) {
+				usage.add(&builds[i])
+			}
+		}
+		if usage.fits(osBuilds.BuildQueue, candidate) {
+			return victim.build
+		}
+	}
+	return nil
+}
+
+func startedAfter(a, b *automotivev1alpha1.ImageBuild) bool {
+	if a.Status.StartTime == nil || b.Status.StartTime == nil {
+		return a.Status.StartTime == nil && b.Status.StartTime != nil
+	}
+	return b.Status.StartTime.Before(a.Status.StartTime)
+}
+
+// queueOperatorConfig returns the OperatorConfig, or nil when it cannot be read.
+func (r *ImageBuildReconciler) queueOperatorConfig(ctx context.Context) *automotivev1alpha1.OperatorConfig {
+	operatorConfig := &automotivev1alpha1.OperatorConfig{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "config", Namespace: controllerutils.OperatorNamespace()}, operatorConfig); err != nil {
+		return nil
+	}
+	return operatorConfig
+}
+
+// queueNamespaces returns the namespaces whose builds share the queue of a
+// build in namespace. The Build API namespace and the tenant namespaces share
+// one queue so that the limits apply across all tenants; builds in any other
+// namespace are queued per namespace.
+func queueNamespaces(operatorConfig *automotivev1alpha1.OperatorConfig, namespace string) []string {
+	shared := []string{controllerutils.OperatorNamespace()}
+	if operatorConfig.Spec.BuildAPI != nil {
+		for _, ns := range operatorConfig.Spec.BuildAPI.Tenancy.Namespaces() {
+			if !slices.Contains(shared, ns) {
+				shared = append(shared, ns)
+			}
+		}
+	}
+	if !slices.Contains(shared, namespace) {
+		return []string{namespace}
+	}
+	return shared
+}
+
+// handleQueuedState admits a queued build once it fits within the OperatorConfig
+// concurrency limits, and otherwise records its position in the queue. When the
+// preemption policy allows it, the build at the head of the queue preempts a
+// lower-priority running build to make room.
+func (r *ImageBuildReconciler) handleQueuedState(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+) (result ctrl.Result, err error) {
+	ctx, span := ibTracer.Start(ctx, "ImageBuild.HandleQueuedState")
+	defer controllerutils.EndSpanWithError(span, &err)
+	log := r.buildLogger(imageBuild)
+
+	var osBuilds *automotivev1alpha1.OSBuildsConfig
+	operatorConfig := r.queueOperatorConfig(ctx)
+	if operatorConfig != nil {
+		osBuilds = operatorConfig.Spec.OSBuilds
+	}
+	if osBuilds != nil && imageBuild.Spec.Priority != "" && osBuilds.GetPriorityClass(imageBuild.Spec.Priority) == nil {
+		return ctrl.Result{}, r.updateStatus(ctx, imageBuild, phaseFailed,
+			fmt.Sprintf("Build configuration error: unknown priority class %q", imageBuild.Spec.Priority))
+	}
+
+	if osBuilds != nil && osBuilds.BuildQueue.IsLimited() {
+		// Read through the API server so builds admitted by the previous
+		// reconcile are counted even if the cache has not caught up yet.
+		reader := r.APIReader
+		if reader == nil {
+			reader = r.Client
+		}
+		builds := &automotivev1alpha1.ImageBuildList{}
+		for _, ns := range queueNamespaces(operatorConfig, imageBuild.Namespace) {
+			nsBuilds := &automotivev1alpha1.ImageBuildList{}
+			if err := reader.List(ctx, nsBuilds, client.InNamespace(ns)); err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to list image builds in %s: %w", ns, err)
+			}
+			builds.Items = append(builds.Items, nsBuilds.Items...)
+		}
+
+		plan := planBuildQueue(osBuilds, builds.Items)
+		if !plan.admitted[queueKey(imageBuild)] {
+			position, ok := plan.positions[queueKey(imageBuild)]
+			if !ok {
+				// Not yet visible as queued; check again shortly.
+				return ctrl.Result{RequeueAfter: queueRequeueInterval}, nil
+			}
+			if position == 1 && osBuilds.BuildQueue.PreemptsLowerPriority() {
+				if victim := selectPreemptionVictim(osBuilds, builds.Items, imageBuild); victim != nil {
+					if err := r.preemptBuild(ctx, victim, imageBuild); err != nil {
+						return ctrl.Result{}, err
+					}
+					return ctrl.Result{Requeue: true}, nil
+				}
+			}
+			if err := r.updateQueuePosition(ctx, imageBuild, position); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: queueRequeueInterval}, nil
+		}
+		log.Info("Admitting build from queue")
+	}
+
+	if err := r.updateStatus(ctx, imageBuild, phaseBuilding, "Build started"); err != nil {
+		log.Error(err, "Failed to update status to Building")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// updateQueuePosition records the queue position of a waiting build. It patches
+// the status directly rather than through updateStatus so that position changes
+// do not emit a phase-change event each time the queue moves.
+func (r *ImageBuildReconciler) updateQueuePosition(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+	position int32,
+) error {
+	fresh := &automotivev1alpha1.ImageBuild{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      imageBuild.Name,
+		Namespace: imageBuild.Namespace,
+	}, fresh); err != nil {
+		return err
+	}
+	message := fmt.Sprintf("Waiting in build queue (position %d)", position)
+	if fresh.Status.Phase != phaseQueued ||
+		(fresh.Status.QueuePosition == position && fresh.Status.Message == message) {
+		return nil
+	}
+
+	patch := client.MergeFrom(fresh.DeepCopy())
+	fresh.Status.QueuePosition = position
+	fresh.Status.Message = message
+	setImageBuildConditions(fresh, phaseQueued, message)
+	return r.Status().Patch(ctx, fresh, patch)
+}
+
+// preemptBuild returns a running build to the queue and deletes its
+// PipelineRuns so the capacity goes to preemptor. The preempted build starts
+// again from the beginning once it is admitted.
+func (r *ImageBuildReconciler) preemptBuild(
+	ctx context.Context,
+	victim, preemptor *automotivev1alpha1.ImageBuild,
+) error {
+	log := r.buildLogger(preemptor)
+
+	fresh := &automotivev1alpha1.ImageBuild{}
+	if err := r.Get(ctx, types.NamespacedName{Name: victim.Name, Namespace: victim.Namespace}, fresh); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if fresh.Status.Phase != phaseBuilding {
+		return nil
+	}
+
+	message := fmt.Sprintf("Preempted by higher-priority build %s; waiting in build queue", preemptor.Name)
+	patch := client.MergeFrom(fresh.DeepCopy())
+	fresh.Status.Phase = phaseQueued
+	fresh.Status.Message = message
+	fresh.Status.PipelineRunName = ""
+	fresh.Status.StartTime = nil
+	fresh.Status.Architectures = nil
+	setImageBuildConditions(fresh, phaseQueued, message)
+	meta.SetStatusCondition(&fresh.Status.Conditions, metav1.Condition{
+		Type:    automotivev1alpha1.ImageBuildConditionPreempted,
+		Status:  metav1.ConditionTrue,
+		Reason:  "PreemptedByHigherPriority",
+		Message: message,
+	})
+	if err := r.Status().Patch(ctx, fresh, patch); err != nil {
+		return fmt.Errorf("failed to requeue preempted build %s: %w", victim.Name, err)
+	}
+	adjustActiveBuildsGauge(phaseBuilding, phaseQueued)
+	r.notifyPhaseChange(ctx, fresh, phaseBuilding)
+
+	pipelineRuns := &tektonv1.PipelineRunList{}
+	if err := r.List(ctx, pipelineRuns,
+		client.InNamespace(victim.Namespace),
+		client.MatchingLabels{automotivev1alpha1.LabelImageBuildName: victim.Name}); err != nil {
+		return fmt.Errorf("failed to list pipeline runs of preempted build %s: %w", victim.Name, err)
+	}
+	for i := range pipelineRuns.Items {
+		if err := r.Delete(ctx, &pipelineRuns.Items[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil &&
+			!errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete pipeline run %s of preempted build %s: %w",
+				pipelineRuns.Items[i].Name, victim.Name, err)
+		}
+	}
+
+	log.Info("Preempted lower-priority build", "preempted", victim.Name)
+	r.emitEventf(fresh, corev1.EventTypeWarning, eventReasonBuildPreempted, "%s", message)
+	r.emitEventf(preemptor, corev1.EventTypeNormal, eventReasonBuildPreempted,
+		"Preempted lower-priority build %s", victim.Name)
+	return nil
+}
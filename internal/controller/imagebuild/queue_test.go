@@ -0,0 +1,369 @@
+package imagebuild
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	controllerutils "github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/controllerutils"
+	"github.com/go-logr/logr"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var queueEpoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func queuedBuild(name, requester, arch, phase string, ageMinutes int) automotivev1alpha1.ImageBuild {
+	return automotivev1alpha1.ImageBuild{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "test-ns",
+			CreationTimestamp: metav1.NewTime(queueEpoch.Add(-time.Duration(ageMinutes) * time.Minute)),
+			Annotations:       map[string]string{automotivev1alpha1.AnnotationRequestedBy: requester},
+		},
+		Spec:   automotivev1alpha1.ImageBuildSpec{Architecture: arch},
+		Status: automotivev1alpha1.ImageBuildStatus{Phase: phase},
+	}
+}
+
+func queueLimits(queue *automotivev1alpha1.BuildQueueConfig) *automotivev1alpha1.OSBuildsConfig {
+	return &automotivev1alpha1.OSBuildsConfig{Enabled: true, BuildQueue: queue}
+}
+
+func TestPlanBuildQueue_FairOrderingAcrossRequesters(t *testing.T) {
+	builds := []automotivev1alpha1.ImageBuild{
+		queuedBuild("alice-1", "alice", "amd64", phaseQueued, 30),
+		queuedBuild("alice-2", "alice", "amd64", phaseQueued, 29),
+		queuedBuild("alice-3", "alice", "amd64", phaseQueued, 28),
+		queuedBuild("bob-1", "bob", "amd64", phaseQueued, 10),
+		queuedBuild("carol-1", "carol", "amd64", phaseQueued, 5),
+	}
+	plan := planBuildQueue(queueLimits(&automotivev1alpha1.BuildQueueConfig{MaxConcurrentBuilds: 1}), builds)
+
+	if !plan.admitted["test-ns/alice-1"] || len(plan.admitted) != 1 {
+		t.Fatalf("expected only alice-1 to be admitted, got %v", plan.admitted)
+	}
+	want := map[string]int32{"test-ns/bob-1": 1, "test-ns/carol-1": 2, "test-ns/alice-2": 3, "test-ns/alice-3": 4}
+	for name, position := range want {
+		if plan.positions[name] != position {
+			t.Errorf("position of %s = %d, want %d (all: %v)", name, plan.positions[name], position, plan.positions)
+		}
+	}
+}
+
+func TestPlanBuildQueue_RunningBuildsCountTowardFairness(t *testing.T) {
+	builds := []automotivev1alpha1.ImageBuild{
+		queuedBuild("alice-running", "alice", "amd64", phaseBuilding, 60),
+		queuedBuild("alice-1", "alice", "amd64", phaseQueued, 30),
+		queuedBuild("bob-1", "bob", "amd64", phaseQueued, 10),
+	}
+	plan := planBuildQueue(queueLimits(&automotivev1alpha1.BuildQueueConfig{MaxConcurrentBuilds: 2}), builds)
+
+	if !plan.admitted["test-ns/bob-1"] || plan.admitted["test-ns/alice-1"] {
+		t.Errorf("expected bob-1 to be admitted ahead of alice-1, got admitted=%v", plan.admitted)
+	}
+	if plan.positions["test-ns/alice-1"] != 1 {
+		t.Errorf("position of alice-1 = %d, want 1", plan.positions["test-ns/alice-1"])
+	}
+}
+
+func TestPlanBuildQueue_PerUserLimit(t *testing.T) {
+	builds := []automotivev1alpha1.ImageBuild{
+		queuedBuild("alice-running", "alice", "amd64", automotivev1alpha1.ImageBuildPhasePushing, 60),
+		queuedBuild("alice-1", "alice", "amd64", phaseQueued, 30),
+		queuedBuild("bob-1", "bob", "amd64", phaseQueued, 10),
+	}
+	plan := planBuildQueue(queueLimits(&automotivev1alpha1.BuildQueueConfig{MaxConcurrentBuildsPerUser: 1}), builds)
+
+	if plan.admitted["test-ns/alice-1"] {
+		t.Error("alice-1 should wait for alice's running build")
+	}
+	if !plan.admitted["test-ns/bob-1"] {
+		t.Error("bob-1 should not be blocked by alice's limit")
+	}
+}
+
+func TestPlanBuildQueue_PerArchitectureLimit(t *testing.T) {
+	multi := queuedBuild("multi", "carol", "", phaseQueued, 5)
+	multi.Spec.Architectures = []string{"amd64", "arm64"}
+	builds := []automotivev1alpha1.ImageBuild{
+		queuedBuild("arm-running", "alice", "arm64", phaseBuilding, 60),
+		queuedBuild("arm-1", "bob", "arm64", phaseQueued, 30),
+		queuedBuild("amd-1", "bob", "amd64", phaseQueued, 20),
+		multi,
+		// Flashing builds hold a device, not a build slot.
+		queuedBuild("arm-flashing", "dave", "arm64", automotivev1alpha1.ImageBuildPhaseFlashing, 90),
+	}
+	cfg := &automotivev1alpha1.BuildQueueConfig{
+		MaxConcurrentBuildsPerArchitecture: map[string]int32{"arm64": 1},
+	}
+	plan := planBuildQueue(queueLimits(cfg), builds)
+
+	if plan.admitted["test-ns/arm-1"] || plan.admitted["test-ns/multi"] {
+		t.Errorf("arm64 builds should wait for the running arm64 build, got admitted=%v", plan.admitted)
+	}
+	if !plan.admitted["test-ns/amd-1"] {
+		t.Error("amd64 builds should not be limited by the arm64 limit")
+	}
+	if plan.positions["test-ns/arm-1"] != 1 || plan.positions["test-ns/multi"] != 2 {
+		t.Errorf("unexpected positions %v", plan.positions)
+	}
+}
+
+func newQueueReconciler(queue *automotivev1alpha1.BuildQueueConfig, builds ...automotivev1alpha1.ImageBuild) *ImageBuildReconciler {
+	scheme := newTestSchemeWithTekton()
+	builder := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&automotivev1alpha1.ImageBuild{})
+	for i := range builds {
+		builder = builder.WithObjects(&builds[i])
+	}
+	if queue != nil {
+		builder = builder.WithObjects(&automotivev1alpha1.OperatorConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: controllerutils.OperatorNamespace()},
+			Spec: automotivev1alpha1.OperatorConfigSpec{
+				OSBuilds: &automotivev1alpha1.OSBuildsConfig{Enabled: true, BuildQueue: queue},
+			},
+		})
+	}
+	return &ImageBuildReconciler{
+		Client:   builder.Build(),
+		Scheme:   scheme,
+		Log:      logr.Discard(),
+		Recorder: record.NewFakeRecorder(100),
+	}
+}
+
+func getTestBuild(t *testing.T, c client.Client, name string) *automotivev1alpha1.ImageBuild {
+	t.Helper()
+	ib := &automotivev1alpha1.ImageBuild{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "test-ns"}, ib); err != nil {
+		t.Fatalf("get %s: %v", name, err)
+	}
+	return ib
+}
+
+func TestHandleQueuedState_AdmitsWithoutLimits(t *testing.T) {
+	build := queuedBuild("my-build", "alice", "amd64", phaseQueued, 1)
+	r := newQueueReconciler(nil, build)
+
+	result, err := r.handleQueuedState(context.Background(), &build)
+	if err != nil {
+		t.Fatalf("handleQueuedState() error = %v", err)
+	}
+	if result != (ctrl.Result{Requeue: true}) {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if got := getTestBuild(t, r.Client, "my-build"); got.Status.Phase != phaseBuilding || got.Status.StartTime == nil {
+		t.Errorf("expected build to start, got phase=%q startTime=%v", got.Status.Phase, got.Status.StartTime)
+	}
+}
+
+func TestHandleQueuedState_RecordsPositionThenAdmits(t *testing.T) {
+	running := queuedBuild("running", "bob", "amd64", phaseBuilding, 10)
+	build := queuedBuild("my-build", "alice", "amd64", phaseQueued, 1)
+	r := newQueueReconciler(&automotivev1alpha1.BuildQueueConfig{MaxConcurrentBuilds: 1}, running, build)
+
+	result, err := r.handleQueuedState(context.Background(), &build)
+	if err != nil {
+		t.Fatalf("handleQueuedState() error = %v", err)
+	}
+	if result.RequeueAfter != queueRequeueInterval {
+		t.Errorf("expected requeue after %v, got %+v", queueRequeueInterval, result)
+	}
+	got := getTestBuild(t, r.Client, "my-build")
+	if got.Status.Phase != phaseQueued || got.Status.QueuePosition != 1 {
+		t.Fatalf("expected queued at position 1, got phase=%q position=%d", got.Status.Phase, got.Status.QueuePosition)
+	}
+	if got.Status.Message != "Waiting in build queue (position 1)" {
+		t.Errorf("unexpected message %q", got.Status.Message)
+	}
+
+	if err := r.updateStatus(context.Background(), &running, phaseCompleted, "done"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.handleQueuedState(context.Background(), got); err != nil {
+		t.Fatalf("handleQueuedState() error = %v", err)
+	}
+	got = getTestBuild(t, r.Client, "my-build")
+	if got.Status.Phase != phaseBuilding || got.Status.QueuePosition != 0 {
+		t.Errorf("expected build to start and clear its position, got phase=%q position=%d",
+			got.Status.Phase, got.Status.QueuePosition)
+	}
+}
+
+func TestHandleQueuedState_LimitsSpanTenantNamespaces(t *testing.T) {
+	running := queuedBuild("running", "bob", "amd64", phaseBuilding, 10)
+	running.Namespace = "team-b"
+	elsewhere := queuedBuild("elsewhere", "carol", "amd64", phaseBuilding, 10)
+	elsewhere.Namespace = "unrelated"
+	build := queuedBuild("my-build", "alice", "amd64", phaseQueued, 1)
+	r := newQueueReconciler(&automotivev1alpha1.BuildQueueConfig{MaxConcurrentBuilds: 1}, running, elsewhere, build)
+	config := &automotivev1alpha1.OperatorConfig{}
+	if err := r.Get(context.Background(), types.NamespacedName{Name: "config", Namespace: controllerutils.OperatorNamespace()}, config); err != nil {
+		t.Fatal(err)
+	}
+	config.Spec.BuildAPI = &automotivev1alpha1.BuildAPIConfig{Tenancy: &automotivev1alpha1.TenancyConfig{
+		Tenants: []automotivev1alpha1.TenantConfig{{Namespace: "test-ns"}, {Namespace: "team-b"}},
+	}}
+	if err := r.Update(context.Background(), config); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.handleQueuedState(context.Background(), &build); err != nil {
+		t.Fatalf("handleQueuedState() error = %v", err)
+	}
+	if got := getTestBuild(t, r.Client, "my-build"); got.Status.Phase != phaseQueued || got.Status.QueuePosition != 1 {
+		t.Errorf("expected the build to wait for the other tenant's build, got phase=%q position=%d",
+			got.Status.Phase, got.Status.QueuePosition)
+	}
+
+	queued := queuedBuild("queued", "dave", "amd64", phaseQueued, 1)
+	queued.Namespace = "unrelated"
+	if err := r.Create(context.Background(), &queued); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.handleQueuedState(context.Background(), &queued); err != nil {
+		t.Fatalf("handleQueuedState() error = %v", err)
+	}
+	got := &automotivev1alpha1.ImageBuild{}
+	if err := r.Get(context.Background(), types.NamespacedName{Name: "queued", Namespace: "unrelated"}, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.Phase != phaseQueued || got.Status.QueuePosition != 1 {
+		t.Errorf("expected builds outside the tenants to be queued per namespace, got phase=%q position=%d",
+			got.Status.Phase, got.Status.QueuePosition)
+	}
+}
+
+func priorityClasses(queue *automotivev1alpha1.BuildQueueConfig) *automotivev1alpha1.OSBuildsConfig {
+	osBuilds := queueLimits(queue)
+	osBuilds.PriorityClasses = []automotivev1alpha1.BuildPriorityClass{
+		{Name: "release", Value: 1000, PriorityClassName: "build-release"},
+		{Name: "normal", Value: 100, Default: true},
+		{Name: "experiment", Value: 10},
+	}
+	return osBuilds
+}
+
+func withPriority(build automotivev1alpha1.ImageBuild, priority string) automotivev1alpha1.ImageBuild {
+	build.Spec.Priority = priority
+	return build
+}
+
+func TestPlanBuildQueue_HigherPriorityFirst(t *testing.T) {
+	builds := []automotivev1alpha1.ImageBuild{
+		withPriority(queuedBuild("alice-experiment", "alice", "amd64", phaseQueued, 30), "experiment"),
+		queuedBuild("bob-default", "bob", "amd64", phaseQueued, 20),
+		withPriority(queuedBuild("alice-release", "alice", "amd64", phaseQueued, 1), "release"),
+	}
+	plan := planBuildQueue(priorityClasses(&automotivev1alpha1.BuildQueueConfig{MaxConcurrentBuilds: 1}), builds)
+
+	if !plan.admitted["test-ns/alice-release"] {
+		t.Fatalf("expected the release build to be admitted first, got admitted=%v", plan.admitted)
+	}
+	if plan.positions["test-ns/bob-default"] != 1 || plan.positions["test-ns/alice-experiment"] != 2 {
+		t.Errorf("unexpected positions %v", plan.positions)
+	}
+}
+
+func TestSelectPreemptionVictim(t *testing.T) {
+	started := func(build automotivev1alpha1.ImageBuild, minutesAgo int) automotivev1alpha1.ImageBuild {
+		start := metav1.NewTime(queueEpoch.Add(-time.Duration(minutesAgo) * time.Minute))
+		build.Status.StartTime = &start
+		return build
+	}
+	release := withPriority(queuedBuild("release", "carol", "amd64", phaseQueued, 1), "release")
+	builds := []automotivev1alpha1.ImageBuild{
+		started(withPriority(queuedBuild("exp-old", "alice", "amd64", phaseBuilding, 60), "experiment"), 50),
+		started(withPriority(queuedBuild("exp-new", "bob", "amd64", phaseBuilding, 30), "experiment"), 5),
+		started(queuedBuild("normal", "dave", "amd64", phaseBuilding, 60), 40),
+		release,
+	}
+	osBuilds := priorityClasses(&automotivev1alpha1.BuildQueueConfig{MaxConcurrentBuilds: 3})
+
+	victim := selectPreemptionVictim(osBuilds, builds, &release)
+	if victim == nil || victim.Name != "exp-new" {
+		t.Fatalf("expected the most recently started experiment build to be preempted, got %v", victim)
+	}
+
+	normal := queuedBuild("normal-queued", "erin", "amd64", phaseQueued, 1)
+	if victim := selectPreemptionVictim(osBuilds, builds, &normal); victim == nil || victim.Name != "exp-new" {
+		t.Errorf("expected a default-priority build to preempt an experiment, got %v", victim)
+	}
+
+	experiment := withPriority(queuedBuild("exp-queued", "erin", "amd64", phaseQueued, 1), "experiment")
+	if victim := selectPreemptionVictim(osBuilds, builds, &experiment); victim != nil {
+		t.Errorf("builds must not preempt equal-priority builds, got %s", victim.Name)
+	}
+}
+
+func TestHandleQueuedState_PreemptsLowerPriorityBuild(t *testing.T) {
+	running := withPriority(queuedBuild("experiment", "alice", "amd64", phaseBuilding, 30), "experiment")
+	running.Status.PipelineRunName = "experiment-build-abc"
+	release := withPriority(queuedBuild("release", "bob", "amd64", phaseQueued, 1), "release")
+
+	queue := &automotivev1alpha1.BuildQueueConfig{
+		MaxConcurrentBuilds: 1,
+		Preemption:          automotivev1alpha1.BuildPreemptionPreemptLowerPriority,
+	}
+	r := newQueueReconciler(queue, running, release)
+	config := &automotivev1alpha1.OperatorConfig{}
+	if err := r.Get(context.Background(), types.NamespacedName{Name: "config", Namespace: controllerutils.OperatorNamespace()}, config); err != nil {
+		t.Fatal(err)
+	}
+	config.Spec.OSBuilds.PriorityClasses = priorityClasses(nil).PriorityClasses
+	if err := r.Update(context.Background(), config); err != nil {
+		t.Fatal(err)
+	}
+	pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Name:      "experiment-build-abc",
+		Namespace: "test-ns",
+		Labels:    map[string]string{automotivev1alpha1.LabelImageBuildName: "experiment"},
+	}}
+	if err := r.Create(context.Background(), pr); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.handleQueuedState(context.Background(), &release); err != nil {
+		t.Fatalf("handleQueuedState() error = %v", err)
+	}
+
+	preempted := getTestBuild(t, r.Client, "experiment")
+	if preempted.Status.Phase != phaseQueued || preempted.Status.PipelineRunName != "" || preempted.Status.StartTime != nil {
+		t.Errorf("expected the experiment build to be requeued, got %+v", preempted.Status)
+	}
+	cond := meta.FindStatusCondition(preempted.Status.Conditions, automotivev1alpha1.ImageBuildConditionPreempted)
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		t.Errorf("expected a Preempted condition, got %v", preempted.Status.Conditions)
+	}
+	if err := r.Get(context.Background(), client.ObjectKeyFromObject(pr), &tektonv1.PipelineRun{}); !errors.IsNotFound(err) {
+		t.Errorf("expected the preempted PipelineRun to be deleted, got %v", err)
+	}
+
+	if _, err := r.handleQueuedState(context.Background(), &release); err != nil {
+		t.Fatalf("handleQueuedState() error = %v", err)
+	}
+	if got := getTestBuild(t, r.Client, "release"); got.Status.Phase != phaseBuilding {
+		t.Errorf("expected the release build to start after preemption, got %q", got.Status.Phase)
+	}
+}
+
+func TestHandleQueuedState_UnknownPriorityFails(t *testing.T) {
+	build := withPriority(queuedBuild("my-build", "alice", "amd64", phaseQueued, 1), "urgent")
+	r := newQueueReconciler(&automotivev1alpha1.BuildQueueConfig{}, build)
+
+	if _, err := r.handleQueuedState(context.Background(), &build); err != nil {
+		t.Fatalf("handleQueuedState() error = %v", err)
+	}
+	if got := getTestBuild(t, r.Client, "my-build"); got.Status.Phase != phaseFailed {
+		t.Errorf("expected an unknown priority to fail the build, got %q", got.Status.Phase)
+	}
+}
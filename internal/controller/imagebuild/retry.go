@@ -0,0 +1,309 @@
+package imagebuild
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	eventReasonBuildRetrying = "BuildRetrying"
+
+	pipelineTaskBuildImage = "build-image"
+	pipelineTaskPushDisk   = "push-disk-artifact"
+)
+
+// failurePatterns maps failure classes to lower-case substrings of TaskRun
+// failure messages that identify them. Registry errors are checked before
+// mirror errors because both can report HTTP 5xx status codes.
+var failurePatterns = []struct {
+	class    string
+	patterns []string
+}{
+	{
+		class: automotivev1alpha1.FailureClassRegistryServerError,
+		patterns: []string{
+			"500 internal server error",
+			"502 bad gateway",
+			"503 service unavailable",
+			"504 gateway timeout",
+			"received unexpected http status: 5",
+			"toomanyrequests",
+		},
+	},
+	{
+		class: automotivev1alpha1.FailureClassMirrorDownload,
+		patterns: []string{
+			"curl error",
+			"cannot download",
+			"failed to download",
+			"no more mirrors to try",
+			"could not resolve host",
+			"connection reset by peer",
+			"connection timed out",
+			"status code: 5",
+		},
+	},
+}
+
+// classifyBuildFailure returns the failure class for a failed TaskRun or
+// PipelineRun condition, or "" when the failure is not known to be transient.
+func classifyBuildFailure(reason, message string) string {
+	if reason == string(tektonv1.TaskRunReasonTimedOut) || reason == string(tektonv1.PipelineRunReasonTimedOut) {
+		return automotivev1alpha1.FailureClassTimeout
+	}
+	lower := strings.ToLower(message)
+	for _, fp := range failurePatterns {
+		for _, pattern := range fp.patterns {
+			if strings.Contains(lower, pattern) {
+				return fp.class
+			}
+		}
+	}
+	return ""
+}
+
+// taskRunFailureReason returns the reason of a failed TaskRun's Succeeded condition.
+func taskRunFailureReason(taskRun *tektonv1.TaskRun) string {
+	for _, condition := range taskRun.Status.Conditions {
+		if condition.Type == conditionSucceeded && condition.Status != corev1.ConditionTrue {
+			return condition.Reason
+		}
+	}
+	return ""
+}
+
+// pipelineRunFailureReason returns the reason of a failed PipelineRun's Succeeded condition.
+func pipelineRunFailureReason(pipelineRun *tektonv1.PipelineRun) string {
+	for _, condition := range pipelineRun.Status.Conditions {
+		if condition.Type == conditionSucceeded && condition.Status != corev1.ConditionTrue {
+			return condition.Reason
+		}
+	}
+	return ""
+}
+
+// failedChildTaskRun returns the first failed TaskRun of a PipelineRun and the
+// name of its pipeline task, or nil if none of the child TaskRuns failed.
+func (r *ImageBuildReconciler) failedChildTaskRun(
+	ctx context.Context,
+	pipelineRun *tektonv1.PipelineRun,
+) (*tektonv1.TaskRun, string) {
+	for _, child := range pipelineRun.Status.ChildReferences {
+		taskRun := &tektonv1.TaskRun{}
+		if err := r.Get(ctx, types.NamespacedName{
+			Name:      child.Name,
+			Namespace: pipelineRun.Namespace,
+		}, taskRun); err != nil {
+			continue
+		}
+		if isTaskRunCompleted(taskRun) && !isTaskRunSuccessful(taskRun) {
+			return taskRun, child.PipelineTaskName
+		}
+	}
+	return nil, ""
+}
+
+// childTaskRunResult returns a result of the named pipeline task's TaskRun.
+func (r *ImageBuildReconciler) childTaskRunResult(
+	ctx context.Context,
+	pipelineRun *tektonv1.PipelineRun,
+	pipelineTask, result string,
+) string {
+	for _, child := range pipelineRun.Status.ChildReferences {
+		if child.PipelineTaskName != pipelineTask {
+			continue
+		}
+		taskRun := &tektonv1.TaskRun{}
+		if err := r.Get(ctx, types.NamespacedName{
+			Name:      child.Name,
+			Namespace: pipelineRun.Namespace,
+		}, taskRun); err != nil {
+			return ""
+		}
+		for _, res := range taskRun.Status.Results {
+			if res.Name == result {
+				return res.Value.StringVal
+			}
+		}
+	}
+	return ""
+}
+
+// pipelineRunArtifactFilename returns the disk artifact filename of a
+// PipelineRun. Failed PipelineRuns carry no pipeline results, so the
+// build-image TaskRun result is used as a fallback.
+func (r *ImageBuildReconciler) pipelineRunArtifactFilename(ctx context.Context, pipelineRun *tektonv1.PipelineRun) string {
+	if name := extractArtifactFilename(pipelineRun); name != "" {
+		return name
+	}
+	return r.childTaskRunResult(ctx, pipelineRun, pipelineTaskBuildImage, "artifact-filename")
+}
+
+// sharedWorkspaceClaim returns the PVC bound to a TaskRun's shared workspace.
+// Tekton binds VolumeClaimTemplate workspaces to the created PVC by name.
+func sharedWorkspaceClaim(taskRun *tektonv1.TaskRun) string {
+	for _, ws := range taskRun.Spec.Workspaces {
+		if ws.Name == "shared-workspace" && ws.PersistentVolumeClaim != nil {
+			return ws.PersistentVolumeClaim.ClaimName
+		}
+	}
+	return ""
+}
+
+// retryDelay returns how long a build has to wait before its scheduled retry.
+func retryDelay(imageBuild *automotivev1alpha1.ImageBuild) time.Duration {
+	if imageBuild.Status.NextRetryTime == nil {
+		return 0
+	}
+	return time.Until(imageBuild.Status.NextRetryTime.Time)
+}
+
+// isFailedAttempt reports whether a PipelineRun belongs to an earlier failed attempt.
+func isFailedAttempt(imageBuild *automotivev1alpha1.ImageBuild, pipelineRunName string) bool {
+	for _, attempt := range imageBuild.Status.Attempts {
+		if attempt.Stage == automotivev1alpha1.BuildStageBuild && attempt.PipelineRunName == pipelineRunName {
+			return true
+		}
+	}
+	return false
+}
+
+// retryFailedPipelineRun schedules a retry of a failed single-architecture
+// PipelineRun. A failure of the push-disk-artifact task re-runs only the push
+// from the existing workspace; any other failure re-runs the PipelineRun.
+func (r *ImageBuildReconciler) retryFailedPipelineRun(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+	pipelineRun *tektonv1.PipelineRun,
+) (ctrl.Result, bool, error) {
+	if imageBuild.Spec.RetryPolicy == nil {
+		return ctrl.Result{}, false, nil
+	}
+
+	attempt := automotivev1alpha1.BuildAttempt{
+		Stage:           automotivev1alpha1.BuildStageBuild,
+		PipelineRunName: pipelineRun.Name,
+		FailureReason:   r.pipelineRunFailureDetail(ctx, pipelineRun),
+	}
+	reason := pipelineRunFailureReason(pipelineRun)
+
+	var pvcName, artifactFilename string
+	if taskRun, pipelineTask := r.failedChildTaskRun(ctx, pipelineRun); taskRun != nil {
+		attempt.TaskRunName = taskRun.Name
+		reason = taskRunFailureReason(taskRun)
+		if pipelineTask == pipelineTaskPushDisk {
+			pvcName = sharedWorkspaceClaim(taskRun)
+			artifactFilename = r.pipelineRunArtifactFilename(ctx, pipelineRun)
+			// The push can only be re-run on its own when its inputs are known;
+			// otherwise the whole PipelineRun is retried.
+			if pvcName != "" && artifactFilename != "" && imageBuild.Spec.GetPushSecretRef() != "" {
+				attempt.Stage = automotivev1alpha1.BuildStagePush
+			}
+		}
+	}
+	attempt.FailureClass = classifyBuildFailure(reason, attempt.FailureReason)
+
+	return r.scheduleRetry(ctx, imageBuild, attempt, func(fresh *automotivev1alpha1.ImageBuild) {
+		if attempt.Stage == automotivev1alpha1.BuildStagePush {
+			fresh.Status.Phase = automotivev1alpha1.ImageBuildPhasePushing
+			fresh.Status.PVCName = pvcName
+			fresh.Status.PushTaskRunName = ""
+			return
+		}
+		fresh.Status.PipelineRunName = ""
+	})
+}
+
+// retryFailedPush schedules a retry of a failed push TaskRun.
+func (r *ImageBuildReconciler) retryFailedPush(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+	taskRun *tektonv1.TaskRun,
+) (ctrl.Result, bool, error) {
+	if imageBuild.Spec.RetryPolicy == nil {
+		return ctrl.Result{}, false, nil
+	}
+
+	attempt := automotivev1alpha1.BuildAttempt{
+		Stage:           automotivev1alpha1.BuildStagePush,
+		PipelineRunName: imageBuild.Status.PipelineRunName,
+		TaskRunName:     taskRun.Name,
+		FailureReason:   taskRunFailureMessage(taskRun, "Push to registry failed"),
+	}
+	attempt.FailureClass = classifyBuildFailure(taskRunFailureReason(taskRun), attempt.FailureReason)
+
+	return r.scheduleRetry(ctx, imageBuild, attempt, func(fresh *automotivev1alpha1.ImageBuild) {
+		fresh.Status.PushTaskRunName = ""
+	})
+}
+
+// scheduleRetry records a failed attempt in status. When the retry policy
+// covers the failure class and attempts remain, it also applies reset to
+// restart the failed stage after the policy backoff and reports true.
+func (r *ImageBuildReconciler) scheduleRetry(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+	attempt automotivev1alpha1.BuildAttempt,
+	reset func(fresh *automotivev1alpha1.ImageBuild),
+) (ctrl.Result, bool, error) {
+	fresh := &automotivev1alpha1.ImageBuild{}
+	if err := r.Get(ctx, types.NamespacedName{Name: imageBuild.Name, Namespace: imageBuild.Namespace}, fresh); err != nil {
+		return ctrl.Result{}, false, err
+	}
+	if fresh.Status.Phase == phaseCancelled || fresh.Spec.RetryPolicy == nil {
+		return ctrl.Result{}, false, nil
+	}
+	policy := fresh.Spec.RetryPolicy
+
+	patch := client.MergeFrom(fresh.DeepCopy())
+	now := metav1.Now()
+	attempt.Attempt = int32(len(fresh.Status.Attempts)) + 1
+	attempt.CompletionTime = &now
+	fresh.Status.Attempts = append(fresh.Status.Attempts, attempt)
+
+	retry := policy.Retries(attempt.FailureClass) && attempt.Attempt < policy.MaxAttempts
+	var backoff time.Duration
+	oldPhase := fresh.Status.Phase
+	if retry {
+		backoff = policy.GetBackoff(int(attempt.Attempt))
+		next := metav1.NewTime(now.Add(backoff))
+		fresh.Status.NextRetryTime = &next
+		fresh.Status.Message = fmt.Sprintf("Retrying %s stage (attempt %d of %d) in %s after %s failure",
+			strings.ToLower(attempt.Stage), attempt.Attempt+1, policy.MaxAttempts, backoff, attempt.FailureClass)
+		reset(fresh)
+	}
+
+	if err := r.Status().Patch(ctx, fresh, patch); err != nil {
+		return ctrl.Result{}, false, fmt.Errorf("failed to record build attempt: %w", err)
+	}
+	if !retry {
+		return ctrl.Result{}, false, nil
+	}
+	adjustActiveBuildsGauge(oldPhase, fresh.Status.Phase)
+
+	r.emitEventf(
+		fresh,
+		corev1.EventTypeWarning,
+		eventReasonBuildRetrying,
+		"Attempt %d failed with a %s error, retrying %s stage in %s: %s",
+		attempt.Attempt,
+		attempt.FailureClass,
+		strings.ToLower(attempt.Stage),
+		backoff,
+		attempt.FailureReason,
+	)
+	if backoff <= 0 {
+		return ctrl.Result{Requeue: true}, true, nil
+	}
+	return ctrl.Result{RequeueAfter: backoff}, true, nil
+}
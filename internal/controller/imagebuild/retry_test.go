@@ -0,0 +1,276 @@
+package imagebuild
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/go-logr/logr"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	knativev1 "knative.dev/pkg/apis/duck/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestClassifyBuildFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		reason  string
+		message string
+		want    string
+	}{
+		{
+			name:    "dnf mirror download",
+			message: "Image build failed: Curl error (28): Timeout was reached for https://mirror.example.com/repodata",
+			want:    automotivev1alpha1.FailureClassMirrorDownload,
+		},
+		{
+			name:    "mirror 5xx",
+			message: "Image build failed: Status code: 503 for https://mirror.example.com/Packages/vim.rpm",
+			want:    automotivev1alpha1.FailureClassMirrorDownload,
+		},
+		{
+			name:    "registry 5xx during push",
+			message: "Disk image push failed: received unexpected HTTP status: 502 Bad Gateway",
+			want:    automotivev1alpha1.FailureClassRegistryServerError,
+		},
+		{
+			name:   "task timeout",
+			reason: string(tektonv1.TaskRunReasonTimedOut),
+			want:   automotivev1alpha1.FailureClassTimeout,
+		},
+		{
+			name:    "manifest error is not transient",
+			message: "Image build failed: step build exited with code 1",
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyBuildFailure(tt.reason, tt.message); got != tt.want {
+				t.Errorf("classifyBuildFailure() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func failedPipelineRun(name string, children ...tektonv1.ChildStatusReference) *tektonv1.PipelineRun {
+	now := metav1.Now()
+	return &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-ns"},
+		Status: tektonv1.PipelineRunStatus{
+			Status: knativev1.Status{
+				Conditions: knativev1.Conditions{{
+					Type:   conditionSucceeded,
+					Status: corev1.ConditionFalse,
+					Reason: string(tektonv1.PipelineRunReasonFailed),
+				}},
+			},
+			PipelineRunStatusFields: tektonv1.PipelineRunStatusFields{
+				CompletionTime:  &now,
+				ChildReferences: children,
+			},
+		},
+	}
+}
+
+func retryingBuild(policy *automotivev1alpha1.RetryPolicy, pipelineRunName string) *automotivev1alpha1.ImageBuild {
+	return &automotivev1alpha1.ImageBuild{
+		ObjectMeta: metav1.ObjectMeta{Name: "retry-build", Namespace: "test-ns"},
+		Spec: automotivev1alpha1.ImageBuildSpec{
+			Architecture:  "amd64",
+			PushSecretRef: "push-secret",
+			RetryPolicy:   policy,
+		},
+		Status: automotivev1alpha1.ImageBuildStatus{
+			Phase:           phaseBuilding,
+			PipelineRunName: pipelineRunName,
+		},
+	}
+}
+
+func newRetryReconciler(ib *automotivev1alpha1.ImageBuild, objs ...client.Object) *ImageBuildReconciler {
+	scheme := newTestSchemeWithTekton()
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(ib).
+		WithObjects(append(objs, ib)...).
+		Build()
+	return &ImageBuildReconciler{
+		Client:   c,
+		Scheme:   scheme,
+		Log:      logr.Discard(),
+		Recorder: record.NewFakeRecorder(20),
+	}
+}
+
+func getRetryBuild(t *testing.T, r *ImageBuildReconciler) *automotivev1alpha1.ImageBuild {
+	t.Helper()
+	ib := &automotivev1alpha1.ImageBuild{}
+	if err := r.Get(context.Background(), types.NamespacedName{Name: "retry-build", Namespace: "test-ns"}, ib); err != nil {
+		t.Fatalf("get ImageBuild: %v", err)
+	}
+	return ib
+}
+
+func TestCheckBuildProgress_RetriesTransientBuildFailure(t *testing.T) {
+	ib := retryingBuild(&automotivev1alpha1.RetryPolicy{MaxAttempts: 3, Backoff: "1m"}, "retry-build-pr-1")
+	pr := failedPipelineRun("retry-build-pr-1",
+		tektonv1.ChildStatusReference{Name: "retry-build-pr-1-build", PipelineTaskName: pipelineTaskBuildImage})
+	build := testTaskRun("retry-build-pr-1-build", true, "Curl error (6): Couldn't resolve host name")
+	r := newRetryReconciler(ib, pr, build)
+
+	result, err := r.checkBuildProgress(context.Background(), ib)
+	if err != nil {
+		t.Fatalf("checkBuildProgress() error = %v", err)
+	}
+	if result.RequeueAfter != time.Minute {
+		t.Errorf("RequeueAfter = %s, want 1m", result.RequeueAfter)
+	}
+
+	got := getRetryBuild(t, r)
+	if got.Status.Phase != phaseBuilding || got.Status.PipelineRunName != "" {
+		t.Errorf("expected Building with cleared PipelineRun, got phase=%s pipelineRun=%q",
+			got.Status.Phase, got.Status.PipelineRunName)
+	}
+	if got.Status.NextRetryTime == nil {
+		t.Error("expected NextRetryTime to be set")
+	}
+	if len(got.Status.Attempts) != 1 {
+		t.Fatalf("expected 1 recorded attempt, got %+v", got.Status.Attempts)
+	}
+	attempt := got.Status.Attempts[0]
+	if attempt.Attempt != 1 || attempt.Stage != automotivev1alpha1.BuildStageBuild ||
+		attempt.PipelineRunName != "retry-build-pr-1" || attempt.TaskRunName != "retry-build-pr-1-build" ||
+		attempt.FailureClass != automotivev1alpha1.FailureClassMirrorDownload {
+		t.Errorf("unexpected attempt %+v", attempt)
+	}
+
+	// While waiting for the retry, the failed PipelineRun must not be picked up again.
+	result, err = r.handleBuildingState(context.Background(), got)
+	if err != nil {
+		t.Fatalf("handleBuildingState() error = %v", err)
+	}
+	if result.RequeueAfter <= 0 || result.RequeueAfter > time.Minute {
+		t.Errorf("expected to wait for the retry backoff, got %+v", result)
+	}
+	if !isFailedAttempt(got, "retry-build-pr-1") {
+		t.Error("expected the failed PipelineRun to be recognised as an earlier attempt")
+	}
+}
+
+func TestCheckBuildProgress_RetriesOnlyFailedPush(t *testing.T) {
+	ib := retryingBuild(&automotivev1alpha1.RetryPolicy{MaxAttempts: 2}, "retry-build-pr-1")
+	pr := failedPipelineRun("retry-build-pr-1",
+		tektonv1.ChildStatusReference{Name: "retry-build-pr-1-build", PipelineTaskName: pipelineTaskBuildImage},
+		tektonv1.ChildStatusReference{Name: "retry-build-pr-1-push", PipelineTaskName: pipelineTaskPushDisk})
+	build := testTaskRun("retry-build-pr-1-build", false, "")
+	build.Status.Results = []tektonv1.TaskRunResult{
+		{Name: "artifact-filename", Value: *tektonv1.NewStructuredValues("disk.qcow2")},
+	}
+	push := testTaskRun("retry-build-pr-1-push", true, "received unexpected HTTP status: 503 Service Unavailable")
+	push.Spec.Workspaces = []tektonv1.WorkspaceBinding{{
+		Name:                  "shared-workspace",
+		PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "pvc-1234"},
+	}}
+	r := newRetryReconciler(ib, pr, build, push)
+
+	if _, err := r.checkBuildProgress(context.Background(), ib); err != nil {
+		t.Fatalf("checkBuildProgress() error = %v", err)
+	}
+
+	got := getRetryBuild(t, r)
+	if got.Status.Phase != automotivev1alpha1.ImageBuildPhasePushing {
+		t.Fatalf("expected Pushing phase for a push retry, got %s (%s)", got.Status.Phase, got.Status.Message)
+	}
+	if got.Status.PVCName != "pvc-1234" || got.Status.PipelineRunName != "retry-build-pr-1" {
+		t.Errorf("expected push retry to reuse the workspace, got pvc=%q pipelineRun=%q",
+			got.Status.PVCName, got.Status.PipelineRunName)
+	}
+	if len(got.Status.Attempts) != 1 || got.Status.Attempts[0].Stage != automotivev1alpha1.BuildStagePush ||
+		got.Status.Attempts[0].FailureClass != automotivev1alpha1.FailureClassRegistryServerError {
+		t.Errorf("unexpected attempts %+v", got.Status.Attempts)
+	}
+	if filename := r.pipelineRunArtifactFilename(context.Background(), pr); filename != "disk.qcow2" {
+		t.Errorf("pipelineRunArtifactFilename() = %q, want disk.qcow2", filename)
+	}
+}
+
+func TestCheckBuildProgress_FailsWhenAttemptsExhausted(t *testing.T) {
+	ib := retryingBuild(&automotivev1alpha1.RetryPolicy{MaxAttempts: 2}, "retry-build-pr-2")
+	ib.Status.Attempts = []automotivev1alpha1.BuildAttempt{{
+		Attempt:         1,
+		Stage:           automotivev1alpha1.BuildStageBuild,
+		PipelineRunName: "retry-build-pr-1",
+		FailureClass:    automotivev1alpha1.FailureClassMirrorDownload,
+	}}
+	pr := failedPipelineRun("retry-build-pr-2",
+		tektonv1.ChildStatusReference{Name: "retry-build-pr-2-build", PipelineTaskName: pipelineTaskBuildImage})
+	build := testTaskRun("retry-build-pr-2-build", true, "Curl error (28): Timeout was reached")
+	r := newRetryReconciler(ib, pr, build)
+
+	if _, err := r.checkBuildProgress(context.Background(), ib); err != nil {
+		t.Fatalf("checkBuildProgress() error = %v", err)
+	}
+
+	got := getRetryBuild(t, r)
+	if got.Status.Phase != phaseFailed {
+		t.Errorf("expected Failed after the last attempt, got %s", got.Status.Phase)
+	}
+	if len(got.Status.Attempts) != 2 || got.Status.Attempts[1].PipelineRunName != "retry-build-pr-2" {
+		t.Errorf("expected the final attempt to be recorded, got %+v", got.Status.Attempts)
+	}
+	if got.Status.NextRetryTime != nil {
+		t.Error("expected no retry to be scheduled")
+	}
+}
+
+func TestCheckBuildProgress_DoesNotRetryPermanentFailure(t *testing.T) {
+	ib := retryingBuild(&automotivev1alpha1.RetryPolicy{MaxAttempts: 3}, "retry-build-pr-1")
+	pr := failedPipelineRun("retry-build-pr-1",
+		tektonv1.ChildStatusReference{Name: "retry-build-pr-1-build", PipelineTaskName: pipelineTaskBuildImage})
+	build := testTaskRun("retry-build-pr-1-build", true, "step build exited with code 1")
+	r := newRetryReconciler(ib, pr, build)
+
+	if _, err := r.checkBuildProgress(context.Background(), ib); err != nil {
+		t.Fatalf("checkBuildProgress() error = %v", err)
+	}
+
+	got := getRetryBuild(t, r)
+	if got.Status.Phase != phaseFailed {
+		t.Errorf("expected Failed, got %s", got.Status.Phase)
+	}
+	if len(got.Status.Attempts) != 1 || got.Status.Attempts[0].FailureClass != "" {
+		t.Errorf("expected one unclassified attempt, got %+v", got.Status.Attempts)
+	}
+}
+
+func TestHandlePushingState_RetriesFailedPushTaskRun(t *testing.T) {
+	ib := retryingBuild(&automotivev1alpha1.RetryPolicy{MaxAttempts: 3, Backoff: "0s"}, "retry-build-pr-1")
+	ib.Status.Phase = automotivev1alpha1.ImageBuildPhasePushing
+	ib.Status.PushTaskRunName = "retry-build-push-abc"
+	push := testTaskRun("retry-build-push-abc", true, "received unexpected HTTP status: 500 Internal Server Error")
+	r := newRetryReconciler(ib, push)
+
+	result, err := r.handlePushingState(context.Background(), ib)
+	if err != nil {
+		t.Fatalf("handlePushingState() error = %v", err)
+	}
+	if !result.Requeue {
+		t.Errorf("expected an immediate requeue with zero backoff, got %+v", result)
+	}
+
+	got := getRetryBuild(t, r)
+	if got.Status.Phase != automotivev1alpha1.ImageBuildPhasePushing || got.Status.PushTaskRunName != "" {
+		t.Errorf("expected push to be retried, got phase=%s pushTaskRun=%q", got.Status.Phase, got.Status.PushTaskRunName)
+	}
+	if len(got.Status.Attempts) != 1 || got.Status.Attempts[0].TaskRunName != "retry-build-push-abc" {
+		t.Errorf("unexpected attempts %+v", got.Status.Attempts)
+	}
+}
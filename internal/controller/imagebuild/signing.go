@@ -0,0 +1,45 @@
+package imagebuild
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// signingKeySecretRef returns the Secret holding the cosign key a build is
+// signed with: its own key if set, else the OperatorConfig default. An empty
+// result leaves the sign-artifacts task skipped.
+func signingKeySecretRef(
+	imageBuild *automotivev1alpha1.ImageBuild, operatorConfig *automotivev1alpha1.OperatorConfig,
+) string {
+	if imageBuild.Spec.SigningKeySecretRef != "" {
+		return imageBuild.Spec.SigningKeySecretRef
+	}
+	if operatorConfig == nil {
+		return ""
+	}
+	return operatorConfig.Spec.OSBuilds.GetSigningKeySecretRef()
+}
+
+// signingParams enables the sign-artifacts task when a signing key is set.
+func signingParams(secretRef string) []tektonv1.Param {
+	if secretRef == "" {
+		return nil
+	}
+	return []tektonv1.Param{{
+		Name:  "sign-artifacts",
+		Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "true"},
+	}}
+}
+
+// signingWorkspaces binds the signing key secret used by the sign-artifacts task.
+func signingWorkspaces(secretRef string) []tektonv1.WorkspaceBinding {
+	if secretRef == "" {
+		return nil
+	}
+	return []tektonv1.WorkspaceBinding{{
+		Name:   "signing-key",
+		Secret: &corev1.SecretVolumeSource{SecretName: secretRef},
+	}}
+}
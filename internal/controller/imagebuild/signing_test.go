@@ -0,0 +1,38 @@
+package imagebuild
+
+import (
+	"testing"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+func TestSigningKeySecretRef(t *testing.T) {
+	build := &automotivev1alpha1.ImageBuild{}
+	config := &automotivev1alpha1.OperatorConfig{}
+	if got := signingKeySecretRef(build, config); got != "" {
+		t.Errorf("expected no signing key without configuration, got %q", got)
+	}
+	if signingParams("") != nil || signingWorkspaces("") != nil {
+		t.Error("expected no signing params or workspaces without a key")
+	}
+
+	config.Spec.OSBuilds = &automotivev1alpha1.OSBuildsConfig{
+		Signing: &automotivev1alpha1.ArtifactSigningConfig{KeySecretRef: "cluster-cosign"},
+	}
+	if got := signingKeySecretRef(build, config); got != "cluster-cosign" {
+		t.Errorf("expected the OperatorConfig key, got %q", got)
+	}
+
+	build.Spec.SigningKeySecretRef = "team-cosign"
+	if got := signingKeySecretRef(build, config); got != "team-cosign" {
+		t.Errorf("expected the per-build key to take precedence, got %q", got)
+	}
+
+	if params := paramValues(signingParams("team-cosign")); params["sign-artifacts"] != "true" {
+		t.Errorf("expected sign-artifacts=true, got %+v", params)
+	}
+	bindings := signingWorkspaces("team-cosign")
+	if len(bindings) != 1 || bindings[0].Name != "signing-key" || bindings[0].Secret.SecretName != "team-cosign" {
+		t.Errorf("expected the key secret bound as signing-key, got %+v", bindings)
+	}
+}
@@ -0,0 +1,73 @@
+package imagebuild
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// diskStorageExportParams returns the pipeline params that drive the
+// export-disk-artifact task. It returns nil when the build has no s3, http
+// or pvc export, leaving the task skipped.
+func diskStorageExportParams(export *automotivev1alpha1.DiskExport) []tektonv1.Param {
+	if export == nil {
+		return nil
+	}
+	var url, endpoint, region string
+	switch {
+	case export.S3 != nil:
+		url = export.S3.Bucket + "/" + export.S3.Key
+		endpoint = export.S3.Endpoint
+		region = export.S3.GetRegion()
+	case export.HTTP != nil:
+		url = export.HTTP.URL
+	case export.PVC != nil:
+		url = export.PVC.Path
+	}
+
+	param := func(name, value string) tektonv1.Param {
+		return tektonv1.Param{
+			Name:  name,
+			Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: value},
+		}
+	}
+	params := []tektonv1.Param{
+		param("disk-export-type", export.StorageType()),
+		param("disk-export-url", url),
+	}
+	if endpoint != "" {
+		params = append(params, param("disk-export-endpoint", endpoint), param("disk-export-region", region))
+	}
+	return params
+}
+
+// diskStorageExportWorkspaces binds the credentials secret and, for pvc
+// exports, the destination claim used by the export-disk-artifact task.
+func diskStorageExportWorkspaces(export *automotivev1alpha1.DiskExport) []tektonv1.WorkspaceBinding {
+	if export == nil {
+		return nil
+	}
+	var bindings []tektonv1.WorkspaceBinding
+	secretRef := ""
+	switch {
+	case export.S3 != nil:
+		secretRef = export.S3.SecretRef
+	case export.HTTP != nil:
+		secretRef = export.HTTP.SecretRef
+	case export.PVC != nil:
+		bindings = append(bindings, tektonv1.WorkspaceBinding{
+			Name: "disk-export-pvc",
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: export.PVC.ClaimName,
+			},
+		})
+	}
+	if secretRef != "" {
+		bindings = append(bindings, tektonv1.WorkspaceBinding{
+			Name:   "disk-export-auth",
+			Secret: &corev1.SecretVolumeSource{SecretName: secretRef},
+		})
+	}
+	return bindings
+}
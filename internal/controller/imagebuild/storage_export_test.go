@@ -0,0 +1,82 @@
+package imagebuild
+
+import (
+	"testing"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+func paramValues(params []tektonv1.Param) map[string]string {
+	out := map[string]string{}
+	for _, p := range params {
+		out[p.Name] = p.Value.StringVal
+	}
+	return out
+}
+
+func TestDiskStorageExportParams(t *testing.T) {
+	if got := diskStorageExportParams(nil); got != nil {
+		t.Errorf("expected no params without a storage export, got %+v", got)
+	}
+
+	s3 := paramValues(diskStorageExportParams(&automotivev1alpha1.DiskExport{
+		S3: &automotivev1alpha1.S3Location{
+			Endpoint:  "http://minio.minio.svc:9000",
+			Bucket:    "images",
+			Key:       "rhivos/disk.qcow2",
+			SecretRef: "minio-creds",
+		},
+	}))
+	want := map[string]string{
+		"disk-export-type":     "s3",
+		"disk-export-url":      "images/rhivos/disk.qcow2",
+		"disk-export-endpoint": "http://minio.minio.svc:9000",
+		"disk-export-region":   "us-east-1",
+	}
+	for k, v := range want {
+		if s3[k] != v {
+			t.Errorf("param %s = %q, want %q", k, s3[k], v)
+		}
+	}
+
+	pvc := paramValues(diskStorageExportParams(&automotivev1alpha1.DiskExport{
+		PVC: &automotivev1alpha1.PVCLocation{ClaimName: "images", Path: "builds/disk.raw"},
+	}))
+	if pvc["disk-export-type"] != "pvc" || pvc["disk-export-url"] != "builds/disk.raw" {
+		t.Errorf("unexpected pvc params %+v", pvc)
+	}
+	if _, ok := pvc["disk-export-endpoint"]; ok {
+		t.Error("pvc exports should not set an s3 endpoint")
+	}
+}
+
+func TestDiskStorageExportWorkspaces(t *testing.T) {
+	bindings := diskStorageExportWorkspaces(&automotivev1alpha1.DiskExport{
+		HTTP: &automotivev1alpha1.HTTPLocation{URL: "https://files.example.com/disk.raw", SecretRef: "upload-token"},
+	})
+	if len(bindings) != 1 || bindings[0].Name != "disk-export-auth" || bindings[0].Secret.SecretName != "upload-token" {
+		t.Errorf("expected the credentials secret bound as disk-export-auth, got %+v", bindings)
+	}
+
+	bindings = diskStorageExportWorkspaces(&automotivev1alpha1.DiskExport{
+		PVC: &automotivev1alpha1.PVCLocation{ClaimName: "images", Path: "disk.raw"},
+	})
+	if len(bindings) != 1 || bindings[0].Name != "disk-export-pvc" || bindings[0].PersistentVolumeClaim.ClaimName != "images" {
+		t.Errorf("expected the claim bound as disk-export-pvc, got %+v", bindings)
+	}
+}
+
+func TestValidateMultiArchSpecRejectsStorageExport(t *testing.T) {
+	spec := &automotivev1alpha1.ImageBuildSpec{
+		Architectures: []string{"amd64", "arm64"},
+		Export: &automotivev1alpha1.ExportSpec{
+			Disk: &automotivev1alpha1.DiskExport{
+				PVC: &automotivev1alpha1.PVCLocation{ClaimName: "images", Path: "disk.raw"},
+			},
+		},
+	}
+	if validateMultiArchSpec(spec) == "" {
+		t.Error("expected pvc exports to be rejected for multi-architecture builds")
+	}
+}
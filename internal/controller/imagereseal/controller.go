@@ -40,6 +40,7 @@ import (
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 
 	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/notifications"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/tasks"
 	controllerutils "github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/controllerutils"
 )
@@ -57,6 +58,7 @@ type Reconciler struct {
 	Scheme   *runtime.Scheme
 	Log      logr.Logger
 	Recorder record.EventRecorder
+	Notifier *notifications.Notifier
 }
 
 // +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,namespace=system,resources=imagereseals,verbs=get;list;watch;create;update;patch;delete
@@ -166,6 +168,7 @@ func (r *Reconciler) handlePending(ctx context.Context, sealed *automotivev1alph
 	}
 
 	sealed.Status.StartTime = &metav1.Time{Time: time.Now()}
+	oldPhase := sealed.Status.Phase
 	sealed.Status.Phase = phaseRunning
 	if len(stages) == 1 {
 		sealed.Status.Message = fmt.Sprintf("Running - %s started", stages[0])
@@ -186,6 +189,7 @@ func (r *Reconciler) handlePending(ctx context.Context, sealed *automotivev1alph
 		sealed.Status.PipelineRunName,
 		strings.Join(stages, ","),
 	)
+	r.notifyPhaseChange(ctx, sealed, oldPhase)
 	return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 }
 
@@ -576,6 +580,7 @@ func (r *Reconciler) updateStatus(ctx context.Context, sealed *automotivev1alpha
 			message,
 		)
 		r.emitResealLifecycleEvent(sealed, oldPhase, phase, message)
+		r.notifyPhaseChange(ctx, sealed, oldPhase)
 	}
 	return ctrl.Result{}, nil
 }
@@ -683,6 +688,15 @@ func pipelineTaskStageIndex(taskName string) int {
 	return idx
 }
 
+// notifyPhaseChange sends the transition of sealed into its current phase to the
+// notification targets of the OperatorConfig.
+func (r *Reconciler) notifyPhaseChange(ctx context.Context, sealed *automotivev1alpha1.ImageReseal, oldPhase string) {
+	if oldPhase == sealed.Status.Phase {
+		return
+	}
+	r.Notifier.Notify(ctx, notifications.EventFor(sealed, "ImageReseal", oldPhase, sealed.Status.Phase, sealed.Status.Message))
+}
+
 func eventTypeForResealPhase(phase string) string {
 	if phase == phaseFailed {
 		return corev1.EventTypeWarning
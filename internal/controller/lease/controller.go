@@ -0,0 +1,126 @@
+// Package lease provides the controller that tracks the Jumpstarter leases
+// acquired for builds, flash jobs and workspaces, and releases them once no
+// resource references them anymore.
+package lease
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-logr/logr"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/jumpstarter"
+	controllerutils "github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/controllerutils"
+)
+
+// DefaultSyncInterval is how often leases are claimed and orphans released.
+const DefaultSyncInterval = 2 * time.Minute
+
+// Reconciler periodically claims the leases acquired for the resources of the
+// operator namespace and releases claimed leases whose resources were deleted.
+type Reconciler struct {
+	client.Client
+	Log logr.Logger
+	// Interval between syncs (default DefaultSyncInterval)
+	Interval time.Duration
+}
+
+// +kubebuilder:rbac:groups=jumpstarter.dev,resources=leases,verbs=get;list;patch
+// +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,namespace=system,resources=imagebuilds;flashjobs;workspaces,verbs=get;list
+// +kubebuilder:rbac:groups="",namespace=system,resources=secrets,verbs=get
+
+// Start runs the lease sync until the context is cancelled.
+func (r *Reconciler) Start(ctx context.Context) error {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = DefaultSyncInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		namespaces := append([]string{controllerutils.OperatorNamespace()},
+			controllerutils.TenantNamespaces(ctx, r.Client, controllerutils.OperatorNamespace())...)
+		for _, namespace := range namespaces {
+			if err := r.Sync(ctx, namespace); err != nil {
+				if errors.Is(err, jumpstarter.ErrNotInstalled) {
+					r.Log.V(1).Info("Jumpstarter is not installed, skipping lease sync")
+					break
+				}
+				r.Log.Error(err, "Lease sync failed", "namespace", namespace)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync claims the leases acquired for the builds, flash jobs and workspaces
+// of namespace and releases the claimed leases none of them references.
+func (r *Reconciler) Sync(ctx context.Context, namespace string) error {
+	owners, err := jumpstarter.FindOwners(ctx, r.Client, namespace)
+	if err != nil {
+		return err
+	}
+	fallback := r.jumpstarterNamespace(ctx)
+
+	for leaseID, leaseOwners := range owners {
+		if !jumpstarter.Acquired(leaseOwners) {
+			continue
+		}
+		leaseNamespace := jumpstarter.LeaseNamespace(ctx, r.Client, namespace, leaseOwners, fallback)
+		if leaseNamespace == "" {
+			continue
+		}
+		if err := jumpstarter.ClaimLease(ctx, r.Client, leaseNamespace, leaseID, namespace); err != nil {
+			if errors.Is(err, jumpstarter.ErrNotInstalled) {
+				return err
+			}
+			if !k8serrors.IsNotFound(err) {
+				r.Log.Error(err, "Failed to claim lease", "lease", leaseID, "namespace", leaseNamespace)
+			}
+		}
+	}
+
+	claimed, err := jumpstarter.ListClaimedLeases(ctx, r.Client, namespace)
+	if err != nil {
+		return err
+	}
+	for _, lease := range claimed {
+		if !lease.Active() || len(owners[lease.Name]) > 0 {
+			continue
+		}
+		if err := jumpstarter.ReleaseLease(ctx, r.Client, lease.Namespace, lease.Name); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				r.Log.Error(err, "Failed to release orphaned lease", "lease", lease.Name, "namespace", lease.Namespace)
+			}
+			continue
+		}
+		r.Log.Info("Released orphaned lease", "lease", lease.Name, "namespace", lease.Namespace, "exporter", lease.Exporter)
+	}
+	return nil
+}
+
+// jumpstarterNamespace returns the Jumpstarter namespace configured in the OperatorConfig.
+func (r *Reconciler) jumpstarterNamespace(ctx context.Context) string {
+	operatorConfig := &automotivev1alpha1.OperatorConfig{}
+	if err := r.Get(ctx, client.ObjectKey{Name: "config", Namespace: controllerutils.OperatorNamespace()}, operatorConfig); err != nil {
+		return ""
+	}
+	if operatorConfig.Spec.Jumpstarter == nil {
+		return ""
+	}
+	return operatorConfig.Spec.Jumpstarter.Namespace
+}
+
+// SetupWithManager runs the lease sync on the leader.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return mgr.Add(r)
+}
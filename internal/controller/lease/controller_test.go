@@ -0,0 +1,119 @@
+package lease
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/jumpstarter"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+)
+
+func newLease(name string, claimed, ended bool) *unstructured.Unstructured {
+	lease := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   map[string]interface{}{"duration": "3h0m0s"},
+		"status": map[string]interface{}{"ended": ended},
+	}}
+	lease.SetGroupVersionKind(jumpstarter.LeaseGVK)
+	lease.SetName(name)
+	lease.SetNamespace("lab")
+	if claimed {
+		lease.SetLabels(map[string]string{labels.ManagedBy: labels.ValueOperator, labels.LeaseOwnerNS: "builds"})
+	}
+	return lease
+}
+
+func newFlashJob(name, leaseID, leaseName string) *automotivev1alpha1.FlashJob {
+	return &automotivev1alpha1.FlashJob{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "builds"},
+		Spec: automotivev1alpha1.FlashJobSpec{
+			ImageRef:              "quay.io/org/disk:v1",
+			LeaseName:             leaseName,
+			ClientConfigSecretRef: name + "-jumpstarter-client",
+		},
+		Status: automotivev1alpha1.FlashJobStatus{LeaseID: leaseID},
+	}
+}
+
+func newClientSecret(name string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "builds"},
+		Data:       map[string][]byte{"client.yaml": []byte("metadata:\n  namespace: lab\n  name: alice\n")},
+	}
+}
+
+func newTestReconciler(objs ...client.Object) (*Reconciler, client.Client) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(automotivev1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return &Reconciler{Client: c, Log: logr.Discard()}, c
+}
+
+func getLease(t *testing.T, c client.Client, name string) *jumpstarter.Lease {
+	t.Helper()
+	lease, err := jumpstarter.GetLease(context.Background(), c, "lab", name)
+	if err != nil {
+		t.Fatalf("failed to get lease %s: %v", name, err)
+	}
+	return lease
+}
+
+func TestSync_ClaimsAcquiredLeases(t *testing.T) {
+	r, c := newTestReconciler(
+		newFlashJob("flash-1", "lease-1", ""),
+		newClientSecret("flash-1-jumpstarter-client"),
+		newFlashJob("flash-2", "lease-2", "lease-2"),
+		newClientSecret("flash-2-jumpstarter-client"),
+		newLease("lease-1", false, false),
+		newLease("lease-2", false, false),
+	)
+
+	if err := r.Sync(context.Background(), "builds"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if lease := getLease(t, c, "lease-1"); lease.OwnerNamespace != "builds" || !lease.Active() {
+		t.Errorf("expected lease-1 claimed and active, got %+v", lease)
+	}
+	if lease := getLease(t, c, "lease-2"); lease.OwnerNamespace != "" {
+		t.Errorf("expected the user's lease-2 not to be claimed, got %+v", lease)
+	}
+}
+
+func TestSync_ReleasesOrphanedLeases(t *testing.T) {
+	ws := &automotivev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws-1", Namespace: "builds"},
+		Spec:       automotivev1alpha1.WorkspaceSpec{Owner: "alice", LeaseID: "lease-2"},
+	}
+	r, c := newTestReconciler(
+		ws,
+		newLease("lease-1", true, false),
+		newLease("lease-2", true, false),
+		newLease("lease-3", false, false),
+	)
+
+	if err := r.Sync(context.Background(), "builds"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if lease := getLease(t, c, "lease-1"); !lease.Released {
+		t.Error("expected orphaned lease-1 to be released")
+	}
+	if lease := getLease(t, c, "lease-2"); lease.Released {
+		t.Error("expected lease-2 held by a workspace to be kept")
+	}
+	if lease := getLease(t, c, "lease-3"); lease.Released {
+		t.Error("expected unclaimed lease-3 to be left alone")
+	}
+}
@@ -104,6 +104,14 @@ var targetDefaultsYAML = `targets:
     defaultFormat: "raw"
 `
 
+// managedTaskNames are the Tekton tasks the operator deploys for builds.
+var managedTaskNames = []string{
+	"build-automotive-image", "push-artifact-registry", "prepare-builder", "flash-image", tasks.PushImageIndexTaskName,
+	tasks.PushArtifactStorageTaskName,
+	tasks.SignArtifactsTaskName,
+	"sealed-prepare-reseal", "sealed-reseal", "sealed-extract-for-signing", "sealed-inject-signed",
+}
+
 // isNoMatchError checks if error is "no matches for kind" error (CRD doesn't exist)
 func isNoMatchError(err error) bool {
 	if err == nil {
@@ -181,6 +189,8 @@ func (r *OperatorConfigReconciler) detectJumpstarter(ctx context.Context) bool {
 //nolint:revive // Name follows Kubebuilder convention for reconcilers
 type OperatorConfigReconciler struct {
 	client.Client
+	// APIReader reads tenant namespaces outside the manager's cache.
+	APIReader     client.Reader
 	Scheme        *runtime.Scheme
 	Log           logr.Logger
 	IsOpenShift   *bool
@@ -198,6 +208,8 @@ type OperatorConfigReconciler struct {
 // +kubebuilder:rbac:groups="",namespace=system,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,namespace=system,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=bind,resourceNames=ado-tenant
 // +kubebuilder:rbac:groups=route.openshift.io,namespace=system,resources=routes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,namespace=system,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=tekton.dev,namespace=system,resources=tasks;pipelines;pipelineruns,verbs=get;list;watch;create;update;patch;delete
@@ -596,7 +608,7 @@ func (r *OperatorConfigReconciler) createOrUpdate(
 	key := client.ObjectKeyFromObject(obj)
 	existing := obj.DeepCopyObject().(client.Object)
 
-	err := r.Get(ctx, key, existing)
+	err := r.reader(key.Namespace).Get(ctx, key, existing)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Stamp hash annotation before creating
@@ -681,20 +693,13 @@ func (r *OperatorConfigReconciler) deployOSBuilds(
 	}
 
 	// Create target defaults ConfigMap (architecture, partition rules, etc.)
-	if err := r.createOrUpdateTargetDefaults(ctx, config); err != nil {
+	if err := r.createOrUpdateTargetDefaults(ctx, config, config.Namespace); err != nil {
 		r.Log.Error(err, "Failed to create target defaults ConfigMap")
 		return fmt.Errorf("failed to create target defaults ConfigMap: %w", err)
 	}
 
 	// Generate and deploy Tekton tasks
-	tektonTasks := []*tektonv1.Task{
-		tasks.GenerateBuildAutomotiveImageTask(config.Namespace, buildConfig, ""),
-		tasks.GeneratePushArtifactRegistryTask(config.Namespace, buildConfig),
-		tasks.GenerateFlashTask(config.Namespace, buildConfig),
-	}
-	tektonTasks = append(tektonTasks, tasks.GenerateSealedTasks(config.Namespace, buildConfig)...)
-
-	for _, task := range tektonTasks {
+	for _, task := range generateTektonTasks(config.Namespace, buildConfig) {
 		task.Labels["automotive.sdv.cloud.redhat.com/managed-by"] = config.Name
 
 		if err := controllerutil.SetControllerReference(config, task, r.Scheme); err != nil {
@@ -745,28 +750,53 @@ func (r *OperatorConfigReconciler) deployOSBuilds(
 		return fmt.Errorf("failed to deploy workspace infrastructure: %w", err)
 	}
 
+	// Provision the tenant namespaces the Build API routes requests to
+	if err := r.deployTenants(ctx, config, buildConfig); err != nil {
+		return fmt.Errorf("failed to deploy tenant namespaces: %w", err)
+	}
+
 	r.Log.Info("OSBuilds deployment completed successfully")
 	return nil
 }
 
-func (r *OperatorConfigReconciler) createOrUpdateTargetDefaults(ctx context.Context, owner *automotivev1alpha1.OperatorConfig) error {
+// generateTektonTasks returns the Tekton tasks of the build pipeline in namespace.
+func generateTektonTasks(namespace string, buildConfig *tasks.BuildConfig) []*tektonv1.Task {
+	tektonTasks := []*tektonv1.Task{
+		tasks.GenerateBuildAutomotiveImageTask(namespace, buildConfig, ""),
+		tasks.GeneratePushArtifactRegistryTask(namespace, buildConfig),
+		tasks.GenerateFlashTask(namespace, buildConfig),
+		tasks.GeneratePushImageIndexTask(namespace, buildConfig),
+		tasks.GeneratePushArtifactStorageTask(namespace, buildConfig),
+		tasks.GenerateSignArtifactsTask(namespace, buildConfig),
+	}
+	return append(tektonTasks, tasks.GenerateSealedTasks(namespace, buildConfig)...)
+}
+
+func (r *OperatorConfigReconciler) createOrUpdateTargetDefaults(
+	ctx context.Context,
+	owner *automotivev1alpha1.OperatorConfig,
+	namespace string,
+) error {
 	configMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "aib-target-defaults",
-			Namespace: owner.Namespace,
+			Namespace: namespace,
 		},
 		Data: map[string]string{
 			"target-defaults.yaml": targetDefaultsYAML,
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(owner, configMap, r.Scheme); err != nil {
-		return fmt.Errorf("failed to set controller reference: %w", err)
+	// Owner references cannot cross namespaces, tenant copies are cleaned up by cleanupTenant
+	if namespace == owner.Namespace {
+		if err := controllerutil.SetControllerReference(owner, configMap, r.Scheme); err != nil {
+			return fmt.Errorf("failed to set controller reference: %w", err)
+		}
 	}
 
 	// Only create the ConfigMap if it doesn't exist; never overwrite user changes
 	existing := &corev1.ConfigMap{}
-	err := r.Get(ctx, client.ObjectKeyFromObject(configMap), existing)
+	err := r.reader(namespace).Get(ctx, client.ObjectKeyFromObject(configMap), existing)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return r.Create(ctx, configMap)
@@ -898,12 +928,13 @@ func (r *OperatorConfigReconciler) cleanupOSBuilds(ctx context.Context, config *
 	}
 	r.Log.Info("Target defaults ConfigMap deleted")
 
-	// Delete Tekton tasks
-	taskNames := []string{
-		"build-automotive-image", "push-artifact-registry", "prepare-builder", "flash-image",
-		"sealed-prepare-reseal", "sealed-reseal", "sealed-extract-for-signing", "sealed-inject-signed",
+	// Deprovision tenant namespaces while the operator still has access to them
+	if err := r.pruneTenants(ctx, config, nil); err != nil {
+		return fmt.Errorf("failed to cleanup tenant namespaces: %w", err)
 	}
-	for _, taskName := range taskNames {
+
+	// Delete Tekton tasks
+	for _, taskName := range managedTaskNames {
 		task := &tektonv1.Task{}
 		task.Name = taskName
 		task.Namespace = config.Namespace
@@ -499,6 +499,7 @@ func (r *OperatorConfigReconciler) buildBuildControllerDeployment(namespace stri
 						"app.kubernetes.io/name":      "automotive-dev-operator",
 						"app.kubernetes.io/component": "build-controller",
 					},
+					Annotations: tenantNamespacesAnnotations(config),
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: buildControllerName,
@@ -668,6 +669,53 @@ func (r *OperatorConfigReconciler) buildBuildControllerClusterRole() *rbacv1.Clu
 				Resources: []string{"workspaces/finalizers"},
 				Verbs:     []string{"update"},
 			},
+			// FlashJob controller RBAC
+			{
+				APIGroups: []string{"automotive.sdv.cloud.redhat.com"},
+				Resources: []string{"flashjobs"},
+				Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+			},
+			{
+				APIGroups: []string{"automotive.sdv.cloud.redhat.com"},
+				Resources: []string{"flashjobs/status"},
+				Verbs:     []string{"get", "update", "patch"},
+			},
+			{
+				APIGroups: []string{"automotive.sdv.cloud.redhat.com"},
+				Resources: []string{"flashjobs/finalizers"},
+				Verbs:     []string{"update"},
+			},
+			// WorkspaceSnapshot controller RBAC
+			{
+				APIGroups: []string{"automotive.sdv.cloud.redhat.com"},
+				Resources: []string{"workspacesnapshots"},
+				Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+			},
+			{
+				APIGroups: []string{"automotive.sdv.cloud.redhat.com"},
+				Resources: []string{"workspacesnapshots/status"},
+				Verbs:     []string{"get", "update", "patch"},
+			},
+			{
+				APIGroups: []string{"automotive.sdv.cloud.redhat.com"},
+				Resources: []string{"workspacesnapshots/finalizers"},
+				Verbs:     []string{"update"},
+			},
+			{
+				APIGroups: []string{"snapshot.storage.k8s.io"},
+				Resources: []string{"volumesnapshots"},
+				Verbs:     []string{"get", "list", "watch", "create", "delete"},
+			},
+			{
+				APIGroups: []string{"snapshot.storage.k8s.io"},
+				Resources: []string{"volumesnapshotclasses"},
+				Verbs:     []string{"get", "list"},
+			},
+			{
+				APIGroups: []string{"storage.k8s.io"},
+				Resources: []string{"storageclasses"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
 			// Read-only access to OperatorConfig (for build config)
 			{
 				APIGroups: []string{"automotive.sdv.cloud.redhat.com"},
@@ -736,6 +784,11 @@ func (r *OperatorConfigReconciler) buildBuildControllerClusterRole() *rbacv1.Clu
 				Resources: []string{"imagestreams"},
 				Verbs:     []string{"get", "create"},
 			},
+			{
+				APIGroups: []string{"image.openshift.io"},
+				Resources: []string{"imagestreamtags"},
+				Verbs:     []string{"delete"},
+			},
 			{
 				APIGroups: []string{"route.openshift.io"},
 				Resources: []string{"routes"},
@@ -769,6 +822,18 @@ func (r *OperatorConfigReconciler) buildBuildControllerClusterRole() *rbacv1.Clu
 				Resources: []string{"builds", "buildruns"},
 				Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
 			},
+			// Jumpstarter resources (fleet flashes count exporters, the lease
+			// controller claims and releases leases)
+			{
+				APIGroups: []string{"jumpstarter.dev"},
+				Resources: []string{"exporters"},
+				Verbs:     []string{"get", "list"},
+			},
+			{
+				APIGroups: []string{"jumpstarter.dev"},
+				Resources: []string{"leases"},
+				Verbs:     []string{"get", "list", "patch"},
+			},
 		},
 	}
 }
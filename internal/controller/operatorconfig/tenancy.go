@@ -0,0 +1,314 @@
+package operatorconfig
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/tasks"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/controllerutils"
+)
+
+const (
+	// tenantRoleName is the ClusterRole, from config/rbac/tenant_role.yaml, that
+	// gives the operator and the Build API access to a tenant namespace.
+	tenantRoleName             = "ado-tenant"
+	operatorServiceAccountName = "ado-operator"
+	pipelinePodAnnotatorName   = "ado-pipeline-pod-annotator"
+	pipelineRegistryEditorName = "ado-pipeline-registry-editor"
+	// tenantNamespacesAnnotation rolls the build controller when tenants
+	// change, as its cache only covers the namespaces known at startup.
+	tenantNamespacesAnnotation = "automotive.sdv.cloud.redhat.com/tenant-namespaces"
+)
+
+// reader returns the reader for objects in namespace. Tenant namespaces that
+// were added after startup are outside the manager's cache, so objects there
+// are read from the API server.
+func (r *OperatorConfigReconciler) reader(namespace string) client.Reader {
+	if r.APIReader != nil && namespace != "" && namespace != controllerutils.OperatorNamespace() {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+// tenantNamespaces returns the tenant namespaces of config, without the
+// operator namespace.
+func tenantNamespaces(config *automotivev1alpha1.OperatorConfig) []string {
+	if config.Spec.BuildAPI == nil {
+		return nil
+	}
+	var namespaces []string
+	for _, namespace := range config.Spec.BuildAPI.Tenancy.Namespaces() {
+		if namespace != config.Namespace && !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+// deployTenants provisions the tenant namespaces the Build API routes requests
+// to with the build and workspace infrastructure of the operator namespace.
+// Namespaces of tenants that were removed from the config are deprovisioned;
+// the namespaces and the builds in them are kept.
+func (r *OperatorConfigReconciler) deployTenants(
+	ctx context.Context,
+	config *automotivev1alpha1.OperatorConfig,
+	buildConfig *tasks.BuildConfig,
+) error {
+	tenants := tenantNamespaces(config)
+	if len(tenants) > 0 {
+		isOpenShift := r.detectOpenShift(ctx, config.Namespace)
+		for _, namespace := range tenants {
+			if err := r.deployTenant(ctx, config, namespace, buildConfig, isOpenShift); err != nil {
+				return fmt.Errorf("failed to provision tenant namespace %s: %w", namespace, err)
+			}
+		}
+	}
+	return r.pruneTenants(ctx, config, tenants)
+}
+
+func (r *OperatorConfigReconciler) deployTenant(
+	ctx context.Context,
+	config *automotivev1alpha1.OperatorConfig,
+	namespace string,
+	buildConfig *tasks.BuildConfig,
+	isOpenShift bool,
+) error {
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		if err := r.Create(ctx, r.buildTenantNamespace(namespace, config.Namespace)); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create namespace: %w", err)
+		}
+		r.Log.Info("Tenant namespace created", "namespace", namespace)
+	}
+
+	// Access for the operator comes first, everything else is created with it
+	if err := r.createOrUpdate(ctx, r.buildTenantRoleBinding(namespace, config.Namespace), config); err != nil {
+		return fmt.Errorf("failed to create/update tenant role binding: %w", err)
+	}
+
+	if err := r.createOrUpdateTargetDefaults(ctx, config, namespace); err != nil {
+		return fmt.Errorf("failed to create target defaults ConfigMap: %w", err)
+	}
+
+	for _, task := range generateTektonTasks(namespace, buildConfig) {
+		task.Labels["automotive.sdv.cloud.redhat.com/managed-by"] = config.Name
+		task.Labels[labels.Tenant] = config.Namespace
+		if err := r.createOrUpdateTask(ctx, task); err != nil {
+			return fmt.Errorf("failed to create/update task %s: %w", task.Name, err)
+		}
+	}
+	pipeline := tasks.GenerateTektonPipeline("automotive-build-pipeline", namespace, buildConfig)
+	pipeline.Labels["automotive.sdv.cloud.redhat.com/managed-by"] = config.Name
+	pipeline.Labels[labels.Tenant] = config.Namespace
+	if err := r.createOrUpdatePipeline(ctx, pipeline); err != nil {
+		return fmt.Errorf("failed to create/update pipeline: %w", err)
+	}
+
+	objects := []client.Object{
+		r.buildBuildServiceAccount(namespace),
+		r.buildWorkspaceServiceAccount(namespace),
+		r.buildPipelinePodAnnotatorRole(namespace),
+		r.buildPipelinePodAnnotatorRoleBinding(namespace),
+	}
+	if isOpenShift {
+		objects = append(objects,
+			r.buildPipelineRegistryEditorRoleBinding(namespace),
+			r.buildBuildSCCRoleBinding(namespace),
+			r.buildWorkspaceSCCRoleBinding(namespace),
+		)
+	}
+	for _, obj := range objects {
+		if err := r.createOrUpdate(ctx, obj, config); err != nil {
+			return fmt.Errorf("failed to create/update %s: %w", obj.GetName(), err)
+		}
+	}
+
+	r.Log.Info("Tenant namespace provisioned", "namespace", namespace)
+	return nil
+}
+
+// pruneTenants deprovisions the namespaces that were tenants of config but are
+// not in tenants anymore.
+func (r *OperatorConfigReconciler) pruneTenants(ctx context.Context, config *automotivev1alpha1.OperatorConfig, tenants []string) error {
+	reader := client.Reader(r.Client)
+	if r.APIReader != nil {
+		reader = r.APIReader
+	}
+	bindings := &rbacv1.RoleBindingList{}
+	if err := reader.List(ctx, bindings, client.MatchingLabels{labels.Tenant: config.Namespace}); err != nil {
+		return fmt.Errorf("failed to list tenant role bindings: %w", err)
+	}
+	for _, binding := range bindings.Items {
+		if binding.Name != tenantRoleName || slices.Contains(tenants, binding.Namespace) {
+			continue
+		}
+		if err := r.cleanupTenant(ctx, binding.Namespace); err != nil {
+			return fmt.Errorf("failed to deprovision tenant namespace %s: %w", binding.Namespace, err)
+		}
+	}
+	return nil
+}
+
+// cleanupTenant removes what deployTenant provisioned in namespace, except the
+// namespace itself. The operator's own access goes last.
+func (r *OperatorConfigReconciler) cleanupTenant(ctx context.Context, namespace string) error {
+	var objects []client.Object
+	for _, name := range managedTaskNames {
+		objects = append(objects, &tektonv1.Task{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}})
+	}
+	objects = append(objects,
+		&tektonv1.Pipeline{ObjectMeta: metav1.ObjectMeta{Name: "automotive-build-pipeline", Namespace: namespace}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "aib-target-defaults", Namespace: namespace}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: automotivev1alpha1.BuildServiceAccountName, Namespace: namespace}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: workspaceServiceAccountName, Namespace: namespace}},
+		&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: pipelinePodAnnotatorName, Namespace: namespace}},
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: pipelinePodAnnotatorName, Namespace: namespace}},
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: pipelineRegistryEditorName, Namespace: namespace}},
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: pipelineSCCBindingName, Namespace: namespace}},
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: workspaceServiceAccountName + "-privileged", Namespace: namespace}},
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: tenantRoleName, Namespace: namespace}},
+	)
+	for _, obj := range objects {
+		if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) && !isNoMatchError(err) {
+			return fmt.Errorf("failed to delete %s: %w", obj.GetName(), err)
+		}
+	}
+	r.Log.Info("Tenant namespace deprovisioned", "namespace", namespace)
+	return nil
+}
+
+// tenantNamespacesAnnotations returns the pod template annotations that make
+// a deployment restart when the tenant namespaces change.
+func tenantNamespacesAnnotations(config *automotivev1alpha1.OperatorConfig) map[string]string {
+	tenants := tenantNamespaces(config)
+	if len(tenants) == 0 {
+		return nil
+	}
+	return map[string]string{tenantNamespacesAnnotation: strings.Join(tenants, ",")}
+}
+
+func (r *OperatorConfigReconciler) buildTenantNamespace(name, operatorNamespace string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "automotive-dev-operator",
+				labels.Tenant:                  operatorNamespace,
+			},
+		},
+	}
+}
+
+func (r *OperatorConfigReconciler) buildTenantRoleBinding(namespace, operatorNamespace string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tenantRoleName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":      "automotive-dev-operator",
+				"app.kubernetes.io/component": "tenant",
+				"app.kubernetes.io/part-of":   "automotive-dev-operator",
+				labels.Tenant:                 operatorNamespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     tenantRoleName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      operatorServiceAccountName,
+				Namespace: operatorNamespace,
+			},
+		},
+	}
+}
+
+func (r *OperatorConfigReconciler) buildPipelinePodAnnotatorRole(namespace string) *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pipelinePodAnnotatorName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":      "automotive-dev-operator",
+				"app.kubernetes.io/component": "build",
+				"app.kubernetes.io/part-of":   "automotive-dev-operator",
+			},
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods"},
+				Verbs:     []string{"patch"},
+			},
+		},
+	}
+}
+
+func (r *OperatorConfigReconciler) buildPipelinePodAnnotatorRoleBinding(namespace string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pipelinePodAnnotatorName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":      "automotive-dev-operator",
+				"app.kubernetes.io/component": "build",
+				"app.kubernetes.io/part-of":   "automotive-dev-operator",
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     pipelinePodAnnotatorName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      automotivev1alpha1.BuildServiceAccountName,
+				Namespace: namespace,
+			},
+		},
+	}
+}
+
+func (r *OperatorConfigReconciler) buildPipelineRegistryEditorRoleBinding(namespace string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pipelineRegistryEditorName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":      "automotive-dev-operator",
+				"app.kubernetes.io/component": "build",
+				"app.kubernetes.io/part-of":   "automotive-dev-operator",
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     "registry-editor",
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      automotivev1alpha1.BuildServiceAccountName,
+				Namespace: namespace,
+			},
+		},
+	}
+}
@@ -4,6 +4,9 @@ package workspace
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
 	"fmt"
 	"net/http"
 	"strings"
@@ -12,6 +15,7 @@ import (
 
 	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/bundleverify"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/tasks"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/controllerutils"
 	"github.com/go-logr/logr"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
@@ -19,6 +23,7 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/crypto/ssh"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -41,6 +46,8 @@ const (
 	pvcSuffix                   = "-workspace"
 	leaseAnn                    = "automotive.sdv.cloud.redhat.com/lease-id"
 	workspaceServiceAccountName = "ado-workspace"
+	sshKeySecretSuffix          = "-ssh-key"
+	sshPublicKeyKey             = "ssh-publickey"
 )
 
 // Reconciler reconciles a Workspace object.
@@ -61,6 +68,8 @@ type Reconciler struct {
 // +kubebuilder:rbac:groups="",namespace=system,resources=pods,verbs=get;list;watch;create;delete
 // +kubebuilder:rbac:groups="",namespace=system,resources=pods/exec,verbs=create
 // +kubebuilder:rbac:groups="",namespace=system,resources=persistentvolumeclaims,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",namespace=system,resources=secrets,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,namespace=system,resources=workspacesnapshots,verbs=get;list;watch
 
 // Reconcile handles Workspace CR changes.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
@@ -87,6 +96,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		return ctrl.Result{}, err
 	}
 
+	// Ensure the SSH keypair the workspace installs on leased boards exists
+	if err := r.ensureSSHKeySecret(ctx, ws); err != nil {
+		if statusErr := r.setStatus(ctx, ws, "Failed", fmt.Sprintf("SSH key error: %v", err)); statusErr != nil {
+			log.Error(statusErr, "failed to update status after SSH key error")
+		}
+		return ctrl.Result{}, err
+	}
+
 	// Ensure Pod exists (needs PVC name from status)
 	if ws.Status.PVCName == "" {
 		return ctrl.Result{Requeue: true}, nil
@@ -102,6 +119,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		return ctrl.Result{}, r.setStatus(ctx, ws, "Stopped", msg)
 	}
 
+	// Restore an exported snapshot into the volume before the workspace starts
+	if ws.Spec.FromSnapshot != "" && !ws.Status.SnapshotRestored {
+		restored, err := r.restoreSnapshot(ctx, ws, log)
+		if err != nil {
+			if statusErr := r.setStatus(ctx, ws, "Failed", fmt.Sprintf("Snapshot restore error: %v", err)); statusErr != nil {
+				log.Error(statusErr, "failed to update status after snapshot restore error")
+			}
+			return ctrl.Result{}, err
+		}
+		if !restored {
+			return ctrl.Result{}, r.setStatus(ctx, ws, "Creating",
+				fmt.Sprintf("Restoring workspace from snapshot %s", ws.Spec.FromSnapshot))
+		}
+	}
+
 	pod, err := r.ensurePod(ctx, ws, log)
 	if err != nil {
 		if statusErr := r.setStatus(ctx, ws, "Failed", fmt.Sprintf("Pod error: %v", err)); statusErr != nil {
@@ -188,6 +220,25 @@ func (r *Reconciler) ensurePVC(ctx context.Context, ws *automotivev1alpha1.Works
 	if ws.Spec.StorageClass != "" {
 		pvc.Spec.StorageClassName = &ws.Spec.StorageClass
 	}
+
+	// A VolumeSnapshot is restored by the storage provisioner, an OCI export by
+	// the restore pod once the volume exists
+	restoredFromVolumeSnapshot := false
+	if ws.Spec.FromSnapshot != "" {
+		snapshot, err := r.getReadySnapshot(ctx, ws)
+		if err != nil {
+			return err
+		}
+		if snapshot.Status.Method == automotivev1alpha1.SnapshotMethodVolumeSnapshot {
+			pvc.Spec.DataSource = &corev1.TypedLocalObjectReference{
+				APIGroup: ptr.To("snapshot.storage.k8s.io"),
+				Kind:     "VolumeSnapshot",
+				Name:     snapshot.Status.VolumeSnapshotName,
+			}
+			restoredFromVolumeSnapshot = true
+		}
+	}
+
 	if err := controllerutil.SetControllerReference(ws, pvc, r.Scheme); err != nil {
 		return err
 	}
@@ -198,9 +249,141 @@ func (r *Reconciler) ensurePVC(ctx context.Context, ws *automotivev1alpha1.Works
 	// Store the PVC name in status
 	patch := client.MergeFrom(ws.DeepCopy())
 	ws.Status.PVCName = pvcName
+	if restoredFromVolumeSnapshot {
+		ws.Status.SnapshotRestored = true
+	}
 	return r.Status().Patch(ctx, ws, patch)
 }
 
+// ensureSSHKeySecret creates the workspace's SSH keypair unless it exists. The
+// init container installs it as /workspace/.ssh/id_ed25519, so the key stays
+// the same across restarts and is not shared by clones of the workspace.
+func (r *Reconciler) ensureSSHKeySecret(ctx context.Context, ws *automotivev1alpha1.Workspace) (err error) {
+	ctx, span := wsTracer.Start(ctx, "Workspace.EnsureSSHKeySecret")
+	defer controllerutils.EndSpanWithError(span, &err)
+
+	secretName := ws.Name + sshKeySecretSuffix
+	existing := &corev1.Secret{}
+	err = r.Get(ctx, client.ObjectKey{Namespace: ws.Namespace, Name: secretName}, existing)
+	if k8serrors.IsNotFound(err) {
+		data, genErr := generateSSHKeyPair("caib-workspace-" + ws.Name)
+		if genErr != nil {
+			return genErr
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: ws.Namespace,
+			},
+			Type: corev1.SecretTypeSSHAuth,
+			Data: data,
+		}
+		if err := controllerutil.SetControllerReference(ws, secret, r.Scheme); err != nil {
+			return err
+		}
+		if err := r.Create(ctx, secret); err != nil && !k8serrors.IsAlreadyExists(err) {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if ws.Status.SSHKeySecretName != secretName {
+		patch := client.MergeFrom(ws.DeepCopy())
+		ws.Status.SSHKeySecretName = secretName
+		return r.Status().Patch(ctx, ws, patch)
+	}
+	return nil
+}
+
+// generateSSHKeyPair returns the data of a kubernetes.io/ssh-auth secret with
+// a new ed25519 key in OpenSSH format and its authorized_keys line.
+func generateSSHKeyPair(comment string) (map[string][]byte, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generating SSH key: %w", err)
+	}
+	block, err := ssh.MarshalPrivateKey(priv, comment)
+	if err != nil {
+		return nil, fmt.Errorf("encoding SSH private key: %w", err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return nil, fmt.Errorf("encoding SSH public key: %w", err)
+	}
+	authorizedKey := bytes.TrimSuffix(ssh.MarshalAuthorizedKey(sshPub), []byte("\n"))
+	return map[string][]byte{
+		corev1.SSHAuthPrivateKey: pem.EncodeToMemory(block),
+		sshPublicKeyKey:          append(authorizedKey, []byte(" "+comment+"\n")...),
+	}, nil
+}
+
+// getReadySnapshot returns the snapshot the workspace is cloned from.
+func (r *Reconciler) getReadySnapshot(ctx context.Context, ws *automotivev1alpha1.Workspace) (*automotivev1alpha1.WorkspaceSnapshot, error) {
+	snapshot := &automotivev1alpha1.WorkspaceSnapshot{}
+	if err := r.Get(ctx, client.ObjectKey{Name: ws.Spec.FromSnapshot, Namespace: ws.Namespace}, snapshot); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("snapshot %q not found", ws.Spec.FromSnapshot)
+		}
+		return nil, err
+	}
+	if snapshot.Status.Phase != automotivev1alpha1.WorkspaceSnapshotPhaseReady {
+		return nil, fmt.Errorf("snapshot %q is not ready (phase %q)", ws.Spec.FromSnapshot, snapshot.Status.Phase)
+	}
+	return snapshot, nil
+}
+
+// restoreSnapshot runs the pod extracting an exported snapshot into the
+// workspace volume. It returns true once the volume is restored.
+func (r *Reconciler) restoreSnapshot(ctx context.Context, ws *automotivev1alpha1.Workspace, log logr.Logger) (_ bool, err error) {
+	ctx, span := wsTracer.Start(ctx, "Workspace.RestoreSnapshot")
+	defer controllerutils.EndSpanWithError(span, &err)
+
+	podName := "workspace-" + ws.Name + "-restore"
+	pod := &corev1.Pod{}
+	err = r.Get(ctx, client.ObjectKey{Namespace: ws.Namespace, Name: podName}, pod)
+	if err == nil {
+		switch pod.Status.Phase {
+		case corev1.PodSucceeded:
+			log.Info("Workspace restored from snapshot", "snapshot", ws.Spec.FromSnapshot)
+			patch := client.MergeFrom(ws.DeepCopy())
+			ws.Status.SnapshotRestored = true
+			if err := r.Status().Patch(ctx, ws, patch); err != nil {
+				return false, err
+			}
+			return true, client.IgnoreNotFound(r.Delete(ctx, pod))
+		case corev1.PodFailed:
+			return false, fmt.Errorf("restore pod %s failed, see its logs for details", podName)
+		default:
+			return false, nil
+		}
+	}
+	if !k8serrors.IsNotFound(err) {
+		return false, err
+	}
+
+	snapshot, err := r.getReadySnapshot(ctx, ws)
+	if err != nil {
+		return false, err
+	}
+	if snapshot.Status.ArtifactRef == "" {
+		return false, fmt.Errorf("snapshot %q has no exported artifact", snapshot.Name)
+	}
+
+	var wsConfig *automotivev1alpha1.WorkspacesConfig
+	if oc := r.getOperatorConfig(ctx, ws); oc != nil {
+		wsConfig = oc.Spec.Workspaces
+	}
+	pod = tasks.GenerateWorkspaceSnapshotPod(ws.Namespace, podName, tasks.WorkspaceSnapshotRestore,
+		ws.Status.PVCName, snapshot.Status.ArtifactRef, wsConfig.GetToolchainImage())
+	pod.Labels[automotivev1alpha1.LabelWorkspaceName] = controllerutils.SanitizeLabelValue(ws.Name)
+	if err := controllerutil.SetControllerReference(ws, pod, r.Scheme); err != nil {
+		return false, err
+	}
+	log.Info("Creating snapshot restore pod", "pod", podName, "snapshot", snapshot.Name)
+	return false, r.Create(ctx, pod)
+}
+
 func (r *Reconciler) ensurePod(ctx context.Context, ws *automotivev1alpha1.Workspace, log logr.Logger) (_ *corev1.Pod, err error) {
 	ctx, span := wsTracer.Start(ctx, "Workspace.EnsurePod")
 	defer controllerutils.EndSpanWithError(span, &err)
@@ -216,11 +399,7 @@ func (r *Reconciler) ensurePod(ctx context.Context, ws *automotivev1alpha1.Works
 	}
 
 	// Load OperatorConfig only when creating a new pod
-	var operatorConfig *automotivev1alpha1.OperatorConfig
-	oc := &automotivev1alpha1.OperatorConfig{}
-	if err := r.Get(ctx, client.ObjectKey{Name: "config", Namespace: ws.Namespace}, oc); err == nil {
-		operatorConfig = oc
-	}
+	operatorConfig := r.getOperatorConfig(ctx, ws)
 
 	var wsConfig *automotivev1alpha1.WorkspacesConfig
 	if operatorConfig != nil {
@@ -385,10 +564,19 @@ func (r *Reconciler) buildPod(ws *automotivev1alpha1.Workspace, operatorConfig *
 	}
 
 	// Init container: sets up PVC directories, SSH keys, and Jumpstarter config
-	// using the toolchain image (which has ssh-keygen, etc.). Runs before the main
+	// using the toolchain image (which has jmp, etc.). Runs before the main
 	// container regardless of image, so arbitrary images get a prepared workspace.
+	volumes = append(volumes, corev1.Volume{
+		Name: "ssh-key",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: ws.Name + sshKeySecretSuffix,
+			},
+		},
+	})
 	initMounts := []corev1.VolumeMount{
 		{Name: "workspace", MountPath: "/workspace"},
+		{Name: "ssh-key", MountPath: "/etc/workspace-ssh", ReadOnly: true},
 	}
 	if ws.Spec.ClientConfigSecretRef != "" {
 		initMounts = append(initMounts, corev1.VolumeMount{
@@ -399,7 +587,8 @@ func (r *Reconciler) buildPod(ws *automotivev1alpha1.Workspace, operatorConfig *
 mkdir -p /workspace/src /workspace/cache /workspace/.cache /workspace/.ssh \
          /workspace/.config /workspace/.local/share/containers \
          /workspace/.pkg-overlay/{usr,etc,var-lib,opt}-{upper,work}
-[ -f /workspace/.ssh/id_ed25519 ] || ssh-keygen -t ed25519 -f /workspace/.ssh/id_ed25519 -N '' -q
+install -m 600 /etc/workspace-ssh/ssh-privatekey /workspace/.ssh/id_ed25519
+install -m 644 /etc/workspace-ssh/ssh-publickey /workspace/.ssh/id_ed25519.pub
 if [ -f /jumpstarter/client.yaml ]; then
   mkdir -p /workspace/.config/jumpstarter/clients
   cp /jumpstarter/client.yaml /workspace/.config/jumpstarter/clients/workspace.yaml
@@ -546,16 +735,26 @@ func (r *Reconciler) getAutoPauseTimeout(ctx context.Context, ws *automotivev1al
 		return time.Duration(mins) * time.Minute
 	}
 
-	oc := &automotivev1alpha1.OperatorConfig{}
-	if err := r.Get(ctx, client.ObjectKey{Name: "config", Namespace: ws.Namespace}, oc); err == nil {
-		if oc.Spec.Workspaces != nil {
-			return time.Duration(oc.Spec.Workspaces.GetAutoPauseTimeoutMinutes()) * time.Minute
-		}
+	if oc := r.getOperatorConfig(ctx, ws); oc != nil && oc.Spec.Workspaces != nil {
+		return time.Duration(oc.Spec.Workspaces.GetAutoPauseTimeoutMinutes()) * time.Minute
 	}
 
 	return time.Duration(automotivev1alpha1.DefaultAutoPauseTimeoutMinutes) * time.Minute
 }
 
+// getOperatorConfig returns the OperatorConfig that applies to a workspace: the
+// one in its namespace or, for workspaces in tenant namespaces, the one in the
+// operator namespace. It returns nil if there is none.
+func (r *Reconciler) getOperatorConfig(ctx context.Context, ws *automotivev1alpha1.Workspace) *automotivev1alpha1.OperatorConfig {
+	for _, namespace := range []string{ws.Namespace, controllerutils.OperatorNamespace()} {
+		oc := &automotivev1alpha1.OperatorConfig{}
+		if err := r.Get(ctx, client.ObjectKey{Name: "config", Namespace: namespace}, oc); err == nil {
+			return oc
+		}
+	}
+	return nil
+}
+
 // checkAutoPause checks if a Running workspace should be auto-paused due to inactivity.
 func (r *Reconciler) checkAutoPause(ctx context.Context, ws *automotivev1alpha1.Workspace, log logr.Logger) (ctrl.Result, error) {
 	timeout := r.getAutoPauseTimeout(ctx, ws)
@@ -683,6 +882,7 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&automotivev1alpha1.Workspace{}).
 		Owns(&corev1.Pod{}).
 		Owns(&corev1.PersistentVolumeClaim{}).
+		Owns(&corev1.Secret{}).
 		Complete(r)
 }
 
@@ -1,12 +1,14 @@
 package workspace
 
 import (
+	"bytes"
 	"context"
 	"strings"
 	"testing"
 
 	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
 	"github.com/go-logr/logr"
+	"golang.org/x/crypto/ssh"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -660,3 +662,184 @@ func TestBuildPod_SecurityContext(t *testing.T) {
 		})
 	}
 }
+
+func readySnapshot(name, namespace, method string) *automotivev1alpha1.WorkspaceSnapshot {
+	snapshot := &automotivev1alpha1.WorkspaceSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       automotivev1alpha1.WorkspaceSnapshotSpec{Workspace: "source", Owner: "testuser"},
+		Status: automotivev1alpha1.WorkspaceSnapshotStatus{
+			Phase:  automotivev1alpha1.WorkspaceSnapshotPhaseReady,
+			Method: method,
+		},
+	}
+	if method == automotivev1alpha1.SnapshotMethodVolumeSnapshot {
+		snapshot.Status.VolumeSnapshotName = name
+	} else {
+		snapshot.Status.ArtifactRef = "image-registry.openshift-image-registry.svc:5000/" + namespace + "/workspace-snapshots:" + name
+	}
+	return snapshot
+}
+
+func TestEnsurePVC_ClonesFromVolumeSnapshot(t *testing.T) {
+	ws := &automotivev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "clone", Namespace: "default"},
+		Spec:       automotivev1alpha1.WorkspaceSpec{Owner: "testuser", FromSnapshot: "snap"},
+	}
+	r, fc := newTestReconciler(ws, readySnapshot("snap", "default", automotivev1alpha1.SnapshotMethodVolumeSnapshot))
+	ctx := context.Background()
+
+	if err := r.ensurePVC(ctx, ws); err != nil {
+		t.Fatalf("ensurePVC() error = %v", err)
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := fc.Get(ctx, types.NamespacedName{Name: "clone" + pvcSuffix, Namespace: "default"}, pvc); err != nil {
+		t.Fatal(err)
+	}
+	ds := pvc.Spec.DataSource
+	if ds == nil || ds.Kind != "VolumeSnapshot" || ds.Name != "snap" || ptr.Deref(ds.APIGroup, "") != "snapshot.storage.k8s.io" {
+		t.Errorf("expected the PVC to be provisioned from the VolumeSnapshot, got %+v", ds)
+	}
+	updated := &automotivev1alpha1.Workspace{}
+	if err := fc.Get(ctx, types.NamespacedName{Name: "clone", Namespace: "default"}, updated); err != nil {
+		t.Fatal(err)
+	}
+	if !updated.Status.SnapshotRestored {
+		t.Error("expected SnapshotRestored to be set")
+	}
+}
+
+func TestEnsurePVC_RejectsSnapshotNotReady(t *testing.T) {
+	ws := &automotivev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "clone", Namespace: "default"},
+		Spec:       automotivev1alpha1.WorkspaceSpec{Owner: "testuser", FromSnapshot: "snap"},
+	}
+	snapshot := readySnapshot("snap", "default", automotivev1alpha1.SnapshotMethodVolumeSnapshot)
+	snapshot.Status.Phase = automotivev1alpha1.WorkspaceSnapshotPhaseCreating
+	r, _ := newTestReconciler(ws, snapshot)
+
+	err := r.ensurePVC(context.Background(), ws)
+	if err == nil || !strings.Contains(err.Error(), "not ready") {
+		t.Errorf("expected a not ready error, got %v", err)
+	}
+}
+
+func TestReconcile_RestoresOCISnapshotBeforeStarting(t *testing.T) {
+	ws, pvc, _ := runningWorkspace("clone", "default")
+	ws.Spec.FromSnapshot = "snap"
+	ws.Status = automotivev1alpha1.WorkspaceStatus{PVCName: pvc.Name}
+	snapshot := readySnapshot("snap", "default", automotivev1alpha1.SnapshotMethodOCI)
+
+	r, fc := newTestReconciler(ws, pvc, snapshot)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "clone", Namespace: "default"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	restorePod := &corev1.Pod{}
+	if err := fc.Get(ctx, types.NamespacedName{Name: "workspace-clone-restore", Namespace: "default"}, restorePod); err != nil {
+		t.Fatalf("expected restore pod: %v", err)
+	}
+	env := map[string]string{}
+	for _, e := range restorePod.Spec.Containers[0].Env {
+		env[e.Name] = e.Value
+	}
+	if env["SNAPSHOT_MODE"] != "restore" || env["SNAPSHOT_REF"] != snapshot.Status.ArtifactRef {
+		t.Errorf("unexpected restore env %v", env)
+	}
+	if err := fc.Get(ctx, types.NamespacedName{Name: "workspace-clone", Namespace: "default"}, &corev1.Pod{}); err == nil {
+		t.Error("expected the workspace pod to wait for the restore")
+	}
+
+	restorePod.Status.Phase = corev1.PodSucceeded
+	if err := fc.Status().Update(ctx, restorePod); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated := &automotivev1alpha1.Workspace{}
+	if err := fc.Get(ctx, req.NamespacedName, updated); err != nil {
+		t.Fatal(err)
+	}
+	if !updated.Status.SnapshotRestored {
+		t.Error("expected SnapshotRestored to be set")
+	}
+	if err := fc.Get(ctx, types.NamespacedName{Name: "workspace-clone-restore", Namespace: "default"}, &corev1.Pod{}); err == nil {
+		t.Error("expected the restore pod to be deleted")
+	}
+	if err := fc.Get(ctx, types.NamespacedName{Name: "workspace-clone", Namespace: "default"}, &corev1.Pod{}); err != nil {
+		t.Errorf("expected the workspace pod after the restore: %v", err)
+	}
+}
+
+func TestEnsureSSHKeySecret(t *testing.T) {
+	ws := &automotivev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: "default"},
+		Spec:       automotivev1alpha1.WorkspaceSpec{Owner: "testuser"},
+	}
+	r, fc := newTestReconciler(ws)
+	ctx := context.Background()
+
+	if err := r.ensureSSHKeySecret(ctx, ws); err != nil {
+		t.Fatalf("ensureSSHKeySecret() error = %v", err)
+	}
+
+	secret := &corev1.Secret{}
+	if err := fc.Get(ctx, types.NamespacedName{Name: "my-app" + sshKeySecretSuffix, Namespace: "default"}, secret); err != nil {
+		t.Fatal(err)
+	}
+	if secret.Type != corev1.SecretTypeSSHAuth {
+		t.Errorf("expected secret type %s, got %s", corev1.SecretTypeSSHAuth, secret.Type)
+	}
+	signer, err := ssh.ParsePrivateKey(secret.Data[corev1.SSHAuthPrivateKey])
+	if err != nil {
+		t.Fatalf("invalid private key: %v", err)
+	}
+	pub, comment, _, _, err := ssh.ParseAuthorizedKey(secret.Data[sshPublicKeyKey])
+	if err != nil {
+		t.Fatalf("invalid public key: %v", err)
+	}
+	if !bytes.Equal(pub.Marshal(), signer.PublicKey().Marshal()) {
+		t.Error("public key does not match the private key")
+	}
+	if comment != "caib-workspace-my-app" {
+		t.Errorf("unexpected key comment %q", comment)
+	}
+	if len(secret.OwnerReferences) != 1 || secret.OwnerReferences[0].Name != "my-app" {
+		t.Errorf("expected the secret to be owned by the workspace, got %+v", secret.OwnerReferences)
+	}
+
+	// The key is kept on later reconciles
+	if err := r.ensureSSHKeySecret(ctx, ws); err != nil {
+		t.Fatalf("ensureSSHKeySecret() error = %v", err)
+	}
+	again := &corev1.Secret{}
+	if err := fc.Get(ctx, types.NamespacedName{Name: "my-app" + sshKeySecretSuffix, Namespace: "default"}, again); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(again.Data[corev1.SSHAuthPrivateKey], secret.Data[corev1.SSHAuthPrivateKey]) {
+		t.Error("expected the existing key to be kept")
+	}
+
+	updated := &automotivev1alpha1.Workspace{}
+	if err := fc.Get(ctx, types.NamespacedName{Name: "my-app", Namespace: "default"}, updated); err != nil {
+		t.Fatal(err)
+	}
+	if updated.Status.SSHKeySecretName != "my-app"+sshKeySecretSuffix {
+		t.Errorf("expected SSHKeySecretName to be set, got %q", updated.Status.SSHKeySecretName)
+	}
+
+	// The init container installs the key from the secret
+	pod := r.buildPod(updated, nil)
+	mounted := false
+	for _, m := range pod.Spec.InitContainers[0].VolumeMounts {
+		mounted = mounted || m.Name == "ssh-key"
+	}
+	if !mounted || !strings.Contains(pod.Spec.InitContainers[0].Command[2], "/etc/workspace-ssh/ssh-privatekey") {
+		t.Error("expected the init container to install the managed key")
+	}
+}
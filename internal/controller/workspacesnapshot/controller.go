@@ -0,0 +1,513 @@
+// Package workspacesnapshot provides the controller for WorkspaceSnapshot resources.
+package workspacesnapshot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/labels"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/tasks"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/controllerutils"
+)
+
+// defaultSnapshotClassAnnotation marks the default VolumeSnapshotClass of a CSI driver
+const defaultSnapshotClassAnnotation = "snapshot.storage.kubernetes.io/is-default-class"
+
+var (
+	volumeSnapshotGVK      = schema.GroupVersionKind{Group: "snapshot.storage.k8s.io", Version: "v1", Kind: "VolumeSnapshot"}
+	volumeSnapshotClassGVK = schema.GroupVersionKind{Group: "snapshot.storage.k8s.io", Version: "v1", Kind: "VolumeSnapshotClassList"}
+	imageStreamGVK         = schema.GroupVersionKind{Group: "image.openshift.io", Version: "v1", Kind: "ImageStream"}
+	imageStreamTagGVK      = schema.GroupVersionKind{Group: "image.openshift.io", Version: "v1", Kind: "ImageStreamTag"}
+)
+
+// Reconciler reconciles a WorkspaceSnapshot object
+type Reconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,namespace=system,resources=workspacesnapshots,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,namespace=system,resources=workspacesnapshots/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,namespace=system,resources=workspacesnapshots/finalizers,verbs=update
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,namespace=system,resources=volumesnapshots,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshotclasses,verbs=get;list
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=image.openshift.io,namespace=system,resources=imagestreams,verbs=get;create
+// +kubebuilder:rbac:groups=image.openshift.io,namespace=system,resources=imagestreamtags,verbs=delete
+
+// Reconcile handles reconciliation of WorkspaceSnapshot resources.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	snapshot := &automotivev1alpha1.WorkspaceSnapshot{}
+	if err := r.Get(ctx, req.NamespacedName, snapshot); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !snapshot.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, snapshot)
+	}
+
+	switch snapshot.Status.Phase {
+	case "", automotivev1alpha1.WorkspaceSnapshotPhasePending:
+		return r.handlePending(ctx, snapshot)
+	case automotivev1alpha1.WorkspaceSnapshotPhaseCreating:
+		return r.handleCreating(ctx, snapshot)
+	case automotivev1alpha1.WorkspaceSnapshotPhaseReady, automotivev1alpha1.WorkspaceSnapshotPhaseFailed:
+		return r.checkExpiry(ctx, snapshot)
+	default:
+		r.Log.Info("Unknown phase", "workspacesnapshot", req.NamespacedName, "phase", snapshot.Status.Phase)
+		return ctrl.Result{}, nil
+	}
+}
+
+func (r *Reconciler) handlePending(ctx context.Context, snapshot *automotivev1alpha1.WorkspaceSnapshot) (ctrl.Result, error) {
+	ws := &automotivev1alpha1.Workspace{}
+	if err := r.Get(ctx, client.ObjectKey{Name: snapshot.Spec.Workspace, Namespace: snapshot.Namespace}, ws); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return r.fail(ctx, snapshot, fmt.Sprintf("workspace %q not found", snapshot.Spec.Workspace))
+		}
+		return ctrl.Result{}, err
+	}
+	if ws.Status.PVCName == "" {
+		return r.updateStatus(ctx, snapshot, automotivev1alpha1.WorkspaceSnapshotPhasePending,
+			"Waiting for the workspace volume")
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(ctx, client.ObjectKey{Name: ws.Status.PVCName, Namespace: ws.Namespace}, pvc); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return r.fail(ctx, snapshot, fmt.Sprintf("workspace volume %q not found", ws.Status.PVCName))
+		}
+		return ctrl.Result{}, err
+	}
+
+	method, snapshotClass, err := r.resolveMethod(ctx, snapshot.Spec.Method, pvc)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if method == "" {
+		return r.fail(ctx, snapshot, fmt.Sprintf(
+			"the storage class of workspace volume %s does not support volume snapshots", pvc.Name))
+	}
+
+	if method == automotivev1alpha1.SnapshotMethodVolumeSnapshot {
+		if err := r.createVolumeSnapshot(ctx, snapshot, pvc.Name, snapshotClass); err != nil {
+			return ctrl.Result{}, err
+		}
+		snapshot.Status.Method = method
+		snapshot.Status.Source = snapshotSource(ws, pvc)
+		snapshot.Status.VolumeSnapshotName = snapshot.Name
+		return r.updateStatus(ctx, snapshot, automotivev1alpha1.WorkspaceSnapshotPhaseCreating,
+			fmt.Sprintf("Creating VolumeSnapshot with class %s", snapshotClass))
+	}
+
+	// The exported artifact lives in the registry, outside of owner reference
+	// garbage collection
+	if !controllerutil.ContainsFinalizer(snapshot, automotivev1alpha1.WorkspaceSnapshotFinalizer) {
+		controllerutil.AddFinalizer(snapshot, automotivev1alpha1.WorkspaceSnapshotFinalizer)
+		if err := r.Update(ctx, snapshot); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	pod, err := r.createExportPod(ctx, snapshot, ws, pvc.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	snapshot.Status.Method = method
+	snapshot.Status.Source = snapshotSource(ws, pvc)
+	snapshot.Status.PodName = pod.Name
+	return r.updateStatus(ctx, snapshot, automotivev1alpha1.WorkspaceSnapshotPhaseCreating,
+		"Exporting workspace to the cluster registry")
+}
+
+func (r *Reconciler) handleCreating(ctx context.Context, snapshot *automotivev1alpha1.WorkspaceSnapshot) (ctrl.Result, error) {
+	if snapshot.Status.Method == automotivev1alpha1.SnapshotMethodVolumeSnapshot {
+		return r.checkVolumeSnapshot(ctx, snapshot)
+	}
+	return r.checkExportPod(ctx, snapshot)
+}
+
+// resolveMethod picks the snapshot method for the workspace volume. A
+// VolumeSnapshot is used when a VolumeSnapshotClass exists for the CSI driver
+// provisioning the volume, an OCI export otherwise. It also returns the
+// VolumeSnapshotClass to use, and an empty method when a VolumeSnapshot was
+// requested but is not supported.
+func (r *Reconciler) resolveMethod(ctx context.Context, requested string, pvc *corev1.PersistentVolumeClaim) (string, string, error) {
+	if requested == automotivev1alpha1.SnapshotMethodOCI {
+		return automotivev1alpha1.SnapshotMethodOCI, "", nil
+	}
+
+	snapshotClass, err := r.findSnapshotClass(ctx, pvc)
+	if err != nil {
+		return "", "", err
+	}
+	if snapshotClass != "" {
+		return automotivev1alpha1.SnapshotMethodVolumeSnapshot, snapshotClass, nil
+	}
+	if requested == automotivev1alpha1.SnapshotMethodVolumeSnapshot {
+		return "", "", nil
+	}
+	return automotivev1alpha1.SnapshotMethodOCI, "", nil
+}
+
+// findSnapshotClass returns the VolumeSnapshotClass matching the provisioner of
+// the volume storage class, preferring the default class of the driver. It
+// returns an empty name when there is none or the snapshot API is not installed.
+func (r *Reconciler) findSnapshotClass(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (string, error) {
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return "", nil
+	}
+	storageClass := &storagev1.StorageClass{}
+	if err := r.Get(ctx, client.ObjectKey{Name: *pvc.Spec.StorageClassName}, storageClass); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get storage class %s: %w", *pvc.Spec.StorageClassName, err)
+	}
+
+	classes := &unstructured.UnstructuredList{}
+	classes.SetGroupVersionKind(volumeSnapshotClassGVK)
+	if err := r.List(ctx, classes); err != nil {
+		if apimeta.IsNoMatchError(err) || k8serrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to list volume snapshot classes: %w", err)
+	}
+
+	match := ""
+	for _, class := range classes.Items {
+		if driver, _, _ := unstructured.NestedString(class.Object, "driver"); driver != storageClass.Provisioner {
+			continue
+		}
+		if class.GetAnnotations()[defaultSnapshotClassAnnotation] == "true" {
+			return class.GetName(), nil
+		}
+		if match == "" {
+			match = class.GetName()
+		}
+	}
+	return match, nil
+}
+
+func snapshotSource(ws *automotivev1alpha1.Workspace, pvc *corev1.PersistentVolumeClaim) *automotivev1alpha1.WorkspaceSnapshotSource {
+	source := &automotivev1alpha1.WorkspaceSnapshotSource{
+		Architecture: ws.Spec.Architecture,
+		Image:        ws.Spec.Image,
+		PVCSize:      ws.Spec.PVCSize,
+		StorageClass: ws.Spec.StorageClass,
+	}
+	if size, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		source.PVCSize = size.String()
+	}
+	if pvc.Spec.StorageClassName != nil {
+		source.StorageClass = *pvc.Spec.StorageClassName
+	}
+	return source
+}
+
+func (r *Reconciler) createVolumeSnapshot(ctx context.Context, snapshot *automotivev1alpha1.WorkspaceSnapshot, pvcName, snapshotClass string) error {
+	vs := &unstructured.Unstructured{}
+	vs.SetGroupVersionKind(volumeSnapshotGVK)
+	vs.SetName(snapshot.Name)
+	vs.SetNamespace(snapshot.Namespace)
+	vs.SetLabels(map[string]string{
+		"app.kubernetes.io/managed-by":        "automotive-dev-operator",
+		automotivev1alpha1.LabelWorkspaceName: controllerutils.SanitizeLabelValue(snapshot.Spec.Workspace),
+	})
+	vs.Object["spec"] = map[string]interface{}{
+		"volumeSnapshotClassName": snapshotClass,
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": pvcName,
+		},
+	}
+	if err := controllerutil.SetControllerReference(snapshot, vs, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, vs); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create VolumeSnapshot: %w", err)
+	}
+	return nil
+}
+
+func (r *Reconciler) checkVolumeSnapshot(ctx context.Context, snapshot *automotivev1alpha1.WorkspaceSnapshot) (ctrl.Result, error) {
+	vs := &unstructured.Unstructured{}
+	vs.SetGroupVersionKind(volumeSnapshotGVK)
+	if err := r.Get(ctx, client.ObjectKey{Name: snapshot.Status.VolumeSnapshotName, Namespace: snapshot.Namespace}, vs); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return r.fail(ctx, snapshot, fmt.Sprintf("VolumeSnapshot %s not found", snapshot.Status.VolumeSnapshotName))
+		}
+		return ctrl.Result{}, err
+	}
+
+	if message, found, _ := unstructured.NestedString(vs.Object, "status", "error", "message"); found && message != "" {
+		return r.fail(ctx, snapshot, fmt.Sprintf("VolumeSnapshot failed: %s", message))
+	}
+	if ready, _, _ := unstructured.NestedBool(vs.Object, "status", "readyToUse"); ready {
+		return r.complete(ctx, snapshot, "VolumeSnapshot is ready")
+	}
+	return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+}
+
+func (r *Reconciler) createExportPod(
+	ctx context.Context,
+	snapshot *automotivev1alpha1.WorkspaceSnapshot,
+	ws *automotivev1alpha1.Workspace,
+	pvcName string,
+) (*corev1.Pod, error) {
+	podName := snapshot.Name + "-export"
+	existing := &corev1.Pod{}
+	if err := r.Get(ctx, client.ObjectKey{Name: podName, Namespace: snapshot.Namespace}, existing); err == nil {
+		return existing, nil
+	} else if !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	if err := r.ensureImageStream(ctx, snapshot.Namespace); err != nil {
+		return nil, err
+	}
+	operatorConfig, err := r.loadOperatorConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := tasks.WorkspaceSnapshotRef(snapshot.Namespace, snapshot.Name)
+	pod := tasks.GenerateWorkspaceSnapshotPod(snapshot.Namespace, podName, tasks.WorkspaceSnapshotExport,
+		pvcName, ref, operatorConfig.Spec.Workspaces.GetToolchainImage())
+	pod.Labels[automotivev1alpha1.LabelWorkspaceName] = controllerutils.SanitizeLabelValue(ws.Name)
+
+	// The workspace volume is ReadWriteOnce, so a running workspace pins the
+	// export to its node
+	wsPod := &corev1.Pod{}
+	if err := r.Get(ctx, client.ObjectKey{Name: "workspace-" + ws.Name, Namespace: ws.Namespace}, wsPod); err == nil {
+		pod.Spec.NodeName = wsPod.Spec.NodeName
+	} else if !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	if err := controllerutil.SetControllerReference(snapshot, pod, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Create(ctx, pod); err != nil {
+		return nil, fmt.Errorf("failed to create export pod: %w", err)
+	}
+	return pod, nil
+}
+
+func (r *Reconciler) checkExportPod(ctx context.Context, snapshot *automotivev1alpha1.WorkspaceSnapshot) (ctrl.Result, error) {
+	pod := &corev1.Pod{}
+	if err := r.Get(ctx, client.ObjectKey{Name: snapshot.Status.PodName, Namespace: snapshot.Namespace}, pod); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return r.fail(ctx, snapshot, fmt.Sprintf("export pod %s not found", snapshot.Status.PodName))
+		}
+		return ctrl.Result{}, err
+	}
+
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		snapshot.Status.ArtifactRef = tasks.WorkspaceSnapshotRef(snapshot.Namespace, snapshot.Name)
+		snapshot.Status.PodName = ""
+		if err := client.IgnoreNotFound(r.Delete(ctx, pod)); err != nil {
+			return ctrl.Result{}, err
+		}
+		return r.complete(ctx, snapshot, "Workspace exported to the cluster registry")
+	case corev1.PodFailed:
+		return r.fail(ctx, snapshot, fmt.Sprintf("export pod %s failed, see its logs for details", pod.Name))
+	default:
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+}
+
+// ensureImageStream creates the ImageStream the OpenShift internal registry
+// requires before snapshots can be pushed to it.
+func (r *Reconciler) ensureImageStream(ctx context.Context, namespace string) error {
+	is := &unstructured.Unstructured{}
+	is.SetGroupVersionKind(imageStreamGVK)
+	err := r.Get(ctx, client.ObjectKey{Name: tasks.WorkspaceSnapshotImageStream, Namespace: namespace}, is)
+	if err == nil {
+		return nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("error checking ImageStream %s: %w", tasks.WorkspaceSnapshotImageStream, err)
+	}
+
+	is = &unstructured.Unstructured{}
+	is.SetGroupVersionKind(imageStreamGVK)
+	is.SetName(tasks.WorkspaceSnapshotImageStream)
+	is.SetNamespace(namespace)
+	is.SetLabels(map[string]string{
+		labels.ManagedBy: labels.ValueOperator,
+		labels.PartOf:    labels.ValueAutomotiveDev,
+	})
+	if err := r.Create(ctx, is); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("error creating ImageStream %s: %w", tasks.WorkspaceSnapshotImageStream, err)
+	}
+	return nil
+}
+
+// finalize deletes the exported registry artifact of a snapshot. VolumeSnapshots
+// and export pods are garbage collected through their owner references.
+func (r *Reconciler) finalize(ctx context.Context, snapshot *automotivev1alpha1.WorkspaceSnapshot) error {
+	if !controllerutil.ContainsFinalizer(snapshot, automotivev1alpha1.WorkspaceSnapshotFinalizer) {
+		return nil
+	}
+	if snapshot.Status.ArtifactRef != "" {
+		ist := &unstructured.Unstructured{}
+		ist.SetGroupVersionKind(imageStreamTagGVK)
+		ist.SetName(tasks.WorkspaceSnapshotImageStream + ":" + snapshot.Name)
+		ist.SetNamespace(snapshot.Namespace)
+		if err := r.Delete(ctx, ist); err != nil && !k8serrors.IsNotFound(err) && !apimeta.IsNoMatchError(err) {
+			return fmt.Errorf("failed to delete snapshot artifact: %w", err)
+		}
+	}
+	controllerutil.RemoveFinalizer(snapshot, automotivev1alpha1.WorkspaceSnapshotFinalizer)
+	return r.Update(ctx, snapshot)
+}
+
+// checkExpiry deletes a finished snapshot once its TTL has passed.
+func (r *Reconciler) checkExpiry(ctx context.Context, snapshot *automotivev1alpha1.WorkspaceSnapshot) (ctrl.Result, error) {
+	if !snapshot.Status.IsFinished() || snapshot.Status.CompletionTime == nil {
+		return ctrl.Result{}, nil
+	}
+
+	var ttl time.Duration
+	if snapshot.Annotations[automotivev1alpha1.NoExpireAnnotation] != "true" {
+		var err error
+		if ttl, err = r.resolveEffectiveTTL(ctx, snapshot); err != nil {
+			r.emitEventf(snapshot, corev1.EventTypeWarning, "InvalidTTL",
+				"Failed to resolve TTL, expiry disabled for this snapshot: %v", err)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	var expiresAt *metav1.Time
+	if ttl > 0 {
+		t := metav1.NewTime(snapshot.Status.CompletionTime.Add(ttl).Truncate(time.Second))
+		expiresAt = &t
+	}
+	if !expiresAt.Equal(snapshot.Status.ExpiresAt) {
+		snapshot.Status.ExpiresAt = expiresAt
+		if err := r.Status().Update(ctx, snapshot); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if expiresAt == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if remaining := time.Until(expiresAt.Time); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+	r.Log.Info("WorkspaceSnapshot expired, deleting", "workspacesnapshot", snapshot.Name, "ttl", ttl)
+	if err := r.Delete(ctx, snapshot); err != nil && !k8serrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// resolveEffectiveTTL returns 0 if expiry is disabled.
+func (r *Reconciler) resolveEffectiveTTL(ctx context.Context, snapshot *automotivev1alpha1.WorkspaceSnapshot) (time.Duration, error) {
+	ttlStr := snapshot.Spec.TTL
+	if ttlStr == "" {
+		operatorConfig, err := r.loadOperatorConfig(ctx)
+		if err != nil {
+			return 0, err
+		}
+		ttlStr = operatorConfig.Spec.Workspaces.GetSnapshotTTL()
+	}
+	if ttlStr == "0" {
+		return 0, nil
+	}
+
+	ttl, err := time.ParseDuration(ttlStr)
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, fmt.Errorf("TTL must not be negative: %s", ttlStr)
+	}
+	return ttl, nil
+}
+
+// loadOperatorConfig returns the OperatorConfig, or an empty one when it does not exist.
+func (r *Reconciler) loadOperatorConfig(ctx context.Context) (*automotivev1alpha1.OperatorConfig, error) {
+	operatorConfig := &automotivev1alpha1.OperatorConfig{}
+	if err := r.Get(ctx, client.ObjectKey{Name: "config", Namespace: controllerutils.OperatorNamespace()}, operatorConfig); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to load OperatorConfig: %w", err)
+		}
+		return &automotivev1alpha1.OperatorConfig{}, nil
+	}
+	return operatorConfig, nil
+}
+
+func (r *Reconciler) complete(ctx context.Context, snapshot *automotivev1alpha1.WorkspaceSnapshot, message string) (ctrl.Result, error) {
+	now := metav1.Now()
+	snapshot.Status.CompletionTime = &now
+	return r.updateStatus(ctx, snapshot, automotivev1alpha1.WorkspaceSnapshotPhaseReady, message)
+}
+
+func (r *Reconciler) fail(ctx context.Context, snapshot *automotivev1alpha1.WorkspaceSnapshot, message string) (ctrl.Result, error) {
+	now := metav1.Now()
+	snapshot.Status.CompletionTime = &now
+	return r.updateStatus(ctx, snapshot, automotivev1alpha1.WorkspaceSnapshotPhaseFailed, message)
+}
+
+func (r *Reconciler) updateStatus(
+	ctx context.Context,
+	snapshot *automotivev1alpha1.WorkspaceSnapshot,
+	phase, message string,
+) (ctrl.Result, error) {
+	oldPhase := snapshot.Status.Phase
+	snapshot.Status.Phase = phase
+	snapshot.Status.Message = message
+	if err := r.Status().Update(ctx, snapshot); err != nil {
+		return ctrl.Result{}, err
+	}
+	if oldPhase != phase {
+		eventType := corev1.EventTypeNormal
+		if phase == automotivev1alpha1.WorkspaceSnapshotPhaseFailed {
+			eventType = corev1.EventTypeWarning
+		}
+		r.emitEventf(snapshot, eventType, "PhaseChanged", "Phase transitioned: %s -> %s, message=%s", oldPhase, phase, message)
+	}
+	switch phase {
+	case automotivev1alpha1.WorkspaceSnapshotPhasePending, automotivev1alpha1.WorkspaceSnapshotPhaseCreating:
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	return r.checkExpiry(ctx, snapshot)
+}
+
+func (r *Reconciler) emitEventf(
+	snapshot *automotivev1alpha1.WorkspaceSnapshot,
+	eventType, reason, messageFmt string,
+	args ...interface{},
+) {
+	if r.Recorder == nil || snapshot == nil {
+		return
+	}
+	r.Recorder.Eventf(snapshot, eventType, reason, messageFmt, args...)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&automotivev1alpha1.WorkspaceSnapshot{}).
+		Owns(&corev1.Pod{}).
+		Complete(r)
+}
@@ -0,0 +1,279 @@
+package workspacesnapshot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/tasks"
+)
+
+const testNamespace = "builds"
+
+func newTestReconciler(objs ...client.Object) (*Reconciler, client.Client) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(automotivev1alpha1.AddToScheme(scheme))
+	for _, gvk := range []schema.GroupVersionKind{volumeSnapshotGVK, imageStreamGVK, imageStreamTagGVK,
+		volumeSnapshotClassGVK.GroupVersion().WithKind("VolumeSnapshotClass")} {
+		scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&automotivev1alpha1.WorkspaceSnapshot{}).
+		Build()
+	return &Reconciler{Client: fakeClient, Scheme: scheme, Log: logr.Discard()}, fakeClient
+}
+
+func newWorkspace() (*automotivev1alpha1.Workspace, *corev1.PersistentVolumeClaim) {
+	ws := &automotivev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "dev", Namespace: testNamespace},
+		Spec:       automotivev1alpha1.WorkspaceSpec{Owner: "alice", Architecture: "arm64"},
+		Status:     automotivev1alpha1.WorkspaceStatus{PVCName: "dev-workspace"},
+	}
+	storageClass := "ceph-rbd"
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "dev-workspace", Namespace: testNamespace},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClass,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")},
+			},
+		},
+	}
+	return ws, pvc
+}
+
+func newSnapshot(method string) *automotivev1alpha1.WorkspaceSnapshot {
+	return &automotivev1alpha1.WorkspaceSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "dev-snap", Namespace: testNamespace, UID: types.UID("dev-snap-uid")},
+		Spec:       automotivev1alpha1.WorkspaceSnapshotSpec{Workspace: "dev", Owner: "alice", Method: method},
+	}
+}
+
+func snapshotClass(name, driver string, isDefault bool) *unstructured.Unstructured {
+	class := &unstructured.Unstructured{}
+	class.SetGroupVersionKind(volumeSnapshotClassGVK.GroupVersion().WithKind("VolumeSnapshotClass"))
+	class.SetName(name)
+	if isDefault {
+		class.SetAnnotations(map[string]string{defaultSnapshotClassAnnotation: "true"})
+	}
+	class.Object["driver"] = driver
+	class.Object["deletionPolicy"] = "Delete"
+	return class
+}
+
+func reconcileSnapshot(t *testing.T, r *Reconciler) ctrl.Result {
+	t.Helper()
+	result, err := r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "dev-snap", Namespace: testNamespace},
+	})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	return result
+}
+
+func getSnapshot(t *testing.T, c client.Client) *automotivev1alpha1.WorkspaceSnapshot {
+	t.Helper()
+	snapshot := &automotivev1alpha1.WorkspaceSnapshot{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "dev-snap", Namespace: testNamespace}, snapshot); err != nil {
+		t.Fatal(err)
+	}
+	return snapshot
+}
+
+func TestReconcile_UsesVolumeSnapshotWhenSupported(t *testing.T) {
+	ws, pvc := newWorkspace()
+	sc := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "ceph-rbd"}, Provisioner: "rbd.csi.ceph.com"}
+	r, c := newTestReconciler(ws, pvc, sc, newSnapshot(""),
+		snapshotClass("other-driver", "ebs.csi.aws.com", true),
+		snapshotClass("rbd-retain", "rbd.csi.ceph.com", false),
+		snapshotClass("rbd-default", "rbd.csi.ceph.com", true))
+
+	reconcileSnapshot(t, r)
+
+	snapshot := getSnapshot(t, c)
+	if snapshot.Status.Phase != automotivev1alpha1.WorkspaceSnapshotPhaseCreating ||
+		snapshot.Status.Method != automotivev1alpha1.SnapshotMethodVolumeSnapshot {
+		t.Fatalf("status = %+v", snapshot.Status)
+	}
+	if src := snapshot.Status.Source; src == nil || src.Architecture != "arm64" || src.PVCSize != "20Gi" || src.StorageClass != "ceph-rbd" {
+		t.Errorf("source = %+v", snapshot.Status.Source)
+	}
+
+	vs := &unstructured.Unstructured{}
+	vs.SetGroupVersionKind(volumeSnapshotGVK)
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "dev-snap", Namespace: testNamespace}, vs); err != nil {
+		t.Fatalf("expected VolumeSnapshot: %v", err)
+	}
+	if class, _, _ := unstructured.NestedString(vs.Object, "spec", "volumeSnapshotClassName"); class != "rbd-default" {
+		t.Errorf("volumeSnapshotClassName = %q, want the default class of the driver", class)
+	}
+	if claim, _, _ := unstructured.NestedString(vs.Object, "spec", "source", "persistentVolumeClaimName"); claim != "dev-workspace" {
+		t.Errorf("persistentVolumeClaimName = %q", claim)
+	}
+	if refs := vs.GetOwnerReferences(); len(refs) != 1 || refs[0].Kind != "WorkspaceSnapshot" {
+		t.Errorf("expected VolumeSnapshot owned by the snapshot, got %v", refs)
+	}
+
+	if err := unstructured.SetNestedField(vs.Object, true, "status", "readyToUse"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Update(context.Background(), vs); err != nil {
+		t.Fatal(err)
+	}
+	result := reconcileSnapshot(t, r)
+
+	snapshot = getSnapshot(t, c)
+	if snapshot.Status.Phase != automotivev1alpha1.WorkspaceSnapshotPhaseReady || snapshot.Status.CompletionTime == nil {
+		t.Fatalf("status = %+v", snapshot.Status)
+	}
+	if snapshot.Status.ExpiresAt == nil || result.RequeueAfter <= 0 {
+		t.Errorf("expected expiry from the default snapshot TTL, got %v (requeue %v)", snapshot.Status.ExpiresAt, result.RequeueAfter)
+	}
+}
+
+func TestReconcile_FallsBackToOCIExport(t *testing.T) {
+	ws, pvc := newWorkspace()
+	wsPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "workspace-dev", Namespace: testNamespace},
+		Spec:       corev1.PodSpec{NodeName: "worker-2"},
+	}
+	r, c := newTestReconciler(ws, pvc, wsPod, newSnapshot(""))
+
+	reconcileSnapshot(t, r)
+
+	snapshot := getSnapshot(t, c)
+	if snapshot.Status.Phase != automotivev1alpha1.WorkspaceSnapshotPhaseCreating ||
+		snapshot.Status.Method != automotivev1alpha1.SnapshotMethodOCI {
+		t.Fatalf("status = %+v", snapshot.Status)
+	}
+	if len(snapshot.Finalizers) != 1 || snapshot.Finalizers[0] != automotivev1alpha1.WorkspaceSnapshotFinalizer {
+		t.Errorf("expected the snapshot finalizer, got %v", snapshot.Finalizers)
+	}
+
+	is := &unstructured.Unstructured{}
+	is.SetGroupVersionKind(imageStreamGVK)
+	if err := c.Get(context.Background(), types.NamespacedName{Name: tasks.WorkspaceSnapshotImageStream, Namespace: testNamespace}, is); err != nil {
+		t.Errorf("expected snapshot ImageStream: %v", err)
+	}
+
+	pod := &corev1.Pod{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: snapshot.Status.PodName, Namespace: testNamespace}, pod); err != nil {
+		t.Fatalf("expected export pod: %v", err)
+	}
+	if pod.Spec.NodeName != "worker-2" {
+		t.Errorf("NodeName = %q, want the node of the running workspace", pod.Spec.NodeName)
+	}
+	if pod.Spec.Volumes[0].PersistentVolumeClaim.ClaimName != "dev-workspace" {
+		t.Errorf("expected the workspace volume to be mounted, got %+v", pod.Spec.Volumes)
+	}
+
+	pod.Status.Phase = corev1.PodSucceeded
+	if err := c.Status().Update(context.Background(), pod); err != nil {
+		t.Fatal(err)
+	}
+	reconcileSnapshot(t, r)
+
+	snapshot = getSnapshot(t, c)
+	if snapshot.Status.Phase != automotivev1alpha1.WorkspaceSnapshotPhaseReady {
+		t.Fatalf("status = %+v", snapshot.Status)
+	}
+	if want := tasks.WorkspaceSnapshotRef(testNamespace, "dev-snap"); snapshot.Status.ArtifactRef != want {
+		t.Errorf("ArtifactRef = %q, want %q", snapshot.Status.ArtifactRef, want)
+	}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: pod.Name, Namespace: testNamespace}, pod); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected the export pod to be deleted, got %v", err)
+	}
+}
+
+func TestReconcile_FailsWhenVolumeSnapshotUnsupported(t *testing.T) {
+	ws, pvc := newWorkspace()
+	sc := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "ceph-rbd"}, Provisioner: "rbd.csi.ceph.com"}
+	r, c := newTestReconciler(ws, pvc, sc, newSnapshot(automotivev1alpha1.SnapshotMethodVolumeSnapshot))
+
+	reconcileSnapshot(t, r)
+
+	snapshot := getSnapshot(t, c)
+	if snapshot.Status.Phase != automotivev1alpha1.WorkspaceSnapshotPhaseFailed {
+		t.Errorf("status = %+v", snapshot.Status)
+	}
+}
+
+func TestReconcile_FailsWithoutWorkspace(t *testing.T) {
+	r, c := newTestReconciler(newSnapshot(""))
+
+	reconcileSnapshot(t, r)
+
+	if snapshot := getSnapshot(t, c); snapshot.Status.Phase != automotivev1alpha1.WorkspaceSnapshotPhaseFailed {
+		t.Errorf("status = %+v", snapshot.Status)
+	}
+}
+
+func TestReconcile_DeletesExpiredSnapshot(t *testing.T) {
+	snapshot := newSnapshot("")
+	snapshot.Spec.TTL = "1h"
+	snapshot.Finalizers = []string{automotivev1alpha1.WorkspaceSnapshotFinalizer}
+	completed := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	snapshot.Status = automotivev1alpha1.WorkspaceSnapshotStatus{
+		Phase:          automotivev1alpha1.WorkspaceSnapshotPhaseReady,
+		Method:         automotivev1alpha1.SnapshotMethodOCI,
+		ArtifactRef:    tasks.WorkspaceSnapshotRef(testNamespace, "dev-snap"),
+		CompletionTime: &completed,
+	}
+	tag := &unstructured.Unstructured{}
+	tag.SetGroupVersionKind(imageStreamTagGVK)
+	tag.SetName(tasks.WorkspaceSnapshotImageStream + ":dev-snap")
+	tag.SetNamespace(testNamespace)
+	r, c := newTestReconciler(snapshot, tag)
+
+	// The first pass deletes the snapshot, the second runs its finalizer
+	reconcileSnapshot(t, r)
+	reconcileSnapshot(t, r)
+
+	err := c.Get(context.Background(), types.NamespacedName{Name: "dev-snap", Namespace: testNamespace}, snapshot)
+	if !k8serrors.IsNotFound(err) {
+		t.Errorf("expected the expired snapshot to be deleted, got %v", err)
+	}
+	err = c.Get(context.Background(), types.NamespacedName{Name: tag.GetName(), Namespace: testNamespace}, tag)
+	if !k8serrors.IsNotFound(err) {
+		t.Errorf("expected the snapshot artifact to be deleted, got %v", err)
+	}
+}
+
+func TestReconcile_NoExpireAnnotation(t *testing.T) {
+	snapshot := newSnapshot("")
+	snapshot.Annotations = map[string]string{automotivev1alpha1.NoExpireAnnotation: "true"}
+	completed := metav1.NewTime(time.Now().Add(-30 * 24 * time.Hour))
+	snapshot.Status = automotivev1alpha1.WorkspaceSnapshotStatus{
+		Phase:          automotivev1alpha1.WorkspaceSnapshotPhaseReady,
+		CompletionTime: &completed,
+	}
+	r, c := newTestReconciler(snapshot)
+
+	reconcileSnapshot(t, r)
+
+	if got := getSnapshot(t, c); got.Status.ExpiresAt != nil {
+		t.Errorf("expected no expiry, got %v", got.Status.ExpiresAt)
+	}
+}